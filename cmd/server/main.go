@@ -2,12 +2,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/preston-bernstein/nba-data-service/internal/config"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/preflight"
 	"github.com/preston-bernstein/nba-data-service/internal/server"
 )
 
@@ -19,16 +25,97 @@ func main() {
 	}
 
 	cfg := config.Load()
+	level := new(slog.LevelVar)
 	logger := logging.NewLogger(logging.Config{
-		Level:   os.Getenv("LOG_LEVEL"),
-		Format:  os.Getenv("LOG_FORMAT"),
-		Service: "nba-data-service",
-		Version: appVersion,
+		Level:    cfg.LogLevel,
+		LevelVar: level,
+		Format:   os.Getenv("LOG_FORMAT"),
+		Service:  "nba-data-service",
+		Version:  appVersion,
 	})
+	if len(os.Args) > 1 && (os.Args[1] == "--check" || os.Args[1] == "-check") {
+		os.Exit(runCheck(cfg, os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "backfill" {
+		os.Exit(runBackfill(cfg, os.Args[2:], logger, os.Stdout, os.Stderr))
+	}
+	for _, fallback := range cfg.Fallbacks() {
+		logger.Warn("config value ignored", "problem", fallback)
+	}
+	if err := cfg.Validate(); err != nil {
+		logger.Error("invalid configuration", "error", err.Error())
+		os.Exit(1)
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	srv := server.New(cfg, logger)
+	srv.SetLogLevel(level)
+	go reloadOnHangup(ctx, srv, logger)
 	srv.Run(ctx, stop)
 }
+
+// runCheck handles `server --check`: it prints the preflight report as JSON
+// and returns 0 when every check passed, 1 otherwise.
+func runCheck(cfg config.Config, stdout io.Writer) int {
+	report := preflight.Run(context.Background(), preflight.Options{
+		Config:      cfg,
+		NewProvider: server.NewProvider,
+	})
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+	if !report.OK() {
+		return 1
+	}
+	return 0
+}
+
+// runBackfill handles `server backfill --from DATE --to DATE [--provider NAME]
+// [--dry-run]` and returns the exit code: 1 when a date still failed, 2 for
+// bad arguments.
+func runBackfill(cfg config.Config, args []string, logger *slog.Logger, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var opts server.BackfillOptions
+	flags.StringVar(&opts.From, "from", "", "first date to sync (YYYY-MM-DD)")
+	flags.StringVar(&opts.To, "to", "", "last date to sync (YYYY-MM-DD); defaults to --from")
+	flags.StringVar(&opts.Provider, "provider", "", "provider to fetch from instead of PROVIDER")
+	flags.BoolVar(&opts.DryRun, "dry-run", false, "list the dates that would be fetched and exit")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if opts.From == "" {
+		fmt.Fprintln(stderr, "backfill: --from is required")
+		return 2
+	}
+	if opts.To == "" {
+		opts.To = opts.From
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := server.Backfill(ctx, cfg, opts, logger, stdout); err != nil {
+		fmt.Fprintf(stderr, "backfill: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// reloadOnHangup applies a config reload for each SIGHUP until ctx ends.
+func reloadOnHangup(ctx context.Context, srv *server.Server, logger *slog.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			if _, err := srv.Reload(); err != nil {
+				logger.Error("config reload rejected", "error", err.Error())
+			}
+		}
+	}
+}
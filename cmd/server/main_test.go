@@ -1,7 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"strings"
 	"testing"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
 )
 
 // Smoke test to ensure main honors SKIP_SERVER_RUN and does not block test runs.
@@ -9,3 +15,41 @@ func TestMainSkipsWhenEnvSet(t *testing.T) {
 	t.Setenv("SKIP_SERVER_RUN", "1")
 	main()
 }
+
+func TestRunBackfillExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SNAPSHOT_DIR", dir)
+	cfg := config.Load()
+	var stdout, stderr bytes.Buffer
+
+	if code := runBackfill(cfg, nil, nil, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "--from is required") {
+		t.Fatalf("expected usage exit 2, got %d %s", code, stderr.String())
+	}
+	if code := runBackfill(cfg, []string{"--from", "2024-01-15", "--dry-run"}, nil, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "2024-01-15\n1 dates would be fetched") {
+		t.Fatalf("expected a dry run listing the date, got %d %s %s", code, stdout.String(), stderr.String())
+	}
+	if code := runBackfill(cfg, []string{"--from", "2024-01-15", "--provider", "fixture"}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected a fixture backfill to succeed, got %d %s", code, stderr.String())
+	}
+	if _, err := os.Stat(snapshots.GameSnapshotPath(dir, "2024-01-15")); err != nil {
+		t.Fatalf("expected the backfilled snapshot: %v", err)
+	}
+	if code := runBackfill(cfg, []string{"--from", "2024-01-15", "--to", "2024-01-01"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected a bad range to exit 1, got %d", code)
+	}
+}
+
+func TestRunCheckPrintsReport(t *testing.T) {
+	t.Setenv("SNAPSHOT_DIR", t.TempDir())
+	t.Setenv("PROVIDER", "fixture")
+	var stdout bytes.Buffer
+	if code := runCheck(config.Load(), &stdout); code != 0 || !strings.Contains(stdout.String(), `"status": "pass"`) {
+		t.Fatalf("expected a passing report, got %d %s", code, stdout.String())
+	}
+
+	t.Setenv("PROVIDER", "espn")
+	stdout.Reset()
+	if code := runCheck(config.Load(), &stdout); code != 1 || !strings.Contains(stdout.String(), `"status": "fail"`) {
+		t.Fatalf("expected a failing report, got %d %s", code, stdout.String())
+	}
+}
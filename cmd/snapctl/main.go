@@ -0,0 +1,151 @@
+// Command snapctl moves snapshot data between environments and repairs it.
+//
+//	snapctl export [-dir DIR] [-o FILE]
+//	snapctl import [-dir DIR] [-merge] FILE
+//	snapctl rebuild-manifest [-dir DIR]
+//
+// export writes the games snapshots and manifest under DIR to one versioned
+// archive (stdout without -o); import validates an archive and unpacks it
+// into DIR, which must be empty unless -merge is given. rebuild-manifest
+// rewrites DIR's manifest from the snapshot files, after hand edits, and
+// prints the dates added and removed per kind. DIR defaults to
+// SNAPSHOT_DIR, and retention and timezone come from the same config the
+// server reads.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: snapctl export|import|rebuild-manifest [flags]")
+		return 2
+	}
+	cfg := config.Load()
+	var err error
+	switch args[0] {
+	case "export":
+		err = runExport(cfg, args[1:], stdout, stderr)
+	case "import":
+		err = runImport(cfg, args[1:], stdin, stdout, stderr)
+	case "rebuild-manifest":
+		err = runRebuildManifest(cfg, args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "snapctl: unknown command %q (export, import, rebuild-manifest)\n", args[0])
+		return 2
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "snapctl %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func runExport(cfg config.Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", cfg.Snapshots.SnapshotFolder, "snapshot directory")
+	out := flags.String("o", "", "archive to write (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dst := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		dst = f
+	}
+	dates, err := newWriter(cfg, *dir).Export(dst)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "exported %d dates from %s\n", len(dates), *dir)
+	return nil
+}
+
+func runImport(cfg config.Config, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", cfg.Snapshots.SnapshotFolder, "snapshot directory")
+	merge := flags.Bool("merge", false, "merge into existing snapshots, keeping local dates that are newer or frozen")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected one archive path (- for stdin)")
+	}
+
+	src := stdin
+	if path := flags.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		src = f
+	}
+	result, err := newWriter(cfg, *dir).Import(src, snapshots.ImportOptions{Merge: *merge})
+	if err != nil {
+		return err
+	}
+	return printJSON(stdout, result)
+}
+
+func runRebuildManifest(cfg config.Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("rebuild-manifest", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", cfg.Snapshots.SnapshotFolder, "snapshot directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("unexpected arguments")
+	}
+	report, err := newWriter(cfg, *dir).RebuildManifest()
+	if err != nil {
+		return err
+	}
+	return printJSON(stdout, report)
+}
+
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// newWriter builds a writer over dir configured like the server's, so the
+// manifest it saves records the same retention.
+func newWriter(cfg config.Config, dir string) *snapshots.Writer {
+	writer := snapshots.NewWriterWithRetention(dir, snapshots.RetentionConfig{
+		GamesDays:   cfg.Snapshots.RetentionDays,
+		TeamsDays:   cfg.Snapshots.TeamsDays,
+		PlayersDays: cfg.Snapshots.PlayersDays,
+	})
+	writer.SetLocation(cfg.Location())
+	return writer
+}
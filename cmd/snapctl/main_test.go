@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+func TestExportThenImportMovesSnapshots(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	writer := snapshots.NewWriter(src, 36500)
+	if err := writer.WriteGamesSnapshot("2024-01-15", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "g1"}}}); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	archive := filepath.Join(t.TempDir(), "snapshots.tar.gz")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"export", "-dir", src, "-o", archive}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("export exited %d: %s", code, stderr.String())
+	}
+	if code := run([]string{"import", "-dir", dst, archive}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("import exited %d: %s", code, stderr.String())
+	}
+	var result snapshots.ImportResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || len(result.Imported) != 1 {
+		t.Fatalf("expected one imported date, got %s %v", stdout.String(), err)
+	}
+	if _, err := os.Stat(snapshots.GameSnapshotPath(dst, "2024-01-15")); err != nil {
+		t.Fatalf("expected the snapshot in the target dir: %v", err)
+	}
+
+	stderr.Reset()
+	if code := run([]string{"import", "-dir", dst, archive}, nil, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "not empty") {
+		t.Fatalf("expected import into a populated dir without -merge to fail, got %d %s", code, stderr.String())
+	}
+	if code := run([]string{"import", "-dir", dst, "-merge", "-"}, bytes.NewReader(mustRead(t, archive)), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected a merge from stdin to succeed, got %d %s", code, stderr.String())
+	}
+}
+
+func TestRebuildManifestReportsRemovedDates(t *testing.T) {
+	dir := t.TempDir()
+	writer := snapshots.NewWriter(dir, 36500)
+	for _, date := range []string{"2024-01-14", "2024-01-15"} {
+		if err := writer.WriteGamesSnapshot(date, domaingames.TodayResponse{}); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if err := os.Remove(snapshots.GameSnapshotPath(dir, "2024-01-14")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"rebuild-manifest", "-dir", dir}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("rebuild-manifest exited %d: %s", code, stderr.String())
+	}
+	var report snapshots.ManifestRebuild
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("decode %s: %v", stdout.String(), err)
+	}
+	if len(report.Games.Removed) != 1 || report.Games.Removed[0] != "2024-01-14" || len(report.Games.Dates) != 1 {
+		t.Fatalf("expected 2024-01-14 removed, got %+v", report.Games)
+	}
+	if code := run([]string{"rebuild-manifest", "-dir", dir, "extra"}, nil, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected stray arguments rejected, got %d", code)
+	}
+}
+
+func TestRunRejectsUnknownCommands(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("expected usage exit 2, got %d", code)
+	}
+	if code := run([]string{"restore"}, nil, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "unknown command") {
+		t.Fatalf("expected unknown command exit 2, got %d %s", code, stderr.String())
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return data
+}
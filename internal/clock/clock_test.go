@@ -0,0 +1,45 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRealClockPrimitives(t *testing.T) {
+	c := Real()
+	before := time.Now()
+	if now := c.Now(); now.Before(before) {
+		t.Fatalf("expected real now after %s, got %s", before, now)
+	}
+
+	timer := c.NewTimer(time.Millisecond)
+	select {
+	case <-timer.C():
+	case <-time.After(time.Second):
+		t.Fatal("expected timer to fire")
+	}
+	timer.Reset(time.Hour)
+	if !timer.Stop() {
+		t.Fatalf("expected stop to report an active timer")
+	}
+
+	ticker := c.NewTicker(time.Millisecond)
+	select {
+	case <-ticker.C():
+	case <-time.After(time.Second):
+		t.Fatal("expected ticker to fire")
+	}
+	ticker.Stop()
+
+	c.Sleep(time.Microsecond)
+}
+
+func TestOrRealDefaultsNil(t *testing.T) {
+	if _, ok := OrReal(nil).(realClock); !ok {
+		t.Fatalf("expected real clock for nil")
+	}
+	c := Real()
+	if OrReal(c) != c {
+		t.Fatalf("expected provided clock returned")
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	envAPIKeys        = "API_KEYS"
+	envAPIKeyRequired = "API_KEY_REQUIRED"
+)
+
+// APIKey is one partner credential from API_KEYS.
+type APIKey struct {
+	Key  string
+	Name string // logged and used as the metrics label in place of the key
+	RPM  int    // requests per minute; 0 is unlimited
+}
+
+// APIKeysConfig controls X-API-Key auth on the public read endpoints.
+type APIKeysConfig struct {
+	Keys     []APIKey
+	Required bool // reject requests without a key; false keeps anonymous access working
+}
+
+// loadAPIKeys parses API_KEYS as comma-separated key:name[:rpm] entries.
+// Malformed entries are skipped and reported by position, never by value.
+func loadAPIKeys() APIKeysConfig {
+	var keys []APIKey
+	for i, entry := range listEnv(envAPIKeys) {
+		key, ok := parseAPIKey(entry)
+		if !ok {
+			fallbacks = append(fallbacks, fmt.Sprintf("%s entry %d is not key:name[:rpm]; skipping it", envAPIKeys, i+1))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return APIKeysConfig{
+		Keys:     keys,
+		Required: boolEnvOrDefault(envAPIKeyRequired, false),
+	}
+}
+
+func parseAPIKey(entry string) (APIKey, bool) {
+	parts := strings.Split(entry, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return APIKey{}, false
+	}
+	key := APIKey{Key: strings.TrimSpace(parts[0]), Name: strings.TrimSpace(parts[1])}
+	if key.Key == "" || key.Name == "" {
+		return APIKey{}, false
+	}
+	if len(parts) == 3 {
+		rpm, err := strconv.Atoi(strings.TrimSpace(parts[2]))
+		if err != nil || rpm < 0 {
+			return APIKey{}, false
+		}
+		key.RPM = rpm
+	}
+	return key, true
+}
+
+// validate rejects a required-key setup with no keys, which would
+// lock every client out, and keys or names configured twice.
+func (c APIKeysConfig) validate() []error {
+	var errs []error
+	if c.Required && len(c.Keys) == 0 {
+		errs = append(errs, fmt.Errorf("%s=true requires at least one %s entry", envAPIKeyRequired, envAPIKeys))
+	}
+	keys := make(map[string]bool, len(c.Keys))
+	names := make(map[string]bool, len(c.Keys))
+	for i, k := range c.Keys {
+		if keys[k.Key] {
+			errs = append(errs, fmt.Errorf("%s entry %d repeats an earlier key", envAPIKeys, i+1))
+		}
+		if names[k.Name] {
+			errs = append(errs, fmt.Errorf("%s entry %d repeats the name %q", envAPIKeys, i+1, k.Name))
+		}
+		keys[k.Key], names[k.Name] = true, true
+	}
+	return errs
+}
@@ -1,37 +1,96 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
 
 const (
 	envBdlBaseURL   = "BALLDONTLIE_BASE_URL"
 	envBdlAPIKey    = "BALLDONTLIE_API_KEY"
+	envBdlAPIKey2   = "BALLDONTLIE_API_KEY_SECONDARY"
 	envBdlTimezone  = "BALLDONTLIE_TIMEZONE"
 	envBdlMaxPages  = "BALLDONTLIE_MAX_PAGES"
 	envBdlPageDelay = "BALLDONTLIE_PAGE_DELAY"
+	envBdlTimeout   = "BALLDONTLIE_TIMEOUT"
+
+	envBdlMaxIdleConns          = "BALLDONTLIE_MAX_IDLE_CONNS"
+	envBdlMaxIdleConnsPerHost   = "BALLDONTLIE_MAX_IDLE_CONNS_PER_HOST"
+	envBdlIdleConnTimeout       = "BALLDONTLIE_IDLE_CONN_TIMEOUT"
+	envBdlTLSHandshakeTimeout   = "BALLDONTLIE_TLS_HANDSHAKE_TIMEOUT"
+	envBdlResponseHeaderTimeout = "BALLDONTLIE_RESPONSE_HEADER_TIMEOUT"
+	envBdlHTTP2                 = "BALLDONTLIE_HTTP2"
+
+	envBdlMaxBodyBytes = "BALLDONTLIE_MAX_BODY_BYTES"
+	envBdlStrictDecode = "BALLDONTLIE_STRICT_DECODE"
+	envBdlCaptureDir   = "BALLDONTLIE_CAPTURE_DIR"
 
 	defaultBdlBaseURL  = "https://api.balldontlie.io/v1"
 	defaultBdlTimezone = "America/New_York"
 	defaultBdlMaxPages = 5
+	defaultBdlTimeout  = 10 * time.Second
+
+	defaultBdlMaxIdleConns          = 100
+	defaultBdlMaxIdleConnsPerHost   = 10
+	defaultBdlIdleConnTimeout       = 90 * time.Second
+	defaultBdlTLSHandshakeTimeout   = 10 * time.Second
+	defaultBdlResponseHeaderTimeout = 8 * time.Second
+
+	defaultBdlMaxBodyBytes = 10 << 20
 )
 
 // BalldontlieConfig controls how we talk to the balldontlie API.
 type BalldontlieConfig struct {
-	BaseURL   string
-	APIKey    string
-	Timezone  string
-	MaxPages  int
-	PageDelay time.Duration
+	BaseURL         string
+	APIKey          string
+	SecondaryAPIKey string // used for zero-downtime key rotation
+	Timezone        string
+	MaxPages        int
+	PageDelay       time.Duration
+	Timeout         time.Duration // whole request, including reading the body
+	MaxBodyBytes    int           // cap on each response body
+	StrictDecode    bool          // fail pages with unknown JSON fields instead of logging them
+	CaptureDir      string        // save successful /games responses here for PROVIDER=replay; empty disables
+
+	// Connection pool and per-phase timeouts for the shared provider transport.
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	HTTP2                 bool
 }
 
 func loadBalldontlie() BalldontlieConfig {
 	return BalldontlieConfig{
-		BaseURL:   envOrDefault(envBdlBaseURL, defaultBdlBaseURL),
-		APIKey:    envOrDefault(envBdlAPIKey, ""),
-		Timezone:  envOrDefault(envBdlTimezone, defaultBdlTimezone),
-		MaxPages:  intEnvOrDefault(envBdlMaxPages, defaultBdlMaxPages),
-		PageDelay: durationEnvOrDefault(envBdlPageDelay, 0),
+		BaseURL:         envOrDefault(envBdlBaseURL, defaultBdlBaseURL),
+		APIKey:          envOrDefault(envBdlAPIKey, ""),
+		SecondaryAPIKey: envOrDefault(envBdlAPIKey2, ""),
+		Timezone:        envOrDefault(envBdlTimezone, defaultBdlTimezone),
+		MaxPages:        intEnvOrDefault(envBdlMaxPages, defaultBdlMaxPages),
+		PageDelay:       durationEnvOrDefault(envBdlPageDelay, 0),
+		Timeout:         durationEnvOrDefault(envBdlTimeout, defaultBdlTimeout),
+		MaxBodyBytes:    intEnvOrDefault(envBdlMaxBodyBytes, defaultBdlMaxBodyBytes),
+		StrictDecode:    boolEnvOrDefault(envBdlStrictDecode, false),
+		CaptureDir:      envOrDefault(envBdlCaptureDir, ""),
+
+		MaxIdleConns:          intEnvOrDefault(envBdlMaxIdleConns, defaultBdlMaxIdleConns),
+		MaxIdleConnsPerHost:   intEnvOrDefault(envBdlMaxIdleConnsPerHost, defaultBdlMaxIdleConnsPerHost),
+		IdleConnTimeout:       durationEnvOrDefault(envBdlIdleConnTimeout, defaultBdlIdleConnTimeout),
+		TLSHandshakeTimeout:   durationEnvOrDefault(envBdlTLSHandshakeTimeout, defaultBdlTLSHandshakeTimeout),
+		ResponseHeaderTimeout: durationEnvOrDefault(envBdlResponseHeaderTimeout, defaultBdlResponseHeaderTimeout),
+		HTTP2:                 boolEnvOrDefault(envBdlHTTP2, false),
 	}
 }
 
+// Location is the service timezone, taken from the balldontlie timezone since
+// that is the calendar upstream dates games by. The poller's slate, the
+// syncer's date window and retention pruning all use it; an unknown or empty
+// name falls back to UTC.
+func (c Config) Location() *time.Location {
+	return timeutil.ResolveLocation(c.Balldontlie.Timezone)
+}
+
 // Ensure Duration alias is used to avoid unused import of time in constants.
 var _ = time.Second
@@ -0,0 +1,30 @@
+package config
+
+import "reflect"
+
+// Changed lists the settings that differ between old and next as dotted
+// field paths, e.g. "PollInterval" or "Snapshots.RetentionDays", in field
+// order. A reload uses it to tell applied settings from skipped ones.
+func Changed(old, next Config) []string {
+	var out []string
+	changedFields("", reflect.ValueOf(old), reflect.ValueOf(next), &out)
+	return out
+}
+
+func changedFields(prefix string, a, b reflect.Value, out *[]string) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := prefix + f.Name
+		if f.Type.Kind() == reflect.Struct {
+			changedFields(name+".", a.Field(i), b.Field(i), out)
+			continue
+		}
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			*out = append(*out, name)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChangedListsDifferingFields(t *testing.T) {
+	old := validConfig()
+	next := old
+	next.PollInterval = time.Minute
+	next.ClientNames = []string{"web"}
+	next.Snapshots.RetentionDays = 3
+	next.fallbacks = []string{"ignored"}
+
+	got := Changed(old, next)
+	want := []string{"PollInterval", "ClientNames", "Snapshots.RetentionDays"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if len(Changed(old, old)) != 0 {
+		t.Fatalf("expected no changes for equal configs")
+	}
+}
@@ -1,23 +1,112 @@
 package config
 
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
 // Config holds runtime configuration for the server.
 type Config struct {
-	Port         string
-	PollInterval Duration
-	Provider     string
-	Balldontlie  BalldontlieConfig
-	Metrics      MetricsConfig
-	Snapshots    SnapshotSyncConfig
+	Port             string
+	PollInterval     Duration
+	PollFetchTimeout Duration
+	// Adaptive poll intervals; zero means use PollInterval.
+	PollLiveInterval    Duration
+	PollPreGameInterval Duration
+	PollIdleInterval    Duration
+	PollJitter          Duration // max random delay before the first poll
+	PollReplaceGames    bool     // write each fetch as-is instead of merging into the known slate
+	AllowRegression     bool     // accept polls moving a FINAL game back to live or a lower score
+	Provider            string
+	ProviderRateLimit   Duration // minimum spacing between upstream fetches
+	ReplayDir           string   // recorded balldontlie responses PROVIDER=replay serves
+	ClientNames         []string // allowlisted X-Client-Name values
+	StreamMax           int      // concurrent /games/stream connections
+	LogSampleRate       int      // log 1 in N 2xx requests; errors and slow requests always log
+	LogSlowRequest      Duration // requests slower than this log at Warn
+	LogRedactParams     []string // query params logged redacted, on top of the middleware's defaults
+	LogLevel            string   // debug, info, warn or error
+	HandlerTimeout      Duration // per-request deadline for read endpoints
+	ShutdownPreStop     Duration // drain delay before listeners close on shutdown; 0 skips it
+	Balldontlie         BalldontlieConfig
+	Metrics             MetricsConfig
+	Snapshots           SnapshotSyncConfig
+	Webhook             WebhookConfig
+	APIKeys             APIKeysConfig
+	RetryBudget         RetryBudgetConfig
+	Quota               QuotaConfig
+	ValidateStrict      bool // Validate also fails on env values Load replaced with defaults
+
+	fallbacks []string // filled by Load; see Fallbacks
+	fileErr   error    // CONFIG_FILE could not be read or parsed; see Validate
 }
 
 // Load reads configuration from environment variables with sensible defaults.
+// When CONFIG_FILE names a JSON or YAML file, its settings stand in for unset
+// env vars; a file that cannot be read or parsed is reported by Validate.
 func Load() Config {
-	return Config{
-		Port:         envOrDefault(envPort, defaultPort),
-		PollInterval: durationEnvOrDefault(envPollInterval, defaultPollInterval),
-		Provider:     envOrDefault(envProvider, defaultProvider),
-		Balldontlie:  loadBalldontlie(),
-		Metrics:      loadMetrics(),
-		Snapshots:    loadSnapshotSync(),
+	path := os.Getenv(envConfigFile)
+	if path == "" {
+		return load(nil, nil)
+	}
+	values, warnings, err := readConfigFile(path)
+	cfg := load(values, warnings)
+	cfg.fileErr = err
+	return cfg
+}
+
+// LoadFrom is Load with the config file read from r instead of CONFIG_FILE.
+// Env vars still override r's settings.
+func LoadFrom(r io.Reader) (Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Config{}, fmt.Errorf("config file: %w", err)
+	}
+	values, warnings, err := parseConfigFile(data)
+	if err != nil {
+		return Config{}, err
+	}
+	return load(values, warnings), nil
+}
+
+// load builds a Config from env vars over the given file values. Warnings
+// from the file are kept as fallbacks, ahead of any env parse failures.
+func load(values map[string]string, warnings []string) Config {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	fallbacks, fileValues = warnings, values
+	defer func() { fileValues = nil }()
+	cfg := Config{
+		Port:                envOrDefault(envPort, defaultPort),
+		PollInterval:        durationEnvOrDefault(envPollInterval, defaultPollInterval),
+		PollFetchTimeout:    durationEnvOrDefault(envPollFetchTimeout, defaultPollFetchTimeout),
+		PollLiveInterval:    durationEnvOrDefault(envPollLiveInterval, 0),
+		PollPreGameInterval: durationEnvOrDefault(envPollPreGameInterval, 0),
+		PollIdleInterval:    durationEnvOrDefault(envPollIdleInterval, 0),
+		PollJitter:          durationEnvOrDefault(envPollJitter, 0),
+		PollReplaceGames:    boolEnvOrDefault(envPollReplaceGames, false),
+		AllowRegression:     boolEnvOrDefault(envAllowFinalRegress, false),
+		Provider:            envOrDefault(envProvider, defaultProvider),
+		ProviderRateLimit:   durationEnvOrDefault(envProviderRateLimit, defaultProviderRateLimit),
+		ReplayDir:           envOrDefault(envReplayDir, defaultReplayDir),
+		ClientNames:         listEnv(envClientNames),
+		StreamMax:           intEnvOrDefault(envStreamMax, defaultStreamMax),
+		LogSampleRate:       intEnvOrDefault(envLogSampleRate, defaultLogSampleRate),
+		LogSlowRequest:      durationEnvOrDefault(envLogSlowRequest, defaultLogSlowRequest),
+		LogRedactParams:     listEnv(envLogRedactParams),
+		LogLevel:            envOrDefault(envLogLevel, defaultLogLevel),
+		HandlerTimeout:      durationEnvOrDefault(envHandlerTimeout, defaultHandlerTimeout),
+		ShutdownPreStop:     nonNegativeDurationEnvOrDefault(envShutdownPreStop, defaultShutdownPreStop),
+		Balldontlie:         loadBalldontlie(),
+		Metrics:             loadMetrics(),
+		Snapshots:           loadSnapshotSync(),
+		Webhook:             loadWebhook(),
+		APIKeys:             loadAPIKeys(),
+		RetryBudget:         loadRetryBudget(),
+		Quota:               loadQuota(),
+		ValidateStrict:      boolEnvOrDefault(envValidateStrict, false),
 	}
+	cfg.fallbacks, fallbacks = fallbacks, nil
+	return cfg
 }
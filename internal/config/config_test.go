@@ -1,20 +1,60 @@
 package config
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestLoadDefaults(t *testing.T) {
+	t.Setenv(envConfigFile, "")
 	t.Setenv(envPort, "")
 	t.Setenv(envPollInterval, "")
+	t.Setenv(envPollFetchTimeout, "")
+	t.Setenv(envPollLiveInterval, "")
+	t.Setenv(envPollPreGameInterval, "")
+	t.Setenv(envPollIdleInterval, "")
+	t.Setenv(envPollJitter, "")
+	t.Setenv(envPollReplaceGames, "")
+	t.Setenv(envAllowFinalRegress, "")
 	t.Setenv(envProvider, "")
+	t.Setenv(envProviderRateLimit, "")
+	t.Setenv(envClientNames, "")
+	t.Setenv(envStreamMax, "")
+	t.Setenv(envLogSampleRate, "")
+	t.Setenv(envLogSlowRequest, "")
+	t.Setenv(envLogRedactParams, "")
+	t.Setenv(envHandlerTimeout, "")
+	t.Setenv(envShutdownPreStop, "")
+	t.Setenv(envRetryBudgetRatio, "")
+	t.Setenv(envRetryBudgetWindow, "")
+	t.Setenv(envRetryBudgetMinRetries, "")
+	t.Setenv(envQuotaThreshold, "")
+	t.Setenv(envQuotaWindow, "")
+	t.Setenv(envLogLevel, "")
+	t.Setenv(envClockSkewThreshold, "")
+	t.Setenv(envWebhookURL, "")
+	t.Setenv(envWebhookSecret, "")
+	t.Setenv(envWebhookAttempts, "")
+	t.Setenv(envWebhookTimeout, "")
 	t.Setenv(envBdlBaseURL, "")
 	t.Setenv(envBdlAPIKey, "")
+	t.Setenv(envBdlAPIKey2, "")
 	t.Setenv(envBdlTimezone, "")
 	t.Setenv(envBdlMaxPages, "")
+	t.Setenv(envBdlTimeout, "")
+	t.Setenv(envBdlMaxIdleConnsPerHost, "")
+	t.Setenv(envBdlResponseHeaderTimeout, "")
+	t.Setenv(envBdlHTTP2, "")
+	t.Setenv(envBdlMaxBodyBytes, "")
+	t.Setenv(envBdlStrictDecode, "")
+	t.Setenv(envBdlCaptureDir, "")
+	t.Setenv(envReplayDir, "")
 	t.Setenv(envMetricsPort, "")
 	t.Setenv(envMetricsOn, "")
+	t.Setenv(envMetricsMaxProviders, "")
+	t.Setenv(envMetricsBuckets, "")
 	t.Setenv(envOtelEndpoint, "")
 	t.Setenv(envOtelService, "")
 	t.Setenv(envOtelInsecure, "")
@@ -22,7 +62,22 @@ func TestLoadDefaults(t *testing.T) {
 	t.Setenv(envSnapshotDays, "")
 	t.Setenv(envSnapshotFutureDays, "")
 	t.Setenv(envSnapshotRate, "")
+	t.Setenv(envSnapshotConcurrency, "")
+	t.Setenv(envSnapshotZones, "")
 	t.Setenv(envSnapshotHour, "")
+	t.Setenv(envSnapshotMinute, "")
+	t.Setenv(envSnapshotDir, "")
+	t.Setenv(envSnapshotCache, "")
+	t.Setenv(envSnapshotFreeze, "")
+	t.Setenv(envRetentionGames, "")
+	t.Setenv(envRetentionTeams, "")
+	t.Setenv(envRetentionPlayers, "")
+	t.Setenv(envSnapshotHistory, "")
+	t.Setenv(envRetentionHistory, "")
+	t.Setenv(envSeasonStart, "")
+	t.Setenv(envSeasonEnd, "")
+	t.Setenv(envForceOffseasonSync, "")
+	t.Setenv(envAdminTimeout, "")
 
 	cfg := Load()
 
@@ -32,21 +87,85 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.PollInterval != defaultPollInterval {
 		t.Fatalf("expected default poll interval %s, got %s", defaultPollInterval, cfg.PollInterval)
 	}
+	if cfg.PollFetchTimeout != defaultPollFetchTimeout {
+		t.Fatalf("expected default poll fetch timeout %s, got %s", defaultPollFetchTimeout, cfg.PollFetchTimeout)
+	}
+	if cfg.PollLiveInterval != 0 || cfg.PollPreGameInterval != 0 || cfg.PollIdleInterval != 0 {
+		t.Fatalf("expected adaptive poll intervals unset by default, got %s/%s/%s", cfg.PollLiveInterval, cfg.PollPreGameInterval, cfg.PollIdleInterval)
+	}
+	if cfg.PollJitter != 0 {
+		t.Fatalf("expected no poll jitter by default, got %s", cfg.PollJitter)
+	}
+	if cfg.PollReplaceGames {
+		t.Fatalf("expected poller to merge games by default")
+	}
+	if cfg.AllowRegression {
+		t.Fatalf("expected final game regressions to be ignored by default")
+	}
 	if cfg.Provider != defaultProvider {
 		t.Fatalf("expected default provider %s, got %s", defaultProvider, cfg.Provider)
 	}
+	if len(cfg.ClientNames) != 0 {
+		t.Fatalf("expected no client names by default, got %v", cfg.ClientNames)
+	}
+	if len(cfg.LogRedactParams) != 0 {
+		t.Fatalf("expected no extra redacted params by default, got %v", cfg.LogRedactParams)
+	}
+	if cfg.StreamMax != defaultStreamMax {
+		t.Fatalf("expected default stream max %d, got %d", defaultStreamMax, cfg.StreamMax)
+	}
+	if cfg.HandlerTimeout != Duration(10*time.Second) {
+		t.Fatalf("expected default handler timeout 10s, got %s", time.Duration(cfg.HandlerTimeout))
+	}
+	if cfg.ShutdownPreStop != Duration(5*time.Second) {
+		t.Fatalf("expected default pre-stop delay 5s, got %s", time.Duration(cfg.ShutdownPreStop))
+	}
+	if cfg.ProviderRateLimit != Duration(time.Minute) {
+		t.Fatalf("expected default provider rate limit 1m, got %s", time.Duration(cfg.ProviderRateLimit))
+	}
+	if want := (RetryBudgetConfig{Ratio: 0.1, Window: Duration(time.Minute), MinRetries: 3}); cfg.RetryBudget != want {
+		t.Fatalf("expected default retry budget %+v, got %+v", want, cfg.RetryBudget)
+	}
+	if want := (QuotaConfig{Threshold: 5, Window: Duration(time.Minute)}); cfg.Quota != want {
+		t.Fatalf("expected default quota %+v, got %+v", want, cfg.Quota)
+	}
+	if cfg.LogLevel != "info" {
+		t.Fatalf("expected default log level info, got %q", cfg.LogLevel)
+	}
+	if cfg.LogSampleRate != 1 || cfg.LogSlowRequest != defaultLogSlowRequest {
+		t.Fatalf("expected every request logged with 500ms slow threshold, got rate %d threshold %s", cfg.LogSampleRate, time.Duration(cfg.LogSlowRequest))
+	}
+	if cfg.Webhook.URL != "" || cfg.Webhook.Secret != "" {
+		t.Fatalf("expected webhooks disabled by default, got %+v", cfg.Webhook)
+	}
+	if cfg.Webhook.MaxAttempts != defaultWebhookAttempts || cfg.Webhook.Timeout != defaultWebhookTimeout {
+		t.Fatalf("expected default webhook attempts/timeout, got %+v", cfg.Webhook)
+	}
 	if cfg.Balldontlie.BaseURL != defaultBdlBaseURL {
 		t.Fatalf("expected default balldontlie base url %s, got %s", defaultBdlBaseURL, cfg.Balldontlie.BaseURL)
 	}
 	if cfg.Balldontlie.APIKey != "" {
 		t.Fatalf("expected empty balldontlie api key by default, got %s", cfg.Balldontlie.APIKey)
 	}
+	if cfg.Balldontlie.SecondaryAPIKey != "" {
+		t.Fatalf("expected empty secondary api key by default, got %s", cfg.Balldontlie.SecondaryAPIKey)
+	}
 	if cfg.Balldontlie.Timezone != defaultBdlTimezone {
 		t.Fatalf("expected default balldontlie timezone %s, got %s", defaultBdlTimezone, cfg.Balldontlie.Timezone)
 	}
 	if cfg.Balldontlie.MaxPages != defaultBdlMaxPages {
 		t.Fatalf("expected default balldontlie max pages %d, got %d", defaultBdlMaxPages, cfg.Balldontlie.MaxPages)
 	}
+	if b := cfg.Balldontlie; b.Timeout != defaultBdlTimeout || b.MaxIdleConnsPerHost != defaultBdlMaxIdleConnsPerHost ||
+		b.ResponseHeaderTimeout != defaultBdlResponseHeaderTimeout || b.HTTP2 {
+		t.Fatalf("unexpected default balldontlie transport settings %+v", b)
+	}
+	if b := cfg.Balldontlie; b.MaxBodyBytes != 10<<20 || b.StrictDecode {
+		t.Fatalf("expected a 10MB lenient decoder by default, got %d %v", b.MaxBodyBytes, b.StrictDecode)
+	}
+	if cfg.Balldontlie.CaptureDir != "" || cfg.ReplayDir != defaultReplayDir {
+		t.Fatalf("expected capture off and the default replay dir, got %q %q", cfg.Balldontlie.CaptureDir, cfg.ReplayDir)
+	}
 	if !cfg.Metrics.Enabled {
 		t.Fatalf("expected metrics enabled by default")
 	}
@@ -62,6 +181,12 @@ func TestLoadDefaults(t *testing.T) {
 	if !cfg.Metrics.OtlpInsecure {
 		t.Fatalf("expected otlp insecure default true")
 	}
+	if cfg.Metrics.MaxProviders != defaultMetricsMaxProviders {
+		t.Fatalf("expected default metrics max providers %d, got %d", defaultMetricsMaxProviders, cfg.Metrics.MaxProviders)
+	}
+	if !slices.Equal(cfg.Metrics.LatencyBuckets, defaultMetricsLatencyBuckets) {
+		t.Fatalf("expected default latency buckets, got %v", cfg.Metrics.LatencyBuckets)
+	}
 	if !cfg.Snapshots.Enabled {
 		t.Fatalf("expected snapshot sync enabled by default")
 	}
@@ -74,25 +199,95 @@ func TestLoadDefaults(t *testing.T) {
 	if cfg.Snapshots.Interval != defaultSnapshotInterval {
 		t.Fatalf("expected default snapshot interval %s, got %s", defaultSnapshotInterval, cfg.Snapshots.Interval)
 	}
+	if cfg.Snapshots.Concurrency != 1 {
+		t.Fatalf("expected snapshot sync concurrency 1 by default, got %d", cfg.Snapshots.Concurrency)
+	}
+	if len(cfg.Snapshots.Zones) != 0 {
+		t.Fatalf("expected no extra snapshot zones by default, got %v", cfg.Snapshots.Zones)
+	}
 	if cfg.Snapshots.DailyHourUTC != defaultSnapshotDailyHour {
 		t.Fatalf("expected default snapshot daily hour %d, got %d", defaultSnapshotDailyHour, cfg.Snapshots.DailyHourUTC)
 	}
+	if cfg.Snapshots.DailyMinuteUTC != 0 {
+		t.Fatalf("expected daily run on the hour by default, got minute %d", cfg.Snapshots.DailyMinuteUTC)
+	}
+	if cfg.Snapshots.SnapshotFolder != defaultSnapshotDir {
+		t.Fatalf("expected default snapshot dir %s, got %s", defaultSnapshotDir, cfg.Snapshots.SnapshotFolder)
+	}
+	if cfg.Snapshots.CacheEntries != defaultSnapshotCache {
+		t.Fatalf("expected default snapshot cache entries %d, got %d", defaultSnapshotCache, cfg.Snapshots.CacheEntries)
+	}
+	if cfg.Snapshots.FreezeGrace != defaultSnapshotFreeze {
+		t.Fatalf("expected default freeze grace %s, got %s", defaultSnapshotFreeze, cfg.Snapshots.FreezeGrace)
+	}
+	if cfg.Snapshots.SkewThreshold != defaultClockSkewThreshold {
+		t.Fatalf("expected default skew threshold %s, got %s", defaultClockSkewThreshold, cfg.Snapshots.SkewThreshold)
+	}
+	if cfg.Snapshots.AdminTimeout != defaultAdminTimeout {
+		t.Fatalf("expected default admin timeout %s, got %s", defaultAdminTimeout, cfg.Snapshots.AdminTimeout)
+	}
 	expectedRetention := defaultSnapshotDays + 1
 	if cfg.Snapshots.RetentionDays != expectedRetention {
 		t.Fatalf("expected default retention days %d, got %d", expectedRetention, cfg.Snapshots.RetentionDays)
 	}
+	if cfg.Snapshots.TeamsDays != defaultRetentionTeams || cfg.Snapshots.PlayersDays != defaultRetentionPlayers {
+		t.Fatalf("expected default teams/players retention %d/%d, got %d/%d", defaultRetentionTeams, defaultRetentionPlayers, cfg.Snapshots.TeamsDays, cfg.Snapshots.PlayersDays)
+	}
+	if cfg.Snapshots.History || cfg.Snapshots.HistoryDays != defaultRetentionHistory {
+		t.Fatalf("expected snapshot history off with %d days, got %t/%d", defaultRetentionHistory, cfg.Snapshots.History, cfg.Snapshots.HistoryDays)
+	}
+	if cfg.Snapshots.SeasonStart != "" || cfg.Snapshots.SeasonEnd != "" || cfg.Snapshots.ForceOffseason {
+		t.Fatalf("expected an open season without force, got %+v", cfg.Snapshots)
+	}
 }
 
 func TestLoadOverrides(t *testing.T) {
 	t.Setenv(envPort, "5000")
 	t.Setenv(envPollInterval, "45s")
+	t.Setenv(envPollFetchTimeout, "5s")
+	t.Setenv(envPollLiveInterval, "10s")
+	t.Setenv(envPollPreGameInterval, "1m")
+	t.Setenv(envPollIdleInterval, "10m")
+	t.Setenv(envPollJitter, "15s")
+	t.Setenv(envPollReplaceGames, "true")
+	t.Setenv(envAllowFinalRegress, "true")
 	t.Setenv(envProvider, "balldontlie")
+	t.Setenv(envProviderRateLimit, "15s")
+	t.Setenv(envClientNames, "bff, ios-app,,")
+	t.Setenv(envStreamMax, "5")
+	t.Setenv(envLogSampleRate, "10")
+	t.Setenv(envLogSlowRequest, "2s")
+	t.Setenv(envLogRedactParams, "sig, partner_secret")
+	t.Setenv(envHandlerTimeout, "3s")
+	t.Setenv(envShutdownPreStop, "15s")
+	t.Setenv(envRetryBudgetRatio, "0.25")
+	t.Setenv(envRetryBudgetWindow, "30s")
+	t.Setenv(envRetryBudgetMinRetries, "0")
+	t.Setenv(envQuotaThreshold, "0")
+	t.Setenv(envQuotaWindow, "1h")
+	t.Setenv(envLogLevel, "debug")
+	t.Setenv(envClockSkewThreshold, "6h")
+	t.Setenv(envWebhookURL, "https://hooks.example.com/nba")
+	t.Setenv(envWebhookSecret, "hook-secret")
+	t.Setenv(envWebhookAttempts, "5")
+	t.Setenv(envWebhookTimeout, "2s")
 	t.Setenv(envBdlBaseURL, "http://example.com/api")
 	t.Setenv(envBdlAPIKey, "secret-key")
+	t.Setenv(envBdlAPIKey2, "next-key")
 	t.Setenv(envBdlTimezone, "UTC")
 	t.Setenv(envBdlMaxPages, "2")
+	t.Setenv(envBdlTimeout, "15s")
+	t.Setenv(envBdlMaxIdleConnsPerHost, "4")
+	t.Setenv(envBdlResponseHeaderTimeout, "3s")
+	t.Setenv(envBdlHTTP2, "true")
+	t.Setenv(envBdlMaxBodyBytes, "1048576")
+	t.Setenv(envBdlStrictDecode, "true")
+	t.Setenv(envBdlCaptureDir, "/tmp/capture")
+	t.Setenv(envReplayDir, "/tmp/replay")
 	t.Setenv(envMetricsOn, "false")
 	t.Setenv(envMetricsPort, "9999")
+	t.Setenv(envMetricsMaxProviders, "8")
+	t.Setenv(envMetricsBuckets, "1,5,20")
 	t.Setenv(envOtelEndpoint, "http://otel-collector:4318")
 	t.Setenv(envOtelService, "custom-service")
 	t.Setenv(envOtelInsecure, "false")
@@ -100,7 +295,21 @@ func TestLoadOverrides(t *testing.T) {
 	t.Setenv(envSnapshotDays, "3")
 	t.Setenv(envSnapshotFutureDays, "4")
 	t.Setenv(envSnapshotRate, "1m")
+	t.Setenv(envSnapshotConcurrency, "3")
+	t.Setenv(envSnapshotZones, "Asia/Tokyo, Europe/London")
 	t.Setenv(envSnapshotHour, "5")
+	t.Setenv(envSnapshotMinute, "45")
+	t.Setenv(envSnapshotDir, "/var/lib/nba/snapshots")
+	t.Setenv(envSnapshotCache, "0")
+	t.Setenv(envSnapshotFreeze, "30m")
+	t.Setenv(envRetentionTeams, "365")
+	t.Setenv(envRetentionPlayers, "30")
+	t.Setenv(envSnapshotHistory, "true")
+	t.Setenv(envRetentionHistory, "3")
+	t.Setenv(envSeasonStart, "2024-10-22")
+	t.Setenv(envSeasonEnd, "2025-06-22")
+	t.Setenv(envForceOffseasonSync, "true")
+	t.Setenv(envAdminTimeout, "30s")
 
 	cfg := Load()
 
@@ -110,21 +319,82 @@ func TestLoadOverrides(t *testing.T) {
 	if cfg.PollInterval != 45*time.Second {
 		t.Fatalf("expected poll interval 45s, got %s", cfg.PollInterval)
 	}
+	if cfg.PollFetchTimeout != 5*time.Second {
+		t.Fatalf("expected poll fetch timeout 5s, got %s", cfg.PollFetchTimeout)
+	}
+	if cfg.PollLiveInterval != 10*time.Second || cfg.PollPreGameInterval != time.Minute || cfg.PollIdleInterval != 10*time.Minute {
+		t.Fatalf("expected adaptive poll intervals 10s/1m/10m, got %s/%s/%s", cfg.PollLiveInterval, cfg.PollPreGameInterval, cfg.PollIdleInterval)
+	}
+	if cfg.PollJitter != 15*time.Second {
+		t.Fatalf("expected poll jitter 15s, got %s", cfg.PollJitter)
+	}
+	if !cfg.PollReplaceGames {
+		t.Fatalf("expected POLL_REPLACE_GAMES override")
+	}
+	if !cfg.AllowRegression {
+		t.Fatalf("expected ALLOW_FINAL_REGRESSION override")
+	}
 	if cfg.Provider != "balldontlie" {
 		t.Fatalf("expected provider balldontlie, got %s", cfg.Provider)
 	}
+	if len(cfg.ClientNames) != 2 || cfg.ClientNames[0] != "bff" || cfg.ClientNames[1] != "ios-app" {
+		t.Fatalf("expected client names [bff ios-app], got %v", cfg.ClientNames)
+	}
+	if len(cfg.LogRedactParams) != 2 || cfg.LogRedactParams[1] != "partner_secret" {
+		t.Fatalf("expected redacted params [sig partner_secret], got %v", cfg.LogRedactParams)
+	}
+	if cfg.StreamMax != 5 {
+		t.Fatalf("expected stream max override 5, got %d", cfg.StreamMax)
+	}
+	if cfg.HandlerTimeout != Duration(3*time.Second) {
+		t.Fatalf("expected handler timeout 3s, got %s", time.Duration(cfg.HandlerTimeout))
+	}
+	if cfg.ShutdownPreStop != Duration(15*time.Second) {
+		t.Fatalf("expected pre-stop delay 15s, got %s", time.Duration(cfg.ShutdownPreStop))
+	}
+	if cfg.ProviderRateLimit != Duration(15*time.Second) {
+		t.Fatalf("expected provider rate limit 15s, got %s", time.Duration(cfg.ProviderRateLimit))
+	}
+	if want := (RetryBudgetConfig{Ratio: 0.25, Window: Duration(30 * time.Second)}); cfg.RetryBudget != want {
+		t.Fatalf("expected retry budget overrides %+v, got %+v", want, cfg.RetryBudget)
+	}
+	if want := (QuotaConfig{Window: Duration(time.Hour)}); cfg.Quota != want {
+		t.Fatalf("expected quota overrides %+v, got %+v", want, cfg.Quota)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Fatalf("expected log level debug, got %q", cfg.LogLevel)
+	}
+	if cfg.LogSampleRate != 10 || cfg.LogSlowRequest != Duration(2*time.Second) {
+		t.Fatalf("expected log sampling overrides, got rate %d threshold %s", cfg.LogSampleRate, time.Duration(cfg.LogSlowRequest))
+	}
+	want := WebhookConfig{URL: "https://hooks.example.com/nba", Secret: "hook-secret", MaxAttempts: 5, Timeout: 2 * time.Second}
+	if cfg.Webhook != want {
+		t.Fatalf("expected webhook overrides %+v, got %+v", want, cfg.Webhook)
+	}
 	if cfg.Balldontlie.BaseURL != "http://example.com/api" {
 		t.Fatalf("expected balldontlie base url override, got %s", cfg.Balldontlie.BaseURL)
 	}
 	if cfg.Balldontlie.APIKey != "secret-key" {
 		t.Fatalf("expected balldontlie api key override, got %s", cfg.Balldontlie.APIKey)
 	}
+	if cfg.Balldontlie.SecondaryAPIKey != "next-key" {
+		t.Fatalf("expected secondary api key override, got %s", cfg.Balldontlie.SecondaryAPIKey)
+	}
 	if cfg.Balldontlie.Timezone != "UTC" {
 		t.Fatalf("expected balldontlie timezone override, got %s", cfg.Balldontlie.Timezone)
 	}
 	if cfg.Balldontlie.MaxPages != 2 {
 		t.Fatalf("expected balldontlie max pages override, got %d", cfg.Balldontlie.MaxPages)
 	}
+	if b := cfg.Balldontlie; b.Timeout != 15*time.Second || b.MaxIdleConnsPerHost != 4 || b.ResponseHeaderTimeout != 3*time.Second || !b.HTTP2 {
+		t.Fatalf("expected balldontlie transport overrides, got %+v", b)
+	}
+	if b := cfg.Balldontlie; b.MaxBodyBytes != 1<<20 || !b.StrictDecode {
+		t.Fatalf("expected decode overrides, got %d %v", b.MaxBodyBytes, b.StrictDecode)
+	}
+	if cfg.Balldontlie.CaptureDir != "/tmp/capture" || cfg.ReplayDir != "/tmp/replay" {
+		t.Fatalf("expected replay overrides, got %q %q", cfg.Balldontlie.CaptureDir, cfg.ReplayDir)
+	}
 	if cfg.Metrics.Enabled {
 		t.Fatalf("expected metrics disabled via env override")
 	}
@@ -140,6 +410,12 @@ func TestLoadOverrides(t *testing.T) {
 	if cfg.Metrics.OtlpInsecure {
 		t.Fatalf("expected otlp insecure false override")
 	}
+	if cfg.Metrics.MaxProviders != 8 {
+		t.Fatalf("expected metrics max providers override 8, got %d", cfg.Metrics.MaxProviders)
+	}
+	if !slices.Equal(cfg.Metrics.LatencyBuckets, []float64{1, 5, 20}) {
+		t.Fatalf("expected latency buckets override, got %v", cfg.Metrics.LatencyBuckets)
+	}
 	if cfg.Snapshots.Enabled {
 		t.Fatalf("expected snapshot sync disabled via env override")
 	}
@@ -152,12 +428,70 @@ func TestLoadOverrides(t *testing.T) {
 	if cfg.Snapshots.Interval != time.Minute {
 		t.Fatalf("expected snapshot interval 1m, got %s", cfg.Snapshots.Interval)
 	}
+	if cfg.Snapshots.Concurrency != 3 {
+		t.Fatalf("expected snapshot sync concurrency 3, got %d", cfg.Snapshots.Concurrency)
+	}
+	if len(cfg.Snapshots.Zones) != 2 || cfg.Snapshots.Zones[0] != "Asia/Tokyo" {
+		t.Fatalf("expected snapshot zones [Asia/Tokyo Europe/London], got %v", cfg.Snapshots.Zones)
+	}
 	if cfg.Snapshots.DailyHourUTC != 5 {
 		t.Fatalf("expected snapshot daily hour 5, got %d", cfg.Snapshots.DailyHourUTC)
 	}
+	if cfg.Snapshots.DailyMinuteUTC != 45 {
+		t.Fatalf("expected snapshot daily minute 45, got %d", cfg.Snapshots.DailyMinuteUTC)
+	}
+	if cfg.Snapshots.SnapshotFolder != "/var/lib/nba/snapshots" {
+		t.Fatalf("expected snapshot dir override, got %s", cfg.Snapshots.SnapshotFolder)
+	}
+	if cfg.Snapshots.CacheEntries != 0 {
+		t.Fatalf("expected snapshot cache disabled, got %d", cfg.Snapshots.CacheEntries)
+	}
+	if cfg.Snapshots.FreezeGrace != 30*time.Minute {
+		t.Fatalf("expected freeze grace 30m, got %s", cfg.Snapshots.FreezeGrace)
+	}
+	if cfg.Snapshots.SkewThreshold != 6*time.Hour {
+		t.Fatalf("expected skew threshold 6h, got %s", cfg.Snapshots.SkewThreshold)
+	}
+	if cfg.Snapshots.AdminTimeout != 30*time.Second {
+		t.Fatalf("expected admin timeout 30s, got %s", cfg.Snapshots.AdminTimeout)
+	}
 	if cfg.Snapshots.RetentionDays != 4 {
 		t.Fatalf("expected retention days 4, got %d", cfg.Snapshots.RetentionDays)
 	}
+	if cfg.Snapshots.TeamsDays != 365 || cfg.Snapshots.PlayersDays != 30 {
+		t.Fatalf("expected teams/players retention 365/30, got %d/%d", cfg.Snapshots.TeamsDays, cfg.Snapshots.PlayersDays)
+	}
+	if !cfg.Snapshots.History || cfg.Snapshots.HistoryDays != 3 {
+		t.Fatalf("expected snapshot history on with 3 days, got %t/%d", cfg.Snapshots.History, cfg.Snapshots.HistoryDays)
+	}
+	if cfg.Snapshots.SeasonStart != "2024-10-22" || cfg.Snapshots.SeasonEnd != "2025-06-22" || !cfg.Snapshots.ForceOffseason {
+		t.Fatalf("expected season overrides, got %s..%s force=%t", cfg.Snapshots.SeasonStart, cfg.Snapshots.SeasonEnd, cfg.Snapshots.ForceOffseason)
+	}
+}
+
+func TestLoadMalformedSeasonDateFallsBack(t *testing.T) {
+	t.Setenv(envConfigFile, "")
+	t.Setenv(envSeasonStart, "October")
+	t.Setenv(envSeasonEnd, "")
+
+	cfg := Load()
+	if cfg.Snapshots.SeasonStart != "" {
+		t.Fatalf("expected a malformed season start to be ignored, got %q", cfg.Snapshots.SeasonStart)
+	}
+	if got := strings.Join(cfg.Fallbacks(), "\n"); !strings.Contains(got, `SNAPSHOT_SEASON_START="October" is not a YYYY-MM-DD date`) {
+		t.Fatalf("expected the season start fallback reported, got %q", got)
+	}
+}
+
+func TestLoadGamesRetentionOverridesSyncWindow(t *testing.T) {
+	t.Setenv(envSnapshotDays, "3")
+	t.Setenv(envRetentionGames, "14")
+
+	cfg := Load()
+
+	if cfg.Snapshots.RetentionDays != 14 {
+		t.Fatalf("expected explicit games retention 14, got %d", cfg.Snapshots.RetentionDays)
+	}
 }
 
 func TestLoadInvalidDurationFallsBack(t *testing.T) {
@@ -179,3 +513,36 @@ func TestLoadNonPositiveDurationFallsBack(t *testing.T) {
 		t.Fatalf("expected default poll interval on non-positive value, got %s", cfg.PollInterval)
 	}
 }
+
+func TestLoadAPIKeys(t *testing.T) {
+	t.Setenv(envConfigFile, "")
+	t.Setenv(envAPIKeyRequired, "true")
+	t.Setenv(envAPIKeys, "k-alpha:alpha:120, k-beta:beta, k-secret-bad:gamma:lots, :noname")
+
+	cfg := Load()
+	want := []APIKey{{Key: "k-alpha", Name: "alpha", RPM: 120}, {Key: "k-beta", Name: "beta"}}
+	if !cfg.APIKeys.Required || len(cfg.APIKeys.Keys) != 2 || cfg.APIKeys.Keys[0] != want[0] || cfg.APIKeys.Keys[1] != want[1] {
+		t.Fatalf("unexpected api keys %+v", cfg.APIKeys)
+	}
+	got := strings.Join(cfg.Fallbacks(), "\n")
+	if !strings.Contains(got, "API_KEYS entry 3 is not") || !strings.Contains(got, "API_KEYS entry 4 is not") {
+		t.Fatalf("expected malformed entries reported, got %q", got)
+	}
+	if strings.Contains(got, "k-secret-bad") {
+		t.Fatalf("expected fallbacks not to echo key values, got %q", got)
+	}
+}
+
+func TestConfigLocation(t *testing.T) {
+	cases := map[string]string{
+		"America/New_York": "America/New_York",
+		"":                 "UTC",
+		"Not/A_Zone":       "UTC",
+	}
+	for tz, want := range cases {
+		cfg := Config{Balldontlie: BalldontlieConfig{Timezone: tz}}
+		if got := cfg.Location().String(); got != want {
+			t.Fatalf("timezone %q: expected location %s, got %s", tz, want, got)
+		}
+	}
+}
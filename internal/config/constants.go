@@ -3,31 +3,97 @@ package config
 import "time"
 
 const (
-	envPort               = "PORT"
-	envPollInterval       = "POLL_INTERVAL"
-	envProvider           = "PROVIDER"
-	envMetricsPort        = "METRICS_PORT"
-	envMetricsOn          = "METRICS_ENABLED"
-	envOtelEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
-	envOtelService        = "OTEL_SERVICE_NAME"
-	envOtelInsecure       = "OTEL_EXPORTER_OTLP_INSECURE"
-	envAdminToken         = "ADMIN_TOKEN"
-	envSnapshotSync       = "SNAPSHOT_SYNC_ENABLED"
-	envSnapshotDays       = "SNAPSHOT_SYNC_DAYS"
-	envSnapshotFutureDays = "SNAPSHOT_FUTURE_DAYS"
-	envSnapshotRate       = "SNAPSHOT_SYNC_INTERVAL"
-	envSnapshotHour       = "SNAPSHOT_DAILY_HOUR"
+	envPort                = "PORT"
+	envPollInterval        = "POLL_INTERVAL"
+	envPollFetchTimeout    = "POLL_FETCH_TIMEOUT"
+	envPollLiveInterval    = "POLL_LIVE_INTERVAL"
+	envPollPreGameInterval = "POLL_PREGAME_INTERVAL"
+	envPollIdleInterval    = "POLL_IDLE_INTERVAL"
+	envPollJitter          = "POLL_JITTER"
+	envPollReplaceGames    = "POLL_REPLACE_GAMES"
+	envAllowFinalRegress   = "ALLOW_FINAL_REGRESSION"
+	envProvider            = "PROVIDER"
+	envClientNames         = "CLIENT_NAMES"
+	envStreamMax           = "STREAM_MAX_CONNECTIONS"
+	envLogSampleRate       = "LOG_SAMPLE_RATE"
+	envLogSlowRequest      = "LOG_SLOW_REQUEST_THRESHOLD"
+	envLogRedactParams     = "LOG_REDACT_QUERY_PARAMS"
+	envLogLevel            = "LOG_LEVEL"
+	envHandlerTimeout      = "HANDLER_TIMEOUT"
+	envShutdownPreStop     = "SHUTDOWN_PRESTOP_DELAY"
+	envProviderRateLimit   = "PROVIDER_RATE_LIMIT_INTERVAL"
+	envReplayDir           = "REPLAY_DIR"
+	envValidateStrict      = "VALIDATE_STRICT"
+	envConfigFile          = "CONFIG_FILE"
+	envMetricsPort         = "METRICS_PORT"
+	envMetricsOn           = "METRICS_ENABLED"
+	envMetricsMaxProviders = "METRICS_MAX_PROVIDERS"
+	envMetricsBuckets      = "METRICS_LATENCY_BUCKETS"
+	envOtelEndpoint        = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOtelService         = "OTEL_SERVICE_NAME"
+	envOtelInsecure        = "OTEL_EXPORTER_OTLP_INSECURE"
+	envAdminToken          = "ADMIN_TOKEN"
+	envAdminTimeout        = "ADMIN_FETCH_TIMEOUT"
+	envSnapshotSync        = "SNAPSHOT_SYNC_ENABLED"
+	envSnapshotDays        = "SNAPSHOT_SYNC_DAYS"
+	envSnapshotFutureDays  = "SNAPSHOT_FUTURE_DAYS"
+	envSnapshotRate        = "SNAPSHOT_SYNC_INTERVAL"
+	envSnapshotConcurrency = "SNAPSHOT_SYNC_CONCURRENCY"
+	envSnapshotZones       = "SNAPSHOT_SYNC_ZONES"
+	envSnapshotHour        = "SNAPSHOT_DAILY_HOUR"
+	envSnapshotMinute      = "SNAPSHOT_DAILY_MINUTE"
+	envSnapshotDir         = "SNAPSHOT_DIR"
+	envSnapshotCache       = "SNAPSHOT_CACHE_ENTRIES"
+	envSnapshotFreeze      = "SNAPSHOT_FREEZE_GRACE"
+	envRetentionGames      = "SNAPSHOT_RETENTION_GAMES_DAYS"
+	envRetentionTeams      = "SNAPSHOT_RETENTION_TEAMS_DAYS"
+	envRetentionPlayers    = "SNAPSHOT_RETENTION_PLAYERS_DAYS"
+	envSnapshotHistory     = "SNAPSHOT_HISTORY_ENABLED"
+	envRetentionHistory    = "SNAPSHOT_HISTORY_RETENTION_DAYS"
+	envClockSkewThreshold  = "CLOCK_SKEW_THRESHOLD"
+	envSeasonStart         = "SNAPSHOT_SEASON_START"
+	envSeasonEnd           = "SNAPSHOT_SEASON_END"
+	envForceOffseasonSync  = "FORCE_OFFSEASON_SYNC"
 
 	defaultPort = "4000"
 	// Conservative default poll interval to respect upstream quotas (balldontlie: 5 req/min).
-	defaultPollInterval       = 2 * Duration(time.Minute)
-	defaultProvider           = "fixture"
-	defaultMetricsPort        = "9090"
-	defaultSnapshotSync       = true
-	defaultSnapshotDays       = 7
-	defaultSnapshotFutureDays = 7
+	defaultPollInterval = 2 * Duration(time.Minute)
+	// Upper bound on a single poller fetch so a hung upstream cannot stall a cycle.
+	defaultPollFetchTimeout = 20 * Duration(time.Second)
+	defaultProvider         = "fixture"
+	// Minimum spacing between upstream fetches (balldontlie's free tier allows 5 req/min).
+	defaultProviderRateLimit   = Duration(time.Minute)
+	defaultStreamMax           = 100
+	defaultLogSampleRate       = 1
+	defaultMetricsPort         = "9090"
+	defaultMetricsMaxProviders = 64
+	defaultSnapshotSync        = true
+	defaultSnapshotDays        = 7
+	defaultSnapshotFutureDays  = 7
 	// Snapshot fetch cadence during backfill; spaced to stay under upstream quota and leave headroom.
 	defaultSnapshotInterval = 90 * Duration(time.Second)
 	// UTC hour to run daily snapshot prune/backfill (2 AM UTC by default).
 	defaultSnapshotDailyHour = 2
+	defaultSnapshotDir       = "data/snapshots"
+	defaultReplayDir         = "data/replay"
+	// Teams rarely change, so keep them effectively forever; players roll over within a season.
+	defaultRetentionTeams   = 3650
+	defaultRetentionPlayers = 60
+	// Archived versions of replaced games snapshots are a debugging aid; a week covers most investigations.
+	defaultRetentionHistory = 7
+	// Decoded snapshots kept in memory; 0 reads every request from disk.
+	defaultSnapshotCache = 64
+	// How long a date's games must all stay final before the snapshot is frozen (late stat corrections land within hours).
+	defaultSnapshotFreeze = 6 * Duration(time.Hour)
+	// Disagreement between wall clock and snapshots/provider before the clock is distrusted.
+	defaultClockSkewThreshold = 24 * Duration(time.Hour)
+	// Admin refreshes may page through a full slate; allow more headroom than a poll cycle.
+	defaultAdminTimeout = 2 * Duration(time.Minute)
+	defaultLogLevel     = "info"
+	// Requests slower than this are logged at Warn even when sampled out.
+	defaultLogSlowRequest = 500 * Duration(time.Millisecond)
+	// Read endpoints answer 504 past this; streams and admin refreshes are exempt.
+	defaultHandlerTimeout = 10 * Duration(time.Second)
+	// Time between failing /ready and closing listeners, so load balancers notice first.
+	defaultShutdownPreStop = 5 * Duration(time.Second)
 )
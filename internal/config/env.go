@@ -1,17 +1,42 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 // Duration wraps time.Duration for clearer type usage in Config.
 type Duration = time.Duration
 
+// fallbacks collects env values the helpers below rejected in favour of a
+// default, and fileValues holds the config file's settings keyed by env var.
+// Load holds loadMu for its whole run, so each Config sees only its own.
+var (
+	loadMu     sync.Mutex
+	fallbacks  []string
+	fileValues map[string]string
+)
+
+// getenv returns the env var, or the config file's value when it is unset.
+func getenv(key string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+	return fileValues[key]
+}
+
+func noteFallback(key, raw, want string) {
+	fallbacks = append(fallbacks, fmt.Sprintf("%s=%q is not %s; using the default", key, raw, want))
+}
+
 func envOrDefault(key, defaultValue string) string {
-	val := os.Getenv(key)
+	val := getenv(key)
 	if val != "" {
 		return val
 	}
@@ -19,32 +44,119 @@ func envOrDefault(key, defaultValue string) string {
 }
 
 func durationEnvOrDefault(key string, defaultValue time.Duration) time.Duration {
-	raw := os.Getenv(key)
+	raw := getenv(key)
 	if raw == "" {
 		return defaultValue
 	}
 
 	parsed, err := time.ParseDuration(raw)
 	if err != nil || parsed <= 0 {
+		noteFallback(key, raw, "a positive duration")
+		return defaultValue
+	}
+	return parsed
+}
+
+// nonNegativeDurationEnvOrDefault is durationEnvOrDefault but accepts 0, for settings where zero disables a feature.
+func nonNegativeDurationEnvOrDefault(key string, defaultValue time.Duration) time.Duration {
+	raw := getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	parsed, err := time.ParseDuration(raw)
+	if err != nil || parsed < 0 {
+		noteFallback(key, raw, "a non-negative duration")
 		return defaultValue
 	}
 	return parsed
 }
 
 func intEnvOrDefault(key string, defaultValue int) int {
-	raw := os.Getenv(key)
+	raw := getenv(key)
 	if raw == "" {
 		return defaultValue
 	}
 	val, err := strconv.Atoi(raw)
 	if err != nil || val <= 0 {
+		noteFallback(key, raw, "a positive integer")
 		return defaultValue
 	}
 	return val
 }
 
+// nonNegativeIntEnvOrDefault is intEnvOrDefault but accepts 0, for settings where zero disables a feature.
+func nonNegativeIntEnvOrDefault(key string, defaultValue int) int {
+	raw := getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	val, err := strconv.Atoi(raw)
+	if err != nil || val < 0 {
+		noteFallback(key, raw, "a non-negative integer")
+		return defaultValue
+	}
+	return val
+}
+
+// ratioEnvOrDefault parses a fraction in (0, 1].
+func ratioEnvOrDefault(key string, defaultValue float64) float64 {
+	raw := getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	val, err := strconv.ParseFloat(raw, 64)
+	if err != nil || val <= 0 || val > 1 {
+		noteFallback(key, raw, "a ratio in (0, 1]")
+		return defaultValue
+	}
+	return val
+}
+
+// bucketsEnvOrDefault parses comma-separated, strictly ascending positive
+// numbers, such as histogram bucket boundaries.
+func bucketsEnvOrDefault(key string, defaultValue []float64) []float64 {
+	raw := getenv(key)
+	if raw == "" {
+		return defaultValue
+	}
+	var out []float64
+	for _, part := range strings.Split(raw, ",") {
+		val, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || val <= 0 || (len(out) > 0 && val <= out[len(out)-1]) {
+			noteFallback(key, raw, "ascending positive numbers")
+			return defaultValue
+		}
+		out = append(out, val)
+	}
+	return out
+}
+
+// dateEnv returns a YYYY-MM-DD env var, or "" when it is unset or malformed.
+func dateEnv(key string) string {
+	raw := strings.TrimSpace(getenv(key))
+	if raw == "" {
+		return ""
+	}
+	if _, err := timeutil.ParseDate(raw); err != nil {
+		noteFallback(key, raw, "a YYYY-MM-DD date")
+		return ""
+	}
+	return raw
+}
+
+// listEnv splits a comma-separated env var, dropping blanks.
+func listEnv(key string) []string {
+	var out []string
+	for _, part := range strings.Split(getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 func boolEnvOrDefault(key string, defaultValue bool) bool {
-	raw := strings.TrimSpace(os.Getenv(key))
+	raw := strings.TrimSpace(getenv(key))
 	if raw == "" {
 		return defaultValue
 	}
@@ -54,5 +166,6 @@ func boolEnvOrDefault(key string, defaultValue bool) bool {
 	if raw == "0" || strings.EqualFold(raw, "false") || strings.EqualFold(raw, "no") {
 		return false
 	}
+	noteFallback(key, raw, "a boolean")
 	return defaultValue
 }
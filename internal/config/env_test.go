@@ -1,6 +1,10 @@
 package config
 
-import "testing"
+import (
+	"slices"
+	"testing"
+	"time"
+)
 
 func TestBoolEnvOrDefault(t *testing.T) {
 	t.Setenv("BOOL_TEST", "")
@@ -30,3 +34,83 @@ func TestBoolEnvOrDefault(t *testing.T) {
 		}
 	}
 }
+
+func TestNonNegativeIntEnvOrDefault(t *testing.T) {
+	cases := []struct {
+		val      string
+		expected int
+	}{
+		{"", 64},
+		{"0", 0},
+		{"10", 10},
+		{"-1", 64},
+		{"many", 64},
+	}
+	for _, tc := range cases {
+		t.Setenv("INT_TEST", tc.val)
+		if got := nonNegativeIntEnvOrDefault("INT_TEST", 64); got != tc.expected {
+			t.Fatalf("expected %d for %q, got %d", tc.expected, tc.val, got)
+		}
+	}
+}
+
+func TestRatioEnvOrDefault(t *testing.T) {
+	cases := []struct {
+		val      string
+		expected float64
+	}{
+		{"", 0.1},
+		{"0.5", 0.5},
+		{"1", 1},
+		{"0", 0.1},
+		{"1.5", 0.1},
+		{"half", 0.1},
+	}
+	for _, tc := range cases {
+		t.Setenv("RATIO_TEST", tc.val)
+		if got := ratioEnvOrDefault("RATIO_TEST", 0.1); got != tc.expected {
+			t.Fatalf("expected %v for %q, got %v", tc.expected, tc.val, got)
+		}
+	}
+}
+
+func TestBucketsEnvOrDefault(t *testing.T) {
+	def := []float64{5, 10}
+	cases := []struct {
+		val      string
+		expected []float64
+	}{
+		{"", def},
+		{"1, 2.5,10", []float64{1, 2.5, 10}},
+		{"10,5", def},
+		{"1,1", def},
+		{"0,5", def},
+		{"1,fast", def},
+	}
+	for _, tc := range cases {
+		t.Setenv("BUCKETS_TEST", tc.val)
+		if got := bucketsEnvOrDefault("BUCKETS_TEST", def); !slices.Equal(got, tc.expected) {
+			t.Fatalf("expected %v for %q, got %v", tc.expected, tc.val, got)
+		}
+	}
+}
+
+func TestNonNegativeDurationEnvOrDefault(t *testing.T) {
+	cases := []struct {
+		val      string
+		expected time.Duration
+	}{
+		{"", 5 * time.Second},
+		{"0", 0},
+		{"0s", 0},
+		{"2s", 2 * time.Second},
+		{"-1s", 5 * time.Second},
+		{"soon", 5 * time.Second},
+	}
+	for _, tc := range cases {
+		t.Setenv("DURATION_TEST", tc.val)
+		if got := nonNegativeDurationEnvOrDefault("DURATION_TEST", 5*time.Second); got != tc.expected {
+			t.Fatalf("expected %s for %q, got %s", tc.expected, tc.val, got)
+		}
+	}
+}
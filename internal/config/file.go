@@ -0,0 +1,286 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fileKeys maps config file keys, which mirror Config's field names, to the
+// env var each stands in for. Keys match case-insensitively; nested structs
+// are sections (balldontlie.apiKey or, in YAML, apiKey under balldontlie).
+var fileKeys = map[string]string{
+	"port":                              envPort,
+	"pollInterval":                      envPollInterval,
+	"pollFetchTimeout":                  envPollFetchTimeout,
+	"pollLiveInterval":                  envPollLiveInterval,
+	"pollPreGameInterval":               envPollPreGameInterval,
+	"pollIdleInterval":                  envPollIdleInterval,
+	"pollJitter":                        envPollJitter,
+	"pollReplaceGames":                  envPollReplaceGames,
+	"allowFinalRegression":              envAllowFinalRegress,
+	"provider":                          envProvider,
+	"providerRateLimit":                 envProviderRateLimit,
+	"replayDir":                         envReplayDir,
+	"clientNames":                       envClientNames,
+	"streamMax":                         envStreamMax,
+	"logSampleRate":                     envLogSampleRate,
+	"logSlowRequest":                    envLogSlowRequest,
+	"logRedactQueryParams":              envLogRedactParams,
+	"logLevel":                          envLogLevel,
+	"handlerTimeout":                    envHandlerTimeout,
+	"shutdownPreStop":                   envShutdownPreStop,
+	"validateStrict":                    envValidateStrict,
+	"balldontlie.baseURL":               envBdlBaseURL,
+	"balldontlie.apiKey":                envBdlAPIKey,
+	"balldontlie.secondaryAPIKey":       envBdlAPIKey2,
+	"balldontlie.timezone":              envBdlTimezone,
+	"balldontlie.maxPages":              envBdlMaxPages,
+	"balldontlie.pageDelay":             envBdlPageDelay,
+	"balldontlie.timeout":               envBdlTimeout,
+	"balldontlie.maxIdleConns":          envBdlMaxIdleConns,
+	"balldontlie.maxIdleConnsPerHost":   envBdlMaxIdleConnsPerHost,
+	"balldontlie.idleConnTimeout":       envBdlIdleConnTimeout,
+	"balldontlie.tlsHandshakeTimeout":   envBdlTLSHandshakeTimeout,
+	"balldontlie.responseHeaderTimeout": envBdlResponseHeaderTimeout,
+	"balldontlie.http2":                 envBdlHTTP2,
+	"balldontlie.maxBodyBytes":          envBdlMaxBodyBytes,
+	"balldontlie.strictDecode":          envBdlStrictDecode,
+	"balldontlie.captureDir":            envBdlCaptureDir,
+	"metrics.enabled":                   envMetricsOn,
+	"metrics.port":                      envMetricsPort,
+	"metrics.otlpEndpoint":              envOtelEndpoint,
+	"metrics.serviceName":               envOtelService,
+	"metrics.otlpInsecure":              envOtelInsecure,
+	"metrics.maxProviders":              envMetricsMaxProviders,
+	"metrics.latencyBuckets":            envMetricsBuckets,
+	"snapshots.enabled":                 envSnapshotSync,
+	"snapshots.days":                    envSnapshotDays,
+	"snapshots.futureDays":              envSnapshotFutureDays,
+	"snapshots.interval":                envSnapshotRate,
+	"snapshots.concurrency":             envSnapshotConcurrency,
+	"snapshots.zones":                   envSnapshotZones,
+	"snapshots.dailyHourUTC":            envSnapshotHour,
+	"snapshots.dailyMinuteUTC":          envSnapshotMinute,
+	"snapshots.retentionDays":           envRetentionGames,
+	"snapshots.teamsDays":               envRetentionTeams,
+	"snapshots.playersDays":             envRetentionPlayers,
+	"snapshots.adminToken":              envAdminToken,
+	"snapshots.adminTimeout":            envAdminTimeout,
+	"snapshots.snapshotFolder":          envSnapshotDir,
+	"snapshots.cacheEntries":            envSnapshotCache,
+	"snapshots.freezeGrace":             envSnapshotFreeze,
+	"snapshots.skewThreshold":           envClockSkewThreshold,
+	"snapshots.history":                 envSnapshotHistory,
+	"snapshots.historyDays":             envRetentionHistory,
+	"snapshots.seasonStart":             envSeasonStart,
+	"snapshots.seasonEnd":               envSeasonEnd,
+	"snapshots.forceOffseason":          envForceOffseasonSync,
+	"webhook.url":                       envWebhookURL,
+	"webhook.secret":                    envWebhookSecret,
+	"webhook.maxAttempts":               envWebhookAttempts,
+	"webhook.timeout":                   envWebhookTimeout,
+	"apiKeys.keys":                      envAPIKeys,
+	"apiKeys.required":                  envAPIKeyRequired,
+	"retryBudget.ratio":                 envRetryBudgetRatio,
+	"retryBudget.window":                envRetryBudgetWindow,
+	"retryBudget.minRetries":            envRetryBudgetMinRetries,
+	"quota.threshold":                   envQuotaThreshold,
+	"quota.window":                      envQuotaWindow,
+}
+
+func readConfigFile(path string) (map[string]string, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config file: %w", err)
+	}
+	values, warnings, err := parseConfigFile(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, warnings, nil
+}
+
+// parseConfigFile decodes a config file into env var values, plus a warning
+// for each key that maps to no setting. Documents starting with '{' are JSON;
+// anything else is read as YAML (see parseYAML for the supported subset).
+func parseConfigFile(data []byte) (map[string]string, []string, error) {
+	flat := make(map[string]string)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var doc map[string]any
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, nil, fmt.Errorf("config file: %w", err)
+		}
+		if err := flattenJSON("", doc, flat); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var err error
+		if flat, err = parseYAML(data); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	byLower := make(map[string]string, len(fileKeys))
+	for key, env := range fileKeys {
+		byLower[strings.ToLower(key)] = env
+	}
+	values := make(map[string]string)
+	var warnings []string
+	for key, value := range flat {
+		env, ok := byLower[strings.ToLower(key)]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("config file key %q is unknown; ignoring it", key))
+			continue
+		}
+		values[env] = value
+	}
+	sort.Strings(warnings)
+	return values, warnings, nil
+}
+
+func flattenJSON(prefix string, v any, out map[string]string) error {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if err := flattenJSON(joinKey(prefix, key), child, out); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := jsonScalar(item)
+			if !ok {
+				return fmt.Errorf("config file: %s: lists may only hold scalars", prefix)
+			}
+			items = append(items, s)
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+	default:
+		s, _ := jsonScalar(v)
+		out[prefix] = s
+	}
+	return nil
+}
+
+func jsonScalar(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// parseYAML reads the YAML subset config files need: nested block mappings
+// indented with spaces, scalar values (optionally quoted), lists as "- item"
+// lines or [a, b], and # comments. Anchors, multi-line strings and flow
+// mappings are rejected or read literally.
+func parseYAML(data []byte) (map[string]string, error) {
+	type section struct {
+		indent int
+		prefix string
+	}
+	out := make(map[string]string)
+	stack := []section{{indent: -1}}
+	listKey := "" // the last key opened without a value, which "- item" lines fill
+
+	for n, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(stripYAMLComment(raw), " \r")
+		text := strings.TrimLeft(line, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("config file line %d: indent with spaces, not tabs", n+1)
+		}
+		indent := len(line) - len(text)
+
+		if text == "-" || strings.HasPrefix(text, "- ") {
+			if listKey == "" {
+				return nil, fmt.Errorf("config file line %d: list item without a key", n+1)
+			}
+			item := yamlScalar(strings.TrimSpace(strings.TrimPrefix(text, "-")))
+			if prev, ok := out[listKey]; ok && prev != "" {
+				item = prev + "," + item
+			}
+			out[listKey] = item
+			continue
+		}
+
+		for len(stack) > 1 && indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		key, value, ok := strings.Cut(text, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " {}[]") {
+			return nil, fmt.Errorf("config file line %d: expected \"key: value\", got %q", n+1, text)
+		}
+		full := joinKey(stack[len(stack)-1].prefix, key)
+		value = strings.TrimSpace(value)
+		if value == "" {
+			stack = append(stack, section{indent: indent, prefix: full})
+			listKey = full
+			continue
+		}
+		listKey = ""
+		out[full] = yamlScalar(value)
+	}
+	return out, nil
+}
+
+// yamlScalar unquotes a value and turns an inline [a, b] list into "a,b".
+func yamlScalar(v string) string {
+	if len(v) >= 2 && v[0] == '[' && v[len(v)-1] == ']' {
+		var items []string
+		for _, item := range strings.Split(v[1:len(v)-1], ",") {
+			if item = yamlScalar(strings.TrimSpace(item)); item != "" {
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ",")
+	}
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	if v == "~" || v == "null" {
+		return ""
+	}
+	return v
+}
+
+// stripYAMLComment drops a # comment that starts the line or follows a space,
+// leaving #s inside quotes alone.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
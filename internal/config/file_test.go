@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const yamlConfig = `# service settings
+port: 5000
+pollInterval: 45s
+clientNames: [web, "ios"]
+balldontlie:
+  apiKey: file-key   # overridden by env in one test
+  maxPages: 7
+snapshots:
+  snapshotFolder: /var/snapshots
+webhook:
+  url: "https://hooks.example.com/#games"
+`
+
+func clearFileEnv(t *testing.T) {
+	t.Helper()
+	for _, env := range fileKeys {
+		t.Setenv(env, "")
+	}
+}
+
+func TestLoadFromYAMLFileOnly(t *testing.T) {
+	clearFileEnv(t)
+
+	cfg, err := LoadFrom(strings.NewReader(yamlConfig))
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if cfg.Port != "5000" || cfg.PollInterval != 45*time.Second {
+		t.Fatalf("expected top-level file values, got port=%s poll=%s", cfg.Port, cfg.PollInterval)
+	}
+	if cfg.Balldontlie.APIKey != "file-key" || cfg.Balldontlie.MaxPages != 7 {
+		t.Fatalf("expected nested file values, got %+v", cfg.Balldontlie)
+	}
+	if cfg.Snapshots.SnapshotFolder != "/var/snapshots" || cfg.Webhook.URL != "https://hooks.example.com/#games" {
+		t.Fatalf("unexpected snapshots/webhook values: %q %q", cfg.Snapshots.SnapshotFolder, cfg.Webhook.URL)
+	}
+	if len(cfg.ClientNames) != 2 || cfg.ClientNames[0] != "web" || cfg.ClientNames[1] != "ios" {
+		t.Fatalf("expected list from file, got %v", cfg.ClientNames)
+	}
+	if cfg.LogSampleRate != defaultLogSampleRate {
+		t.Fatalf("expected unset keys to keep defaults, got %d", cfg.LogSampleRate)
+	}
+	if len(cfg.Fallbacks()) != 0 {
+		t.Fatalf("expected no warnings, got %v", cfg.Fallbacks())
+	}
+}
+
+func TestLoadFromJSON(t *testing.T) {
+	clearFileEnv(t)
+
+	cfg, err := LoadFrom(strings.NewReader(`{"port": 5001, "metrics": {"enabled": false}, "clientNames": ["web"]}`))
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if cfg.Port != "5001" || cfg.Metrics.Enabled || len(cfg.ClientNames) != 1 {
+		t.Fatalf("unexpected JSON config: port=%s metrics=%v clients=%v", cfg.Port, cfg.Metrics.Enabled, cfg.ClientNames)
+	}
+}
+
+func TestEnvOverridesConfigFile(t *testing.T) {
+	clearFileEnv(t)
+	t.Setenv(envBdlAPIKey, "env-key")
+	t.Setenv(envPort, "6000")
+
+	cfg, err := LoadFrom(strings.NewReader(yamlConfig))
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if cfg.Balldontlie.APIKey != "env-key" || cfg.Port != "6000" {
+		t.Fatalf("expected env to win, got key=%q port=%q", cfg.Balldontlie.APIKey, cfg.Port)
+	}
+	if cfg.Balldontlie.MaxPages != 7 {
+		t.Fatalf("expected file value where env is unset, got %d", cfg.Balldontlie.MaxPages)
+	}
+}
+
+func TestMalformedConfigFile(t *testing.T) {
+	clearFileEnv(t)
+	for name, doc := range map[string]string{
+		"json":          `{"port": 5000,`,
+		"yaml no colon": "port 5000\n",
+		"yaml tabs":     "balldontlie:\n\tapiKey: k\n",
+	} {
+		if _, err := LoadFrom(strings.NewReader(doc)); err == nil {
+			t.Fatalf("%s: expected parse error", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("port 5000\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv(envConfigFile, path)
+	cfg := Load()
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), path) {
+		t.Fatalf("expected Validate to report the bad file, got %v", err)
+	}
+}
+
+func TestConfigFileUnknownKeysWarn(t *testing.T) {
+	clearFileEnv(t)
+	t.Setenv(envValidateStrict, "")
+
+	cfg, err := LoadFrom(strings.NewReader("port: 5000\npollIntervall: 1m\nmetrics:\n  colour: blue\n"))
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	got := strings.Join(cfg.Fallbacks(), "\n")
+	if !strings.Contains(got, `"pollIntervall"`) || !strings.Contains(got, `"metrics.colour"`) {
+		t.Fatalf("expected unknown keys reported, got %q", got)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected unknown keys to only warn, got %v", err)
+	}
+}
+
+func TestLoadReadsConfigFileEnv(t *testing.T) {
+	clearFileEnv(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"snapshots": {"days": 3}}`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv(envConfigFile, path)
+
+	cfg := Load()
+	if cfg.Snapshots.Days != 3 || cfg.Snapshots.RetentionDays != 4 {
+		t.Fatalf("expected file days and derived retention, got %+v", cfg.Snapshots)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+}
@@ -7,6 +7,9 @@ type MetricsConfig struct {
 	OtlpEndpoint string
 	ServiceName  string
 	OtlpInsecure bool
+	MaxProviders int // provider names tracked by the in-memory recorder before LRU eviction
+	// LatencyBuckets are the latency histogram boundaries in milliseconds.
+	LatencyBuckets []float64
 }
 
 func loadMetrics() MetricsConfig {
@@ -16,5 +19,11 @@ func loadMetrics() MetricsConfig {
 		OtlpEndpoint: envOrDefault(envOtelEndpoint, ""),
 		ServiceName:  envOrDefault(envOtelService, "nba-data-service"),
 		OtlpInsecure: boolEnvOrDefault(envOtelInsecure, true),
+		MaxProviders: intEnvOrDefault(envMetricsMaxProviders, defaultMetricsMaxProviders),
+
+		LatencyBuckets: bucketsEnvOrDefault(envMetricsBuckets, defaultMetricsLatencyBuckets),
 	}
 }
+
+// defaultMetricsLatencyBuckets matches metrics.DefaultLatencyBuckets.
+var defaultMetricsLatencyBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+const (
+	envQuotaThreshold = "PROVIDER_QUOTA_THRESHOLD"
+	envQuotaWindow    = "PROVIDER_QUOTA_WINDOW"
+
+	defaultQuotaThreshold = 5
+	defaultQuotaWindow    = Duration(time.Minute)
+)
+
+// QuotaConfig tunes preemptive throttling: while the upstream reports fewer
+// than Threshold requests left in its Window-long rate limit window, the
+// provider rate limiter stretches its interval.
+type QuotaConfig struct {
+	Threshold int // 0 turns throttling off
+	Window    Duration
+}
+
+func loadQuota() QuotaConfig {
+	return QuotaConfig{
+		Threshold: nonNegativeIntEnvOrDefault(envQuotaThreshold, defaultQuotaThreshold),
+		Window:    durationEnvOrDefault(envQuotaWindow, defaultQuotaWindow),
+	}
+}
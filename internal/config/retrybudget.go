@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	envRetryBudgetRatio      = "PROVIDER_RETRY_BUDGET_RATIO"
+	envRetryBudgetWindow     = "PROVIDER_RETRY_BUDGET_WINDOW"
+	envRetryBudgetMinRetries = "PROVIDER_RETRY_BUDGET_MIN_RETRIES"
+
+	defaultRetryBudgetRatio      = 0.1
+	defaultRetryBudgetWindow     = Duration(time.Minute)
+	defaultRetryBudgetMinRetries = 3
+	// minRetryBudgetWindow matches providers.MinRetryBudgetWindow.
+	minRetryBudgetWindow = Duration(100 * time.Millisecond)
+)
+
+// RetryBudgetConfig sizes the retry budget shared by every provider retry
+// wrapper: at most MinRetries plus Ratio of the calls seen in Window may be retries.
+type RetryBudgetConfig struct {
+	Ratio      float64
+	Window     Duration
+	MinRetries int // 0 leaves only Ratio
+}
+
+func loadRetryBudget() RetryBudgetConfig {
+	return RetryBudgetConfig{
+		Ratio:      ratioEnvOrDefault(envRetryBudgetRatio, defaultRetryBudgetRatio),
+		Window:     durationEnvOrDefault(envRetryBudgetWindow, defaultRetryBudgetWindow),
+		MinRetries: nonNegativeIntEnvOrDefault(envRetryBudgetMinRetries, defaultRetryBudgetMinRetries),
+	}
+}
+
+// validate rejects a window too short to bucket; zero uses the default.
+func (c RetryBudgetConfig) validate() []error {
+	if c.Window > 0 && c.Window < minRetryBudgetWindow {
+		return []error{fmt.Errorf("%s=%s is shorter than %s", envRetryBudgetWindow, time.Duration(c.Window), time.Duration(minRetryBudgetWindow))}
+	}
+	return nil
+}
@@ -8,27 +8,57 @@ type SnapshotSyncConfig struct {
 	Days           int           // how many past days to maintain
 	FutureDays     int           // how many future days to prefetch
 	Interval       time.Duration // delay between snapshot fetches
+	Concurrency    int           // dates a sync run fetches at once
+	Zones          []string      // extra IANA zones synced as zone-scoped snapshots
 	DailyHourUTC   int           // hour of day (0-23) for daily prune/backfill
+	DailyMinuteUTC int           // minute past DailyHourUTC (0-59)
 	RetentionDays  int           // retention for pruning (games)
+	TeamsDays      int           // retention for team snapshots
+	PlayersDays    int           // retention for player snapshots
 	AdminToken     string        // reused for refresh endpoint auth
+	AdminTimeout   time.Duration // upper bound for an admin-triggered refresh fetch
 	SnapshotFolder string        // base path for snapshots
+	CacheEntries   int           // decoded snapshots cached in memory (0 disables)
+	FreezeGrace    time.Duration // all-final time before a date is frozen
+	SkewThreshold  time.Duration // clock skew that suspends pruning
+	History        bool          // archive replaced games snapshots under games/history
+	HistoryDays    int           // retention for archived versions
+	// Active season bounds (YYYY-MM-DD, inclusive; empty is open). Sync skips
+	// dates outside them unless ForceOffseason is set for a backfill.
+	SeasonStart    string
+	SeasonEnd      string
+	ForceOffseason bool
 }
 
 func loadSnapshotSync() SnapshotSyncConfig {
 	// Default retention covers both past and future windows (with some buffer).
 	pastDays := intEnvOrDefault(envSnapshotDays, defaultSnapshotDays)
 	futureDays := intEnvOrDefault(envSnapshotFutureDays, defaultSnapshotFutureDays)
-	// Retain only the rolling past window (+1 for the crossover day); future snapshots are naturally kept.
-	retentionDays := pastDays + 1
+	// Retain only the rolling past window (+1 for the crossover day) unless set explicitly; future snapshots are naturally kept.
+	retentionDays := intEnvOrDefault(envRetentionGames, pastDays+1)
 
 	return SnapshotSyncConfig{
 		Enabled:        boolEnvOrDefault(envSnapshotSync, defaultSnapshotSync),
 		Days:           pastDays,
 		FutureDays:     futureDays,
 		Interval:       durationEnvOrDefault(envSnapshotRate, defaultSnapshotInterval),
+		Concurrency:    intEnvOrDefault(envSnapshotConcurrency, 1),
+		Zones:          listEnv(envSnapshotZones),
 		DailyHourUTC:   intEnvOrDefault(envSnapshotHour, defaultSnapshotDailyHour),
+		DailyMinuteUTC: nonNegativeIntEnvOrDefault(envSnapshotMinute, 0),
 		RetentionDays:  retentionDays,
+		TeamsDays:      intEnvOrDefault(envRetentionTeams, defaultRetentionTeams),
+		PlayersDays:    intEnvOrDefault(envRetentionPlayers, defaultRetentionPlayers),
 		AdminToken:     envOrDefault(envAdminToken, ""),
-		SnapshotFolder: "data/snapshots",
+		AdminTimeout:   durationEnvOrDefault(envAdminTimeout, defaultAdminTimeout),
+		SnapshotFolder: envOrDefault(envSnapshotDir, defaultSnapshotDir),
+		CacheEntries:   nonNegativeIntEnvOrDefault(envSnapshotCache, defaultSnapshotCache),
+		FreezeGrace:    durationEnvOrDefault(envSnapshotFreeze, defaultSnapshotFreeze),
+		SkewThreshold:  durationEnvOrDefault(envClockSkewThreshold, defaultClockSkewThreshold),
+		History:        boolEnvOrDefault(envSnapshotHistory, false),
+		HistoryDays:    intEnvOrDefault(envRetentionHistory, defaultRetentionHistory),
+		SeasonStart:    dateEnv(envSeasonStart),
+		SeasonEnd:      dateEnv(envSeasonEnd),
+		ForceOffseason: boolEnvOrDefault(envForceOffseasonSync, false),
 	}
 }
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fallbacks describes each value Load ignored: config file keys it does not
+// know, and env or file values it could not parse and replaced with their
+// default, e.g. `BALLDONTLIE_MAX_PAGES="five" is not a positive integer`.
+func (c Config) Fallbacks() []string {
+	return append([]string(nil), c.fallbacks...)
+}
+
+// Validate reports every configuration problem at once, joined with
+// errors.Join. An unreadable CONFIG_FILE and settings the service cannot run
+// correctly with always fail; with ValidateStrict, so does every entry in
+// Fallbacks.
+func (c Config) Validate() error {
+	var errs []error
+	if c.fileErr != nil {
+		errs = append(errs, c.fileErr)
+	}
+	switch c.Provider {
+	case "", "fixture", "replay":
+	case "balldontlie":
+		if strings.TrimSpace(c.Balldontlie.APIKey) == "" {
+			errs = append(errs, fmt.Errorf("%s=balldontlie requires %s", envProvider, envBdlAPIKey))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s=%q is not a known provider (fixture, balldontlie, replay)", envProvider, c.Provider))
+	}
+	if !validPort(c.Port) {
+		errs = append(errs, fmt.Errorf("%s=%q is not a port number", envPort, c.Port))
+	}
+	if c.Metrics.Enabled && !validPort(c.Metrics.Port) {
+		errs = append(errs, fmt.Errorf("%s=%q is not a port number (set %s=false to run without metrics)", envMetricsPort, c.Metrics.Port, envMetricsOn))
+	}
+	if h := c.Snapshots.DailyHourUTC; h < 0 || h > 23 {
+		errs = append(errs, fmt.Errorf("%s=%d is outside 0-23", envSnapshotHour, h))
+	}
+	if m := c.Snapshots.DailyMinuteUTC; m < 0 || m > 59 {
+		errs = append(errs, fmt.Errorf("%s=%d is outside 0-59", envSnapshotMinute, m))
+	}
+	if start, end := c.Snapshots.SeasonStart, c.Snapshots.SeasonEnd; start != "" && end != "" && start > end {
+		errs = append(errs, fmt.Errorf("%s=%s is after %s=%s", envSeasonStart, start, envSeasonEnd, end))
+	}
+	for _, zone := range c.Snapshots.Zones {
+		if _, err := time.LoadLocation(zone); err != nil || zone == "Local" {
+			errs = append(errs, fmt.Errorf("%s entry %q is not an IANA timezone", envSnapshotZones, zone))
+		}
+	}
+	errs = append(errs, c.APIKeys.validate()...)
+	errs = append(errs, c.RetryBudget.validate()...)
+	if c.ValidateStrict {
+		for _, f := range c.fallbacks {
+			errs = append(errs, errors.New(f))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func validConfig() Config {
+	return Config{
+		Port:      "4000",
+		Provider:  "fixture",
+		Metrics:   MetricsConfig{Enabled: true, Port: "9090"},
+		Snapshots: SnapshotSyncConfig{DailyHourUTC: 2},
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string // substring of the error; "" means valid
+	}{
+		{"defaults", func(*Config) {}, ""},
+		{"empty provider is fixture", func(c *Config) { c.Provider = "" }, ""},
+		{"unknown provider", func(c *Config) { c.Provider = "espn" }, `PROVIDER="espn" is not a known provider`},
+		{"balldontlie without key", func(c *Config) { c.Provider = "balldontlie" }, "requires BALLDONTLIE_API_KEY"},
+		{"balldontlie with key", func(c *Config) { c.Provider = "balldontlie"; c.Balldontlie.APIKey = "k" }, ""},
+		{"bad http port", func(c *Config) { c.Port = "http" }, `PORT="http" is not a port number`},
+		{"bad metrics port", func(c *Config) { c.Metrics.Port = "70000" }, `METRICS_PORT="70000"`},
+		{"bad metrics port ignored when disabled", func(c *Config) { c.Metrics.Enabled = false; c.Metrics.Port = "x" }, ""},
+		{"daily hour out of range", func(c *Config) { c.Snapshots.DailyHourUTC = 24 }, "SNAPSHOT_DAILY_HOUR=24"},
+		{"daily minute out of range", func(c *Config) { c.Snapshots.DailyMinuteUTC = 60 }, "SNAPSHOT_DAILY_MINUTE=60"},
+		{"unknown snapshot zone", func(c *Config) { c.Snapshots.Zones = []string{"Asia/Tokyo", "Mars/Olympus"} }, `SNAPSHOT_SYNC_ZONES entry "Mars/Olympus" is not an IANA timezone`},
+		{"season ends before it starts", func(c *Config) { c.Snapshots.SeasonStart = "2025-06-22"; c.Snapshots.SeasonEnd = "2024-10-22" }, "SNAPSHOT_SEASON_START=2025-06-22 is after SNAPSHOT_SEASON_END=2024-10-22"},
+		{"api key required without keys", func(c *Config) { c.APIKeys.Required = true }, "API_KEY_REQUIRED=true requires"},
+		{"api key required with keys", func(c *Config) { c.APIKeys = APIKeysConfig{Required: true, Keys: []APIKey{{Key: "k", Name: "a"}}} }, ""},
+		{"repeated api key", func(c *Config) { c.APIKeys.Keys = []APIKey{{Key: "k", Name: "a"}, {Key: "k", Name: "b"}} }, "entry 2 repeats an earlier key"},
+		{"repeated api key name", func(c *Config) { c.APIKeys.Keys = []APIKey{{Key: "k1", Name: "a"}, {Key: "k2", Name: "a"}} }, `repeats the name "a"`},
+		{"retry budget window too short", func(c *Config) { c.RetryBudget.Window = Duration(5 * time.Nanosecond) }, "PROVIDER_RETRY_BUDGET_WINDOW=5ns is shorter than 100ms"},
+		{"fallbacks ignored when lenient", func(c *Config) { c.fallbacks = []string{"POLL_INTERVAL bad"} }, ""},
+		{"fallbacks fail when strict", func(c *Config) { c.ValidateStrict = true; c.fallbacks = []string{"POLL_INTERVAL bad"} }, "POLL_INTERVAL bad"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := validConfig()
+			tc.mutate(&cfg)
+			err := cfg.Validate()
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateJoinsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.Provider = "espn"
+	cfg.Port = ""
+	cfg.Metrics.Port = "x"
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatalf("expected errors")
+	}
+	if lines := strings.Split(err.Error(), "\n"); len(lines) != 3 {
+		t.Fatalf("expected 3 joined problems, got %q", err.Error())
+	}
+}
+
+func TestLoadRecordsFallbacksForStrictMode(t *testing.T) {
+	t.Setenv(envProvider, "")
+	t.Setenv(envPort, "")
+	t.Setenv(envMetricsPort, "")
+	t.Setenv(envBdlMaxPages, "five")
+	t.Setenv(envPollInterval, "-1m")
+	t.Setenv(envValidateStrict, "")
+
+	lenient := Load()
+	if lenient.Balldontlie.MaxPages != defaultBdlMaxPages || lenient.PollInterval != defaultPollInterval {
+		t.Fatalf("expected Load to keep defaulting malformed values")
+	}
+	got := strings.Join(lenient.Fallbacks(), "\n")
+	if !strings.Contains(got, `BALLDONTLIE_MAX_PAGES="five" is not a positive integer`) || !strings.Contains(got, `POLL_INTERVAL="-1m" is not a positive duration`) {
+		t.Fatalf("expected both fallbacks reported, got %q", got)
+	}
+	if err := lenient.Validate(); err != nil {
+		t.Fatalf("expected lenient mode to accept fallbacks, got %v", err)
+	}
+
+	t.Setenv(envValidateStrict, "true")
+	strict := Load()
+	if err := strict.Validate(); err == nil || !strings.Contains(err.Error(), "BALLDONTLIE_MAX_PAGES") {
+		t.Fatalf("expected strict mode to reject fallbacks, got %v", err)
+	}
+
+	t.Setenv(envBdlMaxPages, "")
+	t.Setenv(envPollInterval, "")
+	if clean := Load(); len(clean.Fallbacks()) != 0 {
+		t.Fatalf("expected no fallbacks carried between loads, got %v", clean.Fallbacks())
+	}
+}
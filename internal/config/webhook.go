@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+const (
+	envWebhookURL      = "WEBHOOK_URL"
+	envWebhookSecret   = "WEBHOOK_SECRET"
+	envWebhookAttempts = "WEBHOOK_MAX_ATTEMPTS"
+	envWebhookTimeout  = "WEBHOOK_TIMEOUT"
+
+	defaultWebhookAttempts = 3
+	defaultWebhookTimeout  = 5 * time.Second
+)
+
+// WebhookConfig controls outbound notifications for game status transitions.
+type WebhookConfig struct {
+	URL         string // empty disables webhooks
+	Secret      string // HMAC key for the signature header
+	MaxAttempts int
+	Timeout     time.Duration // per-attempt request timeout
+}
+
+func loadWebhook() WebhookConfig {
+	return WebhookConfig{
+		URL:         envOrDefault(envWebhookURL, ""),
+		Secret:      envOrDefault(envWebhookSecret, ""),
+		MaxAttempts: intEnvOrDefault(envWebhookAttempts, defaultWebhookAttempts),
+		Timeout:     durationEnvOrDefault(envWebhookTimeout, defaultWebhookTimeout),
+	}
+}
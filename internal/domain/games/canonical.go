@@ -0,0 +1,56 @@
+package games
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CanonicalID identifies a game independently of the provider that reported
+// it: "{date}-{away}-{home}" with lowercased team abbreviations, for example
+// "2024-01-15-lal-bos" for the Lakers at Boston on that slate date. It is empty
+// when the date or either abbreviation is missing.
+func CanonicalID(date, homeAbbr, awayAbbr string) string {
+	home := strings.ToLower(strings.TrimSpace(homeAbbr))
+	away := strings.ToLower(strings.TrimSpace(awayAbbr))
+	if date == "" || home == "" || away == "" {
+		return ""
+	}
+	return date + "-" + away + "-" + home
+}
+
+// AssignCanonicalIDs sets CanonicalID on each game in place for the slate
+// date. When the same matchup appears more than once on a date (a
+// doubleheader), games are ordered by StartTime, then provider ID, and every
+// game after the first gets a "-2", "-3", ... suffix. Games without team
+// abbreviations are left without a canonical ID.
+func AssignCanonicalIDs(date string, games []Game) {
+	byBase := make(map[string][]int)
+	for i := range games {
+		base := CanonicalID(date, games[i].HomeTeam.Abbreviation, games[i].AwayTeam.Abbreviation)
+		games[i].CanonicalID = base
+		if base != "" {
+			byBase[base] = append(byBase[base], i)
+		}
+	}
+	for base, idx := range byBase {
+		if len(idx) < 2 {
+			continue
+		}
+		sort.Slice(idx, func(a, b int) bool {
+			ga, gb := games[idx[a]], games[idx[b]]
+			if ga.StartTime != gb.StartTime {
+				return ga.StartTime < gb.StartTime
+			}
+			return ga.ID < gb.ID
+		})
+		for n, i := range idx[1:] {
+			games[i].CanonicalID = base + "-" + strconv.Itoa(n+2)
+		}
+	}
+}
+
+// MatchesID reports whether id is the game's provider ID or its canonical ID.
+func (g Game) MatchesID(id string) bool {
+	return id != "" && (g.ID == id || g.CanonicalID == id)
+}
@@ -0,0 +1,75 @@
+package games
+
+import (
+	"testing"
+
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+)
+
+func matchup(id, provider, home, away, start string) Game {
+	return Game{
+		ID:        id,
+		Provider:  provider,
+		HomeTeam:  teams.Team{Abbreviation: home},
+		AwayTeam:  teams.Team{Abbreviation: away},
+		StartTime: start,
+	}
+}
+
+func TestCanonicalIDSameAcrossProviders(t *testing.T) {
+	games := []Game{
+		matchup("balldontlie-10", "balldontlie", "BOS", "LAL", "2024-01-15T19:00:00Z"),
+		matchup("fixture-1", "fixture", "bos", " lal", "2024-01-15T19:30:00Z"),
+	}
+	AssignCanonicalIDs("2024-01-15", games[:1])
+	AssignCanonicalIDs("2024-01-15", games[1:])
+	if games[0].CanonicalID != "2024-01-15-lal-bos" || games[1].CanonicalID != games[0].CanonicalID {
+		t.Fatalf("expected one canonical id across providers, got %q and %q", games[0].CanonicalID, games[1].CanonicalID)
+	}
+}
+
+func TestCanonicalIDRequiresDateAndTeams(t *testing.T) {
+	cases := []struct{ date, home, away string }{
+		{"", "BOS", "LAL"},
+		{"2024-01-15", "", "LAL"},
+		{"2024-01-15", "BOS", " "},
+	}
+	for _, tc := range cases {
+		if got := CanonicalID(tc.date, tc.home, tc.away); got != "" {
+			t.Fatalf("expected empty canonical id for %+v, got %q", tc, got)
+		}
+	}
+}
+
+func TestAssignCanonicalIDsSuffixesDoubleheaders(t *testing.T) {
+	// Listed out of order: the later tip-off gets the suffix regardless.
+	games := []Game{
+		matchup("b", "p", "BOS", "LAL", "2024-01-15T23:00:00Z"),
+		matchup("c", "p", "NYK", "MIA", "2024-01-15T20:00:00Z"),
+		matchup("a", "p", "BOS", "LAL", "2024-01-15T17:00:00Z"),
+		matchup("d", "p", "BOS", "LAL", "2024-01-15T23:00:00Z"), // same start as b: ordered by id
+	}
+	AssignCanonicalIDs("2024-01-15", games)
+
+	want := map[string]string{
+		"a": "2024-01-15-lal-bos",
+		"b": "2024-01-15-lal-bos-2",
+		"d": "2024-01-15-lal-bos-3",
+		"c": "2024-01-15-mia-nyk",
+	}
+	for _, g := range games {
+		if g.CanonicalID != want[g.ID] {
+			t.Fatalf("game %s: expected canonical id %q, got %q", g.ID, want[g.ID], g.CanonicalID)
+		}
+	}
+}
+
+func TestGameMatchesID(t *testing.T) {
+	g := Game{ID: "balldontlie-10", CanonicalID: "2024-01-15-lal-bos"}
+	if !g.MatchesID("balldontlie-10") || !g.MatchesID("2024-01-15-lal-bos") {
+		t.Fatalf("expected both id forms to match")
+	}
+	if g.MatchesID("") || g.MatchesID("other") || (Game{ID: "x"}).MatchesID("") {
+		t.Fatalf("expected empty and unknown ids not to match")
+	}
+}
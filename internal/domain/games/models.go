@@ -1,6 +1,10 @@
 package games
 
-import "github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+import (
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+)
 
 // GameStatusKind normalizes provider status into a small enum.
 type GameStatusKind string
@@ -13,6 +17,12 @@ const (
 	StatusCanceled   GameStatusKind = "CANCELED"
 )
 
+// Game types reported in GameMeta.GameType.
+const (
+	GameTypeRegularSeason = "regular_season"
+	GameTypePostseason    = "postseason"
+)
+
 // Score captures home and away points.
 type Score struct {
 	Home int `json:"home"`
@@ -25,26 +35,34 @@ type GameMeta struct {
 	UpstreamGameID int    `json:"upstreamGameId"`
 	Period         int    `json:"period,omitempty"`
 	Postseason     bool   `json:"postseason,omitempty"`
+	GameType       string `json:"gameType,omitempty"` // GameTypeRegularSeason or GameTypePostseason
 	Time           string `json:"time,omitempty"`
 }
 
 // Game is the canonical game shape exposed by the service.
 type Game struct {
-	ID         string         `json:"id"`
-	Provider   string         `json:"provider"`
-	HomeTeam   teams.Team     `json:"homeTeam"`
-	AwayTeam   teams.Team     `json:"awayTeam"`
-	StartTime  string         `json:"startTime"`
-	Status     string         `json:"status"`
-	StatusKind GameStatusKind `json:"statusKind"`
-	Score      Score          `json:"score"`
-	Meta       GameMeta       `json:"meta"`
+	ID          string         `json:"id"`
+	CanonicalID string         `json:"canonicalId,omitempty"` // provider-independent; see CanonicalID
+	Provider    string         `json:"provider"`
+	HomeTeam    teams.Team     `json:"homeTeam"`
+	AwayTeam    teams.Team     `json:"awayTeam"`
+	StartTime   string         `json:"startTime"`
+	Status      string         `json:"status"`
+	StatusKind  GameStatusKind `json:"statusKind"`
+	Score       Score          `json:"score"`
+	// UpdatedAt is when the poller last saw the status or score change; nil
+	// in snapshots written before it was tracked.
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	Meta      GameMeta   `json:"meta"`
 }
 
 // TodayResponse is the payload returned by /games?date=YYYY-MM-DD.
 type TodayResponse struct {
 	Date  string `json:"date"`
 	Games []Game `json:"games"`
+	// DataAsOf is the poller's last successful fetch, set on responses for
+	// the date it refreshes; never snapshotted.
+	DataAsOf *time.Time `json:"dataAsOf,omitempty"`
 }
 
 // NewTodayResponse builds a TodayResponse payload.
@@ -32,6 +32,7 @@ func TestGameJSONTags(t *testing.T) {
 	gameType := reflect.TypeOf(Game{})
 	fields := []fieldCheck{
 		{"ID", "id"},
+		{"CanonicalID", "canonicalId,omitempty"},
 		{"Provider", "provider"},
 		{"HomeTeam", "homeTeam"},
 		{"AwayTeam", "awayTeam"},
@@ -39,6 +40,7 @@ func TestGameJSONTags(t *testing.T) {
 		{"Status", "status"},
 		{"StatusKind", "statusKind"},
 		{"Score", "score"},
+		{"UpdatedAt", "updatedAt,omitempty"},
 		{"Meta", "meta"},
 	}
 
@@ -53,6 +55,22 @@ func TestGameJSONTags(t *testing.T) {
 	}
 }
 
+func TestGameMetaJSONTags(t *testing.T) {
+	metaType := reflect.TypeOf(GameMeta{})
+	for name, want := range map[string]string{
+		"Postseason": "postseason,omitempty",
+		"GameType":   "gameType,omitempty",
+	} {
+		field, ok := metaType.FieldByName(name)
+		if !ok {
+			t.Fatalf("missing field %s", name)
+		}
+		if got := field.Tag.Get("json"); got != want {
+			t.Fatalf("field %s expected json tag %s, got %s", name, want, got)
+		}
+	}
+}
+
 func TestGameUsesTeamsDomain(t *testing.T) {
 	g := Game{
 		HomeTeam: teams.Team{ID: "t1", Name: "Home"},
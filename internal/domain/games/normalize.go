@@ -0,0 +1,79 @@
+package games
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Coercion records one field Normalize rewrote.
+type Coercion struct {
+	GameID string
+	Field  string // statusKind, score.home or score.away
+	From   string
+}
+
+// NormalizeSummary reports what Normalize did to a batch of games.
+type NormalizeSummary struct {
+	Accepted int // games kept, including coerced ones
+	Dropped  int // games without an ID
+	Coerced  int // kept games with a status or score rewritten
+	// BadStartTimes counts kept games whose StartTime is not RFC 3339; they
+	// are left as-is since there is no safe value to substitute.
+	BadStartTimes int
+	Coercions     []Coercion
+}
+
+// Normalize cleans provider games before they are stored: games with an empty
+// ID are dropped, unknown status kinds become SCHEDULED, negative scores are
+// clamped to 0, and StartTime is rewritten as RFC 3339 UTC. The input slice is
+// not modified.
+func Normalize(in []Game) ([]Game, NormalizeSummary) {
+	var summary NormalizeSummary
+	out := make([]Game, 0, len(in))
+	for _, g := range in {
+		if strings.TrimSpace(g.ID) == "" {
+			summary.Dropped++
+			continue
+		}
+		before := len(summary.Coercions)
+		coerce := func(field, from string) {
+			summary.Coercions = append(summary.Coercions, Coercion{GameID: g.ID, Field: field, From: from})
+		}
+
+		if !g.StatusKind.Known() {
+			coerce("statusKind", string(g.StatusKind))
+			g.StatusKind = StatusScheduled
+		}
+		if g.Score.Home < 0 {
+			coerce("score.home", strconv.Itoa(g.Score.Home))
+			g.Score.Home = 0
+		}
+		if g.Score.Away < 0 {
+			coerce("score.away", strconv.Itoa(g.Score.Away))
+			g.Score.Away = 0
+		}
+		if start, err := time.Parse(time.RFC3339Nano, g.StartTime); err != nil {
+			summary.BadStartTimes++
+		} else {
+			// Same instant, canonical spelling; not counted as a coercion.
+			g.StartTime = start.UTC().Format(time.RFC3339)
+		}
+
+		if len(summary.Coercions) > before {
+			summary.Coerced++
+		}
+		summary.Accepted++
+		out = append(out, g)
+	}
+	return out, summary
+}
+
+// Known reports whether k is one of the defined status kinds.
+func (k GameStatusKind) Known() bool {
+	switch k {
+	case StatusScheduled, StatusInProgress, StatusFinal, StatusPostponed, StatusCanceled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package games
+
+import "testing"
+
+func TestNormalizeRules(t *testing.T) {
+	valid := Game{ID: "g1", StartTime: "2024-01-15T19:00:00Z", StatusKind: StatusFinal, Score: Score{Home: 100, Away: 98}}
+
+	cases := []struct {
+		name    string
+		in      Game
+		want    Game
+		dropped bool
+		coerced []string // fields reported as coerced
+		badTime bool
+	}{
+		{name: "valid game passes through", in: valid, want: valid},
+		{name: "empty id dropped", in: Game{ID: "", StatusKind: StatusFinal}, dropped: true},
+		{name: "blank id dropped", in: Game{ID: "  ", StatusKind: StatusFinal}, dropped: true},
+		{
+			name:    "unknown status becomes scheduled",
+			in:      Game{ID: "g1", StartTime: valid.StartTime, StatusKind: "HALFTIME"},
+			want:    Game{ID: "g1", StartTime: valid.StartTime, StatusKind: StatusScheduled},
+			coerced: []string{"statusKind"},
+		},
+		{
+			name:    "empty status becomes scheduled",
+			in:      Game{ID: "g1", StartTime: valid.StartTime},
+			want:    Game{ID: "g1", StartTime: valid.StartTime, StatusKind: StatusScheduled},
+			coerced: []string{"statusKind"},
+		},
+		{
+			name:    "negative scores clamped",
+			in:      Game{ID: "g1", StartTime: valid.StartTime, StatusKind: StatusInProgress, Score: Score{Home: -3, Away: -1}},
+			want:    Game{ID: "g1", StartTime: valid.StartTime, StatusKind: StatusInProgress},
+			coerced: []string{"score.home", "score.away"},
+		},
+		{
+			name: "offset start time canonicalized to utc",
+			in:   Game{ID: "g1", StartTime: "2024-01-15T14:00:00-05:00", StatusKind: StatusScheduled},
+			want: Game{ID: "g1", StartTime: "2024-01-15T19:00:00Z", StatusKind: StatusScheduled},
+		},
+		{
+			name: "fractional start time canonicalized",
+			in:   Game{ID: "g1", StartTime: "2024-01-15T19:00:00.000Z", StatusKind: StatusScheduled},
+			want: Game{ID: "g1", StartTime: "2024-01-15T19:00:00Z", StatusKind: StatusScheduled},
+		},
+		{
+			name:    "unparseable start time kept and reported",
+			in:      Game{ID: "g1", StartTime: "tonight", StatusKind: StatusScheduled},
+			want:    Game{ID: "g1", StartTime: "tonight", StatusKind: StatusScheduled},
+			badTime: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, summary := Normalize([]Game{tc.in})
+			if tc.dropped {
+				if len(out) != 0 || summary.Dropped != 1 || summary.Accepted != 0 {
+					t.Fatalf("expected game dropped, got %v %+v", out, summary)
+				}
+				return
+			}
+			if len(out) != 1 || summary.Accepted != 1 {
+				t.Fatalf("expected game accepted, got %v %+v", out, summary)
+			}
+			if out[0] != tc.want {
+				t.Fatalf("got %+v, want %+v", out[0], tc.want)
+			}
+			if len(summary.Coercions) != len(tc.coerced) {
+				t.Fatalf("expected coercions %v, got %+v", tc.coerced, summary.Coercions)
+			}
+			for i, field := range tc.coerced {
+				if summary.Coercions[i].Field != field || summary.Coercions[i].GameID != "g1" {
+					t.Fatalf("expected coercion %s, got %+v", field, summary.Coercions[i])
+				}
+			}
+			if wantCoerced := len(tc.coerced) > 0; (summary.Coerced == 1) != wantCoerced {
+				t.Fatalf("expected coerced=%v, got %+v", wantCoerced, summary)
+			}
+			if (summary.BadStartTimes == 1) != tc.badTime {
+				t.Fatalf("expected bad start time=%v, got %+v", tc.badTime, summary)
+			}
+		})
+	}
+}
+
+func TestNormalizeSummaryCountsBatchAndLeavesInput(t *testing.T) {
+	in := []Game{
+		{ID: "a", StartTime: "2024-01-15T19:00:00Z", StatusKind: "weird", Score: Score{Home: -1}},
+		{ID: ""},
+		{ID: "b", StartTime: "2024-01-15T19:00:00Z", StatusKind: StatusFinal},
+	}
+	out, summary := Normalize(in)
+	if summary.Accepted != 2 || summary.Dropped != 1 || summary.Coerced != 1 || len(summary.Coercions) != 2 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if len(out) != 2 || out[0].ID != "a" || out[1].ID != "b" {
+		t.Fatalf("expected order preserved, got %v", out)
+	}
+	if in[0].StatusKind != "weird" || in[0].Score.Home != -1 {
+		t.Fatalf("expected input untouched, got %+v", in[0])
+	}
+}
@@ -0,0 +1,95 @@
+package games
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey names an ordering of a slate's games.
+type SortKey string
+
+const (
+	// SortStartTime orders games by start time, earliest first. It is the
+	// default for responses and snapshots.
+	SortStartTime SortKey = "startTime"
+	// SortStartTimeDesc orders games by start time, latest first.
+	SortStartTimeDesc SortKey = "-startTime"
+	// SortStatus puts live games first, then scheduled, postponed, final and
+	// canceled ones, each group by start time.
+	SortStatus SortKey = "status"
+)
+
+// SortKeys lists the accepted sort keys in documentation order.
+var SortKeys = []SortKey{SortStartTime, SortStartTimeDesc, SortStatus}
+
+// ParseSortKey resolves a sort parameter; an empty one is SortStartTime.
+func ParseSortKey(raw string) (SortKey, error) {
+	if raw == "" {
+		return SortStartTime, nil
+	}
+	for _, key := range SortKeys {
+		if string(key) == raw {
+			return key, nil
+		}
+	}
+	names := make([]string, len(SortKeys))
+	for i, key := range SortKeys {
+		names[i] = string(key)
+	}
+	return "", fmt.Errorf("unknown sort %q; allowed: %s", raw, strings.Join(names, ", "))
+}
+
+// statusRank orders status kinds for SortStatus; unknown kinds sort last.
+var statusRank = map[GameStatusKind]int{
+	StatusInProgress: 0,
+	StatusScheduled:  1,
+	StatusPostponed:  2,
+	StatusFinal:      3,
+	StatusCanceled:   4,
+}
+
+// SortGames orders games in place by key, breaking ties by canonical ID and
+// then provider ID so equal slates always come out in the same order. Games
+// whose StartTime is not RFC 3339 sort after those with one, in either
+// direction. An unknown key sorts as SortStartTime.
+func SortGames(games []Game, key SortKey) {
+	starts := make(map[string]time.Time, len(games))
+	for _, g := range games {
+		if t, err := time.Parse(time.RFC3339, g.StartTime); err == nil {
+			starts[g.StartTime] = t
+		}
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		a, b := games[i], games[j]
+		if key == SortStatus {
+			ra, oka := statusRank[a.StatusKind]
+			rb, okb := statusRank[b.StatusKind]
+			if !oka {
+				ra = len(statusRank)
+			}
+			if !okb {
+				rb = len(statusRank)
+			}
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		ta, oka := starts[a.StartTime]
+		tb, okb := starts[b.StartTime]
+		switch {
+		case oka != okb:
+			return oka
+		case oka && !ta.Equal(tb):
+			if key == SortStartTimeDesc {
+				return ta.After(tb)
+			}
+			return ta.Before(tb)
+		}
+		if a.CanonicalID != b.CanonicalID {
+			return a.CanonicalID < b.CanonicalID
+		}
+		return a.ID < b.ID
+	})
+}
@@ -0,0 +1,71 @@
+package games
+
+import (
+	"testing"
+)
+
+func sortFixture() []Game {
+	return []Game{
+		{ID: "late", StartTime: "2024-01-15T03:00:00Z", StatusKind: StatusScheduled},
+		{ID: "b-early", StartTime: "2024-01-15T00:00:00Z", StatusKind: StatusFinal},
+		{ID: "live", StartTime: "2024-01-15T01:00:00Z", StatusKind: StatusInProgress},
+		{ID: "no-time", StartTime: "TBD", StatusKind: StatusScheduled},
+		{ID: "a-early", StartTime: "2024-01-14T19:00:00-05:00", StatusKind: StatusCanceled},
+	}
+}
+
+func sortedIDs(games []Game, key SortKey) []string {
+	SortGames(games, key)
+	ids := make([]string, len(games))
+	for i, g := range games {
+		ids[i] = g.ID
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSortGamesByStartTime(t *testing.T) {
+	// a-early and b-early start at the same instant in different offsets.
+	assertIDs(t, sortedIDs(sortFixture(), SortStartTime), []string{"a-early", "b-early", "live", "late", "no-time"})
+	assertIDs(t, sortedIDs(sortFixture(), SortStartTimeDesc), []string{"late", "live", "a-early", "b-early", "no-time"})
+	assertIDs(t, sortedIDs(sortFixture(), SortStatus), []string{"live", "late", "no-time", "b-early", "a-early"})
+}
+
+func TestSortGamesIsStableAcrossInputOrder(t *testing.T) {
+	want := sortedIDs(sortFixture(), SortStartTime)
+	reversed := sortFixture()
+	for i, j := 0, len(reversed)-1; i < j; i, j = i+1, j-1 {
+		reversed[i], reversed[j] = reversed[j], reversed[i]
+	}
+	assertIDs(t, sortedIDs(reversed, SortStartTime), want)
+
+	tied := []Game{
+		{ID: "x", CanonicalID: "2024-01-15-lal-bos", StartTime: "2024-01-15T00:00:00Z"},
+		{ID: "a", CanonicalID: "2024-01-15-nyk-mia", StartTime: "2024-01-15T00:00:00Z"},
+	}
+	assertIDs(t, sortedIDs(tied, SortStartTime), []string{"x", "a"})
+}
+
+func TestParseSortKey(t *testing.T) {
+	for raw, want := range map[string]SortKey{"": SortStartTime, "startTime": SortStartTime, "-startTime": SortStartTimeDesc, "status": SortStatus} {
+		if got, err := ParseSortKey(raw); err != nil || got != want {
+			t.Fatalf("ParseSortKey(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"starttime", "-status", "id"} {
+		if _, err := ParseSortKey(raw); err == nil {
+			t.Fatalf("expected %q rejected", raw)
+		}
+	}
+}
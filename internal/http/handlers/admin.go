@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/http/middleware"
 	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
@@ -17,99 +21,306 @@ import (
 
 // AdminHandler exposes admin-only endpoints (e.g., snapshot refresh).
 type AdminHandler struct {
-	writer   *snapshots.Writer
-	provider providers.GameProvider
-	token    string
-	logger   *slog.Logger
+	writer       *snapshots.Writer
+	provider     providers.GameProvider
+	token        string
+	logger       *slog.Logger
+	fetchTimeout time.Duration
+	jobs         *refreshJobs
+	reload       func() (ConfigReload, error) // nil until SetConfigReload
+	clock        clock.Clock
 }
 
+// defaultAdminFetchTimeout bounds admin-triggered fetches when no timeout is configured.
+const defaultAdminFetchTimeout = 2 * time.Minute
+
 // NewAdminHandler constructs an AdminHandler.
 func NewAdminHandler(writer *snapshots.Writer, provider providers.GameProvider, token string, logger *slog.Logger) *AdminHandler {
+	return NewAdminHandlerWithTimeout(writer, provider, token, logger, 0)
+}
+
+// NewAdminHandlerWithTimeout is identical to NewAdminHandler but bounds refresh fetches with fetchTimeout.
+func NewAdminHandlerWithTimeout(writer *snapshots.Writer, provider providers.GameProvider, token string, logger *slog.Logger, fetchTimeout time.Duration) *AdminHandler {
+	return NewAdminHandlerWithClock(writer, provider, token, logger, fetchTimeout, nil)
+}
+
+// NewAdminHandlerWithClock is NewAdminHandlerWithTimeout with an injectable
+// clock for default dates, job timestamps and fetch latency.
+func NewAdminHandlerWithClock(writer *snapshots.Writer, provider providers.GameProvider, token string, logger *slog.Logger, fetchTimeout time.Duration, clk clock.Clock) *AdminHandler {
+	if fetchTimeout <= 0 {
+		fetchTimeout = defaultAdminFetchTimeout
+	}
 	return &AdminHandler{
-		writer:   writer,
-		provider: provider,
-		token:    token,
-		logger:   logger,
+		writer:       writer,
+		provider:     provider,
+		token:        token,
+		logger:       logger,
+		fetchTimeout: fetchTimeout,
+		jobs:         newRefreshJobs(),
+		clock:        clock.OrReal(clk),
 	}
 }
 
+// Register mounts the admin routes on mux.
+func (h *AdminHandler) Register(mux *Mux) {
+	mux.HandleFunc("GET /admin/snapshots", h.ListSnapshots)
+	mux.HandleFunc("POST /admin/snapshots/refresh", h.RefreshSnapshots)
+	mux.HandleFunc("POST /admin/snapshots/pin/{date}", h.PinSnapshot)
+	mux.HandleFunc("DELETE /admin/snapshots/pin/{date}", h.PinSnapshot)
+	mux.HandleFunc("POST /admin/snapshots/manifest/rebuild", h.RebuildManifest)
+	mux.HandleFunc("GET /admin/snapshots/jobs/{id}", h.RefreshJobStatus)
+	mux.HandleFunc("GET /admin/snapshots/games/{date}/history", h.SnapshotHistory)
+	mux.HandleFunc("GET /admin/snapshots/games/{date}/diff", h.SnapshotDiff)
+	mux.HandleFunc("POST /admin/config/reload", h.ReloadConfig)
+}
+
 // RefreshSnapshots writes a games snapshot for the requested date (defaults to today).
-// Guarded by ADMIN_TOKEN env; returns 401 if missing/invalid.
+// Guarded by ADMIN_TOKEN env; returns 401 if missing/invalid. If the client disconnects
+// the refresh keeps running; its result is available from RefreshJobStatus.
 func (h *AdminHandler) RefreshSnapshots(w http.ResponseWriter, r *http.Request) {
-	if !requireMethod(w, r, http.MethodPost, h.logger) {
-		return
-	}
-	if !h.authorize(r) {
-		logging.Warn(h.logger, "admin unauthorized",
-			slog.String("path", r.URL.Path),
-			slog.String("client_ip", clientIP(r)),
-		)
-		writeError(w, r, http.StatusUnauthorized, "unauthorized", h.logger)
+	if !h.requireAuth(w, r) {
 		return
 	}
 	if h.provider == nil || h.writer == nil {
-		writeError(w, r, http.StatusServiceUnavailable, "snapshot writer not configured", h.logger)
+		writeError(w, r, http.StatusServiceUnavailable, CodeStorageUnavailable, "snapshot writer not configured", h.logger)
 		return
 	}
 
 	logger := loggerFromContext(r, h.logger)
 	date := strings.TrimSpace(r.URL.Query().Get("date"))
 	if date == "" {
-		date = timeutil.FormatDate(time.Now())
+		date = timeutil.DateIn(h.clock.Now(), h.writer.Location())
 	}
 	// Validate date format.
 	if _, err := timeutil.ParseDate(date); err != nil {
 		logging.Warn(logger, "admin snapshot invalid date", slog.String("date", date))
-		writeError(w, r, http.StatusBadRequest, "invalid date format", logger)
+		writeError(w, r, http.StatusBadRequest, CodeInvalidDate, "invalid date format", logger)
 		return
 	}
 	// Fetch games from provider for the date; no tz support here (keep simple).
 	tz := strings.TrimSpace(r.URL.Query().Get("tz"))
 	if tz != "" {
+		if err := timeutil.ValidateTimezoneName(tz); err != nil {
+			logging.Warn(logger, "admin snapshot malformed tz", slog.Int("length", len(tz)), slog.String("reason", err.Error()))
+			writeError(w, r, http.StatusBadRequest, CodeInvalidTimezone, "invalid timezone", logger)
+			return
+		}
 		if _, err := time.LoadLocation(tz); err != nil {
 			logging.Warn(logger, "admin snapshot invalid tz", slog.String("tz", tz))
-			writeError(w, r, http.StatusBadRequest, "invalid timezone", logger)
+			writeError(w, r, http.StatusBadRequest, CodeInvalidTimezone, "invalid timezone", logger)
 			return
 		}
 	}
-	games, err := h.provider.FetchGames(r.Context(), date, tz)
-	if err != nil {
-		logging.Warn(logger, "admin snapshot fetch failed",
+	// Frozen dates are settled; only an explicit force=true re-fetches them.
+	force := r.URL.Query().Get("force") == "true"
+	if !force && h.writer.IsFrozen(date) {
+		writeError(w, r, http.StatusConflict, CodeSnapshotFrozen, "snapshot frozen (pass force=true to overwrite)", logger)
+		return
+	}
+	jobID := h.jobs.start(date, tz, h.clock.Now())
+	done := make(chan refreshOutcome, 1)
+	go func() {
+		done <- h.runRefresh(r, jobID, date, tz, force, logger)
+	}()
+
+	var out refreshOutcome
+	select {
+	case out = <-done:
+	case <-r.Context().Done():
+		logging.Warn(logger, "admin snapshot client disconnected; refresh continues in background",
 			slog.String("date", date),
-			slog.String("tz", tz),
-			slog.Any("err", err),
+			slog.String("job_id", jobID),
 		)
-		writeError(w, r, http.StatusBadGateway, "failed to fetch games", logger)
 		return
 	}
-	if len(games) == 0 {
-		logging.Warn(logger, "admin snapshot no games", slog.String("date", date))
-		writeError(w, r, http.StatusBadRequest, "no games to snapshot", logger)
+	if out.status != http.StatusOK {
+		if out.failure != nil {
+			writeFailure(w, r, *out.failure, out.message, logger)
+			return
+		}
+		writeError(w, r, out.status, out.code, out.message, logger)
 		return
 	}
 
-	snap := domaingames.NewTodayResponse(date, games)
-	if err := h.writer.WriteGamesSnapshot(date, snap); err != nil {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"date":      date,
+		"jobId":     jobID,
+		"snapshots": out.count,
+		"status":    "ok",
+	}, logger)
+}
+
+type refreshOutcome struct {
+	status  int
+	code    ErrorCode
+	message string
+	count   int
+	failure *upstreamFailure // set when the provider fetch failed
+}
+
+// runRefresh fetches and writes a snapshot on a context detached from the client
+// connection, so a disconnect never abandons a half-complete multi-page fetch.
+// Concurrent refreshes of the same date (and tz and force), including the
+// syncer's, share one upstream fetch through the writer. The outcome is
+// logged and recorded on the job either way.
+func (h *AdminHandler) runRefresh(r *http.Request, jobID, date, tz string, force bool, logger *slog.Logger) refreshOutcome {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), h.fetchTimeout)
+	defer cancel()
+
+	fetchStart := h.clock.Now()
+	games, shared, err := h.writer.RefreshGames(date, snapshots.RefreshOptions{Force: force, Variant: tz}, func() ([]domaingames.Game, error) {
+		return h.provider.FetchGames(ctx, date, tz)
+	})
+	middleware.RecordUpstreamLatency(r.Context(), h.clock.Now().Sub(fetchStart))
+	switch {
+	case errors.Is(err, snapshots.ErrSnapshotFrozen):
+		h.jobs.finish(jobID, len(games), err, h.clock.Now())
+		return refreshOutcome{status: http.StatusConflict, code: CodeSnapshotFrozen, message: "snapshot frozen (pass force=true to overwrite)"}
+	case errors.Is(err, snapshots.ErrSnapshotWrite):
 		logging.Warn(logger, "admin snapshot write failed",
 			slog.String("date", date),
 			slog.String("tz", tz),
 			slog.Int("count", len(games)),
+			slog.String("job_id", jobID),
 			slog.Any("err", err),
 		)
-		writeError(w, r, http.StatusInternalServerError, "failed to write snapshot", logger)
-		return
+		h.jobs.finish(jobID, len(games), err, h.clock.Now())
+		return refreshOutcome{status: http.StatusInternalServerError, code: CodeInternal, message: "failed to write snapshot"}
+	case err != nil:
+		logging.Warn(logger, "admin snapshot fetch failed",
+			slog.String("date", date),
+			slog.String("tz", tz),
+			slog.String("job_id", jobID),
+			slog.Any("err", err),
+		)
+		h.jobs.finish(jobID, 0, err, h.clock.Now())
+		failure := classifyUpstream(err)
+		return refreshOutcome{status: failure.status, code: failure.code, message: "failed to fetch games", failure: &failure}
+	}
+	if len(games) == 0 {
+		logging.Warn(logger, "admin snapshot no games", slog.String("date", date), slog.String("job_id", jobID))
+		h.jobs.finish(jobID, 0, errors.New("no games to snapshot"), h.clock.Now())
+		return refreshOutcome{status: http.StatusBadRequest, code: CodeNoGames, message: "no games to snapshot"}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]any{
-		"date":      date,
-		"snapshots": len(games),
-		"status":    "ok",
-	}, logger)
+	h.jobs.finish(jobID, len(games), nil, h.clock.Now())
 	logging.Info(logger, "admin snapshot written",
 		slog.String("date", date),
 		slog.String("tz", tz),
 		slog.Int("count", len(games)),
+		slog.String("job_id", jobID),
+		slog.Bool("shared", shared),
 	)
+	return refreshOutcome{status: http.StatusOK, count: len(games)}
+}
+
+// ListSnapshots reports snapshot dates, pinned dates, and disk usage from the manifest.
+func (h *AdminHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAuth(w, r) {
+		return
+	}
+	logger := loggerFromContext(r, h.logger)
+	if h.writer == nil {
+		writeError(w, r, http.StatusServiceUnavailable, CodeStorageUnavailable, "snapshot writer not configured", logger)
+		return
+	}
+	m, err := h.writer.Manifest()
+	if err != nil {
+		logging.Warn(logger, "admin snapshot manifest unreadable", slog.Any("err", err))
+	}
+	usage, err := h.writer.Usage()
+	if err != nil {
+		logging.Warn(logger, "admin snapshot usage failed", slog.Any("err", err))
+	}
+	pinned := m.Games.Pinned
+	if pinned == nil {
+		pinned = []string{}
+	}
+	frozen := m.Games.Frozen
+	if frozen == nil {
+		frozen = []string{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"games": map[string]any{
+			"dates":         m.Games.Dates,
+			"pinned":        pinned,
+			"frozen":        frozen,
+			"lastRefreshed": m.Games.LastRefreshed,
+		},
+		"retention": m.Retention,
+		"usage":     usage,
+	}, logger)
+}
+
+// PinSnapshot pins (POST) or unpins (DELETE) a snapshot date at /admin/snapshots/pin/{date}.
+// Pinned dates survive retention pruning; pinning a date without a snapshot returns 404.
+func (h *AdminHandler) PinSnapshot(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAuth(w, r) {
+		return
+	}
+	logger := loggerFromContext(r, h.logger)
+	if h.writer == nil {
+		writeError(w, r, http.StatusServiceUnavailable, CodeStorageUnavailable, "snapshot writer not configured", logger)
+		return
+	}
+	date := r.PathValue("date")
+	if _, err := timeutil.ParseDate(date); err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidDate, "invalid date format", logger)
+		return
+	}
+
+	var err error
+	pinned := r.Method == http.MethodPost
+	if pinned {
+		err = h.writer.PinDate(date)
+	} else {
+		err = h.writer.UnpinDate(date)
+	}
+	switch {
+	case errors.Is(err, snapshots.ErrSnapshotNotFound):
+		writeError(w, r, http.StatusNotFound, CodeSnapshotNotFound, "snapshot not found", logger)
+		return
+	case err != nil:
+		logging.Warn(logger, "admin snapshot pin failed", slog.String("date", date), slog.Any("err", err))
+		writeError(w, r, http.StatusInternalServerError, CodeInternal, "failed to update pinned dates", logger)
+		return
+	}
+
+	logging.Info(logger, "admin snapshot pin updated", slog.String("date", date), slog.Bool("pinned", pinned))
+	writeJSON(w, http.StatusOK, map[string]any{
+		"date":   date,
+		"pinned": pinned,
+		"status": "ok",
+	}, logger)
+}
+
+// ManifestRebuildResponse is the payload of POST /admin/snapshots/manifest/rebuild.
+type ManifestRebuildResponse struct {
+	Status  string `json:"status"`
+	Changed bool   `json:"changed"`
+	snapshots.ManifestRebuild
+}
+
+// RebuildManifest reconstructs the snapshot manifest from the files on disk,
+// for after snapshots were added or removed by hand, and reports the dates
+// added and removed per kind.
+func (h *AdminHandler) RebuildManifest(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAuth(w, r) {
+		return
+	}
+	logger := loggerFromContext(r, h.logger)
+	if h.writer == nil {
+		writeError(w, r, http.StatusServiceUnavailable, CodeStorageUnavailable, "snapshot writer not configured", logger)
+		return
+	}
+	report, err := h.writer.RebuildManifest()
+	if err != nil {
+		logging.Warn(logger, "admin manifest rebuild failed", slog.Any("err", err))
+		writeError(w, r, http.StatusInternalServerError, CodeInternal, "failed to rebuild manifest", logger)
+		return
+	}
+	logging.Info(logger, "admin manifest rebuilt", slog.Bool("changed", report.Changed()))
+	writeJSON(w, http.StatusOK, ManifestRebuildResponse{Status: "ok", Changed: report.Changed(), ManifestRebuild: report}, logger)
 }
 
 // AdminTokenFromEnv reads ADMIN_TOKEN (optional).
@@ -117,6 +328,19 @@ func AdminTokenFromEnv() string {
 	return os.Getenv("ADMIN_TOKEN")
 }
 
+func (h *AdminHandler) requireAuth(w http.ResponseWriter, r *http.Request) bool {
+	if h.authorize(r) {
+		return true
+	}
+	logging.Warn(h.logger, "admin unauthorized",
+		slog.String("path", r.URL.Path),
+		slog.String("client_ip", clientIP(r)),
+		slog.String("auth_scheme", authScheme(r)),
+	)
+	writeError(w, r, http.StatusUnauthorized, CodeUnauthorized, "unauthorized", h.logger)
+	return false
+}
+
 func (h *AdminHandler) authorize(r *http.Request) bool {
 	if h.token == "" {
 		return false
@@ -124,6 +348,20 @@ func (h *AdminHandler) authorize(r *http.Request) bool {
 	return r.Header.Get("Authorization") == "Bearer "+h.token
 }
 
+// authScheme names the Authorization scheme r presented, or "none", so a
+// rejected request can be diagnosed without logging the credential itself.
+func authScheme(r *http.Request) string {
+	header := strings.TrimSpace(r.Header.Get("Authorization"))
+	if header == "" {
+		return "none"
+	}
+	scheme, _, _ := strings.Cut(header, " ")
+	if len(scheme) > 16 {
+		return "unknown"
+	}
+	return scheme
+}
+
 func clientIP(r *http.Request) string {
 	return requestutil.ClientIP(r)
 }
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+)
+
+// ConfigReload reports what a config reload did. Applied settings took
+// effect live; Skipped ones changed but need a restart. Both hold Config
+// field paths such as "PollInterval" or "Snapshots.RetentionDays".
+type ConfigReload struct {
+	Applied  []string `json:"applied"`
+	Skipped  []string `json:"skipped"`
+	Warnings []string `json:"warnings,omitempty"` // values the reload fell back to defaults for
+}
+
+// SetConfigReload installs fn behind POST /admin/config/reload. fn returns
+// an error, and applies nothing, when the re-read config fails validation.
+func (h *AdminHandler) SetConfigReload(fn func() (ConfigReload, error)) {
+	if h == nil {
+		return
+	}
+	h.reload = fn
+}
+
+// ReloadConfig re-reads env and CONFIG_FILE and applies the reloadable subset.
+func (h *AdminHandler) ReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAuth(w, r) {
+		return
+	}
+	logger := loggerFromContext(r, h.logger)
+	if h.reload == nil {
+		writeError(w, r, http.StatusNotFound, CodeNotFound, "config reload not available", logger)
+		return
+	}
+	result, err := h.reload()
+	if err != nil {
+		logging.Warn(logger, "admin config reload rejected", slog.Any("err", err))
+		writeErrorDetails(w, r, http.StatusUnprocessableEntity, CodeInvalidConfig, "config invalid; nothing applied", strings.Split(err.Error(), "\n"), logger)
+		return
+	}
+	logging.Info(logger, "admin config reloaded",
+		slog.Any("applied", result.Applied),
+		slog.Any("skipped", result.Skipped),
+	)
+	writeJSON(w, http.StatusOK, result, logger)
+}
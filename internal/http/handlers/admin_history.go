@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// SnapshotHistoryResponse is the payload of GET /admin/snapshots/games/{date}/history.
+type SnapshotHistoryResponse struct {
+	Date     string                     `json:"date"`
+	Versions []snapshots.HistoryVersion `json:"versions"`
+}
+
+// SnapshotDiffResponse is the payload of GET /admin/snapshots/games/{date}/diff.
+type SnapshotDiffResponse struct {
+	Date string `json:"date"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+	snapshots.GamesDiff
+}
+
+// SnapshotHistory lists the archived versions of a date's games snapshot,
+// oldest first. Versions exist only while SNAPSHOT_HISTORY_ENABLED is on.
+func (h *AdminHandler) SnapshotHistory(w http.ResponseWriter, r *http.Request) {
+	date, logger, ok := h.historyRequest(w, r)
+	if !ok {
+		return
+	}
+	versions, err := h.writer.GamesHistory(date)
+	if err != nil {
+		logging.Warn(logger, "admin snapshot history failed", slog.String("date", date), slog.Any("err", err))
+		writeError(w, r, http.StatusInternalServerError, CodeInternal, "failed to list snapshot history", logger)
+		return
+	}
+	writeJSON(w, http.StatusOK, SnapshotHistoryResponse{Date: date, Versions: versions}, logger)
+}
+
+// SnapshotDiff compares archived versions a and b (IDs from SnapshotHistory)
+// of a date's games snapshot: games added and removed, and status and score
+// changes of the games in both.
+func (h *AdminHandler) SnapshotDiff(w http.ResponseWriter, r *http.Request) {
+	date, logger, ok := h.historyRequest(w, r)
+	if !ok {
+		return
+	}
+	a, b := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if a == "" || b == "" {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, "a and b versions are required", logger)
+		return
+	}
+	var snaps [2]domaingames.TodayResponse
+	for i, id := range []string{a, b} {
+		snap, err := h.writer.LoadGamesVersion(date, id)
+		switch {
+		case errors.Is(err, snapshots.ErrInvalidHistoryVersion):
+			writeError(w, r, http.StatusBadRequest, CodeInvalidQuery, "invalid version "+id, logger)
+			return
+		case errors.Is(err, snapshots.ErrSnapshotNotFound):
+			writeError(w, r, http.StatusNotFound, CodeSnapshotNotFound, "snapshot version not found", logger)
+			return
+		case err != nil:
+			logging.Warn(logger, "admin snapshot diff failed", slog.String("date", date), slog.String("version", id), slog.Any("err", err))
+			writeError(w, r, http.StatusInternalServerError, CodeInternal, "failed to read snapshot version", logger)
+			return
+		}
+		snaps[i] = snap
+	}
+	writeJSON(w, http.StatusOK, SnapshotDiffResponse{
+		Date:      date,
+		A:         a,
+		B:         b,
+		GamesDiff: snapshots.DiffGames(snaps[0], snaps[1]),
+	}, logger)
+}
+
+// historyRequest authorizes r and validates its {date}, writing the error
+// response when it returns false.
+func (h *AdminHandler) historyRequest(w http.ResponseWriter, r *http.Request) (string, *slog.Logger, bool) {
+	if !h.requireAuth(w, r) {
+		return "", nil, false
+	}
+	logger := loggerFromContext(r, h.logger)
+	if h.writer == nil {
+		writeError(w, r, http.StatusServiceUnavailable, CodeStorageUnavailable, "snapshot writer not configured", logger)
+		return "", nil, false
+	}
+	date := r.PathValue("date")
+	if _, err := timeutil.ParseDate(date); err != nil {
+		writeError(w, r, http.StatusBadRequest, CodeInvalidDate, "invalid date format", logger)
+		return "", nil, false
+	}
+	return date, logger, true
+}
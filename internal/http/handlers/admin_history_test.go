@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+// historyAdmin returns an admin handler whose writer has archived two
+// versions of date's snapshot before the current one.
+func historyAdmin(t *testing.T, date string) (*AdminHandler, *snapshots.Writer) {
+	t.Helper()
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	writer.SetClock(clk)
+	writer.SetHistory(snapshots.HistoryConfig{Enabled: true})
+	for _, score := range []int{0, 2, 5} {
+		game := domaingames.Game{ID: "g1", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: score}}
+		if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{game})); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		clk.Advance(time.Minute)
+	}
+	return NewAdminHandler(writer, nil, "secret", nil), writer
+}
+
+func TestAdminSnapshotHistoryAndDiff(t *testing.T) {
+	date := "2024-01-15"
+	h, _ := historyAdmin(t, date)
+
+	rr := callAdmin(t, h, http.MethodGet, "/admin/snapshots/games/"+date+"/history", "secret")
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var history SnapshotHistoryResponse
+	testutil.DecodeJSON(t, rr, &history)
+	if history.Date != date || len(history.Versions) != 2 {
+		t.Fatalf("expected two archived versions, got %+v", history)
+	}
+
+	a, b := history.Versions[0].ID, history.Versions[1].ID
+	rr = callAdmin(t, h, http.MethodGet, "/admin/snapshots/games/"+date+"/diff?a="+a+"&b="+b, "secret")
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var diff SnapshotDiffResponse
+	testutil.DecodeJSON(t, rr, &diff)
+	if diff.A != a || diff.B != b || len(diff.Added) != 0 || len(diff.Removed) != 0 || len(diff.Changed) != 1 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+	change := diff.Changed[0].Changes
+	if len(change) != 1 || change[0].Field != snapshots.DiffFieldHomeScore || change[0].Old != float64(0) || change[0].New != float64(2) {
+		t.Fatalf("expected the home score change 0 -> 2, got %+v", change)
+	}
+}
+
+func TestAdminSnapshotDiffErrors(t *testing.T) {
+	date := "2024-01-15"
+	h, writer := historyAdmin(t, date)
+	versions, _ := writer.GamesHistory(date)
+	known := versions[0].ID
+
+	cases := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+		code  ErrorCode
+	}{
+		{name: "unauthorized", path: "/admin/snapshots/games/" + date + "/diff?a=" + known + "&b=" + known, want: http.StatusUnauthorized, code: CodeUnauthorized},
+		{name: "invalid date", path: "/admin/snapshots/games/bad/diff?a=" + known + "&b=" + known, token: "secret", want: http.StatusBadRequest, code: CodeInvalidDate},
+		{name: "missing version", path: "/admin/snapshots/games/" + date + "/diff?a=" + known, token: "secret", want: http.StatusBadRequest, code: CodeInvalidQuery},
+		{name: "malformed version", path: "/admin/snapshots/games/" + date + "/diff?a=" + known + "&b=latest", token: "secret", want: http.StatusBadRequest, code: CodeInvalidQuery},
+		{name: "unknown version", path: "/admin/snapshots/games/" + date + "/diff?a=" + known + "&b=20240101T000000.000000000Z", token: "secret", want: http.StatusNotFound, code: CodeSnapshotNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := callAdmin(t, h, http.MethodGet, tc.path, tc.token)
+			testutil.AssertStatus(t, rr, tc.want)
+			if resp := decodeError(t, rr); resp.Error.Code != tc.code {
+				t.Fatalf("expected code %s, got %+v", tc.code, resp.Error)
+			}
+		})
+	}
+
+	noWriter := NewAdminHandler(nil, nil, "secret", nil)
+	if rr := callAdmin(t, noWriter, http.MethodGet, "/admin/snapshots/games/"+date+"/history", "secret"); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without writer, got %d", rr.Code)
+	}
+}
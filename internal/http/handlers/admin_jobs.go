@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
+)
+
+const (
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+
+	// maxRefreshJobs bounds how many finished refresh jobs are kept for lookup.
+	maxRefreshJobs = 100
+)
+
+// RefreshJob records an admin-triggered snapshot refresh so callers that
+// disconnected mid-fetch can still see how it finished.
+type RefreshJob struct {
+	ID         string    `json:"id"`
+	Date       string    `json:"date"`
+	TZ         string    `json:"tz,omitempty"`
+	State      string    `json:"state"`
+	Count      int       `json:"count"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
+
+type refreshJobs struct {
+	mu    sync.RWMutex
+	jobs  map[string]*RefreshJob
+	order []string
+}
+
+func newRefreshJobs() *refreshJobs {
+	return &refreshJobs{jobs: make(map[string]*RefreshJob)}
+}
+
+func (j *refreshJobs) start(date, tz string, now time.Time) string {
+	id := requestutil.NewRequestID()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jobs[id] = &RefreshJob{ID: id, Date: date, TZ: tz, State: jobRunning, StartedAt: now}
+	j.order = append(j.order, id)
+	for len(j.order) > maxRefreshJobs {
+		delete(j.jobs, j.order[0])
+		j.order = j.order[1:]
+	}
+	return id
+}
+
+func (j *refreshJobs) finish(id string, count int, err error, now time.Time) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return
+	}
+	job.Count = count
+	job.FinishedAt = now
+	job.State = jobSucceeded
+	if err != nil {
+		job.State = jobFailed
+		job.Error = err.Error()
+	}
+}
+
+func (j *refreshJobs) get(id string) (RefreshJob, bool) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return RefreshJob{}, false
+	}
+	return *job, true
+}
+
+// RefreshJobStatus reports an admin refresh job at /admin/snapshots/jobs/{id}.
+func (h *AdminHandler) RefreshJobStatus(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAuth(w, r) {
+		return
+	}
+	logger := loggerFromContext(r, h.logger)
+	id := r.PathValue("id")
+	job, ok := h.jobs.get(id)
+	if !ok {
+		writeError(w, r, http.StatusNotFound, CodeJobNotFound, "job not found", logger)
+		return
+	}
+	writeJSON(w, http.StatusOK, job, logger)
+}
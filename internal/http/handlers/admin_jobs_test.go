@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// gatedProvider blocks FetchGames until release is closed or ctx ends;
+// started closes on the first call.
+type gatedProvider struct {
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (g *gatedProvider) FetchGames(ctx context.Context, date, _ string) ([]domaingames.Game, error) {
+	if g.calls.Add(1) == 1 {
+		close(g.started)
+	}
+	select {
+	case <-g.release:
+		return []domaingames.Game{{ID: "g1"}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func waitForJob(t *testing.T, h *AdminHandler, id string) RefreshJob {
+	t.Helper()
+	var job RefreshJob
+	teststubs.WaitFor(t, time.Second, func() bool {
+		var ok bool
+		job, ok = h.jobs.get(id)
+		return ok && job.State != jobRunning
+	}, "job %s did not finish", id)
+	return job
+}
+
+func TestAdminRefreshContinuesAfterClientDisconnect(t *testing.T) {
+	date := timeutil.FormatDate(time.Now())
+	writer := snapshots.NewWriter(t.TempDir(), 1)
+	provider := &gatedProvider{started: make(chan struct{}), release: make(chan struct{})}
+	h := NewAdminHandler(writer, provider, "secret", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/admin/snapshots/refresh?date="+date, nil).WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+
+	returned := make(chan struct{})
+	go func() {
+		h.RefreshSnapshots(rr, req)
+		close(returned)
+	}()
+	<-provider.started
+	cancel()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("expected handler to return after client disconnect")
+	}
+
+	if len(h.jobs.order) != 1 {
+		t.Fatalf("expected one job recorded, got %d", len(h.jobs.order))
+	}
+	id := h.jobs.order[0]
+	if job, _ := h.jobs.get(id); job.State != jobRunning {
+		t.Fatalf("expected job still running after disconnect, got %s", job.State)
+	}
+
+	close(provider.release)
+	job := waitForJob(t, h, id)
+	if job.State != jobSucceeded || job.Count != 1 {
+		t.Fatalf("expected background refresh to succeed, got %+v", job)
+	}
+	if _, err := writer.Manifest(); err != nil {
+		t.Fatalf("expected manifest after background write, got %v", err)
+	}
+
+	statusReq := httptest.NewRequest(http.MethodGet, "/admin/snapshots/jobs/"+id, nil)
+	statusReq.Header.Set("Authorization", "Bearer secret")
+	statusRR := httptest.NewRecorder()
+	adminMux(h).ServeHTTP(statusRR, statusReq)
+	if statusRR.Code != http.StatusOK {
+		t.Fatalf("expected 200 from job status, got %d", statusRR.Code)
+	}
+	var got RefreshJob
+	if err := json.NewDecoder(statusRR.Body).Decode(&got); err != nil {
+		t.Fatalf("decode job: %v", err)
+	}
+	if got.ID != id || got.State != jobSucceeded || got.Date != date {
+		t.Fatalf("unexpected job payload %+v", got)
+	}
+}
+
+func TestAdminRefreshFetchTimeout(t *testing.T) {
+	h := NewAdminHandlerWithTimeout(snapshots.NewWriter(t.TempDir(), 1), teststubs.BlockingProvider{}, "secret", nil, 20*time.Millisecond)
+
+	rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?date=2024-01-01", "secret")
+	if rr.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected 504 after fetch timeout, got %d", rr.Code)
+	}
+	job, _ := h.jobs.get(h.jobs.order[0])
+	if job.State != jobFailed || job.Error == "" {
+		t.Fatalf("expected failed job with error, got %+v", job)
+	}
+}
+
+func TestAdminRefreshJobStatusErrors(t *testing.T) {
+	h := NewAdminHandler(nil, nil, "secret", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/snapshots/jobs/missing", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	adminMux(h).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown job, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	adminMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/snapshots/jobs/missing", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	adminMux(h).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/snapshots/jobs/missing", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405, got %d", rr.Code)
+	}
+}
+
+func TestRefreshJobsEvictsOldest(t *testing.T) {
+	jobs := newRefreshJobs()
+	first := jobs.start("2024-01-01", "", time.Now())
+	for i := 0; i < maxRefreshJobs; i++ {
+		jobs.start("2024-01-02", "", time.Now())
+	}
+	if _, ok := jobs.get(first); ok {
+		t.Fatalf("expected oldest job evicted")
+	}
+	if len(jobs.jobs) != maxRefreshJobs {
+		t.Fatalf("expected %d jobs kept, got %d", maxRefreshJobs, len(jobs.jobs))
+	}
+	jobs.finish("unknown", 1, nil, time.Now()) // no-op
+}
+
+func TestAdminRefreshCoalescesConcurrentRequests(t *testing.T) {
+	date := timeutil.FormatDate(time.Now())
+	writer := snapshots.NewWriter(t.TempDir(), 1)
+	provider := &gatedProvider{started: make(chan struct{}), release: make(chan struct{})}
+	h := NewAdminHandler(writer, provider, "secret", nil)
+
+	const requests = 10
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := range codes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes[i] = callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?date="+date, "secret").Code
+		}()
+	}
+	<-provider.started
+	teststubs.WaitFor(t, time.Second, func() bool {
+		return writer.RefreshWaiters(date, snapshots.RefreshOptions{}) == requests-1
+	}, "refreshes did not join the in-flight fetch")
+	close(provider.release)
+	wg.Wait()
+
+	if got := provider.calls.Load(); got != 1 {
+		t.Fatalf("expected exactly one upstream fetch, got %d", got)
+	}
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, code)
+		}
+	}
+	if len(h.jobs.order) != requests {
+		t.Fatalf("expected a job per request, got %d", len(h.jobs.order))
+	}
+}
@@ -6,25 +6,40 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
 	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
-func callRefresh(t *testing.T, h *AdminHandler, method, path, token string) *httptest.ResponseRecorder {
+// adminMux mounts h's routes the way the server does.
+func adminMux(h *AdminHandler) *Mux {
+	mux := NewMux(nil)
+	h.Register(mux)
+	return mux
+}
+
+func callAdmin(t *testing.T, h *AdminHandler, method, path, token string) *httptest.ResponseRecorder {
 	t.Helper()
 	req := httptest.NewRequest(method, path, nil)
 	if token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 	rr := httptest.NewRecorder()
-	h.RefreshSnapshots(rr, req)
+	adminMux(h).ServeHTTP(rr, req)
 	return rr
 }
 
+func callRefresh(t *testing.T, h *AdminHandler, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return callAdmin(t, h, method, path, token)
+}
+
 func TestAdminRefreshRequiresAuth(t *testing.T) {
 	h := NewAdminHandler(nil, nil, "secret", nil)
 	rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh", "")
@@ -47,6 +62,32 @@ func TestAdminRefreshWritesSnapshot(t *testing.T) {
 	}
 }
 
+func TestAdminRefreshDefaultsDateAndStampsJobFromClock(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	clk := teststubs.NewFakeClock(now)
+	writer := snapshots.NewWriter(t.TempDir(), 1)
+	writer.SetClock(clk)
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{{ID: "g1"}}}
+	h := NewAdminHandlerWithClock(writer, provider, "secret", nil, 0, clk)
+
+	rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh", "secret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	var body struct {
+		Date  string `json:"date"`
+		JobID string `json:"jobId"`
+	}
+	testutil.DecodeJSON(t, rr, &body)
+	if body.Date != "2024-01-15" {
+		t.Fatalf("expected the clock's date, got %q", body.Date)
+	}
+	job, ok := h.jobs.get(body.JobID)
+	if !ok || !job.StartedAt.Equal(now) || !job.FinishedAt.Equal(now) {
+		t.Fatalf("expected job stamped with the clock, got %+v", job)
+	}
+}
+
 func TestAdminRefreshRejectsWrongMethod(t *testing.T) {
 	h := NewAdminHandler(nil, nil, "secret", nil)
 	rr := callRefresh(t, h, http.MethodGet, "/admin/snapshots/refresh", "secret")
@@ -71,9 +112,11 @@ func TestAdminRefreshRejectsInvalidDate(t *testing.T) {
 func TestAdminRefreshValidatesTimezone(t *testing.T) {
 	h := NewAdminHandler(snapshots.NewWriter(t.TempDir(), 1), &teststubs.StubProvider{Games: []domaingames.Game{{ID: "g1"}}}, "secret", nil)
 
-	rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?tz=bad/tz", "secret")
-	if rr.Code != http.StatusBadRequest {
-		t.Fatalf("expected 400 for bad tz, got %d", rr.Code)
+	for _, tz := range []string{"bad/tz", "..%2F..%2Fetc%2Fpasswd", "UTC%0Aforged", strings.Repeat("A", 65)} {
+		rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?tz="+tz, "secret")
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for tz %q, got %d", tz, rr.Code)
+		}
 	}
 }
 
@@ -112,8 +155,8 @@ func TestAdminRefreshRequiresConfiguredWriterAndProvider(t *testing.T) {
 func TestAdminRefreshMethodNotAllowed(t *testing.T) {
 	h := NewAdminHandler(nil, nil, "secret", nil)
 	rr := callRefresh(t, h, http.MethodGet, "/admin/snapshots/refresh", "")
-	if rr.Code != http.StatusMethodNotAllowed {
-		t.Fatalf("expected 405, got %d", rr.Code)
+	if rr.Code != http.StatusMethodNotAllowed || rr.Header().Get("Allow") != "POST" {
+		t.Fatalf("expected 405 allowing POST, got %d %q", rr.Code, rr.Header().Get("Allow"))
 	}
 }
 
@@ -137,6 +180,7 @@ func TestAdminRefreshUnauthorizedLogsClientIP(t *testing.T) {
 	h := NewAdminHandler(nil, nil, "secret", logger)
 	req := httptest.NewRequest(http.MethodPost, "/admin/snapshots/refresh", nil)
 	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	req.Header.Set("Authorization", "Bearer wrong-s3cret")
 	rr := httptest.NewRecorder()
 
 	h.RefreshSnapshots(rr, req)
@@ -144,8 +188,12 @@ func TestAdminRefreshUnauthorizedLogsClientIP(t *testing.T) {
 	if rr.Code != http.StatusUnauthorized {
 		t.Fatalf("expected 401, got %d", rr.Code)
 	}
-	if buf.Len() == 0 {
-		t.Fatalf("expected unauthorized access to be logged")
+	logs := buf.String()
+	if !strings.Contains(logs, "client_ip=10.0.0.1") || !strings.Contains(logs, "auth_scheme=Bearer") {
+		t.Fatalf("expected unauthorized access to be logged with its client and scheme, got %s", logs)
+	}
+	if strings.Contains(logs, "s3cret") {
+		t.Fatalf("expected the bearer token kept out of the log, got %s", logs)
 	}
 }
 
@@ -184,3 +232,208 @@ func TestAdminTokenFromEnv(t *testing.T) {
 		t.Fatalf("expected token from env, got %s", got)
 	}
 }
+
+func callPin(t *testing.T, h *AdminHandler, method, date, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	return callAdmin(t, h, method, "/admin/snapshots/pin/"+date, token)
+}
+
+func TestAdminPinAndUnpinSnapshot(t *testing.T) {
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	date := timeutil.FormatDate(time.Now())
+	if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, nil)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	h := NewAdminHandler(writer, nil, "secret", nil)
+
+	if rr := callPin(t, h, http.MethodPost, date, "secret"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 on pin, got %d", rr.Code)
+	}
+	m, _ := writer.Manifest()
+	if len(m.Games.Pinned) != 1 || m.Games.Pinned[0] != date {
+		t.Fatalf("expected date pinned, got %v", m.Games.Pinned)
+	}
+
+	if rr := callPin(t, h, http.MethodDelete, date, "secret"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 on unpin, got %d", rr.Code)
+	}
+	m, _ = writer.Manifest()
+	if len(m.Games.Pinned) != 0 {
+		t.Fatalf("expected no pinned dates, got %v", m.Games.Pinned)
+	}
+}
+
+func TestAdminPinSnapshotErrors(t *testing.T) {
+	h := NewAdminHandler(snapshots.NewWriter(t.TempDir(), 1), nil, "secret", nil)
+
+	cases := []struct {
+		name   string
+		method string
+		date   string
+		token  string
+		want   int
+	}{
+		{name: "missing snapshot", method: http.MethodPost, date: "2024-01-01", token: "secret", want: http.StatusNotFound},
+		{name: "invalid date", method: http.MethodPost, date: "bad-date", token: "secret", want: http.StatusBadRequest},
+		{name: "unauthorized", method: http.MethodPost, date: "2024-01-01", want: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodGet, date: "2024-01-01", token: "secret", want: http.StatusMethodNotAllowed},
+		{name: "escaped date", method: http.MethodPost, date: "2024%2D01%2D01", token: "secret", want: http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if rr := callPin(t, h, tc.method, tc.date, tc.token); rr.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rr.Code)
+			}
+		})
+	}
+	if allow := callPin(t, h, http.MethodGet, "2024-01-01", "secret").Header().Get("Allow"); allow != "POST, DELETE" {
+		t.Fatalf("expected Allow listing POST and DELETE, got %q", allow)
+	}
+
+	noWriter := NewAdminHandler(nil, nil, "secret", nil)
+	if rr := callPin(t, noWriter, http.MethodPost, "2024-01-01", "secret"); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without writer, got %d", rr.Code)
+	}
+}
+
+func TestAdminListSnapshots(t *testing.T) {
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	date := timeutil.FormatDate(time.Now())
+	if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, nil)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	h := NewAdminHandler(writer, nil, "secret", nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/snapshots", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rr := httptest.NewRecorder()
+	h.ListSnapshots(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if !strings.Contains(rr.Body.String(), `"pinned":[]`) || !strings.Contains(rr.Body.String(), `"usage"`) {
+		t.Fatalf("unexpected body %s", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ListSnapshots(rr, httptest.NewRequest(http.MethodGet, "/admin/snapshots", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	NewAdminHandler(nil, nil, "secret", nil).ListSnapshots(rr, req)
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without writer, got %d", rr.Code)
+	}
+}
+
+func TestAdminRebuildManifest(t *testing.T) {
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	date := timeutil.FormatDate(time.Now())
+	if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, nil)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := os.Remove(snapshots.GameSnapshotPath(writer.BasePath(), date)); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	h := NewAdminHandler(writer, nil, "secret", nil)
+	path := "/admin/snapshots/manifest/rebuild"
+
+	rr := callAdmin(t, h, http.MethodPost, path, "secret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %s", rr.Code, rr.Body.String())
+	}
+	body := rr.Body.String()
+	if !strings.Contains(body, `"changed":true`) || !strings.Contains(body, `"removed":["`+date+`"]`) {
+		t.Fatalf("expected the removed date reported, got %s", body)
+	}
+	if m, _ := writer.Manifest(); len(m.Games.Dates) != 0 {
+		t.Fatalf("expected the manifest to drop the date, got %v", m.Games.Dates)
+	}
+	if rr := callAdmin(t, h, http.MethodPost, path, "secret"); !strings.Contains(rr.Body.String(), `"changed":false`) {
+		t.Fatalf("expected an unchanged second rebuild, got %s", rr.Body.String())
+	}
+
+	if rr := callAdmin(t, h, http.MethodPost, path, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rr.Code)
+	}
+	if rr := callAdmin(t, NewAdminHandler(nil, nil, "secret", nil), http.MethodPost, path, "secret"); rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without writer, got %d", rr.Code)
+	}
+}
+
+func TestAdminRefreshFrozenDateRequiresForce(t *testing.T) {
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	writer.SetFreezeGrace(0)
+	date := timeutil.FormatDate(time.Now())
+	final := domaingames.Game{ID: "g1", StatusKind: domaingames.StatusFinal}
+	if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{final})); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{final}}
+	h := NewAdminHandler(writer, provider, "secret", nil)
+
+	rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?date="+date, "secret")
+	testutil.AssertStatus(t, rr, http.StatusConflict)
+	if body := decodeError(t, rr); body.Error.Code != CodeSnapshotFrozen {
+		t.Fatalf("expected SNAPSHOT_FROZEN, got %+v", body.Error)
+	}
+	if provider.Calls.Load() != 0 {
+		t.Fatalf("expected no upstream fetch for a frozen date")
+	}
+
+	rr = callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?force=true&date="+date, "secret")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected forced refresh to succeed, got %d %s", rr.Code, rr.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/snapshots", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	list := httptest.NewRecorder()
+	h.ListSnapshots(list, req)
+	if !strings.Contains(list.Body.String(), `"frozen":["`+date+`"]`) {
+		t.Fatalf("expected frozen date in list, got %s", list.Body.String())
+	}
+}
+
+func TestAdminReloadConfig(t *testing.T) {
+	h := NewAdminHandler(nil, nil, "secret", nil)
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		h.ReloadConfig(rr, req)
+		return rr
+	}
+
+	if rr := serve("secret"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a reloader, got %d", rr.Code)
+	}
+
+	h.SetConfigReload(func() (ConfigReload, error) {
+		return ConfigReload{Applied: []string{"PollInterval"}, Skipped: []string{"Port"}}, nil
+	})
+	if rr := serve(""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rr.Code)
+	}
+	rr := serve("secret")
+	var got ConfigReload
+	testutil.DecodeJSON(t, rr, &got)
+	if rr.Code != http.StatusOK || len(got.Applied) != 1 || got.Skipped[0] != "Port" {
+		t.Fatalf("unexpected reload response %d %+v", rr.Code, got)
+	}
+
+	h.SetConfigReload(func() (ConfigReload, error) {
+		return ConfigReload{}, errors.Join(errors.New("PORT bad"), errors.New("PROVIDER bad"))
+	})
+	rr = serve("secret")
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 for invalid config, got %d", rr.Code)
+	}
+	if body := decodeError(t, rr); body.Error.Code != CodeInvalidConfig {
+		t.Fatalf("expected INVALID_CONFIG, got %+v", body)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+// APIKeyHeader carries a partner's API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKey is one accepted key. Name stands in for the key in logs and metrics.
+type APIKey struct {
+	Key  string
+	Name string
+	RPM  int // requests per minute, with up to a minute's worth in a burst; 0 is unlimited
+}
+
+// APIKeyConfig configures NewAPIKeyAuth.
+type APIKeyConfig struct {
+	Keys []APIKey
+	// Required rejects requests without a key. When false they pass through
+	// anonymously and unlimited, as before keys existed; a wrong key is
+	// rejected either way.
+	Required bool
+	Clock    clock.Clock // defaults to the real clock
+}
+
+// APIKeyAuth checks X-API-Key and enforces each key's rate limit.
+type APIKeyAuth struct {
+	keys     map[string]*apiKeyState
+	required bool
+	clock    clock.Clock
+	recorder *metrics.Recorder
+	logger   *slog.Logger
+}
+
+type apiKeyState struct {
+	name   string
+	bucket *tokenBucket // nil when unlimited
+}
+
+// NewAPIKeyAuth builds the key checker. It returns nil, which Protect treats
+// as a passthrough, when there are no keys and none is required.
+func NewAPIKeyAuth(cfg APIKeyConfig, recorder *metrics.Recorder, logger *slog.Logger) *APIKeyAuth {
+	if len(cfg.Keys) == 0 && !cfg.Required {
+		return nil
+	}
+	a := &APIKeyAuth{
+		keys:     make(map[string]*apiKeyState, len(cfg.Keys)),
+		required: cfg.Required,
+		clock:    clock.OrReal(cfg.Clock),
+		recorder: recorder,
+		logger:   logger,
+	}
+	for _, k := range cfg.Keys {
+		state := &apiKeyState{name: k.Name}
+		if k.RPM > 0 {
+			state.bucket = newTokenBucket(k.RPM, a.clock.Now())
+		}
+		a.keys[k.Key] = state
+	}
+	return a
+}
+
+// Protect checks keys on requests whose path starts with one of prefixes and
+// passes every other request straight to next. An authenticated request's
+// context logger gains an api_key attribute, and APIKeyName reports the name.
+func (a *APIKeyAuth) Protect(next nethttp.Handler, prefixes ...string) nethttp.Handler {
+	if a == nil {
+		return next
+	}
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if !hasAnyPrefix(r.URL.Path, prefixes) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		logger := loggerFromContext(r, a.logger)
+		raw := r.Header.Get(APIKeyHeader)
+		if raw == "" {
+			if a.required {
+				writeError(w, r, nethttp.StatusUnauthorized, CodeUnauthorized, "API key required", logger)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, ok := a.keys[raw]
+		if !ok {
+			logging.Warn(logger, "invalid api key", slog.String("client_ip", clientIP(r)))
+			writeError(w, r, nethttp.StatusUnauthorized, CodeUnauthorized, "invalid API key", logger)
+			return
+		}
+
+		if logger != nil {
+			logger = logger.With(slog.String(metrics.AttrAPIKey, key.name))
+		}
+		if wait, ok := key.bucket.take(a.clock.Now()); !ok {
+			a.recorder.RecordAPIKeyRequest(key.name, true)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, r, nethttp.StatusTooManyRequests, CodeRateLimited, "API key rate limit exceeded", logger)
+			return
+		}
+		a.recorder.RecordAPIKeyRequest(key.name, false)
+
+		ctx := context.WithValue(r.Context(), apiKeyNameKey{}, key.name)
+		if logger != nil {
+			ctx = logging.WithLogger(ctx, logger)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+type apiKeyNameKey struct{}
+
+// APIKeyName returns the name of the key that authenticated the request in
+// ctx, or "" for anonymous requests.
+func APIKeyName(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyNameKey{}).(string)
+	return name
+}
+
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenBucket refills at rpm tokens per minute up to rpm tokens, so a key may
+// spend a minute's quota in a burst but not exceed it on average.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	capacity float64
+	perSec   float64
+	last     time.Time
+}
+
+func newTokenBucket(rpm int, now time.Time) *tokenBucket {
+	return &tokenBucket{tokens: float64(rpm), capacity: float64(rpm), perSec: float64(rpm) / 60, last: now}
+}
+
+// take spends a token, or reports how long until one is available. A nil
+// bucket always allows.
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	if b == nil {
+		return 0, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.perSec)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / b.perSec * float64(time.Second)), false
+}
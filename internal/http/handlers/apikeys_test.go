@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/http/middleware"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func apiKeyServer(t *testing.T, required bool) (http.Handler, *teststubs.FakeClock, *metrics.Recorder) {
+	t.Helper()
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	rec := metrics.NewRecorder()
+	auth := NewAPIKeyAuth(APIKeyConfig{
+		Keys: []APIKey{
+			{Key: "key-a", Name: "alpha", RPM: 2},
+			{Key: "key-b", Name: "beta", RPM: 60},
+		},
+		Required: required,
+		Clock:    clk,
+	}, rec, nil)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Key-Name", APIKeyName(r.Context()))
+		w.WriteHeader(http.StatusOK)
+	})
+	return auth.Protect(next, "/games", "/teams/"), clk, rec
+}
+
+func callWithKey(h http.Handler, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestAPIKeyValid(t *testing.T) {
+	h, _, rec := apiKeyServer(t, true)
+	rr := callWithKey(h, "/games", "key-a")
+	if rr.Code != http.StatusOK || rr.Header().Get("X-Key-Name") != "alpha" {
+		t.Fatalf("expected 200 as alpha, got %d %q", rr.Code, rr.Header().Get("X-Key-Name"))
+	}
+	if rec.APIKeyRequests("alpha") != 1 {
+		t.Fatalf("expected request counted for alpha")
+	}
+}
+
+func TestAPIKeyInvalid(t *testing.T) {
+	for _, required := range []bool{true, false} {
+		h, _, _ := apiKeyServer(t, required)
+		rr := callWithKey(h, "/teams/1", "nope")
+		if rr.Code != http.StatusUnauthorized || decodeError(t, rr).Error.Code != CodeUnauthorized {
+			t.Fatalf("required=%v: expected 401 for an unknown key, got %d", required, rr.Code)
+		}
+	}
+}
+
+func TestAPIKeyLimitsArePerKey(t *testing.T) {
+	h, clk, rec := apiKeyServer(t, true)
+	for i := 0; i < 2; i++ {
+		if rr := callWithKey(h, "/games", "key-a"); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: expected alpha within its burst, got %d", i, rr.Code)
+		}
+	}
+	rr := callWithKey(h, "/games", "key-a")
+	if rr.Code != http.StatusTooManyRequests || decodeError(t, rr).Error.Code != CodeRateLimited {
+		t.Fatalf("expected alpha limited, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Retry-After"); got != "30" {
+		t.Fatalf("expected Retry-After 30 (2 rpm), got %q", got)
+	}
+	if rr := callWithKey(h, "/games", "key-b"); rr.Code != http.StatusOK {
+		t.Fatalf("expected beta unaffected by alpha's limit, got %d", rr.Code)
+	}
+	if rec.APIKeyRequests("alpha") != 3 || rec.APIKeyRequests("beta") != 1 {
+		t.Fatalf("unexpected counts alpha=%d beta=%d", rec.APIKeyRequests("alpha"), rec.APIKeyRequests("beta"))
+	}
+
+	clk.Advance(30 * time.Second)
+	if rr := callWithKey(h, "/games", "key-a"); rr.Code != http.StatusOK {
+		t.Fatalf("expected alpha refilled after 30s, got %d", rr.Code)
+	}
+}
+
+func TestAPIKeyAnonymousAllowed(t *testing.T) {
+	h, _, _ := apiKeyServer(t, false)
+	if rr := callWithKey(h, "/games", ""); rr.Code != http.StatusOK || rr.Header().Get("X-Key-Name") != "" {
+		t.Fatalf("expected anonymous request through, got %d", rr.Code)
+	}
+
+	h, _, _ = apiKeyServer(t, true)
+	if rr := callWithKey(h, "/games", ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a key when required, got %d", rr.Code)
+	}
+	if rr := callWithKey(h, "/health", ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected unprotected paths to skip the check, got %d", rr.Code)
+	}
+}
+
+func TestNewAPIKeyAuthWithoutKeysIsPassthrough(t *testing.T) {
+	auth := NewAPIKeyAuth(APIKeyConfig{}, nil, nil)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	if auth != nil {
+		t.Fatalf("expected nil auth without keys")
+	}
+	if rr := callWithKey(auth.Protect(next, "/games"), "/games", "anything"); rr.Code != http.StatusOK {
+		t.Fatalf("expected passthrough, got %d", rr.Code)
+	}
+}
+
+func TestAPIKeyRequestKeepsRouteLabel(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	mux := NewMux(nil)
+	mux.HandleFunc("GET /teams/{id}/vs/{otherId}", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	auth := NewAPIKeyAuth(APIKeyConfig{Keys: []APIKey{{Key: "key-a", Name: "alpha"}}}, nil, nil)
+	// Slow requests log their route label, which the mux reports through the
+	// request context even though auth hands it a cloned request.
+	handler := middleware.LoggingMiddlewareWithOptions(logger, nil, middleware.LoggingOptions{SlowThreshold: time.Millisecond}, auth.Protect(mux, "/teams/"))
+
+	if rr := callWithKey(handler, "/teams/1/vs/2", "key-a"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if logs := buf.String(); !strings.Contains(logs, "route=/teams/:id/vs/:otherId") {
+		t.Fatalf("expected the keyed request labeled by its pattern, got %s", logs)
+	}
+}
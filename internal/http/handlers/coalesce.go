@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	nethttp "net/http"
+	"sync"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+// SetRecorder makes the handler count coalesced requests on rec.
+func (h *Handler) SetRecorder(rec *metrics.Recorder) {
+	if h == nil {
+		return
+	}
+	h.recorder = rec
+}
+
+// bufferedResponse captures a handler's output so concurrent identical
+// requests can replay it.
+type bufferedResponse struct {
+	header nethttp.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: nethttp.Header{}, status: nethttp.StatusOK}
+}
+
+func (b *bufferedResponse) Header() nethttp.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(status int) { b.status = status }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// writeTo copies the captured response onto w.
+func (b *bufferedResponse) writeTo(w nethttp.ResponseWriter) {
+	for k, v := range b.header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes())
+}
+
+// flight is one in-progress computation shared by every request with its key.
+type flight struct {
+	done    chan struct{}
+	res     *bufferedResponse
+	waiters int
+}
+
+// flightGroup coalesces concurrent calls with the same key into one; results
+// are shared only while the leader is running, never cached afterwards.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// do runs fn once per key among concurrent callers. shared reports whether
+// the result came from another caller's run.
+func (g *flightGroup) do(key string, fn func() *bufferedResponse) (res *bufferedResponse, shared bool) {
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		f.waiters++
+		g.mu.Unlock()
+		<-f.done
+		return f.res, true
+	}
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.res = fn()
+	return f.res, false
+}
+
+// waiting returns how many callers are blocked on key's in-progress run.
+func (g *flightGroup) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.flights[key]; ok {
+		return f.waiters
+	}
+	return 0
+}
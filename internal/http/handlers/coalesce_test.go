@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+// slowStore blocks every LoadGames until release is closed and counts calls.
+type slowStore struct {
+	*teststubs.StubSnapshotStore
+	loads   atomic.Int32
+	release chan struct{}
+}
+
+func (s *slowStore) LoadGames(ctx context.Context, date string) (domaingames.TodayResponse, error) {
+	s.loads.Add(1)
+	<-s.release
+	return s.StubSnapshotStore.LoadGames(ctx, date)
+}
+
+func TestGamesByDateCoalescesConcurrentIdenticalRequests(t *testing.T) {
+	const n = 8
+	date := "2024-02-01"
+	store := &slowStore{
+		StubSnapshotStore: storeWithResponse(date, testutil.SampleTodayResponse(date, "shared-game")),
+		release:           make(chan struct{}),
+	}
+	h := newHandler(store, nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	rec := metrics.NewRecorder()
+	h.SetRecorder(rec)
+
+	var wg sync.WaitGroup
+	results := make([]*httptest.ResponseRecorder, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = testutil.Serve(h, http.MethodGet, "/games?date="+date, nil)
+		}()
+	}
+	key := gamesFlightKey(date, responseShape{}, domaingames.SortStartTime, nil)
+	testutil.WaitFor(t, 2*time.Second, func() bool { return h.flights.waiting(key) >= n-1 }, "expected %d waiters", n-1)
+	close(store.release)
+	wg.Wait()
+
+	if got := store.loads.Load(); got != 1 {
+		t.Fatalf("expected one snapshot load, got %d", got)
+	}
+	if got := rec.CoalescedRequests(); got != n-1 {
+		t.Fatalf("expected %d coalesced requests, got %d", n-1, got)
+	}
+	for _, rr := range results {
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		if rr.Body.String() != results[0].Body.String() {
+			t.Fatalf("expected identical bodies, got %q and %q", rr.Body.String(), results[0].Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("expected json content type, got %q", ct)
+		}
+	}
+}
+
+func TestShapeKeyDistinguishesShapes(t *testing.T) {
+	date := "2024-02-01"
+	plain := date + "|" + responseShape{}.key()
+	proj := date + "|" + responseShape{proj: &projection{fields: []string{"id"}}}.key()
+	if plain == proj {
+		t.Fatalf("expected projected and plain requests to use different keys")
+	}
+}
+
+func TestFlightGroupFollowersOfFailedRunComputeTheirOwn(t *testing.T) {
+	date := "2024-02-01"
+	store := &slowStore{
+		StubSnapshotStore: &teststubs.StubSnapshotStore{},
+		release:           make(chan struct{}),
+	}
+	h := newHandler(store, nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rr := testutil.Serve(h, http.MethodGet, "/games?date="+date, nil)
+			testutil.AssertStatus(t, rr, http.StatusBadGateway)
+		}()
+	}
+	key := gamesFlightKey(date, responseShape{}, domaingames.SortStartTime, nil)
+	testutil.WaitFor(t, 2*time.Second, func() bool { return h.flights.waiting(key) >= 1 }, "expected a waiter")
+	close(store.release)
+	wg.Wait()
+	if got := store.loads.Load(); got != 2 {
+		t.Fatalf("expected the follower to retry after a failed run, got %d loads", got)
+	}
+}
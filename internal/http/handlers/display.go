@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"log/slog"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// includeDisplay is the include= value that adds presentation fields to games.
+const includeDisplay = "display"
+
+// headerTZFallback names the zone used instead of an unrecognised tz parameter.
+const headerTZFallback = "X-Timezone-Fallback"
+
+// Start time layouts by locale. Keys are lower-case BCP 47 tags or bare languages;
+// anything unlisted uses defaultDisplayLayout.
+var displayLayouts = map[string]string{
+	"en":    "Mon, Jan 2 3:04 PM MST",
+	"en-us": "Mon, Jan 2 3:04 PM MST",
+	"en-ca": "Mon, Jan 2 3:04 PM MST",
+	"ja":    "2006/01/02 15:04 MST",
+	"zh":    "2006/01/02 15:04 MST",
+	"ko":    "2006.01.02 15:04 MST",
+}
+
+const defaultDisplayLayout = "Mon 2 Jan 15:04 MST"
+
+// displayGame is a game with presentation-only start time fields. These are
+// computed per request and never written to snapshots. GameDateLocal is the
+// calendar date in the requested zone, which differs from the snapshot date for
+// late West Coast tip-offs.
+type displayGame struct {
+	domaingames.Game
+	StartTimeLocal   string `json:"startTimeLocal,omitempty"`
+	GameDateLocal    string `json:"gameDateLocal,omitempty"`
+	StartTimeDisplay string `json:"startTimeDisplay,omitempty"`
+}
+
+// displayFormat is the zone and layout used for a request's display fields.
+// layout is empty unless include=display asked for startTimeDisplay.
+type displayFormat struct {
+	loc    *time.Location
+	layout string
+}
+
+// wantsDisplay reports whether include= lists "display".
+func wantsDisplay(r *nethttp.Request) bool {
+	for _, part := range strings.Split(r.URL.Query().Get("include"), ",") {
+		if strings.EqualFold(strings.TrimSpace(part), includeDisplay) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestedTZ returns the trimmed tz parameter.
+func requestedTZ(r *nethttp.Request) string {
+	return strings.TrimSpace(r.URL.Query().Get("tz"))
+}
+
+// validTZParam writes a 400 when tz is not shaped like an IANA zone name. Well-formed
+// but unknown zones pass and are handled by the caller.
+func validTZParam(w nethttp.ResponseWriter, r *nethttp.Request, logger *slog.Logger) bool {
+	tz := requestedTZ(r)
+	if tz == "" {
+		return true
+	}
+	if err := timeutil.ValidateTimezoneName(tz); err != nil {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidTimezone, "invalid tz: "+err.Error(), logger)
+		return false
+	}
+	return true
+}
+
+// displayFormatFor resolves the tz parameter and, when include=display is set, a
+// layout from the locale parameter or, failing that, the first Accept-Language
+// tag. An unknown tz uses fallback and reports fellBack.
+func displayFormatFor(r *nethttp.Request, fallback *time.Location) (format displayFormat, fellBack bool) {
+	format.loc = fallback
+	if tz := requestedTZ(r); tz != "" {
+		parsed, err := time.LoadLocation(tz)
+		if err != nil {
+			fellBack = true
+		} else {
+			format.loc = parsed
+		}
+	}
+	if wantsDisplay(r) {
+		locale := strings.TrimSpace(r.URL.Query().Get("locale"))
+		if locale == "" {
+			locale = firstLanguage(r.Header.Get("Accept-Language"))
+		}
+		format.layout = layoutForLocale(locale)
+	}
+	return format, fellBack
+}
+
+func firstLanguage(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	tag, _, _ := strings.Cut(first, ";")
+	return strings.TrimSpace(tag)
+}
+
+func layoutForLocale(locale string) string {
+	tag := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if layout, ok := displayLayouts[tag]; ok {
+		return layout
+	}
+	lang, _, _ := strings.Cut(tag, "-")
+	if layout, ok := displayLayouts[lang]; ok {
+		return layout
+	}
+	return defaultDisplayLayout
+}
+
+// decorate adds display fields to g. Games without a parseable start time are
+// returned unchanged.
+func (f displayFormat) decorate(g domaingames.Game) displayGame {
+	out := displayGame{Game: g}
+	start, err := time.Parse(time.RFC3339, g.StartTime)
+	if err != nil {
+		return out
+	}
+	local := start.In(f.loc)
+	out.StartTimeLocal = local.Format(time.RFC3339)
+	out.GameDateLocal = local.Format("2006-01-02")
+	if f.layout != "" {
+		out.StartTimeDisplay = local.Format(f.layout)
+	}
+	return out
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+func displayHandler() *Handler {
+	date := "2024-01-15"
+	game := testutil.SampleGame("g1")
+	game.StartTime = "2024-01-16T00:30:00Z"
+	undated := testutil.SampleGame("g2")
+	h := newHandler(storeWithGames(date, []domaingames.Game{game, undated}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	return h
+}
+
+func TestGamesDisplayFieldsAbsentByDefault(t *testing.T) {
+	rr := testutil.Serve(displayHandler(), http.MethodGet, "/games?date=2024-01-15", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	if strings.Contains(rr.Body.String(), "startTimeLocal") || strings.Contains(rr.Body.String(), "startTimeDisplay") {
+		t.Fatalf("expected no display fields without tz or include=display, got %s", rr.Body.String())
+	}
+}
+
+func TestGamesTimezoneAddsLocalFieldsWithoutDisplay(t *testing.T) {
+	rr := testutil.Serve(displayHandler(), http.MethodGet, "/games?date=2024-01-15&tz=America/New_York", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var resp struct{ Games []displayGame }
+	testutil.DecodeJSON(t, rr, &resp)
+	got := resp.Games[0]
+	if got.StartTimeLocal != "2024-01-15T19:30:00-05:00" || got.GameDateLocal != "2024-01-15" {
+		t.Fatalf("unexpected local fields %+v", got)
+	}
+	if got.StartTimeDisplay != "" {
+		t.Fatalf("expected startTimeDisplay only with include=display, got %q", got.StartTimeDisplay)
+	}
+}
+
+func TestGamesTimezoneLateWestCoastGameKeepsLocalDate(t *testing.T) {
+	date := "2024-01-15"
+	late := testutil.SampleGame("late")
+	late.StartTime = "2024-01-16T03:30:00Z" // 7:30 PM Pacific on the 15th
+	h := newHandler(storeWithGames(date, []domaingames.Game{late}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-01-15&tz=America/Los_Angeles", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var resp struct{ Games []displayGame }
+	testutil.DecodeJSON(t, rr, &resp)
+	got := resp.Games[0]
+	if got.StartTime != "2024-01-16T03:30:00Z" {
+		t.Fatalf("expected canonical start time untouched, got %s", got.StartTime)
+	}
+	if got.StartTimeLocal != "2024-01-15T19:30:00-08:00" || got.GameDateLocal != "2024-01-15" {
+		t.Fatalf("expected Pacific local date 2024-01-15, got %+v", got)
+	}
+	if rr.Header().Get(headerTZFallback) != "" {
+		t.Fatalf("expected no fallback header for a valid zone")
+	}
+}
+
+func TestGamesDisplayFieldsUseTimezoneAndLocale(t *testing.T) {
+	cases := []struct {
+		name        string
+		query       string
+		acceptLang  string
+		wantLocal   string
+		wantDisplay string
+	}{
+		{"new york us", "tz=America/New_York", "en-US,en;q=0.9", "2024-01-15T19:30:00-05:00", "Mon, Jan 15 7:30 PM EST"},
+		{"tokyo locale param", "tz=Asia/Tokyo&locale=ja-JP", "en-US", "2024-01-16T09:30:00+09:00", "2024/01/16 09:30 JST"},
+		{"london default layout", "tz=Europe/London", "fr-FR", "2024-01-16T00:30:00Z", "Tue 16 Jan 00:30 GMT"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/games?date=2024-01-15&include=display&"+tc.query, nil)
+			req.Header.Set("Accept-Language", tc.acceptLang)
+			rr := testutil.ServeRequest(displayHandler(), req)
+			testutil.AssertStatus(t, rr, http.StatusOK)
+
+			var resp struct{ Games []displayGame }
+			testutil.DecodeJSON(t, rr, &resp)
+			if len(resp.Games) != 2 {
+				t.Fatalf("expected 2 games, got %d", len(resp.Games))
+			}
+			got := resp.Games[0]
+			if got.StartTime != "2024-01-16T00:30:00Z" {
+				t.Fatalf("expected canonical start time untouched, got %s", got.StartTime)
+			}
+			if got.StartTimeLocal != tc.wantLocal || got.StartTimeDisplay != tc.wantDisplay {
+				t.Fatalf("unexpected display fields local=%q display=%q", got.StartTimeLocal, got.StartTimeDisplay)
+			}
+			if resp.Games[1].StartTimeLocal != "" {
+				t.Fatalf("expected no display fields for game without start time")
+			}
+		})
+	}
+}
+
+func TestGameByIDDisplayFields(t *testing.T) {
+	rr := testutil.Serve(displayHandler(), http.MethodGet, "/games/g1?include=display&tz=America/Chicago", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var got map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got["id"] != "g1" || got["startTimeLocal"] != "2024-01-15T18:30:00-06:00" {
+		t.Fatalf("unexpected game payload %v", got)
+	}
+}
+
+func TestGameByIDLooksUpTimezoneLocalDate(t *testing.T) {
+	// 01:30 UTC on 2024-01-16 is the 15th in New York and the 16th in Tokyo.
+	now := time.Date(2024, 1, 16, 1, 30, 0, 0, time.UTC)
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	cases := []struct {
+		name    string
+		service *time.Location
+		date    string
+		tz      string
+	}{
+		{name: "behind service zone", service: time.UTC, date: "2024-01-15", tz: "America/New_York"},
+		{name: "ahead of service zone", service: newYork, date: "2024-01-16", tz: "Asia/Tokyo"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(storeWithGames(tc.date, []domaingames.Game{testutil.SampleGame("g1")}), nil)
+			h.clock = testutil.NewFakeClock(now)
+			h.loc = tc.service
+
+			rr := testutil.Serve(h, http.MethodGet, "/games/g1", nil)
+			testutil.AssertStatus(t, rr, http.StatusNotFound)
+
+			rr = testutil.Serve(h, http.MethodGet, "/games/g1?tz="+tc.tz, nil)
+			testutil.AssertStatus(t, rr, http.StatusOK)
+			if !strings.Contains(rr.Body.String(), `"id":"g1"`) {
+				t.Fatalf("expected game from the %s snapshot, got %s", tc.date, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestUnknownTimezoneFallsBackWithHeader(t *testing.T) {
+	for _, path := range []string{
+		"/games?date=2024-01-15&tz=Not/AZone",
+		"/games?date=2024-01-15&include=display&tz=Not/AZone",
+		"/games/g1?include=display&tz=Not/AZone",
+	} {
+		rr := testutil.Serve(displayHandler(), http.MethodGet, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		if got := rr.Header().Get(headerTZFallback); got != "UTC" {
+			t.Fatalf("%s: expected fallback header naming the service zone, got %q", path, got)
+		}
+		if !strings.Contains(rr.Body.String(), `"startTimeLocal":"2024-01-16T00:30:00Z"`) {
+			t.Fatalf("%s: expected local fields in the service zone, got %s", path, rr.Body.String())
+		}
+	}
+}
+
+func TestLayoutForLocale(t *testing.T) {
+	if got := layoutForLocale("en_CA"); got != displayLayouts["en-ca"] {
+		t.Fatalf("expected underscore tag to match, got %q", got)
+	}
+	if got := layoutForLocale("zh-Hant-TW"); got != displayLayouts["zh"] {
+		t.Fatalf("expected language fallback, got %q", got)
+	}
+	if got := layoutForLocale(""); got != defaultDisplayLayout {
+		t.Fatalf("expected default layout, got %q", got)
+	}
+}
+
+func TestMalformedTimezoneRejected(t *testing.T) {
+	for _, path := range []string{
+		"/games?date=2024-01-15&tz=..%2F..%2Fetc%2Fpasswd",
+		"/games?date=2024-01-15&tz=UTC%0Aforged",
+		"/games/g1?include=display&tz=" + strings.Repeat("A", 65),
+	} {
+		rr := testutil.Serve(displayHandler(), http.MethodGet, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusBadRequest)
+	}
+}
+
+func FuzzGamesTimezoneParam(f *testing.F) {
+	for _, seed := range []string{"America/Los_Angeles", "Not/AZone", "../etc", "\x00", "A//B"} {
+		f.Add(seed)
+	}
+	h := displayHandler()
+	f.Fuzz(func(t *testing.T, tz string) {
+		rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-01-15&tz="+url.QueryEscape(tz), nil)
+		trimmed := strings.TrimSpace(tz)
+		wantBad := trimmed != "" && timeutil.ValidateTimezoneName(trimmed) != nil
+		if wantBad && rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for malformed tz %q, got %d", tz, rr.Code)
+		}
+		if !wantBad && rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 for tz %q, got %d", tz, rr.Code)
+		}
+	})
+}
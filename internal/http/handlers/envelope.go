@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"log/slog"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// headerDebugMeta set to "true" asks for the metadata envelope, as ?meta=true does.
+const headerDebugMeta = "X-Debug-Meta"
+
+// responseMeta says where a response's data came from, for clients debugging
+// stale data.
+type responseMeta struct {
+	// Source is "cache" for a decoded snapshot held in memory and "snapshot"
+	// for one read from disk.
+	Source string `json:"source"`
+	// GeneratedAt is when the served snapshot was written, or when the
+	// response was built if the store does not report it.
+	GeneratedAt  time.Time `json:"generatedAt"`
+	SnapshotDate string    `json:"snapshotDate"`
+	RequestID    string    `json:"requestId"`
+}
+
+// envelope wraps a payload with responseMeta; see wantsMeta.
+type envelope struct {
+	Meta responseMeta `json:"meta"`
+	Data any          `json:"data"`
+}
+
+// wantsMeta reports whether the request asked for the envelope with
+// ?meta=true or X-Debug-Meta: true, writing a 400 for a meta value other
+// than true or false.
+func wantsMeta(w nethttp.ResponseWriter, r *nethttp.Request, logger *slog.Logger) (want, ok bool) {
+	switch r.URL.Query().Get("meta") {
+	case "true":
+		return true, true
+	case "", "false":
+	default:
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidQuery, "meta must be true or false", logger)
+		return false, false
+	}
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get(headerDebugMeta)), "true"), true
+}
+
+// newResponseMeta describes a response built at now from the snapshot for
+// date that load recorded; a store that recorded nothing counts as a
+// snapshot read.
+func newResponseMeta(r *nethttp.Request, date string, load *snapshots.LoadInfo, now time.Time) responseMeta {
+	meta := responseMeta{
+		Source:       string(snapshots.LoadSourceSnapshot),
+		GeneratedAt:  now.UTC(),
+		SnapshotDate: date,
+		RequestID:    requestID(r),
+	}
+	if load.Source != "" {
+		meta.Source = string(load.Source)
+	}
+	if !load.ModTime.IsZero() {
+		meta.GeneratedAt = load.ModTime
+	}
+	return meta
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+type envelopeBody struct {
+	Meta responseMeta `json:"meta"`
+	Data struct {
+		Date  string            `json:"date"`
+		Games []json.RawMessage `json:"games"`
+	} `json:"data"`
+}
+
+func serveWithRequestID(h http.Handler, path, reqID string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-Request-ID", reqID)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestGamesMetaReportsCacheAndSnapshotSources(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	writer := snapshots.NewWriter(t.TempDir(), 7)
+	writer.SetClock(testutil.NewFakeClock(now))
+	if err := writer.WriteGamesSnapshot("2024-06-03", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "g1"}}}); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	info, err := os.Stat(snapshots.GameSnapshotPath(writer.BasePath(), "2024-06-03"))
+	if err != nil {
+		t.Fatalf("stat snapshot: %v", err)
+	}
+	h := newHandler(snapshots.NewFSStoreWithCache(writer.BasePath(), 4), nil)
+	h.clock = testutil.NewFakeClock(now)
+
+	for i, want := range []string{"snapshot", "cache"} {
+		rr := serveWithRequestID(h, "/games?date=2024-06-03&meta=true", "req-"+want, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var got envelopeBody
+		testutil.DecodeJSON(t, rr, &got)
+		if got.Meta.Source != want || got.Meta.SnapshotDate != "2024-06-03" || got.Meta.RequestID != "req-"+want {
+			t.Fatalf("request %d: unexpected meta %+v", i, got.Meta)
+		}
+		if !got.Meta.GeneratedAt.Equal(info.ModTime()) {
+			t.Fatalf("request %d: expected generatedAt %s (the file's mtime), got %s", i, info.ModTime(), got.Meta.GeneratedAt)
+		}
+		if got.Data.Date != "2024-06-03" || len(got.Data.Games) != 1 {
+			t.Fatalf("request %d: expected the games under data, got %+v", i, got.Data)
+		}
+	}
+
+	// Without the cache every read is from disk.
+	h = newHandler(snapshots.NewFSStore(writer.BasePath()), nil)
+	h.clock = testutil.NewFakeClock(now)
+	rr := serveWithRequestID(h, "/games?date=2024-06-03", "req-header", http.Header{headerDebugMeta: {"true"}})
+	var got envelopeBody
+	testutil.DecodeJSON(t, rr, &got)
+	if got.Meta.Source != "snapshot" || got.Meta.RequestID != "req-header" {
+		t.Fatalf("expected X-Debug-Meta to enable the envelope, got %+v", got.Meta)
+	}
+}
+
+func TestGamesMetaWithStoreThatDoesNotRecord(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	h := newHandler(storeWithGames("2024-06-03", []domaingames.Game{{ID: "g1"}}), nil)
+	h.clock = testutil.NewFakeClock(now)
+
+	rr := serveWithRequestID(h, "/games?date=2024-06-03&meta=true&fields=id", "req-1", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var got envelopeBody
+	testutil.DecodeJSON(t, rr, &got)
+	if got.Meta.Source != "snapshot" || !got.Meta.GeneratedAt.Equal(now) || got.Meta.RequestID != "req-1" {
+		t.Fatalf("expected a snapshot read generated now, got %+v", got.Meta)
+	}
+	if len(got.Data.Games) != 1 || string(got.Data.Games[0]) != `{"id":"g1"}` {
+		t.Fatalf("expected the projected games under data, got %s", got.Data.Games)
+	}
+}
+
+func TestGamesDefaultShapeHasNoEnvelope(t *testing.T) {
+	h := newHandler(storeWithGames("2024-06-03", []domaingames.Game{{ID: "g1"}}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC))
+
+	for _, path := range []string{"/games?date=2024-06-03", "/games?date=2024-06-03&meta=false"} {
+		rr := serveWithRequestID(h, path, "req-1", http.Header{headerDebugMeta: {"false"}})
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var body map[string]json.RawMessage
+		testutil.DecodeJSON(t, rr, &body)
+		if _, ok := body["meta"]; ok || body["games"] == nil {
+			t.Fatalf("%s: expected the plain TodayResponse, got %v", path, body)
+		}
+	}
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-06-03&meta=yes", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+// ErrorCode is the stable, machine-readable half of an error response. Messages
+// are free-form and may change; clients should branch on the code.
+type ErrorCode string
+
+const (
+	CodeInvalidDate      ErrorCode = "INVALID_DATE"
+	CodeDateOutOfRange   ErrorCode = "DATE_OUT_OF_RANGE"
+	CodeInvalidGameID    ErrorCode = "INVALID_GAME_ID"
+	CodeInvalidTeamID    ErrorCode = "INVALID_TEAM_ID"
+	CodeInvalidTimezone  ErrorCode = "INVALID_TIMEZONE"
+	CodeInvalidQuery     ErrorCode = "INVALID_QUERY" // unknown query parameters, fields or sort
+	CodeNoGames          ErrorCode = "NO_GAMES"
+	CodeUnauthorized     ErrorCode = "UNAUTHORIZED"
+	CodeNotFound         ErrorCode = "NOT_FOUND"
+	CodeGameNotFound     ErrorCode = "GAME_NOT_FOUND"
+	CodeSnapshotNotFound ErrorCode = "SNAPSHOT_NOT_FOUND"
+	CodeJobNotFound      ErrorCode = "JOB_NOT_FOUND"
+	CodeMethodNotAllowed ErrorCode = "METHOD_NOT_ALLOWED"
+	CodeSnapshotFrozen   ErrorCode = "SNAPSHOT_FROZEN"
+	CodeRateLimited      ErrorCode = "RATE_LIMITED"
+	CodeInternal         ErrorCode = "INTERNAL"
+	// CodeStorageUnavailable covers a missing or unreadable snapshot store,
+	// writer or manifest.
+	CodeStorageUnavailable  ErrorCode = "STORAGE_UNAVAILABLE"
+	CodeUpstreamUnavailable ErrorCode = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamTimeout     ErrorCode = "UPSTREAM_TIMEOUT"
+	CodeRequestTimeout      ErrorCode = "REQUEST_TIMEOUT" // the handler ran past its deadline
+	CodeNotReady            ErrorCode = "NOT_READY"
+	CodeShuttingDown        ErrorCode = "SHUTTING_DOWN"
+	CodeTooManyStreams      ErrorCode = "TOO_MANY_STREAMS"
+	CodeInvalidConfig       ErrorCode = "INVALID_CONFIG" // a config reload failed validation
+)
+
+// errorCodes lists every code, in declaration order, for the OpenAPI enum.
+var errorCodes = []ErrorCode{
+	CodeInvalidDate, CodeDateOutOfRange, CodeInvalidGameID, CodeInvalidTeamID,
+	CodeInvalidTimezone, CodeInvalidQuery, CodeNoGames, CodeUnauthorized,
+	CodeNotFound, CodeGameNotFound, CodeSnapshotNotFound, CodeJobNotFound,
+	CodeMethodNotAllowed, CodeSnapshotFrozen, CodeRateLimited, CodeInternal,
+	CodeStorageUnavailable, CodeUpstreamUnavailable, CodeUpstreamTimeout,
+	CodeRequestTimeout, CodeNotReady, CodeShuttingDown, CodeTooManyStreams,
+	CodeInvalidConfig,
+}
+
+// errorResponse is the envelope every error is written in.
+type errorResponse struct {
+	Error     errorDetail `json:"error"`
+	RequestID string      `json:"requestId,omitempty"`
+}
+
+type errorDetail struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Details any       `json:"details,omitempty"`
+}
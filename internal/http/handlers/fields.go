@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"sort"
+	"strings"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// responseShape is the per-request presentation of games: local start time fields
+// (tz= or include=display) and/or a ?fields= projection. The zero value writes games unchanged.
+type responseShape struct {
+	display *displayFormat
+	proj    *projection
+}
+
+// shapedGames is the /games payload once games have been shaped.
+type shapedGames struct {
+	Date     string     `json:"date"`
+	Games    []any      `json:"games"`
+	DataAsOf *time.Time `json:"dataAsOf,omitempty"`
+}
+
+// parseShape resolves tz=, include=display and ?fields=, writing a 400 for a
+// malformed tz or unknown fields.
+func (h *Handler) parseShape(w nethttp.ResponseWriter, r *nethttp.Request) (responseShape, bool) {
+	var shape responseShape
+	var template any = domaingames.Game{}
+	if !validTZParam(w, r, h.logger) {
+		return shape, false
+	}
+	if wantsDisplay(r) || requestedTZ(r) != "" {
+		format, fellBack := displayFormatFor(r, h.loc)
+		if fellBack {
+			w.Header().Set(headerTZFallback, format.loc.String())
+		}
+		shape.display = &format
+		template = displayGame{}
+	}
+	if fields, ok := requestedFields(r); ok {
+		proj, err := newProjection(template, fields)
+		if err != nil {
+			writeError(w, r, nethttp.StatusBadRequest, CodeInvalidQuery, err.Error(), h.logger)
+			return shape, false
+		}
+		shape.proj = &proj
+	}
+	return shape, true
+}
+
+func (s responseShape) plain() bool {
+	return s.display == nil && s.proj == nil
+}
+
+// key identifies the shape for request coalescing; equal keys render equal bodies.
+func (s responseShape) key() string {
+	var b strings.Builder
+	if s.display != nil {
+		b.WriteString(s.display.loc.String())
+		b.WriteString("|")
+		b.WriteString(s.display.layout)
+	}
+	b.WriteString("|")
+	if s.proj != nil {
+		b.WriteString(strings.Join(s.proj.fields, ","))
+	}
+	return b.String()
+}
+
+func (s responseShape) game(g domaingames.Game) (any, error) {
+	var v any = g
+	if s.display != nil {
+		v = s.display.decorate(g)
+	}
+	if s.proj != nil {
+		return s.proj.apply(v)
+	}
+	return v, nil
+}
+
+func (s responseShape) games(snap domaingames.TodayResponse) (shapedGames, error) {
+	out := shapedGames{Date: snap.Date, Games: make([]any, 0, len(snap.Games)), DataAsOf: snap.DataAsOf}
+	for _, g := range snap.Games {
+		shaped, err := s.game(g)
+		if err != nil {
+			return shapedGames{}, err
+		}
+		out.Games = append(out.Games, shaped)
+	}
+	return out, nil
+}
+
+// requestedFields parses ?fields= into a list of names; ok is false when the
+// parameter is absent or empty.
+func requestedFields(r *nethttp.Request) ([]string, bool) {
+	raw := strings.TrimSpace(r.URL.Query().Get("fields"))
+	if raw == "" {
+		return nil, false
+	}
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields, len(fields) > 0
+}
+
+// projection keeps only the requested top-level JSON fields of values shaped like
+// its template type. Valid names come from the template's JSON tags.
+type projection struct {
+	fields []string
+}
+
+// newProjection validates fields against template's top-level JSON properties.
+func newProjection(template any, fields []string) (projection, error) {
+	props, _ := schemaOf(template)["properties"].(map[string]any)
+	var unknown []string
+	for _, f := range fields {
+		if _, ok := props[f]; !ok {
+			unknown = append(unknown, f)
+		}
+	}
+	if len(unknown) > 0 {
+		allowed := make([]string, 0, len(props))
+		for name := range props {
+			allowed = append(allowed, name)
+		}
+		sort.Strings(allowed)
+		return projection{}, fmt.Errorf("unknown field(s) %s; allowed: %s", strings.Join(unknown, ", "), strings.Join(allowed, ", "))
+	}
+	return projection{fields: fields}, nil
+}
+
+// apply round-trips v through JSON and keeps the projected keys.
+func (p projection) apply(v any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var full map[string]json.RawMessage
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(p.fields))
+	for _, f := range p.fields {
+		if raw, ok := full[f]; ok {
+			out[f] = raw
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func fieldsHandler() *Handler {
+	game := testutil.SampleGame("g1")
+	game.StartTime = "2024-01-15T19:00:00Z"
+	game.Score = domaingames.Score{Home: 101, Away: 99}
+	h := newHandler(storeWithGames("2024-01-15", []domaingames.Game{game}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	return h
+}
+
+// decodeGames returns the games array of a /games response as raw field maps.
+func decodeGames(t *testing.T, body []byte) []map[string]json.RawMessage {
+	t.Helper()
+	var resp struct {
+		Date  string                       `json:"date"`
+		Games []map[string]json.RawMessage `json:"games"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Date != "2024-01-15" {
+		t.Fatalf("expected date kept alongside projected games, got %q", resp.Date)
+	}
+	return resp.Games
+}
+
+func keys(m map[string]json.RawMessage) string {
+	return strings.Join(sortedKeys(m), ",")
+}
+
+func TestGamesFieldsSingleField(t *testing.T) {
+	rr := testutil.Serve(fieldsHandler(), http.MethodGet, "/games?date=2024-01-15&fields=id", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	games := decodeGames(t, rr.Body.Bytes())
+	if len(games) != 1 || keys(games[0]) != "id" || string(games[0]["id"]) != `"g1"` {
+		t.Fatalf("unexpected projection %v", games)
+	}
+}
+
+func TestGamesFieldsMultipleWithNestedScore(t *testing.T) {
+	rr := testutil.Serve(fieldsHandler(), http.MethodGet, "/games?date=2024-01-15&fields=id,%20statusKind,score,startTime", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	games := decodeGames(t, rr.Body.Bytes())
+	if got := keys(games[0]); got != "id,score,startTime,statusKind" {
+		t.Fatalf("unexpected projected fields %s", got)
+	}
+	// Nested objects are kept whole.
+	if string(games[0]["score"]) != `{"home":101,"away":99}` {
+		t.Fatalf("expected full nested score, got %s", games[0]["score"])
+	}
+}
+
+func TestGameByIDFieldsComposeWithDisplay(t *testing.T) {
+	rr := testutil.Serve(fieldsHandler(), http.MethodGet, "/games/g1?fields=id,startTimeLocal&include=display&tz=America/New_York", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if keys(got) != "id,startTimeLocal" || string(got["startTimeLocal"]) != `"2024-01-15T14:00:00-05:00"` {
+		t.Fatalf("unexpected projection %s", rr.Body.String())
+	}
+
+	// Display fields are only selectable when display is included.
+	rr = testutil.Serve(fieldsHandler(), http.MethodGet, "/games/g1?fields=startTimeLocal", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+}
+
+func TestGamesFieldsRejectsUnknownField(t *testing.T) {
+	rr := testutil.Serve(fieldsHandler(), http.MethodGet, "/games?date=2024-01-15&fields=id,homeScore", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+	body := decodeError(t, rr)
+	if body.Error.Code != CodeInvalidQuery || !strings.HasPrefix(body.Error.Message, "unknown field(s) homeScore; allowed: awayTeam, canonicalId, homeTeam, id,") {
+		t.Fatalf("unexpected error %+v", body.Error)
+	}
+}
@@ -3,169 +3,380 @@ package handlers
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"log/slog"
 	nethttp "net/http"
-	"net/url"
-	"strings"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/poller"
 	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
-type nowFunc func() time.Time
-
 // Handler wires HTTP routes to the snapshot store.
 type Handler struct {
 	snaps    snapshots.Store
 	logger   *slog.Logger
-	now      nowFunc
+	clock    clock.Clock
 	statusFn func() poller.Status
 	loc      *time.Location
+
+	snapshotDir string      // checked by verbose health; "" skips the check
+	drainCheck  func() bool // reports shutdown draining; nil never drains
+
+	recorder *metrics.Recorder
+	flights  flightGroup
+
+	statusMu       sync.RWMutex
+	statusSections map[string]StatusFunc
+
+	mux *Mux
 }
 
 // NewHandler constructs a Handler with defaults.
 func NewHandler(snaps snapshots.Store, logger *slog.Logger, statusFn func() poller.Status, loc *time.Location) *Handler {
+	return NewHandlerWithClock(snaps, logger, statusFn, loc, nil)
+}
+
+// NewHandlerWithClock is identical to NewHandler but reads the current date from clk.
+func NewHandlerWithClock(snaps snapshots.Store, logger *slog.Logger, statusFn func() poller.Status, loc *time.Location, clk clock.Clock) *Handler {
 	if loc == nil {
 		loc = time.UTC
 	}
-	return &Handler{
+	h := &Handler{
 		snaps:    snaps,
 		logger:   logger,
-		now:      time.Now,
+		clock:    clock.OrReal(clk),
 		statusFn: statusFn,
 		loc:      loc,
 	}
+	h.mux = h.routes()
+	return h
+}
+
+// routes maps method patterns to handler methods. "GET /games/" catches ids
+// that span segments so they get the same 400 as any other invalid id; Mux
+// answers everything unmatched with a JSON 404 or 405.
+func (h *Handler) routes() *Mux {
+	mux := NewMux(h.logger)
+	mux.HandleFunc("GET /health", h.Health)
+	mux.HandleFunc("GET /ready", h.Ready)
+	mux.HandleFunc("GET /status", h.Status)
+	mux.HandleFunc("GET /openapi.json", h.OpenAPI)
+	mux.HandleFunc("GET /games", h.GamesToday)
+	mux.HandleFunc("GET /games/{id}", h.GameByID)
+	mux.HandleFunc("GET /games/", h.GameByID)
+	return mux
 }
 
-// Health reports the service health.
+// ServeHTTP dispatches to the route matching r.
 func (h *Handler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
-	switch {
-	case r.URL.Path == "/health":
-		h.Health(w, r)
-	case r.URL.Path == "/ready":
-		h.Ready(w, r)
-	case r.URL.Path == "/games":
-		h.GamesToday(w, r)
-	case strings.HasPrefix(r.URL.Path, "/games/"):
-		h.GameByID(w, r)
-	default:
-		writeError(w, r, nethttp.StatusNotFound, "not found", h.logger)
-	}
+	h.mux.ServeHTTP(w, r)
 }
 
 func (h *Handler) Health(w nethttp.ResponseWriter, r *nethttp.Request) {
-	if !requireMethod(w, r, nethttp.MethodGet, h.logger) {
+	if err := r.Context().Err(); err != nil {
+		writeError(w, r, nethttp.StatusServiceUnavailable, CodeShuttingDown, "shutting down", h.logger)
 		return
 	}
-	if err := r.Context().Err(); err != nil {
-		writeError(w, r, nethttp.StatusServiceUnavailable, "shutting down", h.logger)
+	if r.URL.Query().Get("verbose") == "true" {
+		h.writeHealthReport(w)
 		return
 	}
-	resp := map[string]string{"status": "ok"}
+	status := HealthOK
+	if h.draining() {
+		status = HealthDraining
+	}
+	resp := map[string]string{"status": status}
 	writeJSON(w, nethttp.StatusOK, resp, h.logger)
 }
 
 // Ready reports readiness for traffic (e.g., for Kubernetes probes).
 func (h *Handler) Ready(w nethttp.ResponseWriter, r *nethttp.Request) {
-	if !requireMethod(w, r, nethttp.MethodGet, h.logger) {
+	if h.draining() {
+		writeError(w, r, nethttp.StatusServiceUnavailable, CodeShuttingDown, "draining", h.logger)
 		return
 	}
 	if h.statusFn == nil {
 		writeJSON(w, nethttp.StatusOK, map[string]string{"status": "ready"}, h.logger)
 		return
 	}
-	if h.statusFn().IsReady() {
+	st := h.statusFn()
+	if st.IsReady() {
 		writeJSON(w, nethttp.StatusOK, map[string]string{"status": "ready"}, h.logger)
 		return
 	}
-	msg := h.statusFn().LastError
+	// Before the first successful poll, a snapshot for today already on disk
+	// is enough to serve traffic.
+	if st.LastSuccess.IsZero() && h.hasTodaySnapshot(r) {
+		writeJSON(w, nethttp.StatusOK, map[string]string{"status": "ready", "source": "snapshot"}, h.logger)
+		return
+	}
+	msg := st.LastError
 	if msg == "" {
 		msg = "not ready"
 	}
-	writeError(w, r, nethttp.StatusServiceUnavailable, msg, h.logger)
+	writeError(w, r, nethttp.StatusServiceUnavailable, CodeNotReady, msg, h.logger)
 }
 
 // GamesToday returns the snapshot of games for a requested date.
 func (h *Handler) GamesToday(w nethttp.ResponseWriter, r *nethttp.Request) {
-	if !requireMethod(w, r, nethttp.MethodGet, h.logger) {
+	if !validateQuery(w, r, "/games", h.logger) {
+		return
+	}
+	shape, ok := h.parseShape(w, r)
+	if !ok {
+		return
+	}
+	postseason, ok := postseasonFilter(w, r, h.logger)
+	if !ok {
+		return
+	}
+	order, ok := gamesOrder(w, r, h.logger)
+	if !ok {
+		return
+	}
+	withMeta, ok := wantsMeta(w, r, h.logger)
+	if !ok {
 		return
 	}
 	dateParam := r.URL.Query().Get("date")
 	if dateParam == "" {
-		writeError(w, r, nethttp.StatusBadRequest, "date query param required (expected YYYY-MM-DD)", h.logger)
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidDate, "date query param required (expected YYYY-MM-DD)", h.logger)
 		return
 	}
-	now := h.now().In(h.loc)
-	logger := loggerFromContext(r, h.logger)
+	now := h.clock.Now().In(h.loc)
 
 	_, err := timeutil.ParseDate(dateParam)
 	if err != nil {
-		writeError(w, r, nethttp.StatusBadRequest, "invalid date format (expected YYYY-MM-DD)", h.logger)
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidDate, "invalid date format (expected YYYY-MM-DD)", h.logger)
 		return
 	}
-	minDate := timeutil.FormatDate(now.AddDate(0, 0, -7))
-	maxDate := timeutil.FormatDate(now.AddDate(0, 0, 7))
+	minDate := timeutil.DateOffset(now, h.loc, -7)
+	maxDate := timeutil.DateOffset(now, h.loc, 7)
 	if dateParam < minDate || dateParam > maxDate {
-		writeError(w, r, nethttp.StatusBadRequest, "date must be within 7 days of today", h.logger)
+		writeError(w, r, nethttp.StatusBadRequest, CodeDateOutOfRange, "date must be within 7 days of today", h.logger)
+		return
+	}
+
+	if withMeta {
+		// Served on its own so the meta describes this request's read.
+		h.serveGames(w, r, dateParam, postseason, order, shape, true)
 		return
 	}
+	res, shared := h.flights.do(gamesFlightKey(dateParam, shape, order, postseason), func() *bufferedResponse {
+		buf := newBufferedResponse()
+		h.serveGames(buf, r, dateParam, postseason, order, shape, false)
+		return buf
+	})
+	// Errors carry the leader's request id, so followers of a failed run
+	// compute their own response.
+	if shared && (res == nil || res.status != nethttp.StatusOK) {
+		h.serveGames(w, r, dateParam, postseason, order, shape, false)
+		return
+	}
+	if shared {
+		h.recorder.RecordCoalescedRequest("/games")
+	}
+	res.writeTo(w)
+}
+
+// gamesFlightKey identifies a /games request for coalescing; equal keys
+// render equal bodies.
+func gamesFlightKey(date string, shape responseShape, order domaingames.SortKey, postseason *bool) string {
+	key := date + "|" + shape.key() + "|sort=" + string(order)
+	if postseason != nil {
+		key += "|postseason=" + strconv.FormatBool(*postseason)
+	}
+	return key
+}
 
-	snap, err := h.loadSnapshot(dateParam)
+// serveGames loads the snapshot for date and writes it in the requested shape,
+// keeping only games whose postseason flag matches when postseason is set.
+// withMeta wraps the payload in the metadata envelope.
+func (h *Handler) serveGames(w nethttp.ResponseWriter, r *nethttp.Request, date string, postseason *bool, order domaingames.SortKey, shape responseShape, withMeta bool) {
+	logger := loggerFromContext(r, h.logger)
+	var zone *time.Location
+	if shape.display != nil {
+		zone = shape.display.loc
+	}
+	ctx, load := snapshots.WithLoadInfo(r.Context())
+	snap, err := h.loadSnapshotIn(ctx, date, zone)
 	if err != nil {
-		writeError(w, r, nethttp.StatusBadGateway, "snapshot unavailable", h.logger)
+		// A past date the syncer never wrote will not appear on retry, so it
+		// is a 404 rather than an unavailable store.
+		if errors.Is(err, fs.ErrNotExist) && date < timeutil.DateIn(h.clock.Now(), h.loc) {
+			writeError(w, r, nethttp.StatusNotFound, CodeSnapshotNotFound, "no snapshot for date", h.logger)
+			return
+		}
+		writeUpstreamError(w, r, err, "snapshot unavailable", h.logger)
 		return
 	}
 	if logger != nil {
 		logger.Info("served snapshot games", "date", snap.Date, "provider", "snapshot", "count", len(snap.Games))
 	}
 
-	payload := domaingames.NewTodayResponse(snap.Date, snap.Games)
-	writeJSON(w, nethttp.StatusOK, payload, h.logger)
+	// The snapshot's games may be shared with the store's cache.
+	games := slices.Clone(snap.Games)
+	domaingames.SortGames(games, order)
+	payload := domaingames.NewTodayResponse(snap.Date, games)
+	payload.DataAsOf = h.dataAsOf(date)
+	if postseason != nil {
+		payload.Games = filterPostseason(games, *postseason)
+	}
+	var body any = payload
+	if !shape.plain() {
+		shaped, err := shape.games(payload)
+		if err != nil {
+			writeError(w, r, nethttp.StatusInternalServerError, CodeInternal, "failed to shape response", h.logger)
+			return
+		}
+		body = shaped
+	}
+	if withMeta {
+		body = envelope{Meta: newResponseMeta(r, snap.Date, load, h.clock.Now()), Data: body}
+	}
+	writeJSON(w, nethttp.StatusOK, body, h.logger)
+}
+
+// dataAsOf is the poller's last successful fetch when date is today, the
+// only date it refreshes, and nil otherwise.
+func (h *Handler) dataAsOf(date string) *time.Time {
+	if h.statusFn == nil || date != timeutil.DateIn(h.clock.Now(), h.loc) {
+		return nil
+	}
+	last := h.statusFn().LastSuccess
+	if last.IsZero() {
+		return nil
+	}
+	return &last
+}
+
+// gamesOrder reads ?sort= (see domaingames.SortKeys), writing a 400 for an
+// unknown key. An absent sort orders by start time.
+func gamesOrder(w nethttp.ResponseWriter, r *nethttp.Request, logger *slog.Logger) (domaingames.SortKey, bool) {
+	order, err := domaingames.ParseSortKey(r.URL.Query().Get("sort"))
+	if err != nil {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidQuery, err.Error(), logger)
+		return "", false
+	}
+	return order, true
+}
+
+// postseasonFilter reads ?postseason=true|false, writing a 400 for any other
+// value. It returns nil when the parameter is absent.
+func postseasonFilter(w nethttp.ResponseWriter, r *nethttp.Request, logger *slog.Logger) (*bool, bool) {
+	var want bool
+	switch r.URL.Query().Get("postseason") {
+	case "":
+		return nil, true
+	case "true":
+		want = true
+	case "false":
+	default:
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidQuery, "postseason must be true or false", logger)
+		return nil, false
+	}
+	return &want, true
+}
+
+func filterPostseason(games []domaingames.Game, want bool) []domaingames.Game {
+	kept := make([]domaingames.Game, 0, len(games))
+	for _, g := range games {
+		if g.Meta.Postseason == want {
+			kept = append(kept, g)
+		}
+	}
+	return kept
 }
 
-// GameByID returns a specific game if present in today's snapshot.
+// GameByID returns a specific game if present in today's snapshot or, when tz=
+// puts the caller on a different date, in that date's snapshot.
 func (h *Handler) GameByID(w nethttp.ResponseWriter, r *nethttp.Request) {
-	if !requireMethod(w, r, nethttp.MethodGet, h.logger) {
+	id, ok := requestutil.PathID(r, "id")
+	if !ok || id == "games" {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidGameID, "invalid game id", h.logger)
 		return
 	}
-	// Expect path: /games/{id}
-	path := strings.TrimPrefix(r.URL.Path, "/games")
-	if path == "" || path == "/" {
-		writeError(w, r, nethttp.StatusBadRequest, "invalid game id", h.logger)
+	if !validateQuery(w, r, "/games/{id}", h.logger) {
 		return
 	}
-
-	idRaw := strings.TrimPrefix(path, "/")
-	id, err := url.PathUnescape(idRaw)
-	if err != nil || id == "" || id == "games" || strings.ContainsAny(id, " \t/") {
-		writeError(w, r, nethttp.StatusBadRequest, "invalid game id", h.logger)
+	shape, ok := h.parseShape(w, r)
+	if !ok {
 		return
 	}
 
 	if h.snaps == nil {
-		writeError(w, r, nethttp.StatusBadGateway, "snapshot store not configured", h.logger)
+		writeError(w, r, nethttp.StatusBadGateway, CodeStorageUnavailable, "snapshot store not configured", h.logger)
 		return
 	}
-	today := timeutil.FormatDate(h.now().In(h.loc))
-	game, ok := h.snaps.FindGameByID(today, id)
+	now := h.clock.Now()
+	today := timeutil.DateIn(now, h.loc)
+	game, ok := h.snaps.FindGameByID(r.Context(), today, id)
+	if !ok && shape.display != nil {
+		// Near midnight a tz= caller's today can be a day off the service's;
+		// look in the snapshot for the caller's date as well.
+		if local := timeutil.DateIn(now, shape.display.loc); local != today {
+			game, ok = h.snaps.FindGameByID(r.Context(), local, id)
+		}
+	}
 	if !ok {
-		writeError(w, r, nethttp.StatusNotFound, "game not found", h.logger)
+		writeError(w, r, nethttp.StatusNotFound, CodeGameNotFound, "game not found", h.logger)
+		return
+	}
+
+	shaped, err := shape.game(game)
+	if err != nil {
+		writeError(w, r, nethttp.StatusInternalServerError, CodeInternal, "failed to shape response", h.logger)
 		return
 	}
+	writeJSON(w, nethttp.StatusOK, shaped, h.logger)
+}
 
-	writeJSON(w, nethttp.StatusOK, game, h.logger)
+// hasTodaySnapshot reports whether the store has a games snapshot for today.
+func (h *Handler) hasTodaySnapshot(r *nethttp.Request) bool {
+	today := timeutil.DateIn(h.clock.Now(), h.loc)
+	_, err := h.loadSnapshot(r.Context(), today)
+	return err == nil
+}
+
+// zoneStore is implemented by stores that keep zone-scoped games snapshots
+// (snapshots.FSStore).
+type zoneStore interface {
+	LoadGamesForZone(ctx context.Context, date, zone string) (domaingames.TodayResponse, error)
+}
+
+// loadSnapshotIn reads date's games as a caller in zone sees them: the
+// zone-scoped snapshot when the store has one, else the service-zone
+// snapshot. A nil zone, or the service zone, reads the latter directly.
+func (h *Handler) loadSnapshotIn(ctx context.Context, date string, zone *time.Location) (domaingames.TodayResponse, error) {
+	if zs, ok := h.snaps.(zoneStore); ok && zone != nil && zone.String() != h.loc.String() {
+		if err := ctx.Err(); err != nil {
+			return domaingames.TodayResponse{}, err
+		}
+		snap, err := zs.LoadGamesForZone(ctx, date, zone.String())
+		if err == nil || !(errors.Is(err, fs.ErrNotExist) || errors.Is(err, snapshots.ErrInvalidSnapshotZone)) {
+			return snap, err
+		}
+	}
+	return h.loadSnapshot(ctx, date)
 }
 
-func (h *Handler) loadSnapshot(date string) (domaingames.TodayResponse, error) {
+// loadSnapshot reads date's games, giving up before touching the store once
+// the request's context is done.
+func (h *Handler) loadSnapshot(ctx context.Context, date string) (domaingames.TodayResponse, error) {
 	if h.snaps == nil {
 		return domaingames.TodayResponse{}, errors.New("snapshot store not configured")
 	}
-	ctx := context.Background()
 	if err := ctx.Err(); err != nil {
 		return domaingames.TodayResponse{}, err
 	}
-	return h.snaps.LoadGames(date)
+	return h.snaps.LoadGames(ctx, date)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -58,10 +59,9 @@ func TestHealthShuttingDownReturnsServiceUnavailable(t *testing.T) {
 	rr := testutil.ServeRequest(http.HandlerFunc(h.Health), req)
 
 	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
-	var resp map[string]string
-	testutil.DecodeJSON(t, rr, &resp)
-	if resp["error"] != "shutting down" {
-		t.Fatalf("unexpected error %q", resp["error"])
+	resp := decodeError(t, rr)
+	if resp.Error.Code != CodeShuttingDown || resp.Error.Message != "shutting down" {
+		t.Fatalf("unexpected error %+v", resp.Error)
 	}
 }
 func TestGamesByDateRequiresDate(t *testing.T) {
@@ -76,7 +76,7 @@ func TestGamesByDateUsesSnapshot(t *testing.T) {
 	snaps := storeWithResponse(date, testutil.SampleTodayResponse(date, "snapshot-game"))
 
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
 
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-02-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusOK)
@@ -92,6 +92,99 @@ func TestGamesByDateUsesSnapshot(t *testing.T) {
 	}
 }
 
+func TestGamesByDateFiltersPostseason(t *testing.T) {
+	date := "2024-02-01"
+	playoff := testutil.SampleGame("playoff")
+	playoff.Meta.Postseason = true
+	h := newHandler(storeWithGames(date, []domaingames.Game{testutil.SampleGame("regular"), playoff}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	for query, want := range map[string]string{"true": "playoff", "false": "regular"} {
+		rr := testutil.Serve(h, http.MethodGet, "/games?date="+date+"&postseason="+query, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var resp domaingames.TodayResponse
+		testutil.DecodeJSON(t, rr, &resp)
+		if len(resp.Games) != 1 || resp.Games[0].ID != want {
+			t.Fatalf("postseason=%s: expected only %s, got %+v", query, want, resp.Games)
+		}
+	}
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date="+date, nil)
+	var resp domaingames.TodayResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	if len(resp.Games) != 2 {
+		t.Fatalf("expected both games without the filter, got %+v", resp.Games)
+	}
+
+	rr = testutil.Serve(h, http.MethodGet, "/games?date="+date+"&postseason=yes", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+}
+
+func TestGamesByDateSortsGames(t *testing.T) {
+	date := "2024-02-01"
+	game := func(id, start string, kind domaingames.GameStatusKind) domaingames.Game {
+		g := testutil.SampleGame(id)
+		g.StartTime, g.StatusKind = start, kind
+		return g
+	}
+	h := newHandler(storeWithGames(date, []domaingames.Game{
+		game("late", "2024-02-02T03:00:00Z", domaingames.StatusScheduled),
+		game("early", "2024-02-02T00:00:00Z", domaingames.StatusFinal),
+		game("live", "2024-02-02T01:00:00Z", domaingames.StatusInProgress),
+	}), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	for query, want := range map[string][]string{
+		"":                 {"early", "live", "late"},
+		"&sort=startTime":  {"early", "live", "late"},
+		"&sort=-startTime": {"late", "live", "early"},
+		"&sort=status":     {"live", "late", "early"},
+	} {
+		rr := testutil.Serve(h, http.MethodGet, "/games?date="+date+query, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var resp domaingames.TodayResponse
+		testutil.DecodeJSON(t, rr, &resp)
+		var got []string
+		for _, g := range resp.Games {
+			got = append(got, g.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%q: expected %v, got %v", query, want, got)
+		}
+	}
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date="+date+"&sort=score", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+	if body := decodeError(t, rr); body.Error.Code != CodeInvalidQuery {
+		t.Fatalf("expected %s for an unknown sort, got %s", CodeInvalidQuery, body.Error.Code)
+	}
+}
+
+func TestGamesByDateReportsDataAsOfForToday(t *testing.T) {
+	lastSuccess := time.Date(2024, 2, 1, 11, 59, 0, 0, time.UTC)
+	snaps := &teststubs.StubSnapshotStore{Games: map[string]domaingames.TodayResponse{
+		"2024-02-01": testutil.SampleTodayResponse("2024-02-01", "today"),
+		"2024-01-31": testutil.SampleTodayResponse("2024-01-31", "yesterday"),
+	}}
+	h := newHandler(snaps, func() poller.Status { return poller.Status{LastSuccess: lastSuccess} })
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	for _, path := range []string{"/games?date=2024-02-01", "/games?date=2024-02-01&fields=id"} {
+		rr := testutil.Serve(h, http.MethodGet, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var resp domaingames.TodayResponse
+		testutil.DecodeJSON(t, rr, &resp)
+		if resp.DataAsOf == nil || !resp.DataAsOf.Equal(lastSuccess) {
+			t.Fatalf("%s: expected dataAsOf %s, got %v", path, lastSuccess, resp.DataAsOf)
+		}
+	}
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-01-31", nil)
+	if strings.Contains(rr.Body.String(), "dataAsOf") {
+		t.Fatalf("expected no dataAsOf for a date the poller does not refresh, got %s", rr.Body.String())
+	}
+}
+
 func TestGamesByDateWithInvalidDateReturnsBadRequest(t *testing.T) {
 	h := newHandler(nil, nil)
 
@@ -101,7 +194,7 @@ func TestGamesByDateWithInvalidDateReturnsBadRequest(t *testing.T) {
 
 func TestGamesByDateOutOfRangeReturnsBadRequest(t *testing.T) {
 	h := newHandler(nil, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
 
 	// 8 days before - should fail
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-01-06", nil)
@@ -128,15 +221,35 @@ func TestGamesByDateLogsSnapshotErrors(t *testing.T) {
 	snaps := &teststubs.StubSnapshotStore{LoadErr: errors.New("missing snapshot")}
 
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
 
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-02-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusBadGateway)
 }
 
+func TestGamesByDateCanceledRequestSkipsSnapshotLoad(t *testing.T) {
+	date := "2024-02-01"
+	snaps := storeWithResponse(date, testutil.SampleTodayResponse(date, "unread"))
+	h := newHandler(snaps, nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/games?date=2024-02-01", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if got := snaps.Loads.Load(); got != 0 {
+		t.Fatalf("expected no snapshot loads for a canceled request, got %d", got)
+	}
+	if rr.Code == http.StatusOK {
+		t.Fatalf("expected canceled request not to be served")
+	}
+}
+
 func TestGamesByDateSnapshotMissingReturnsBadGateway(t *testing.T) {
 	h := newHandler(nil, nil)
-	h.now = func() time.Time { return time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
 
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-04-01", nil)
 
@@ -145,17 +258,67 @@ func TestGamesByDateSnapshotMissingReturnsBadGateway(t *testing.T) {
 
 func TestGamesByDateWithNilSnapshotsReturnsBadGateway(t *testing.T) {
 	h := newHandler(nil, nil)
-	h.now = func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-06-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusBadGateway)
 }
 
+func TestGamesByDateMissingPastSnapshotReturnsNotFound(t *testing.T) {
+	h := newHandler(snapshots.NewFSStoreWithCache(t.TempDir(), 4), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-06-01", nil)
+	testutil.AssertStatus(t, rr, http.StatusNotFound)
+	if resp := decodeError(t, rr); resp.Error.Code != CodeSnapshotNotFound || resp.Error.Message != "no snapshot for date" {
+		t.Fatalf("unexpected error %+v", resp.Error)
+	}
+
+	// Today's snapshot may still be on its way, so its absence stays a 502.
+	rr = testutil.Serve(h, http.MethodGet, "/games?date=2024-06-03", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadGateway)
+}
+
+func TestGamesByDatePrefersZoneSnapshotForTZ(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	writer := snapshots.NewWriter(t.TempDir(), 7)
+	writer.SetClock(testutil.NewFakeClock(now))
+	if err := writer.WriteGamesSnapshot("2024-06-03", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "service"}}}); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if err := writer.WriteGamesSnapshotForZone("2024-06-03", "Asia/Tokyo", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "tokyo"}}}); err != nil {
+		t.Fatalf("write zone snapshot: %v", err)
+	}
+	h := newHandler(snapshots.NewFSStoreWithCache(writer.BasePath(), 4), nil)
+	h.clock = testutil.NewFakeClock(now)
+
+	cases := map[string]string{
+		"/games?date=2024-06-03":                 "service",
+		"/games?date=2024-06-03&tz=Asia/Tokyo":   "tokyo",
+		"/games?date=2024-06-03&tz=Europe/Paris": "service", // no zone snapshot
+		"/games?date=2024-06-03&tz=UTC":          "service", // the service zone
+		"/games?date=2024-06-03&tz=Mars/Olympus": "service", // unknown zone falls back
+	}
+	for path, want := range cases {
+		rr := testutil.Serve(h, http.MethodGet, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusOK)
+		var got struct {
+			Games []struct {
+				ID string `json:"id"`
+			} `json:"games"`
+		}
+		testutil.DecodeJSON(t, rr, &got)
+		if len(got.Games) != 1 || got.Games[0].ID != want {
+			t.Fatalf("%s: expected the %s snapshot, got %+v", path, want, got.Games)
+		}
+	}
+}
+
 func TestGamesByDateWithLoggerLogsSnapshot(t *testing.T) {
 	logger, buf := testutil.NewBufferLogger()
 	date := "2024-07-01"
 	snaps := storeWithResponse(date, testutil.SampleTodayResponse(date, "logged-snap"))
 	h := NewHandler(snaps, logger, nil, nil)
-	h.now = func() time.Time { return time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-07-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusOK)
 	if buf.Len() == 0 {
@@ -169,9 +332,9 @@ func TestGameByID(t *testing.T) {
 	game.StartTime = time.Date(2024, 1, 1, 15, 30, 0, 0, time.UTC).Format(time.RFC3339)
 	snaps := storeWithGames(date, []domaingames.Game{game})
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
 
-	rr := testutil.Serve(http.HandlerFunc(h.GameByID), http.MethodGet, "/games/id-1", nil)
+	rr := testutil.Serve(h, http.MethodGet, "/games/id-1", nil)
 
 	testutil.AssertStatus(t, rr, http.StatusOK)
 
@@ -186,7 +349,7 @@ func TestGameByID(t *testing.T) {
 func TestGameByIDInvalid(t *testing.T) {
 	h := newHandler(nil, nil)
 
-	rr := testutil.Serve(http.HandlerFunc(h.GameByID), http.MethodGet, "/games", nil)
+	rr := testutil.Serve(h, http.MethodGet, "/games/", nil)
 
 	testutil.AssertStatus(t, rr, http.StatusBadRequest)
 }
@@ -195,9 +358,9 @@ func TestGameByIDNotFound(t *testing.T) {
 	date := "2024-01-01"
 	snaps := storeWithGames(date, nil)
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
 
-	rr := testutil.Serve(http.HandlerFunc(h.GameByID), http.MethodGet, "/games/unknown", nil)
+	rr := testutil.Serve(h, http.MethodGet, "/games/unknown", nil)
 
 	testutil.AssertStatus(t, rr, http.StatusNotFound)
 }
@@ -206,7 +369,7 @@ func TestGameByIDInvalidCharacters(t *testing.T) {
 	h := newHandler(nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/games/bad%20id", nil)
-	rr := testutil.ServeRequest(http.HandlerFunc(h.GameByID), req)
+	rr := testutil.ServeRequest(h, req)
 
 	testutil.AssertStatus(t, rr, http.StatusBadRequest)
 }
@@ -215,7 +378,7 @@ func TestGameByIDWithEncodedSlash(t *testing.T) {
 	h := newHandler(nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/games/foo%2Fbar", nil)
-	rr := testutil.ServeRequest(http.HandlerFunc(h.GameByID), req)
+	rr := testutil.ServeRequest(h, req)
 
 	testutil.AssertStatus(t, rr, http.StatusBadRequest)
 }
@@ -231,7 +394,7 @@ func TestGameByIDUnescapeError(t *testing.T) {
 		},
 		Header: make(http.Header),
 	}
-	rr := testutil.ServeRequest(http.HandlerFunc(h.GameByID), req)
+	rr := testutil.ServeRequest(h, req)
 
 	testutil.AssertStatus(t, rr, http.StatusBadRequest)
 }
@@ -244,7 +407,7 @@ func TestGameByIDRejectsGamesKeyword(t *testing.T) {
 
 func TestGameByIDNilSnapshotStore(t *testing.T) {
 	h := NewHandler(nil, nil, nil, nil)
-	rr := testutil.Serve(http.HandlerFunc(h.GameByID), http.MethodGet, "/games/id-1", nil)
+	rr := testutil.Serve(h, http.MethodGet, "/games/id-1", nil)
 	testutil.AssertStatus(t, rr, http.StatusBadGateway)
 }
 
@@ -252,7 +415,7 @@ func TestGamesByDateReturnsSnapshot(t *testing.T) {
 	date := "2024-03-01"
 	snaps := storeWithResponse(date, testutil.SampleTodayResponse(date, "snap-id"))
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
 
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-03-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusOK)
@@ -270,18 +433,23 @@ func TestMethodNotAllowedHandlers(t *testing.T) {
 	tests := []struct {
 		name string
 		path string
-		fn   func(w http.ResponseWriter, r *http.Request)
 	}{
-		{"health", "/health", h.Health},
-		{"ready", "/ready", h.Ready},
-		{"gamesByDate", "/games?date=2024-01-01", h.GamesToday},
-		{"gameByID", "/games/id", h.GameByID},
+		{"health", "/health"},
+		{"ready", "/ready"},
+		{"gamesByDate", "/games?date=2024-01-01"},
+		{"gameByID", "/games/id"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			rr := testutil.Serve(http.HandlerFunc(tt.fn), http.MethodPost, tt.path, nil)
+			rr := testutil.Serve(h, http.MethodPost, tt.path, nil)
 			testutil.AssertStatus(t, rr, http.StatusMethodNotAllowed)
+			if allow := rr.Header().Get("Allow"); allow != "GET, HEAD" {
+				t.Fatalf("expected Allow: GET, HEAD, got %q", allow)
+			}
+			if decodeError(t, rr).Error.Code != CodeMethodNotAllowed {
+				t.Fatalf("expected METHOD_NOT_ALLOWED envelope")
+			}
 		})
 	}
 }
@@ -290,10 +458,10 @@ func TestRequestIDPropagatesThroughMiddleware(t *testing.T) {
 	date := "2024-01-01"
 	snaps := storeWithGames(date, nil)
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/games/", h.GameByID)
+	mux.HandleFunc("/games/{id}", h.GameByID)
 	wrapped := middleware.LoggingMiddleware(nil, nil, mux)
 
 	req := httptest.NewRequest(http.MethodGet, "/games/missing", nil)
@@ -302,13 +470,9 @@ func TestRequestIDPropagatesThroughMiddleware(t *testing.T) {
 
 	testutil.AssertStatus(t, rr, http.StatusNotFound)
 
-	var resp map[string]string
-	testutil.DecodeJSON(t, rr, &resp)
-	if resp["requestId"] != "abc123" {
-		t.Fatalf("expected requestId propagated, got %s", resp["requestId"])
-	}
-	if resp["error"] == "" {
-		t.Fatalf("expected error field in response")
+	resp := decodeError(t, rr)
+	if resp.RequestID != "abc123" {
+		t.Fatalf("expected requestId propagated, got %s", resp.RequestID)
 	}
 }
 
@@ -351,13 +515,45 @@ func TestReadyNotReadyUsesLastError(t *testing.T) {
 	rr := testutil.Serve(http.HandlerFunc(h.Ready), http.MethodGet, "/ready", nil)
 
 	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
+	resp := decodeError(t, rr)
+	if resp.Error.Code != CodeNotReady || resp.Error.Message != "upstream down" {
+		t.Fatalf("expected last error propagated, got %+v", resp.Error)
+	}
+}
+
+func TestReadyBeforeFirstPollUsesTodaysSnapshot(t *testing.T) {
+	date := "2024-03-01"
+	h := newHandler(storeWithGames(date, nil), func() poller.Status { return poller.Status{} })
+	h.clock = testutil.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(http.HandlerFunc(h.Ready), http.MethodGet, "/ready", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
 	var resp map[string]string
 	testutil.DecodeJSON(t, rr, &resp)
-	if resp["error"] != "upstream down" {
-		t.Fatalf("expected last error propagated, got %s", resp["error"])
+	if resp["status"] != "ready" || resp["source"] != "snapshot" {
+		t.Fatalf("expected ready from snapshot, got %v", resp)
 	}
 }
 
+func TestReadyWithoutPollOrSnapshotIsUnavailable(t *testing.T) {
+	h := newHandler(storeWithGames("2024-02-29", nil), func() poller.Status { return poller.Status{} })
+	h.clock = testutil.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(http.HandlerFunc(h.Ready), http.MethodGet, "/ready", nil)
+	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
+}
+
+func TestReadyFailingPollerIgnoresSnapshot(t *testing.T) {
+	date := "2024-03-01"
+	h := newHandler(storeWithGames(date, nil), func() poller.Status {
+		return poller.Status{LastSuccess: time.Now().Add(-time.Hour), ConsecutiveFailures: 5}
+	})
+	h.clock = testutil.NewFakeClock(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(http.HandlerFunc(h.Ready), http.MethodGet, "/ready", nil)
+	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
+}
+
 func TestServeHTTPNotFound(t *testing.T) {
 	h := newHandler(nil, nil)
 	rr := testutil.Serve(h, http.MethodGet, "/unknown", nil)
@@ -371,7 +567,7 @@ func TestServeHTTPRoutes(t *testing.T) {
 	game.StartTime = time.Now().Format(time.RFC3339)
 	snaps := storeWithGames(date, []domaingames.Game{game})
 	h := NewHandler(snaps, nil, func() poller.Status { return poller.Status{LastSuccess: time.Now()} }, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 
 	tests := []struct {
 		path   string
@@ -393,7 +589,7 @@ func TestServeHTTPRoutingAndMethods(t *testing.T) {
 	date := "2024-01-01"
 	snaps := storeWithGames(date, nil)
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
 	tests := []struct {
 		method string
 		path   string
@@ -423,7 +619,7 @@ func TestGamesByDateUpstreamErrorsReturnBadGateway(t *testing.T) {
 	snaps := &teststubs.StubSnapshotStore{LoadErr: errors.New("boom")}
 
 	h := newHandler(snaps, nil)
-	h.now = func() time.Time { return time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC) }
+	h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
 
 	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-02-01", nil)
 	testutil.AssertStatus(t, rr, http.StatusBadGateway)
@@ -478,7 +674,7 @@ func BenchmarkGamesByDate(b *testing.B) {
 		b.Fatalf("failed to write snapshot: %v", err)
 	}
 	h := NewHandler(snapshots.NewFSStore(basePath), nil, nil, nil)
-	h.now = func() time.Time { return now }
+	h.clock = testutil.NewFakeClock(now)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -511,12 +707,13 @@ func BenchmarkGameByID(b *testing.B) {
 		b.Fatalf("failed to write snapshot: %v", err)
 	}
 	h := NewHandler(snapshots.NewFSStore(basePath), nil, nil, nil)
-	h.now = func() time.Time { return now }
+	h.clock = testutil.NewFakeClock(now)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		rr := httptest.NewRecorder()
 		req := httptest.NewRequest(http.MethodGet, "/games/game-1", nil)
+		req.SetPathValue("id", "game-1")
 		h.GameByID(rr, req)
 	}
 }
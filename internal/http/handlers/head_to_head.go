@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	nethttp "net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// Matchup is one game between the two teams.
+type Matchup struct {
+	Date       string                     `json:"date"`
+	GameID     string                     `json:"gameId"`
+	HomeTeam   teams.Team                 `json:"homeTeam"`
+	AwayTeam   teams.Team                 `json:"awayTeam"`
+	Score      domaingames.Score          `json:"score"`
+	StatusKind domaingames.GameStatusKind `json:"statusKind"`
+	Season     string                     `json:"season"`
+	WinnerID   string                     `json:"winnerId,omitempty"` // set for FINAL games
+}
+
+// HeadToHeadSummary is the record of team against opponent over FINAL matchups.
+type HeadToHeadSummary struct {
+	Played int `json:"played"`
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+}
+
+// HeadToHeadResponse is the payload returned by /teams/{id}/vs/{otherId}.
+type HeadToHeadResponse struct {
+	TeamID     string            `json:"teamId"`
+	OpponentID string            `json:"opponentId"`
+	Season     string            `json:"season,omitempty"`
+	Matchups   []Matchup         `json:"matchups"`
+	Summary    HeadToHeadSummary `json:"summary"`
+}
+
+// HeadToHeadHandler serves GET /teams/{id}/vs/{otherId} by scanning game snapshots
+// one date at a time.
+type HeadToHeadHandler struct {
+	writer *snapshots.Writer
+	snaps  snapshots.Store
+	logger *slog.Logger
+	clock  clock.Clock
+}
+
+// NewHeadToHeadHandler constructs a HeadToHeadHandler reading snapshot dates from
+// writer's manifest and games from snaps.
+func NewHeadToHeadHandler(writer *snapshots.Writer, snaps snapshots.Store, logger *slog.Logger, clk clock.Clock) *HeadToHeadHandler {
+	return &HeadToHeadHandler{
+		writer: writer,
+		snaps:  snaps,
+		logger: logger,
+		clock:  clock.OrReal(clk),
+	}
+}
+
+func (h *HeadToHeadHandler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	teamID, opponentID, ok := parseHeadToHeadPath(r.URL.EscapedPath())
+	if !ok {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidTeamID, "invalid team ids (expected /teams/{id}/vs/{otherId})", h.logger)
+		return
+	}
+	if teamID == opponentID {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidTeamID, "team ids must differ", h.logger)
+		return
+	}
+	if !validateQuery(w, r, "/teams/{id}/vs/{otherId}", h.logger) {
+		return
+	}
+	if h.writer == nil || h.snaps == nil {
+		writeError(w, r, nethttp.StatusBadGateway, CodeStorageUnavailable, "snapshot store not configured", h.logger)
+		return
+	}
+	m, err := h.writer.Manifest()
+	if err != nil {
+		writeError(w, r, nethttp.StatusBadGateway, CodeStorageUnavailable, "snapshot manifest unavailable", h.logger)
+		return
+	}
+
+	season := strings.TrimSpace(r.URL.Query().Get("season"))
+	dates := m.Games.Dates
+	if season == "" {
+		// Without a season only the retention window is scanned; pinned history
+		// outside it is reachable by asking for its season.
+		dates = datesWithin(dates, h.clock.Now(), h.writer.Location(), m.Retention.GamesDays)
+	}
+	resp, err := h.scan(r.Context(), dates, teamID, opponentID, season)
+	if err != nil {
+		writeError(w, r, nethttp.StatusGatewayTimeout, CodeRequestTimeout, "request timed out", h.logger)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, resp, h.logger)
+}
+
+// scan loads one snapshot at a time so memory stays bounded by a single date.
+// It stops with ctx's error once ctx is done.
+func (h *HeadToHeadHandler) scan(ctx context.Context, dates []string, teamID, opponentID, season string) (HeadToHeadResponse, error) {
+	resp := HeadToHeadResponse{TeamID: teamID, OpponentID: opponentID, Season: season, Matchups: []Matchup{}}
+	sorted := append([]string(nil), dates...)
+	sort.Strings(sorted)
+	seen := make(map[string]int)
+	for _, date := range sorted {
+		snap, err := h.snaps.LoadGames(ctx, date)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return HeadToHeadResponse{}, ctxErr
+		}
+		if err != nil {
+			continue
+		}
+		for _, g := range snap.Games {
+			if !isMatchup(g, teamID, opponentID) || (season != "" && g.Meta.Season != season) {
+				continue
+			}
+			matchup := newMatchup(date, g)
+			// A game can appear in adjacent snapshots; keep the latest copy.
+			if i, ok := seen[g.ID]; ok {
+				resp.Matchups[i] = matchup
+				continue
+			}
+			seen[g.ID] = len(resp.Matchups)
+			resp.Matchups = append(resp.Matchups, matchup)
+		}
+	}
+	for _, m := range resp.Matchups {
+		switch m.WinnerID {
+		case "":
+		case teamID:
+			resp.Summary.Wins++
+			resp.Summary.Played++
+		default:
+			resp.Summary.Losses++
+			resp.Summary.Played++
+		}
+	}
+	return resp, nil
+}
+
+func isMatchup(g domaingames.Game, a, b string) bool {
+	return (g.HomeTeam.ID == a && g.AwayTeam.ID == b) || (g.HomeTeam.ID == b && g.AwayTeam.ID == a)
+}
+
+func newMatchup(date string, g domaingames.Game) Matchup {
+	m := Matchup{
+		Date:       date,
+		GameID:     g.ID,
+		HomeTeam:   g.HomeTeam,
+		AwayTeam:   g.AwayTeam,
+		Score:      g.Score,
+		StatusKind: g.StatusKind,
+		Season:     g.Meta.Season,
+	}
+	if g.StatusKind == domaingames.StatusFinal && g.Score.Home != g.Score.Away {
+		m.WinnerID = g.HomeTeam.ID
+		if g.Score.Away > g.Score.Home {
+			m.WinnerID = g.AwayTeam.ID
+		}
+	}
+	return m
+}
+
+// parseHeadToHeadPath extracts both ids from /teams/{id}/vs/{otherId}.
+func parseHeadToHeadPath(escaped string) (string, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(escaped, "/teams/"), "/")
+	if len(parts) != 3 || parts[1] != "vs" {
+		return "", "", false
+	}
+	a, errA := url.PathUnescape(parts[0])
+	b, errB := url.PathUnescape(parts[2])
+	if errA != nil || errB != nil || !requestutil.ValidID(a) || !requestutil.ValidID(b) {
+		return "", "", false
+	}
+	return a, b, true
+}
+
+// datesWithin keeps dates no older than days before today in loc, matching the
+// writer's retention pruning.
+func datesWithin(dates []string, now time.Time, loc *time.Location, days int) []string {
+	if days <= 0 {
+		return dates
+	}
+	cutoff := timeutil.DateOffset(now, loc, -days)
+	var kept []string
+	for _, d := range dates {
+		if d >= cutoff {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
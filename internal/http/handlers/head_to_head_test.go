@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func newHeadToHeadFixture(t *testing.T) *HeadToHeadHandler {
+	t.Helper()
+	writer := snapshots.NewWriter(t.TempDir(), 10)
+	write := func(date string, games ...domaingames.Game) {
+		if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, games)); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+	}
+	upcoming := finalGame("m3", lakers, celtics, 0, 0, "2023-2024")
+	upcoming.StatusKind = domaingames.StatusScheduled
+	write(standingsDate(-3),
+		finalGame("m1", celtics, lakers, 110, 100, "2023-2024"),
+		finalGame("x1", knicks, bucks, 90, 80, "2023-2024"),
+	)
+	write(standingsDate(-2), finalGame("x2", celtics, knicks, 100, 101, "2023-2024"))
+	write(standingsDate(-1),
+		finalGame("m2", lakers, celtics, 120, 115, "2023-2024"),
+		finalGame("x3", lakers, bucks, 100, 90, "2023-2024"),
+	)
+	write(standingsDate(0), upcoming)
+	return NewHeadToHeadHandler(writer, snapshots.NewFSStore(writer.BasePath()), nil, nil)
+}
+
+func TestHeadToHeadListsMatchupsWithRecord(t *testing.T) {
+	h := newHeadToHeadFixture(t)
+	rr := testutil.Serve(h, http.MethodGet, "/teams/bos/vs/lal", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+
+	var resp HeadToHeadResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	if resp.TeamID != "bos" || resp.OpponentID != "lal" {
+		t.Fatalf("unexpected ids %+v", resp)
+	}
+	if len(resp.Matchups) != 3 {
+		t.Fatalf("expected 3 matchups, got %+v", resp.Matchups)
+	}
+	first, second, third := resp.Matchups[0], resp.Matchups[1], resp.Matchups[2]
+	if first.GameID != "m1" || first.Date != standingsDate(-3) || first.WinnerID != "bos" {
+		t.Fatalf("unexpected first matchup %+v", first)
+	}
+	if second.GameID != "m2" || second.WinnerID != "lal" || second.Score.Home != 120 {
+		t.Fatalf("unexpected second matchup %+v", second)
+	}
+	if third.GameID != "m3" || third.WinnerID != "" {
+		t.Fatalf("expected scheduled matchup without winner, got %+v", third)
+	}
+	if resp.Summary != (HeadToHeadSummary{Played: 2, Wins: 1, Losses: 1}) {
+		t.Fatalf("unexpected summary %+v", resp.Summary)
+	}
+}
+
+func TestHeadToHeadSeasonFilter(t *testing.T) {
+	h := newHeadToHeadFixture(t)
+	rr := testutil.Serve(h, http.MethodGet, "/teams/lal/vs/bos?season=2022-2023", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var resp HeadToHeadResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	if len(resp.Matchups) != 0 || resp.Season != "2022-2023" {
+		t.Fatalf("expected no matchups for other season, got %+v", resp)
+	}
+}
+
+func TestHeadToHeadValidatesIDs(t *testing.T) {
+	h := newHeadToHeadFixture(t)
+	for _, path := range []string{
+		"/teams/bos/vs/bos",
+		"/teams/bos/vs/",
+		"/teams/bos/lal",
+		"/teams/bos/vs/lal/extra",
+		"/teams/b%20os/vs/lal",
+		"/teams/bos/vs/lal?limit=5",
+	} {
+		rr := testutil.Serve(h, http.MethodGet, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusBadRequest)
+	}
+	rr := testutil.Serve(NewHeadToHeadHandler(nil, nil, nil, nil), http.MethodGet, "/teams/bos/vs/lal", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadGateway)
+}
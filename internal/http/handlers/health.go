@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	nethttp "net/http"
+	"os"
+)
+
+// Component health states, worst last.
+const (
+	HealthOK       = "ok"
+	HealthDegraded = "degraded"
+	HealthDown     = "down"
+	// HealthDraining marks a service that is shutting down but still
+	// finishing in-flight requests.
+	HealthDraining = "draining"
+)
+
+// HealthReport is the /health?verbose=true body. Status is "down" (and the
+// response 503) only when a hard dependency fails; a soft one such as the
+// provider makes it "degraded" while still answering 200.
+type HealthReport struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth is one dependency's check result.
+type ComponentHealth struct {
+	Status string `json:"status"`
+	Hard   bool   `json:"hard"` // failing it takes the service down
+	Error  string `json:"error,omitempty"`
+	// ConsecutiveFailures is set for the provider, from the poller.
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+}
+
+// SetDrainCheck makes /ready answer 503 and /health report "draining" while
+// fn returns true.
+func (h *Handler) SetDrainCheck(fn func() bool) {
+	if h == nil {
+		return
+	}
+	h.drainCheck = fn
+}
+
+func (h *Handler) draining() bool {
+	return h.drainCheck != nil && h.drainCheck()
+}
+
+// SetSnapshotDir makes verbose health checks confirm dir is writable.
+func (h *Handler) SetSnapshotDir(dir string) {
+	if h == nil {
+		return
+	}
+	h.snapshotDir = dir
+}
+
+// healthReport runs the cheap dependency checks behind /health?verbose=true.
+func (h *Handler) healthReport() HealthReport {
+	report := HealthReport{Status: HealthOK, Components: map[string]ComponentHealth{}}
+	add := func(name string, c ComponentHealth) {
+		report.Components[name] = c
+		switch {
+		case c.Status == HealthOK:
+		case c.Hard:
+			report.Status = HealthDown
+		case report.Status == HealthOK:
+			report.Status = HealthDegraded
+		}
+	}
+
+	store := ComponentHealth{Status: HealthOK, Hard: true}
+	if h.snaps == nil {
+		store.Status, store.Error = HealthDown, "snapshot store not configured"
+	}
+	add("store", store)
+
+	if h.snapshotDir != "" {
+		dir := ComponentHealth{Status: HealthOK, Hard: true}
+		if err := checkWritableDir(h.snapshotDir); err != nil {
+			dir.Status, dir.Error = HealthDown, err.Error()
+		}
+		add("snapshotDir", dir)
+	}
+
+	if h.statusFn != nil {
+		st := h.statusFn()
+		provider := ComponentHealth{Status: HealthOK, ConsecutiveFailures: st.ConsecutiveFailures}
+		if !st.IsReady() {
+			provider.Status, provider.Error = HealthDegraded, st.LastError
+			if provider.Error == "" {
+				provider.Error = "no successful fetch yet"
+			}
+		}
+		add("provider", provider)
+	}
+	if report.Status != HealthDown && h.draining() {
+		report.Status = HealthDraining
+	}
+	return report
+}
+
+// checkWritableDir creates and removes a temp file in dir. A missing dir is
+// created, as the writer would on its first snapshot.
+func checkWritableDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".health-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	_ = f.Close()
+	return os.Remove(name)
+}
+
+func (h *Handler) writeHealthReport(w nethttp.ResponseWriter) {
+	report := h.healthReport()
+	status := nethttp.StatusOK
+	if report.Status == HealthDown {
+		status = nethttp.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report, h.logger)
+}
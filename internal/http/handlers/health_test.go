@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func verboseHealth(t *testing.T, h *Handler) (int, HealthReport) {
+	t.Helper()
+	rr := testutil.Serve(h, http.MethodGet, "/health?verbose=true", nil)
+	var report HealthReport
+	testutil.DecodeJSON(t, rr, &report)
+	return rr.Code, report
+}
+
+func healthyPoller() poller.Status {
+	return poller.Status{LastSuccess: time.Now()}
+}
+
+func TestVerboseHealthAllHealthy(t *testing.T) {
+	dir := t.TempDir()
+	h := newHandler(&teststubs.StubSnapshotStore{}, healthyPoller)
+	h.SetSnapshotDir(dir)
+
+	code, report := verboseHealth(t, h)
+	if code != http.StatusOK || report.Status != HealthOK {
+		t.Fatalf("expected 200 ok, got %d %+v", code, report)
+	}
+	for _, name := range []string{"store", "snapshotDir", "provider"} {
+		if c, ok := report.Components[name]; !ok || c.Status != HealthOK {
+			t.Fatalf("expected %s ok, got %+v", name, report.Components)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("expected probe file removed, found %d entries", len(entries))
+	}
+}
+
+func TestVerboseHealthUnwritableDirIsDown(t *testing.T) {
+	// A regular file where the directory should be fails even as root.
+	blocked := filepath.Join(t.TempDir(), "snapshots")
+	if err := os.WriteFile(blocked, []byte("x"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	h := newHandler(&teststubs.StubSnapshotStore{}, healthyPoller)
+	h.SetSnapshotDir(blocked)
+
+	code, report := verboseHealth(t, h)
+	if code != http.StatusServiceUnavailable || report.Status != HealthDown {
+		t.Fatalf("expected 503 down, got %d %+v", code, report)
+	}
+	if c := report.Components["snapshotDir"]; c.Status != HealthDown || !c.Hard || c.Error == "" {
+		t.Fatalf("expected snapshotDir down with error, got %+v", c)
+	}
+}
+
+func TestVerboseHealthDegradedProviderStays200(t *testing.T) {
+	h := newHandler(&teststubs.StubSnapshotStore{}, func() poller.Status {
+		return poller.Status{LastSuccess: time.Now().Add(-time.Hour), ConsecutiveFailures: 30, LastError: "upstream 500"}
+	})
+
+	code, report := verboseHealth(t, h)
+	if code != http.StatusOK || report.Status != HealthDegraded {
+		t.Fatalf("expected 200 degraded, got %d %+v", code, report)
+	}
+	c := report.Components["provider"]
+	if c.Status != HealthDegraded || c.Hard || c.Error != "upstream 500" || c.ConsecutiveFailures != 30 {
+		t.Fatalf("unexpected provider health %+v", c)
+	}
+	if _, ok := report.Components["snapshotDir"]; ok {
+		t.Fatalf("expected no dir check without a configured dir")
+	}
+}
+
+func TestVerboseHealthMissingStoreIsDown(t *testing.T) {
+	code, report := verboseHealth(t, newHandler(nil, nil))
+	if code != http.StatusServiceUnavailable || report.Components["store"].Status != HealthDown {
+		t.Fatalf("expected 503 with store down, got %d %+v", code, report)
+	}
+}
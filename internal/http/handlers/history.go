@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	nethttp "net/http"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// maxHistoryPerGame bounds each game's change log; the oldest entries are dropped first.
+const maxHistoryPerGame = 50
+
+// History fields reported in GameChange.Field.
+const (
+	HistoryFieldStatus    = "status"
+	HistoryFieldHomeScore = "score.home"
+	HistoryFieldAwayScore = "score.away"
+)
+
+// GameChange is one recorded transition of a game field. Old is null the first
+// time a game is seen.
+type GameChange struct {
+	At    time.Time `json:"at"`
+	Field string    `json:"field"`
+	Old   any       `json:"old"`
+	New   any       `json:"new"`
+}
+
+// GameHistoryResponse is the payload returned by /games/{id}/history.
+type GameHistoryResponse struct {
+	GameID  string       `json:"gameId"`
+	Date    string       `json:"date"`
+	Changes []GameChange `json:"changes"`
+}
+
+// HistoryHandler serves GET /games/{id}/history: the status and score changes the
+// poller has seen for today's games, oldest first. The log lives in memory only
+// and starts over when the service date rolls over.
+type HistoryHandler struct {
+	snaps  snapshots.Store
+	logger *slog.Logger
+	loc    *time.Location
+	clock  clock.Clock
+
+	mu    sync.Mutex
+	date  string // service date the log belongs to
+	games map[string][]GameChange
+}
+
+// NewHistoryHandler constructs a HistoryHandler; snaps is consulted only to tell
+// a known game without changes from an unknown one.
+func NewHistoryHandler(snaps snapshots.Store, logger *slog.Logger, loc *time.Location, clk clock.Clock) *HistoryHandler {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &HistoryHandler{
+		snaps:  snaps,
+		logger: logger,
+		loc:    loc,
+		clock:  clock.OrReal(clk),
+		games:  make(map[string][]GameChange),
+	}
+}
+
+// Observe records the fields an event changed. Suitable for Poller.OnChange.
+func (h *HistoryHandler) Observe(ev poller.GameChangeEvent) {
+	var changes []GameChange
+	switch ev.Kind {
+	case poller.ChangeAdded:
+		changes = []GameChange{{At: ev.At, Field: HistoryFieldStatus, New: ev.NewStatus}}
+	case poller.ChangeStatus:
+		changes = []GameChange{{At: ev.At, Field: HistoryFieldStatus, Old: ev.OldStatus, New: ev.NewStatus}}
+	case poller.ChangeScore:
+		if ev.OldScore.Home != ev.Score.Home {
+			changes = append(changes, GameChange{At: ev.At, Field: HistoryFieldHomeScore, Old: ev.OldScore.Home, New: ev.Score.Home})
+		}
+		if ev.OldScore.Away != ev.Score.Away {
+			changes = append(changes, GameChange{At: ev.At, Field: HistoryFieldAwayScore, Old: ev.OldScore.Away, New: ev.Score.Away})
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollover(timeutil.DateIn(ev.At, h.loc))
+	log := append(h.games[ev.GameID], changes...)
+	if len(log) > maxHistoryPerGame {
+		log = append([]GameChange(nil), log[len(log)-maxHistoryPerGame:]...)
+	}
+	h.games[ev.GameID] = log
+}
+
+// rollover drops the log when date starts a new service day. Callers hold h.mu.
+func (h *HistoryHandler) rollover(date string) {
+	if date == h.date {
+		return
+	}
+	h.date = date
+	h.games = make(map[string][]GameChange)
+}
+
+func (h *HistoryHandler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	id, ok := requestutil.PathID(r, "id")
+	if !ok {
+		writeError(w, r, nethttp.StatusBadRequest, CodeInvalidGameID, "invalid game id", h.logger)
+		return
+	}
+	if !validateQuery(w, r, "/games/{id}/history", h.logger) {
+		return
+	}
+
+	today := timeutil.DateIn(h.clock.Now(), h.loc)
+	h.mu.Lock()
+	h.rollover(today)
+	changes := append([]GameChange{}, h.games[id]...)
+	h.mu.Unlock()
+
+	if len(changes) == 0 && !h.knownGame(r.Context(), today, id) {
+		writeError(w, r, nethttp.StatusNotFound, CodeGameNotFound, "game not found", h.logger)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, GameHistoryResponse{GameID: id, Date: today, Changes: changes}, loggerFromContext(r, h.logger))
+}
+
+func (h *HistoryHandler) knownGame(ctx context.Context, date, id string) bool {
+	if h.snaps == nil {
+		return false
+	}
+	_, ok := h.snaps.FindGameByID(ctx, date, id)
+	return ok
+}
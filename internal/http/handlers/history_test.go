@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func historyMux(h *HistoryHandler) http.Handler {
+	mux := NewMux(nil)
+	mux.Handle("GET /games/{id}/history", h)
+	return mux
+}
+
+func getHistory(t *testing.T, h *HistoryHandler, id string) GameHistoryResponse {
+	t.Helper()
+	rr := testutil.Serve(historyMux(h), http.MethodGet, "/games/"+id+"/history", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var resp GameHistoryResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	return resp
+}
+
+func TestHistoryRecordsTransitionsAcrossPollCycles(t *testing.T) {
+	start := time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)
+	clk := testutil.NewFakeClock(start)
+	h := NewHistoryHandler(nil, nil, time.UTC, clk)
+
+	// Four poll cycles: first sighting, tip-off, a home basket, then both teams score.
+	cycles := []poller.GameChangeEvent{
+		{Kind: poller.ChangeAdded, GameID: "g1", NewStatus: domaingames.StatusScheduled},
+		{Kind: poller.ChangeStatus, GameID: "g1", OldStatus: domaingames.StatusScheduled, NewStatus: domaingames.StatusInProgress},
+		{Kind: poller.ChangeScore, GameID: "g1", Score: domaingames.Score{Home: 2}},
+		{Kind: poller.ChangeScore, GameID: "g1", OldScore: domaingames.Score{Home: 2}, Score: domaingames.Score{Home: 4, Away: 3}},
+	}
+	for i, ev := range cycles {
+		ev.At = start.Add(time.Duration(i) * time.Minute)
+		h.Observe(ev)
+	}
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeAdded, GameID: "g2", NewStatus: domaingames.StatusScheduled, At: start})
+
+	resp := getHistory(t, h, "g1")
+	if resp.GameID != "g1" || resp.Date != "2024-01-15" {
+		t.Fatalf("unexpected history header %+v", resp)
+	}
+	want := []struct {
+		field    string
+		old, new any
+	}{
+		{HistoryFieldStatus, nil, "SCHEDULED"},
+		{HistoryFieldStatus, "SCHEDULED", "IN_PROGRESS"},
+		{HistoryFieldHomeScore, 0.0, 2.0},
+		{HistoryFieldHomeScore, 2.0, 4.0},
+		{HistoryFieldAwayScore, 0.0, 3.0},
+	}
+	if len(resp.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), resp.Changes)
+	}
+	for i, w := range want {
+		got := resp.Changes[i]
+		if got.Field != w.field || got.Old != w.old || got.New != w.new {
+			t.Fatalf("change %d: expected %s %v->%v, got %+v", i, w.field, w.old, w.new, got)
+		}
+	}
+	if !resp.Changes[4].At.Equal(start.Add(3 * time.Minute)) {
+		t.Fatalf("expected change timestamps from the poll, got %s", resp.Changes[4].At)
+	}
+	if got := getHistory(t, h, "g2"); len(got.Changes) != 1 {
+		t.Fatalf("expected games logged separately, got %+v", got.Changes)
+	}
+}
+
+func TestHistoryBoundedPerGame(t *testing.T) {
+	at := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	h := NewHistoryHandler(nil, nil, time.UTC, testutil.NewFakeClock(at))
+	for i := 0; i < maxHistoryPerGame+10; i++ {
+		h.Observe(poller.GameChangeEvent{
+			Kind: poller.ChangeScore, GameID: "g1", At: at,
+			OldScore: domaingames.Score{Home: i}, Score: domaingames.Score{Home: i + 1},
+		})
+	}
+	resp := getHistory(t, h, "g1")
+	if len(resp.Changes) != maxHistoryPerGame {
+		t.Fatalf("expected %d changes kept, got %d", maxHistoryPerGame, len(resp.Changes))
+	}
+	if resp.Changes[0].Old != 10.0 || resp.Changes[maxHistoryPerGame-1].New != float64(maxHistoryPerGame+10) {
+		t.Fatalf("expected the oldest changes dropped, got first=%+v last=%+v", resp.Changes[0], resp.Changes[maxHistoryPerGame-1])
+	}
+}
+
+func TestHistoryClearedOnDateRollover(t *testing.T) {
+	start := time.Date(2024, 1, 15, 23, 30, 0, 0, time.UTC)
+	clk := testutil.NewFakeClock(start)
+	h := NewHistoryHandler(storeWithGames("2024-01-16", []domaingames.Game{testutil.SampleGame("g1")}), nil, time.UTC, clk)
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeAdded, GameID: "g1", NewStatus: domaingames.StatusScheduled, At: start})
+	if got := getHistory(t, h, "g1"); len(got.Changes) != 1 {
+		t.Fatalf("expected today's change, got %+v", got.Changes)
+	}
+
+	clk.Advance(time.Hour)
+	resp := getHistory(t, h, "g1")
+	if resp.Date != "2024-01-16" || len(resp.Changes) != 0 {
+		t.Fatalf("expected an empty log for the new day, got %+v", resp)
+	}
+}
+
+func TestHistoryUnknownGameAndMethod(t *testing.T) {
+	h := NewHistoryHandler(storeWithGames("2024-01-15", nil), nil, time.UTC, testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)))
+	testutil.AssertStatus(t, testutil.Serve(historyMux(h), http.MethodGet, "/games/nope/history", nil), http.StatusNotFound)
+	testutil.AssertStatus(t, testutil.Serve(historyMux(h), http.MethodPost, "/games/nope/history", nil), http.StatusMethodNotAllowed)
+	testutil.AssertStatus(t, testutil.Serve(historyMux(h), http.MethodGet, "/games/nope/history?date=2024-01-15", nil), http.StatusBadRequest)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"log/slog"
+	nethttp "net/http"
+	"strings"
+
+	"github.com/preston-bernstein/nba-data-service/internal/http/middleware"
+)
+
+// probeMethods are tried against an unmatched path to build a 405's Allow header.
+var probeMethods = []string{
+	nethttp.MethodGet, nethttp.MethodHead, nethttp.MethodPost, nethttp.MethodPut,
+	nethttp.MethodPatch, nethttp.MethodDelete, nethttp.MethodOptions,
+}
+
+// Mux routes method-aware ServeMux patterns ("GET /games/{id}") and answers
+// unmatched requests with the JSON error envelope instead of ServeMux's plain
+// text: 405 with Allow when the path is routed for other methods, 404 otherwise.
+// Each pattern's route template is recorded at registration and reported to
+// the logging middleware on a match, so metrics are labeled by route.
+type Mux struct {
+	mux       *nethttp.ServeMux
+	logger    *slog.Logger
+	getOnly   map[string]bool   // GET patterns that must not also answer HEAD
+	templates map[string]string // pattern to metrics route template
+}
+
+// NewMux returns an empty Mux; logger is the fallback for its 404/405 logs.
+func NewMux(logger *slog.Logger) *Mux {
+	return &Mux{mux: nethttp.NewServeMux(), logger: logger, getOnly: map[string]bool{}, templates: map[string]string{}}
+}
+
+// Handle registers h for pattern, as ServeMux.Handle does.
+func (m *Mux) Handle(pattern string, h nethttp.Handler) {
+	m.mux.Handle(pattern, h)
+	m.templates[pattern] = middleware.RouteTemplate(pattern)
+}
+
+// HandleGetOnly registers h for a "GET ..." pattern without the implicit HEAD
+// match, for handlers such as event streams that must not start on HEAD.
+func (m *Mux) HandleGetOnly(pattern string, h nethttp.Handler) {
+	m.Handle(pattern, h)
+	m.getOnly[pattern] = true
+}
+
+// HandleFunc registers fn for pattern, as ServeMux.HandleFunc does.
+func (m *Mux) HandleFunc(pattern string, fn func(nethttp.ResponseWriter, *nethttp.Request)) {
+	m.Handle(pattern, nethttp.HandlerFunc(fn))
+}
+
+// Templates maps every registered pattern to its metrics route template.
+func (m *Mux) Templates() map[string]string {
+	out := make(map[string]string, len(m.templates))
+	for pattern, template := range m.templates {
+		out[pattern] = template
+	}
+	return out
+}
+
+func (m *Mux) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	if _, pattern := m.mux.Handler(r); pattern != "" && !m.headOnGetOnly(r.Method, pattern) {
+		template, ok := m.templates[pattern]
+		if !ok {
+			template = middleware.RouteTemplate(pattern)
+		}
+		middleware.SetRoute(r.Context(), template)
+		m.mux.ServeHTTP(w, r)
+		return
+	}
+	if allowed := m.allowed(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeError(w, r, nethttp.StatusMethodNotAllowed, CodeMethodNotAllowed, "method not allowed", m.logger)
+		return
+	}
+	writeError(w, r, nethttp.StatusNotFound, CodeNotFound, "not found", m.logger)
+}
+
+// allowed lists the methods some pattern would accept for r's path.
+func (m *Mux) allowed(r *nethttp.Request) []string {
+	var methods []string
+	for _, method := range probeMethods {
+		probe := r.WithContext(r.Context())
+		probe.Method = method
+		if _, pattern := m.mux.Handler(probe); pattern != "" && !m.headOnGetOnly(method, pattern) {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+func (m *Mux) headOnGetOnly(method, pattern string) bool {
+	return method == nethttp.MethodHead && m.getOnly[pattern]
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const openAPIVersion = "3.0.3"
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+)
+
+// OpenAPIDocument renders Routes as an OpenAPI 3 document.
+func OpenAPIDocument() map[string]any {
+	paths := map[string]any{}
+	examples := openAPIExamples()
+	for _, route := range Routes {
+		item, _ := paths[route.Path].(map[string]any)
+		if item == nil {
+			item = map[string]any{}
+			paths[route.Path] = item
+		}
+		item[strings.ToLower(route.Method)] = operation(route, examples[route.OperationID])
+	}
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "NBA Data Service",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"adminToken": map[string]any{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+}
+
+func operation(route Route, example any) map[string]any {
+	op := map[string]any{
+		"operationId": route.OperationID,
+		"summary":     route.Summary,
+		"tags":        []string{route.Tag},
+	}
+	if len(route.Params) > 0 {
+		params := make([]map[string]any, 0, len(route.Params))
+		for _, p := range route.Params {
+			schema := map[string]any{"type": "string"}
+			if p.Format != "" {
+				schema["format"] = p.Format
+			}
+			params = append(params, map[string]any{
+				"name":        p.Name,
+				"in":          p.In,
+				"description": p.Description,
+				"required":    p.Required,
+				"schema":      schema,
+			})
+		}
+		op["parameters"] = params
+	}
+	responses := map[string]any{}
+	for status, desc := range route.Responses {
+		resp := map[string]any{"description": desc}
+		switch {
+		case status >= 400:
+			resp["content"] = jsonContent(errorSchema(), errorExample(status))
+		case status == nethttp.StatusOK && route.Body != nil:
+			resp["content"] = jsonContent(schemaOf(route.Body), example)
+		}
+		responses[strconv.Itoa(status)] = resp
+	}
+	op["responses"] = responses
+	if route.Admin {
+		op["security"] = []map[string][]string{{"adminToken": {}}}
+	}
+	return op
+}
+
+func jsonContent(schema map[string]any, example any) map[string]any {
+	media := map[string]any{"schema": schema}
+	if example != nil {
+		media["example"] = example
+	}
+	return map[string]any{"application/json": media}
+}
+
+// OpenAPI serves the OpenAPI document at GET /openapi.json.
+func (h *Handler) OpenAPI(w nethttp.ResponseWriter, r *nethttp.Request) {
+	openAPIOnce.Do(func() {
+		openAPIDoc, _ = json.Marshal(OpenAPIDocument())
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(nethttp.StatusOK)
+	_, _ = w.Write(openAPIDoc)
+}
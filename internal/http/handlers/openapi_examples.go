@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/fixture"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// exampleDate is the date every OpenAPI example is generated for.
+const exampleDate = "2024-01-15"
+
+const exampleRequestID = "3f2a9c1d5e7b8a64"
+
+// exampleGames derives one scheduled, one live, and one final game from the
+// fixture provider so examples track the current structs and fixture data.
+// Statuses and scores are set here so the examples do not depend on how far
+// exampleDate is from the current time.
+func exampleGames() (scheduled, live, final domaingames.Game) {
+	games, _ := fixture.New().FetchGames(context.Background(), exampleDate, "")
+	scheduled = games[0]
+	scheduled.Status = "Scheduled"
+	scheduled.StatusKind = domaingames.StatusScheduled
+	scheduled.Score = domaingames.Score{}
+	scheduled.Meta.Period = 0
+
+	live = games[1]
+	live.Status = "3rd Qtr"
+	live.StatusKind = domaingames.StatusInProgress
+	live.Score = domaingames.Score{Home: 78, Away: 74}
+	live.Meta.Period = 3
+	live.Meta.Time = "5:12"
+
+	final = games[2]
+	final.Status = "Final"
+	final.StatusKind = domaingames.StatusFinal
+	final.Score = domaingames.Score{Home: 112, Away: 104}
+	final.Meta.Period = 4
+	return scheduled, live, final
+}
+
+// openAPIExamples maps operation IDs to a 200 response example.
+func openAPIExamples() map[string]any {
+	scheduled, live, final := exampleGames()
+	today := domaingames.NewTodayResponse(exampleDate, []domaingames.Game{scheduled, live, final})
+	started := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	return map[string]any{
+		"health":      map[string]string{"status": "ok"},
+		"ready":       map[string]string{"status": "ready"},
+		"listGames":   today,
+		"getGame":     live,
+		"standings":   computeStandings([]domaingames.Game{final}, ""),
+		"headToHead":  exampleHeadToHead(final),
+		"gameHistory": exampleHistory(live, started),
+		"refreshJob": RefreshJob{
+			ID: "a1b2c3d4e5f60718", Date: exampleDate, State: jobSucceeded, Count: len(today.Games),
+			StartedAt: started, FinishedAt: started.Add(4 * time.Second),
+		},
+		"snapshotHistory": SnapshotHistoryResponse{Date: exampleDate, Versions: []snapshots.HistoryVersion{
+			{ID: "20240115T093000.000000000Z", ArchivedAt: started.Add(30 * time.Minute), Games: 2},
+			{ID: "20240115T221500.000000000Z", ArchivedAt: started.Add(13*time.Hour + 15*time.Minute), Games: 3},
+		}},
+		"snapshotDiff": exampleDiff(scheduled, live),
+		"rebuildManifest": ManifestRebuildResponse{Status: "ok", Changed: true, ManifestRebuild: snapshots.ManifestRebuild{
+			Games:   snapshots.ManifestChange{Dates: []string{"2024-01-14", exampleDate}, Added: []string{"2024-01-14"}, Removed: []string{"2024-01-13"}},
+			Teams:   snapshots.ManifestChange{Dates: []string{exampleDate}, Added: []string{}, Removed: []string{}},
+			Players: snapshots.ManifestChange{Dates: []string{}, Added: []string{}, Removed: []string{}},
+		}},
+		"reloadConfig": ConfigReload{
+			Applied: []string{"PollInterval", "LogLevel"},
+			Skipped: []string{"Port"},
+		},
+	}
+}
+
+func exampleHeadToHead(final domaingames.Game) HeadToHeadResponse {
+	m := newMatchup(exampleDate, final)
+	return HeadToHeadResponse{
+		TeamID:     final.HomeTeam.ID,
+		OpponentID: final.AwayTeam.ID,
+		Matchups:   []Matchup{m},
+		Summary:    HeadToHeadSummary{Played: 1, Wins: 1},
+	}
+}
+
+// exampleHistory replays a tip-off and first basket through HistoryHandler.
+func exampleHistory(live domaingames.Game, at time.Time) GameHistoryResponse {
+	h := NewHistoryHandler(nil, nil, time.UTC, nil)
+	tipoff := at.Add(10 * time.Hour)
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeAdded, GameID: live.ID, NewStatus: domaingames.StatusScheduled, At: at})
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeStatus, GameID: live.ID, OldStatus: domaingames.StatusScheduled, NewStatus: domaingames.StatusInProgress, At: tipoff})
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeScore, GameID: live.ID, Score: domaingames.Score{Home: 2}, At: tipoff.Add(time.Minute)})
+	return GameHistoryResponse{GameID: live.ID, Date: exampleDate, Changes: h.games[live.ID]}
+}
+
+func exampleDiff(scheduled, live domaingames.Game) SnapshotDiffResponse {
+	earlier := live
+	earlier.StatusKind = domaingames.StatusScheduled
+	earlier.Score = domaingames.Score{}
+	return SnapshotDiffResponse{
+		Date: exampleDate,
+		A:    "20240115T093000.000000000Z",
+		B:    "20240115T221500.000000000Z",
+		GamesDiff: snapshots.DiffGames(
+			domaingames.NewTodayResponse(exampleDate, []domaingames.Game{earlier}),
+			domaingames.NewTodayResponse(exampleDate, []domaingames.Game{scheduled, live}),
+		),
+	}
+}
+
+// errorExamples are the error envelopes documented for each status.
+var errorExamples = map[int]errorDetail{
+	http.StatusBadRequest:          {Code: CodeInvalidDate, Message: "invalid date format (expected YYYY-MM-DD)"},
+	http.StatusUnauthorized:        {Code: CodeUnauthorized, Message: "unauthorized"},
+	http.StatusNotFound:            {Code: CodeGameNotFound, Message: "game not found"},
+	http.StatusMethodNotAllowed:    {Code: CodeMethodNotAllowed, Message: "method not allowed"},
+	http.StatusConflict:            {Code: CodeSnapshotFrozen, Message: "snapshot frozen (pass force=true to overwrite)"},
+	http.StatusTooManyRequests:     {Code: CodeRateLimited, Message: "snapshot unavailable"},
+	http.StatusBadGateway:          {Code: CodeUpstreamUnavailable, Message: "snapshot unavailable"},
+	http.StatusServiceUnavailable:  {Code: CodeNotReady, Message: "not ready"},
+	http.StatusGatewayTimeout:      {Code: CodeUpstreamTimeout, Message: "snapshot unavailable"},
+	http.StatusInternalServerError: {Code: CodeInternal, Message: "failed to write snapshot"},
+}
+
+func errorExample(status int) errorResponse {
+	detail, ok := errorExamples[status]
+	if !ok {
+		detail = errorDetail{Code: CodeInternal, Message: http.StatusText(status)}
+	}
+	return errorResponse{Error: detail, RequestID: exampleRequestID}
+}
+
+// errorSchema describes the envelope written by writeError; code is one of
+// errorCodes and details, when present, is route-specific.
+func errorSchema() map[string]any {
+	codes := make([]string, len(errorCodes))
+	for i, c := range errorCodes {
+		codes[i] = string(c)
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"error": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"code":    map[string]any{"type": "string", "enum": codes},
+					"message": map[string]any{"type": "string"},
+					"details": map[string]any{"type": "object"},
+				},
+				"required": []string{"code", "message"},
+			},
+			"requestId": map[string]any{"type": "string"},
+		},
+		"required": []string{"error"},
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func TestOpenAPIDocumentServedAndParses(t *testing.T) {
+	rr := testutil.Serve(newHandler(nil, nil), http.MethodGet, "/openapi.json", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+
+	var doc struct {
+		OpenAPI string                               `json:"openapi"`
+		Paths   map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("spec does not parse: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("expected OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+	for _, route := range Routes {
+		op, ok := doc.Paths[route.Path][strings.ToLower(route.Method)]
+		if !ok {
+			t.Fatalf("spec missing %s %s", route.Method, route.Path)
+		}
+		if op["operationId"] != route.OperationID {
+			t.Fatalf("unexpected operationId for %s: %v", route.Path, op["operationId"])
+		}
+		if _, secured := op["security"]; secured != route.Admin {
+			t.Fatalf("expected security=%v on %s %s", route.Admin, route.Method, route.Path)
+		}
+	}
+}
+
+func TestGamesRejectsUnknownQueryParams(t *testing.T) {
+	h := newHandler(storeWithGames("2024-01-15", nil), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/games?date=2024-01-15&dte=2024-01-16", "unknown query parameter(s) dte; allowed: date, fields, include, locale, meta, postseason, sort, tz"},
+		{"/games/g1?foo=1&bar=2", "unknown query parameter(s) bar, foo; allowed: fields, include, locale, tz"},
+	}
+	for _, tc := range cases {
+		rr := testutil.Serve(h, http.MethodGet, tc.path, nil)
+		testutil.AssertStatus(t, rr, http.StatusBadRequest)
+		resp := decodeError(t, rr)
+		if resp.Error.Code != CodeInvalidQuery || resp.Error.Message != tc.want {
+			t.Fatalf("unexpected error for %s: %+v", tc.path, resp.Error)
+		}
+	}
+
+	stream := NewStreamHandler(nil, nil, 0, nil, nil)
+	rr := testutil.Serve(stream, http.MethodGet, "/games/stream?since=1", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+}
+
+func TestGamesAcceptsDeclaredQueryParams(t *testing.T) {
+	h := newHandler(storeWithGames("2024-01-15", nil), nil)
+	h.clock = testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+
+	rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-01-15&include=display&tz=UTC&locale=en-US", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+}
+
+// validateExample checks value (decoded JSON) against the subset of JSON Schema
+// that schemaOf emits.
+func validateExample(t *testing.T, where string, schema map[string]any, value any) {
+	t.Helper()
+	switch schema["type"] {
+	case nil:
+		return
+	case "string":
+		if _, ok := value.(string); !ok {
+			t.Fatalf("%s: expected string, got %T", where, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			t.Fatalf("%s: expected boolean, got %T", where, value)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			t.Fatalf("%s: expected integer, got %v", where, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			t.Fatalf("%s: expected number, got %T", where, value)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			t.Fatalf("%s: expected array, got %T", where, value)
+		}
+		for i, item := range items {
+			validateExample(t, fmt.Sprintf("%s[%d]", where, i), schema["items"].(map[string]any), item)
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			t.Fatalf("%s: expected object, got %T", where, value)
+		}
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				t.Fatalf("%s: missing required property %q", where, name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		extra, _ := schema["additionalProperties"].(map[string]any)
+		for name, v := range obj {
+			prop, ok := props[name].(map[string]any)
+			switch {
+			case ok:
+				validateExample(t, where+"."+name, prop, v)
+			case extra != nil:
+				validateExample(t, where+"."+name, extra, v)
+			default:
+				t.Fatalf("%s: unexpected property %q", where, name)
+			}
+		}
+	default:
+		t.Fatalf("%s: unsupported schema type %v", where, schema["type"])
+	}
+}
+
+func TestOpenAPIExamplesMatchSchemas(t *testing.T) {
+	rr := testutil.Serve(newHandler(nil, nil), http.MethodGet, "/openapi.json", nil)
+	var doc struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Responses   map[string]struct {
+				Content map[string]struct {
+					Schema  map[string]any `json:"schema"`
+					Example any            `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	testutil.DecodeJSON(t, rr, &doc)
+
+	examples := 0
+	for path, ops := range doc.Paths {
+		for method, op := range ops {
+			for status, resp := range op.Responses {
+				media, ok := resp.Content["application/json"]
+				if !ok {
+					continue
+				}
+				where := fmt.Sprintf("%s %s %s", method, path, status)
+				if media.Example == nil {
+					t.Fatalf("%s: expected an example", where)
+				}
+				validateExample(t, where, media.Schema, media.Example)
+				examples++
+			}
+		}
+	}
+	if examples == 0 {
+		t.Fatalf("expected examples in the spec")
+	}
+
+	// The list example carries a scheduled, a live, and a final game from the fixture provider.
+	listGames := doc.Paths["/games"]["get"].Responses["200"].Content["application/json"].Example.(map[string]any)
+	var kinds []string
+	for _, g := range listGames["games"].([]any) {
+		kinds = append(kinds, g.(map[string]any)["statusKind"].(string))
+	}
+	if strings.Join(kinds, ",") != "SCHEDULED,IN_PROGRESS,FINAL" {
+		t.Fatalf("unexpected example game statuses %v", kinds)
+	}
+}
+
+// committedOpenAPI is the checked-in copy of the served document, for
+// consumers that read the contract from the repo.
+var committedOpenAPI = filepath.Join("..", "..", "..", "api", "openapi.json")
+
+func TestCommittedOpenAPIMatchesServed(t *testing.T) {
+	want, err := json.MarshalIndent(OpenAPIDocument(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want = append(want, '\n')
+	if os.Getenv("UPDATE_OPENAPI") != "" {
+		if err := os.MkdirAll(filepath.Dir(committedOpenAPI), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(committedOpenAPI, want, 0o644); err != nil {
+			t.Fatalf("write %s: %v", committedOpenAPI, err)
+		}
+	}
+	got, err := os.ReadFile(committedOpenAPI)
+	if err != nil {
+		t.Fatalf("read %s: %v", committedOpenAPI, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s is stale; regenerate it with `make openapi`", committedOpenAPI)
+	}
+}
@@ -1,37 +1,71 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 
 	"github.com/preston-bernstein/nba-data-service/internal/http/middleware"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
 )
 
+// maxBufferedResponse is the largest body sent with Content-Length and the
+// largest buffer returned to the pool. Every body is still encoded in full
+// before the first byte is written; larger ones are only sent without
+// Content-Length (chunked), and their buffers are dropped so one big response
+// does not pin memory.
+const maxBufferedResponse = 1 << 20
+
+var responseBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// releaseBuffer returns a response buffer to the pool; tests replace it to
+// observe what is pooled.
+var releaseBuffer = func(buf *bytes.Buffer) { responseBuffers.Put(buf) }
+
+// writeJSON encodes payload before writing anything, so an encoding failure is
+// answered with a clean 500 instead of a truncated body under a 2xx status.
 func writeJSON(w http.ResponseWriter, status int, payload any, logger *slog.Logger) {
+	buf := responseBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxBufferedResponse {
+			releaseBuffer(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		if logger != nil {
+			logger.Error("failed to encode response", "err", err)
+		}
+		if _, isError := payload.(errorResponse); !isError {
+			writeJSON(w, http.StatusInternalServerError, errorResponse{Error: errorDetail{Code: CodeInternal, Message: "failed to encode response"}}, logger)
+		}
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
+	if buf.Len() <= maxBufferedResponse {
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	}
 	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(payload); err != nil && logger != nil {
-		logger.Error("failed to encode response", "err", err)
+	if _, err := w.Write(buf.Bytes()); err != nil && logger != nil {
+		logger.Warn("failed to write response", "err", err)
 	}
 }
 
-func writeError(w http.ResponseWriter, r *http.Request, status int, message string, logger *slog.Logger) {
-	reqID := requestID(r)
-	body := map[string]string{"error": message}
-	if reqID != "" {
-		body["requestId"] = reqID
-	}
-	writeJSON(w, status, body, logger)
+// writeError writes the error envelope with a stable code and a free-form message.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, message string, logger *slog.Logger) {
+	writeErrorDetails(w, r, status, code, message, nil, logger)
 }
 
-func requireMethod(w http.ResponseWriter, r *http.Request, method string, logger *slog.Logger) bool {
-	if r.Method != method {
-		writeError(w, r, http.StatusMethodNotAllowed, "method not allowed", logger)
-		return false
-	}
-	return true
+// writeErrorDetails is writeError with structured details (omitted when nil).
+func writeErrorDetails(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, message string, details any, logger *slog.Logger) {
+	writeJSON(w, status, errorResponse{
+		Error:     errorDetail{Code: code, Message: message, Details: details},
+		RequestID: requestID(r),
+	}, logger)
 }
 
 func loggerFromContext(r *http.Request, fallback *slog.Logger) *slog.Logger {
@@ -2,10 +2,22 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 	"github.com/preston-bernstein/nba-data-service/internal/testutil"
 )
 
@@ -16,7 +28,7 @@ func TestWriteErrorIncludesRequestID(t *testing.T) {
 	req.Header.Set("X-Request-ID", "abc123")
 
 	rr := testutil.ServeRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		writeError(w, r, http.StatusTeapot, "boom", logger)
+		writeError(w, r, http.StatusTeapot, CodeInternal, "boom", logger)
 	}), req)
 
 	if rr.Code != http.StatusTeapot {
@@ -31,27 +43,179 @@ func TestWriteErrorIncludesRequestID(t *testing.T) {
 	}
 }
 
-func TestWriteJSONLogsEncodeError(t *testing.T) {
+// decodeError decodes rr's body as the error envelope.
+func decodeError(t *testing.T, rr *httptest.ResponseRecorder) errorResponse {
+	t.Helper()
+	var body errorResponse
+	testutil.DecodeJSON(t, rr, &body)
+	if body.Error.Code == "" || body.Error.Message == "" {
+		t.Fatalf("expected error envelope with code and message, got %s", rr.Body.String())
+	}
+	return body
+}
+
+func TestWriteErrorEnvelopeShape(t *testing.T) {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/games?date=x", nil)
+	req.Header.Set("X-Request-ID", "abc123")
+	writeErrorDetails(rr, req, http.StatusBadRequest, CodeInvalidQuery, "bad", map[string]string{"k": "v"}, nil)
+
+	want := `{"error":{"code":"INVALID_QUERY","message":"bad","details":{"k":"v"}},"requestId":"abc123"}` + "\n"
+	if got := rr.Body.String(); got != want {
+		t.Fatalf("unexpected envelope\n got %s\nwant %s", got, want)
+	}
+
+	rr = httptest.NewRecorder()
+	writeError(rr, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusNotFound, CodeNotFound, "not found", nil)
+	if got := rr.Body.String(); got != `{"error":{"code":"NOT_FOUND","message":"not found"}}`+"\n" {
+		t.Fatalf("expected details and requestId omitted, got %s", got)
+	}
+}
+
+func TestWriteJSONEncodeErrorAnswersInternal(t *testing.T) {
 	logger, buf := testutil.NewBufferLogger()
 	rr := testutil.Serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusOK, make(chan int), logger)
 	}), http.MethodGet, "/encode-error", nil)
 
-	if rr.Code != http.StatusOK {
-		t.Fatalf("expected status written even on encode error, got %d", rr.Code)
+	testutil.AssertStatus(t, rr, http.StatusInternalServerError)
+	if resp := decodeError(t, rr); resp.Error.Code != CodeInternal {
+		t.Fatalf("expected an INTERNAL envelope instead of a partial body, got %+v", resp.Error)
 	}
 	if buf.Len() == 0 {
 		t.Fatalf("expected logger to record encode error")
 	}
 }
 
+func TestWriteJSONSetsContentLength(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeJSON(rr, http.StatusOK, map[string]string{"status": "ok"}, nil)
+	if got, want := rr.Header().Get("Content-Length"), strconv.Itoa(rr.Body.Len()); got != want {
+		t.Fatalf("expected Content-Length %s, got %q", want, got)
+	}
+
+	rr = httptest.NewRecorder()
+	writeJSON(rr, http.StatusOK, strings.Repeat("x", maxBufferedResponse), nil)
+	if got := rr.Header().Get("Content-Length"); got != "" {
+		t.Fatalf("expected a body over the buffer limit sent without Content-Length, got %q", got)
+	}
+	if rr.Body.Len() <= maxBufferedResponse {
+		t.Fatalf("expected the full large body, got %d bytes", rr.Body.Len())
+	}
+}
+
+func TestWriteJSONPoolsOnlySmallBuffers(t *testing.T) {
+	var pooled []int
+	orig := releaseBuffer
+	releaseBuffer = func(buf *bytes.Buffer) { pooled = append(pooled, buf.Cap()) }
+	t.Cleanup(func() { releaseBuffer = orig })
+
+	writeJSON(httptest.NewRecorder(), http.StatusOK, map[string]string{"status": "ok"}, nil)
+	if len(pooled) != 1 {
+		t.Fatalf("expected the small response's buffer pooled, got %v", pooled)
+	}
+
+	rr := httptest.NewRecorder()
+	writeJSON(rr, http.StatusOK, strings.Repeat("x", 2*maxBufferedResponse), nil)
+	if got := rr.Header().Get("Content-Length"); got != "" || rr.Body.Len() <= 2*maxBufferedResponse {
+		t.Fatalf("expected a %d-byte body without Content-Length, got %q and %d bytes", 2*maxBufferedResponse, got, rr.Body.Len())
+	}
+	if len(pooled) != 1 {
+		t.Fatalf("expected the large response's buffer dropped, got pooled capacities %v", pooled)
+	}
+}
+
 func TestWriteErrorFallsBackToHeaderRequestID(t *testing.T) {
 	logger, _ := testutil.NewBufferLogger()
 	rr := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	req.Header.Set("X-Request-ID", "header-id")
-	writeError(rr, req, http.StatusTeapot, "boom", logger)
+	writeError(rr, req, http.StatusTeapot, CodeInternal, "boom", logger)
 	if !bytes.Contains(rr.Body.Bytes(), []byte("header-id")) {
 		t.Fatalf("expected header request id used when context missing")
 	}
 }
+
+func TestErrorCodesByStatus(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	store := storeWithGames("2024-01-15", []domaingames.Game{{ID: "g1"}})
+	rateLimited := &teststubs.StubSnapshotStore{LoadErr: &providers.RateLimitError{StatusCode: 429}}
+	down := &teststubs.StubSnapshotStore{LoadErr: errors.New("boom")}
+	notReady := func() poller.Status { return poller.Status{} }
+
+	cases := []struct {
+		name   string
+		snaps  snapshots.Store
+		status func() poller.Status
+		method string
+		path   string
+		want   int
+		code   ErrorCode
+	}{
+		{"missing date", store, nil, http.MethodGet, "/games", http.StatusBadRequest, CodeInvalidDate},
+		{"bad date", store, nil, http.MethodGet, "/games?date=2024-1-15", http.StatusBadRequest, CodeInvalidDate},
+		{"date out of range", store, nil, http.MethodGet, "/games?date=2023-01-15", http.StatusBadRequest, CodeDateOutOfRange},
+		{"unknown query", store, nil, http.MethodGet, "/games?date=2024-01-15&x=1", http.StatusBadRequest, CodeInvalidQuery},
+		{"bad tz", store, nil, http.MethodGet, "/games?date=2024-01-15&tz=../x", http.StatusBadRequest, CodeInvalidTimezone},
+		{"bad game id", store, nil, http.MethodGet, "/games/a/b", http.StatusBadRequest, CodeInvalidGameID},
+		{"game not found", store, nil, http.MethodGet, "/games/missing", http.StatusNotFound, CodeGameNotFound},
+		{"unknown route", store, nil, http.MethodGet, "/nope", http.StatusNotFound, CodeNotFound},
+		{"wrong method", store, nil, http.MethodPost, "/games?date=2024-01-15", http.StatusMethodNotAllowed, CodeMethodNotAllowed},
+		{"rate limited", rateLimited, nil, http.MethodGet, "/games?date=2024-01-15", http.StatusTooManyRequests, CodeRateLimited},
+		{"upstream down", down, nil, http.MethodGet, "/games?date=2024-01-15", http.StatusBadGateway, CodeUpstreamUnavailable},
+		{"storage missing", nil, nil, http.MethodGet, "/games/g1", http.StatusBadGateway, CodeStorageUnavailable},
+		{"not ready", down, notReady, http.MethodGet, "/ready", http.StatusServiceUnavailable, CodeNotReady},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(tc.snaps, tc.status)
+			h.clock = testutil.NewFakeClock(now)
+			rr := testutil.Serve(h, tc.method, tc.path, nil)
+			testutil.AssertStatus(t, rr, tc.want)
+			if body := decodeError(t, rr); body.Error.Code != tc.code {
+				t.Fatalf("expected code %s, got %+v", tc.code, body.Error)
+			}
+		})
+	}
+}
+
+func TestREADMEListsEveryErrorCode(t *testing.T) {
+	readme, err := os.ReadFile(filepath.Join("..", "..", "..", "README.md"))
+	if err != nil {
+		t.Fatalf("read README: %v", err)
+	}
+	for _, code := range errorCodes {
+		if !strings.Contains(string(readme), "`"+string(code)+"`") {
+			t.Errorf("README error code list is missing %s", code)
+		}
+	}
+}
+
+func benchmarkPayload() domaingames.TodayResponse {
+	var games []domaingames.Game
+	for i := 0; i < 12; i++ {
+		games = append(games, testutil.SampleGame(strconv.Itoa(i)))
+	}
+	return domaingames.NewTodayResponse("2024-01-15", games)
+}
+
+// BenchmarkWriteJSON measures the pooled-buffer writer; compare allocations
+// with BenchmarkWriteJSONUnbuffered, the encoder-to-writer approach it replaced.
+func BenchmarkWriteJSON(b *testing.B) {
+	payload := benchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		writeJSON(httptest.NewRecorder(), http.StatusOK, payload, nil)
+	}
+}
+
+func BenchmarkWriteJSONUnbuffered(b *testing.B) {
+	payload := benchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(payload)
+	}
+}
@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	nethttp "net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// Param describes one route parameter for the OpenAPI document and query validation.
+type Param struct {
+	Name        string
+	In          string // "query", "path", or "header"
+	Description string
+	Format      string // OpenAPI string format, e.g. "date"
+	Required    bool
+}
+
+// Route is the single source of truth for a public route: /openapi.json is rendered
+// from it and, when ValidateQuery is set, unknown query parameters are rejected.
+type Route struct {
+	Method        string
+	Path          string // OpenAPI path template, e.g. /games/{id}
+	OperationID   string
+	Summary       string
+	Tag           string
+	Params        []Param
+	Responses     map[int]string
+	Body          any  // zero value of the 200 JSON body, for its schema; nil when untyped
+	Admin         bool // requires the admin bearer token
+	ValidateQuery bool
+}
+
+var (
+	paramDate    = Param{Name: "date", In: "query", Format: "date", Description: "Snapshot date (YYYY-MM-DD)."}
+	paramInclude = Param{Name: "include", In: "query", Description: "Comma-separated extras; \"display\" adds startTimeLocal and startTimeDisplay."}
+	paramTZ      = Param{Name: "tz", In: "query", Description: "IANA timezone; adds startTimeLocal and gameDateLocal. Unknown zones fall back to the service zone, named in X-Timezone-Fallback."}
+	paramLocale  = Param{Name: "locale", In: "query", Description: "Locale for startTimeDisplay; defaults to Accept-Language."}
+	paramFields  = Param{Name: "fields", In: "query", Description: "Comma-separated top-level game fields to return, e.g. id,statusKind,score,startTime."}
+)
+
+// Routes lists every documented route in the order they appear in /openapi.json.
+var Routes = []Route{
+	{
+		Method: nethttp.MethodGet, Path: "/health", OperationID: "health", Tag: "ops",
+		Summary: "Liveness check.",
+		Params: []Param{
+			{Name: "verbose", In: "query", Description: "\"true\" checks the store, snapshot directory and provider and returns a component map."},
+		},
+		Responses: map[int]string{200: "Service is up (verbose: ok or degraded).", 503: "Shutting down, or a hard dependency is down."},
+		Body:      map[string]string{},
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/ready", OperationID: "ready", Tag: "ops",
+		Summary:   "Readiness check based on poller health.",
+		Responses: map[int]string{200: "Ready for traffic.", 503: "Not ready."},
+		Body:      map[string]string{},
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/status", OperationID: "status", Tag: "ops",
+		Summary:   "Operational details (poller, provider, clients, streams).",
+		Responses: map[int]string{200: "Status document."},
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/openapi.json", OperationID: "openapi", Tag: "ops",
+		Summary:   "This OpenAPI document.",
+		Responses: map[int]string{200: "OpenAPI 3 document."},
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/games", OperationID: "listGames", Tag: "games",
+		Summary: "Games snapshot for a date within 7 days of today.",
+		Params: []Param{
+			{Name: "date", In: "query", Format: "date", Required: true, Description: paramDate.Description},
+			{Name: "postseason", In: "query", Description: "true for playoff games only, false for regular-season games only."},
+			{Name: "sort", In: "query", Description: "Game order: startTime (default, earliest first), -startTime (latest first) or status (live, scheduled, postponed, final, canceled; each by start time). Ties go by canonical ID, then ID."},
+			paramInclude,
+			{Name: "tz", In: "query", Description: paramTZ.Description + " Reads the zone's own snapshot (its local slate for date) when the sync keeps one."},
+			paramLocale, paramFields,
+			{Name: "meta", In: "query", Description: "true wraps the body as {meta: {source, generatedAt, snapshotDate, requestId}, data}; source is cache or snapshot."},
+			{Name: "X-Debug-Meta", In: "header", Description: "true is the same as meta=true."},
+		},
+		Responses:     map[int]string{200: "Games for the date.", 400: "Invalid or missing parameters.", 404: "No snapshot for a past date.", 429: "Upstream rate limited.", 502: "Snapshot unavailable.", 504: "Upstream timed out."},
+		Body:          domaingames.TodayResponse{},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/games/{id}", OperationID: "getGame", Tag: "games",
+		Summary: "A game from today's snapshot.",
+		Params: []Param{
+			{Name: "id", In: "path", Required: true, Description: "Game ID."},
+			paramInclude, paramTZ, paramLocale, paramFields,
+		},
+		Responses:     map[int]string{200: "The game.", 400: "Invalid ID or parameters.", 404: "Game not found."},
+		Body:          domaingames.Game{},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/games/{id}/history", OperationID: "gameHistory", Tag: "games",
+		Summary: "Status and score changes the poller has seen for one of today's games (last 50).",
+		Params: []Param{
+			{Name: "id", In: "path", Required: true, Description: "Game ID."},
+		},
+		Responses:     map[int]string{200: "Changes, oldest first.", 404: "Game not found today."},
+		Body:          GameHistoryResponse{},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/games/stream", OperationID: "streamGames", Tag: "games",
+		Summary: "Server-Sent Events: a snapshot event, then game updates.",
+		Params: []Param{
+			{Name: "Last-Event-ID", In: "header", Description: "Resume after this event ID."},
+		},
+		Responses:     map[int]string{200: "text/event-stream of snapshot and game events.", 503: "Too many streams."},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/standings", OperationID: "standings", Tag: "games",
+		Summary: "Win/loss records by conference and division from final games in stored snapshots.",
+		Params: []Param{
+			{Name: "season", In: "query", Description: "Season (e.g. 2023-2024); defaults to the latest season seen."},
+		},
+		Responses:     map[int]string{200: "Standings.", 502: "Snapshots unavailable."},
+		Body:          StandingsResponse{},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/teams/{id}/vs/{otherId}", OperationID: "headToHead", Tag: "games",
+		Summary: "Matchups between two teams found in stored snapshots, with a record for {id}.",
+		Params: []Param{
+			{Name: "id", In: "path", Required: true, Description: "Team ID."},
+			{Name: "otherId", In: "path", Required: true, Description: "Opponent team ID; must differ from id."},
+			{Name: "season", In: "query", Description: "Season to scan, including pinned dates; defaults to the retention window."},
+		},
+		Responses:     map[int]string{200: "Matchups and summary.", 400: "Invalid or equal team IDs.", 502: "Snapshots unavailable."},
+		Body:          HeadToHeadResponse{},
+		ValidateQuery: true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/admin/snapshots", OperationID: "listSnapshots", Tag: "admin",
+		Summary:   "List snapshot dates and pins.",
+		Responses: map[int]string{200: "Snapshot manifest.", 401: "Unauthorized."},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodPost, Path: "/admin/snapshots/refresh", OperationID: "refreshSnapshot", Tag: "admin",
+		Summary: "Fetch and write a games snapshot (defaults to today).",
+		Params: []Param{
+			paramDate,
+			{Name: "tz", In: "query", Description: "IANA timezone for the upstream fetch."},
+			{Name: "force", In: "query", Description: "Set to true to overwrite a frozen date (late stat corrections)."},
+		},
+		Responses: map[int]string{200: "Snapshot written.", 400: "Invalid date.", 401: "Unauthorized.", 409: "Date is frozen.", 429: "Upstream rate limited.", 502: "Upstream unavailable.", 504: "Upstream timed out."},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodPost, Path: "/admin/snapshots/pin/{date}", OperationID: "pinSnapshot", Tag: "admin",
+		Summary:   "Pin a snapshot date so retention keeps it.",
+		Params:    []Param{{Name: "date", In: "path", Format: "date", Required: true, Description: paramDate.Description}},
+		Responses: map[int]string{200: "Pinned.", 401: "Unauthorized.", 404: "Snapshot not found."},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodDelete, Path: "/admin/snapshots/pin/{date}", OperationID: "unpinSnapshot", Tag: "admin",
+		Summary:   "Unpin a snapshot date.",
+		Params:    []Param{{Name: "date", In: "path", Format: "date", Required: true, Description: paramDate.Description}},
+		Responses: map[int]string{200: "Unpinned.", 401: "Unauthorized.", 404: "Snapshot not found."},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodPost, Path: "/admin/snapshots/manifest/rebuild", OperationID: "rebuildManifest", Tag: "admin",
+		Summary:   "Rebuild the snapshot manifest from the files on disk and report dates added and removed per kind.",
+		Responses: map[int]string{200: "Manifest rebuilt.", 401: "Unauthorized."},
+		Body:      ManifestRebuildResponse{},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/admin/snapshots/jobs/{id}", OperationID: "refreshJob", Tag: "admin",
+		Summary:   "Status of an admin refresh job.",
+		Params:    []Param{{Name: "id", In: "path", Required: true, Description: "Job ID."}},
+		Responses: map[int]string{200: "Job status.", 401: "Unauthorized.", 404: "Job not found."},
+		Body:      RefreshJob{},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/admin/snapshots/games/{date}/history", OperationID: "snapshotHistory", Tag: "admin",
+		Summary:   "Archived versions of a date's games snapshot (SNAPSHOT_HISTORY_ENABLED), oldest first.",
+		Params:    []Param{{Name: "date", In: "path", Format: "date", Required: true, Description: paramDate.Description}},
+		Responses: map[int]string{200: "Archived versions.", 400: "Invalid date.", 401: "Unauthorized."},
+		Body:      SnapshotHistoryResponse{},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodGet, Path: "/admin/snapshots/games/{date}/diff", OperationID: "snapshotDiff", Tag: "admin",
+		Summary: "Games added and removed, and status and score changes, between two archived versions.",
+		Params: []Param{
+			{Name: "date", In: "path", Format: "date", Required: true, Description: paramDate.Description},
+			{Name: "a", In: "query", Required: true, Description: "Older version ID from the history listing."},
+			{Name: "b", In: "query", Required: true, Description: "Newer version ID from the history listing."},
+		},
+		Responses: map[int]string{200: "Structured diff.", 400: "Invalid date or version.", 401: "Unauthorized.", 404: "Version not found."},
+		Body:      SnapshotDiffResponse{},
+		Admin:     true,
+	},
+	{
+		Method: nethttp.MethodPost, Path: "/admin/config/reload", OperationID: "reloadConfig", Tag: "admin",
+		Summary:   "Re-read env and CONFIG_FILE and apply the live-reloadable settings.",
+		Responses: map[int]string{200: "Applied and skipped settings.", 401: "Unauthorized.", 422: "Config invalid; nothing applied."},
+		Body:      ConfigReload{},
+		Admin:     true,
+	},
+}
+
+func lookupRoute(method, path string) (Route, bool) {
+	if method == nethttp.MethodHead {
+		method = nethttp.MethodGet // GET patterns serve HEAD too
+	}
+	for _, route := range Routes {
+		if route.Method == method && route.Path == path {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
+
+// allowedQuery returns the route's query parameter names, sorted.
+func (r Route) allowedQuery() []string {
+	var names []string
+	for _, p := range r.Params {
+		if p.In == "query" {
+			names = append(names, p.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateQuery rejects query parameters the route does not declare with a 400
+// listing the allowed ones. Routes without ValidateQuery accept anything.
+func validateQuery(w nethttp.ResponseWriter, r *nethttp.Request, path string, logger *slog.Logger) bool {
+	route, ok := lookupRoute(r.Method, path)
+	if !ok || !route.ValidateQuery {
+		return true
+	}
+	allowed := route.allowedQuery()
+	var unknown []string
+	for name := range r.URL.Query() {
+		if !slices.Contains(allowed, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return true
+	}
+	sort.Strings(unknown)
+	msg := fmt.Sprintf("unknown query parameter(s) %s; allowed: %s", strings.Join(unknown, ", "), strings.Join(allowed, ", "))
+	if len(allowed) == 0 {
+		msg = fmt.Sprintf("unknown query parameter(s) %s; this route takes none", strings.Join(unknown, ", "))
+	}
+	writeErrorDetails(w, r, nethttp.StatusBadRequest, CodeInvalidQuery, msg, map[string][]string{
+		"unknown": unknown,
+		"allowed": allowed,
+	}, logger)
+	return false
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	nethttp "net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// unknownGroup names the conference or division of teams without that data.
+const unknownGroup = "Unknown"
+
+// TeamRecord is one team's line in the standings.
+type TeamRecord struct {
+	Team   teams.Team `json:"team"`
+	Wins   int        `json:"wins"`
+	Losses int        `json:"losses"`
+	WinPct float64    `json:"winPct"`
+}
+
+// DivisionStandings lists a division's teams, best record first.
+type DivisionStandings struct {
+	Name  string       `json:"name"`
+	Teams []TeamRecord `json:"teams"`
+}
+
+// ConferenceStandings groups divisions by conference.
+type ConferenceStandings struct {
+	Name      string              `json:"name"`
+	Divisions []DivisionStandings `json:"divisions"`
+}
+
+// StandingsResponse is the payload returned by /standings.
+type StandingsResponse struct {
+	Season      string                `json:"season"`
+	Games       int                   `json:"games"` // final games counted
+	Conferences []ConferenceStandings `json:"conferences"`
+}
+
+// StandingsHandler serves GET /standings by folding over the FINAL games in stored
+// snapshots. Results are cached until a game goes final or the snapshot dates change.
+type StandingsHandler struct {
+	writer *snapshots.Writer
+	snaps  snapshots.Store
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	dates   []string // snapshot dates the cache was computed from
+	results map[string]StandingsResponse
+}
+
+// NewStandingsHandler constructs a StandingsHandler reading the snapshot dates from
+// writer's manifest and the games from snaps.
+func NewStandingsHandler(writer *snapshots.Writer, snaps snapshots.Store, logger *slog.Logger) *StandingsHandler {
+	return &StandingsHandler{
+		writer: writer,
+		snaps:  snaps,
+		logger: logger,
+	}
+}
+
+// Observe invalidates the cache when a game goes final. Suitable for Poller.OnChange.
+func (h *StandingsHandler) Observe(ev poller.GameChangeEvent) {
+	if ev.Kind == poller.ChangeStatus && ev.Game.StatusKind == domaingames.StatusFinal {
+		h.Invalidate()
+	}
+}
+
+// Invalidate drops cached standings.
+func (h *StandingsHandler) Invalidate() {
+	h.mu.Lock()
+	h.results = nil
+	h.mu.Unlock()
+}
+
+func (h *StandingsHandler) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	if !validateQuery(w, r, "/standings", h.logger) {
+		return
+	}
+	if h.writer == nil || h.snaps == nil {
+		writeError(w, r, nethttp.StatusBadGateway, CodeStorageUnavailable, "snapshot store not configured", h.logger)
+		return
+	}
+	m, err := h.writer.Manifest()
+	if err != nil {
+		writeError(w, r, nethttp.StatusBadGateway, CodeStorageUnavailable, "snapshot manifest unavailable", h.logger)
+		return
+	}
+	season := strings.TrimSpace(r.URL.Query().Get("season"))
+	resp, err := h.standings(r.Context(), m.Games.Dates, season)
+	if err != nil {
+		writeError(w, r, nethttp.StatusGatewayTimeout, CodeRequestTimeout, "request timed out", h.logger)
+		return
+	}
+	writeJSON(w, nethttp.StatusOK, resp, h.logger)
+}
+
+// standings returns cached results for season ("" means the latest season seen),
+// recomputing when the cache was invalidated or the snapshot dates changed. A
+// computation cut short by ctx is returned as an error and not cached.
+func (h *StandingsHandler) standings(ctx context.Context, dates []string, season string) (StandingsResponse, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.results == nil || !slices.Equal(h.dates, dates) {
+		h.results = make(map[string]StandingsResponse)
+		h.dates = slices.Clone(dates)
+	}
+	if cached, ok := h.results[season]; ok {
+		return cached, nil
+	}
+	games, err := h.finalGames(ctx, dates)
+	if err != nil {
+		return StandingsResponse{}, err
+	}
+	resp := computeStandings(games, season)
+	h.results[season] = resp
+	return resp, nil
+}
+
+// finalGames loads FINAL games across dates, oldest first, deduplicated by ID.
+func (h *StandingsHandler) finalGames(ctx context.Context, dates []string) ([]domaingames.Game, error) {
+	sorted := slices.Clone(dates)
+	sort.Strings(sorted)
+	seen := make(map[string]int)
+	var games []domaingames.Game
+	for _, date := range sorted {
+		snap, err := h.snaps.LoadGames(ctx, date)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err != nil {
+			continue
+		}
+		for _, g := range snap.Games {
+			if g.StatusKind != domaingames.StatusFinal {
+				continue
+			}
+			if i, ok := seen[g.ID]; ok {
+				games[i] = g
+				continue
+			}
+			seen[g.ID] = len(games)
+			games = append(games, g)
+		}
+	}
+	return games, nil
+}
+
+// computeStandings folds games (oldest first) into records for season; an empty
+// season selects the season of the most recent game.
+func computeStandings(games []domaingames.Game, season string) StandingsResponse {
+	if season == "" && len(games) > 0 {
+		season = games[len(games)-1].Meta.Season
+	}
+	resp := StandingsResponse{Season: season, Conferences: []ConferenceStandings{}}
+	records := make(map[string]*TeamRecord)
+	record := func(team teams.Team) *TeamRecord {
+		rec, ok := records[team.ID]
+		if !ok {
+			rec = &TeamRecord{}
+			records[team.ID] = rec
+		}
+		// Later games carry the freshest team data.
+		rec.Team = team
+		return rec
+	}
+	for _, g := range games {
+		if g.Meta.Season != season || g.Score.Home == g.Score.Away {
+			continue
+		}
+		home, away := record(g.HomeTeam), record(g.AwayTeam)
+		if g.Score.Home > g.Score.Away {
+			home.Wins++
+			away.Losses++
+		} else {
+			away.Wins++
+			home.Losses++
+		}
+		resp.Games++
+	}
+
+	grouped := make(map[string]map[string][]TeamRecord)
+	for _, rec := range records {
+		if played := rec.Wins + rec.Losses; played > 0 {
+			rec.WinPct = float64(rec.Wins) / float64(played)
+		}
+		conf, div := groupName(rec.Team.Conference), groupName(rec.Team.Division)
+		if grouped[conf] == nil {
+			grouped[conf] = make(map[string][]TeamRecord)
+		}
+		grouped[conf][div] = append(grouped[conf][div], *rec)
+	}
+	for _, conf := range sortedKeys(grouped) {
+		cs := ConferenceStandings{Name: conf}
+		for _, div := range sortedKeys(grouped[conf]) {
+			teamRecords := grouped[conf][div]
+			sort.Slice(teamRecords, func(i, j int) bool {
+				a, b := teamRecords[i], teamRecords[j]
+				if a.WinPct != b.WinPct {
+					return a.WinPct > b.WinPct
+				}
+				if a.Wins != b.Wins {
+					return a.Wins > b.Wins
+				}
+				return a.Team.Name < b.Team.Name
+			})
+			cs.Divisions = append(cs.Divisions, DivisionStandings{Name: div, Teams: teamRecords})
+		}
+		resp.Conferences = append(resp.Conferences, cs)
+	}
+	return resp
+}
+
+func groupName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return unknownGroup
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+var (
+	celtics = teams.Team{ID: "bos", Name: "Celtics", Conference: "East", Division: "Atlantic"}
+	knicks  = teams.Team{ID: "nyk", Name: "Knicks", Conference: "East", Division: "Atlantic"}
+	bucks   = teams.Team{ID: "mil", Name: "Bucks", Conference: "East", Division: "Central"}
+	lakers  = teams.Team{ID: "lal", Name: "Lakers", Conference: "West", Division: "Pacific"}
+	// Team data missing from the game payload falls into the Unknown group.
+	expansion = teams.Team{ID: "exp", Name: "Expansion"}
+)
+
+func finalGame(id string, home, away teams.Team, homePts, awayPts int, season string) domaingames.Game {
+	g := testutil.SampleGame(id)
+	g.HomeTeam, g.AwayTeam = home, away
+	g.StatusKind = domaingames.StatusFinal
+	g.Score = domaingames.Score{Home: homePts, Away: awayPts}
+	g.Meta.Season = season
+	return g
+}
+
+// standingsDate returns a date offset from today so writer retention keeps it.
+func standingsDate(offset int) string {
+	return timeutil.FormatDate(time.Now().UTC().AddDate(0, 0, offset))
+}
+
+func newStandingsFixture(t *testing.T) (*StandingsHandler, *snapshots.Writer) {
+	t.Helper()
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	write := func(date string, games ...domaingames.Game) {
+		if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, games)); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+	}
+	scheduled := testutil.SampleGame("g-sched")
+	scheduled.HomeTeam, scheduled.AwayTeam = celtics, lakers
+	write(standingsDate(-2),
+		finalGame("g1", celtics, knicks, 110, 100, "2023-2024"),
+		finalGame("g2", lakers, bucks, 99, 101, "2023-2024"),
+		finalGame("old", knicks, celtics, 120, 90, "2022-2023"),
+	)
+	write(standingsDate(-1),
+		finalGame("g3", knicks, lakers, 105, 95, "2023-2024"),
+		finalGame("g4", expansion, celtics, 80, 100, "2023-2024"),
+		scheduled,
+	)
+	return NewStandingsHandler(writer, snapshots.NewFSStore(writer.BasePath()), nil), writer
+}
+
+func TestStandingsGroupsAndOrdersRecords(t *testing.T) {
+	h, _ := newStandingsFixture(t)
+	rr := testutil.Serve(h, http.MethodGet, "/standings", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+
+	var resp StandingsResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	if resp.Season != "2023-2024" || resp.Games != 4 {
+		t.Fatalf("expected 4 final games in 2023-2024, got season=%s games=%d", resp.Season, resp.Games)
+	}
+	var confs []string
+	for _, c := range resp.Conferences {
+		confs = append(confs, c.Name)
+	}
+	if len(confs) != 3 || confs[0] != "East" || confs[1] != unknownGroup || confs[2] != "West" {
+		t.Fatalf("unexpected conference order %v", confs)
+	}
+	atlantic := resp.Conferences[0].Divisions[0]
+	if atlantic.Name != "Atlantic" || len(atlantic.Teams) != 2 {
+		t.Fatalf("unexpected atlantic division %+v", atlantic)
+	}
+	bos, nyk := atlantic.Teams[0], atlantic.Teams[1]
+	if bos.Team.ID != "bos" || bos.Wins != 2 || bos.Losses != 0 || bos.WinPct != 1 {
+		t.Fatalf("unexpected leader %+v", bos)
+	}
+	if nyk.Team.ID != "nyk" || nyk.Wins != 1 || nyk.Losses != 1 || nyk.WinPct != 0.5 {
+		t.Fatalf("unexpected second place %+v", nyk)
+	}
+	if unknown := resp.Conferences[1].Divisions[0]; unknown.Name != unknownGroup || unknown.Teams[0].Team.Name != "Expansion" {
+		t.Fatalf("expected team without conference data in Unknown, got %+v", unknown)
+	}
+}
+
+func TestStandingsSeasonParam(t *testing.T) {
+	h, _ := newStandingsFixture(t)
+	rr := testutil.Serve(h, http.MethodGet, "/standings?season=2022-2023", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+	var resp StandingsResponse
+	testutil.DecodeJSON(t, rr, &resp)
+	if resp.Games != 1 || resp.Conferences[0].Divisions[0].Teams[0].Team.ID != "nyk" {
+		t.Fatalf("unexpected 2022-2023 standings %+v", resp)
+	}
+
+	rr = testutil.Serve(h, http.MethodGet, "/standings?conference=East", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadRequest)
+}
+
+func TestStandingsCacheInvalidatedByFinalResult(t *testing.T) {
+	h, writer := newStandingsFixture(t)
+	games := func() int {
+		var resp StandingsResponse
+		testutil.DecodeJSON(t, testutil.Serve(h, http.MethodGet, "/standings", nil), &resp)
+		return resp.Games
+	}
+	if got := games(); got != 4 {
+		t.Fatalf("expected 4 games, got %d", got)
+	}
+
+	// Overwrite an existing date: the date set is unchanged, so the cache holds.
+	late := finalGame("g5", bucks, knicks, 100, 90, "2023-2024")
+	updated := domaingames.NewTodayResponse(standingsDate(-1), []domaingames.Game{
+		finalGame("g3", knicks, lakers, 105, 95, "2023-2024"),
+		finalGame("g4", expansion, celtics, 80, 100, "2023-2024"),
+		late,
+	})
+	if err := writer.WriteGamesSnapshot(standingsDate(-1), updated); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if got := games(); got != 4 {
+		t.Fatalf("expected cached standings before invalidation, got %d", got)
+	}
+
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeScore, Game: late})
+	if got := games(); got != 4 {
+		t.Fatalf("expected score change to keep the cache, got %d", got)
+	}
+	h.Observe(poller.GameChangeEvent{Kind: poller.ChangeStatus, Game: late})
+	if got := games(); got != 5 {
+		t.Fatalf("expected final result to invalidate standings, got %d", got)
+	}
+
+	// A new snapshot date also invalidates.
+	if err := writer.WriteGamesSnapshot(standingsDate(0), domaingames.NewTodayResponse(standingsDate(0), []domaingames.Game{
+		finalGame("g6", lakers, celtics, 100, 90, "2023-2024"),
+	})); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+	if got := games(); got != 6 {
+		t.Fatalf("expected new snapshot date to invalidate standings, got %d", got)
+	}
+}
+
+func TestStandingsWithoutSnapshots(t *testing.T) {
+	rr := testutil.Serve(NewStandingsHandler(nil, nil, nil), http.MethodGet, "/standings", nil)
+	testutil.AssertStatus(t, rr, http.StatusBadGateway)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	nethttp "net/http"
+	"sort"
+)
+
+// StatusFunc reports one named section of the /status payload.
+type StatusFunc func() any
+
+// RegisterStatus adds a named section to the /status payload.
+// Sections are evaluated on every request, so callers should keep them cheap.
+func (h *Handler) RegisterStatus(name string, fn StatusFunc) {
+	if h == nil || name == "" || fn == nil {
+		return
+	}
+	h.statusMu.Lock()
+	defer h.statusMu.Unlock()
+	if h.statusSections == nil {
+		h.statusSections = make(map[string]StatusFunc)
+	}
+	h.statusSections[name] = fn
+}
+
+// Status reports operational details (poller health plus any registered sections) for operators.
+func (h *Handler) Status(w nethttp.ResponseWriter, r *nethttp.Request) {
+	resp := make(map[string]any)
+	if h.statusFn != nil {
+		resp["poller"] = h.statusFn()
+	}
+
+	h.statusMu.RLock()
+	names := make([]string, 0, len(h.statusSections))
+	for name := range h.statusSections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sections := make([]StatusFunc, 0, len(names))
+	for _, name := range names {
+		sections = append(sections, h.statusSections[name])
+	}
+	h.statusMu.RUnlock()
+
+	for i, name := range names {
+		resp[name] = sections[i]()
+	}
+	writeJSON(w, nethttp.StatusOK, resp, h.logger)
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func TestStatusIncludesPollerAndRegisteredSections(t *testing.T) {
+	h := newHandler(nil, func() poller.Status {
+		return poller.Status{ConsecutiveFailures: 2, LastError: "boom"}
+	})
+	h.RegisterStatus("provider", func() any {
+		return map[string]string{"name": "balldontlie", "apiKeySlot": "secondary"}
+	})
+
+	rr := testutil.Serve(h, http.MethodGet, "/status", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+
+	var resp struct {
+		Poller   poller.Status     `json:"poller"`
+		Provider map[string]string `json:"provider"`
+	}
+	testutil.DecodeJSON(t, rr, &resp)
+	if resp.Poller.ConsecutiveFailures != 2 || resp.Poller.LastError != "boom" {
+		t.Fatalf("unexpected poller section %+v", resp.Poller)
+	}
+	if resp.Provider["apiKeySlot"] != "secondary" {
+		t.Fatalf("unexpected provider section %+v", resp.Provider)
+	}
+}
+
+func TestStatusWithoutSectionsReturnsEmptyObject(t *testing.T) {
+	h := newHandler(nil, nil)
+	h.RegisterStatus("", func() any { return "ignored" })
+	h.RegisterStatus("nil", nil)
+
+	rr := testutil.Serve(h, http.MethodGet, "/status", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+
+	var resp map[string]any
+	testutil.DecodeJSON(t, rr, &resp)
+	if len(resp) != 0 {
+		t.Fatalf("expected empty status, got %+v", resp)
+	}
+}
+
+func TestStatusRejectsNonGet(t *testing.T) {
+	h := newHandler(nil, nil)
+	rr := testutil.Serve(h, http.MethodPost, "/status", nil)
+	testutil.AssertStatus(t, rr, http.StatusMethodNotAllowed)
+}
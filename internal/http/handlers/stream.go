@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// SSE event names sent on /games/stream. StreamEventClose is the last event on
+// a stream the server ends; it carries no id, so a reconnect resumes from the
+// last event actually delivered.
+const (
+	StreamEventSnapshot = "snapshot"
+	StreamEventGame     = "game"
+	StreamEventClose    = "close"
+)
+
+// StreamCloseSlowConsumer is the close reason for a client that fell too far behind.
+const StreamCloseSlowConsumer = "slow_consumer"
+
+const (
+	defaultMaxStreams  = 100
+	streamHeartbeat    = 15 * time.Second
+	streamBuffer       = 32  // events queued per connection before drops
+	streamReplay       = 256 // recent events kept for Last-Event-ID resumes
+	streamDropLimit    = 64  // drops after which a connection is closed
+	streamWriteTimeout = 30 * time.Second
+)
+
+// StreamClose is the data payload of a "close" event.
+type StreamClose struct {
+	Reason  string `json:"reason"`
+	Dropped int    `json:"dropped"`
+}
+
+// StreamUpdate is the data payload of a "game" event.
+type StreamUpdate struct {
+	Kind string           `json:"kind"` // poller.ChangeAdded, ChangeStatus, or ChangeScore
+	Game domaingames.Game `json:"game"`
+}
+
+type streamEvent struct {
+	id   uint64
+	name string
+	data []byte
+}
+
+// StreamHandler serves GET /games/stream as Server-Sent Events. Each connection
+// gets today's snapshot followed by game updates published from the poller.
+type StreamHandler struct {
+	snaps      snapshots.Store
+	logger     *slog.Logger
+	clock      clock.Clock
+	loc        *time.Location
+	maxStreams int
+	heartbeat  time.Duration
+	dropLimit  int
+	recorder   *metrics.Recorder
+
+	mu           sync.Mutex
+	seq          uint64
+	recent       []streamEvent
+	subs         map[*streamSub]struct{}
+	dropped      int
+	forcedCloses int
+}
+
+// NewStreamHandler constructs a StreamHandler allowing at most maxStreams concurrent connections.
+func NewStreamHandler(snaps snapshots.Store, logger *slog.Logger, maxStreams int, loc *time.Location, clk clock.Clock) *StreamHandler {
+	if maxStreams <= 0 {
+		maxStreams = defaultMaxStreams
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &StreamHandler{
+		snaps:      snaps,
+		logger:     logger,
+		clock:      clock.OrReal(clk),
+		loc:        loc,
+		maxStreams: maxStreams,
+		heartbeat:  streamHeartbeat,
+		dropLimit:  streamDropLimit,
+		subs:       make(map[*streamSub]struct{}),
+	}
+}
+
+// SetRecorder makes the handler report drops, forced closes, and open
+// connections on rec.
+func (h *StreamHandler) SetRecorder(rec *metrics.Recorder) {
+	h.recorder = rec
+}
+
+// Publish fans a poller change out to connected streams. It never blocks; a
+// connection whose buffer is full misses the event, and one that misses
+// dropLimit events is closed with a "close" event so the client reconnects and
+// resyncs. Suitable for passing directly to Poller.OnChange.
+func (h *StreamHandler) Publish(ev poller.GameChangeEvent) {
+	data, err := json.Marshal(StreamUpdate{Kind: ev.Kind, Game: ev.Game})
+	if err != nil {
+		logging.Error(h.logger, "stream event encode failed", err, "game_id", ev.GameID)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seq++
+	event := streamEvent{id: h.seq, name: StreamEventGame, data: data}
+	h.recent = append(h.recent, event)
+	if len(h.recent) > streamReplay {
+		h.recent = h.recent[len(h.recent)-streamReplay:]
+	}
+	for sub := range h.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			h.dropLocked(sub, event.id)
+		}
+	}
+}
+
+// dropLocked counts a missed event for sub and kicks it past the drop limit.
+func (h *StreamHandler) dropLocked(sub *streamSub, eventID uint64) {
+	sub.dropped++
+	h.dropped++
+	h.recorder.RecordStreamDrop()
+	if sub.kicked || sub.dropped < h.dropLimit {
+		logging.Warn(h.logger, "stream client too slow, dropping event", "event_id", eventID, "dropped", sub.dropped)
+		return
+	}
+	sub.kicked = true
+	close(sub.kick)
+	delete(h.subs, sub)
+	h.recorder.SetStreamConnections(len(h.subs))
+	h.forcedCloses++
+	h.recorder.RecordStreamForcedClose()
+	logging.Warn(h.logger, "stream client too slow, closing", "event_id", eventID, "dropped", sub.dropped)
+}
+
+// Active returns the number of open stream connections.
+func (h *StreamHandler) Active() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+// Status reports connection usage and drop totals for /status.
+func (h *StreamHandler) Status() any {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return map[string]int{
+		"active":       len(h.subs),
+		"max":          h.maxStreams,
+		"dropped":      h.dropped,
+		"forcedCloses": h.forcedCloses,
+	}
+}
+
+func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !validateQuery(w, r, "/games/stream", h.logger) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, r, http.StatusInternalServerError, CodeInternal, "streaming unsupported", h.logger)
+		return
+	}
+
+	sub, ok := h.subscribe(r.Header.Get("Last-Event-ID"))
+	if !ok {
+		writeError(w, r, http.StatusServiceUnavailable, CodeTooManyStreams, "too many streams", h.logger)
+		return
+	}
+	defer h.unsubscribe(sub)
+
+	logger := loggerFromContext(r, h.logger)
+	// Streams outlive the server's WriteTimeout; each write gets its own
+	// deadline instead so a client that stops reading cannot pin the handler.
+	rc := http.NewResponseController(w)
+	extend := func() { _ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)) }
+	extend()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	replay := sub.replay
+	if !sub.resumed {
+		replay = []streamEvent{h.snapshotEvent(r.Context(), sub.seq)}
+	}
+	for _, event := range replay {
+		if err := writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+	logging.Info(logger, "stream opened", "resumed", sub.resumed)
+
+	heartbeat := h.clock.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		// A kicked client is closed even while events are still queued.
+		select {
+		case <-sub.kick:
+			h.closeSlow(w, flusher, sub, logger)
+			return
+		default:
+		}
+		select {
+		case <-r.Context().Done():
+			logging.Info(logger, "stream closed")
+			return
+		case <-sub.kick:
+			h.closeSlow(w, flusher, sub, logger)
+			return
+		case event := <-sub.ch:
+			extend()
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C():
+			extend()
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// closeSlow ends a kicked stream with a "close" event naming the reason.
+func (h *StreamHandler) closeSlow(w http.ResponseWriter, flusher http.Flusher, sub *streamSub, logger *slog.Logger) {
+	h.mu.Lock()
+	dropped := sub.dropped
+	h.mu.Unlock()
+	data, _ := json.Marshal(StreamClose{Reason: StreamCloseSlowConsumer, Dropped: dropped})
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", StreamEventClose, data); err == nil {
+		flusher.Flush()
+	}
+	logging.Warn(logger, "stream closed for slow consumer", "dropped", dropped)
+}
+
+// streamSub is a registered connection and what it must send before live events.
+type streamSub struct {
+	ch      chan streamEvent
+	seq     uint64        // sequence at registration; the snapshot's event ID
+	replay  []streamEvent // missed events when resumed
+	resumed bool
+
+	// Guarded by StreamHandler.mu; kick is closed once dropped reaches the limit.
+	dropped int
+	kicked  bool
+	kick    chan struct{}
+}
+
+// subscribe registers a connection. When lastEventID still falls inside the replay
+// window the missed events are returned with resumed set; otherwise the caller sends
+// a fresh snapshot. Registration and replay share one lock so no event is lost.
+func (h *StreamHandler) subscribe(lastEventID string) (*streamSub, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs) >= h.maxStreams {
+		return nil, false
+	}
+	sub := &streamSub{ch: make(chan streamEvent, streamBuffer), seq: h.seq, kick: make(chan struct{})}
+	h.subs[sub] = struct{}{}
+	h.recorder.SetStreamConnections(len(h.subs))
+
+	last, err := strconv.ParseUint(strings.TrimSpace(lastEventID), 10, 64)
+	if err != nil || last > h.seq {
+		return sub, true
+	}
+	oldest := h.seq + 1
+	if len(h.recent) > 0 {
+		oldest = h.recent[0].id
+	}
+	if last+1 < oldest {
+		return sub, true
+	}
+	for _, event := range h.recent {
+		if event.id > last {
+			sub.replay = append(sub.replay, event)
+		}
+	}
+	sub.resumed = true
+	return sub, true
+}
+
+func (h *StreamHandler) unsubscribe(sub *streamSub) {
+	h.mu.Lock()
+	delete(h.subs, sub)
+	h.recorder.SetStreamConnections(len(h.subs))
+	h.mu.Unlock()
+}
+
+func (h *StreamHandler) snapshotEvent(ctx context.Context, id uint64) streamEvent {
+	today := timeutil.DateIn(h.clock.Now(), h.loc)
+	snap := domaingames.NewTodayResponse(today, []domaingames.Game{})
+	if h.snaps != nil {
+		if loaded, err := h.snaps.LoadGames(ctx, today); err == nil {
+			snap = loaded
+		}
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		logging.Error(h.logger, "stream snapshot encode failed", err)
+		data = []byte("{}")
+	}
+	return streamEvent{id: id, name: StreamEventSnapshot, data: data}
+}
+
+func writeStreamEvent(w http.ResponseWriter, event streamEvent) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.id, event.name, event.data)
+	return err
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+// flushRecorder is a goroutine-safe ResponseWriter that records flushed output.
+type flushRecorder struct {
+	mu      sync.Mutex
+	header  http.Header
+	status  int
+	body    bytes.Buffer
+	flushed string
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{header: http.Header{}}
+}
+
+func (f *flushRecorder) Header() http.Header { return f.header }
+
+func (f *flushRecorder) WriteHeader(status int) {
+	f.mu.Lock()
+	f.status = status
+	f.mu.Unlock()
+}
+
+func (f *flushRecorder) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.body.Write(p)
+}
+
+func (f *flushRecorder) Flush() {
+	f.mu.Lock()
+	f.flushed = f.body.String()
+	f.mu.Unlock()
+}
+
+// waitFor blocks until the flushed output contains want.
+func (f *flushRecorder) waitFor(t *testing.T, want string) string {
+	t.Helper()
+	var out string
+	testutil.WaitFor(t, 2*time.Second, func() bool {
+		f.mu.Lock()
+		out = f.flushed
+		f.mu.Unlock()
+		return strings.Contains(out, want)
+	}, "waiting for %q in stream", want)
+	return out
+}
+
+var streamNow = time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+
+// openStream serves one stream request in the background; cancel disconnects the client.
+func openStream(h *StreamHandler, lastEventID string) (rec *flushRecorder, cancel func(), done <-chan struct{}) {
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/games/stream", nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	rec = newFlushRecorder()
+	finished := make(chan struct{})
+	go func() {
+		h.ServeHTTP(rec, req)
+		close(finished)
+	}()
+	return rec, cancelCtx, finished
+}
+
+func waitClosed(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("stream handler did not return after disconnect")
+	}
+}
+
+func finalChange(id string) poller.GameChangeEvent {
+	game := testutil.SampleGame(id)
+	game.StatusKind = domaingames.StatusFinal
+	return poller.GameChangeEvent{Kind: poller.ChangeStatus, GameID: id, Game: game}
+}
+
+func TestStreamSendsSnapshotThenUpdates(t *testing.T) {
+	date := "2024-01-15"
+	store := storeWithGames(date, []domaingames.Game{testutil.SampleGame("g1")})
+	h := NewStreamHandler(store, nil, 0, nil, testutil.NewFakeClock(streamNow))
+
+	rec, cancel, done := openStream(h, "")
+	out := rec.waitFor(t, "event: snapshot\n")
+	if !strings.HasPrefix(out, "id: 0\nevent: snapshot\ndata: {\"date\":\"2024-01-15\"") {
+		t.Fatalf("unexpected snapshot framing %q", out)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected event-stream content type, got %q", got)
+	}
+
+	h.Publish(finalChange("g1"))
+	out = rec.waitFor(t, "event: game\n")
+	if !strings.Contains(out, "id: 1\nevent: game\ndata: {\"kind\":\"status\",\"game\":{\"id\":\"g1\"") ||
+		!strings.HasSuffix(out, "\n\n") {
+		t.Fatalf("unexpected update framing %q", out)
+	}
+
+	cancel()
+	waitClosed(t, done)
+	if h.Active() != 0 {
+		t.Fatalf("expected disconnected stream cleaned up, got %d active", h.Active())
+	}
+}
+
+func TestStreamResumesFromLastEventID(t *testing.T) {
+	h := NewStreamHandler(nil, nil, 0, nil, testutil.NewFakeClock(streamNow))
+	h.Publish(finalChange("g1"))
+	h.Publish(finalChange("g2"))
+	h.Publish(finalChange("g3"))
+
+	rec, cancel, done := openStream(h, "1")
+	out := rec.waitFor(t, "id: 3\n")
+	if strings.Contains(out, "event: snapshot") || strings.Contains(out, "id: 1\n") {
+		t.Fatalf("expected only missed events on resume, got %q", out)
+	}
+	if !strings.Contains(out, "id: 2\n") {
+		t.Fatalf("expected event 2 replayed, got %q", out)
+	}
+	cancel()
+	waitClosed(t, done)
+
+	// An ID outside the replay window falls back to a fresh snapshot.
+	rec, cancel, done = openStream(h, "99")
+	out = rec.waitFor(t, "event: snapshot\n")
+	if !strings.HasPrefix(out, "id: 3\n") {
+		t.Fatalf("expected snapshot tagged with current sequence, got %q", out)
+	}
+	cancel()
+	waitClosed(t, done)
+}
+
+func TestStreamSendsHeartbeats(t *testing.T) {
+	clk := testutil.NewFakeClock(streamNow)
+	h := NewStreamHandler(nil, nil, 0, nil, clk)
+
+	rec, cancel, done := openStream(h, "")
+	defer func() {
+		cancel()
+		waitClosed(t, done)
+	}()
+	rec.waitFor(t, "event: snapshot\n")
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatalf("expected heartbeat ticker registered")
+	}
+	clk.Advance(streamHeartbeat)
+	rec.waitFor(t, ": heartbeat\n\n")
+}
+
+func TestStreamRejectsOverCapacity(t *testing.T) {
+	h := NewStreamHandler(nil, nil, 1, nil, testutil.NewFakeClock(streamNow))
+	rec, cancel, done := openStream(h, "")
+	defer func() {
+		cancel()
+		waitClosed(t, done)
+	}()
+	rec.waitFor(t, "event: snapshot\n")
+
+	rr := testutil.Serve(h, http.MethodGet, "/games/stream", nil)
+	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
+	if status := h.Status().(map[string]int); status["active"] != 1 || status["max"] != 1 {
+		t.Fatalf("unexpected stream status %v", status)
+	}
+}
+
+func TestStreamRejectsNonGet(t *testing.T) {
+	h := NewStreamHandler(nil, nil, 0, nil, nil)
+	mux := NewMux(nil)
+	mux.HandleGetOnly("GET /games/stream", h)
+	for _, method := range []string{http.MethodPost, http.MethodHead} {
+		rr := testutil.Serve(mux, method, "/games/stream", nil)
+		testutil.AssertStatus(t, rr, http.StatusMethodNotAllowed)
+		if allow := rr.Header().Get("Allow"); allow != "GET" {
+			t.Fatalf("%s: expected Allow: GET, got %q", method, allow)
+		}
+	}
+}
+
+type noFlushWriter struct{ http.ResponseWriter }
+
+func TestStreamRequiresFlusher(t *testing.T) {
+	h := NewStreamHandler(nil, nil, 0, nil, nil)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(noFlushWriter{rr}, httptest.NewRequest(http.MethodGet, "/games/stream", nil))
+	testutil.AssertStatus(t, rr, http.StatusInternalServerError)
+}
+
+// stallingRecorder blocks writes once stall is called until release, like a
+// client that stopped reading with its socket buffer full.
+type stallingRecorder struct {
+	*flushRecorder
+	stalled atomic.Bool
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (s *stallingRecorder) stall() { s.stalled.Store(true) }
+
+func (s *stallingRecorder) Write(p []byte) (int, error) {
+	if s.stalled.CompareAndSwap(true, false) {
+		close(s.blocked)
+		<-s.release
+	}
+	return s.flushRecorder.Write(p)
+}
+
+func TestStreamClosesSlowConsumerAfterDropLimit(t *testing.T) {
+	rec := metrics.NewRecorder()
+	h := NewStreamHandler(nil, nil, 0, nil, testutil.NewFakeClock(streamNow))
+	h.SetRecorder(rec)
+	h.dropLimit = 3
+
+	w := &stallingRecorder{flushRecorder: newFlushRecorder(), blocked: make(chan struct{}), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/games/stream", nil))
+		close(done)
+	}()
+	w.waitFor(t, "event: snapshot\n")
+
+	w.stall()
+	h.Publish(finalChange("g0"))
+	<-w.blocked // handler is stuck writing g0
+	for i := 0; i < streamBuffer+h.dropLimit; i++ {
+		h.Publish(finalChange("g1"))
+	}
+
+	status := h.Status().(map[string]int)
+	if status["dropped"] != 3 || status["forcedCloses"] != 1 || status["active"] != 0 {
+		t.Fatalf("unexpected stream status after drops %v", status)
+	}
+	if dropped, closes := rec.StreamStats(); dropped != 3 || closes != 1 {
+		t.Fatalf("expected recorder to count 3 drops and 1 close, got %d/%d", dropped, closes)
+	}
+
+	close(w.release)
+	waitClosed(t, done)
+	out := w.waitFor(t, "event: close\n")
+	if !strings.HasSuffix(out, "event: close\ndata: {\"reason\":\"slow_consumer\",\"dropped\":3}\n\n") {
+		t.Fatalf("expected slow consumer close event last, got %q", out)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	nethttp "net/http"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+)
+
+// WithTimeout bounds next to d: the request context gets a deadline, and if
+// next has not finished by then a 504 REQUEST_TIMEOUT is written and anything
+// next writes afterwards is discarded. A request canceled earlier still gets
+// whatever next writes in response. next's output is buffered until it
+// returns, so WithTimeout is unsuitable for streaming handlers. d <= 0
+// returns next unchanged.
+func WithTimeout(d time.Duration, logger *slog.Logger, next nethttp.Handler) nethttp.Handler {
+	if d <= 0 {
+		return next
+	}
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), d)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		buf := newBufferedResponse()
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicked <- p
+				}
+			}()
+			next.ServeHTTP(buf, r)
+			close(done)
+		}()
+
+		deadline := time.NewTimer(d)
+		defer deadline.Stop()
+		select {
+		case p := <-panicked:
+			panic(p)
+		case <-done:
+			buf.writeTo(w)
+		case <-deadline.C:
+			logging.Warn(loggerFromContext(r, logger), "request timed out", "timeout_ms", d.Milliseconds())
+			writeError(w, r, nethttp.StatusGatewayTimeout, CodeRequestTimeout, "request timed out", logger)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func TestWithTimeoutAnswersSlowHandlerWith504(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rr := testutil.Serve(WithTimeout(20*time.Millisecond, nil, slow), http.MethodGet, "/games", nil)
+	testutil.AssertStatus(t, rr, http.StatusGatewayTimeout)
+	if body := decodeError(t, rr); body.Error.Code != CodeRequestTimeout {
+		t.Fatalf("expected %s, got %+v", CodeRequestTimeout, body.Error)
+	}
+}
+
+func TestWithTimeoutPassesFastResponsesThrough(t *testing.T) {
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Errorf("expected request context to carry a deadline")
+		}
+		w.Header().Set("X-Test", "1")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	rr := testutil.Serve(WithTimeout(time.Second, nil, fast), http.MethodGet, "/games", nil)
+	testutil.AssertStatus(t, rr, http.StatusTeapot)
+	if rr.Header().Get("X-Test") != "1" || rr.Body.String() != "ok" {
+		t.Fatalf("expected buffered response copied through, got headers %v body %q", rr.Header(), rr.Body.String())
+	}
+}
+
+func TestWithTimeoutDisabledReturnsHandler(t *testing.T) {
+	rr := testutil.Serve(WithTimeout(0, nil, http.NotFoundHandler()), http.MethodGet, "/x", nil)
+	testutil.AssertStatus(t, rr, http.StatusNotFound)
+}
+
+func TestWithTimeoutKeepsHandlerResponseToCanceledRequest(t *testing.T) {
+	h := newHandler(nil, nil)
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	ctx, cancel := context.WithCancel(req.Context())
+	cancel()
+
+	rr := testutil.ServeRequest(WithTimeout(time.Second, nil, h), req.WithContext(ctx))
+	testutil.AssertStatus(t, rr, http.StatusServiceUnavailable)
+	if body := decodeError(t, rr); body.Error.Code != CodeShuttingDown {
+		t.Fatalf("expected %s, got %+v", CodeShuttingDown, body.Error)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+)
+
+// upstreamFailure is how an upstream error is reported to clients.
+type upstreamFailure struct {
+	status     int
+	code       ErrorCode
+	retryAfter int // seconds; set for rate limits when upstream said
+}
+
+// classifyUpstream maps an upstream error to a response: deadlines and network
+// timeouts are 504, rate limits 429, and everything else (refused connections,
+// upstream 5xx, unknown failures) 502. A rate limit whose Retry-After outlasted
+// the request (providers.ErrRetryAfterExceedsDeadline) is still a 429 with the
+// upstream Retry-After, even though it also carries the deadline.
+func classifyUpstream(err error) upstreamFailure {
+	if rlErr, ok := providers.AsRateLimitError(err); ok {
+		f := upstreamFailure{status: http.StatusTooManyRequests, code: CodeRateLimited}
+		if rlErr.RetryAfter > 0 {
+			f.retryAfter = int(math.Ceil(rlErr.RetryAfter.Seconds()))
+		}
+		return f
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return upstreamFailure{status: http.StatusGatewayTimeout, code: CodeUpstreamTimeout}
+	}
+	return upstreamFailure{status: http.StatusBadGateway, code: CodeUpstreamUnavailable}
+}
+
+// writeUpstreamError reports err using classifyUpstream's status and code.
+func writeUpstreamError(w http.ResponseWriter, r *http.Request, err error, message string, logger *slog.Logger) {
+	writeFailure(w, r, classifyUpstream(err), message, logger)
+}
+
+func writeFailure(w http.ResponseWriter, r *http.Request, f upstreamFailure, message string, logger *slog.Logger) {
+	if f.retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(f.retryAfter))
+	}
+	writeError(w, r, f.status, f.code, message, logger)
+}
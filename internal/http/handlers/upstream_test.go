@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+var upstreamCases = []struct {
+	name       string
+	err        error
+	status     int
+	code       ErrorCode
+	retryAfter string
+}{
+	{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, CodeUpstreamTimeout, ""},
+	{"wrapped deadline", fmt.Errorf("fetch page 2: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, CodeUpstreamTimeout, ""},
+	{"net timeout", &net.OpError{Op: "read", Err: timeoutErr{}}, http.StatusGatewayTimeout, CodeUpstreamTimeout, ""},
+	{"refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, http.StatusBadGateway, CodeUpstreamUnavailable, ""},
+	{"upstream 5xx", errors.New("balldontlie: unexpected status 503: down"), http.StatusBadGateway, CodeUpstreamUnavailable, ""},
+	{"rate limited", &providers.RateLimitError{StatusCode: 429, RetryAfter: 1500 * time.Millisecond}, http.StatusTooManyRequests, CodeRateLimited, "2"},
+	{"rate limited no retry-after", fmt.Errorf("retry: %w", &providers.RateLimitError{StatusCode: 429}), http.StatusTooManyRequests, CodeRateLimited, ""},
+	{"retry-after past deadline", fmt.Errorf("%w: %w", providers.ErrRetryAfterExceedsDeadline, &providers.RateLimitError{StatusCode: 429, RetryAfter: time.Minute}), http.StatusTooManyRequests, CodeRateLimited, "60"},
+	{"retry-after past deadline and timed out", fmt.Errorf("%w: %w: %w", providers.ErrRetryAfterExceedsDeadline, &providers.RateLimitError{StatusCode: 429, RetryAfter: 30 * time.Second}, context.DeadlineExceeded), http.StatusTooManyRequests, CodeRateLimited, "30"},
+}
+
+func TestGamesUpstreamErrorMapping(t *testing.T) {
+	for _, tc := range upstreamCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHandler(&teststubs.StubSnapshotStore{LoadErr: tc.err}, nil)
+			h.clock = testutil.NewFakeClock(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+
+			rr := testutil.Serve(h, http.MethodGet, "/games?date=2024-02-01", nil)
+			testutil.AssertStatus(t, rr, tc.status)
+			body := decodeError(t, rr)
+			if body.Error.Code != tc.code || body.Error.Message != "snapshot unavailable" {
+				t.Fatalf("unexpected error body %+v", body)
+			}
+			if got := rr.Header().Get("Retry-After"); got != tc.retryAfter {
+				t.Fatalf("expected Retry-After %q, got %q", tc.retryAfter, got)
+			}
+		})
+	}
+}
+
+func TestAdminRefreshUpstreamErrorMapping(t *testing.T) {
+	for _, tc := range upstreamCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &teststubs.StubProvider{Err: tc.err}
+			h := NewAdminHandler(snapshots.NewWriter(t.TempDir(), 1), provider, "secret", nil)
+
+			rr := callRefresh(t, h, http.MethodPost, "/admin/snapshots/refresh?date=2024-01-01", "secret")
+			testutil.AssertStatus(t, rr, tc.status)
+			body := decodeError(t, rr)
+			if body.Error.Code != tc.code || body.Error.Message != "failed to fetch games" {
+				t.Fatalf("unexpected error body %+v", body)
+			}
+		})
+	}
+}
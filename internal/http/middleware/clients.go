@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+)
+
+const (
+	// ClientNameHeader optionally identifies the calling consumer.
+	ClientNameHeader = "X-Client-Name"
+
+	// ClientAnonymous labels requests with a missing or malformed client name.
+	ClientAnonymous = "anonymous"
+	// ClientOther labels well-formed client names that are not allowlisted.
+	ClientOther = "other"
+
+	maxClientNameLen    = 64
+	defaultClientWindow = time.Minute
+)
+
+// ClientTracker resolves X-Client-Name to a bounded set of labels and keeps a
+// sliding per-second window of request counts per label.
+type ClientTracker struct {
+	allowed map[string]struct{}
+	window  time.Duration
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	windows map[string]*slidingWindow
+}
+
+// ClientRate is the recent request rate for one client label.
+type ClientRate struct {
+	Requests      int64   `json:"requests"`
+	PerSecond     float64 `json:"perSecond"`
+	WindowSeconds int     `json:"windowSeconds"`
+}
+
+// NewClientTracker builds a tracker for the allowlisted client names.
+// A zero window defaults to one minute; a nil clock uses the real clock.
+func NewClientTracker(allowed []string, window time.Duration, clk clock.Clock) *ClientTracker {
+	if window < time.Second {
+		window = defaultClientWindow
+	}
+	set := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		if clean, ok := sanitizeClientName(name); ok {
+			set[clean] = struct{}{}
+		}
+	}
+	return &ClientTracker{
+		allowed: set,
+		window:  window,
+		clock:   clock.OrReal(clk),
+		windows: make(map[string]*slidingWindow),
+	}
+}
+
+// Identify maps the request's X-Client-Name to an allowlisted name, "other", or "anonymous".
+func (t *ClientTracker) Identify(r *http.Request) string {
+	if t == nil || r == nil {
+		return ClientAnonymous
+	}
+	name, ok := sanitizeClientName(r.Header.Get(ClientNameHeader))
+	if !ok {
+		return ClientAnonymous
+	}
+	if _, allowed := t.allowed[name]; !allowed {
+		return ClientOther
+	}
+	return name
+}
+
+// Record counts one request for the client label.
+func (t *ClientTracker) Record(client string) {
+	if t == nil {
+		return
+	}
+	now := t.clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.windows[client]
+	if !ok {
+		w = newSlidingWindow(int(t.window / time.Second))
+		t.windows[client] = w
+	}
+	w.add(now)
+}
+
+// Rates reports per-client request rates over the sliding window.
+func (t *ClientTracker) Rates() map[string]ClientRate {
+	out := make(map[string]ClientRate)
+	if t == nil {
+		return out
+	}
+	now := t.clock.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.windows))
+	for name := range t.windows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seconds := int(t.window / time.Second)
+	for _, name := range names {
+		count := t.windows[name].total(now)
+		if count == 0 {
+			continue
+		}
+		out[name] = ClientRate{
+			Requests:      count,
+			PerSecond:     float64(count) / float64(seconds),
+			WindowSeconds: seconds,
+		}
+	}
+	return out
+}
+
+// Status is a /status section reporting per-client rates.
+func (t *ClientTracker) Status() any {
+	return t.Rates()
+}
+
+// sanitizeClientName lowercases and validates a client name; only [a-z0-9._-] is accepted.
+func sanitizeClientName(raw string) (string, bool) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+	if name == "" || len(name) > maxClientNameLen {
+		return "", false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return "", false
+		}
+	}
+	if name == ClientAnonymous || name == ClientOther {
+		return "", false
+	}
+	return name, true
+}
+
+// slidingWindow keeps one counter per second for the last len(buckets) seconds.
+type slidingWindow struct {
+	buckets []int64
+	stamps  []int64 // unix second each bucket currently holds
+}
+
+func newSlidingWindow(seconds int) *slidingWindow {
+	return &slidingWindow{
+		buckets: make([]int64, seconds),
+		stamps:  make([]int64, seconds),
+	}
+}
+
+func (w *slidingWindow) add(at time.Time) {
+	sec := at.Unix()
+	i := int(sec % int64(len(w.buckets)))
+	if w.stamps[i] != sec {
+		w.stamps[i] = sec
+		w.buckets[i] = 0
+	}
+	w.buckets[i]++
+}
+
+func (w *slidingWindow) total(now time.Time) int64 {
+	oldest := now.Unix() - int64(len(w.buckets)) + 1
+	var sum int64
+	for i, stamp := range w.stamps {
+		if stamp >= oldest && stamp <= now.Unix() {
+			sum += w.buckets[i]
+		}
+	}
+	return sum
+}
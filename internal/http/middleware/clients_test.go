@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+)
+
+func requestWithClient(name string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/games", nil)
+	if name != "" {
+		req.Header.Set(ClientNameHeader, name)
+	}
+	return req
+}
+
+func TestClientTrackerIdentify(t *testing.T) {
+	tracker := NewClientTracker([]string{"bff", "iOS-App", "bad name"}, 0, nil)
+
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "allowlisted", header: "bff", want: "bff"},
+		{name: "allowlisted case-insensitive", header: "  IOS-app ", want: "ios-app"},
+		{name: "unlisted", header: "scraper", want: ClientOther},
+		{name: "missing", header: "", want: ClientAnonymous},
+		{name: "malformed characters", header: "bff; drop table", want: ClientAnonymous},
+		{name: "too long", header: strings.Repeat("a", maxClientNameLen+1), want: ClientAnonymous},
+		{name: "reserved label", header: "other", want: ClientAnonymous},
+		{name: "invalid allowlist entry ignored", header: "bad name", want: ClientAnonymous},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tracker.Identify(requestWithClient(tc.header)); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	var nilTracker *ClientTracker
+	if got := nilTracker.Identify(requestWithClient("bff")); got != ClientAnonymous {
+		t.Fatalf("expected nil tracker to report anonymous, got %q", got)
+	}
+	nilTracker.Record("bff")
+	if len(nilTracker.Rates()) != 0 {
+		t.Fatalf("expected nil tracker to report no rates")
+	}
+}
+
+func TestClientTrackerSlidingWindow(t *testing.T) {
+	clk := testutil.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	tracker := NewClientTracker([]string{"bff"}, 10*time.Second, clk)
+
+	for i := 0; i < 5; i++ {
+		tracker.Record("bff")
+	}
+	clk.Advance(5 * time.Second)
+	tracker.Record("bff")
+	tracker.Record(ClientAnonymous)
+
+	rates := tracker.Rates()
+	if got := rates["bff"]; got.Requests != 6 || got.WindowSeconds != 10 || got.PerSecond != 0.6 {
+		t.Fatalf("unexpected bff rate %+v", got)
+	}
+	if got := rates[ClientAnonymous]; got.Requests != 1 {
+		t.Fatalf("unexpected anonymous rate %+v", got)
+	}
+
+	// The first burst ages out of the window.
+	clk.Advance(6 * time.Second)
+	if got := tracker.Rates()["bff"].Requests; got != 1 {
+		t.Fatalf("expected 1 request left in window, got %d", got)
+	}
+	clk.Advance(time.Minute)
+	if len(tracker.Rates()) != 0 {
+		t.Fatalf("expected idle clients dropped from status, got %v", tracker.Rates())
+	}
+}
+
+func TestLoggingMiddlewareLabelsClient(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	tracker := NewClientTracker([]string{"bff"}, 0, nil)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := LoggingMiddlewareWithClients(logger, metrics.NewRecorder(), tracker, next)
+
+	handler.ServeHTTP(httptest.NewRecorder(), requestWithClient("bff"))
+	handler.ServeHTTP(httptest.NewRecorder(), requestWithClient("unknown-app"))
+	handler.ServeHTTP(httptest.NewRecorder(), requestWithClient(""))
+
+	logs := buf.String()
+	for _, want := range []string{"client_name=bff", "client_name=other", "client_name=anonymous"} {
+		if !strings.Contains(logs, want) {
+			t.Fatalf("expected log to contain %q, got %s", want, logs)
+		}
+	}
+	rates, ok := tracker.Status().(map[string]ClientRate)
+	if !ok || rates["bff"].Requests != 1 || rates[ClientOther].Requests != 1 || rates[ClientAnonymous].Requests != 1 {
+		t.Fatalf("unexpected client rates %v", tracker.Status())
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/http/requestutil"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
@@ -14,41 +17,113 @@ import (
 
 // LoggingMiddleware wraps the handler with request logging, request ID support, and metrics.
 func LoggingMiddleware(baseLogger *slog.Logger, recorder *metrics.Recorder, next http.Handler) http.Handler {
+	return LoggingMiddlewareWithClients(baseLogger, recorder, nil, next)
+}
+
+// LoggingMiddlewareWithClients is identical to LoggingMiddleware but labels logs and
+// metrics with the caller's X-Client-Name as resolved by clients.
+func LoggingMiddlewareWithClients(baseLogger *slog.Logger, recorder *metrics.Recorder, clients *ClientTracker, next http.Handler) http.Handler {
+	return LoggingMiddlewareWithOptions(baseLogger, recorder, LoggingOptions{Clients: clients}, next)
+}
+
+// LoggingOptions tunes request logging; the zero value logs every request at Info.
+type LoggingOptions struct {
+	Clients *ClientTracker
+	// SampleRate logs 1 in N successful (< 400) requests; <= 1 logs them all.
+	// Errors and slow requests are always logged.
+	SampleRate int
+	// SlowThreshold elevates requests at least this slow to Warn with the
+	// route and any upstream time recorded via RecordUpstreamLatency; 0 disables.
+	SlowThreshold time.Duration
+	// Clock times each request; nil uses the real clock.
+	Clock clock.Clock
+	// RedactQueryParams names query parameters, matched case-insensitively,
+	// whose values are logged as RedactedValue in addition to
+	// DefaultRedactedQueryParams.
+	RedactQueryParams []string
+}
+
+// DefaultRedactedQueryParams are the query parameters whose values are never
+// logged, so credentials passed in the URL stay out of request logs.
+var DefaultRedactedQueryParams = []string{"token", "key", "apikey", "authorization"}
+
+// RedactedValue replaces the logged value of a redacted query parameter.
+const RedactedValue = "REDACTED"
+
+// MaxLoggedQueryBytes caps the logged query string; longer ones are cut and
+// marked with a trailing "...".
+const MaxLoggedQueryBytes = 512
+
+// LoggingMiddlewareWithOptions is LoggingMiddlewareWithClients with sampling
+// and slow-request flagging. Metrics are recorded for every request regardless.
+func LoggingMiddlewareWithOptions(baseLogger *slog.Logger, recorder *metrics.Recorder, opts LoggingOptions, next http.Handler) http.Handler {
 	if baseLogger == nil {
 		baseLogger = slog.Default()
 	}
+	clients := opts.Clients
+	clk := clock.OrReal(opts.Clock)
+	var seen atomic.Uint64
+	redact := make(map[string]bool, len(DefaultRedactedQueryParams)+len(opts.RedactQueryParams))
+	for _, name := range append(append([]string{}, DefaultRedactedQueryParams...), opts.RedactQueryParams...) {
+		redact[strings.ToLower(strings.TrimSpace(name))] = true
+	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
+		start := clk.Now()
 		reqID := requestutil.SanitizeRequestID(r.Header.Get("X-Request-ID"))
 		w.Header().Set("X-Request-ID", reqID)
 
 		clientIP := requestutil.ClientIP(r)
+		clientName := clients.Identify(r)
 
 		logger := baseLogger.With(
 			slog.String("request_id", reqID),
 			slog.String("method", r.Method),
 			slog.String("path", r.URL.Path),
-			slog.String("query", r.URL.RawQuery),
+			slog.String("query", sanitizeQuery(r.URL.RawQuery, redact)),
 			slog.String("client_ip", clientIP),
+			slog.String("client_name", clientName),
 		)
 
+		upstream := &upstreamLatency{}
 		ctx := logging.WithLogger(r.Context(), logger)
 		ctx = withRequestID(ctx, reqID)
+		ctx = context.WithValue(ctx, upstreamLatencyKey{}, upstream)
+		matched := &matchedRoute{}
+		ctx = context.WithValue(ctx, matchedRouteKey{}, matched)
 		r = r.WithContext(ctx)
 		ww := &responseWriter{ResponseWriter: w, status: http.StatusOK}
 
 		next.ServeHTTP(ww, r)
 
-		duration := time.Since(start)
+		duration := clk.Now().Sub(start)
+		route := routeLabel(r, matched)
 		if recorder != nil {
-			recorder.RecordHTTPRequest(r.Method, normalizePath(r.URL.Path), ww.status, duration)
+			recorder.RecordHTTPRequestForClient(r.Method, route, clientName, ww.status, duration)
 		}
+		clients.Record(clientName)
 
-		logger.Info("request complete",
+		attrs := []any{
 			slog.Int(logging.FieldStatusCode, ww.status),
 			slog.Int64(logging.FieldDurationMS, duration.Milliseconds()),
-		)
+			slog.Int64(logging.FieldBytes, ww.bytes),
+		}
+		switch {
+		case opts.SlowThreshold > 0 && duration >= opts.SlowThreshold:
+			attrs = append(attrs,
+				slog.Bool("slow", true),
+				slog.String("route", route),
+				slog.Int64("slow_threshold_ms", opts.SlowThreshold.Milliseconds()),
+			)
+			if d := upstream.total(); d > 0 {
+				attrs = append(attrs, slog.Int64("upstream_ms", d.Milliseconds()))
+			}
+			logger.Warn("request complete", attrs...)
+		case ww.status >= http.StatusBadRequest || opts.SampleRate <= 1:
+			logger.Info("request complete", attrs...)
+		case (seen.Add(1)-1)%uint64(opts.SampleRate) == 0:
+			logger.Info("request complete", append(attrs, slog.Int("sample_rate", opts.SampleRate))...)
+		}
 	})
 }
 
@@ -57,9 +132,29 @@ func (w *responseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+// Write counts the body bytes the client was actually sent.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	status int
+	bytes  int64
+}
+
+// Flush forwards to the underlying writer so streaming handlers keep working.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
 // RequestIDFromContext extracts the request ID stored by the logging middleware.
@@ -79,20 +174,107 @@ func withRequestID(ctx context.Context, id string) context.Context {
 
 type requestIDKey struct{}
 
-func normalizePath(path string) string {
-	if path == "" {
+// RecordUpstreamLatency attributes d of upstream (provider) time to the request
+// in ctx, so a slow-request log can say how much of it was spent upstream.
+// Outside a request handled by the logging middleware it does nothing.
+func RecordUpstreamLatency(ctx context.Context, d time.Duration) {
+	if ctx == nil {
+		return
+	}
+	if u, ok := ctx.Value(upstreamLatencyKey{}).(*upstreamLatency); ok {
+		u.ns.Add(int64(d))
+	}
+}
+
+type upstreamLatencyKey struct{}
+
+type upstreamLatency struct {
+	ns atomic.Int64
+}
+
+func (u *upstreamLatency) total() time.Duration {
+	return time.Duration(u.ns.Load())
+}
+
+// sanitizeQuery returns raw for logging with the values of redacted
+// parameters replaced and the result cut to MaxLoggedQueryBytes. Pairs keep
+// their original order and encoding; request headers are never logged.
+func sanitizeQuery(raw string, redact map[string]bool) string {
+	if raw == "" {
 		return ""
 	}
-	path = strings.Split(path, "?")[0]
-	switch path {
-	case "/games":
-		return "/games"
-	case "/health":
-		return "/health"
-	default:
-		if strings.HasPrefix(path, "/games/") {
-			return "/games/:id"
+	pairs := strings.Split(raw, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		name := key
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			name = unescaped
+		}
+		if hasValue && redact[strings.ToLower(name)] {
+			pairs[i] = key + "=" + RedactedValue
+		}
+	}
+	out := strings.Join(pairs, "&")
+	if len(out) > MaxLoggedQueryBytes {
+		out = strings.ToValidUTF8(out[:MaxLoggedQueryBytes], "") + "..."
+	}
+	return out
+}
+
+// UnmatchedRoute is the metrics path label of requests no route served, so
+// unknown paths cannot grow label cardinality.
+const UnmatchedRoute = "unmatched"
+
+// SetRoute records template (see RouteTemplate) as the route serving the
+// request in ctx; the router calls it on a match so the logging middleware
+// can label metrics even when later middleware clones the request. Outside a
+// request handled by the logging middleware it does nothing.
+func SetRoute(ctx context.Context, template string) {
+	if ctx == nil {
+		return
+	}
+	if m, ok := ctx.Value(matchedRouteKey{}).(*matchedRoute); ok {
+		m.template.Store(&template)
+	}
+}
+
+type matchedRouteKey struct{}
+
+type matchedRoute struct {
+	template atomic.Pointer[string]
+}
+
+// routeLabel returns the metrics path label for r: the template the router
+// recorded, else the template of the ServeMux pattern set on r, else
+// UnmatchedRoute.
+func routeLabel(r *http.Request, matched *matchedRoute) string {
+	if t := matched.template.Load(); t != nil {
+		return *t
+	}
+	if r.Pattern != "" {
+		return RouteTemplate(r.Pattern)
+	}
+	return UnmatchedRoute
+}
+
+// RouteTemplate converts a ServeMux pattern to its metrics label: the method
+// and host are dropped and wildcards become ":name", so "GET /games/{id}" is
+// "/games/:id".
+func RouteTemplate(pattern string) string {
+	// Drop the optional "METHOD " and host prefixes.
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i:], " ")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if seg == "{$}" {
+			segments[i] = ""
+		} else if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segments[i] = ":" + strings.TrimSuffix(seg[1:len(seg)-1], "...")
 		}
-		return path
 	}
+	return strings.Join(segments, "/")
 }
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -41,6 +43,20 @@ func TestLoggingMiddlewareSetsRequestIDAndRecordsMetrics(t *testing.T) {
 	}
 }
 
+func TestLoggingMiddlewareLogsBytesWritten(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello "))
+		_, _ = w.Write([]byte("world"))
+	})
+
+	testutil.Serve(LoggingMiddleware(logger, nil, next), http.MethodGet, "/games", nil)
+
+	if !strings.Contains(buf.String(), " bytes=11") {
+		t.Fatalf("expected bytes written in the request log, got %s", buf.String())
+	}
+}
+
 func TestLoggingMiddlewareGeneratesRequestIDWhenMissing(t *testing.T) {
 	logger, _ := testutil.NewBufferLogger()
 	rec := metrics.NewRecorder()
@@ -88,26 +104,103 @@ func TestResponseWriterDefaultsStatus(t *testing.T) {
 	}
 }
 
-func TestNormalizePathHandlesEmpty(t *testing.T) {
-	if got := normalizePath(""); got != "" {
-		t.Fatalf("expected empty path to stay empty, got %s", got)
+func TestResponseWriterFlushesAndUnwraps(t *testing.T) {
+	rr := httptest.NewRecorder()
+	w := &responseWriter{ResponseWriter: rr}
+	w.Flush()
+	if !rr.Flushed {
+		t.Fatalf("expected flush forwarded to underlying writer")
+	}
+	if w.Unwrap() != rr {
+		t.Fatalf("expected Unwrap to return underlying writer")
 	}
 }
 
-func TestNormalizePath(t *testing.T) {
+func TestRouteTemplate(t *testing.T) {
 	tests := []struct {
-		in   string
-		want string
+		pattern string
+		want    string
 	}{
-		{in: "/games", want: "/games"},
-		{in: "/games/123", want: "/games/:id"},
-		{in: "/health", want: "/health"},
-		{in: "/ready", want: "/ready"},
+		{pattern: "/games/{id}", want: "/games/:id"},
+		{pattern: "GET /games/{id}/history", want: "/games/:id/history"},
+		{pattern: "example.com/files/{rest...}", want: "/files/:rest"},
+		{pattern: "/admin/snapshots/pin/", want: "/admin/snapshots/pin/"},
+		{pattern: "/{$}", want: "/"},
 	}
-
 	for _, tt := range tests {
-		if got := normalizePath(tt.in); got != tt.want {
-			t.Fatalf("normalizePath(%s) = %s, want %s", tt.in, got, tt.want)
+		if got := RouteTemplate(tt.pattern); got != tt.want {
+			t.Fatalf("RouteTemplate(%q) = %s, want %s", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRouteLabelPrefersRecordedRoute(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/games/123", nil)
+	if got := routeLabel(r, &matchedRoute{}); got != UnmatchedRoute {
+		t.Fatalf("expected an unrouted request labeled %s, got %s", UnmatchedRoute, got)
+	}
+	r.Pattern = "GET /games/{id}"
+	if got := routeLabel(r, &matchedRoute{}); got != "/games/:id" {
+		t.Fatalf("expected the ServeMux pattern used, got %s", got)
+	}
+
+	matched := &matchedRoute{}
+	ctx := context.WithValue(context.Background(), matchedRouteKey{}, matched)
+	SetRoute(ctx, "/teams/:id")
+	if got := routeLabel(r, matched); got != "/teams/:id" {
+		t.Fatalf("expected the recorded route to win, got %s", got)
+	}
+	SetRoute(context.Background(), "/ignored")
+}
+
+func TestLoggingMiddlewareLabelsUnknownPathsUnmatched(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := LoggingMiddlewareWithOptions(logger, nil, LoggingOptions{SlowThreshold: time.Millisecond}, notFound)
+
+	testutil.Serve(handler, http.MethodGet, "/players/12345/stats", nil)
+	if logs := buf.String(); !strings.Contains(logs, "route=unmatched") {
+		t.Fatalf("expected the raw path kept out of the route label, got %s", logs)
+	}
+}
+
+func TestLoggingMiddlewareRedactsQueryCredentials(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	handler := LoggingMiddlewareWithOptions(logger, nil, LoggingOptions{RedactQueryParams: []string{"Sig"}}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/games?date=2024-01-15&API%4Bey=s3cret-1&token=s3cret-2&sig=s3cret-3&tz=America%2FNew_York", nil)
+	req.Header.Set("Authorization", "Bearer s3cret-4")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	logs := buf.String()
+	if strings.Contains(logs, "s3cret") {
+		t.Fatalf("expected credentials redacted, got %s", logs)
+	}
+	if want := "query=\"date=2024-01-15&API%4Bey=REDACTED&token=REDACTED&sig=REDACTED&tz=America%2FNew_York\""; !strings.Contains(logs, want) {
+		t.Fatalf("expected %s in log, got %s", want, logs)
+	}
+}
+
+func TestSanitizeQuery(t *testing.T) {
+	redact := map[string]bool{"key": true}
+	long := "date=2024-01-15&ids=" + strings.Repeat("g", MaxLoggedQueryBytes)
+	cases := map[string]string{
+		"":                        "",
+		"date=2024-01-15":         "date=2024-01-15",
+		"key=abc&key=def":         "key=REDACTED&key=REDACTED",
+		"key&keys=abc":            "key&keys=abc", // no value to hide; "keys" is another param
+		"key=%zz&date=2024-01-15": "key=REDACTED&date=2024-01-15",
+		long:                      long[:MaxLoggedQueryBytes] + "...",
+	}
+	for raw, want := range cases {
+		if got := sanitizeQuery(raw, redact); got != want {
+			t.Fatalf("sanitizeQuery(%q) = %q, want %q", raw, got, want)
 		}
 	}
 }
@@ -147,3 +240,89 @@ func BenchmarkLoggingMiddleware(b *testing.B) {
 		handler.ServeHTTP(rr, req)
 	}
 }
+
+func TestLoggingMiddlewareSamplesSuccessesButLogsErrors(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	rec, promHandler, shutdown, err := metrics.Setup(context.Background(), metrics.TelemetryConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("metrics setup failed: %v", err)
+	}
+	defer func() { _ = shutdown(context.Background()) }()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := LoggingMiddlewareWithOptions(logger, rec, LoggingOptions{SampleRate: 4}, next)
+
+	for i := 0; i < 8; i++ {
+		testutil.Serve(handler, http.MethodGet, "/health", nil)
+	}
+	for i := 0; i < 3; i++ {
+		testutil.Serve(handler, http.MethodGet, "/missing", nil)
+	}
+
+	logs := buf.String()
+	if got := strings.Count(logs, "status_code=200"); got != 2 {
+		t.Fatalf("expected 1 in 4 successes logged (2 of 8), got %d:\n%s", got, logs)
+	}
+	if got := strings.Count(logs, "sample_rate=4"); got != 2 {
+		t.Fatalf("expected sampled lines to carry sample_rate, got %d", got)
+	}
+	if got := strings.Count(logs, "status_code=404"); got != 3 {
+		t.Fatalf("expected every error logged, got %d", got)
+	}
+
+	// Metrics are recorded for sampled-out requests too.
+	scrape := httptest.NewRecorder()
+	promHandler.ServeHTTP(scrape, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	total := 0.0
+	for _, line := range strings.Split(scrape.Body.String(), "\n") {
+		if strings.HasPrefix(line, "http_requests_total{") {
+			fields := strings.Fields(line)
+			v, _ := strconv.ParseFloat(fields[len(fields)-1], 64)
+			total += v
+		}
+	}
+	if total != 11 {
+		t.Fatalf("expected 11 requests counted, got %v", total)
+	}
+}
+
+func TestLoggingMiddlewareWarnsOnSlowRequests(t *testing.T) {
+	logger, buf := testutil.NewBufferLogger()
+	clk := testutil.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	elapsed := time.Duration(0)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RecordUpstreamLatency(r.Context(), 3*time.Millisecond)
+		RecordUpstreamLatency(r.Context(), 2*time.Millisecond)
+		clk.Advance(elapsed)
+		w.WriteHeader(http.StatusOK)
+	})
+	mux := http.NewServeMux()
+	mux.Handle("/games/{id}", next)
+	// Sampling would drop this request; slowness overrides it.
+	handler := LoggingMiddlewareWithOptions(logger, nil, LoggingOptions{SampleRate: 1000, SlowThreshold: 10 * time.Millisecond, Clock: clk}, mux)
+
+	testutil.Serve(handler, http.MethodGet, "/games/g1", nil) // first request is always sampled
+	if logs := buf.String(); strings.Contains(logs, "slow=true") {
+		t.Fatalf("expected an instant request not to be flagged slow, got %s", logs)
+	}
+	buf.Reset()
+	elapsed = 10 * time.Millisecond
+	testutil.Serve(handler, http.MethodGet, "/games/g2", nil)
+
+	logs := buf.String()
+	for _, want := range []string{"level=WARN", "slow=true", "route=/games/:id", "slow_threshold_ms=10", "duration_ms=10", "upstream_ms=5"} {
+		if !strings.Contains(logs, want) {
+			t.Fatalf("expected %q in slow request log, got %s", want, logs)
+		}
+	}
+}
+
+func TestRecordUpstreamLatencyOutsideRequest(t *testing.T) {
+	RecordUpstreamLatency(context.Background(), time.Second)
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"sync/atomic"
@@ -46,3 +47,23 @@ func ClientIP(r *http.Request) string {
 	}
 	return r.RemoteAddr
 }
+
+// PathID returns the named wildcard from the route pattern matched for r,
+// unescaped once more and checked with ValidID; ok is false when it is missing
+// or invalid.
+func PathID(r *http.Request, name string) (string, bool) {
+	if r == nil {
+		return "", false
+	}
+	id, err := url.PathUnescape(r.PathValue(name))
+	if err != nil || !ValidID(id) {
+		return "", false
+	}
+	return id, true
+}
+
+// ValidID reports whether id is usable as a path identifier: non-empty with no
+// whitespace or slashes.
+func ValidID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, " \t/")
+}
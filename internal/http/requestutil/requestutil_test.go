@@ -40,3 +40,29 @@ func TestClientIP(t *testing.T) {
 		t.Fatalf("expected remote addr fallback, got %s", got)
 	}
 }
+
+func TestPathID(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{value: "game-1", want: "game-1", ok: true},
+		{value: "ns%3A1", want: "ns:1", ok: true},
+		{value: "", ok: false},
+		{value: "bad id", ok: false},
+		{value: "a/b", ok: false},
+		{value: "%zz", ok: false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/games/x", nil)
+		r.SetPathValue("id", tt.value)
+		got, ok := PathID(r, "id")
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("PathID(%q) = %q, %v; want %q, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, ok := PathID(nil, "id"); ok {
+		t.Fatalf("expected nil request to be rejected")
+	}
+}
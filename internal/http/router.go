@@ -1,13 +1,22 @@
 package http
 
-import nethttp "net/http"
+import (
+	nethttp "net/http"
 
-// NewRouter registers HTTP routes on a ServeMux.
-func NewRouter(handler nethttp.Handler) nethttp.Handler {
-	mux := nethttp.NewServeMux()
-	mux.Handle("/health", handler)
-	mux.Handle("/ready", handler)
-	mux.Handle("/games", handler)
-	mux.Handle("/games/", handler)
+	"github.com/preston-bernstein/nba-data-service/internal/http/handlers"
+)
+
+// NewRouter registers the core read routes, all served by handler, on a
+// method-aware Mux; callers mount the rest. "GET /games/" still reaches
+// handler so multi-segment ids get its JSON 400 rather than a 404.
+func NewRouter(handler nethttp.Handler) *handlers.Mux {
+	mux := handlers.NewMux(nil)
+	mux.Handle("GET /health", handler)
+	mux.Handle("GET /ready", handler)
+	mux.Handle("GET /status", handler)
+	mux.Handle("GET /openapi.json", handler)
+	mux.Handle("GET /games", handler)
+	mux.Handle("GET /games/{id}", handler)
+	mux.Handle("GET /games/", handler)
 	return mux
 }
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/http/handlers"
+	"github.com/preston-bernstein/nba-data-service/internal/http/middleware"
 	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 	"github.com/preston-bernstein/nba-data-service/internal/testutil"
 )
@@ -17,10 +21,14 @@ func TestRouterRoutesKnownPaths(t *testing.T) {
 	router := NewRouter(h)
 
 	cases := map[string]int{
-		"/health":      http.StatusOK,
-		"/games":       http.StatusBadRequest,
-		"/games/today": http.StatusNotFound,
-		"/games/foo":   http.StatusNotFound, // known route with missing game
+		"/health":       http.StatusOK,
+		"/status":       http.StatusOK,
+		"/openapi.json": http.StatusOK,
+		"/games":        http.StatusBadRequest,
+		"/games/today":  http.StatusNotFound,
+		"/games/foo":    http.StatusNotFound, // known route with missing game
+		"/games/a/b":    http.StatusBadRequest,
+		"/games/a%20b":  http.StatusBadRequest,
 	}
 
 	for path, expected := range cases {
@@ -29,6 +37,82 @@ func TestRouterRoutesKnownPaths(t *testing.T) {
 	}
 }
 
+func TestRouterGameIDFromPattern(t *testing.T) {
+	snaps := &teststubs.StubSnapshotStore{FindGame: &domaingames.Game{ID: "ns:123"}}
+	router := NewRouter(handlers.NewHandler(snaps, nil, nil, nil))
+
+	rr := testutil.Serve(router, http.MethodGet, "/games/ns:123", nil)
+	testutil.AssertStatus(t, rr, http.StatusOK)
+}
+
+func TestRouterGameIDFromEscapedPath(t *testing.T) {
+	snaps := &teststubs.StubSnapshotStore{FindGame: &domaingames.Game{ID: "ns:123"}}
+	router := NewRouter(handlers.NewHandler(snaps, nil, nil, nil))
+
+	// The stub only matches "ns:123", so a 200 means the id was unescaped.
+	testutil.AssertStatus(t, testutil.Serve(router, http.MethodGet, "/games/ns%3A123", nil), http.StatusOK)
+	// An escaped slash stays inside one segment but is still not a valid id.
+	testutil.AssertStatus(t, testutil.Serve(router, http.MethodGet, "/games/a%2Fb", nil), http.StatusBadRequest)
+}
+
+func TestRouterMethodNotAllowedSetsAllow(t *testing.T) {
+	router := NewRouter(handlers.NewHandler(&teststubs.StubSnapshotStore{}, nil, nil, nil))
+	router.HandleFunc("POST /jobs/{id}", func(http.ResponseWriter, *http.Request) {})
+
+	cases := map[string]string{
+		"/health":  "GET, HEAD",
+		"/games/x": "GET, HEAD",
+		"/jobs/1":  "POST",
+	}
+	for path, allow := range cases {
+		rr := testutil.Serve(router, http.MethodDelete, path, nil)
+		testutil.AssertStatus(t, rr, http.StatusMethodNotAllowed)
+		if got := rr.Header().Get("Allow"); got != allow {
+			t.Fatalf("%s: expected Allow %q, got %q", path, allow, got)
+		}
+	}
+	if rr := testutil.Serve(router, http.MethodHead, "/health", nil); rr.Code != http.StatusOK {
+		t.Fatalf("expected HEAD served by the GET route, got %d", rr.Code)
+	}
+}
+
+func TestRouterLabelsMetricsByRouteTemplate(t *testing.T) {
+	router := NewRouter(handlers.NewHandler(&teststubs.StubSnapshotStore{}, nil, nil, nil))
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	logger, buf := testutil.NewBufferLogger()
+	// Every request is slow under the fake clock, so each logs its route.
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r)
+		clk.Advance(time.Millisecond)
+	})
+	handler := middleware.LoggingMiddlewareWithOptions(logger, nil, middleware.LoggingOptions{SlowThreshold: time.Millisecond, Clock: clk}, slow)
+
+	routeOf := func(path string) string {
+		t.Helper()
+		buf.Reset()
+		testutil.Serve(handler, http.MethodGet, path, nil)
+		_, after, ok := strings.Cut(buf.String(), "route=")
+		if !ok {
+			t.Fatalf("%s: no route in log %s", path, buf.String())
+		}
+		return strings.Fields(after)[0]
+	}
+
+	for pattern, template := range router.Templates() {
+		path := strings.TrimPrefix(pattern, "GET ")
+		path = strings.ReplaceAll(path, "{id}", "g1")
+		if got := routeOf(path); got != template {
+			t.Fatalf("%s: expected route %s, got %s", pattern, template, got)
+		}
+	}
+	if got := routeOf("/games/0022300061"); got != "/games/:id" {
+		t.Fatalf("expected the id collapsed into the template, got %s", got)
+	}
+	if got := routeOf("/players/12345/stats"); got != middleware.UnmatchedRoute {
+		t.Fatalf("expected an unknown path labeled unmatched, got %s", got)
+	}
+}
+
 func TestRouterUnknownRouteReturns404(t *testing.T) {
 	snaps := &teststubs.StubSnapshotStore{}
 	h := handlers.NewHandler(snaps, nil, nil, nil)
@@ -41,6 +125,6 @@ func TestRouterUnknownRouteReturns404(t *testing.T) {
 
 // StubSnapshotStore is defined in teststubs for reuse across packages.
 var _ interface {
-	LoadGames(date string) (domaingames.TodayResponse, error)
-	FindGameByID(date, id string) (domaingames.Game, bool)
+	LoadGames(ctx context.Context, date string) (domaingames.TodayResponse, error)
+	FindGameByID(ctx context.Context, date, id string) (domaingames.Game, bool)
 } = (*teststubs.StubSnapshotStore)(nil)
@@ -14,6 +14,7 @@ const (
 	FieldDate       = "date"
 	FieldCount      = "count"
 	FieldDurationMS = "duration_ms"
+	FieldBytes      = "bytes"
 )
 
 // WithCommon appends service/version fields when provided.
@@ -12,6 +12,9 @@ type Config struct {
 	Format  string
 	Service string
 	Version string
+	// LevelVar, when set, is given Level and used by the handler, so the
+	// level can be changed while the logger runs.
+	LevelVar *slog.LevelVar
 }
 
 const (
@@ -23,7 +26,11 @@ const (
 
 // NewLogger returns a structured logger with sane defaults.
 func NewLogger(cfg Config) *slog.Logger {
-	level := parseLevel(cfg.Level)
+	var level slog.Leveler = ParseLevel(cfg.Level)
+	if cfg.LevelVar != nil {
+		cfg.LevelVar.Set(level.Level())
+		level = cfg.LevelVar
+	}
 	handler := buildHandler(cfg.Format, level)
 
 	return slog.New(handler).With(
@@ -50,7 +57,9 @@ func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
 	return context.WithValue(ctx, loggerKey{}, logger)
 }
 
-func parseLevel(raw string) slog.Level {
+// ParseLevel maps debug, info, warn and error (any case) to a level; anything
+// else is info.
+func ParseLevel(raw string) slog.Level {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "debug":
 		return slog.LevelDebug
@@ -63,7 +72,7 @@ func parseLevel(raw string) slog.Level {
 	}
 }
 
-func buildHandler(format string, level slog.Level) slog.Handler {
+func buildHandler(format string, level slog.Leveler) slog.Handler {
 	opts := &slog.HandlerOptions{Level: level}
 	if strings.ToLower(strings.TrimSpace(format)) == formatText {
 		opts.AddSource = true // helpful for local debugging
@@ -29,16 +29,16 @@ func TestFromContextAndWithLogger(t *testing.T) {
 }
 
 func TestParseLevel(t *testing.T) {
-	if parseLevel("debug") != slog.LevelDebug {
+	if ParseLevel("debug") != slog.LevelDebug {
 		t.Fatalf("expected debug level")
 	}
-	if parseLevel("warn") != slog.LevelWarn {
+	if ParseLevel("warn") != slog.LevelWarn {
 		t.Fatalf("expected warn level")
 	}
-	if parseLevel("error") != slog.LevelError {
+	if ParseLevel("error") != slog.LevelError {
 		t.Fatalf("expected error level")
 	}
-	if parseLevel("") != slog.LevelInfo {
+	if ParseLevel("") != slog.LevelInfo {
 		t.Fatalf("expected default info level")
 	}
 }
@@ -74,10 +74,10 @@ func TestBuildHandlerReturnsJSONAndText(t *testing.T) {
 }
 
 func TestParseLevelFallsBackOnUnknown(t *testing.T) {
-	if got := parseLevel("DEBUG"); got != slog.LevelDebug {
+	if got := ParseLevel("DEBUG"); got != slog.LevelDebug {
 		t.Fatalf("expected debug for DEBUG")
 	}
-	if got := parseLevel("unknown"); got != slog.LevelInfo {
+	if got := ParseLevel("unknown"); got != slog.LevelInfo {
 		t.Fatalf("expected info fallback, got %v", got)
 	}
 }
@@ -108,3 +108,15 @@ func newBufferLogger() (*slog.Logger, *bytes.Buffer) {
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
 	return logger, &buf
 }
+
+func TestNewLoggerLevelVarChangesLevelLive(t *testing.T) {
+	level := new(slog.LevelVar)
+	logger := NewLogger(Config{Level: "warn", LevelVar: level})
+	if level.Level() != slog.LevelWarn || logger.Enabled(context.Background(), slog.LevelInfo) {
+		t.Fatalf("expected LevelVar seeded with warn")
+	}
+	level.Set(slog.LevelDebug)
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("expected debug enabled after LevelVar change")
+	}
+}
@@ -2,8 +2,19 @@ package metrics
 
 // Common metric attribute keys to keep telemetry consistent/searchable.
 const (
-	AttrMethod   = "method"
-	AttrPath     = "path"
-	AttrStatus   = "status"
-	AttrProvider = "provider"
+	AttrMethod    = "method"
+	AttrPath      = "path"
+	AttrStatus    = "status"
+	AttrProvider  = "provider"
+	AttrClient    = "client"
+	AttrComponent = "component"
+	AttrOutcome   = "outcome"
+	AttrKind      = "kind"
+	AttrAPIKey    = "api_key" // the configured key name, never the key itself
+)
+
+// Score anomaly kinds recorded by RecordScoreAnomaly.
+const (
+	AnomalyFinalRegression = "final_regression" // a FINAL game reported live again or with a lower score
+	AnomalyScoreCorrection = "score_correction" // a live game's score went down
 )
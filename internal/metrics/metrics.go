@@ -1,34 +1,94 @@
 package metrics
 
 import (
+	"log/slog"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
 )
 
+// DefaultMaxProviders caps how many provider names the Recorder tracks before
+// evicting the least recently updated.
+const DefaultMaxProviders = 64
+
 type providerStats struct {
 	calls           int
 	errors          int
 	rateLimitHits   int
+	budgetExhausted int
 	lastRetryAfter  time.Duration
 	lastCallLatency time.Duration
+	remaining       int  // last upstream rate limit remaining header
+	remainingSeen   bool // remaining has been reported
+	quota           QuotaEstimate
+	quotaSeen       bool   // quota has been reported
+	touched         uint64 // Recorder.tick at the last update, for LRU eviction
+	evicted         bool   // stats were evicted earlier and restarted from zero
 }
 
 // Recorder captures lightweight, in-memory metrics about provider calls.
 // It is intentionally simple so it can be swapped for a real backend later.
 type Recorder struct {
-	mu    sync.Mutex
-	stats map[string]*providerStats
-	otel  *otelInstruments
+	mu             sync.Mutex
+	stats          map[string]*providerStats
+	maxProviders   int
+	tick           uint64
+	evicted        map[string]struct{} // bounded like stats; see markEvicted
+	evictedOrder   []string
+	logger         *slog.Logger
+	pollerTimeouts int
+	gamesAdded     int
+	statusChanges  int
+	scoreUpdates   int
+	gamesAccepted  int
+	gamesDropped   int
+	gamesCoerced   int
+	webhooksSent   int
+	webhookFails   int
+	webhookDrops   int
+	coalesced      int
+	cacheHits      int
+	cacheMisses    int
+	streamDrops    int
+	streamKicks    int
+	apiKeyRequests map[string]int // by key name; names come from config, so bounded
+	scoreAnomalies map[string]int // by AnomalyFinalRegression or AnomalyScoreCorrection
+	nextRuns       *nextRuns
+	observables    *observables
+	otel           *otelInstruments
 }
 
 func NewRecorder() *Recorder {
 	return newRecorder(nil)
 }
 
+// NewRecorderWithLimit is identical to NewRecorder but tracks at most maxProviders
+// provider names, logging evictions to logger.
+func NewRecorderWithLimit(maxProviders int, logger *slog.Logger) *Recorder {
+	r := newRecorder(nil)
+	r.SetProviderLimit(maxProviders, logger)
+	return r
+}
+
 func newRecorder(otel *otelInstruments) *Recorder {
+	runs := newNextRuns()
+	if otel != nil && otel.nextRuns != nil {
+		runs = otel.nextRuns
+	}
+	obs := newObservables()
+	if otel != nil && otel.observables != nil {
+		obs = otel.observables
+	}
 	return &Recorder{
-		stats: make(map[string]*providerStats),
-		otel:  otel,
+		stats:        make(map[string]*providerStats),
+		maxProviders: DefaultMaxProviders,
+		evicted:      make(map[string]struct{}),
+		nextRuns:     runs,
+		observables:  obs,
+		otel:         otel,
 	}
 }
 
@@ -38,12 +98,13 @@ func (r *Recorder) RecordProviderAttempt(provider string, duration time.Duration
 		return
 	}
 
-	stats := r.ensureStats(provider)
-	stats.calls++
-	stats.lastCallLatency = duration
-	if err != nil {
-		stats.errors++
-	}
+	r.updateStats(provider, func(stats *providerStats) {
+		stats.calls++
+		stats.lastCallLatency = duration
+		if err != nil {
+			stats.errors++
+		}
+	})
 	if r.otel != nil {
 		r.otel.recordProviderAttempt(provider, duration, err)
 	}
@@ -55,16 +116,99 @@ func (r *Recorder) RecordRateLimit(provider string, retryAfter time.Duration) {
 		return
 	}
 
-	stats := r.ensureStats(provider)
-	stats.rateLimitHits++
-	if retryAfter > 0 {
-		stats.lastRetryAfter = retryAfter
-	}
+	r.updateStats(provider, func(stats *providerStats) {
+		stats.rateLimitHits++
+		if retryAfter > 0 {
+			stats.lastRetryAfter = retryAfter
+		}
+	})
 	if r.otel != nil {
 		r.otel.recordRateLimit(provider, retryAfter)
 	}
 }
 
+// RecordRetryBudgetExhausted tracks a retry the provider skipped because the
+// shared retry budget was spent.
+func (r *Recorder) RecordRetryBudgetExhausted(provider string) {
+	if r == nil {
+		return
+	}
+
+	r.updateStats(provider, func(stats *providerStats) {
+		stats.budgetExhausted++
+	})
+	if r.otel != nil {
+		r.otel.recordRetryBudgetExhausted(provider)
+	}
+}
+
+// RecordProviderRequest tracks one HTTP request a provider sent upstream;
+// status is 0 when no response arrived.
+func (r *Recorder) RecordProviderRequest(provider, method, path string, status int, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	if r.otel != nil {
+		r.otel.recordProviderRequest(provider, method, path, status, duration)
+	}
+}
+
+// SetRateLimitRemaining reports the upstream's remaining request quota for
+// the provider_rate_limit_remaining gauge.
+func (r *Recorder) SetRateLimitRemaining(provider string, n int) {
+	if r == nil {
+		return
+	}
+	r.updateStats(provider, func(stats *providerStats) {
+		stats.remaining = n
+		stats.remainingSeen = true
+	})
+	if r.otel != nil {
+		r.otel.setRateLimitRemaining(provider, n)
+	}
+}
+
+// RateLimitRemaining returns the last quota reported for provider; ok is
+// false until one has been.
+func (r *Recorder) RateLimitRemaining(provider string) (n int, ok bool) {
+	if r == nil {
+		return 0, false
+	}
+	stats := r.snapshot(provider)
+	return stats.remaining, stats.remainingSeen
+}
+
+// QuotaEstimate is a provider's forecast of the upstream requests left in the
+// current rate limit window.
+type QuotaEstimate struct {
+	Remaining int
+	Known     bool // false before the first header and once the window passed
+	Throttled bool // Remaining is below the threshold, so calls are stretched
+	Interval  time.Duration
+	ResetsAt  time.Time // when the window is assumed to reset; zero unless Known
+}
+
+// SetQuotaEstimate reports a provider's current quota forecast.
+func (r *Recorder) SetQuotaEstimate(provider string, estimate QuotaEstimate) {
+	if r == nil {
+		return
+	}
+	r.updateStats(provider, func(stats *providerStats) {
+		stats.quota = estimate
+		stats.quotaSeen = true
+	})
+}
+
+// QuotaEstimate returns the last forecast reported for provider; ok is false
+// until one has been.
+func (r *Recorder) QuotaEstimate(provider string) (estimate QuotaEstimate, ok bool) {
+	if r == nil {
+		return QuotaEstimate{}, false
+	}
+	stats := r.snapshot(provider)
+	return stats.quota, stats.quotaSeen
+}
+
 // ProviderCalls returns the total attempts recorded for a provider.
 func (r *Recorder) ProviderCalls(provider string) int {
 	return r.Snapshot(provider).Calls
@@ -80,6 +224,11 @@ func (r *Recorder) RateLimitHits(provider string) int {
 	return r.Snapshot(provider).RateLimitHits
 }
 
+// RetryBudgetExhausted returns how many retries were skipped for lack of budget.
+func (r *Recorder) RetryBudgetExhausted(provider string) int {
+	return r.Snapshot(provider).RetryBudgetExhausted
+}
+
 // LastRetryAfter returns the most recent Retry-After recorded for a provider.
 func (r *Recorder) LastRetryAfter(provider string) time.Duration {
 	return r.Snapshot(provider).LastRetryAfter
@@ -92,33 +241,95 @@ func (r *Recorder) LastCallLatency(provider string) time.Duration {
 
 // Snapshot returns a copy of the current stats for the provider.
 type Snapshot struct {
-	Calls           int
-	Errors          int
-	RateLimitHits   int
-	LastRetryAfter  time.Duration
-	LastCallLatency time.Duration
+	Calls         int
+	Errors        int
+	RateLimitHits int
+	// RetryBudgetExhausted counts retries skipped because the budget was spent.
+	RetryBudgetExhausted int
+	LastRetryAfter       time.Duration
+	LastCallLatency      time.Duration
+	// Evicted is set when the provider's stats were dropped to respect the
+	// provider cap; counters then cover only activity since it was re-tracked.
+	Evicted bool
 }
 
 func (r *Recorder) Snapshot(provider string) Snapshot {
 	if r == nil {
 		return Snapshot{}
 	}
-	stats := r.snapshot(provider)
-	return Snapshot{
-		Calls:           stats.calls,
-		Errors:          stats.errors,
-		RateLimitHits:   stats.rateLimitHits,
-		LastRetryAfter:  stats.lastRetryAfter,
-		LastCallLatency: stats.lastCallLatency,
+	return r.snapshot(provider).snapshot()
+}
+
+// AllSnapshots returns a copy of the stats of every tracked provider, keyed
+// by the name each decorator layer recorded under.
+func (r *Recorder) AllSnapshots() map[string]Snapshot {
+	if r == nil {
+		return map[string]Snapshot{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]Snapshot, len(r.stats))
+	for name, stats := range r.stats {
+		out[name] = stats.snapshot()
+	}
+	return out
+}
+
+// TotalSnapshot sums the counters of every tracked provider. LastRetryAfter
+// and LastCallLatency come from the most recently updated provider, and
+// Evicted is set when any provider's stats were evicted.
+func (r *Recorder) TotalSnapshot() Snapshot {
+	var total Snapshot
+	if r == nil {
+		return total
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest uint64
+	for _, stats := range r.stats {
+		total.Calls += stats.calls
+		total.Errors += stats.errors
+		total.RateLimitHits += stats.rateLimitHits
+		total.RetryBudgetExhausted += stats.budgetExhausted
+		total.Evicted = total.Evicted || stats.evicted
+		if stats.touched >= latest {
+			latest = stats.touched
+			total.LastRetryAfter = stats.lastRetryAfter
+			total.LastCallLatency = stats.lastCallLatency
+		}
+	}
+	return total
+}
+
+// Reset forgets provider's stats, including any eviction mark. Meant for tests.
+func (r *Recorder) Reset(provider string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.stats[provider]; ok {
+		delete(r.stats, provider)
+		r.otel.setTrackedProviders(len(r.stats))
+	}
+	if _, ok := r.evicted[provider]; ok {
+		delete(r.evicted, provider)
+		r.evictedOrder = slices.DeleteFunc(r.evictedOrder, func(name string) bool { return name == provider })
 	}
 }
 
 // RecordHTTPRequest tracks basic HTTP metrics.
 func (r *Recorder) RecordHTTPRequest(method, path string, status int, duration time.Duration) {
+	r.RecordHTTPRequestForClient(method, path, "anonymous", status, duration)
+}
+
+// RecordHTTPRequestForClient is RecordHTTPRequest with an explicit client label.
+// Callers must keep client low-cardinality (see middleware.ClientTracker).
+func (r *Recorder) RecordHTTPRequestForClient(method, path, client string, status int, duration time.Duration) {
 	if r == nil || r.otel == nil {
 		return
 	}
-	r.otel.recordHTTPRequest(method, path, status, duration)
+	r.otel.recordHTTPRequest(method, path, client, status, duration)
 }
 
 // RecordPollerCycle tracks poller cycles and errors.
@@ -129,16 +340,402 @@ func (r *Recorder) RecordPollerCycle(duration time.Duration, err error) {
 	r.otel.recordPoller(duration, err)
 }
 
-func (r *Recorder) ensureStats(provider string) *providerStats {
+// RecordPollerTimeout tracks poller cycles whose provider fetch exceeded the per-cycle timeout.
+func (r *Recorder) RecordPollerTimeout() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.pollerTimeouts++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordPollerTimeout()
+	}
+}
+
+// PollerTimeouts returns the number of poller fetches that timed out.
+func (r *Recorder) PollerTimeouts() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pollerTimeouts
+}
+
+// RecordGameChanges tracks what changed between consecutive poller fetches.
+func (r *Recorder) RecordGameChanges(added, statusChanges, scoreUpdates int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.gamesAdded += added
+	r.statusChanges += statusChanges
+	r.scoreUpdates += scoreUpdates
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordGameChanges(added, statusChanges, scoreUpdates)
+	}
+}
+
+// RecordNormalize tracks how provider games fared in normalization; coerced
+// games are also counted as accepted.
+func (r *Recorder) RecordNormalize(accepted, dropped, coerced int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.gamesAccepted += accepted
+	r.gamesDropped += dropped
+	r.gamesCoerced += coerced
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordNormalize(accepted, dropped, coerced)
+	}
+}
+
+// NormalizeCounts returns the accepted, dropped and coerced game totals.
+func (r *Recorder) NormalizeCounts() (accepted, dropped, coerced int) {
+	if r == nil {
+		return 0, 0, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gamesAccepted, r.gamesDropped, r.gamesCoerced
+}
+
+// GamesAdded returns the number of new games seen by the poller.
+func (r *Recorder) GamesAdded() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gamesAdded
+}
+
+// StatusChanges returns the number of game status transitions seen by the poller.
+func (r *Recorder) StatusChanges() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statusChanges
+}
+
+// ScoreUpdates returns the number of score changes seen by the poller.
+func (r *Recorder) ScoreUpdates() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.scoreUpdates
+}
+
+// RecordWebhookDelivery tracks the outcome of a webhook delivery after all retries.
+func (r *Recorder) RecordWebhookDelivery(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if err != nil {
+		r.webhookFails++
+	} else {
+		r.webhooksSent++
+	}
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordWebhookDelivery(err)
+	}
+}
+
+// RecordWebhookDropped tracks a webhook event discarded because the queue was full.
+func (r *Recorder) RecordWebhookDropped() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.webhookDrops++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordWebhookDropped()
+	}
+}
+
+// WebhookStats returns delivered, failed, and dropped webhook event counts.
+func (r *Recorder) WebhookStats() (sent, failed, dropped int) {
+	if r == nil {
+		return 0, 0, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.webhooksSent, r.webhookFails, r.webhookDrops
+}
+
+// RecordCoalescedRequest tracks a request on path that shared another in-flight request's response.
+func (r *Recorder) RecordCoalescedRequest(path string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.coalesced++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordCoalescedRequest(path)
+	}
+}
+
+// CoalescedRequests returns how many requests were served from a shared response.
+func (r *Recorder) CoalescedRequests() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.coalesced
+}
+
+// RecordProviderCache tracks a provider page lookup; hit marks one served from
+// cache after the upstream answered 304 Not Modified.
+func (r *Recorder) RecordProviderCache(provider string, hit bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if hit {
+		r.cacheHits++
+	} else {
+		r.cacheMisses++
+	}
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordProviderCache(provider, hit)
+	}
+}
+
+// ProviderCacheStats returns provider page cache hits and misses.
+func (r *Recorder) ProviderCacheStats() (hits, misses int) {
+	if r == nil {
+		return 0, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cacheHits, r.cacheMisses
+}
+
+// RecordAPIKeyRequest tracks a request made with the named API key; limited
+// marks one rejected by the key's rate limit.
+func (r *Recorder) RecordAPIKeyRequest(name string, limited bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.apiKeyRequests == nil {
+		r.apiKeyRequests = make(map[string]int)
+	}
+	r.apiKeyRequests[name]++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordAPIKeyRequest(name, limited)
+	}
+}
+
+// APIKeyRequests returns how many requests the named key has made, limited or not.
+func (r *Recorder) APIKeyRequests(name string) int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.apiKeyRequests[name]
+}
+
+// RecordScoreAnomaly tracks a poller update that lowered a score or moved a
+// final game back; ignored marks one the poller discarded.
+func (r *Recorder) RecordScoreAnomaly(kind string, ignored bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if r.scoreAnomalies == nil {
+		r.scoreAnomalies = make(map[string]int)
+	}
+	r.scoreAnomalies[kind]++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordScoreAnomaly(kind, ignored)
+	}
+}
+
+// ScoreAnomalies returns how many anomalies of kind the poller has seen.
+func (r *Recorder) ScoreAnomalies(kind string) int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.scoreAnomalies[kind]
+}
+
+// RecordStreamDrop tracks a stream event discarded because a client's queue was full.
+func (r *Recorder) RecordStreamDrop() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.streamDrops++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordStreamDrop()
+	}
+}
+
+// RecordStreamForcedClose tracks a stream closed for dropping too many events.
+func (r *Recorder) RecordStreamForcedClose() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.streamKicks++
+	r.mu.Unlock()
+	if r.otel != nil {
+		r.otel.recordStreamForcedClose()
+	}
+}
+
+// SetStreamConnections reports the number of open stream connections for the
+// stream_connections gauge.
+func (r *Recorder) SetStreamConnections(n int) {
+	if r == nil {
+		return
+	}
+	r.otel.setStreamConnections(n)
+}
+
+// SetWarmStartGames reports how many games were pre-loaded from snapshots at
+// startup for the warm_start_games gauge.
+func (r *Recorder) SetWarmStartGames(n int) {
+	if r == nil {
+		return
+	}
+	r.otel.setWarmStartGames(n)
+}
+
+// StreamStats returns dropped stream events and forced stream closes.
+func (r *Recorder) StreamStats() (dropped, forcedCloses int) {
+	if r == nil {
+		return 0, 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.streamDrops, r.streamKicks
+}
+
+// SetProviderLimit changes the provider cap (values <= 0 use DefaultMaxProviders)
+// and sets the logger for evictions. Call before the recorder is shared.
+func (r *Recorder) SetProviderLimit(maxProviders int, logger *slog.Logger) {
+	if r == nil {
+		return
+	}
+	if maxProviders <= 0 {
+		maxProviders = DefaultMaxProviders
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxProviders = maxProviders
+	r.logger = logger
+}
+
+// SetClock sets the clock the next_run_seconds and snapshot_age_seconds
+// gauges measure against; nil restores the real clock.
+func (r *Recorder) SetClock(clk clock.Clock) {
+	if r == nil {
+		return
+	}
+	r.nextRuns.setClock(clk)
+	r.observables.setClock(clk)
+}
+
+// TrackedProviders returns how many provider names currently have stats.
+func (r *Recorder) TrackedProviders() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.stats)
+}
+
+// updateStats applies fn to provider's stats under the lock, creating them (and
+// evicting the least recently updated provider when at the cap) as needed.
+func (r *Recorder) updateStats(provider string, fn func(*providerStats)) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	stats, ok := r.stats[provider]
 	if !ok {
-		stats = &providerStats{}
+		if len(r.stats) >= r.maxProviders {
+			r.evictOldest()
+		}
+		_, wasEvicted := r.evicted[provider]
+		stats = &providerStats{evicted: wasEvicted}
 		r.stats[provider] = stats
+		r.otel.setTrackedProviders(len(r.stats))
+	}
+	r.tick++
+	stats.touched = r.tick
+	fn(stats)
+}
+
+// evictOldest drops the least recently updated provider. Caller holds r.mu.
+func (r *Recorder) evictOldest() {
+	var (
+		oldest  string
+		touched uint64
+		found   bool
+	)
+	for name, stats := range r.stats {
+		if !found || stats.touched < touched {
+			oldest, touched, found = name, stats.touched, true
+		}
+	}
+	if !found {
+		return
+	}
+	delete(r.stats, oldest)
+	r.markEvicted(oldest)
+	logging.Warn(r.logger, "metrics provider stats evicted",
+		"provider", oldest,
+		"max_providers", r.maxProviders,
+	)
+}
+
+// markEvicted remembers name as evicted, forgetting the oldest names so the set
+// stays within the same cap as stats. Caller holds r.mu.
+func (r *Recorder) markEvicted(name string) {
+	if _, ok := r.evicted[name]; ok {
+		return
+	}
+	r.evicted[name] = struct{}{}
+	r.evictedOrder = append(r.evictedOrder, name)
+	for len(r.evictedOrder) > r.maxProviders {
+		delete(r.evicted, r.evictedOrder[0])
+		r.evictedOrder = r.evictedOrder[1:]
+	}
+}
+
+func (s providerStats) snapshot() Snapshot {
+	return Snapshot{
+		Calls:                s.calls,
+		Errors:               s.errors,
+		RateLimitHits:        s.rateLimitHits,
+		RetryBudgetExhausted: s.budgetExhausted,
+		LastRetryAfter:       s.lastRetryAfter,
+		LastCallLatency:      s.lastCallLatency,
+		Evicted:              s.evicted,
 	}
-	return stats
 }
 
 func (r *Recorder) snapshot(provider string) providerStats {
@@ -148,5 +745,6 @@ func (r *Recorder) snapshot(provider string) providerStats {
 	if stats, ok := r.stats[provider]; ok && stats != nil {
 		return *stats
 	}
-	return providerStats{}
+	_, evicted := r.evicted[provider]
+	return providerStats{evicted: evicted}
 }
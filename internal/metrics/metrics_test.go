@@ -1,14 +1,20 @@
 package metrics
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
 func TestRecorderTracksProviderAttemptsAndErrors(t *testing.T) {
@@ -47,6 +53,17 @@ func TestRecorderTracksRateLimits(t *testing.T) {
 	}
 }
 
+func TestRecorderTracksRetryBudgetExhaustion(t *testing.T) {
+	rec := NewRecorder()
+	rec.RecordRetryBudgetExhausted("balldontlie")
+	rec.RecordRetryBudgetExhausted("balldontlie")
+	if got := rec.RetryBudgetExhausted("balldontlie"); got != 2 {
+		t.Fatalf("expected 2 exhausted retries, got %d", got)
+	}
+	var nilRec *Recorder
+	nilRec.RecordRetryBudgetExhausted("balldontlie")
+}
+
 func TestRecorderNilSafeOtelPaths(t *testing.T) {
 	r := NewRecorder()
 	// Ensure otel-less recorder does not panic.
@@ -75,6 +92,13 @@ func TestRecorderWithOtelInstruments(t *testing.T) {
 	rec.RecordProviderAttempt("fixture", 2*time.Millisecond, nil)
 	rec.RecordRateLimit("fixture", time.Second)
 	rec.RecordPollerCycle(time.Millisecond, errors.New("fail"))
+	rec.RecordPollerTimeout()
+	rec.RecordGameChanges(1, 2, 3)
+	rec.RecordGameChanges(0, 0, 0)
+	rec.RecordNormalize(3, 1, 1)
+	rec.RecordWebhookDelivery(nil)
+	rec.RecordWebhookDelivery(errors.New("fail"))
+	rec.RecordWebhookDropped()
 }
 
 func TestRecorderNilSafeSnapshotAndRecords(t *testing.T) {
@@ -109,6 +133,21 @@ func TestRecorderRateLimitRecordsRetryAfter(t *testing.T) {
 	}
 }
 
+func TestRecorderTracksPollerTimeouts(t *testing.T) {
+	r := NewRecorder()
+	r.RecordPollerTimeout()
+	r.RecordPollerTimeout()
+	if got := r.PollerTimeouts(); got != 2 {
+		t.Fatalf("expected 2 poller timeouts, got %d", got)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordPollerTimeout()
+	if got := nilRec.PollerTimeouts(); got != 0 {
+		t.Fatalf("expected nil recorder to report 0 timeouts, got %d", got)
+	}
+}
+
 func TestRecorderPollerCycleRecordsError(t *testing.T) {
 	r := NewRecorder()
 	r.RecordPollerCycle(time.Millisecond, context.DeadlineExceeded)
@@ -131,3 +170,313 @@ func TestRecordCounterAndHistogram(t *testing.T) {
 	inst.recordCounter(counter, 3, attrs...)
 	inst.recordHistogram(hist, 5.5, attrs...)
 }
+
+func TestRecorderTracksGameChanges(t *testing.T) {
+	r := NewRecorder()
+	r.RecordGameChanges(2, 1, 3)
+	r.RecordGameChanges(0, 1, 1)
+	if r.GamesAdded() != 2 || r.StatusChanges() != 2 || r.ScoreUpdates() != 4 {
+		t.Fatalf("unexpected game change counts added=%d status=%d score=%d", r.GamesAdded(), r.StatusChanges(), r.ScoreUpdates())
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordGameChanges(1, 1, 1)
+	if nilRec.GamesAdded() != 0 || nilRec.StatusChanges() != 0 || nilRec.ScoreUpdates() != 0 {
+		t.Fatalf("expected nil recorder to report zero game changes")
+	}
+}
+
+func TestRecorderTracksNormalize(t *testing.T) {
+	r := NewRecorder()
+	r.RecordNormalize(5, 1, 2)
+	r.RecordNormalize(3, 0, 0)
+	if accepted, dropped, coerced := r.NormalizeCounts(); accepted != 8 || dropped != 1 || coerced != 2 {
+		t.Fatalf("unexpected normalize counts accepted=%d dropped=%d coerced=%d", accepted, dropped, coerced)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordNormalize(1, 1, 1)
+	if accepted, dropped, coerced := nilRec.NormalizeCounts(); accepted != 0 || dropped != 0 || coerced != 0 {
+		t.Fatalf("expected nil recorder to report zero normalize counts")
+	}
+}
+
+func TestRecorderTracksWebhookDeliveries(t *testing.T) {
+	r := NewRecorder()
+	r.RecordWebhookDelivery(nil)
+	r.RecordWebhookDelivery(nil)
+	r.RecordWebhookDelivery(errors.New("boom"))
+	r.RecordWebhookDropped()
+	if sent, failed, dropped := r.WebhookStats(); sent != 2 || failed != 1 || dropped != 1 {
+		t.Fatalf("unexpected webhook stats sent=%d failed=%d dropped=%d", sent, failed, dropped)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordWebhookDelivery(nil)
+	nilRec.RecordWebhookDropped()
+	if sent, failed, dropped := nilRec.WebhookStats(); sent+failed+dropped != 0 {
+		t.Fatalf("expected nil recorder to report zero webhook stats")
+	}
+}
+
+func TestRecorderTracksStreamDrops(t *testing.T) {
+	r := NewRecorder()
+	r.RecordStreamDrop()
+	r.RecordStreamDrop()
+	r.RecordStreamForcedClose()
+	r.SetStreamConnections(3)
+	if dropped, closes := r.StreamStats(); dropped != 2 || closes != 1 {
+		t.Fatalf("unexpected stream stats dropped=%d closes=%d", dropped, closes)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordStreamDrop()
+	nilRec.RecordStreamForcedClose()
+	nilRec.SetStreamConnections(1)
+	nilRec.SetWarmStartGames(1)
+	if dropped, closes := nilRec.StreamStats(); dropped+closes != 0 {
+		t.Fatalf("expected nil recorder to report zero stream stats")
+	}
+}
+
+func TestRecorderSecondsUntilNextRun(t *testing.T) {
+	rec := NewRecorder()
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	rec.SetClock(teststubs.NewFakeClock(now))
+
+	if _, ok := rec.SecondsUntilNextRun("poller"); ok {
+		t.Fatalf("expected unknown component to report no next run")
+	}
+	at := now.Add(30 * time.Second)
+	rec.ObserveNextRun("poller", func() (time.Time, bool) { return at, true })
+	if got, ok := rec.SecondsUntilNextRun("poller"); !ok || got != 30 {
+		t.Fatalf("expected 30s countdown, got %v ok=%v", got, ok)
+	}
+	at = now.Add(-time.Second)
+	if got, _ := rec.SecondsUntilNextRun("poller"); got != 0 {
+		t.Fatalf("expected overdue run clamped to 0, got %v", got)
+	}
+	rec.ObserveNextRun("poller", nil)
+	if _, ok := rec.SecondsUntilNextRun("poller"); ok {
+		t.Fatalf("expected removed component to report no next run")
+	}
+
+	var nilRec *Recorder
+	nilRec.ObserveNextRun("poller", func() (time.Time, bool) { return at, true })
+	if _, ok := nilRec.SecondsUntilNextRun("poller"); ok {
+		t.Fatalf("expected nil recorder to report no next run")
+	}
+}
+
+func TestRecorderCapsTrackedProvidersWithLRUEviction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	rec := NewRecorderWithLimit(3, logger)
+
+	rec.RecordProviderAttempt("primary", time.Millisecond, nil)
+	for i := 0; i < 50; i++ {
+		rec.RecordProviderAttempt(fmt.Sprintf("shadow-%d", i), time.Millisecond, nil)
+		// Keep primary recently used so it survives the churn.
+		rec.RecordRateLimit("primary", 0)
+		if got := rec.TrackedProviders(); got > 3 {
+			t.Fatalf("expected at most 3 tracked providers, got %d", got)
+		}
+	}
+
+	if got := rec.TrackedProviders(); got != 3 {
+		t.Fatalf("expected cap of 3 providers, got %d", got)
+	}
+	primary := rec.Snapshot("primary")
+	if primary.Calls != 1 || primary.RateLimitHits != 50 || primary.Evicted {
+		t.Fatalf("expected recently used provider kept intact, got %+v", primary)
+	}
+	if snap := rec.Snapshot("shadow-47"); !snap.Evicted || snap.Calls != 0 {
+		t.Fatalf("expected evicted provider flagged with no stats, got %+v", snap)
+	}
+	if snap := rec.Snapshot("shadow-49"); snap.Evicted || snap.Calls != 1 {
+		t.Fatalf("expected newest provider tracked, got %+v", snap)
+	}
+	if !strings.Contains(buf.String(), "metrics provider stats evicted") {
+		t.Fatalf("expected eviction logged, got %q", buf.String())
+	}
+
+	// A re-tracked provider restarts from zero but keeps the evicted marker.
+	rec.RecordProviderAttempt("shadow-47", time.Millisecond, nil)
+	if snap := rec.Snapshot("shadow-47"); !snap.Evicted || snap.Calls != 1 {
+		t.Fatalf("expected re-tracked provider flagged as evicted, got %+v", snap)
+	}
+	// Eviction markers are bounded too, so long-gone names are forgotten.
+	if rec.Snapshot("shadow-0").Evicted || rec.Snapshot("never-seen").Evicted {
+		t.Fatalf("expected forgotten and unknown providers not flagged as evicted")
+	}
+}
+
+func TestRecorderDefaultProviderLimit(t *testing.T) {
+	rec := NewRecorderWithLimit(0, nil)
+	for i := 0; i < DefaultMaxProviders+10; i++ {
+		rec.RecordProviderAttempt(fmt.Sprintf("p-%d", i), time.Millisecond, nil)
+	}
+	if got := rec.TrackedProviders(); got != DefaultMaxProviders {
+		t.Fatalf("expected default cap %d, got %d", DefaultMaxProviders, got)
+	}
+	var nilRec *Recorder
+	nilRec.SetProviderLimit(1, nil)
+	if nilRec.TrackedProviders() != 0 {
+		t.Fatalf("expected nil recorder to track nothing")
+	}
+}
+
+func TestRecorderCountsAPIKeyRequestsByName(t *testing.T) {
+	r := NewRecorder()
+	r.RecordAPIKeyRequest("partner-a", false)
+	r.RecordAPIKeyRequest("partner-a", true)
+	r.RecordAPIKeyRequest("partner-b", false)
+	if a, b := r.APIKeyRequests("partner-a"), r.APIKeyRequests("partner-b"); a != 2 || b != 1 {
+		t.Fatalf("expected 2 and 1 requests, got %d and %d", a, b)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordAPIKeyRequest("partner-a", false)
+	if nilRec.APIKeyRequests("partner-a") != 0 {
+		t.Fatalf("expected nil recorder to report zero")
+	}
+}
+
+func TestRecorderCountsCoalescedRequests(t *testing.T) {
+	r := NewRecorder()
+	r.RecordCoalescedRequest("/games")
+	r.RecordCoalescedRequest("/games")
+	if got := r.CoalescedRequests(); got != 2 {
+		t.Fatalf("expected 2 coalesced requests, got %d", got)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordCoalescedRequest("/games")
+	if nilRec.CoalescedRequests() != 0 {
+		t.Fatalf("expected nil recorder to report zero coalesced requests")
+	}
+}
+
+func TestRecorderCountsScoreAnomalies(t *testing.T) {
+	r := NewRecorder()
+	r.RecordScoreAnomaly(AnomalyFinalRegression, true)
+	r.RecordScoreAnomaly(AnomalyFinalRegression, false)
+	r.RecordScoreAnomaly(AnomalyScoreCorrection, false)
+	if got := r.ScoreAnomalies(AnomalyFinalRegression); got != 2 {
+		t.Fatalf("expected 2 final regressions, got %d", got)
+	}
+	if got := r.ScoreAnomalies(AnomalyScoreCorrection); got != 1 {
+		t.Fatalf("expected 1 score correction, got %d", got)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordScoreAnomaly(AnomalyFinalRegression, true)
+	if nilRec.ScoreAnomalies(AnomalyFinalRegression) != 0 {
+		t.Fatalf("expected nil recorder to report zero anomalies")
+	}
+}
+
+func TestRecorderTracksRateLimitRemaining(t *testing.T) {
+	r := NewRecorder()
+	if _, ok := r.RateLimitRemaining("balldontlie"); ok {
+		t.Fatalf("expected no quota before one is reported")
+	}
+	r.SetRateLimitRemaining("balldontlie", 10)
+	r.SetRateLimitRemaining("balldontlie", 9)
+	if n, ok := r.RateLimitRemaining("balldontlie"); !ok || n != 9 {
+		t.Fatalf("expected remaining 9, got %d %v", n, ok)
+	}
+
+	var nilRec *Recorder
+	nilRec.SetRateLimitRemaining("balldontlie", 1)
+	nilRec.RecordProviderRequest("balldontlie", "GET", "/games", 200, time.Millisecond)
+	if _, ok := nilRec.RateLimitRemaining("balldontlie"); ok {
+		t.Fatalf("expected nil recorder to report no quota")
+	}
+}
+
+func TestRecorderTracksQuotaEstimate(t *testing.T) {
+	r := NewRecorder()
+	if _, ok := r.QuotaEstimate("balldontlie"); ok {
+		t.Fatalf("expected no estimate before one is reported")
+	}
+	want := QuotaEstimate{Remaining: 3, Known: true, Throttled: true, Interval: 2 * time.Minute}
+	r.SetQuotaEstimate("balldontlie", want)
+	if got, ok := r.QuotaEstimate("balldontlie"); !ok || got != want {
+		t.Fatalf("expected %+v, got %+v %v", want, got, ok)
+	}
+
+	var nilRec *Recorder
+	nilRec.SetQuotaEstimate("balldontlie", want)
+	if _, ok := nilRec.QuotaEstimate("balldontlie"); ok {
+		t.Fatalf("expected nil recorder to report no estimate")
+	}
+}
+
+func TestRecorderCountsProviderCache(t *testing.T) {
+	r := NewRecorder()
+	r.RecordProviderCache("balldontlie", false)
+	r.RecordProviderCache("balldontlie", true)
+	r.RecordProviderCache("balldontlie", true)
+	if hits, misses := r.ProviderCacheStats(); hits != 2 || misses != 1 {
+		t.Fatalf("expected 2 hits and 1 miss, got %d and %d", hits, misses)
+	}
+
+	var nilRec *Recorder
+	nilRec.RecordProviderCache("balldontlie", true)
+	if hits, misses := nilRec.ProviderCacheStats(); hits != 0 || misses != 0 {
+		t.Fatalf("expected nil recorder to report zero")
+	}
+}
+
+func TestRecorderAllSnapshotsAndTotal(t *testing.T) {
+	r := NewRecorder()
+	r.RecordProviderAttempt("balldontlie", 10*time.Millisecond, nil)
+	r.RecordProviderAttempt("balldontlie", 20*time.Millisecond, errors.New("boom"))
+	r.RecordRateLimit("balldontlie", 3*time.Second)
+	r.RecordProviderAttempt("fixture", 5*time.Millisecond, nil)
+	r.RecordRetryBudgetExhausted("fixture")
+
+	all := r.AllSnapshots()
+	if len(all) != 2 || all["balldontlie"].Calls != 2 || all["balldontlie"].Errors != 1 || all["fixture"].Calls != 1 {
+		t.Fatalf("unexpected per-provider snapshots %+v", all)
+	}
+
+	total := r.TotalSnapshot()
+	if total.Calls != 3 || total.Errors != 1 || total.RateLimitHits != 1 || total.RetryBudgetExhausted != 1 {
+		t.Fatalf("unexpected total %+v", total)
+	}
+	if total.LastCallLatency != 5*time.Millisecond || total.LastRetryAfter != 0 {
+		t.Fatalf("expected last values from the most recently updated provider, got %+v", total)
+	}
+
+	all["balldontlie"] = Snapshot{}
+	delete(all, "fixture")
+	r.RecordProviderAttempt("balldontlie", time.Millisecond, nil)
+	if again := r.AllSnapshots(); again["balldontlie"].Calls != 3 || again["fixture"].Calls != 1 {
+		t.Fatalf("expected the returned map to be a copy, got %+v", again)
+	}
+	if all["balldontlie"].Calls != 0 {
+		t.Fatalf("expected later records not to reach an earlier copy")
+	}
+}
+
+func TestRecorderResetForgetsProvider(t *testing.T) {
+	r := NewRecorderWithLimit(1, nil)
+	r.RecordProviderAttempt("a", time.Millisecond, nil)
+	r.RecordProviderAttempt("b", time.Millisecond, nil) // evicts a
+	r.Reset("a")
+	r.Reset("b")
+	if snap := r.Snapshot("a"); snap.Evicted || snap.Calls != 0 {
+		t.Fatalf("expected reset to clear the eviction mark, got %+v", snap)
+	}
+	if len(r.AllSnapshots()) != 0 || r.TotalSnapshot() != (Snapshot{}) {
+		t.Fatalf("expected no providers after reset")
+	}
+
+	var nilRec *Recorder
+	nilRec.Reset("a")
+	if len(nilRec.AllSnapshots()) != 0 || nilRec.TotalSnapshot() != (Snapshot{}) {
+		t.Fatalf("expected nil recorder to report nothing")
+	}
+}
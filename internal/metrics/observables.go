@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+)
+
+// StoreSizer reports how many games the service is currently serving.
+type StoreSizer interface {
+	GamesInStore() int
+}
+
+// SnapshotFreshness reports when each snapshot kind (e.g. "games") was last
+// refreshed; kinds never refreshed are omitted.
+type SnapshotFreshness interface {
+	LastRefreshed() map[string]time.Time
+}
+
+// observables holds the sources behind the store gauges. Like nextRuns it is
+// shared between the Recorder and the OTel callback, so sources registered
+// after Setup are still read.
+type observables struct {
+	mu    sync.RWMutex
+	store StoreSizer
+	fresh SnapshotFreshness
+	clock clock.Clock
+}
+
+func newObservables() *observables {
+	return &observables{clock: clock.Real()}
+}
+
+func (o *observables) set(store StoreSizer, fresh SnapshotFreshness) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.store = store
+	o.fresh = fresh
+}
+
+func (o *observables) setClock(clk clock.Clock) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.clock = clock.OrReal(clk)
+}
+
+// gamesInStore returns the store's game count; ok is false with no store.
+func (o *observables) gamesInStore() (int, bool) {
+	o.mu.RLock()
+	store := o.store
+	o.mu.RUnlock()
+	if store == nil {
+		return 0, false
+	}
+	return store.GamesInStore(), true
+}
+
+// snapshotAges returns seconds since each kind's last refresh, clamped at zero.
+func (o *observables) snapshotAges() map[string]float64 {
+	o.mu.RLock()
+	fresh := o.fresh
+	clk := o.clock
+	o.mu.RUnlock()
+	if fresh == nil {
+		return nil
+	}
+	now := clk.Now()
+	ages := make(map[string]float64)
+	for kind, at := range fresh.LastRefreshed() {
+		if at.IsZero() {
+			continue
+		}
+		ages[kind] = max(now.Sub(at).Seconds(), 0)
+	}
+	return ages
+}
+
+// RegisterObservables makes the games_in_store and snapshot_age_seconds gauges
+// read from store and writer. Either may be nil to stop reporting that gauge.
+func (r *Recorder) RegisterObservables(store StoreSizer, writer SnapshotFreshness) {
+	if r == nil {
+		return
+	}
+	r.observables.set(store, writer)
+}
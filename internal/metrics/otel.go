@@ -3,6 +3,8 @@ package metrics
 import (
 	"context"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -22,13 +24,21 @@ var (
 	instrumentFactory = newOtelInstruments
 )
 
+// DefaultLatencyBuckets are the latency histogram boundaries, in
+// milliseconds, used when TelemetryConfig.LatencyBuckets is empty.
+var DefaultLatencyBuckets = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000}
+
+// latencyHistograms are the instruments LatencyBuckets applies to.
+var latencyHistograms = []string{"http_request_duration_ms", "provider_duration_ms", "provider_http_request_duration_ms", "poller_cycle_duration_ms"}
+
 // TelemetryConfig controls how metrics are exported.
 type TelemetryConfig struct {
-	Enabled      bool
-	Port         string
-	ServiceName  string
-	OtlpEndpoint string
-	OtlpInsecure bool
+	Enabled        bool
+	Port           string
+	ServiceName    string
+	OtlpEndpoint   string
+	OtlpInsecure   bool
+	LatencyBuckets []float64 // ascending ms boundaries; empty uses DefaultLatencyBuckets
 }
 
 // Setup configures OpenTelemetry metrics with a Prometheus exporter and optional OTLP exporter.
@@ -47,7 +57,10 @@ func Setup(ctx context.Context, cfg TelemetryConfig) (*Recorder, http.Handler, f
 		return nil, nil, nil, err
 	}
 
-	opts := []sdkmetric.Option{sdkmetric.WithReader(promReader)}
+	opts := []sdkmetric.Option{
+		sdkmetric.WithReader(promReader),
+		sdkmetric.WithView(latencyViews(cfg.LatencyBuckets)...),
+	}
 
 	if cfg.OtlpEndpoint != "" {
 		otlpReader, err := otlpReaderFactory(ctx, cfg.OtlpEndpoint, cfg.OtlpInsecure)
@@ -81,6 +94,22 @@ func Setup(ctx context.Context, cfg TelemetryConfig) (*Recorder, http.Handler, f
 	return rec, promHandler, shutdown, nil
 }
 
+// latencyViews sets explicit bucket boundaries on the latency histograms; the
+// SDK defaults are too coarse for this service's latencies.
+func latencyViews(buckets []float64) []sdkmetric.View {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	views := make([]sdkmetric.View, 0, len(latencyHistograms))
+	for _, name := range latencyHistograms {
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: name},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: buckets}},
+		))
+	}
+	return views
+}
+
 func buildOTLPReader(ctx context.Context, endpoint string, insecure bool) (sdkmetric.Reader, error) {
 	otlpOpts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint)}
 	if insecure {
@@ -102,10 +131,34 @@ type otelInstruments struct {
 	providerErrors    metric.Int64Counter
 	providerLatencyMs metric.Float64Histogram
 	rateLimitHits     metric.Int64Counter
+	budgetExhausted   metric.Int64Counter
 	retryAfterMs      metric.Float64Histogram
 	pollerCycles      metric.Int64Counter
 	pollerErrors      metric.Int64Counter
 	pollerLatencyMs   metric.Float64Histogram
+	pollerTimeouts    metric.Int64Counter
+	gamesAdded        metric.Int64Counter
+	statusChanges     metric.Int64Counter
+	scoreUpdates      metric.Int64Counter
+	webhooksSent      metric.Int64Counter
+	webhookFailures   metric.Int64Counter
+	webhookDrops      metric.Int64Counter
+	coalesced         metric.Int64Counter
+	providerCache     metric.Int64Counter
+	apiKeyRequests    metric.Int64Counter
+	streamDrops       metric.Int64Counter
+	streamKicks       metric.Int64Counter
+	gamesNormalized   metric.Int64Counter
+	scoreAnomalies    metric.Int64Counter
+	upstreamLatencyMs metric.Float64Histogram
+	nextRuns          *nextRuns
+	observables       *observables
+	trackedProviders  atomic.Int64
+	streamConnections atomic.Int64
+	warmStartGames    atomic.Int64
+
+	quotaMu sync.Mutex
+	quota   map[string]int64 // rate limit remaining by provider
 }
 
 func prometheusComponents() (sdkmetric.Reader, http.Handler, error) {
@@ -146,6 +199,12 @@ func newOtelInstruments(provider metric.MeterProvider) (*otelInstruments, error)
 	if err != nil {
 		return nil, err
 	}
+	budgetExhausted, err := meter.Int64Counter("provider_retry_budget_exhausted_total",
+		metric.WithDescription("Retries skipped because the shared retry budget was spent"),
+	)
+	if err != nil {
+		return nil, err
+	}
 	retryAfter, err := meter.Float64Histogram("provider_retry_after_ms")
 	if err != nil {
 		return nil, err
@@ -162,8 +221,149 @@ func newOtelInstruments(provider metric.MeterProvider) (*otelInstruments, error)
 	if err != nil {
 		return nil, err
 	}
+	pollerTimeouts, err := meter.Int64Counter("poller_timeouts_total")
+	if err != nil {
+		return nil, err
+	}
+	gamesAdded, err := meter.Int64Counter("games_added_total")
+	if err != nil {
+		return nil, err
+	}
+	statusChanges, err := meter.Int64Counter("game_status_changes_total")
+	if err != nil {
+		return nil, err
+	}
+	scoreUpdates, err := meter.Int64Counter("game_score_updates_total")
+	if err != nil {
+		return nil, err
+	}
+	webhooksSent, err := meter.Int64Counter("webhook_deliveries_total")
+	if err != nil {
+		return nil, err
+	}
+	webhookFailures, err := meter.Int64Counter("webhook_failures_total")
+	if err != nil {
+		return nil, err
+	}
+	webhookDrops, err := meter.Int64Counter("webhook_dropped_total")
+	if err != nil {
+		return nil, err
+	}
+	coalesced, err := meter.Int64Counter("http_coalesced_requests_total")
+	if err != nil {
+		return nil, err
+	}
+	providerCache, err := meter.Int64Counter("provider_cache_requests_total",
+		metric.WithDescription("Provider page lookups by outcome (hit on 304, miss on full fetch)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	apiKeyRequests, err := meter.Int64Counter("api_key_requests_total",
+		metric.WithDescription("Requests by API key name and outcome (allowed, limited)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	streamDrops, err := meter.Int64Counter("stream_events_dropped_total",
+		metric.WithDescription("Stream events discarded because a client's queue was full"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	streamKicks, err := meter.Int64Counter("stream_forced_closes_total",
+		metric.WithDescription("Streams closed for dropping too many events"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	gamesNormalized, err := meter.Int64Counter("games_normalized_total",
+		metric.WithDescription("Provider games by normalization outcome (accepted, dropped, coerced)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	scoreAnomalies, err := meter.Int64Counter("game_score_anomalies_total",
+		metric.WithDescription("Poller score regressions by kind and outcome (ignored, accepted)"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	upstreamLatency, err := meter.Float64Histogram("provider_http_request_duration_ms",
+		metric.WithDescription("Upstream HTTP requests sent by providers, by method, path and status"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	quota, err := meter.Int64ObservableGauge("provider_rate_limit_remaining",
+		metric.WithDescription("Requests left in the upstream rate limit window, from the last response"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	streamConns, err := meter.Int64ObservableGauge("stream_connections",
+		metric.WithDescription("Open /games/stream connections"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	warmStart, err := meter.Int64ObservableGauge("warm_start_games",
+		metric.WithDescription("Games pre-loaded from snapshots at startup"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	runs := newNextRuns()
+	nextRun, err := meter.Float64ObservableGauge("next_run_seconds",
+		metric.WithDescription("Seconds until the component's next scheduled run"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := meter.RegisterCallback(func(_ context.Context, obs metric.Observer) error {
+		for _, component := range runs.components() {
+			if seconds, ok := runs.secondsUntil(component); ok {
+				obs.ObserveFloat64(nextRun, seconds, metric.WithAttributes(attribute.String(AttrComponent, component)))
+			}
+		}
+		return nil
+	}, nextRun); err != nil {
+		return nil, err
+	}
+	obs := newObservables()
+	gamesInStore, err := meter.Int64ObservableGauge("games_in_store",
+		metric.WithDescription("Games in today's snapshot"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	snapshotAge, err := meter.Float64ObservableGauge("snapshot_age_seconds",
+		metric.WithDescription("Seconds since the snapshot kind was last refreshed"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		if n, ok := obs.gamesInStore(); ok {
+			o.ObserveInt64(gamesInStore, int64(n))
+		}
+		for kind, age := range obs.snapshotAges() {
+			o.ObserveFloat64(snapshotAge, age, metric.WithAttributes(attribute.String(AttrKind, kind)))
+		}
+		return nil
+	}, gamesInStore, snapshotAge); err != nil {
+		return nil, err
+	}
+	tracked, err := meter.Int64ObservableGauge("metrics_tracked_providers",
+		metric.WithDescription("Provider names with in-memory stats (capped; least recently updated are evicted)"),
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	return &otelInstruments{
+	inst := &otelInstruments{
 		ctx:               ctx,
 		meter:             meter,
 		requests:          requests,
@@ -172,14 +372,54 @@ func newOtelInstruments(provider metric.MeterProvider) (*otelInstruments, error)
 		providerErrors:    providerErrors,
 		providerLatencyMs: providerLatency,
 		rateLimitHits:     rateLimitHits,
+		budgetExhausted:   budgetExhausted,
 		retryAfterMs:      retryAfter,
 		pollerCycles:      pollerCycles,
 		pollerErrors:      pollerErrors,
 		pollerLatencyMs:   pollerLatency,
-	}, nil
+		pollerTimeouts:    pollerTimeouts,
+		gamesAdded:        gamesAdded,
+		statusChanges:     statusChanges,
+		scoreUpdates:      scoreUpdates,
+		webhooksSent:      webhooksSent,
+		webhookFailures:   webhookFailures,
+		webhookDrops:      webhookDrops,
+		coalesced:         coalesced,
+		providerCache:     providerCache,
+		apiKeyRequests:    apiKeyRequests,
+		streamDrops:       streamDrops,
+		streamKicks:       streamKicks,
+		gamesNormalized:   gamesNormalized,
+		scoreAnomalies:    scoreAnomalies,
+		upstreamLatencyMs: upstreamLatency,
+		quota:             make(map[string]int64),
+		nextRuns:          runs,
+		observables:       obs,
+	}
+	if _, err := meter.RegisterCallback(func(_ context.Context, obs metric.Observer) error {
+		obs.ObserveInt64(tracked, inst.trackedProviders.Load())
+		obs.ObserveInt64(streamConns, inst.streamConnections.Load())
+		obs.ObserveInt64(warmStart, inst.warmStartGames.Load())
+		inst.quotaMu.Lock()
+		for provider, n := range inst.quota {
+			obs.ObserveInt64(quota, n, metric.WithAttributes(attribute.String(AttrProvider, provider)))
+		}
+		inst.quotaMu.Unlock()
+		return nil
+	}, tracked, streamConns, warmStart, quota); err != nil {
+		return nil, err
+	}
+	return inst, nil
 }
 
-func (o *otelInstruments) recordHTTPRequest(method, path string, status int, duration time.Duration) {
+func (o *otelInstruments) setTrackedProviders(n int) {
+	if o == nil {
+		return
+	}
+	o.trackedProviders.Store(int64(n))
+}
+
+func (o *otelInstruments) recordHTTPRequest(method, path, client string, status int, duration time.Duration) {
 	if o == nil {
 		return
 	}
@@ -187,6 +427,7 @@ func (o *otelInstruments) recordHTTPRequest(method, path string, status int, dur
 		attribute.String(AttrMethod, method),
 		attribute.String(AttrPath, path),
 		attribute.Int(AttrStatus, status),
+		attribute.String(AttrClient, client),
 	}
 	o.recordCounter(o.requests, 1, attrs...)
 	o.recordHistogram(o.requestLatencyMs, float64(duration.Milliseconds()), attrs...)
@@ -215,6 +456,13 @@ func (o *otelInstruments) recordRateLimit(provider string, retryAfter time.Durat
 	}
 }
 
+func (o *otelInstruments) recordRetryBudgetExhausted(provider string) {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.budgetExhausted, 1, attribute.String(AttrProvider, provider))
+}
+
 func (o *otelInstruments) recordPoller(duration time.Duration, err error) {
 	if o == nil {
 		return
@@ -226,6 +474,146 @@ func (o *otelInstruments) recordPoller(duration time.Duration, err error) {
 	}
 }
 
+func (o *otelInstruments) recordPollerTimeout() {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.pollerTimeouts, 1)
+}
+
+func (o *otelInstruments) recordGameChanges(added, statusChanges, scoreUpdates int) {
+	if o == nil {
+		return
+	}
+	if added > 0 {
+		o.recordCounter(o.gamesAdded, int64(added))
+	}
+	if statusChanges > 0 {
+		o.recordCounter(o.statusChanges, int64(statusChanges))
+	}
+	if scoreUpdates > 0 {
+		o.recordCounter(o.scoreUpdates, int64(scoreUpdates))
+	}
+}
+
+func (o *otelInstruments) recordNormalize(accepted, dropped, coerced int) {
+	if o == nil {
+		return
+	}
+	for outcome, n := range map[string]int{"accepted": accepted, "dropped": dropped, "coerced": coerced} {
+		if n > 0 {
+			o.recordCounter(o.gamesNormalized, int64(n), attribute.String(AttrOutcome, outcome))
+		}
+	}
+}
+
+func (o *otelInstruments) recordWebhookDelivery(err error) {
+	if o == nil {
+		return
+	}
+	if err != nil {
+		o.recordCounter(o.webhookFailures, 1)
+		return
+	}
+	o.recordCounter(o.webhooksSent, 1)
+}
+
+func (o *otelInstruments) recordWebhookDropped() {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.webhookDrops, 1)
+}
+
+func (o *otelInstruments) recordCoalescedRequest(path string) {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.coalesced, 1, attribute.String(AttrPath, path))
+}
+
+func (o *otelInstruments) recordProviderCache(provider string, hit bool) {
+	if o == nil {
+		return
+	}
+	outcome := "miss"
+	if hit {
+		outcome = "hit"
+	}
+	o.recordCounter(o.providerCache, 1, attribute.String(AttrProvider, provider), attribute.String(AttrOutcome, outcome))
+}
+
+func (o *otelInstruments) recordAPIKeyRequest(name string, limited bool) {
+	if o == nil {
+		return
+	}
+	outcome := "allowed"
+	if limited {
+		outcome = "limited"
+	}
+	o.recordCounter(o.apiKeyRequests, 1, attribute.String(AttrAPIKey, name), attribute.String(AttrOutcome, outcome))
+}
+
+func (o *otelInstruments) recordStreamDrop() {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.streamDrops, 1)
+}
+
+func (o *otelInstruments) recordStreamForcedClose() {
+	if o == nil {
+		return
+	}
+	o.recordCounter(o.streamKicks, 1)
+}
+
+func (o *otelInstruments) recordProviderRequest(provider, method, path string, status int, duration time.Duration) {
+	if o == nil {
+		return
+	}
+	o.recordHistogram(o.upstreamLatencyMs, float64(duration.Milliseconds()),
+		attribute.String(AttrProvider, provider),
+		attribute.String(AttrMethod, method),
+		attribute.String(AttrPath, path),
+		attribute.Int(AttrStatus, status),
+	)
+}
+
+func (o *otelInstruments) setRateLimitRemaining(provider string, n int) {
+	if o == nil {
+		return
+	}
+	o.quotaMu.Lock()
+	o.quota[provider] = int64(n)
+	o.quotaMu.Unlock()
+}
+
+func (o *otelInstruments) recordScoreAnomaly(kind string, ignored bool) {
+	if o == nil {
+		return
+	}
+	outcome := "accepted"
+	if ignored {
+		outcome = "ignored"
+	}
+	o.recordCounter(o.scoreAnomalies, 1, attribute.String(AttrKind, kind), attribute.String(AttrOutcome, outcome))
+}
+
+func (o *otelInstruments) setStreamConnections(n int) {
+	if o == nil {
+		return
+	}
+	o.streamConnections.Store(int64(n))
+}
+
+func (o *otelInstruments) setWarmStartGames(n int) {
+	if o == nil {
+		return
+	}
+	o.warmStartGames.Store(int64(n))
+}
+
 func (o *otelInstruments) recordCounter(counter metric.Int64Counter, value int64, attrs ...attribute.KeyValue) {
 	if o == nil {
 		return
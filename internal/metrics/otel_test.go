@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,6 +13,8 @@ import (
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
 func TestSetupDisabledReturnsNoHandler(t *testing.T) {
@@ -148,10 +151,11 @@ func TestPrometheusComponentsReturnsReaderAndHandler(t *testing.T) {
 func TestOtelInstrumentsRecordingDoesNotPanic(t *testing.T) {
 	// nil receiver should be a no-op
 	var nilInst *otelInstruments
-	nilInst.recordHTTPRequest("GET", "/health", 200, time.Millisecond)
+	nilInst.recordHTTPRequest("GET", "/health", "anonymous", 200, time.Millisecond)
 	nilInst.recordProviderAttempt("p", time.Millisecond, nil)
 	nilInst.recordRateLimit("p", time.Second)
 	nilInst.recordPoller(time.Millisecond, errors.New("err"))
+	nilInst.recordScoreAnomaly(AnomalyFinalRegression, true)
 
 	reader := sdkmetric.NewManualReader()
 	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
@@ -159,13 +163,15 @@ func TestOtelInstrumentsRecordingDoesNotPanic(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected instruments, got %v", err)
 	}
-	inst.recordHTTPRequest("GET", "/games", 200, 50*time.Millisecond)
+	inst.recordHTTPRequest("GET", "/games", "bff", 200, 50*time.Millisecond)
 	inst.recordProviderAttempt("balldontlie", 75*time.Millisecond, nil)
 	inst.recordProviderAttempt("balldontlie", 90*time.Millisecond, errors.New("fail"))
 	inst.recordRateLimit("balldontlie", 2*time.Second)
 	inst.recordRateLimit("balldontlie", 0)
 	inst.recordPoller(120*time.Millisecond, nil)
 	inst.recordPoller(130*time.Millisecond, errors.New("poller"))
+	inst.recordScoreAnomaly(AnomalyFinalRegression, true)
+	inst.recordScoreAnomaly(AnomalyScoreCorrection, false)
 }
 
 func TestSetupWithOTLPEnabledUsesDefaultFactories(t *testing.T) {
@@ -272,6 +278,8 @@ type scriptedMeter struct {
 	metric.Meter
 	failCounter   string
 	failHistogram string
+	failGauge     string
+	failIntGauge  string
 }
 
 func (m scriptedMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
@@ -288,6 +296,20 @@ func (m scriptedMeter) Float64Histogram(name string, opts ...metric.Float64Histo
 	return m.Meter.Float64Histogram(name, opts...)
 }
 
+func (m scriptedMeter) Float64ObservableGauge(name string, opts ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	if name == m.failGauge {
+		return nil, errors.New("gauge fail")
+	}
+	return m.Meter.Float64ObservableGauge(name, opts...)
+}
+
+func (m scriptedMeter) Int64ObservableGauge(name string, opts ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	if name == m.failIntGauge {
+		return nil, errors.New("gauge fail")
+	}
+	return m.Meter.Int64ObservableGauge(name, opts...)
+}
+
 type scriptedProvider struct {
 	metric.MeterProvider
 	m metric.Meter
@@ -309,10 +331,21 @@ func TestNewOtelInstrumentsErrorBranches(t *testing.T) {
 		{"provider_errors_total", false},
 		{"provider_duration_ms", true},
 		{"provider_rate_limit_hits_total", false},
+		{"provider_retry_budget_exhausted_total", false},
 		{"provider_retry_after_ms", true},
 		{"poller_cycles_total", false},
 		{"poller_errors_total", false},
 		{"poller_cycle_duration_ms", true},
+		{"poller_timeouts_total", false},
+		{"games_added_total", false},
+		{"game_status_changes_total", false},
+		{"game_score_updates_total", false},
+		{"webhook_deliveries_total", false},
+		{"webhook_failures_total", false},
+		{"webhook_dropped_total", false},
+		{"http_coalesced_requests_total", false},
+		{"provider_cache_requests_total", false},
+		{"api_key_requests_total", false},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -330,6 +363,80 @@ func TestNewOtelInstrumentsErrorBranches(t *testing.T) {
 	}
 }
 
+func TestNewOtelInstrumentsGaugeErrors(t *testing.T) {
+	for _, m := range []scriptedMeter{
+		{Meter: noop.NewMeterProvider().Meter("base"), failGauge: "next_run_seconds"},
+		{Meter: noop.NewMeterProvider().Meter("base"), failIntGauge: "metrics_tracked_providers"},
+	} {
+		provider := scriptedProvider{MeterProvider: noop.NewMeterProvider(), m: m}
+		if _, err := newOtelInstruments(provider); err == nil {
+			t.Fatalf("expected error for gauge %s%s", m.failGauge, m.failIntGauge)
+		}
+	}
+}
+
+func TestTrackedProvidersGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := newOtelInstruments(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("expected instruments, got %v", err)
+	}
+	rec := newRecorder(inst)
+	rec.SetProviderLimit(2, nil)
+	for _, name := range []string{"a", "b", "c"} {
+		rec.RecordProviderAttempt(name, time.Millisecond, nil)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "metrics_tracked_providers" {
+				points := m.Data.(metricdata.Gauge[int64]).DataPoints
+				if len(points) != 1 || points[0].Value != 2 {
+					t.Fatalf("expected tracked providers gauge at cap 2, got %+v", points)
+				}
+				return
+			}
+		}
+	}
+	t.Fatalf("metrics_tracked_providers not collected")
+}
+
+func TestNextRunGaugeReportsRunningComponents(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := newOtelInstruments(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("expected instruments, got %v", err)
+	}
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	inst.nextRuns.setClock(teststubs.NewFakeClock(now))
+	rec := newRecorder(inst)
+	rec.ObserveNextRun("poller", func() (time.Time, bool) { return now.Add(90 * time.Second), true })
+	rec.ObserveNextRun("snapshot_sync", func() (time.Time, bool) { return time.Time{}, false })
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	var points []metricdata.DataPoint[float64]
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == "next_run_seconds" {
+				points = m.Data.(metricdata.Gauge[float64]).DataPoints
+			}
+		}
+	}
+	if len(points) != 1 {
+		t.Fatalf("expected only the running component observed, got %d points", len(points))
+	}
+	if component, _ := points[0].Attributes.Value(AttrComponent); component.AsString() != "poller" || points[0].Value != 90 {
+		t.Fatalf("unexpected gauge point %+v", points[0])
+	}
+}
+
 func TestSetupFailsWhenInstrumentFactoryErrors(t *testing.T) {
 	orig := instrumentFactory
 	defer func() { instrumentFactory = orig }()
@@ -342,3 +449,178 @@ func TestSetupFailsWhenInstrumentFactoryErrors(t *testing.T) {
 		t.Fatalf("expected setup to fail when instrument factory errors")
 	}
 }
+
+type countingStore struct{ games int }
+
+func (s *countingStore) GamesInStore() int { return s.games }
+
+type refreshTimes map[string]time.Time
+
+func (r refreshTimes) LastRefreshed() map[string]time.Time { return r }
+
+func TestStoreGaugesReadRegisteredSources(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := newOtelInstruments(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("expected instruments, got %v", err)
+	}
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	inst.observables.setClock(teststubs.NewFakeClock(now))
+	rec := newRecorder(inst)
+
+	collect := func() (games int64, gamesSeen bool, ages map[string]float64) {
+		t.Helper()
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Fatalf("collect: %v", err)
+		}
+		ages = map[string]float64{}
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch m.Name {
+				case "games_in_store":
+					for _, p := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+						games, gamesSeen = p.Value, true
+					}
+				case "snapshot_age_seconds":
+					for _, p := range m.Data.(metricdata.Gauge[float64]).DataPoints {
+						kind, _ := p.Attributes.Value(AttrKind)
+						ages[kind.AsString()] = p.Value
+					}
+				}
+			}
+		}
+		return games, gamesSeen, ages
+	}
+
+	if _, seen, ages := collect(); seen || len(ages) != 0 {
+		t.Fatalf("expected no store gauges before registration, got seen=%v ages=%v", seen, ages)
+	}
+
+	store := &countingStore{}
+	fresh := refreshTimes{"games": now.Add(-90 * time.Second)}
+	rec.RegisterObservables(store, fresh)
+	if games, seen, ages := collect(); !seen || games != 0 || ages["games"] != 90 {
+		t.Fatalf("expected empty store and 90s-old games snapshot, got %d (seen %v) ages %v", games, seen, ages)
+	}
+
+	store.games = 12
+	fresh["games"] = now
+	if games, _, ages := collect(); games != 12 || ages["games"] != 0 {
+		t.Fatalf("expected gauges to follow the sources, got %d ages %v", games, ages)
+	}
+}
+
+func TestWarmStartGamesGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := newOtelInstruments(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("expected instruments, got %v", err)
+	}
+	newRecorder(inst).SetWarmStartGames(7)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "warm_start_games" {
+				continue
+			}
+			if points := m.Data.(metricdata.Gauge[int64]).DataPoints; len(points) != 1 || points[0].Value != 7 {
+				t.Fatalf("expected warm_start_games 7, got %+v", points)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a warm_start_games gauge")
+}
+
+func TestRateLimitRemainingGauge(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	inst, err := newOtelInstruments(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatalf("expected instruments, got %v", err)
+	}
+	rec := newRecorder(inst)
+	rec.SetRateLimitRemaining("balldontlie", 12)
+	rec.SetRateLimitRemaining("balldontlie", 11)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "provider_rate_limit_remaining" {
+				continue
+			}
+			if points := m.Data.(metricdata.Gauge[int64]).DataPoints; len(points) != 1 || points[0].Value != 11 {
+				t.Fatalf("expected provider_rate_limit_remaining 11, got %+v", points)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected a provider_rate_limit_remaining gauge")
+}
+
+func TestSetupAppliesLatencyBuckets(t *testing.T) {
+	origProm := promReaderFactory
+	defer func() { promReaderFactory = origProm }()
+
+	cases := []struct {
+		name    string
+		buckets []float64
+		want    []float64
+	}{
+		{name: "defaults", want: DefaultLatencyBuckets},
+		{name: "configured", buckets: []float64{1, 2, 3}, want: []float64{1, 2, 3}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			promReaderFactory = func() (sdkmetric.Reader, http.Handler, error) {
+				return reader, http.NewServeMux(), nil
+			}
+			rec, _, shutdown, err := Setup(context.Background(), TelemetryConfig{Enabled: true, LatencyBuckets: tc.buckets})
+			if err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+			defer func() { _ = shutdown(context.Background()) }()
+			rec.RecordHTTPRequest("GET", "/games", 200, 30*time.Millisecond)
+			rec.RecordProviderAttempt("balldontlie", 80*time.Millisecond, nil)
+			rec.RecordPollerCycle(120*time.Millisecond, nil)
+			rec.RecordProviderRequest("balldontlie", "GET", "/games", 200, 70*time.Millisecond)
+			rec.RecordRateLimit("balldontlie", time.Second)
+
+			var rm metricdata.ResourceMetrics
+			if err := reader.Collect(context.Background(), &rm); err != nil {
+				t.Fatalf("collect: %v", err)
+			}
+			seen := map[string]bool{}
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					hist, ok := m.Data.(metricdata.Histogram[float64])
+					if !ok {
+						continue
+					}
+					bounds := hist.DataPoints[0].Bounds
+					if !slices.Contains(latencyHistograms, m.Name) {
+						if slices.Equal(bounds, tc.want) {
+							t.Fatalf("expected %s to keep the SDK buckets", m.Name)
+						}
+						continue
+					}
+					if !slices.Equal(bounds, tc.want) {
+						t.Fatalf("%s: expected buckets %v, got %v", m.Name, tc.want, bounds)
+					}
+					seen[m.Name] = true
+				}
+			}
+			if len(seen) != len(latencyHistograms) {
+				t.Fatalf("expected buckets on %v, saw %v", latencyHistograms, seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+)
+
+// NextRunFunc reports when a component next runs; ok is false while it is stopped.
+type NextRunFunc func() (at time.Time, ok bool)
+
+// nextRuns holds the schedules exported as the next_run_seconds gauge. It is shared
+// between the Recorder and the OTel callback so either side sees registrations.
+type nextRuns struct {
+	mu    sync.RWMutex
+	fns   map[string]NextRunFunc
+	clock clock.Clock
+}
+
+func newNextRuns() *nextRuns {
+	return &nextRuns{fns: make(map[string]NextRunFunc), clock: clock.Real()}
+}
+
+func (n *nextRuns) set(component string, fn NextRunFunc) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if fn == nil {
+		delete(n.fns, component)
+		return
+	}
+	n.fns[component] = fn
+}
+
+// secondsUntil returns the countdown for component, clamped at zero once due.
+func (n *nextRuns) secondsUntil(component string) (float64, bool) {
+	n.mu.RLock()
+	fn := n.fns[component]
+	clk := n.clock
+	n.mu.RUnlock()
+	if fn == nil {
+		return 0, false
+	}
+	at, ok := fn()
+	if !ok {
+		return 0, false
+	}
+	remaining := at.Sub(clk.Now()).Seconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+func (n *nextRuns) setClock(clk clock.Clock) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.clock = clock.OrReal(clk)
+}
+
+func (n *nextRuns) components() []string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	names := make([]string, 0, len(n.fns))
+	for name := range n.fns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ObserveNextRun registers fn as the schedule for component (e.g. "poller"). The
+// next_run_seconds gauge reports the countdown and omits components that are stopped.
+// A nil fn removes the component.
+func (r *Recorder) ObserveNextRun(component string, fn NextRunFunc) {
+	if r == nil {
+		return
+	}
+	r.nextRuns.set(component, fn)
+}
+
+// SecondsUntilNextRun returns the countdown for component; ok is false when it is
+// unknown or stopped.
+func (r *Recorder) SecondsUntilNextRun(component string) (float64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	return r.nextRuns.secondsUntil(component)
+}
@@ -0,0 +1,113 @@
+package poller
+
+import (
+	"log/slog"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// DiffSummary counts what changed between two consecutive successful fetches.
+type DiffSummary struct {
+	Added         int       `json:"added"`
+	StatusChanges int       `json:"statusChanges"`
+	ScoreUpdates  int       `json:"scoreUpdates"`
+	At            time.Time `json:"at"`
+}
+
+// gameChange describes a single difference between the previous and current fetch.
+type gameChange struct {
+	kind string // ChangeAdded, ChangeStatus, or ChangeScore
+	prev domaingames.Game
+	next domaingames.Game
+}
+
+// diffGames compares fetched games against the previous fetch keyed by game ID.
+// Games that disappeared are ignored; the provider only ever returns a day's slate.
+func diffGames(prev map[string]domaingames.Game, next []domaingames.Game) []gameChange {
+	var changes []gameChange
+	for _, g := range next {
+		old, ok := prev[g.ID]
+		if !ok {
+			changes = append(changes, gameChange{kind: ChangeAdded, next: g})
+			continue
+		}
+		if old.StatusKind != g.StatusKind {
+			changes = append(changes, gameChange{kind: ChangeStatus, prev: old, next: g})
+		}
+		if old.Score != g.Score {
+			changes = append(changes, gameChange{kind: ChangeScore, prev: old, next: g})
+		}
+	}
+	return changes
+}
+
+func summarize(changes []gameChange, at time.Time) DiffSummary {
+	summary := DiffSummary{At: at}
+	for _, c := range changes {
+		switch c.kind {
+		case ChangeAdded:
+			summary.Added++
+		case ChangeStatus:
+			summary.StatusChanges++
+		case ChangeScore:
+			summary.ScoreUpdates++
+		}
+	}
+	return summary
+}
+
+func indexGames(games []domaingames.Game) map[string]domaingames.Game {
+	out := make(map[string]domaingames.Game, len(games))
+	for _, g := range games {
+		out[g.ID] = g
+	}
+	return out
+}
+
+// applyDiff logs and records changes since the previous fetch. The first fetch only
+// seeds the baseline so a restart does not report the whole slate as new.
+func (p *Poller) applyDiff(games []domaingames.Game, at time.Time) {
+	prev := p.prev
+	p.prev = indexGames(games)
+	if prev == nil {
+		return
+	}
+
+	changes := diffGames(prev, games)
+	for _, c := range changes {
+		p.logChange(c)
+	}
+	p.emit(changes, at)
+	summary := summarize(changes, at)
+	if p.metrics != nil {
+		p.metrics.RecordGameChanges(summary.Added, summary.StatusChanges, summary.ScoreUpdates)
+	}
+	p.statusMu.Lock()
+	p.status.LastDiff = summary
+	p.statusMu.Unlock()
+}
+
+func (p *Poller) logChange(c gameChange) {
+	switch c.kind {
+	case ChangeAdded:
+		p.logInfo("poller game added",
+			slog.String("game_id", c.next.ID),
+			slog.String("status", string(c.next.StatusKind)),
+		)
+	case ChangeStatus:
+		p.logInfo("poller game status changed",
+			slog.String("game_id", c.next.ID),
+			slog.String("from", string(c.prev.StatusKind)),
+			slog.String("to", string(c.next.StatusKind)),
+		)
+	case ChangeScore:
+		p.logInfo("poller game score updated",
+			slog.String("game_id", c.next.ID),
+			slog.Int("home", c.next.Score.Home),
+			slog.Int("away", c.next.Score.Away),
+			slog.Int("home_delta", c.next.Score.Home-c.prev.Score.Home),
+			slog.Int("away_delta", c.next.Score.Away-c.prev.Score.Away),
+		)
+	}
+}
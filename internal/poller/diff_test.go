@@ -0,0 +1,86 @@
+package poller
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+var testNow = time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+
+func TestDiffGamesDetectsAddsStatusAndScore(t *testing.T) {
+	prev := indexGames([]domaingames.Game{
+		{ID: "a", StatusKind: domaingames.StatusScheduled},
+		{ID: "b", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 50, Away: 48}},
+		{ID: "gone", StatusKind: domaingames.StatusFinal},
+	})
+	next := []domaingames.Game{
+		{ID: "a", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 2}},
+		{ID: "b", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 50, Away: 48}},
+		{ID: "c", StatusKind: domaingames.StatusScheduled},
+	}
+
+	changes := diffGames(prev, next)
+	summary := summarize(changes, testNow)
+	if summary.Added != 1 || summary.StatusChanges != 1 || summary.ScoreUpdates != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if !summary.At.Equal(testNow) {
+		t.Fatalf("expected summary timestamp, got %s", summary.At)
+	}
+}
+
+func TestPollerRecordsDiffBetweenFetches(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	rec := metrics.NewRecorder()
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		{ID: "g1", StatusKind: domaingames.StatusScheduled},
+		{ID: "g2", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 10, Away: 8}},
+	}}
+	p := New(provider, nil, logger, rec, 0, nil)
+	p.clock = teststubs.NewFakeClock(testNow)
+
+	p.fetchOnce(context.Background())
+	if got := p.Status().LastDiff; got != (DiffSummary{}) {
+		t.Fatalf("expected first fetch to only seed baseline, got %+v", got)
+	}
+	if rec.GamesAdded() != 0 {
+		t.Fatalf("expected no games counted as added on first fetch, got %d", rec.GamesAdded())
+	}
+
+	provider.Games = []domaingames.Game{
+		{ID: "g1", StatusKind: domaingames.StatusInProgress},
+		{ID: "g2", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 12, Away: 8}},
+		{ID: "g3", StatusKind: domaingames.StatusScheduled},
+	}
+	p.fetchOnce(context.Background())
+
+	diff := p.Status().LastDiff
+	if diff.Added != 1 || diff.StatusChanges != 2 || diff.ScoreUpdates != 1 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+	if rec.GamesAdded() != 1 || rec.StatusChanges() != 2 || rec.ScoreUpdates() != 1 {
+		t.Fatalf("unexpected recorder counts added=%d status=%d score=%d", rec.GamesAdded(), rec.StatusChanges(), rec.ScoreUpdates())
+	}
+	logs := buf.String()
+	for _, want := range []string{
+		"poller game added",
+		"game_id=g3",
+		"poller game status changed",
+		"from=SCHEDULED to=IN_PROGRESS",
+		"poller game score updated",
+		"home_delta=2",
+	} {
+		if !strings.Contains(logs, want) {
+			t.Fatalf("expected log to contain %q, got %s", want, logs)
+		}
+	}
+}
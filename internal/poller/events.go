@@ -0,0 +1,62 @@
+package poller
+
+import (
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// Change kinds reported in GameChangeEvent.Kind.
+const (
+	ChangeAdded  = "added"
+	ChangeStatus = "status"
+	ChangeScore  = "score"
+)
+
+// GameChangeEvent is delivered to OnChange subscribers for every change
+// detected between consecutive successful fetches.
+type GameChangeEvent struct {
+	Kind      string                     `json:"kind"`
+	GameID    string                     `json:"gameId"`
+	OldStatus domaingames.GameStatusKind `json:"oldStatus,omitempty"`
+	NewStatus domaingames.GameStatusKind `json:"newStatus"`
+	Score     domaingames.Score          `json:"score"`
+	OldScore  domaingames.Score          `json:"oldScore"` // score as of the previous fetch; zero for added games
+	Game      domaingames.Game           `json:"game"`     // the game as of this fetch
+	At        time.Time                  `json:"at"`
+}
+
+// OnChange registers fn to receive change events. Subscribers run synchronously
+// on the poll loop, so they must hand off any slow work (see webhook.Sender).
+func (p *Poller) OnChange(fn func(GameChangeEvent)) {
+	if p == nil || fn == nil {
+		return
+	}
+	p.subsMu.Lock()
+	p.subs = append(p.subs, fn)
+	p.subsMu.Unlock()
+}
+
+func (p *Poller) emit(changes []gameChange, at time.Time) {
+	p.subsMu.RLock()
+	subs := p.subs
+	p.subsMu.RUnlock()
+	if len(subs) == 0 {
+		return
+	}
+	for _, c := range changes {
+		ev := GameChangeEvent{
+			Kind:      c.kind,
+			GameID:    c.next.ID,
+			OldStatus: c.prev.StatusKind,
+			NewStatus: c.next.StatusKind,
+			Score:     c.next.Score,
+			OldScore:  c.prev.Score,
+			Game:      c.next,
+			At:        at,
+		}
+		for _, fn := range subs {
+			fn(ev)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package poller
+
+import (
+	"context"
+	"testing"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func TestOnChangeReceivesEventsAfterBaseline(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		{ID: "g1", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 100, Away: 98}},
+	}}
+	p := New(provider, nil, nil, nil, 0, nil)
+	p.clock = teststubs.NewFakeClock(testNow)
+
+	var events []GameChangeEvent
+	p.OnChange(func(ev GameChangeEvent) { events = append(events, ev) })
+	p.OnChange(nil)
+
+	p.fetchOnce(context.Background())
+	if len(events) != 0 {
+		t.Fatalf("expected no events on baseline fetch, got %+v", events)
+	}
+
+	provider.Games = []domaingames.Game{
+		{ID: "g1", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 104, Away: 98}},
+	}
+	p.fetchOnce(context.Background())
+
+	if len(events) != 2 {
+		t.Fatalf("expected status and score events, got %+v", events)
+	}
+	final := events[0]
+	if final.Kind != ChangeStatus || final.GameID != "g1" ||
+		final.OldStatus != domaingames.StatusInProgress || final.NewStatus != domaingames.StatusFinal ||
+		final.Score.Home != 104 || !final.At.Equal(testNow) {
+		t.Fatalf("unexpected status event %+v", final)
+	}
+	if events[1].Kind != ChangeScore || events[1].OldScore.Home != 100 || events[1].Score.Home != 104 {
+		t.Fatalf("expected score event with old and new score, got %+v", events[1])
+	}
+}
+
+func TestOnChangeNilPollerIsSafe(t *testing.T) {
+	var p *Poller
+	p.OnChange(func(GameChangeEvent) {})
+}
@@ -0,0 +1,141 @@
+package poller
+
+import (
+	"log/slog"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// missedFetchesToDrop is how many consecutive fetches must omit a game on the
+// fetched date before it is dropped; one miss is treated as a partial page.
+const missedFetchesToDrop = 2
+
+// slate is the merged view of one date's games across fetches.
+type slate struct {
+	date   string
+	games  []domaingames.Game
+	missed map[string]int // consecutive fetches that omitted a game, by ID
+}
+
+// Warm seeds today's slate with games from a stored snapshot for date, so
+// the first fetch merges into what was on disk instead of an empty slate and a
+// game it omits survives like any other single miss. It does nothing when
+// fetches replace the slate. Call before Start.
+func (p *Poller) Warm(date string, games []domaingames.Game) {
+	if p.replace || len(games) == 0 {
+		return
+	}
+	p.slate = slate{date: date, games: append([]domaingames.Game(nil), games...), missed: make(map[string]int)}
+}
+
+// mergeGames upserts fetched into today's slate by ID. Known games that start
+// on another date are kept untouched; ones on the fetched date survive a
+// single missing fetch (a truncated page) and are dropped once a second fetch
+// confirms they are gone. The slate resets when the date rolls over.
+func (p *Poller) mergeGames(date string, fetched []domaingames.Game) []domaingames.Game {
+	if p.slate.date != date {
+		p.slate = slate{date: date, missed: make(map[string]int)}
+	}
+	known := make(map[string]domaingames.Game, len(p.slate.games))
+	for _, g := range p.slate.games {
+		known[g.ID] = g
+	}
+	seen := make(map[string]struct{}, len(fetched))
+	merged := make([]domaingames.Game, 0, len(fetched)+len(p.slate.games))
+	now := p.clock.Now()
+	for _, g := range fetched {
+		seen[g.ID] = struct{}{}
+		delete(p.slate.missed, g.ID)
+		old, ok := known[g.ID]
+		if ok {
+			g = p.guardScore(old, g)
+		}
+		merged = append(merged, stampUpdated(old, ok, g, now))
+	}
+
+	kept := 0
+	for _, g := range p.slate.games {
+		if _, ok := seen[g.ID]; ok {
+			continue
+		}
+		if d := p.gameDate(g); d == "" || d == date {
+			p.slate.missed[g.ID]++
+			if p.slate.missed[g.ID] >= missedFetchesToDrop {
+				delete(p.slate.missed, g.ID)
+				p.logInfo("poller dropped game missing from consecutive fetches",
+					slog.String("game_id", g.ID),
+					slog.String("date", date),
+				)
+				continue
+			}
+		}
+		merged = append(merged, g)
+		kept++
+	}
+	if kept > 0 {
+		p.logInfo("poller kept games missing from fetch",
+			slog.Int("kept", kept),
+			slog.Int("fetched", len(fetched)),
+			slog.String("date", date),
+		)
+	}
+	p.slate.games = merged
+	return append([]domaingames.Game(nil), merged...)
+}
+
+// stampUpdated sets next.UpdatedAt to now unless known is set and old, the
+// game's previous state, has the same status and score; an identical re-fetch
+// keeps old's UpdatedAt.
+func stampUpdated(old domaingames.Game, known bool, next domaingames.Game, now time.Time) domaingames.Game {
+	if known && old.StatusKind == next.StatusKind && old.Score == next.Score {
+		next.UpdatedAt = old.UpdatedAt
+		return next
+	}
+	next.UpdatedAt = &now
+	return next
+}
+
+// guardScore checks next, a fetched game, against old, its merged state. The
+// upstream sometimes briefly reports a FINAL game as live again or with lower
+// scores; unless allowFinalRegression is set, old is kept. A live game whose
+// score goes down is a correction and goes through. Both are logged and
+// counted.
+func (p *Poller) guardScore(old, next domaingames.Game) domaingames.Game {
+	lowered := next.Score.Home < old.Score.Home || next.Score.Away < old.Score.Away
+	attrs := []any{
+		slog.String("game_id", next.ID),
+		slog.String("from_status", string(old.StatusKind)),
+		slog.String("to_status", string(next.StatusKind)),
+		slog.Int("from_home", old.Score.Home),
+		slog.Int("from_away", old.Score.Away),
+		slog.Int("to_home", next.Score.Home),
+		slog.Int("to_away", next.Score.Away),
+	}
+	switch {
+	case old.StatusKind == domaingames.StatusFinal &&
+		(next.StatusKind == domaingames.StatusInProgress || next.StatusKind == domaingames.StatusScheduled || lowered):
+		ignored := !p.allowFinalRegression
+		p.metrics.RecordScoreAnomaly(metrics.AnomalyFinalRegression, ignored)
+		p.logWarn("poller saw final game regress", append(attrs, slog.Bool("ignored", ignored))...)
+		if ignored {
+			return old
+		}
+	case old.StatusKind == domaingames.StatusInProgress && lowered:
+		p.metrics.RecordScoreAnomaly(metrics.AnomalyScoreCorrection, false)
+		p.logInfo("poller saw live score correction", attrs...)
+	}
+	return next
+}
+
+// gameDate is the game's local start date, or "" when StartTime is unparsable
+// (such games are treated as belonging to the fetched date).
+func (p *Poller) gameDate(g domaingames.Game) string {
+	start, err := time.Parse(time.RFC3339, g.StartTime)
+	if err != nil {
+		return ""
+	}
+	return timeutil.DateIn(start, p.loc)
+}
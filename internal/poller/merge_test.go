@@ -0,0 +1,273 @@
+package poller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func gameAt(id, start string) domaingames.Game {
+	return domaingames.Game{ID: id, StartTime: start}
+}
+
+func writtenIDs(t *testing.T, w *teststubs.StubSnapshotWriter, date string) []string {
+	t.Helper()
+	snap, ok := w.Written[date]
+	if !ok {
+		t.Fatalf("expected snapshot for %s", date)
+	}
+	ids := make([]string, 0, len(snap.Games))
+	for _, g := range snap.Games {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got ids %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got ids %v, want %v", got, want)
+		}
+	}
+}
+
+func newMergePoller(provider *teststubs.StubProvider, writer *teststubs.StubSnapshotWriter, replace bool) *Poller {
+	p := NewWithConfig(provider, writer, nil, nil, Config{ReplaceGames: replace}, nil)
+	p.clock = teststubs.NewFakeClock(testNow)
+	return p
+}
+
+func TestPollerMergeKeepsGamesMissingFromPartialFetch(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		gameAt("a", "2024-01-15T19:00:00Z"),
+		gameAt("b", "2024-01-15T21:00:00Z"),
+	}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, false)
+	p.fetchOnce(context.Background())
+
+	provider.Games = []domaingames.Game{{ID: "a", StartTime: "2024-01-15T19:00:00Z", StatusKind: domaingames.StatusInProgress}}
+	p.fetchOnce(context.Background())
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a", "b")
+	if got := writer.Written["2024-01-15"].Games[0].StatusKind; got != domaingames.StatusInProgress {
+		t.Fatalf("expected fetched game upserted, got status %s", got)
+	}
+}
+
+func TestPollerMergeDropsGameOnceConfirmedGone(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		gameAt("a", "2024-01-15T19:00:00Z"),
+		gameAt("b", "2024-01-15T21:00:00Z"),
+	}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, false)
+	p.fetchOnce(context.Background())
+
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}
+	p.fetchOnce(context.Background())
+	p.fetchOnce(context.Background())
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a")
+
+	// A game that reappears after one miss starts its count over.
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z"), gameAt("c", "2024-01-15T22:00:00Z")}
+	p.fetchOnce(context.Background())
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}
+	p.fetchOnce(context.Background())
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z"), gameAt("c", "2024-01-15T22:00:00Z")}
+	p.fetchOnce(context.Background())
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}
+	p.fetchOnce(context.Background())
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a", "c")
+}
+
+func TestPollerMergeLeavesOtherDateGamesUntouched(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		gameAt("today", "2024-01-15T19:00:00Z"),
+		gameAt("moved", "2024-01-17T19:00:00Z"), // rescheduled but still on today's slate
+	}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, false)
+	p.fetchOnce(context.Background())
+
+	provider.Games = []domaingames.Game{gameAt("today", "2024-01-15T19:00:00Z")}
+	for i := 0; i < 3; i++ {
+		p.fetchOnce(context.Background())
+	}
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "today", "moved")
+}
+
+func TestPollerMergeResetsOnDateRollover(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, false)
+	p.fetchOnce(context.Background())
+
+	p.clock.(*teststubs.FakeClock).Advance(24 * time.Hour)
+	provider.Games = []domaingames.Game{gameAt("b", "2024-01-16T19:00:00Z")}
+	p.fetchOnce(context.Background())
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-16"), "b")
+}
+
+func TestPollerReplaceGamesWritesFetchAsIs(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		gameAt("a", "2024-01-15T19:00:00Z"),
+		gameAt("b", "2024-01-15T21:00:00Z"),
+	}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, true)
+	p.fetchOnce(context.Background())
+
+	provider.Games = []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}
+	p.fetchOnce(context.Background())
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a")
+}
+
+func TestPollerWarmSeedsSlateFromSnapshot(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, false)
+	p.Warm("2024-01-15", []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z"), gameAt("b", "2024-01-15T21:00:00Z")})
+
+	// The first fetch omits b, which the warm slate keeps as a single miss.
+	p.fetchOnce(context.Background())
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a", "b")
+
+	replacing := newMergePoller(provider, &teststubs.StubSnapshotWriter{}, true)
+	replacing.Warm("2024-01-15", []domaingames.Game{gameAt("b", "2024-01-15T21:00:00Z")})
+	if len(replacing.slate.games) != 0 {
+		t.Fatalf("expected no warm slate when fetches replace it, got %+v", replacing.slate.games)
+	}
+}
+
+func TestPollerMergeGuardsScoreTransitions(t *testing.T) {
+	const (
+		final   = domaingames.StatusFinal
+		live    = domaingames.StatusInProgress
+		pending = domaingames.StatusScheduled
+	)
+	game := func(kind domaingames.GameStatusKind, home, away int) domaingames.Game {
+		return domaingames.Game{ID: "a", StartTime: "2024-01-15T19:00:00Z", StatusKind: kind, Score: domaingames.Score{Home: home, Away: away}}
+	}
+	cases := []struct {
+		name    string
+		old     domaingames.Game
+		next    domaingames.Game
+		allow   bool
+		keepOld bool
+		anomaly string
+	}{
+		{name: "scheduled to live", old: game(pending, 0, 0), next: game(live, 2, 0)},
+		{name: "live score rises", old: game(live, 50, 48), next: game(live, 52, 48)},
+		{name: "live score correction", old: game(live, 50, 48), next: game(live, 48, 48), anomaly: metrics.AnomalyScoreCorrection},
+		{name: "live back to scheduled", old: game(live, 10, 8), next: game(pending, 10, 8)},
+		{name: "live to final", old: game(live, 100, 98), next: game(final, 102, 98)},
+		{name: "final unchanged", old: game(final, 102, 98), next: game(final, 102, 98)},
+		{name: "final score raised", old: game(final, 102, 98), next: game(final, 102, 99)},
+		{name: "final score lowered", old: game(final, 102, 98), next: game(final, 100, 98), keepOld: true, anomaly: metrics.AnomalyFinalRegression},
+		{name: "final back to live", old: game(final, 102, 98), next: game(live, 102, 98), keepOld: true, anomaly: metrics.AnomalyFinalRegression},
+		{name: "final back to scheduled", old: game(final, 102, 98), next: game(pending, 0, 0), keepOld: true, anomaly: metrics.AnomalyFinalRegression},
+		{name: "final to postponed", old: game(final, 102, 98), next: game(domaingames.StatusPostponed, 102, 98)},
+		{name: "final back to live allowed", old: game(final, 102, 98), next: game(live, 102, 98), allow: true, anomaly: metrics.AnomalyFinalRegression},
+		{name: "final score lowered allowed", old: game(final, 102, 98), next: game(final, 100, 98), allow: true, anomaly: metrics.AnomalyFinalRegression},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := metrics.NewRecorder()
+			p := NewWithConfig(nil, nil, nil, rec, Config{AllowFinalRegression: tc.allow}, nil)
+			p.mergeGames("2024-01-15", []domaingames.Game{tc.old})
+
+			got := p.mergeGames("2024-01-15", []domaingames.Game{tc.next})[0]
+			want := tc.next
+			if tc.keepOld {
+				want = tc.old
+			}
+			if got.StatusKind != want.StatusKind || got.Score != want.Score {
+				t.Fatalf("expected %s %+v, got %s %+v", want.StatusKind, want.Score, got.StatusKind, got.Score)
+			}
+			for _, kind := range []string{metrics.AnomalyFinalRegression, metrics.AnomalyScoreCorrection} {
+				wantCount := 0
+				if kind == tc.anomaly {
+					wantCount = 1
+				}
+				if n := rec.ScoreAnomalies(kind); n != wantCount {
+					t.Fatalf("expected %d %s anomalies, got %d", wantCount, kind, n)
+				}
+			}
+		})
+	}
+}
+
+func TestPollerMergeIgnoredRegressionStaysIgnoredOnNextFetch(t *testing.T) {
+	p := NewWithConfig(nil, nil, nil, nil, Config{}, nil)
+	finished := domaingames.Game{ID: "a", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 102, Away: 98}}
+	p.mergeGames("2024-01-15", []domaingames.Game{finished})
+
+	regressed := finished
+	regressed.StatusKind = domaingames.StatusInProgress
+	p.mergeGames("2024-01-15", []domaingames.Game{regressed})
+	got := p.mergeGames("2024-01-15", []domaingames.Game{regressed})[0]
+	if got.StatusKind != domaingames.StatusFinal {
+		t.Fatalf("expected the final game to be kept across fetches, got %s", got.StatusKind)
+	}
+}
+
+func TestPollerMergeStampsUpdatedAtOnChange(t *testing.T) {
+	clk := teststubs.NewFakeClock(testNow)
+	p := NewWithConfig(nil, nil, nil, nil, Config{Clock: clk}, nil)
+	live := domaingames.Game{ID: "a", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 10, Away: 8}}
+
+	first := p.mergeGames("2024-01-15", []domaingames.Game{live})[0]
+	if first.UpdatedAt == nil || !first.UpdatedAt.Equal(testNow) {
+		t.Fatalf("expected a new game stamped at %s, got %v", testNow, first.UpdatedAt)
+	}
+
+	clk.Advance(time.Minute)
+	same := p.mergeGames("2024-01-15", []domaingames.Game{live})[0]
+	if same.UpdatedAt == nil || !same.UpdatedAt.Equal(testNow) {
+		t.Fatalf("expected an identical re-fetch to keep UpdatedAt, got %v", same.UpdatedAt)
+	}
+
+	clk.Advance(time.Minute)
+	scored := live
+	scored.Score.Home = 12
+	bumped := p.mergeGames("2024-01-15", []domaingames.Game{scored})[0]
+	if bumped.UpdatedAt == nil || !bumped.UpdatedAt.Equal(testNow.Add(2*time.Minute)) {
+		t.Fatalf("expected a score change to bump UpdatedAt, got %v", bumped.UpdatedAt)
+	}
+}
+
+func TestPollerMergeKeepsSnapshotWithoutUpdatedAtUnstamped(t *testing.T) {
+	p := newMergePoller(&teststubs.StubProvider{}, nil, false)
+	g := domaingames.Game{ID: "a", StatusKind: domaingames.StatusFinal}
+	p.Warm("2024-01-15", []domaingames.Game{g})
+	if got := p.mergeGames("2024-01-15", []domaingames.Game{g})[0]; got.UpdatedAt != nil {
+		t.Fatalf("expected an unchanged game from an older snapshot to stay unstamped, got %v", got.UpdatedAt)
+	}
+}
+
+func TestPollerReplaceGamesStampsUpdatedAt(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{gameAt("a", "2024-01-15T19:00:00Z")}}
+	writer := &teststubs.StubSnapshotWriter{}
+	p := newMergePoller(provider, writer, true)
+	p.fetchOnce(context.Background())
+	p.clock.(*teststubs.FakeClock).Advance(time.Minute)
+	p.fetchOnce(context.Background())
+
+	got := writer.Written["2024-01-15"].Games[0].UpdatedAt
+	if got == nil || !got.Equal(testNow) {
+		t.Fatalf("expected the unchanged game to keep its first stamp, got %v", got)
+	}
+}
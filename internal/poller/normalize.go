@@ -0,0 +1,31 @@
+package poller
+
+import (
+	"log/slog"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// normalize cleans fetched games before they are merged or written, warning
+// about each rewritten field and counting the outcome.
+func (p *Poller) normalize(date string, fetched []domaingames.Game) []domaingames.Game {
+	games, summary := domaingames.Normalize(fetched)
+	p.metrics.RecordNormalize(summary.Accepted, summary.Dropped, summary.Coerced)
+	for _, c := range summary.Coercions {
+		p.logWarn("poller coerced game field",
+			slog.String("game_id", c.GameID),
+			slog.String("field", c.Field),
+			slog.String("from", c.From),
+		)
+	}
+	if summary.Dropped > 0 || summary.BadStartTimes > 0 {
+		p.logWarn("poller normalized fetched games",
+			slog.String("date", date),
+			slog.Int("accepted", summary.Accepted),
+			slog.Int("dropped", summary.Dropped),
+			slog.Int("coerced", summary.Coerced),
+			slog.Int("bad_start_times", summary.BadStartTimes),
+		)
+	}
+	return games
+}
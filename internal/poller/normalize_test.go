@@ -0,0 +1,32 @@
+package poller
+
+import (
+	"context"
+	"testing"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func TestPollerNormalizesFetchedGamesAndRecordsSummary(t *testing.T) {
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		{ID: "a", StartTime: "2024-01-15T14:00:00-05:00", StatusKind: "HALFTIME", Score: domaingames.Score{Home: -2}},
+		{ID: "", StartTime: "2024-01-15T19:00:00Z"},
+		{ID: "b", StartTime: "2024-01-15T21:00:00Z", StatusKind: domaingames.StatusScheduled},
+	}}
+	writer := &teststubs.StubSnapshotWriter{}
+	rec := metrics.NewRecorder()
+	p := NewWithConfig(provider, writer, nil, rec, Config{}, nil)
+	p.clock = teststubs.NewFakeClock(testNow)
+	p.fetchOnce(context.Background())
+
+	assertIDs(t, writtenIDs(t, writer, "2024-01-15"), "a", "b")
+	got := writer.Written["2024-01-15"].Games[0]
+	if got.StatusKind != domaingames.StatusScheduled || got.Score.Home != 0 || got.StartTime != "2024-01-15T19:00:00Z" {
+		t.Fatalf("expected normalized game, got %+v", got)
+	}
+	if accepted, dropped, coerced := rec.NormalizeCounts(); accepted != 2 || dropped != 1 || coerced != 1 {
+		t.Fatalf("unexpected normalize counts accepted=%d dropped=%d coerced=%d", accepted, dropped, coerced)
+	}
+}
@@ -2,18 +2,25 @@ package poller
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
-const defaultInterval = 30 * time.Second
+const (
+	defaultInterval     = 30 * time.Second
+	defaultFetchTimeout = 20 * time.Second
+)
 
 // SnapshotWriter persists game snapshots to disk.
 type SnapshotWriter interface {
@@ -27,10 +34,20 @@ type Poller struct {
 	logger   *slog.Logger
 	metrics  *metrics.Recorder
 	interval time.Duration
-	now      func() time.Time
+	schedule schedule
+	jitter   time.Duration
+	rng      *rand.Rand
+	timeout  time.Duration
+	clock    clock.Clock
 	loc      *time.Location
+	prev     map[string]domaingames.Game // last fetched games by ID, for diffing
+	replace  bool
+	slate    slate // today's merged games; unused when replace is set
+	// allowFinalRegression lets a FINAL game go back to live or to a lower
+	// score; see guardScore.
+	allowFinalRegression bool
 
-	ticker   *time.Ticker
+	timer    clock.Timer
 	done     chan struct{}
 	stopOnce sync.Once
 	startMu  sync.Mutex
@@ -38,14 +55,28 @@ type Poller struct {
 
 	statusMu sync.RWMutex
 	status   Status
+
+	subsMu sync.RWMutex
+	subs   []func(GameChangeEvent)
+
+	reconfigMu sync.Mutex
+	pending    *Config       // schedule from SetSchedule, applied by the loop
+	reconfig   chan struct{} // signals a pending schedule
 }
 
 // Status describes the recent health of the poller loop.
 type Status struct {
-	ConsecutiveFailures int
-	LastError           string
-	LastAttempt         time.Time
-	LastSuccess         time.Time
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+	LastAttempt         time.Time `json:"lastAttempt"`
+	LastSuccess         time.Time `json:"lastSuccess"`
+	Timeouts            int       `json:"timeouts"`
+	// CurrentInterval is the delay selected for the next fetch.
+	CurrentInterval time.Duration `json:"currentInterval"`
+	// LastDiff summarizes changes seen on the most recent successful fetch.
+	LastDiff DiffSummary `json:"lastDiff"`
+	// NextPollAt is when the next fetch is due; nil while the poller is not running.
+	NextPollAt *time.Time `json:"nextPollAt,omitempty"`
 }
 
 // IsReady reports whether the poller has had a recent success and is not failing repeatedly.
@@ -56,23 +87,58 @@ func (s Status) IsReady() bool {
 	return s.ConsecutiveFailures < 3
 }
 
+// Config controls poller scheduling and per-cycle limits.
+// LiveInterval, PreGameInterval, and IdleInterval enable adaptive scheduling;
+// any left at zero fall back to Interval.
+type Config struct {
+	Interval        time.Duration
+	LiveInterval    time.Duration // any game IN_PROGRESS
+	PreGameInterval time.Duration // next tip-off within the hour
+	IdleInterval    time.Duration // nothing live or imminent
+	StartJitter     time.Duration // random delay in [0, StartJitter) before the first fetch
+	Clock           clock.Clock   // defaults to the real clock
+	FetchTimeout    time.Duration // upper bound for a single provider fetch
+	ReplaceGames    bool          // write each fetch as-is instead of merging; see mergeGames
+	// AllowFinalRegression accepts upstream updates that move a FINAL game
+	// back to live or lower its score; they are ignored by default.
+	AllowFinalRegression bool
+}
+
 // New constructs a Poller with sane defaults.
 func New(provider providers.GameProvider, writer SnapshotWriter, logger *slog.Logger, recorder *metrics.Recorder, interval time.Duration, loc *time.Location) *Poller {
-	if interval <= 0 {
-		interval = defaultInterval
+	return NewWithConfig(provider, writer, logger, recorder, Config{Interval: interval}, loc)
+}
+
+// NewWithConfig is identical to New but accepts the full poller Config.
+func NewWithConfig(provider providers.GameProvider, writer SnapshotWriter, logger *slog.Logger, recorder *metrics.Recorder, cfg Config, loc *time.Location) *Poller {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = defaultFetchTimeout
 	}
 	if loc == nil {
 		loc = time.UTC
 	}
+	clk := clock.OrReal(cfg.Clock)
 	return &Poller{
 		provider: provider,
 		writer:   writer,
 		logger:   logger,
 		metrics:  recorder,
-		interval: interval,
-		now:      time.Now,
+		interval: cfg.Interval,
+		schedule: newSchedule(cfg),
+		jitter:   cfg.StartJitter,
+		rng:      rand.New(rand.NewSource(clk.Now().UnixNano())),
+		timeout:  cfg.FetchTimeout,
+		replace:  cfg.ReplaceGames,
+		clock:    clk,
 		loc:      loc,
 		done:     make(chan struct{}),
+		reconfig: make(chan struct{}, 1),
+		status:   Status{CurrentInterval: cfg.Interval},
+
+		allowFinalRegression: cfg.AllowFinalRegression,
 	}
 }
 
@@ -86,25 +152,35 @@ func (p *Poller) Start(ctx context.Context) {
 	p.started = true
 	p.startMu.Unlock()
 
-	p.ticker = time.NewTicker(p.interval)
+	p.timer = p.clock.NewTimer(p.interval)
+	p.timer.Stop()
 
 	go func() {
 		p.logInfo("poller started", slog.Int64(logging.FieldDurationMS, p.interval.Milliseconds()))
-		// Initial fetch to warm data on boot.
-		p.fetchOnce(ctx)
+		defer p.setNextPoll(time.Time{})
+		// Spread replicas out before the initial warm-up fetch.
+		delay := p.startDelay()
+		p.setNextPoll(p.clock.Now().Add(delay))
+		if !p.wait(ctx, delay) {
+			p.logInfo("poller stopped")
+			return
+		}
+		p.scheduleNext(p.fetchOnce(ctx))
 
 		for {
 			select {
 			case <-ctx.Done():
-				p.stopTicker()
+				p.stopTimer()
 				p.logInfo("poller stopped")
 				return
 			case <-p.done:
-				p.stopTicker()
+				p.stopTimer()
 				p.logInfo("poller stopped")
 				return
-			case <-p.ticker.C:
-				p.fetchOnce(ctx)
+			case <-p.timer.C():
+				p.scheduleNext(p.fetchOnce(ctx))
+			case <-p.reconfig:
+				p.applySchedule()
 			}
 		}
 	}()
@@ -115,41 +191,174 @@ func (p *Poller) Stop(ctx context.Context) error {
 	_ = ctx
 	p.stopOnce.Do(func() {
 		close(p.done)
-		p.stopTicker()
+		p.stopTimer()
+		p.setNextPoll(time.Time{})
 	})
 	return nil
 }
 
-func (p *Poller) fetchOnce(ctx context.Context) {
-	start := time.Now()
+// SetSchedule swaps in cfg's Interval, LiveInterval, PreGameInterval and
+// IdleInterval; its other fields are ignored. A running poller re-arms its
+// timer from the new schedule and the games it already has, without fetching.
+func (p *Poller) SetSchedule(cfg Config) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	p.reconfigMu.Lock()
+	p.pending = &cfg
+	p.reconfigMu.Unlock()
+	select {
+	case p.reconfig <- struct{}{}:
+	default:
+	}
+}
+
+// applySchedule runs on the polling goroutine, which owns schedule and timer.
+func (p *Poller) applySchedule() {
+	p.reconfigMu.Lock()
+	cfg := p.pending
+	p.pending = nil
+	p.reconfigMu.Unlock()
+	if cfg == nil {
+		return
+	}
+	p.interval = cfg.Interval
+	p.schedule = newSchedule(*cfg)
+	known := make([]domaingames.Game, 0, len(p.prev))
+	for _, g := range p.prev {
+		known = append(known, g)
+	}
+	next := p.schedule.next(known, p.clock.Now())
+	p.statusMu.Lock()
+	p.status.CurrentInterval = next
+	p.statusMu.Unlock()
+	p.timer.Stop()
+	p.scheduleNext(next)
+	p.logInfo("poller schedule updated", "next_interval_ms", next.Milliseconds())
+}
+
+// fetchOnce runs a single poll cycle and returns the delay before the next one.
+func (p *Poller) fetchOnce(ctx context.Context) time.Duration {
+	start := p.clock.Now()
 	p.recordAttempt(start)
-	today := timeutil.FormatDate(p.now().In(p.loc))
-	games, err := p.provider.FetchGames(ctx, today, "")
+	today := timeutil.DateIn(start, p.loc)
+	fetchCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	games, err := p.provider.FetchGames(fetchCtx, today, "")
+	timedOut := err != nil && ctx.Err() == nil && errors.Is(fetchCtx.Err(), context.DeadlineExceeded)
+	cancel()
 	if p.metrics != nil {
-		p.metrics.RecordPollerCycle(time.Since(start), err)
+		p.metrics.RecordPollerCycle(p.since(start), err)
+		if timedOut {
+			p.metrics.RecordPollerTimeout()
+		}
 	}
 	if err != nil {
-		p.logError("poller fetch failed", err, slog.Int64(logging.FieldDurationMS, time.Since(start).Milliseconds()))
-		p.recordFailure(err, start)
-		return
+		p.logError("poller fetch failed", err,
+			slog.Int64(logging.FieldDurationMS, p.since(start).Milliseconds()),
+			slog.Bool("timed_out", timedOut),
+		)
+		p.recordFailure(err, start, timedOut)
+		return p.Status().CurrentInterval
 	}
 
+	games = p.normalize(today, games)
+	if p.replace {
+		for i, g := range games {
+			old, ok := p.prev[g.ID]
+			games[i] = stampUpdated(old, ok, g, start)
+		}
+	} else {
+		games = p.mergeGames(today, games)
+	}
+	domaingames.AssignCanonicalIDs(today, games)
+	// Provider order can change between polls; keep the slate's stable.
+	domaingames.SortGames(games, domaingames.SortStartTime)
 	if p.writer != nil {
 		snap := domaingames.NewTodayResponse(today, games)
-		if writeErr := p.writer.WriteGamesSnapshot(today, snap); writeErr != nil {
+		// Frozen dates are settled; a late poll must not overwrite them.
+		if writeErr := p.writer.WriteGamesSnapshot(today, snap); writeErr != nil && !errors.Is(writeErr, snapshots.ErrSnapshotFrozen) {
 			p.logError("poller snapshot write failed", writeErr)
 		}
 	}
-	p.recordSuccess(start)
+	p.applyDiff(games, start)
+	next := p.schedule.next(games, p.clock.Now())
+	p.recordSuccess(start, next)
 	p.logInfo("poller refreshed games",
 		logging.FieldCount, len(games),
-		logging.FieldDurationMS, time.Since(start).Milliseconds(),
+		logging.FieldDurationMS, p.since(start).Milliseconds(),
+		"next_interval_ms", next.Milliseconds(),
 	)
+	return next
+}
+
+// scheduleNext arms the timer for the next fetch, jittered, and records when it is due.
+func (p *Poller) scheduleNext(interval time.Duration) {
+	d := p.jitterInterval(interval)
+	p.setNextPoll(p.clock.Now().Add(d))
+	p.timer.Reset(d)
+}
+
+// setNextPoll records the next fetch time; the zero time clears it.
+func (p *Poller) setNextPoll(at time.Time) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	if at.IsZero() {
+		p.status.NextPollAt = nil
+		return
+	}
+	p.status.NextPollAt = &at
+}
+
+// NextRun reports when the next fetch is due; ok is false while the poller is not running.
+func (p *Poller) NextRun() (at time.Time, ok bool) {
+	status := p.Status()
+	if status.NextPollAt == nil {
+		return time.Time{}, false
+	}
+	return *status.NextPollAt, true
+}
+
+// startDelay returns a random delay in [0, jitter), or zero when jitter is disabled.
+func (p *Poller) startDelay() time.Duration {
+	if p.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(p.rng.Int63n(int64(p.jitter)))
 }
 
-func (p *Poller) stopTicker() {
-	if p.ticker != nil {
-		p.ticker.Stop()
+// jitterInterval spreads d by ±10% so replicas drift apart between cycles.
+func (p *Poller) jitterInterval(d time.Duration) time.Duration {
+	spread := int64(d) / 10
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(p.rng.Int63n(2*spread+1))
+}
+
+// wait blocks for d, returning false if the poller is stopped first.
+func (p *Poller) wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := p.clock.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-p.done:
+		return false
+	case <-t.C():
+		return true
+	}
+}
+
+func (p *Poller) since(start time.Time) time.Duration {
+	return p.clock.Now().Sub(start)
+}
+
+func (p *Poller) stopTimer() {
+	if p.timer != nil {
+		p.timer.Stop()
 	}
 }
 
@@ -159,6 +368,12 @@ func (p *Poller) logInfo(msg string, args ...any) {
 	}
 }
 
+func (p *Poller) logWarn(msg string, args ...any) {
+	if p.logger != nil {
+		p.logger.Warn(msg, args...)
+	}
+}
+
 func (p *Poller) logError(msg string, err error, attrs ...any) {
 	if p.logger != nil {
 		p.logger.Error(msg, append(attrs, "error", err)...)
@@ -171,18 +386,22 @@ func (p *Poller) recordAttempt(at time.Time) {
 	p.status.LastAttempt = at
 }
 
-func (p *Poller) recordSuccess(at time.Time) {
+func (p *Poller) recordSuccess(at time.Time, next time.Duration) {
 	p.statusMu.Lock()
 	defer p.statusMu.Unlock()
 	p.status.ConsecutiveFailures = 0
 	p.status.LastError = ""
 	p.status.LastSuccess = at
+	p.status.CurrentInterval = next
 }
 
-func (p *Poller) recordFailure(err error, at time.Time) {
+func (p *Poller) recordFailure(err error, at time.Time, timedOut bool) {
 	p.statusMu.Lock()
 	defer p.statusMu.Unlock()
 	p.status.ConsecutiveFailures++
+	if timedOut {
+		p.status.Timeouts++
+	}
 	if err != nil {
 		p.status.LastError = err.Error()
 	}
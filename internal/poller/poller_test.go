@@ -5,11 +5,13 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"math/rand"
 	"testing"
 	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
@@ -33,9 +35,9 @@ func TestPollerFetchesAndWritesSnapshot(t *testing.T) {
 
 	writer := &teststubs.StubSnapshotWriter{}
 
-	p := New(provider, writer, nil, nil, 10*time.Millisecond, nil)
-	// Fix the time for deterministic date.
-	p.now = func() time.Time { return time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC) }
+	// Virtual clock fixes the date and drives the interval timer.
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, writer, nil, nil, Config{Interval: 30 * time.Second, Clock: clk}, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -48,7 +50,17 @@ func TestPollerFetchesAndWritesSnapshot(t *testing.T) {
 		t.Fatal("timed out waiting for initial fetch")
 	}
 
-	time.Sleep(30 * time.Millisecond) // allow at least one ticker fire
+	// Advance past the (jittered) interval once the loop has armed its timer.
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to arm its interval timer")
+	}
+	clk.Advance(time.Minute)
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to re-arm after the second fetch")
+	}
+	if got := provider.Calls.Load(); got != 2 {
+		t.Fatalf("expected exactly 2 fetches after one interval, got %d", got)
+	}
 
 	cancel()
 	_ = p.Stop(context.Background())
@@ -75,7 +87,8 @@ func TestPollerStopsOnContextCancel(t *testing.T) {
 
 	writer := &teststubs.StubSnapshotWriter{}
 
-	p := New(provider, writer, nil, nil, 5*time.Millisecond, nil)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, writer, nil, nil, Config{Interval: 5 * time.Millisecond, Clock: clk}, nil)
 	ctx, cancel := context.WithCancel(context.Background())
 
 	p.Start(ctx)
@@ -86,12 +99,16 @@ func TestPollerStopsOnContextCancel(t *testing.T) {
 	case <-time.After(500 * time.Millisecond):
 		t.Fatal("timed out waiting for initial fetch")
 	}
+	// Stop only once the next cycle is armed, so Stop disarms it.
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to schedule its next cycle")
+	}
 
 	cancel()
 	_ = p.Stop(context.Background())
 
 	callsAfterStop := provider.Calls.Load()
-	time.Sleep(20 * time.Millisecond)
+	clk.Advance(10 * p.interval)
 	if provider.Calls.Load() != callsAfterStop {
 		t.Fatalf("expected no additional fetches after stop; before=%d after=%d", callsAfterStop, provider.Calls.Load())
 	}
@@ -146,24 +163,34 @@ func TestPollerStartReturnsWhenAlreadyStarted(t *testing.T) {
 	p := New(provider, &teststubs.StubSnapshotWriter{}, nil, nil, time.Hour, nil)
 	p.started = true
 	p.Start(context.Background())
-	if p.ticker != nil {
-		t.Fatalf("expected ticker not to be created when already started")
+	if p.timer != nil {
+		t.Fatalf("expected timer not to be created when already started")
 	}
 }
 
 func TestPollerStopTriggersDoneChannel(t *testing.T) {
 	provider := &teststubs.StubProvider{}
-	p := New(provider, &teststubs.StubSnapshotWriter{}, nil, nil, 10*time.Millisecond, nil)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, &teststubs.StubSnapshotWriter{}, nil, nil, Config{Interval: 10 * time.Millisecond, Clock: clk}, nil)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	p.Start(ctx)
-	time.Sleep(15 * time.Millisecond) // allow startup
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to schedule its next cycle")
+	}
 
 	if err := p.Stop(context.Background()); err != nil {
 		t.Fatalf("expected stop without error, got %v", err)
 	}
-	time.Sleep(10 * time.Millisecond) // allow goroutine to exit via done channel
+	select {
+	case <-p.done:
+	default:
+		t.Fatal("expected done channel closed by Stop")
+	}
+	if _, ok := p.NextRun(); ok {
+		t.Fatal("expected no next poll after stop")
+	}
 }
 
 func TestPollerStatusTracksFailuresAndSuccess(t *testing.T) {
@@ -252,6 +279,98 @@ func TestPollerWriteErrorLogsButContinues(t *testing.T) {
 	}
 }
 
+func TestPollerWritesGamesInStartTimeOrder(t *testing.T) {
+	game := func(id string, hour int) domaingames.Game {
+		return domaingames.Game{
+			ID:         id,
+			Provider:   "stub",
+			HomeTeam:   teams.Team{ID: "home-" + id, Name: "Home"},
+			AwayTeam:   teams.Team{ID: "away-" + id, Name: "Away"},
+			StartTime:  time.Date(2024, 1, 15, hour, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			Status:     "Scheduled",
+			StatusKind: domaingames.StatusScheduled,
+		}
+	}
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{game("late", 23), game("b-early", 18), game("a-early", 18)}}
+	writer := &teststubs.StubSnapshotWriter{}
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, writer, nil, nil, Config{Interval: time.Minute, Clock: clk}, nil)
+
+	want := []string{"a-early", "b-early", "late"}
+	for i := 0; i < 2; i++ {
+		p.fetchOnce(context.Background())
+		snap := writer.Written["2024-01-15"]
+		if len(snap.Games) != len(want) {
+			t.Fatalf("poll %d: expected %d games, got %+v", i, len(want), snap.Games)
+		}
+		for j, id := range want {
+			if snap.Games[j].ID != id {
+				t.Fatalf("poll %d: expected order %v, got %+v", i, want, snap.Games)
+			}
+		}
+		// The provider's order changing must not reorder the snapshot.
+		provider.Games = []domaingames.Game{game("a-early", 18), game("late", 23), game("b-early", 18)}
+	}
+}
+
+func TestPollerDefaultsFetchTimeout(t *testing.T) {
+	p := New(&teststubs.StubProvider{}, &teststubs.StubSnapshotWriter{}, nil, nil, time.Minute, nil)
+	if p.timeout != defaultFetchTimeout {
+		t.Fatalf("expected default fetch timeout %s, got %s", defaultFetchTimeout, p.timeout)
+	}
+}
+
+func TestPollerFetchTimeoutBoundsCycle(t *testing.T) {
+	rec := metrics.NewRecorder()
+	writer := &teststubs.StubSnapshotWriter{}
+	p := NewWithConfig(teststubs.BlockingProvider{}, writer, nil, rec, Config{
+		Interval:     time.Minute,
+		FetchTimeout: 20 * time.Millisecond,
+	}, nil)
+
+	done := make(chan struct{})
+	go func() {
+		p.fetchOnce(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected fetch cycle to finish once the timeout elapsed")
+	}
+
+	status := p.Status()
+	if status.ConsecutiveFailures != 1 {
+		t.Fatalf("expected 1 failure, got %d", status.ConsecutiveFailures)
+	}
+	if status.Timeouts != 1 {
+		t.Fatalf("expected 1 timeout, got %d", status.Timeouts)
+	}
+	if got := rec.PollerTimeouts(); got != 1 {
+		t.Fatalf("expected recorder to count 1 timeout, got %d", got)
+	}
+	if len(writer.Written) != 0 {
+		t.Fatalf("expected no snapshot written after timeout")
+	}
+}
+
+func TestPollerParentCancelIsNotCountedAsTimeout(t *testing.T) {
+	rec := metrics.NewRecorder()
+	p := NewWithConfig(teststubs.BlockingProvider{}, nil, nil, rec, Config{FetchTimeout: time.Hour}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.fetchOnce(ctx)
+
+	if status := p.Status(); status.ConsecutiveFailures != 1 || status.Timeouts != 0 {
+		t.Fatalf("expected failure without timeout, got %+v", status)
+	}
+	if got := rec.PollerTimeouts(); got != 0 {
+		t.Fatalf("expected no recorded timeouts, got %d", got)
+	}
+}
+
 func BenchmarkPollerFetchOnce(b *testing.B) {
 	provider := &teststubs.StubProvider{
 		Games: []domaingames.Game{
@@ -276,3 +395,174 @@ func BenchmarkPollerFetchOnce(b *testing.B) {
 		p.fetchOnce(ctx)
 	}
 }
+
+func TestPollerStatusReportsSelectedInterval(t *testing.T) {
+	now := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{
+		{ID: "live", StatusKind: domaingames.StatusInProgress, StartTime: now.Format(time.RFC3339)},
+	}}
+	p := NewWithConfig(provider, nil, nil, nil, Config{
+		Interval:     30 * time.Second,
+		LiveInterval: 10 * time.Second,
+		IdleInterval: 10 * time.Minute,
+	}, nil)
+	p.clock = teststubs.NewFakeClock(now)
+
+	if got := p.Status().CurrentInterval; got != 30*time.Second {
+		t.Fatalf("expected base interval before first fetch, got %s", got)
+	}
+	if next := p.fetchOnce(context.Background()); next != 10*time.Second {
+		t.Fatalf("expected live interval, got %s", next)
+	}
+	if got := p.Status().CurrentInterval; got != 10*time.Second {
+		t.Fatalf("expected status to report live interval, got %s", got)
+	}
+
+	// Failures keep the previously selected interval.
+	provider.Err = errors.New("boom")
+	if next := p.fetchOnce(context.Background()); next != 10*time.Second {
+		t.Fatalf("expected interval unchanged on failure, got %s", next)
+	}
+
+	// The merged slate keeps a game through one fetch that omits it.
+	provider.Err = nil
+	provider.Games = nil
+	if next := p.fetchOnce(context.Background()); next != 10*time.Second {
+		t.Fatalf("expected live interval while the game is retained, got %s", next)
+	}
+	if next := p.fetchOnce(context.Background()); next != 10*time.Minute {
+		t.Fatalf("expected idle interval, got %s", next)
+	}
+}
+
+func TestPollerStartJitterDelaysInitialFetch(t *testing.T) {
+	provider := &teststubs.StubProvider{Notify: make(chan struct{})}
+	jitter := time.Minute
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, nil, nil, nil, Config{Interval: time.Hour, StartJitter: jitter, Clock: clk}, nil)
+	p.rng = rand.New(rand.NewSource(1))
+	want := time.Duration(rand.New(rand.NewSource(1)).Int63n(int64(jitter)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p.Start(ctx)
+
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to wait on start jitter")
+	}
+	clk.Advance(want - time.Nanosecond)
+	if provider.Calls.Load() != 0 {
+		t.Fatalf("expected no fetch before the jitter delay elapsed")
+	}
+	clk.Advance(time.Nanosecond)
+	select {
+	case <-provider.Notify:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for jittered initial fetch")
+	}
+	_ = p.Stop(context.Background())
+}
+
+func TestPollerStopDuringStartJitterSkipsFetch(t *testing.T) {
+	provider := &teststubs.StubProvider{}
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	p := NewWithConfig(provider, nil, nil, nil, Config{Interval: time.Hour, StartJitter: time.Hour, Clock: clk}, nil)
+	p.rng = rand.New(rand.NewSource(1))
+	p.Start(context.Background())
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to wait on start jitter")
+	}
+	_ = p.Stop(context.Background())
+	// The jitter wait stops its timer when it returns on done.
+	teststubs.WaitFor(t, time.Second, func() bool { return clk.ActiveTimers() == 0 }, "expected start jitter wait to return after stop")
+	clk.Advance(time.Hour)
+	if provider.Calls.Load() != 0 {
+		t.Fatalf("expected no fetch when stopped during start jitter, got %d", provider.Calls.Load())
+	}
+}
+
+func TestPollerJitterIntervalStaysInRange(t *testing.T) {
+	p := New(&teststubs.StubProvider{}, nil, nil, nil, time.Minute, nil)
+	p.rng = rand.New(rand.NewSource(42))
+	base := 30 * time.Second
+	lo, hi := base-3*time.Second, base+3*time.Second
+	for i := 0; i < 1000; i++ {
+		if got := p.jitterInterval(base); got < lo || got > hi {
+			t.Fatalf("jittered interval %s outside [%s, %s]", got, lo, hi)
+		}
+	}
+	if got := p.jitterInterval(5 * time.Nanosecond); got != 5*time.Nanosecond {
+		t.Fatalf("expected tiny intervals unchanged, got %s", got)
+	}
+	if got := p.startDelay(); got != 0 {
+		t.Fatalf("expected no start delay without jitter, got %s", got)
+	}
+}
+
+func TestPollerNextPollAtTracksScheduleAndClearsOnStop(t *testing.T) {
+	start := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	clk := teststubs.NewFakeClock(start)
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{{ID: "live", StatusKind: domaingames.StatusInProgress}}}
+	p := NewWithConfig(provider, nil, nil, nil, Config{Interval: time.Hour, LiveInterval: 10 * time.Second, Clock: clk}, nil)
+	if _, ok := p.NextRun(); ok {
+		t.Fatalf("expected no next poll before start")
+	}
+
+	p.Start(context.Background())
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to arm its interval timer")
+	}
+	assertNextIn := func(want time.Duration) {
+		t.Helper()
+		at, ok := p.NextRun()
+		if !ok {
+			t.Fatalf("expected next poll while running")
+		}
+		if got := at.Sub(clk.Now()); got < want*9/10 || got > want*11/10 {
+			t.Fatalf("expected next poll in ~%s, got %s", want, got)
+		}
+	}
+	assertNextIn(10 * time.Second)
+
+	// The slate goes idle; the next poll moves out to the base interval.
+	provider.Games = []domaingames.Game{{ID: "live", StatusKind: domaingames.StatusFinal}}
+	clk.Advance(11 * time.Second)
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to re-arm")
+	}
+	assertNextIn(time.Hour)
+
+	_ = p.Stop(context.Background())
+	if _, ok := p.NextRun(); ok || p.Status().NextPollAt != nil {
+		t.Fatalf("expected next poll cleared after stop")
+	}
+}
+
+func TestPollerSetScheduleRearmsWithoutFetching(t *testing.T) {
+	start := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	clk := teststubs.NewFakeClock(start)
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{{ID: "live", StatusKind: domaingames.StatusInProgress}}}
+	p := NewWithConfig(provider, nil, nil, nil, Config{Interval: time.Hour, Clock: clk}, nil)
+
+	p.Start(context.Background())
+	defer func() { _ = p.Stop(context.Background()) }()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to arm its interval timer")
+	}
+
+	// The known slate is live, so the new live interval applies straight away.
+	p.SetSchedule(Config{Interval: 5 * time.Minute, LiveInterval: 10 * time.Second})
+	teststubs.WaitFor(t, time.Second, func() bool {
+		return p.Status().CurrentInterval == 10*time.Second
+	}, "expected schedule to update")
+	at, ok := p.NextRun()
+	if got := at.Sub(clk.Now()); !ok || got < 9*time.Second || got > 11*time.Second {
+		t.Fatalf("expected next poll re-armed to ~10s, got %s", got)
+	}
+	if calls := provider.Calls.Load(); calls != 1 {
+		t.Fatalf("expected no extra fetch on reschedule, got %d calls", calls)
+	}
+
+	clk.Advance(11 * time.Second)
+	teststubs.WaitFor(t, time.Second, func() bool { return provider.Calls.Load() == 2 }, "expected fetch on the new interval")
+}
@@ -0,0 +1,62 @@
+package poller
+
+import (
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// preGameWindow is how far ahead of a tip-off the poller switches to PreGameInterval.
+const preGameWindow = time.Hour
+
+// schedule picks the next poll delay from the games returned by the last fetch.
+type schedule struct {
+	live    time.Duration
+	preGame time.Duration
+	idle    time.Duration
+}
+
+func newSchedule(cfg Config) schedule {
+	orBase := func(d time.Duration) time.Duration {
+		if d <= 0 {
+			return cfg.Interval
+		}
+		return d
+	}
+	return schedule{
+		live:    orBase(cfg.LiveInterval),
+		preGame: orBase(cfg.PreGameInterval),
+		idle:    orBase(cfg.IdleInterval),
+	}
+}
+
+// next returns LiveInterval when any game is in progress, PreGameInterval when a
+// scheduled tip-off is within the hour, and IdleInterval otherwise.
+func (s schedule) next(games []domaingames.Game, now time.Time) time.Duration {
+	preGame := false
+	for _, g := range games {
+		switch g.StatusKind {
+		case domaingames.StatusInProgress:
+			return s.live
+		case domaingames.StatusScheduled:
+			if tipOffSoon(g.StartTime, now) {
+				preGame = true
+			}
+		}
+	}
+	if preGame {
+		return s.preGame
+	}
+	return s.idle
+}
+
+// tipOffSoon reports whether start is within preGameWindow of now. Tip-offs that
+// passed less than a window ago still count, since status updates can lag.
+func tipOffSoon(start string, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, start)
+	if err != nil {
+		return false
+	}
+	until := t.Sub(now)
+	return until <= preGameWindow && until >= -preGameWindow
+}
@@ -0,0 +1,78 @@
+package poller
+
+import (
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+func TestScheduleNextPicksIntervalFromGames(t *testing.T) {
+	now := time.Date(2024, 1, 15, 18, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	s := newSchedule(Config{
+		Interval:        30 * time.Second,
+		LiveInterval:    10 * time.Second,
+		PreGameInterval: time.Minute,
+		IdleInterval:    10 * time.Minute,
+	})
+
+	cases := []struct {
+		name  string
+		games []domaingames.Game
+		want  time.Duration
+	}{
+		{name: "no games", want: 10 * time.Minute},
+		{
+			name: "live game wins",
+			games: []domaingames.Game{
+				{StatusKind: domaingames.StatusScheduled, StartTime: at(30 * time.Minute)},
+				{StatusKind: domaingames.StatusInProgress, StartTime: at(-time.Hour)},
+			},
+			want: 10 * time.Second,
+		},
+		{
+			name:  "tip-off within the hour",
+			games: []domaingames.Game{{StatusKind: domaingames.StatusScheduled, StartTime: at(45 * time.Minute)}},
+			want:  time.Minute,
+		},
+		{
+			name:  "tip-off just passed but not yet live",
+			games: []domaingames.Game{{StatusKind: domaingames.StatusScheduled, StartTime: at(-5 * time.Minute)}},
+			want:  time.Minute,
+		},
+		{
+			name:  "tip-off later today",
+			games: []domaingames.Game{{StatusKind: domaingames.StatusScheduled, StartTime: at(3 * time.Hour)}},
+			want:  10 * time.Minute,
+		},
+		{
+			name:  "finals only",
+			games: []domaingames.Game{{StatusKind: domaingames.StatusFinal, StartTime: at(-3 * time.Hour)}},
+			want:  10 * time.Minute,
+		},
+		{
+			name:  "unparseable start time",
+			games: []domaingames.Game{{StatusKind: domaingames.StatusScheduled, StartTime: "soon"}},
+			want:  10 * time.Minute,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := s.next(tc.games, now); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestScheduleFallsBackToBaseInterval(t *testing.T) {
+	s := newSchedule(Config{Interval: 30 * time.Second})
+	live := []domaingames.Game{{StatusKind: domaingames.StatusInProgress}}
+	if got := s.next(live, time.Now()); got != 30*time.Second {
+		t.Fatalf("expected base interval for live games, got %s", got)
+	}
+	if got := s.next(nil, time.Now()); got != 30*time.Second {
+		t.Fatalf("expected base interval when idle, got %s", got)
+	}
+}
@@ -0,0 +1,146 @@
+// Package preflight runs the deploy-time checks behind `server --check`:
+// the config validates, the provider answers, and the snapshot directory
+// takes writes. Nothing here binds a port or starts a background loop.
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// DefaultProviderTimeout bounds the provider check's fetch.
+const DefaultProviderTimeout = 10 * time.Second
+
+// Status is the outcome of one check or of the whole report.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+)
+
+// Result is one check's outcome. Detail says what passed, Error why it failed.
+type Result struct {
+	Name       string `json:"name"`
+	Status     Status `json:"status"`
+	Detail     string `json:"detail,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report lists every check in the order it ran; Status fails if any did.
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// OK reports whether every check passed.
+func (r Report) OK() bool {
+	return r.Status == StatusPass
+}
+
+// Options configures Run.
+type Options struct {
+	Config config.Config
+	// NewProvider builds the configured provider; the server passes
+	// server.NewProvider. Nil fails the provider check.
+	NewProvider     func(config.Config) providers.GameProvider
+	ProviderTimeout time.Duration // defaults to DefaultProviderTimeout
+	Clock           clock.Clock   // defaults to the real clock
+}
+
+// Run performs every check, even after one fails, so a single run reports
+// all the problems a deploy would hit.
+func Run(ctx context.Context, opts Options) Report {
+	clk := clock.OrReal(opts.Clock)
+	timeout := opts.ProviderTimeout
+	if timeout <= 0 {
+		timeout = DefaultProviderTimeout
+	}
+
+	report := Report{Status: StatusPass}
+	run := func(name string, check func() (string, error)) {
+		start := clk.Now()
+		detail, err := check()
+		result := Result{Name: name, Status: StatusPass, Detail: detail, DurationMs: clk.Now().Sub(start).Milliseconds()}
+		if err != nil {
+			result.Status, result.Error = StatusFail, err.Error()
+			report.Status = StatusFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	run("config", func() (string, error) { return checkConfig(opts.Config) })
+	run("provider", func() (string, error) {
+		return checkProvider(ctx, opts.Config, opts.NewProvider, clk, timeout)
+	})
+	run("snapshotDir", func() (string, error) { return checkSnapshotDir(opts.Config.Snapshots.SnapshotFolder) })
+	return report
+}
+
+// checkConfig runs Validate. Values Load replaced with defaults only fail it
+// under VALIDATE_STRICT, as at startup, but are listed in the detail.
+func checkConfig(cfg config.Config) (string, error) {
+	if err := cfg.Validate(); err != nil {
+		return "", err
+	}
+	if fallbacks := cfg.Fallbacks(); len(fallbacks) > 0 {
+		return "ignored: " + strings.Join(fallbacks, "; "), nil
+	}
+	return "", nil
+}
+
+// checkProvider fetches today's games once, in the service timezone.
+func checkProvider(ctx context.Context, cfg config.Config, newProvider func(config.Config) providers.GameProvider, clk clock.Clock, timeout time.Duration) (string, error) {
+	if newProvider == nil {
+		return "", errors.New("no provider constructor")
+	}
+	provider := newProvider(cfg)
+	if provider == nil {
+		return "", fmt.Errorf("provider %q could not be built", cfg.Provider)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	today := timeutil.DateIn(clk.Now(), cfg.Location())
+	games, err := provider.FetchGames(ctx, today, "")
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", today, err)
+	}
+	return fmt.Sprintf("%d games for %s from %s", len(games), today, providerName(cfg)), nil
+}
+
+// checkSnapshotDir creates and removes a temp file in dir, creating dir first
+// as the writer would on its first snapshot.
+func checkSnapshotDir(dir string) (string, error) {
+	if dir == "" {
+		return "", errors.New("no snapshot dir configured")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(dir, ".preflight-*")
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+	_ = f.Close()
+	if err := os.Remove(name); err != nil {
+		return "", err
+	}
+	return dir + " is writable", nil
+}
+
+func providerName(cfg config.Config) string {
+	if cfg.Provider == "" {
+		return "fixture"
+	}
+	return cfg.Provider
+}
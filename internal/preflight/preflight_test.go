@@ -0,0 +1,129 @@
+package preflight
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+type stubProvider struct {
+	games []domaingames.Game
+	err   error
+	block bool
+	date  string
+}
+
+func (p *stubProvider) FetchGames(ctx context.Context, date, _ string) ([]domaingames.Game, error) {
+	p.date = date
+	if p.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return p.games, p.err
+}
+
+func passingOptions(t *testing.T, provider *stubProvider) Options {
+	t.Helper()
+	cfg := config.Config{Port: "4000", Provider: "fixture"}
+	cfg.Balldontlie.Timezone = "America/New_York"
+	cfg.Snapshots.SnapshotFolder = filepath.Join(t.TempDir(), "snapshots")
+	return Options{
+		Config:      cfg,
+		NewProvider: func(config.Config) providers.GameProvider { return provider },
+		Clock:       teststubs.NewFakeClock(time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC)),
+	}
+}
+
+func checkResult(t *testing.T, report Report, name string) Result {
+	t.Helper()
+	for _, r := range report.Checks {
+		if r.Name == name {
+			return r
+		}
+	}
+	t.Fatalf("no %s check in %+v", name, report)
+	return Result{}
+}
+
+func TestRunAllPass(t *testing.T) {
+	provider := &stubProvider{games: []domaingames.Game{{ID: "g1"}, {ID: "g2"}}}
+	opts := passingOptions(t, provider)
+	report := Run(context.Background(), opts)
+	if !report.OK() || len(report.Checks) != 3 {
+		t.Fatalf("expected three passing checks, got %+v", report)
+	}
+	if provider.date != "2024-01-15" {
+		t.Fatalf("expected today in the service timezone fetched, got %q", provider.date)
+	}
+	if got := checkResult(t, report, "provider").Detail; got != "2 games for 2024-01-15 from fixture" {
+		t.Fatalf("unexpected provider detail %q", got)
+	}
+	if _, err := os.Stat(opts.Config.Snapshots.SnapshotFolder); err != nil {
+		t.Fatalf("expected the snapshot dir created: %v", err)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil || !strings.Contains(string(data), `"status":"pass"`) || strings.Contains(string(data), `"error"`) {
+		t.Fatalf("unexpected report JSON %s %v", data, err)
+	}
+}
+
+func TestRunReportsEachFailingCheck(t *testing.T) {
+	cases := []struct {
+		name  string
+		check string
+		edit  func(t *testing.T, opts *Options, provider *stubProvider)
+		want  string
+	}{
+		{"invalid config", "config", func(_ *testing.T, opts *Options, _ *stubProvider) { opts.Config.Provider = "espn" }, "not a known provider"},
+		{"provider error", "provider", func(_ *testing.T, _ *Options, p *stubProvider) { p.err = errors.New("401 unauthorized") }, "fetch 2024-01-15: 401 unauthorized"},
+		{"provider timeout", "provider", func(_ *testing.T, opts *Options, p *stubProvider) {
+			p.block = true
+			opts.ProviderTimeout = time.Millisecond
+		}, "deadline exceeded"},
+		{"no provider", "provider", func(_ *testing.T, opts *Options, _ *stubProvider) { opts.NewProvider = nil }, "no provider constructor"},
+		{"unwritable snapshot dir", "snapshotDir", func(t *testing.T, opts *Options, _ *stubProvider) {
+			file := filepath.Join(t.TempDir(), "file")
+			_ = os.WriteFile(file, nil, 0o644)
+			opts.Config.Snapshots.SnapshotFolder = filepath.Join(file, "snapshots")
+		}, "not a directory"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &stubProvider{}
+			opts := passingOptions(t, provider)
+			tc.edit(t, &opts, provider)
+			report := Run(context.Background(), opts)
+			if report.OK() || len(report.Checks) != 3 {
+				t.Fatalf("expected a failing report with every check run, got %+v", report)
+			}
+			for _, r := range report.Checks {
+				failed := r.Status == StatusFail
+				if failed != (r.Name == tc.check) {
+					t.Fatalf("expected only %s to fail, got %+v", tc.check, report.Checks)
+				}
+				if failed && !strings.Contains(r.Error, tc.want) {
+					t.Fatalf("expected %q in %s error, got %q", tc.want, r.Name, r.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckConfigListsFallbacks(t *testing.T) {
+	t.Setenv("POLL_INTERVAL", "soon")
+	detail, err := checkConfig(config.Load())
+	if err != nil || !strings.Contains(detail, "POLL_INTERVAL") {
+		t.Fatalf("expected the ignored value listed without failing, got %q %v", detail, err)
+	}
+}
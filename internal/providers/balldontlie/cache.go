@@ -0,0 +1,125 @@
+package balldontlie
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+// pageCache remembers decoded pages with their ETag/Last-Modified validators,
+// keyed by request URL (so by date and page). Requests for a cached page are
+// sent conditionally and a 304 reuses the stored page without decoding.
+// Entries expire after ttl and the least recently used is evicted past max.
+type pageCache struct {
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+	max      int
+	ttl      time.Duration
+	recorder *metrics.Recorder
+}
+
+type cachedPage struct {
+	key          string
+	etag         string
+	lastModified string
+	data         any // the decoded []T
+	totalPages   int
+	stored       time.Time
+}
+
+// newPageCache returns nil, which disables caching, when max is negative.
+func newPageCache(max int, ttl time.Duration, recorder *metrics.Recorder) *pageCache {
+	if max < 0 {
+		return nil
+	}
+	if max == 0 {
+		max = defaultPageCacheEntries
+	}
+	if ttl <= 0 {
+		ttl = defaultPageCacheTTL
+	}
+	return &pageCache{
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		max:      max,
+		ttl:      ttl,
+		recorder: recorder,
+	}
+}
+
+// lookup returns the live entry for key, dropping it if expired.
+func (c *pageCache) lookup(key string, now time.Time) *cachedPage {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	page := el.Value.(*cachedPage)
+	if now.Sub(page.stored) >= c.ttl {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil
+	}
+	c.order.MoveToFront(el)
+	return page
+}
+
+// store caches a decoded page if the response carried a validator.
+func (c *pageCache) store(key string, resp *http.Response, data any, totalPages int, now time.Time) {
+	if c == nil {
+		return
+	}
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+	page := &cachedPage{key: key, etag: etag, lastModified: lastModified, data: data, totalPages: totalPages, stored: now}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value = page
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(page)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedPage).key)
+	}
+}
+
+// refresh restarts an entry's ttl after the upstream confirmed it unchanged.
+func (c *pageCache) refresh(page *cachedPage, now time.Time) {
+	c.mu.Lock()
+	page.stored = now
+	c.mu.Unlock()
+}
+
+func (c *pageCache) record(hit bool) {
+	if c != nil {
+		c.recorder.RecordProviderCache(providerName, hit)
+	}
+}
+
+// setConditional adds validators from a cached page to req.
+func setConditional(req *http.Request, page *cachedPage) {
+	if page == nil {
+		return
+	}
+	if page.etag != "" {
+		req.Header.Set("If-None-Match", page.etag)
+	}
+	if page.lastModified != "" {
+		req.Header.Set("If-Modified-Since", page.lastModified)
+	}
+}
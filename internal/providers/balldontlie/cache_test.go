@@ -0,0 +1,125 @@
+package balldontlie
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+const cachedGamesBody = `{"data":[{"id":10,"date":"2024-01-15","status":"Final","home_team":{"id":1},"visitor_team":{"id":2}}],"meta":{"total_pages":1}}`
+
+// conditionalUpstream answers 200 with an ETag, or 304 when If-None-Match
+// matches it, counting full bodies sent and the conditional headers seen.
+type conditionalUpstream struct {
+	fullBodies  int
+	conditional []string
+}
+
+func (u *conditionalUpstream) roundTrip(req *http.Request) (*http.Response, error) {
+	u.conditional = append(u.conditional, req.Header.Get("If-None-Match"))
+	if req.Header.Get("If-None-Match") == `"v1"` {
+		// An empty body would fail to decode, so a 304 must not be decoded.
+		return &http.Response{StatusCode: http.StatusNotModified, Body: io.NopCloser(strings.NewReader("")), Header: make(http.Header)}, nil
+	}
+	u.fullBodies++
+	header := make(http.Header)
+	header.Set("ETag", `"v1"`)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(cachedGamesBody)), Header: header}, nil
+}
+
+func cachingClient(u *conditionalUpstream, clk *teststubs.FakeClock, rec *metrics.Recorder, size int) *Client {
+	return NewClient(Config{
+		BaseURL:       "http://example.com",
+		HTTPClient:    &http.Client{Transport: roundTripperFunc(u.roundTrip)},
+		Clock:         clk,
+		Metrics:       rec,
+		PageCacheSize: size,
+		PageCacheTTL:  time.Minute,
+	})
+}
+
+func TestFetchGamesReusesCachedPageOn304(t *testing.T) {
+	u := &conditionalUpstream{}
+	rec := metrics.NewRecorder()
+	client := cachingClient(u, teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)), rec, 0)
+
+	first, err := client.FetchGames(context.Background(), "2024-01-15", "")
+	if err != nil {
+		t.Fatalf("first fetch: %v", err)
+	}
+	second, err := client.FetchGames(context.Background(), "2024-01-15", "")
+	if err != nil {
+		t.Fatalf("second fetch: %v", err)
+	}
+	if len(second) != 1 || second[0].ID != first[0].ID {
+		t.Fatalf("expected the cached page on 304, got %+v", second)
+	}
+	if u.fullBodies != 1 || u.conditional[0] != "" || u.conditional[1] != `"v1"` {
+		t.Fatalf("expected one full fetch then a conditional one, got %d bodies, headers %q", u.fullBodies, u.conditional)
+	}
+	if hits, misses := rec.ProviderCacheStats(); hits != 1 || misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got %d and %d", hits, misses)
+	}
+}
+
+func TestPageCacheEntriesExpire(t *testing.T) {
+	u := &conditionalUpstream{}
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	client := cachingClient(u, clk, nil, 0)
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	clk.Advance(time.Minute)
+	if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if u.fullBodies != 2 || u.conditional[1] != "" {
+		t.Fatalf("expected an expired entry refetched unconditionally, got %d bodies, headers %q", u.fullBodies, u.conditional)
+	}
+}
+
+func TestPageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	u := &conditionalUpstream{}
+	client := cachingClient(u, teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)), nil, 1)
+
+	for _, date := range []string{"2024-01-14", "2024-01-15", "2024-01-14"} {
+		if _, err := client.FetchGames(context.Background(), date, ""); err != nil {
+			t.Fatalf("fetch %s: %v", date, err)
+		}
+	}
+	if u.fullBodies != 3 {
+		t.Fatalf("expected the first date evicted by the second, got %d full fetches", u.fullBodies)
+	}
+	if n := client.pages.order.Len(); n != 1 {
+		t.Fatalf("expected cache bounded at 1 entry, got %d", n)
+	}
+}
+
+func TestPageCacheSkipsResponsesWithoutValidators(t *testing.T) {
+	var conditional []string
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		conditional = append(conditional, req.Header.Get("If-None-Match")+req.Header.Get("If-Modified-Since"))
+		return okGamesResponse(), nil
+	})
+	client := NewClient(Config{BaseURL: "http://example.com", HTTPClient: &http.Client{Transport: rt}})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+	}
+	if conditional[1] != "" {
+		t.Fatalf("expected no conditional request without validators, got %q", conditional[1])
+	}
+
+	if newPageCache(-1, 0, nil) != nil {
+		t.Fatalf("expected a negative size to disable the cache")
+	}
+}
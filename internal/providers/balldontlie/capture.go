@@ -0,0 +1,102 @@
+package balldontlie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// CapturePath is where capture mode saves, and the replay provider reads, the
+// body of one /games page for date: <dir>/<date>/page-<page>.json.
+func CapturePath(dir, date string, page int) string {
+	return filepath.Join(dir, date, fmt.Sprintf("page-%d.json", page))
+}
+
+// captureTransport tees successful /games response bodies to CapturePath as
+// the client reads them. A body is saved only once it has been read to the
+// end, so a page the client gave up on (too large, cancelled) leaves no file.
+// Saving never fails the request; errors are logged.
+type captureTransport struct {
+	next   http.RoundTripper
+	dir    string
+	logger *slog.Logger
+}
+
+func newCaptureTransport(next http.RoundTripper, dir string, logger *slog.Logger) *captureTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &captureTransport{next: next, dir: dir, logger: logger}
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK || !strings.HasSuffix(req.URL.Path, "/games") {
+		return resp, err
+	}
+	q := req.URL.Query()
+	date := q.Get("dates[]")
+	page, pageErr := strconv.Atoi(q.Get("page"))
+	if _, dateErr := timeutil.ParseDate(date); dateErr != nil || pageErr != nil || page < 1 {
+		return resp, nil
+	}
+	path := CapturePath(t.dir, date, page)
+	resp.Body = &captureBody{ReadCloser: resp.Body, save: func(body []byte) {
+		if err := writeCapture(path, body); err != nil {
+			logging.Warn(t.logger, "balldontlie capture failed",
+				slog.String(logging.FieldProvider, providerName),
+				slog.String("path", path),
+				slog.String("error", err.Error()),
+			)
+		}
+	}}
+	return resp, nil
+}
+
+// captureBody copies what is read through it and hands the copy to save on
+// Close if the underlying body reached EOF.
+type captureBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	eof  bool
+	save func([]byte)
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		b.eof = true
+	}
+	return n, err
+}
+
+func (b *captureBody) Close() error {
+	if b.eof && b.save != nil {
+		b.save(b.buf.Bytes())
+		b.save = nil
+	}
+	return b.ReadCloser.Close()
+}
+
+// writeCapture replaces path atomically so a replay never reads half a page.
+func writeCapture(path string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,76 @@
+package balldontlie
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func capturePage(id, totalPages int) string {
+	return fmt.Sprintf(`{"data":[{"id":%d,"date":"2024-01-02","datetime":"2024-01-02T15:00:00Z","status":"Final","period":4,`+
+		`"home_team":{"id":1,"abbreviation":"HTM"},"visitor_team":{"id":2,"abbreviation":"AWY"},"home_team_score":110,"visitor_team_score":102,"season":2023}],`+
+		`"meta":{"total_pages":%d}}`, id, totalPages)
+}
+
+func TestCaptureWritesSuccessfulGamesPages(t *testing.T) {
+	dir := t.TempDir()
+	pages := map[string]string{"1": capturePage(1, 2), "2": capturePage(2, 2)}
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("dates[]") == "2024-01-03" {
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("boom")), Header: make(http.Header)}, nil
+		}
+		body := pages[req.URL.Query().Get("page")]
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})
+	client := NewClient(Config{APIKey: "key", HTTPClient: &http.Client{Transport: rt}, CaptureDir: dir, PageCacheSize: -1})
+
+	games, err := client.FetchGames(context.Background(), "2024-01-02", "")
+	if err != nil || len(games) != 2 {
+		t.Fatalf("expected both pages fetched, got %d games %v", len(games), err)
+	}
+	for page, want := range pages {
+		got, err := os.ReadFile(filepath.Join(dir, "2024-01-02", "page-"+page+".json"))
+		if err != nil || string(got) != want {
+			t.Fatalf("page %s: expected the body captured verbatim, got %q %v", page, got, err)
+		}
+	}
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-03", ""); err == nil {
+		t.Fatalf("expected an upstream error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2024-01-03")); !os.IsNotExist(err) {
+		t.Fatalf("expected failed responses not captured, got %v", err)
+	}
+}
+
+func TestCaptureSkipsBodiesNotReadToTheEnd(t *testing.T) {
+	dir := t.TempDir()
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(capturePage(1, 1))), Header: make(http.Header)}, nil
+	})
+	transport := newCaptureTransport(rt, dir, nil)
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid/games?dates[]=2024-01-02&page=1", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	_, _ = resp.Body.Read(make([]byte, 4))
+	_ = resp.Body.Close()
+	if _, err := os.Stat(CapturePath(dir, "2024-01-02", 1)); !os.IsNotExist(err) {
+		t.Fatalf("expected a partly read body not captured, got %v", err)
+	}
+
+	other, _ := http.NewRequest(http.MethodGet, "http://example.invalid/teams?page=1", nil)
+	resp, _ = transport.RoundTrip(other)
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected only /games responses captured, got %v", entries)
+	}
+}
@@ -6,48 +6,81 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 // Config controls how the balldontlie client reaches the upstream API.
 type Config struct {
-	BaseURL    string
-	APIKey     string
-	HTTPClient *http.Client
-	Timezone   string
-	MaxPages   int
-	PageDelay  time.Duration
+	BaseURL         string
+	APIKey          string
+	SecondaryAPIKey string // optional; used when the primary key is rejected with 401
+	HTTPClient      *http.Client
+	Timezone        string
+	MaxPages        int
+	PageDelay       time.Duration
+	Logger          *slog.Logger
+	Clock           clock.Clock // defaults to the real clock
+	Metrics         *metrics.Recorder
+	Quota           *providers.QuotaTracker // fed each response's rate limit remaining header
+	// PageCacheSize caps how many pages are kept for conditional requests;
+	// 0 uses the default and a negative value disables the cache.
+	PageCacheSize int
+	PageCacheTTL  time.Duration // defaults to defaultPageCacheTTL
+	// MaxBodyBytes caps each response body; 0 uses
+	// providers.DefaultMaxResponseBytes.
+	MaxBodyBytes int64
+	// StrictDecode fails a page whose JSON has fields the client does not
+	// know; otherwise they are logged once and ignored.
+	StrictDecode bool
+	// CaptureDir, when set, receives a copy of every successful /games
+	// response body at CapturePath, for the replay provider to serve later.
+	CaptureDir string
 }
 
 // Client fetches games from the balldontlie API and maps them to domain models.
 type Client struct {
 	baseURL    string
-	apiKey     string
+	keys       *apiKeys
 	httpClient httpDoer
-	now        func() time.Time
+	clock      clock.Clock
 	loc        *time.Location
 	maxPages   int
 	pageDelay  time.Duration
+	pages      *pageCache
+	bodies     *bodyDecoder
+
+	serverClock serverClock
 }
 
 // NewClient constructs a balldontlie client with the provided configuration.
 func NewClient(cfg Config) *Client {
+	observed := providers.ObservedTransportConfig{
+		Logger:  cfg.Logger,
+		Metrics: cfg.Metrics,
+		Quota:   cfg.Quota,
+		Clock:   cfg.Clock,
+	}
 	return &Client{
 		baseURL:    normalizeBaseURL(cfg.BaseURL),
-		apiKey:     cfg.APIKey,
-		httpClient: resolveHTTPClient(cfg.HTTPClient),
-		now:        time.Now,
+		keys:       newAPIKeys(cfg.APIKey, cfg.SecondaryAPIKey, cfg.Logger),
+		httpClient: resolveHTTPClient(cfg.HTTPClient, observed, cfg.CaptureDir),
+		clock:      clock.OrReal(cfg.Clock),
 		loc:        resolveLocation(cfg.Timezone),
 		maxPages:   resolveMaxPages(cfg.MaxPages),
 		pageDelay:  cfg.PageDelay,
+		pages:      newPageCache(cfg.PageCacheSize, cfg.PageCacheTTL, cfg.Metrics),
+		bodies:     newBodyDecoder(cfg.MaxBodyBytes, cfg.StrictDecode, cfg.Logger),
 	}
 }
 
@@ -63,23 +96,36 @@ func (c *Client) FetchGames(ctx context.Context, date string, tz string) ([]doma
 	buildReq := func(page int) (*http.Request, error) {
 		return c.buildRequest(ctx, date, page, loc)
 	}
-	decode := func(dec *json.Decoder) ([]domaingames.Game, int, error) {
-		var payload gamesResponse
-		if err := dec.Decode(&payload); err != nil {
-			return nil, 0, err
-		}
-		mapped := make([]domaingames.Game, 0, len(payload.Data))
-		for _, g := range payload.Data {
-			mapped = append(mapped, mapGame(g))
-		}
-		return mapped, payload.Meta.TotalPages, nil
-	}
-
-	games, err := fetchPaged(ctx, c.maxPages, c.pageDelay, c.now, c.httpClient, buildReq, decode)
+	games, err := fetchPaged(ctx, c.maxPages, c.pageDelay, c.clock, doerFunc(c.do), c.pages, c.bodies, buildReq, decodeGames)
 	if err != nil {
 		return nil, err
 	}
-	return dedupe(games, func(g domaingames.Game) string { return g.ID }), nil
+	return DedupeGames(games), nil
+}
+
+// DecodeGamesPage maps one /games response body to domain games and returns
+// them along with the response's total page count (0 when upstream omits it).
+// Unknown fields are ignored.
+func DecodeGamesPage(body io.Reader) ([]domaingames.Game, int, error) {
+	return decodeGames(json.NewDecoder(body))
+}
+
+// DedupeGames drops games repeated across pages and orders the rest by ID,
+// as FetchGames does with the pages it fetched.
+func DedupeGames(games []domaingames.Game) []domaingames.Game {
+	return dedupe(games, func(g domaingames.Game) string { return g.ID })
+}
+
+func decodeGames(dec *json.Decoder) ([]domaingames.Game, int, error) {
+	var payload gamesResponse
+	if err := dec.Decode(&payload); err != nil {
+		return nil, 0, err
+	}
+	mapped := make([]domaingames.Game, 0, len(payload.Data))
+	for _, g := range payload.Data {
+		mapped = append(mapped, mapGame(g))
+	}
+	return mapped, payload.Meta.TotalPages, nil
 }
 
 func (c *Client) buildRequest(ctx context.Context, date string, page int, loc *time.Location) (*http.Request, error) {
@@ -94,10 +140,6 @@ func (c *Client) buildRequest(ctx context.Context, date string, page int, loc *t
 	q.Set("page", strconv.Itoa(page))
 	req.URL.RawQuery = q.Encode()
 
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	}
-
 	return req, nil
 }
 
@@ -107,7 +149,7 @@ func (c *Client) resolveDate(date string, loc *time.Location) string {
 			return date
 		}
 	}
-	return timeutil.FormatDate(c.now().In(loc))
+	return timeutil.DateIn(c.clock.Now(), loc)
 }
 
 func classifyErrorResponse(resp *http.Response, body []byte, now time.Time) error {
@@ -125,12 +167,16 @@ func classifyErrorResponse(resp *http.Response, body []byte, now time.Time) erro
 }
 
 // fetchPaged centralizes pagination and error handling for list endpoints.
+// With a cache, pages seen before are requested conditionally and a 304 reuses
+// the cached page instead of decoding again.
 func fetchPaged[T any](
 	ctx context.Context,
 	maxPages int,
 	pageDelay time.Duration,
-	now func() time.Time,
+	clk clock.Clock,
 	doer httpDoer,
+	cache *pageCache,
+	bodies *bodyDecoder,
 	buildReq func(page int) (*http.Request, error),
 	decode func(dec *json.Decoder) ([]T, int, error),
 ) ([]T, error) {
@@ -143,21 +189,37 @@ func fetchPaged[T any](
 			return nil, err
 		}
 
+		key := req.URL.String()
+		cached := cache.lookup(key, clk.Now())
+		setConditional(req, cached)
+
 		resp, err := doer.Do(req)
 		if err != nil {
 			return nil, err
 		}
 
-		if resp.StatusCode != http.StatusOK {
+		var (
+			data       []T
+			totalPages int
+		)
+		switch {
+		case resp.StatusCode == http.StatusNotModified && cached != nil:
+			_ = resp.Body.Close()
+			cache.refresh(cached, clk.Now())
+			cache.record(true)
+			data, totalPages = cached.data.([]T), cached.totalPages
+		case resp.StatusCode != http.StatusOK:
 			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
 			_ = resp.Body.Close()
-			return nil, classifyErrorResponse(resp, body, now())
-		}
-
-		data, totalPages, err := decode(json.NewDecoder(resp.Body))
-		_ = resp.Body.Close()
-		if err != nil {
-			return nil, err
+			return nil, classifyErrorResponse(resp, body, clk.Now())
+		default:
+			data, totalPages, err = decodeBody(bodies, resp.Body, decode)
+			_ = resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			cache.record(false)
+			cache.store(key, resp, data, totalPages, clk.Now())
 		}
 
 		all = append(all, data...)
@@ -172,10 +234,12 @@ func fetchPaged[T any](
 			}
 		}
 		if pageDelay > 0 {
+			timer := clk.NewTimer(pageDelay)
 			select {
 			case <-ctx.Done():
+				timer.Stop()
 				return all, ctx.Err()
-			case <-time.After(pageDelay):
+			case <-timer.C():
 			}
 		}
 		page++
@@ -228,7 +292,7 @@ func rateLimitDetails(resp *http.Response, body []byte, now time.Time) (time.Dur
 	}
 
 	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), now)
-	remaining := resp.Header.Get("X-Rate-Limit-Remaining")
+	remaining := resp.Header.Get(providers.HeaderRateLimitRemaining)
 
 	return retryAfter, remaining, true, msg
 }
@@ -11,6 +11,7 @@ import (
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
 func TestFetchGamesHitsAPIAndMapsResponse(t *testing.T) {
@@ -85,7 +86,7 @@ func TestFetchGamesHitsAPIAndMapsResponse(t *testing.T) {
 		Timezone:   "America/New_York",
 		MaxPages:   2,
 	})
-	client.now = func() time.Time { return fixed }
+	client.clock = teststubs.NewFakeClock(fixed)
 
 	games, err := client.FetchGames(context.Background(), "", "")
 	if err != nil {
@@ -156,7 +157,7 @@ func TestFetchGamesHandlesNon200(t *testing.T) {
 		BaseURL:    "http://example.com",
 		HTTPClient: &http.Client{Transport: rt},
 	})
-	client.now = func() time.Time { return time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) }
+	client.clock = teststubs.NewFakeClock(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
 
 	if _, err := client.FetchGames(context.Background(), "", ""); err == nil {
 		t.Fatal("expected error on non-200 response")
@@ -177,7 +178,7 @@ func TestFetchGamesHandlesDecodeError(t *testing.T) {
 		BaseURL:    "http://example.com",
 		HTTPClient: &http.Client{Transport: rt},
 	})
-	client.now = func() time.Time { return time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC) }
+	client.clock = teststubs.NewFakeClock(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
 
 	if _, err := client.FetchGames(context.Background(), "", ""); err == nil {
 		t.Fatal("expected decode error")
@@ -202,7 +203,7 @@ func TestFetchGamesHandlesRateLimit(t *testing.T) {
 		BaseURL:    "http://example.com",
 		HTTPClient: &http.Client{Transport: rt},
 	})
-	client.now = func() time.Time { return time.Unix(0, 0) }
+	client.clock = teststubs.NewFakeClock(time.Unix(0, 0))
 
 	_, err := client.FetchGames(context.Background(), "", "")
 	if err == nil {
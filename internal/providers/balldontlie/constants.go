@@ -8,4 +8,7 @@ const (
 	defaultHTTPTimeout = 10 * time.Second
 	defaultTimezone    = "America/New_York"
 	defaultMaxPages    = 5
+
+	defaultPageCacheEntries = 64
+	defaultPageCacheTTL     = 10 * time.Minute
 )
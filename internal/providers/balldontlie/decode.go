@@ -0,0 +1,61 @@
+package balldontlie
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+)
+
+// bodyDecoder reads response bodies under a size cap and checks them for
+// fields the response types do not know, an early sign of upstream schema
+// drift. Strict mode fails the page on an unknown field; lenient mode logs
+// it, once per distinct field, and decodes the page anyway. Only the first
+// unknown field in a page is reported.
+type bodyDecoder struct {
+	maxBytes int64
+	strict   bool
+	logger   *slog.Logger
+	warned   sync.Map // unknown field errors already logged
+}
+
+func newBodyDecoder(maxBytes int64, strict bool, logger *slog.Logger) *bodyDecoder {
+	return &bodyDecoder{maxBytes: maxBytes, strict: strict, logger: logger}
+}
+
+// decodeBody reads body and hands it to decode. The whole body is read first
+// so lenient mode can decode it again without the unknown-field check.
+func decodeBody[T any](d *bodyDecoder, body io.Reader, decode func(dec *json.Decoder) ([]T, int, error)) ([]T, int, error) {
+	raw, err := io.ReadAll(providers.LimitResponseBody(body, d.maxBytes))
+	if err != nil {
+		return nil, 0, fmt.Errorf("balldontlie: read response: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	data, totalPages, err := decode(dec)
+	if err == nil || !isUnknownField(err) {
+		return data, totalPages, err
+	}
+	if d.strict {
+		return nil, 0, fmt.Errorf("balldontlie: decode response: %w", err)
+	}
+	if _, seen := d.warned.LoadOrStore(err.Error(), struct{}{}); !seen {
+		logging.Warn(d.logger, "balldontlie response has unknown fields",
+			slog.String(logging.FieldProvider, providerName),
+			slog.String("error", err.Error()),
+		)
+	}
+	return decode(json.NewDecoder(bytes.NewReader(raw)))
+}
+
+// isUnknownField reports whether err came from DisallowUnknownFields;
+// encoding/json has no typed error for it.
+func isUnknownField(err error) bool {
+	return strings.HasPrefix(err.Error(), "json: unknown field ")
+}
@@ -0,0 +1,85 @@
+package balldontlie
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+const driftedPage = `{"data":[{"id":7,"status":"Final","home_team":{"id":1},"visitor_team":{"id":2},"arena":"TD Garden"}],"meta":{"total_pages":1,"next_cursor":8}}`
+
+func staticClient(t *testing.T, body string, cfg Config) *Client {
+	t.Helper()
+	cfg.BaseURL = "http://example.com"
+	cfg.PageCacheSize = -1
+	cfg.HTTPClient = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})}
+	client := NewClient(cfg)
+	client.clock = teststubs.NewFakeClock(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	return client
+}
+
+func TestFetchGamesRejectsOversizedBody(t *testing.T) {
+	body := `{"data":[],"meta":{"total_pages":1},"padding":"` + strings.Repeat("x", 2048) + `"}`
+	client := staticClient(t, body, Config{MaxBodyBytes: 1024})
+
+	_, err := client.FetchGames(context.Background(), "2024-01-02", "")
+	if !errors.Is(err, providers.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestFetchGamesUnknownFieldsLenientLogsOnce(t *testing.T) {
+	var buf bytes.Buffer
+	client := staticClient(t, driftedPage, Config{Logger: slog.New(slog.NewTextHandler(&buf, nil))})
+
+	for i := 0; i < 2; i++ {
+		games, err := client.FetchGames(context.Background(), "2024-01-02", "")
+		if err != nil {
+			t.Fatalf("expected lenient mode to decode, got %v", err)
+		}
+		if len(games) != 1 || games[0].ID != "balldontlie-7" {
+			t.Fatalf("unexpected games %+v", games)
+		}
+	}
+	out := buf.String()
+	if got := strings.Count(out, "balldontlie response has unknown fields"); got != 1 {
+		t.Fatalf("expected one unknown field warning, got %d: %s", got, out)
+	}
+	if !strings.Contains(out, "arena") {
+		t.Fatalf("expected the unknown field named, got %s", out)
+	}
+}
+
+func TestFetchGamesUnknownFieldsFailInStrictMode(t *testing.T) {
+	client := staticClient(t, driftedPage, Config{StrictDecode: true})
+
+	_, err := client.FetchGames(context.Background(), "2024-01-02", "")
+	if err == nil || !strings.Contains(err.Error(), `unknown field "arena"`) {
+		t.Fatalf("expected an unknown field error, got %v", err)
+	}
+}
+
+func TestFetchGamesStrictModeAcceptsKnownFields(t *testing.T) {
+	body := `{"data":[{"id":7,"status":"Final","home_team":{"id":1},"visitor_team":{"id":2}}],"meta":{"total_pages":1}}`
+	client := staticClient(t, body, Config{StrictDecode: true})
+
+	if games, err := client.FetchGames(context.Background(), "2024-01-02", ""); err != nil || len(games) != 1 {
+		t.Fatalf("expected a known payload to decode, got %v %+v", err, games)
+	}
+}
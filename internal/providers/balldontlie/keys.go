@@ -0,0 +1,120 @@
+package balldontlie
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+)
+
+const (
+	keySlotPrimary   = "primary"
+	keySlotSecondary = "secondary"
+	keySlotNone      = "none"
+)
+
+// apiKeys tracks the configured primary/secondary keys and which one is active.
+// On a 401 with the active key the other slot is tried once and promoted if it works,
+// so keys can be rotated upstream without restarting the service.
+type apiKeys struct {
+	mu     sync.RWMutex
+	keys   [2]string
+	active int
+	logger *slog.Logger
+}
+
+func newAPIKeys(primary, secondary string, logger *slog.Logger) *apiKeys {
+	return &apiKeys{
+		keys:   [2]string{primary, secondary},
+		logger: logger,
+	}
+}
+
+func (k *apiKeys) current() (int, string) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.active, k.keys[k.active]
+}
+
+// alternate returns the other slot when it has a key configured.
+func (k *apiKeys) alternate(slot int) (int, string, bool) {
+	other := 1 - slot
+	key := k.keys[other]
+	return other, key, key != ""
+}
+
+func (k *apiKeys) promote(slot int) {
+	k.mu.Lock()
+	changed := k.active != slot
+	k.active = slot
+	k.mu.Unlock()
+	if changed {
+		logging.Warn(k.logger, "balldontlie api key promoted",
+			slog.String("provider", providerName),
+			slog.String("key_slot", slotName(slot)),
+		)
+	}
+}
+
+// slot reports the active key slot name; never the key value.
+func (k *apiKeys) slot() string {
+	active, key := k.current()
+	if key == "" {
+		return keySlotNone
+	}
+	return slotName(active)
+}
+
+func slotName(slot int) string {
+	if slot == 1 {
+		return keySlotSecondary
+	}
+	return keySlotPrimary
+}
+
+func setAuthorization(req *http.Request, key string) {
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+}
+
+// doWithKeys sends req using the active key and fails over to the alternate key on 401.
+func (c *Client) doWithKeys(req *http.Request) (*http.Response, error) {
+	slot, key := c.keys.current()
+	setAuthorization(req, key)
+	resp, err := c.httpClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	other, otherKey, ok := c.keys.alternate(slot)
+	if !ok {
+		return resp, nil
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 512))
+	_ = resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	setAuthorization(retry, otherKey)
+	resp, err = c.httpClient.Do(retry)
+	if err == nil && (resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNotModified) {
+		c.keys.promote(other)
+	}
+	return resp, err
+}
+
+// ActiveKeySlot reports which configured API key slot is in use ("primary", "secondary", or "none").
+func (c *Client) ActiveKeySlot() string {
+	if c == nil || c.keys == nil {
+		return keySlotNone
+	}
+	return c.keys.slot()
+}
+
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
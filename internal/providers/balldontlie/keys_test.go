@@ -0,0 +1,135 @@
+package balldontlie
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func okGamesResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"data":[],"meta":{"total_pages":1}}`)),
+		Header:     make(http.Header),
+	}
+}
+
+func unauthorizedResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Body:       io.NopCloser(strings.NewReader(`{"error":"unauthorized"}`)),
+		Header:     make(http.Header),
+	}
+}
+
+func TestFetchGamesPromotesSecondaryKeyAfterPrimaryRevoked(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		revoked bool
+		seen    []string
+	)
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		auth := req.Header.Get("Authorization")
+		seen = append(seen, auth)
+		if auth == "Bearer primary" && revoked {
+			return unauthorizedResponse(), nil
+		}
+		return okGamesResponse(), nil
+	})
+
+	client := NewClient(Config{
+		BaseURL:         "http://example.com",
+		APIKey:          "primary",
+		SecondaryAPIKey: "secondary",
+		HTTPClient:      &http.Client{Transport: rt},
+	})
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected first fetch to succeed, got %v", err)
+	}
+	if got := client.ActiveKeySlot(); got != keySlotPrimary {
+		t.Fatalf("expected primary slot before revocation, got %s", got)
+	}
+
+	mu.Lock()
+	revoked = true
+	seen = nil
+	mu.Unlock()
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected failover fetch to succeed, got %v", err)
+	}
+	if got := client.ActiveKeySlot(); got != keySlotSecondary {
+		t.Fatalf("expected secondary slot after promotion, got %s", got)
+	}
+	if len(seen) != 2 || seen[0] != "Bearer primary" || seen[1] != "Bearer secondary" {
+		t.Fatalf("expected primary then secondary attempts, got %v", seen)
+	}
+
+	mu.Lock()
+	seen = nil
+	mu.Unlock()
+	if _, err := client.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected promoted key fetch to succeed, got %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "Bearer secondary" {
+		t.Fatalf("expected promoted key used directly, got %v", seen)
+	}
+}
+
+func TestFetchGamesWithoutSecondaryKeySurfacesUnauthorized(t *testing.T) {
+	calls := 0
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return unauthorizedResponse(), nil
+	})
+	client := NewClient(Config{
+		BaseURL:    "http://example.com",
+		APIKey:     "primary",
+		HTTPClient: &http.Client{Transport: rt},
+	})
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-01", ""); err == nil {
+		t.Fatalf("expected unauthorized error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single attempt without a secondary key, got %d", calls)
+	}
+	if got := client.ActiveKeySlot(); got != keySlotPrimary {
+		t.Fatalf("expected primary slot retained, got %s", got)
+	}
+}
+
+func TestFetchGamesKeepsActiveKeyWhenSecondaryAlsoRejected(t *testing.T) {
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return unauthorizedResponse(), nil
+	})
+	client := NewClient(Config{
+		BaseURL:         "http://example.com",
+		APIKey:          "primary",
+		SecondaryAPIKey: "secondary",
+		HTTPClient:      &http.Client{Transport: rt},
+	})
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-01", ""); err == nil {
+		t.Fatalf("expected unauthorized error when both keys are rejected")
+	}
+	if got := client.ActiveKeySlot(); got != keySlotPrimary {
+		t.Fatalf("expected no promotion on failed failover, got %s", got)
+	}
+}
+
+func TestActiveKeySlotNoneWithoutKeys(t *testing.T) {
+	if got := NewClient(Config{}).ActiveKeySlot(); got != keySlotNone {
+		t.Fatalf("expected none slot, got %s", got)
+	}
+	var nilClient *Client
+	if got := nilClient.ActiveKeySlot(); got != keySlotNone {
+		t.Fatalf("expected none slot for nil client, got %s", got)
+	}
+}
@@ -3,6 +3,7 @@ package balldontlie
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
@@ -18,7 +19,7 @@ func mapGame(g gameResponse) games.Game {
 		AwayTeam:   mapTeam(g.VisitorTeam),
 		StartTime:  g.Datetime,
 		Status:     status,
-		StatusKind: mapStatusKind(status),
+		StatusKind: mapStatusKind(status, g.Period),
 		Score: games.Score{
 			Home: g.HomeTeamScore,
 			Away: g.VisitorTeamScore,
@@ -28,6 +29,7 @@ func mapGame(g gameResponse) games.Game {
 			UpstreamGameID: g.ID,
 			Period:         g.Period,
 			Postseason:     g.Postseason,
+			GameType:       mapGameType(g.Postseason),
 			Time:           strings.TrimSpace(g.Time),
 		},
 	}
@@ -45,21 +47,59 @@ func mapTeam(t teamResponse) teams.Team {
 	}
 }
 
-func mapStatusKind(status string) games.GameStatusKind {
-	switch strings.ToLower(status) {
-	case "final", "ended":
+// mapStatusKind classifies the upstream status. Besides the fixed words,
+// balldontlie reports live games by period ("1st Qtr", "Halftime", "End of 3rd
+// Qtr", "OT") and not-yet-started games by their RFC 3339 tip-off. An
+// unrecognised status counts as live once the payload has a period.
+func mapStatusKind(status string, period int) games.GameStatusKind {
+	lower := strings.ToLower(status)
+	switch {
+	case lower == "final" || strings.HasPrefix(lower, "final/") || lower == "ended":
 		return games.StatusFinal
-	case "in progress", "halftime", "end of period":
-		return games.StatusInProgress
-	case "postponed":
+	case lower == "postponed":
 		return games.StatusPostponed
-	case "canceled", "cancelled":
+	case lower == "canceled" || lower == "cancelled":
 		return games.StatusCanceled
+	case isTipOff(status):
+		return games.StatusScheduled
+	case isLiveStatus(lower), period > 0:
+		return games.StatusInProgress
 	default:
 		return games.StatusScheduled
 	}
 }
 
+// isTipOff reports whether status is a scheduled start time.
+func isTipOff(status string) bool {
+	_, err := time.Parse(time.RFC3339, status)
+	return err == nil
+}
+
+// isLiveStatus reports whether the lowercased status names a point in a game.
+func isLiveStatus(lower string) bool {
+	if lower == "in progress" || strings.HasPrefix(lower, "end of") {
+		return true
+	}
+	for _, word := range strings.Fields(lower) {
+		switch word {
+		case "qtr", "quarter", "half", "halftime", "ot":
+			return true
+		}
+		// 2OT, 3OT, ...
+		if n := strings.TrimSuffix(word, "ot"); n != word && n != "" && strings.Trim(n, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func mapGameType(postseason bool) string {
+	if postseason {
+		return games.GameTypePostseason
+	}
+	return games.GameTypeRegularSeason
+}
+
 func formatSeason(season int) string {
 	return fmt.Sprintf("%d", season)
 }
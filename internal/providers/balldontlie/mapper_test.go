@@ -43,6 +43,9 @@ func TestMapGameTransformsFields(t *testing.T) {
 	if game.Meta.Period != 3 || !game.Meta.Postseason || game.Meta.Time != "Q3 05:00" {
 		t.Fatalf("unexpected meta extras %+v", game.Meta)
 	}
+	if game.Meta.GameType != games.GameTypePostseason {
+		t.Fatalf("expected postseason game type, got %q", game.Meta.GameType)
+	}
 	if game.HomeTeam.ID != "HMS" || game.AwayTeam.ID != "AWS" {
 		t.Fatalf("unexpected team ids home=%s away=%s", game.HomeTeam.ID, game.AwayTeam.ID)
 	}
@@ -52,21 +55,43 @@ func TestMapGameTransformsFields(t *testing.T) {
 }
 
 func TestMapStatusKindCoversVariants(t *testing.T) {
-	cases := map[string]games.GameStatusKind{
-		"Final":       games.StatusFinal,
-		"In Progress": games.StatusInProgress,
-		"Postponed":   games.StatusPostponed,
-		"Canceled":    games.StatusCanceled,
-		"Unknown":     games.StatusScheduled,
+	cases := []struct {
+		status string
+		period int
+		want   games.GameStatusKind
+	}{
+		{"Final", 4, games.StatusFinal},
+		{"Final/OT", 5, games.StatusFinal},
+		{"In Progress", 2, games.StatusInProgress},
+		{"1st Qtr", 1, games.StatusInProgress},
+		{"2nd Qtr", 2, games.StatusInProgress},
+		{"Halftime", 2, games.StatusInProgress},
+		{"End of 3rd Qtr", 3, games.StatusInProgress},
+		{"4th Qtr", 4, games.StatusInProgress},
+		{"OT", 5, games.StatusInProgress},
+		{"2OT", 6, games.StatusInProgress},
+		{"End of Period", 0, games.StatusInProgress},
+		{"2024-01-16T00:30:00Z", 0, games.StatusScheduled},
+		{"2024-01-15T19:30:00-05:00", 0, games.StatusScheduled},
+		{"Postponed", 0, games.StatusPostponed},
+		{"Canceled", 0, games.StatusCanceled},
+		{"Unknown", 0, games.StatusScheduled},
+		{"Unknown", 3, games.StatusInProgress},
 	}
 
-	for input, expected := range cases {
-		if got := mapStatusKind(input); got != expected {
-			t.Fatalf("status %s expected %s, got %s", input, expected, got)
+	for _, tc := range cases {
+		if got := mapStatusKind(tc.status, tc.period); got != tc.want {
+			t.Fatalf("status %q period %d expected %s, got %s", tc.status, tc.period, tc.want, got)
 		}
 	}
 }
 
+func TestMapGameTypeRegularSeason(t *testing.T) {
+	if got := mapGame(gameResponse{ID: 1}).Meta.GameType; got != games.GameTypeRegularSeason {
+		t.Fatalf("expected regular season game type, got %q", got)
+	}
+}
+
 func TestFormatSeason(t *testing.T) {
 	if got := formatSeason(2024); got != "2024" {
 		t.Fatalf("expected season to be formatted as string, got %s", got)
@@ -0,0 +1,48 @@
+package balldontlie
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// serverClock remembers how far the upstream's Date header was from our clock.
+type serverClock struct {
+	mu     sync.Mutex
+	offset time.Duration
+	seen   bool
+}
+
+func (s *serverClock) observe(resp *http.Response, now time.Time) {
+	if resp == nil {
+		return
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.offset = date.Sub(now)
+	s.seen = true
+	s.mu.Unlock()
+}
+
+// do sends req with key rotation and records the upstream Date header.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.doWithKeys(req)
+	if err == nil {
+		c.serverClock.observe(resp, c.clock.Now())
+	}
+	return resp, err
+}
+
+// ServerClockOffset reports upstream time minus local time from the most recent
+// response's Date header; ok is false until a response with a Date has been seen.
+func (c *Client) ServerClockOffset() (offset time.Duration, ok bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.serverClock.mu.Lock()
+	defer c.serverClock.mu.Unlock()
+	return c.serverClock.offset, c.serverClock.seen
+}
@@ -0,0 +1,49 @@
+package balldontlie
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func TestServerClockOffsetFromDateHeader(t *testing.T) {
+	local := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	upstream := local.Add(90 * 24 * time.Hour)
+	date := ""
+	rt := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp := okGamesResponse()
+		if date != "" {
+			resp.Header.Set("Date", date)
+		}
+		return resp, nil
+	})
+	client := NewClient(Config{
+		BaseURL:    "http://example.com",
+		HTTPClient: &http.Client{Transport: rt},
+		Clock:      teststubs.NewFakeClock(local),
+	})
+
+	if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if _, ok := client.ServerClockOffset(); ok {
+		t.Fatalf("expected no offset without a Date header")
+	}
+
+	date = upstream.Format(http.TimeFormat)
+	if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	offset, ok := client.ServerClockOffset()
+	if !ok || offset != 90*24*time.Hour {
+		t.Fatalf("expected 90d offset, got %s ok=%v", offset, ok)
+	}
+
+	var nilClient *Client
+	if _, ok := nilClient.ServerClockOffset(); ok {
+		t.Fatalf("expected nil client to report no offset")
+	}
+}
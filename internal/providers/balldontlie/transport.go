@@ -4,17 +4,28 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
 )
 
 type httpDoer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-func resolveHTTPClient(client *http.Client) httpDoer {
-	if client != nil {
-		return client
+// resolveHTTPClient returns a copy of client (or a default one) whose
+// transport logs and records each upstream request and, with a captureDir,
+// saves each successful games page there.
+func resolveHTTPClient(client *http.Client, cfg providers.ObservedTransportConfig, captureDir string) httpDoer {
+	if client == nil {
+		client = providers.NewHTTPClient(providers.TransportConfig{}, defaultHTTPTimeout)
+	}
+	cfg.Provider = providerName
+	observed := *client
+	observed.Transport = providers.NewObservedTransport(client.Transport, cfg)
+	if captureDir != "" {
+		observed.Transport = newCaptureTransport(observed.Transport, captureDir, cfg.Logger)
 	}
-	return &http.Client{Timeout: defaultHTTPTimeout}
+	return &observed
 }
 
 func normalizeBaseURL(raw string) string {
@@ -1,9 +1,17 @@
 package balldontlie
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
 )
 
 func TestNormalizeBaseURLTrimsTrailingSlashAndDefaults(t *testing.T) {
@@ -24,7 +32,7 @@ func TestNormalizeBaseURLTrimsTrailingSlashAndDefaults(t *testing.T) {
 }
 
 func TestResolveHTTPClientDefaultsTimeout(t *testing.T) {
-	client := resolveHTTPClient(nil)
+	client := resolveHTTPClient(nil, providers.ObservedTransportConfig{}, "")
 	httpClient, ok := client.(*http.Client)
 	if !ok {
 		t.Fatalf("expected *http.Client, got %T", client)
@@ -35,11 +43,34 @@ func TestResolveHTTPClientDefaultsTimeout(t *testing.T) {
 }
 
 func TestResolveHTTPClientUsesProvidedClient(t *testing.T) {
-	custom := &http.Client{Timeout: 5 * time.Second}
-	client := resolveHTTPClient(custom)
-	if client != custom {
-		t.Fatalf("expected provided client to be used")
+	base := &recordingTransport{}
+	custom := &http.Client{Timeout: 5 * time.Second, Transport: base}
+	client := resolveHTTPClient(custom, providers.ObservedTransportConfig{}, "")
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		t.Fatalf("expected *http.Client, got %T", client)
+	}
+	if httpClient == custom || custom.Transport != base {
+		t.Fatalf("expected provided client to be copied, not modified")
+	}
+	if httpClient.Timeout != custom.Timeout {
+		t.Fatalf("expected timeout %s, got %s", custom.Timeout, httpClient.Timeout)
 	}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid/games", nil)
+	if _, err := httpClient.Transport.RoundTrip(req); err != nil {
+		t.Fatalf("round trip: %v", err)
+	}
+	if base.calls != 1 {
+		t.Fatalf("expected provided transport to be wrapped, got %d calls", base.calls)
+	}
+}
+
+// recordingTransport answers every request with an empty 200.
+type recordingTransport struct{ calls int }
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
 }
 
 func TestResolveLocationDefaultsAndFallback(t *testing.T) {
@@ -66,3 +97,61 @@ func TestResolveMaxPages(t *testing.T) {
 		t.Fatalf("expected max pages 3, got %d", got)
 	}
 }
+
+// countingListener counts accepted connections.
+type countingListener struct {
+	net.Listener
+	accepted atomic.Int32
+}
+
+func (l *countingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err == nil {
+		l.accepted.Add(1)
+	}
+	return conn, err
+}
+
+func TestConcurrentFetchGamesReusesConnections(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		page := r.URL.Query().Get("page")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[{"id":%s,"home_team":{"id":1},"visitor_team":{"id":2}}],"meta":{"total_pages":3}}`, page)
+	}))
+	counter := &countingListener{Listener: srv.Listener}
+	srv.Listener = counter
+	srv.Start()
+	defer srv.Close()
+
+	const workers = 4
+	client := NewClient(Config{
+		BaseURL:       srv.URL,
+		HTTPClient:    providers.NewHTTPClient(providers.TransportConfig{MaxIdleConnsPerHost: workers}, 5*time.Second),
+		PageCacheSize: -1,
+	})
+	for round := 0; round < 3; round++ {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+					t.Errorf("fetch: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	if got := requests.Load(); got != 3*workers*3 {
+		t.Fatalf("expected %d page requests, got %d", 3*workers*3, got)
+	}
+	// A request can dial while another's connection is on its way back to the
+	// pool, so allow a few extra; a pool of 2 per host (net/http's default)
+	// redials every round and reaches 2*workers.
+	if got := counter.accepted.Load(); got >= 2*workers {
+		t.Fatalf("expected fewer than %d connections across %d requests, got %d", 2*workers, requests.Load(), got)
+	}
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseBytes caps provider response bodies when no limit is set.
+const DefaultMaxResponseBytes = 10 << 20
+
+// LimitResponseBody returns a reader over body that fails with
+// ErrResponseTooLarge once more than max bytes have been read, so a runaway
+// upstream or an HTML error page from a proxy cannot grow the decoder without
+// bound. A non-positive max uses DefaultMaxResponseBytes.
+func LimitResponseBody(body io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		max = DefaultMaxResponseBytes
+	}
+	return &limitedBody{r: body, left: max, max: max}
+}
+
+type limitedBody struct {
+	r    io.Reader
+	left int64 // bytes still allowed; negative once the cap was passed
+	max  int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.left < 0 {
+		return 0, l.tooLarge()
+	}
+	// Read one byte past the cap to tell "exactly max" from "more than max".
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) <= l.left {
+		l.left -= int64(n)
+		return n, err
+	}
+	n = int(l.left)
+	l.left = -1
+	return n, l.tooLarge()
+}
+
+func (l *limitedBody) tooLarge() error {
+	return fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, l.max)
+}
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLimitResponseBody(t *testing.T) {
+	cases := []struct {
+		name    string
+		size    int
+		max     int64
+		wantErr bool
+	}{
+		{"under the cap", 10, 16, false},
+		{"exactly the cap", 16, 16, false},
+		{"over the cap", 17, 16, true},
+		{"default cap", 1024, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := io.ReadAll(LimitResponseBody(strings.NewReader(strings.Repeat("x", tc.size)), tc.max))
+			if tc.wantErr {
+				if !errors.Is(err, ErrResponseTooLarge) {
+					t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+				}
+				if int64(len(got)) != tc.max {
+					t.Fatalf("expected %d bytes before the error, got %d", tc.max, len(got))
+				}
+				return
+			}
+			if err != nil || len(got) != tc.size {
+				t.Fatalf("expected %d bytes, got %d and %v", tc.size, len(got), err)
+			}
+		})
+	}
+}
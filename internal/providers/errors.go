@@ -9,6 +9,15 @@ import (
 // ErrProviderUnavailable is returned when a provider is not configured or reachable.
 var ErrProviderUnavailable = errors.New("provider unavailable")
 
+// ErrResponseTooLarge is returned when a provider response body passes the
+// configured size cap.
+var ErrResponseTooLarge = errors.New("provider response too large")
+
+// ErrRetryAfterExceedsDeadline wraps a RateLimitError whose Retry-After
+// outlasts the caller's context deadline: the retrying provider returns it
+// at once instead of sleeping into a cancellation.
+var ErrRetryAfterExceedsDeadline = errors.New("provider retry-after exceeds deadline")
+
 // RateLimitError captures rate limit responses from upstream providers.
 type RateLimitError struct {
 	Provider   string
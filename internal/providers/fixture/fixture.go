@@ -2,62 +2,134 @@ package fixture
 
 import (
 	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
-	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
-// Provider returns a static set of games useful for local testing and bootstrapping.
+const (
+	minGamesPerDate = 4
+	maxGamesPerDate = 10
+	// Tip-offs fall on the half hour from 16:00 to 23:30 UTC, so every game
+	// is dated the same in UTC and the US zones.
+	firstTipOff  = 16 * time.Hour
+	tipOffSlots  = 16
+	gameDuration = 150 * time.Minute
+)
+
+// Provider serves a deterministic pseudo-schedule for local testing and
+// bootstrapping: the games for a date depend only on the date string, and
+// their status on where that date falls relative to the clock.
 type Provider struct {
-	now func() time.Time
+	clock clock.Clock
 }
 
-// New creates a fixture provider with a time source.
+// New creates a fixture provider backed by the real clock.
 func New() *Provider {
+	return NewWithClock(nil)
+}
+
+// NewWithClock creates a fixture provider whose default date and game
+// statuses come from clk.
+func NewWithClock(clk clock.Clock) *Provider {
 	return &Provider{
-		now: time.Now,
+		clock: clock.OrReal(clk),
 	}
 }
 
-// FetchGames returns a deterministic set of example games.
+// FetchGames returns 4-10 games for date (today in tz when date is empty or
+// malformed) between teams drawn from the fixture league. Dates before today
+// are FINAL, later dates SCHEDULED, and today's games are scheduled, live or
+// final by their tip-off. Scores are the same on every call for a date.
 func (p *Provider) FetchGames(ctx context.Context, date string, tz string) ([]domaingames.Game, error) {
 	_ = ctx
-	_ = tz
 
-	start := p.now().UTC().Truncate(time.Hour)
-	if date != "" {
-		parsed, err := timeutil.ParseDate(date)
-		if err == nil {
-			start = parsed.UTC()
+	now := p.clock.Now()
+	today := timeutil.DateIn(now, timeutil.ResolveLocation(tz))
+	if _, err := timeutil.ParseDate(date); err != nil {
+		date = today
+	}
+	day, _ := timeutil.ParseDate(date)
+
+	seed := fnv.New64a()
+	_, _ = seed.Write([]byte(date))
+	rng := rand.New(rand.NewSource(int64(seed.Sum64())))
+
+	count := minGamesPerDate + rng.Intn(maxGamesPerDate-minGamesPerDate+1)
+	order := rng.Perm(len(teamTable))
+	games := make([]domaingames.Game, 0, count)
+	for i := 0; i < count; i++ {
+		// Draw everything up front so each game's values do not depend on status.
+		start := day.Add(firstTipOff + time.Duration(rng.Intn(tipOffSlots))*30*time.Minute)
+		home, away := 95+rng.Intn(36), 95+rng.Intn(36)
+		if home == away {
+			home++
+		}
+		upstreamID := day.Year()*1000000 + int(day.Month())*10000 + day.Day()*100 + i + 1
+		g := domaingames.Game{
+			ID:        fmt.Sprintf("fixture-%d", upstreamID),
+			Provider:  "fixture",
+			HomeTeam:  teamTable[order[2*i]],
+			AwayTeam:  teamTable[order[2*i+1]],
+			StartTime: start.Format(time.RFC3339),
+			Meta:      domaingames.GameMeta{Season: season(day), UpstreamGameID: upstreamID, GameType: domaingames.GameTypeRegularSeason},
+		}
+		if postseason(day) {
+			g.Meta.Postseason = true
+			g.Meta.GameType = domaingames.GameTypePostseason
+		}
+		final := domaingames.Score{Home: home, Away: away}
+		switch {
+		case date < today, date == today && !now.Before(start.Add(gameDuration)):
+			setFinal(&g, final)
+		case date == today && !now.Before(start):
+			setLive(&g, final, now.Sub(start))
+		default:
+			g.Status = "Scheduled"
+			g.StatusKind = domaingames.StatusScheduled
 		}
+		games = append(games, g)
 	}
+	sort.SliceStable(games, func(i, j int) bool { return games[i].StartTime < games[j].StartTime })
+	return games, nil
+}
 
-	games := []domaingames.Game{
-		{
-			ID:         "fixture-1",
-			Provider:   "fixture",
-			HomeTeam:   teams.Team{ID: "bos", Name: "Celtics", Abbreviation: "BOS", City: "Boston", Conference: "East", Division: "Atlantic"},
-			AwayTeam:   teams.Team{ID: "lal", Name: "Lakers", Abbreviation: "LAL", City: "Los Angeles", Conference: "West", Division: "Pacific"},
-			StartTime:  start.Add(2 * time.Hour).Format(time.RFC3339),
-			Status:     "Scheduled",
-			StatusKind: domaingames.StatusScheduled,
-			Score:      domaingames.Score{Home: 0, Away: 0},
-			Meta:       domaingames.GameMeta{Season: "2023-2024", UpstreamGameID: 1001},
-		},
-		{
-			ID:         "fixture-2",
-			Provider:   "fixture",
-			HomeTeam:   teams.Team{ID: "gsw", Name: "Warriors", Abbreviation: "GSW", City: "San Francisco", Conference: "West", Division: "Pacific"},
-			AwayTeam:   teams.Team{ID: "mia", Name: "Heat", Abbreviation: "MIA", City: "Miami", Conference: "East", Division: "Southeast"},
-			StartTime:  start.Add(4 * time.Hour).Format(time.RFC3339),
-			Status:     "Scheduled",
-			StatusKind: domaingames.StatusScheduled,
-			Score:      domaingames.Score{Home: 0, Away: 0},
-			Meta:       domaingames.GameMeta{Season: "2023-2024", UpstreamGameID: 1002},
-		},
+func setFinal(g *domaingames.Game, score domaingames.Score) {
+	g.Status = "Final"
+	g.StatusKind = domaingames.StatusFinal
+	g.Score = score
+	g.Meta.Period = 4
+}
+
+// setLive scores a game elapsed into its gameDuration, scaling toward final.
+func setLive(g *domaingames.Game, final domaingames.Score, elapsed time.Duration) {
+	period := 1 + int(elapsed*4/gameDuration)
+	g.Status = []string{"1st Qtr", "2nd Qtr", "3rd Qtr", "4th Qtr"}[period-1]
+	g.StatusKind = domaingames.StatusInProgress
+	g.Score = domaingames.Score{
+		Home: int(time.Duration(final.Home) * elapsed / gameDuration),
+		Away: int(time.Duration(final.Away) * elapsed / gameDuration),
 	}
+	g.Meta.Period = period
+}
 
-	return games, nil
+// season names the NBA season a date falls in; seasons start in October.
+func season(day time.Time) string {
+	year := day.Year()
+	if day.Month() < time.October {
+		year--
+	}
+	return fmt.Sprintf("%d-%d", year, year+1)
+}
+
+// postseason reports whether day falls in the playoffs, mid-April to June.
+func postseason(day time.Time) bool {
+	m, d := day.Month(), day.Day()
+	return (m == time.April && d >= 20) || m == time.May || m == time.June
 }
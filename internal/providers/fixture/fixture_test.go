@@ -2,49 +2,170 @@ package fixture
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
-)
 
-func TestFetchGamesReturnsDeterministicGames(t *testing.T) {
-	fixed := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
-	p := New()
-	p.now = func() time.Time { return fixed }
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
 
-	games, err := p.FetchGames(context.Background(), "", "")
+func fetch(t *testing.T, p *Provider, date string) []domaingames.Game {
+	t.Helper()
+	games, err := p.FetchGames(context.Background(), date, "")
 	if err != nil {
-		t.Fatalf("expected no error, got %v", err)
+		t.Fatalf("fetch %s: %v", date, err)
+	}
+	return games
+}
+
+func TestFetchGamesIsDeterministicPerDate(t *testing.T) {
+	p := NewWithClock(teststubs.NewFakeClock(time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)))
+	first := fetch(t, p, "2024-01-15")
+	if again := fetch(t, p, "2024-01-15"); !reflect.DeepEqual(first, again) {
+		t.Fatalf("expected the same games for the same date")
+	}
+	if len(first) < minGamesPerDate || len(first) > maxGamesPerDate {
+		t.Fatalf("expected %d-%d games, got %d", minGamesPerDate, maxGamesPerDate, len(first))
+	}
+	if other := fetch(t, p, "2024-01-16"); reflect.DeepEqual(first, other) {
+		t.Fatalf("expected different dates to get different games")
 	}
 
-	if len(games) != 2 {
-		t.Fatalf("expected 2 games, got %d", len(games))
+	league := map[string]bool{}
+	for _, team := range Teams() {
+		league[team.ID] = true
+	}
+	playing := map[string]bool{}
+	ids := map[string]bool{}
+	for i, g := range first {
+		for _, team := range []string{g.HomeTeam.ID, g.AwayTeam.ID} {
+			if !league[team] || playing[team] {
+				t.Fatalf("game %d: team %q is unknown or already playing", i, team)
+			}
+			playing[team] = true
+		}
+		if ids[g.ID] || !strings.HasPrefix(g.StartTime, "2024-01-15T") {
+			t.Fatalf("game %d: duplicate id or start off the date: %+v", i, g)
+		}
+		ids[g.ID] = true
+		if i > 0 && g.StartTime < first[i-1].StartTime {
+			t.Fatalf("expected games ordered by start time")
+		}
 	}
+}
+
+func TestFetchGamesStatusFollowsDate(t *testing.T) {
+	now := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+	p := NewWithClock(teststubs.NewFakeClock(now))
 
-	first := games[0]
-	if first.ID != "fixture-1" || first.Provider != "fixture" {
-		t.Fatalf("unexpected first game: %+v", first)
+	for _, g := range fetch(t, p, "2024-01-14") {
+		if g.StatusKind != domaingames.StatusFinal || g.Score.Home == g.Score.Away || g.Score.Home < 95 {
+			t.Fatalf("expected past games final with a winner, got %+v", g)
+		}
 	}
-	if first.StartTime != fixed.Truncate(time.Hour).Add(2*time.Hour).Format(time.RFC3339) {
-		t.Fatalf("unexpected start time %s", first.StartTime)
+	for _, g := range fetch(t, p, "2024-01-16") {
+		if g.StatusKind != domaingames.StatusScheduled || g.Score != (domaingames.Score{}) {
+			t.Fatalf("expected future games scheduled and scoreless, got %+v", g)
+		}
 	}
-	if first.Meta.UpstreamGameID != 1001 {
-		t.Fatalf("unexpected upstream id %d", first.Meta.UpstreamGameID)
+	for _, g := range fetch(t, p, "2024-01-15") {
+		start, _ := time.Parse(time.RFC3339, g.StartTime)
+		var want domaingames.GameStatusKind
+		switch {
+		case now.Before(start):
+			want = domaingames.StatusScheduled
+		case now.Before(start.Add(gameDuration)):
+			want = domaingames.StatusInProgress
+		default:
+			want = domaingames.StatusFinal
+		}
+		if g.StatusKind != want {
+			t.Fatalf("game at %s: expected %s at %s, got %s", g.StartTime, want, now.Format(time.RFC3339), g.StatusKind)
+		}
+		if want == domaingames.StatusInProgress && (g.Meta.Period < 1 || g.Meta.Period > 4) {
+			t.Fatalf("expected a live period, got %+v", g)
+		}
 	}
 }
 
-func TestNewCreatesProvider(t *testing.T) {
-	p := New()
-	if p == nil || p.now == nil {
-		t.Fatalf("expected provider with now set")
+func TestFetchGamesLiveScoresLeadToFinal(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC))
+	final := fetch(t, NewWithClock(clk), "2024-01-15")[0]
+
+	start, _ := time.Parse(time.RFC3339, final.StartTime)
+	clk.Advance(start.Add(gameDuration / 2).Sub(clk.Now()))
+	live := fetch(t, NewWithClock(clk), "2024-01-15")[0]
+	if live.ID != final.ID || live.StatusKind != domaingames.StatusInProgress || live.Status != "3rd Qtr" {
+		t.Fatalf("expected the first game live at half time, got %+v", live)
+	}
+	if live.Score.Home > final.Score.Home || live.Score.Away > final.Score.Away || live.Score.Home == 0 {
+		t.Fatalf("expected a partial score toward %+v, got %+v", final.Score, live.Score)
 	}
+}
 
-	fixed := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
-	p.now = func() time.Time { return fixed }
-	games, err := p.FetchGames(context.Background(), "2024-02-10", "")
-	if err != nil {
-		t.Fatalf("expected no error, got %v", err)
+func TestFetchGamesDefaultsToToday(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC))
+	p := NewWithClock(clk)
+	if got := fetch(t, p, ""); !strings.HasPrefix(got[0].StartTime, "2024-01-16") {
+		t.Fatalf("expected the UTC date by default, got %s", got[0].StartTime)
+	}
+	games, err := p.FetchGames(context.Background(), "not-a-date", "America/New_York")
+	if err != nil || !strings.HasPrefix(games[0].StartTime, "2024-01-15") {
+		t.Fatalf("expected today in the requested zone, got %v %v", games, err)
+	}
+}
+
+func TestFetchGamesSeasonMeta(t *testing.T) {
+	p := NewWithClock(teststubs.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	cases := []struct {
+		date       string
+		season     string
+		postseason bool
+	}{
+		{"2023-10-24", "2023-2024", false},
+		{"2024-01-15", "2023-2024", false},
+		{"2024-05-10", "2023-2024", true},
+	}
+	for _, tc := range cases {
+		g := fetch(t, p, tc.date)[0]
+		if g.Meta.Season != tc.season || g.Meta.Postseason != tc.postseason {
+			t.Fatalf("%s: expected season %s postseason=%v, got %+v", tc.date, tc.season, tc.postseason, g.Meta)
+		}
+		wantType := domaingames.GameTypeRegularSeason
+		if tc.postseason {
+			wantType = domaingames.GameTypePostseason
+		}
+		if g.Meta.GameType != wantType {
+			t.Fatalf("%s: expected game type %s, got %s", tc.date, wantType, g.Meta.GameType)
+		}
+	}
+}
+
+func TestFetchTeamsMatchesSchedule(t *testing.T) {
+	p := New()
+	if p == nil || p.clock == nil {
+		t.Fatalf("expected provider with clock set")
+	}
+	league, err := p.FetchTeams(context.Background())
+	if err != nil || len(league) != 30 {
+		t.Fatalf("expected 30 teams, got %d %v", len(league), err)
+	}
+	byID := map[string]bool{}
+	for _, team := range league {
+		if team.Abbreviation != strings.ToUpper(team.ID) || team.FullName != team.City+" "+team.Name {
+			t.Fatalf("inconsistent team %+v", team)
+		}
+		byID[team.ID] = true
+	}
+	for _, g := range fetch(t, p, "2024-02-10") {
+		if !byID[g.HomeTeam.ID] || !byID[g.AwayTeam.ID] {
+			t.Fatalf("expected scheduled teams from FetchTeams, got %+v", g)
+		}
 	}
-	if games[0].StartTime[:10] != "2024-02-10" {
-		t.Fatalf("expected date override, got %s", games[0].StartTime)
+	league[0].Name = "changed"
+	if Teams()[0].Name == "changed" {
+		t.Fatalf("expected Teams to return a copy")
 	}
 }
@@ -2,20 +2,40 @@ package providers
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"log/slog"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
 )
 
 // rateLimitedProvider wraps a GameProvider and enforces a minimum interval between calls.
 type rateLimitedProvider struct {
-	next     GameProvider
-	interval time.Duration
-	ticker   *time.Ticker
-	logger   *slog.Logger
-	name     string
+	next   GameProvider
+	clock  clock.Clock
+	logger *slog.Logger
+	name   string
+
+	mu       sync.Mutex
+	interval time.Duration // zero is passthrough
+	quota    *QuotaTracker // stretches interval while upstream quota is low; may be nil
+	nextSlot time.Time     // earliest start for the next call
+}
+
+// RateLimitConfig tunes NewRateLimitedProviderWithConfig.
+type RateLimitConfig struct {
+	// Interval is the minimum spacing between calls; zero disables limiting
+	// (passthrough, for tests).
+	Interval time.Duration
+	// AllowFirstImmediate lets the first call through without waiting; later
+	// calls are spaced Interval from it.
+	AllowFirstImmediate bool
+	// Quota, when set, stretches Interval while the upstream reports few
+	// requests left in its rate limit window.
+	Quota *QuotaTracker
+	Clock clock.Clock // defaults to the real clock
 }
 
 // NewRateLimitedProvider returns a GameProvider that limits calls to the given interval.
@@ -24,13 +44,24 @@ func NewRateLimitedProvider(next GameProvider, interval time.Duration, logger *s
 	if interval <= 0 {
 		interval = time.Minute
 	}
-	return &rateLimitedProvider{
+	return NewRateLimitedProviderWithConfig(next, RateLimitConfig{Interval: interval}, logger)
+}
+
+// NewRateLimitedProviderWithConfig is identical to NewRateLimitedProvider but
+// takes its pacing from cfg.
+func NewRateLimitedProviderWithConfig(next GameProvider, cfg RateLimitConfig, logger *slog.Logger) GameProvider {
+	p := &rateLimitedProvider{
 		next:     next,
-		interval: interval,
-		ticker:   time.NewTicker(interval),
+		clock:    clock.OrReal(cfg.Clock),
+		interval: cfg.Interval,
+		quota:    cfg.Quota,
 		logger:   logger,
 		name:     "rate-limited",
 	}
+	if cfg.Interval > 0 && !cfg.AllowFirstImmediate {
+		p.nextSlot = p.clock.Now().Add(cfg.Interval)
+	}
+	return p
 }
 
 func (p *rateLimitedProvider) FetchGames(ctx context.Context, date string, tz string) ([]games.Game, error) {
@@ -38,11 +69,9 @@ func (p *rateLimitedProvider) FetchGames(ctx context.Context, date string, tz st
 		logWithProvider(ctx, p.logger, slog.LevelWarn, p.name, "provider unavailable")
 		return nil, ErrProviderUnavailable
 	}
-	select {
-	case <-ctx.Done():
+	if err := p.wait(ctx); err != nil {
 		logWithProvider(ctx, p.logger, slog.LevelWarn, p.name, "rate-limited fetch canceled")
-		return nil, ctx.Err()
-	case <-p.ticker.C:
+		return nil, err
 	}
 	logWithProvider(ctx, p.logger, slog.LevelInfo, p.name, "rate-limited provider fetch",
 		slog.String("date", date),
@@ -51,9 +80,68 @@ func (p *rateLimitedProvider) FetchGames(ctx context.Context, date string, tz st
 	return p.next.FetchGames(ctx, date, tz)
 }
 
-// Close stops the internal ticker; callers should invoke when discarding the provider to avoid leaks.
-func (p *rateLimitedProvider) Close() {
-	if p != nil && p.ticker != nil {
-		p.ticker.Stop()
+// wait blocks until the call may proceed. Each call reserves the next free
+// slot, so concurrent callers are spaced an interval apart in arrival order;
+// a canceled caller hands its slot back if no one has queued behind it.
+func (p *rateLimitedProvider) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	if p.interval <= 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	now := p.clock.Now()
+	slot := p.nextSlot
+	if slot.Before(now) {
+		slot = now
+	}
+	p.nextSlot = slot.Add(p.quota.Interval(p.interval))
+	reserved := p.nextSlot
+	p.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
 	}
+	timer := p.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		p.mu.Lock()
+		if p.nextSlot.Equal(reserved) {
+			p.nextSlot = slot
+		}
+		p.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
+
+// RateLimitSetter is implemented by providers whose call spacing can change
+// while running.
+type RateLimitSetter interface {
+	SetInterval(time.Duration)
+}
+
+// SetInterval changes the spacing between calls; non-positive values are
+// ignored. It is safe to call while fetches are waiting: the next free slot
+// moves by the difference, and callers already holding a slot keep it.
+func (p *rateLimitedProvider) SetInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.nextSlot.IsZero() && p.interval > 0 {
+		p.nextSlot = p.nextSlot.Add(d - p.interval)
+	}
+	p.interval = d
+}
+
+// Unwrap returns the wrapped provider.
+func (p *rateLimitedProvider) Unwrap() GameProvider {
+	return p.next
 }
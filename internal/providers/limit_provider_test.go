@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 
 func TestRateLimitedProviderBlocksUntilTick(t *testing.T) {
 	inner := &teststubs.StubProvider{}
+	// Without AllowFirstImmediate even the first call waits a full interval.
 	rl := NewRateLimitedProvider(inner, 5*time.Millisecond, nil).(*rateLimitedProvider)
 
 	start := time.Now()
@@ -26,6 +28,102 @@ func TestRateLimitedProviderBlocksUntilTick(t *testing.T) {
 	}
 }
 
+func TestRateLimitedProviderWaitsOnInjectedClock(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	inner := &teststubs.StubProvider{}
+	rl := NewRateLimitedProviderWithConfig(inner, RateLimitConfig{Interval: time.Minute, AllowFirstImmediate: true, Clock: clk}, nil)
+
+	if _, err := rl.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected the first call through, got %v", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := rl.FetchGames(context.Background(), "2024-01-01", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the second call to wait on the fake clock")
+	}
+	if inner.Calls.Load() != 1 {
+		t.Fatalf("expected the second call held back, got %d calls", inner.Calls.Load())
+	}
+	clk.Advance(time.Minute)
+	if err := <-done; err != nil || inner.Calls.Load() != 2 {
+		t.Fatalf("expected the second call after the interval, got %v and %d calls", err, inner.Calls.Load())
+	}
+}
+
+func TestRateLimitedProviderCanceledWaiterReturnsSlot(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	rl := NewRateLimitedProviderWithConfig(&teststubs.StubProvider{}, RateLimitConfig{Interval: time.Minute, AllowFirstImmediate: true, Clock: clk}, nil).(*rateLimitedProvider)
+	if _, err := rl.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected the first call through, got %v", err)
+	}
+	want := rl.nextSlot
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := rl.FetchGames(ctx, "2024-01-01", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the call to wait")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled, got %v", err)
+	}
+	if !rl.nextSlot.Equal(want) {
+		t.Fatalf("expected the canceled slot handed back, next slot %s want %s", rl.nextSlot, want)
+	}
+}
+
+func TestRateLimitedProviderSetIntervalMovesTheNextSlot(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	inner := &teststubs.StubProvider{}
+	rl := NewRateLimitedProviderWithConfig(inner, RateLimitConfig{Interval: time.Hour, AllowFirstImmediate: true, Clock: clk}, nil)
+	if _, err := rl.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+		t.Fatalf("expected the first call through, got %v", err)
+	}
+
+	setter, ok := rl.(RateLimitSetter)
+	if !ok {
+		t.Fatal("expected the limiter to implement RateLimitSetter")
+	}
+	setter.SetInterval(time.Minute)
+	setter.SetInterval(0) // ignored
+	done := make(chan error, 1)
+	go func() {
+		_, err := rl.FetchGames(context.Background(), "2024-01-01", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the second call to wait")
+	}
+	clk.Advance(time.Minute)
+	if err := <-done; err != nil || inner.Calls.Load() != 2 {
+		t.Fatalf("expected the second call a minute after the first, got %v and %d calls", err, inner.Calls.Load())
+	}
+}
+
+func TestRateLimitedProviderSetIntervalIsSafeDuringFetches(t *testing.T) {
+	rl := NewRateLimitedProviderWithConfig(&teststubs.StubProvider{}, RateLimitConfig{Interval: time.Microsecond, AllowFirstImmediate: true}, nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = rl.FetchGames(context.Background(), "2024-01-01", "")
+		}()
+		go func(d time.Duration) {
+			defer wg.Done()
+			rl.(RateLimitSetter).SetInterval(d)
+		}(time.Duration(i+1) * time.Microsecond)
+	}
+	wg.Wait()
+}
+
 func TestRateLimitedProviderRespectsCanceledContext(t *testing.T) {
 	inner := &teststubs.StubProvider{}
 	rl := NewRateLimitedProvider(inner, time.Minute, nil)
@@ -51,15 +149,68 @@ func TestRateLimitedProviderHandlesNilInner(t *testing.T) {
 	}
 }
 
-func TestRateLimitedProviderCloseStopsTicker(t *testing.T) {
-	rl := NewRateLimitedProvider(&teststubs.StubProvider{}, time.Millisecond, nil).(*rateLimitedProvider)
-	rl.Close() // ensure no panic and ticker stopped
-}
-
 func TestRateLimitedProviderDefaultsInterval(t *testing.T) {
 	rl := NewRateLimitedProvider(&teststubs.StubProvider{}, 0, nil).(*rateLimitedProvider)
 	if rl.interval != time.Minute {
 		t.Fatalf("expected default interval 1m, got %s", rl.interval)
 	}
-	rl.Close()
+}
+
+func TestRateLimitedProviderAllowsImmediateFirstCall(t *testing.T) {
+	inner := &teststubs.StubProvider{}
+	rl := NewRateLimitedProviderWithConfig(inner, RateLimitConfig{Interval: time.Hour, AllowFirstImmediate: true}, nil).(*rateLimitedProvider)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rl.FetchGames(context.Background(), "2024-01-01", "")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected first call to skip the interval")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := rl.FetchGames(ctx, "2024-01-01", ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected second call to wait for the interval, got %v", err)
+	}
+	if inner.Calls.Load() != 1 {
+		t.Fatalf("expected one inner call, got %d", inner.Calls.Load())
+	}
+}
+
+func TestRateLimitedProviderImmediateFirstKeepsSpacing(t *testing.T) {
+	inner := &teststubs.StubProvider{}
+	interval := 30 * time.Millisecond
+	rl := NewRateLimitedProviderWithConfig(inner, RateLimitConfig{Interval: interval, AllowFirstImmediate: true}, nil).(*rateLimitedProvider)
+
+	// Let a tick queue up before the first call; it must not let the second call skip ahead.
+	time.Sleep(2 * interval)
+	start := time.Now()
+	for range 2 {
+		if _, err := rl.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Fatalf("expected second call spaced by the interval, elapsed %s", elapsed)
+	}
+}
+
+func TestRateLimitedProviderZeroIntervalPassthrough(t *testing.T) {
+	inner := &teststubs.StubProvider{}
+	rl := NewRateLimitedProviderWithConfig(inner, RateLimitConfig{}, nil).(*rateLimitedProvider)
+	for range 3 {
+		if _, err := rl.FetchGames(context.Background(), "2024-01-01", ""); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if inner.Calls.Load() != 3 {
+		t.Fatalf("expected passthrough, calls=%d", inner.Calls.Load())
+	}
 }
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+// HeaderRateLimitRemaining is the upstream header reporting requests left in
+// the current rate limit window.
+const HeaderRateLimitRemaining = "X-Rate-Limit-Remaining"
+
+// observedTransport logs and records every request a provider sends upstream,
+// so quota headers on successful responses are seen before they run out.
+type observedTransport struct {
+	next     http.RoundTripper
+	provider string
+	logger   *slog.Logger
+	metrics  *metrics.Recorder
+	quota    *QuotaTracker
+	clock    clock.Clock
+}
+
+// ObservedTransportConfig tunes NewObservedTransport.
+type ObservedTransportConfig struct {
+	Provider string // name requests are logged and recorded under
+	Logger   *slog.Logger
+	Metrics  *metrics.Recorder
+	Quota    *QuotaTracker // fed each rate limit remaining header; may be nil
+	Clock    clock.Clock   // defaults to the real clock
+}
+
+// NewObservedTransport wraps next (http.DefaultTransport when nil). Each
+// request is recorded under cfg.Provider with its method, path, status and
+// latency, and the rate limit remaining header feeds a gauge and cfg.Quota.
+// Successful and not-modified responses log at Debug, anything else at Warn.
+// Query strings and headers are never logged.
+func NewObservedTransport(next http.RoundTripper, cfg ObservedTransportConfig) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &observedTransport{
+		next:     next,
+		provider: cfg.Provider,
+		logger:   cfg.Logger,
+		metrics:  cfg.Metrics,
+		quota:    cfg.Quota,
+		clock:    clock.OrReal(cfg.Clock),
+	}
+}
+
+func (t *observedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := t.clock.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := t.clock.Now().Sub(start)
+	attrs := []any{
+		slog.String(logging.FieldProvider, t.provider),
+		slog.String(logging.FieldMethod, req.Method),
+		slog.String(logging.FieldPath, req.URL.Path),
+		slog.Int64(logging.FieldDurationMS, elapsed.Milliseconds()),
+	}
+	if err != nil {
+		t.metrics.RecordProviderRequest(t.provider, req.Method, req.URL.Path, 0, elapsed)
+		logging.Warn(t.logger, "provider request failed", append(attrs, slog.Any("error", err))...)
+		return nil, err
+	}
+
+	t.metrics.RecordProviderRequest(t.provider, req.Method, req.URL.Path, resp.StatusCode, elapsed)
+	attrs = append(attrs, slog.Int(logging.FieldStatusCode, resp.StatusCode))
+	if remaining, ok := parseRemaining(resp.Header.Get(HeaderRateLimitRemaining)); ok {
+		t.metrics.SetRateLimitRemaining(t.provider, remaining)
+		t.quota.Observe(remaining)
+		attrs = append(attrs, slog.Int("rate_limit_remaining", remaining))
+	}
+	if resp.StatusCode < 300 || resp.StatusCode == http.StatusNotModified {
+		if t.logger != nil {
+			t.logger.Debug("provider request", attrs...)
+		}
+	} else {
+		logging.Warn(t.logger, "provider request", attrs...)
+	}
+	return resp, nil
+}
+
+func parseRemaining(raw string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
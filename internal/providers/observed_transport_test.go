@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+func debugLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+func TestObservedTransportLogsAndRecordsQuota(t *testing.T) {
+	cases := []struct {
+		status int
+		level  string
+	}{
+		{http.StatusOK, "level=DEBUG"},
+		{http.StatusNotModified, "level=DEBUG"},
+		{http.StatusTooManyRequests, "level=WARN"},
+		{http.StatusInternalServerError, "level=WARN"},
+	}
+	for _, tc := range cases {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(HeaderRateLimitRemaining, "42")
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			logger, buf := debugLogger()
+			rec := metrics.NewRecorder()
+			client := &http.Client{Transport: NewObservedTransport(nil, ObservedTransportConfig{Provider: "balldontlie", Logger: logger, Metrics: rec})}
+			resp, err := client.Get(srv.URL + "/v1/games?cursor=secret")
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			_ = resp.Body.Close()
+
+			out := buf.String()
+			for _, want := range []string{tc.level, "provider=balldontlie", "method=GET", "path=/v1/games", "rate_limit_remaining=42"} {
+				if !strings.Contains(out, want) {
+					t.Fatalf("expected %q in log, got %s", want, out)
+				}
+			}
+			if strings.Contains(out, "secret") {
+				t.Fatalf("expected query string not to be logged, got %s", out)
+			}
+			if n, ok := rec.RateLimitRemaining("balldontlie"); !ok || n != 42 {
+				t.Fatalf("expected remaining 42, got %d %v", n, ok)
+			}
+		})
+	}
+}
+
+func TestObservedTransportSkipsMissingQuota(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRateLimitRemaining, "lots")
+	}))
+	defer srv.Close()
+
+	rec := metrics.NewRecorder()
+	client := &http.Client{Transport: NewObservedTransport(nil, ObservedTransportConfig{Provider: "balldontlie", Metrics: rec})}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	_ = resp.Body.Close()
+	if _, ok := rec.RateLimitRemaining("balldontlie"); ok {
+		t.Fatalf("expected an unparseable header to be ignored")
+	}
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestObservedTransportWarnsOnTransportError(t *testing.T) {
+	logger, buf := debugLogger()
+	transport := NewObservedTransport(failingTransport{}, ObservedTransportConfig{Provider: "balldontlie", Logger: logger, Metrics: metrics.NewRecorder()})
+	req, _ := http.NewRequest(http.MethodGet, "http://upstream.invalid/v1/games", nil)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatalf("expected the transport error to be returned")
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "provider request failed") {
+		t.Fatalf("expected a warning for the failed request, got %s", out)
+	}
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"context"
+	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 )
@@ -12,3 +13,35 @@ import (
 type GameProvider interface {
 	FetchGames(ctx context.Context, date string, tz string) ([]domaingames.Game, error)
 }
+
+// KeySlotReporter is implemented by providers that rotate between configured API keys.
+type KeySlotReporter interface {
+	ActiveKeySlot() string
+}
+
+// ClockOffsetReporter is implemented by providers that observe the upstream's clock
+// (e.g. via the HTTP Date header). The offset is upstream time minus local time.
+type ClockOffsetReporter interface {
+	ServerClockOffset() (offset time.Duration, ok bool)
+}
+
+// Unwrapper is implemented by decorators that wrap another GameProvider.
+type Unwrapper interface {
+	Unwrap() GameProvider
+}
+
+// As walks the decorator chain starting at p and returns the first provider implementing T.
+func As[T any](p GameProvider) (T, bool) {
+	for p != nil {
+		if target, ok := p.(T); ok {
+			return target, true
+		}
+		u, ok := p.(Unwrapper)
+		if !ok {
+			break
+		}
+		p = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
@@ -3,6 +3,7 @@ package providers
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
 )
@@ -19,3 +20,39 @@ func (t *testProvider) FetchGames(ctx context.Context, date string, tz string) (
 func TestGameProviderInterfaceImplemented(t *testing.T) {
 	var _ GameProvider = (*testProvider)(nil)
 }
+
+type wrappingProvider struct {
+	next GameProvider
+}
+
+func (w *wrappingProvider) FetchGames(ctx context.Context, date string, tz string) ([]games.Game, error) {
+	return w.next.FetchGames(ctx, date, tz)
+}
+
+func (w *wrappingProvider) Unwrap() GameProvider { return w.next }
+
+func TestAsWalksDecoratorChain(t *testing.T) {
+	inner := &testProvider{}
+	chain := &wrappingProvider{next: &wrappingProvider{next: inner}}
+
+	got, ok := As[*testProvider](chain)
+	if !ok || got != inner {
+		t.Fatalf("expected to find inner provider, got %v ok=%v", got, ok)
+	}
+	if _, ok := As[KeySlotReporter](chain); ok {
+		t.Fatalf("expected no key slot reporter in chain")
+	}
+	if _, ok := As[*testProvider](nil); ok {
+		t.Fatalf("expected nil provider to yield no match")
+	}
+}
+
+func TestRetryAndRateLimitWrappersUnwrap(t *testing.T) {
+	inner := &testProvider{}
+	limited := NewRateLimitedProvider(inner, time.Minute, nil)
+	retrying := NewRetryingProvider(limited, nil, nil, "test", 1, time.Millisecond)
+
+	if got, ok := As[*testProvider](retrying); !ok || got != inner {
+		t.Fatalf("expected to unwrap through retry and rate-limit decorators")
+	}
+}
@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+)
+
+const (
+	DefaultQuotaThreshold = 5
+	DefaultQuotaWindow    = time.Minute
+	// quotaStretch multiplies the call interval while the quota is low.
+	quotaStretch = 2
+)
+
+// QuotaConfig tunes NewQuotaTracker. Zero values use the defaults.
+type QuotaConfig struct {
+	// Threshold is the remaining-request count below which calls are
+	// stretched; negative disables stretching.
+	Threshold int
+	// Window is the upstream's rate limit window. A window is assumed to
+	// reset one Window after its first observation, or sooner when the
+	// remaining count goes back up.
+	Window   time.Duration
+	Provider string // name the estimate is recorded under
+	Metrics  *metrics.Recorder
+	Logger   *slog.Logger
+	Clock    clock.Clock // defaults to the real clock
+}
+
+// QuotaTracker forecasts the upstream requests left in the current rate limit
+// window from the remaining header on each response. While the estimate is
+// below the threshold, the rate limiter sharing the tracker spaces calls
+// further apart instead of running on into 429s.
+type QuotaTracker struct {
+	threshold int
+	window    time.Duration
+	provider  string
+	metrics   *metrics.Recorder
+	logger    *slog.Logger
+	clock     clock.Clock
+
+	mu          sync.Mutex
+	remaining   int
+	known       bool      // remaining was seen in the current window
+	windowStart time.Time // first observation of the current window
+	base        time.Duration
+	throttled   bool
+}
+
+// NewQuotaTracker builds a tracker to share between a provider's transport
+// (Observe) and its rate limiter (Interval).
+func NewQuotaTracker(cfg QuotaConfig) *QuotaTracker {
+	if cfg.Threshold == 0 {
+		cfg.Threshold = DefaultQuotaThreshold
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultQuotaWindow
+	}
+	return &QuotaTracker{
+		threshold: cfg.Threshold,
+		window:    cfg.Window,
+		provider:  cfg.Provider,
+		metrics:   cfg.Metrics,
+		logger:    cfg.Logger,
+		clock:     clock.OrReal(cfg.Clock),
+	}
+}
+
+// Observe records a remaining header value. A nil tracker does nothing.
+func (q *QuotaTracker) Observe(remaining int) {
+	if q == nil {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock.Now()
+	q.expireLocked(now)
+	if !q.known || remaining > q.remaining {
+		q.windowStart = now
+	}
+	q.remaining = remaining
+	q.known = true
+	q.updateLocked(now)
+}
+
+// Interval returns the spacing to use before the next call given the
+// configured base: base while the quota is healthy or unknown, otherwise
+// stretched to at least twice base and to spreading the remaining requests
+// over what is left of the window. A nil tracker returns base.
+func (q *QuotaTracker) Interval(base time.Duration) time.Duration {
+	if q == nil {
+		return base
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	now := q.clock.Now()
+	q.base = base
+	q.expireLocked(now)
+	return q.updateLocked(now)
+}
+
+// expireLocked forgets an estimate whose window has passed.
+func (q *QuotaTracker) expireLocked(now time.Time) {
+	if q.known && now.Sub(q.windowStart) >= q.window {
+		q.known = false
+	}
+}
+
+// updateLocked computes the interval in effect, logging and recording any
+// change between throttled and not.
+func (q *QuotaTracker) updateLocked(now time.Time) time.Duration {
+	interval := q.base
+	throttled := q.threshold > 0 && q.known && q.remaining < q.threshold
+	if throttled {
+		interval = q.base * quotaStretch
+		if spread := q.windowStart.Add(q.window).Sub(now) / time.Duration(q.remaining+1); spread > interval {
+			interval = spread
+		}
+	}
+	if throttled != q.throttled {
+		if throttled {
+			logging.Warn(q.logger, "provider quota low, stretching interval",
+				slog.String(logging.FieldProvider, q.provider),
+				slog.Int("rate_limit_remaining", q.remaining),
+				slog.Int("threshold", q.threshold),
+				slog.Duration("interval", interval),
+			)
+		} else {
+			logging.Info(q.logger, "provider quota recovered", slog.String(logging.FieldProvider, q.provider))
+		}
+		q.throttled = throttled
+	}
+	estimate := metrics.QuotaEstimate{Known: q.known, Throttled: throttled, Interval: interval}
+	if q.known {
+		estimate.Remaining = q.remaining
+		estimate.ResetsAt = q.windowStart.Add(q.window)
+	}
+	q.metrics.SetQuotaEstimate(q.provider, estimate)
+	return interval
+}
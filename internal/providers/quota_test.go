@@ -0,0 +1,156 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func TestQuotaTrackerStretchesAndRecoversAfterWindow(t *testing.T) {
+	start := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	clk := teststubs.NewFakeClock(start)
+	logger, buf := debugLogger()
+	rec := metrics.NewRecorder()
+	q := NewQuotaTracker(QuotaConfig{Threshold: 5, Window: time.Minute, Provider: "balldontlie", Metrics: rec, Logger: logger, Clock: clk})
+	base := 10 * time.Second
+
+	steps := []struct {
+		advance   time.Duration
+		remaining int // -1 skips the observation
+		want      time.Duration
+	}{
+		{0, 20, base},                           // healthy
+		{10 * time.Second, 4, 2 * base},         // low: 50s left over 5 calls is below the 2x stretch
+		{10 * time.Second, 0, 40 * time.Second}, // spent: wait out the 40s left in the window
+		{40 * time.Second, -1, base},            // window passed, estimate forgotten
+	}
+	for i, step := range steps {
+		clk.Advance(step.advance)
+		if step.remaining >= 0 {
+			q.Observe(step.remaining)
+		}
+		if got := q.Interval(base); got != step.want {
+			t.Fatalf("step %d: expected interval %s, got %s", i, step.want, got)
+		}
+	}
+
+	estimate, ok := rec.QuotaEstimate("balldontlie")
+	if !ok || estimate.Known || estimate.Throttled || estimate.Interval != base {
+		t.Fatalf("expected a recovered, unknown estimate, got %+v %v", estimate, ok)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "provider quota low, stretching interval") || !strings.Contains(out, "provider quota recovered") {
+		t.Fatalf("expected throttle and recovery logs, got %s", out)
+	}
+}
+
+func TestQuotaTrackerRecoversWhenRemainingRises(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	rec := metrics.NewRecorder()
+	q := NewQuotaTracker(QuotaConfig{Provider: "balldontlie", Metrics: rec, Clock: clk})
+
+	q.Observe(2)
+	if got := q.Interval(time.Second); got <= time.Second {
+		t.Fatalf("expected a stretched interval, got %s", got)
+	}
+	estimate, _ := rec.QuotaEstimate("balldontlie")
+	if !estimate.Known || !estimate.Throttled || estimate.Remaining != 2 || !estimate.ResetsAt.Equal(clk.Now().Add(DefaultQuotaWindow)) {
+		t.Fatalf("unexpected throttled estimate %+v", estimate)
+	}
+
+	clk.Advance(5 * time.Second)
+	q.Observe(60)
+	if got := q.Interval(time.Second); got != time.Second {
+		t.Fatalf("expected a new window to restore the interval, got %s", got)
+	}
+	estimate, _ = rec.QuotaEstimate("balldontlie")
+	if estimate.Throttled || estimate.Remaining != 60 || !estimate.ResetsAt.Equal(clk.Now().Add(DefaultQuotaWindow)) {
+		t.Fatalf("unexpected recovered estimate %+v", estimate)
+	}
+}
+
+func TestQuotaTrackerDisabledAndNil(t *testing.T) {
+	q := NewQuotaTracker(QuotaConfig{Threshold: -1})
+	q.Observe(0)
+	if got := q.Interval(time.Second); got != time.Second {
+		t.Fatalf("expected a disabled tracker to keep the interval, got %s", got)
+	}
+
+	var nilTracker *QuotaTracker
+	nilTracker.Observe(0)
+	if got := nilTracker.Interval(time.Second); got != time.Second {
+		t.Fatalf("expected a nil tracker to keep the interval, got %s", got)
+	}
+}
+
+// headerProvider makes one upstream request per fetch through client, so
+// its responses reach the quota tracker as a real provider's would.
+type headerProvider struct {
+	url    string
+	client *http.Client
+}
+
+func (p headerProvider) FetchGames(ctx context.Context, date string, tz string) ([]games.Game, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	return nil, nil
+}
+
+func TestRateLimitedProviderStretchesOnShrinkingQuota(t *testing.T) {
+	var remaining atomic.Int32
+	remaining.Store(6)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(int(remaining.Add(-1))))
+	}))
+	defer srv.Close()
+
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	quota := NewQuotaTracker(QuotaConfig{Threshold: 5, Window: 10 * time.Minute, Clock: clk})
+	upstream := headerProvider{
+		url:    srv.URL,
+		client: &http.Client{Transport: NewObservedTransport(nil, ObservedTransportConfig{Quota: quota, Clock: clk})},
+	}
+	rl := NewRateLimitedProviderWithConfig(upstream, RateLimitConfig{
+		Interval:            time.Minute,
+		AllowFirstImmediate: true,
+		Quota:               quota,
+		Clock:               clk,
+	}, nil).(*rateLimitedProvider)
+
+	// Each call reserves the following slot from what earlier responses
+	// reported, then its own response reports one fewer request left.
+	steps := []struct {
+		advance time.Duration
+		want    time.Duration
+	}{
+		{0, time.Minute},                // nothing known yet; the response says 5 left
+		{time.Minute, time.Minute},      // 5 is not below the threshold; 4 left
+		{time.Minute, 2 * time.Minute},  // 4 left: stretched (8m over 5 calls is less than 2x)
+		{10 * time.Minute, time.Minute}, // the window reset; back to the base interval
+	}
+	for i, step := range steps {
+		clk.Advance(step.advance)
+		if _, err := rl.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+			t.Fatalf("step %d: fetch: %v", i, err)
+		}
+		if got := rl.nextSlot.Sub(clk.Now()); got != step.want {
+			t.Fatalf("step %d: expected the next slot in %s, got %s", i, step.want, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/balldontlie"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// Config controls where the replay client reads recorded responses from.
+type Config struct {
+	// Dir holds balldontlie /games bodies laid out by balldontlie.CapturePath,
+	// as the balldontlie client's capture mode writes them.
+	Dir      string
+	Timezone string      // zone "today" is taken in when FetchGames gets no date; defaults to UTC
+	Clock    clock.Clock // defaults to the real clock
+}
+
+// NotRecordedError is returned when Dir has no recorded response for a date.
+// It unwraps to fs.ErrNotExist.
+type NotRecordedError struct {
+	Dir  string
+	Date string
+}
+
+func (e *NotRecordedError) Error() string {
+	return fmt.Sprintf("replay: no recorded games for %s in %s", e.Date, e.Dir)
+}
+
+func (e *NotRecordedError) Unwrap() error {
+	return fs.ErrNotExist
+}
+
+// Client serves recorded balldontlie responses through the balldontlie
+// client's own mapping, so a replayed date yields the games the live client
+// returned when the responses were captured.
+type Client struct {
+	dir   string
+	loc   *time.Location
+	clock clock.Clock
+}
+
+// NewClient constructs a replay client reading from cfg.Dir.
+func NewClient(cfg Config) *Client {
+	return &Client{
+		dir:   cfg.Dir,
+		loc:   timeutil.ResolveLocation(cfg.Timezone),
+		clock: clock.OrReal(cfg.Clock),
+	}
+}
+
+// FetchGames returns the recorded games for date (today in tz, or the
+// configured zone, when date is empty or malformed). Pages are read from 1
+// until the recorded total page count; a later page missing from disk ends
+// the date early since the capture may have been cut off by a page limit.
+func (c *Client) FetchGames(ctx context.Context, date string, tz string) ([]domaingames.Game, error) {
+	if _, err := timeutil.ParseDate(date); err != nil {
+		loc := c.loc
+		if tz != "" {
+			loc = timeutil.ResolveLocation(tz)
+		}
+		date = timeutil.DateIn(c.clock.Now(), loc)
+	}
+
+	var all []domaingames.Game
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, totalPages, err := c.readPage(date, page)
+		if errors.Is(err, fs.ErrNotExist) {
+			if page == 1 {
+				return nil, &NotRecordedError{Dir: c.dir, Date: date}
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, data...)
+		if totalPages > 0 && page >= totalPages {
+			break
+		}
+	}
+	return balldontlie.DedupeGames(all), nil
+}
+
+func (c *Client) readPage(date string, page int) ([]domaingames.Game, int, error) {
+	path := balldontlie.CapturePath(c.dir, date, page)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	data, totalPages, err := balldontlie.DecodeGamesPage(f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("replay: decode %s: %w", path, err)
+	}
+	return data, totalPages, nil
+}
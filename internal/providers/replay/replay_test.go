@@ -0,0 +1,90 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/providers/balldontlie"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+const gamesPage = `{"data":[{"id":%d,"date":"2024-01-15","datetime":"2024-01-15T%02d:00:00Z","status":"Final","time":"Final","period":4,"postseason":false,` +
+	`"home_team":{"id":1,"full_name":"Boston Celtics","abbreviation":"BOS","city":"Boston","conference":"East","division":"Atlantic","name":"Celtics"},` +
+	`"visitor_team":{"id":2,"full_name":"Miami Heat","abbreviation":"MIA","city":"Miami","conference":"East","division":"Southeast","name":"Heat"},` +
+	`"home_team_score":110,"visitor_team_score":%d,"season":2023}],"meta":{"total_pages":2}}`
+
+func TestReplayMatchesLiveMappingOfCapturedResponses(t *testing.T) {
+	dir := t.TempDir()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var page int
+		_, _ = fmt.Sscan(r.URL.Query().Get("page"), &page)
+		_, _ = fmt.Fprintf(w, gamesPage, 100+page, 18+page, 100+page)
+	}))
+	defer upstream.Close()
+
+	live := balldontlie.NewClient(balldontlie.Config{BaseURL: upstream.URL, APIKey: "key", CaptureDir: dir, PageCacheSize: -1})
+	want, err := live.FetchGames(context.Background(), "2024-01-15", "")
+	if err != nil || len(want) != 2 {
+		t.Fatalf("live fetch: %d games %v", len(want), err)
+	}
+
+	got, err := NewClient(Config{Dir: dir}).FetchGames(context.Background(), "2024-01-15", "")
+	if err != nil {
+		t.Fatalf("replay fetch: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected replay to match the live client\nlive:   %+v\nreplay: %+v", want, got)
+	}
+}
+
+func TestReplayStopsAtTheLastRecordedPage(t *testing.T) {
+	dir := t.TempDir()
+	path := balldontlie.CapturePath(dir, "2024-01-15", 1)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	// The capture claims two pages but stopped after one, e.g. at a page limit.
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(gamesPage, 7, 19, 99)), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	games, err := NewClient(Config{Dir: dir}).FetchGames(context.Background(), "2024-01-15", "")
+	if err != nil || len(games) != 1 || games[0].Meta.UpstreamGameID != 7 {
+		t.Fatalf("expected the one recorded page, got %+v %v", games, err)
+	}
+}
+
+func TestReplayMissingDateIsNotRecorded(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC))
+	client := NewClient(Config{Dir: t.TempDir(), Timezone: "America/New_York", Clock: clk})
+
+	_, err := client.FetchGames(context.Background(), "", "")
+	var notRecorded *NotRecordedError
+	if !errors.As(err, &notRecorded) || notRecorded.Date != "2024-01-15" {
+		t.Fatalf("expected NotRecordedError for today in the configured zone, got %v", err)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the error to unwrap to fs.ErrNotExist")
+	}
+}
+
+func TestReplayReportsMalformedPages(t *testing.T) {
+	dir := t.TempDir()
+	path := balldontlie.CapturePath(dir, "2024-01-15", 1)
+	_ = os.MkdirAll(filepath.Dir(path), 0o755)
+	_ = os.WriteFile(path, []byte("{not json"), 0o644)
+
+	_, err := NewClient(Config{Dir: dir}).FetchGames(context.Background(), "2024-01-15", "")
+	var notRecorded *NotRecordedError
+	if err == nil || errors.As(err, &notRecorded) {
+		t.Fatalf("expected a decode error, got %v", err)
+	}
+}
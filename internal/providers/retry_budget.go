@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+)
+
+const (
+	DefaultRetryBudgetRatio      = 0.1
+	DefaultRetryBudgetWindow     = time.Minute
+	DefaultRetryBudgetMinRetries = 3
+	// MinRetryBudgetWindow is the shortest window NewRetryBudget uses, so
+	// each of its buckets spans at least 10ms.
+	MinRetryBudgetWindow = 100 * time.Millisecond
+	retryBudgetBuckets   = 10
+)
+
+// RetryBudgetConfig tunes NewRetryBudget. Zero values use the defaults.
+type RetryBudgetConfig struct {
+	// Ratio is the share of calls in the window that may be retried.
+	Ratio float64
+	// Window is how far back calls and retries are counted; shorter than
+	// MinRetryBudgetWindow uses MinRetryBudgetWindow.
+	Window time.Duration
+	// MinRetries are allowed per window regardless of Ratio, so a quiet
+	// provider still gets a retry or two.
+	MinRetries int
+	Clock      clock.Clock // defaults to the real clock
+}
+
+// RetryBudget caps retries across every retrying wrapper that shares it, so
+// during an outage the poller, syncer and handlers stop multiplying upstream
+// load: at most MinRetries plus Ratio of the calls seen in the sliding window
+// may be retries. Counts are kept in buckets of Window/10.
+type RetryBudget struct {
+	mu         sync.Mutex
+	ratio      float64
+	minRetries int
+	bucketSpan time.Duration
+	clock      clock.Clock
+	buckets    [retryBudgetBuckets]budgetBucket
+}
+
+type budgetBucket struct {
+	start   time.Time
+	calls   int
+	retries int
+}
+
+// NewRetryBudget builds a budget to share between retrying providers.
+func NewRetryBudget(cfg RetryBudgetConfig) *RetryBudget {
+	if cfg.Ratio <= 0 {
+		cfg.Ratio = DefaultRetryBudgetRatio
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = DefaultRetryBudgetWindow
+	} else if cfg.Window < MinRetryBudgetWindow {
+		cfg.Window = MinRetryBudgetWindow
+	}
+	if cfg.MinRetries < 0 {
+		cfg.MinRetries = 0
+	} else if cfg.MinRetries == 0 {
+		cfg.MinRetries = DefaultRetryBudgetMinRetries
+	}
+	return &RetryBudget{
+		ratio:      cfg.Ratio,
+		minRetries: cfg.MinRetries,
+		bucketSpan: cfg.Window / retryBudgetBuckets,
+		clock:      clock.OrReal(cfg.Clock),
+	}
+}
+
+// recordCall counts a first attempt. A nil budget does nothing.
+func (b *RetryBudget) recordCall() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bucket(b.clock.Now()).calls++
+}
+
+// tryRetry spends budget for one retry, reporting false when none is left.
+// A nil budget always allows.
+func (b *RetryBudget) tryRetry() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	var calls, retries int
+	for _, bk := range b.buckets {
+		if b.live(bk, now) {
+			calls += bk.calls
+			retries += bk.retries
+		}
+	}
+	if float64(retries+1) > float64(b.minRetries)+b.ratio*float64(calls) {
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+// bucket returns the bucket for now, clearing it if it held an older span.
+func (b *RetryBudget) bucket(now time.Time) *budgetBucket {
+	start := now.Truncate(b.bucketSpan)
+	bk := &b.buckets[(start.UnixNano()/int64(b.bucketSpan))%retryBudgetBuckets]
+	if !bk.start.Equal(start) {
+		*bk = budgetBucket{start: start}
+	}
+	return bk
+}
+
+func (b *RetryBudget) live(bk budgetBucket, now time.Time) bool {
+	return !bk.start.IsZero() && now.Sub(bk.start) < retryBudgetBuckets*b.bucketSpan
+}
@@ -0,0 +1,116 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+type failingProvider struct{ calls int }
+
+func (f *failingProvider) FetchGames(context.Context, string, string) ([]games.Game, error) {
+	f.calls++
+	return nil, errors.New("upstream down")
+}
+
+// fetchAdvancing runs p.FetchGames, stepping clk past each backoff sleep.
+func fetchAdvancing(clk *teststubs.FakeClock, p GameProvider) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.FetchGames(context.Background(), "", "")
+		done <- err
+	}()
+	for {
+		select {
+		case err := <-done:
+			return err
+		default:
+		}
+		if clk.WaitForTimers(1, 10*time.Millisecond) {
+			clk.Advance(time.Millisecond)
+		}
+	}
+}
+
+func TestRetryBudgetSharedAcrossWrappersDrainsAndRefills(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0.1, Window: time.Minute, MinRetries: 2, Clock: clk})
+	rec := metrics.NewRecorder()
+	poller, syncer := &failingProvider{}, &failingProvider{}
+	cfg := RetryConfig{Name: "flakey", MaxAttempts: 3, Backoff: time.Millisecond, Budget: budget, Clock: clk}
+	rpPoller := NewRetryingProviderWithConfig(poller, nil, rec, cfg)
+	rpSyncer := NewRetryingProviderWithConfig(syncer, nil, rec, cfg)
+
+	if err := fetchAdvancing(clk, rpPoller); err == nil {
+		t.Fatal("expected failure")
+	}
+	if poller.calls != 3 {
+		t.Fatalf("expected both retries within the budget, got %d attempts", poller.calls)
+	}
+	if err := fetchAdvancing(clk, rpSyncer); err == nil {
+		t.Fatal("expected failure")
+	}
+	if syncer.calls != 1 || rec.RetryBudgetExhausted("flakey") != 1 {
+		t.Fatalf("expected a fail-fast once the shared budget drained, got %d attempts, %d exhausted", syncer.calls, rec.RetryBudgetExhausted("flakey"))
+	}
+
+	clk.Advance(time.Minute)
+	if err := fetchAdvancing(clk, rpSyncer); err == nil {
+		t.Fatal("expected failure")
+	}
+	if syncer.calls != 4 {
+		t.Fatalf("expected retries to resume after the window, got %d attempts", syncer.calls)
+	}
+}
+
+func TestRetryBudgetAllowsRatioOfRecentCalls(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	budget := NewRetryBudget(RetryBudgetConfig{Ratio: 0.1, Window: time.Minute, MinRetries: -1, Clock: clk})
+	if budget.tryRetry() {
+		t.Fatalf("expected no retries before any calls")
+	}
+	for i := 0; i < 20; i++ {
+		budget.recordCall()
+		clk.Advance(time.Second)
+	}
+	for i := 0; i < 2; i++ {
+		if !budget.tryRetry() {
+			t.Fatalf("retry %d: expected 10%% of 20 calls allowed", i)
+		}
+	}
+	if budget.tryRetry() {
+		t.Fatalf("expected the third retry denied")
+	}
+
+	// Once the window passes, old calls no longer earn retries.
+	clk.Advance(time.Minute)
+	budget.recordCall()
+	if budget.tryRetry() {
+		t.Fatalf("expected expired calls not to count")
+	}
+	var nilBudget *RetryBudget
+	if !nilBudget.tryRetry() {
+		t.Fatalf("expected a nil budget to allow retries")
+	}
+}
+
+func TestRetryBudgetTinyWindowUsesMinimum(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	budget := NewRetryBudget(RetryBudgetConfig{Window: 5 * time.Nanosecond, MinRetries: 1, Clock: clk})
+	budget.recordCall()
+	if !budget.tryRetry() {
+		t.Fatalf("expected the minimum retry allowed")
+	}
+	if budget.tryRetry() {
+		t.Fatalf("expected the second retry denied within the window")
+	}
+	clk.Advance(MinRetryBudgetWindow)
+	if !budget.tryRetry() {
+		t.Fatalf("expected the budget to refill after MinRetryBudgetWindow")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
@@ -28,23 +29,56 @@ type retryingProvider struct {
 	maxAttempts  int
 	backoffFn    backoffFunc
 	rng          *rand.Rand
+	budget       *RetryBudget
+	clock        clock.Clock
+}
+
+// RetryConfig tunes NewRetryingProviderWithConfig. Zero values use the defaults.
+type RetryConfig struct {
+	// Name labels logs and metrics; when empty it falls back to the provider type.
+	Name        string
+	MaxAttempts int
+	Backoff     time.Duration // base delay, scaled by the attempt number and jittered
+	// Budget caps retries and may be shared with other wrappers; nil leaves
+	// retries unlimited.
+	Budget *RetryBudget
+	Clock  clock.Clock // times attempts and backoff sleeps; defaults to the real clock
+	RNG    *rand.Rand  // jitter source; defaults to a time-seeded one
 }
 
 // NewRetryingProvider wraps the given provider with retries. If maxAttempts/backoff are <= 0, defaults are used.
 // providerName is optional; when empty it falls back to the provider type.
+// Retries draw on a default RetryBudget of the wrapper's own.
 func NewRetryingProvider(inner GameProvider, logger *slog.Logger, metricsRecorder *metrics.Recorder, providerName string, maxAttempts int, backoff time.Duration) GameProvider {
 	return NewRetryingProviderWithRNG(inner, logger, metricsRecorder, providerName, nil, maxAttempts, backoff)
 }
 
 // NewRetryingProviderWithRNG is identical to NewRetryingProvider but allows injecting a rand.Rand for deterministic tests.
 func NewRetryingProviderWithRNG(inner GameProvider, logger *slog.Logger, metricsRecorder *metrics.Recorder, providerName string, rng *rand.Rand, maxAttempts int, backoff time.Duration) GameProvider {
-	if maxAttempts <= 0 {
-		maxAttempts = defaultRetryAttempts
+	return NewRetryingProviderWithConfig(inner, logger, metricsRecorder, RetryConfig{
+		Name:        providerName,
+		MaxAttempts: maxAttempts,
+		Backoff:     backoff,
+		Budget:      NewRetryBudget(RetryBudgetConfig{}),
+		RNG:         rng,
+	})
+}
+
+// NewRetryingProviderWithConfig wraps inner with retries tuned by cfg.
+func NewRetryingProviderWithConfig(inner GameProvider, logger *slog.Logger, metricsRecorder *metrics.Recorder, cfg RetryConfig) GameProvider {
+	return newRetryingProvider(inner, logger, metricsRecorder, cfg)
+}
+
+func newRetryingProvider(inner GameProvider, logger *slog.Logger, metricsRecorder *metrics.Recorder, cfg RetryConfig) *retryingProvider {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultRetryAttempts
 	}
+	backoff := cfg.Backoff
 	if backoff <= 0 {
 		backoff = defaultBackoff
 	}
 
+	providerName := cfg.Name
 	if providerName == "" {
 		if inner != nil {
 			providerName = fmt.Sprintf("%T", inner)
@@ -53,8 +87,10 @@ func NewRetryingProviderWithRNG(inner GameProvider, logger *slog.Logger, metrics
 		}
 	}
 
+	clk := clock.OrReal(cfg.Clock)
+	rng := cfg.RNG
 	if rng == nil {
-		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+		rng = rand.New(rand.NewSource(clk.Now().UnixNano()))
 	}
 
 	return &retryingProvider{
@@ -62,21 +98,24 @@ func NewRetryingProviderWithRNG(inner GameProvider, logger *slog.Logger, metrics
 		logger:       logger,
 		metrics:      metricsRecorder,
 		providerName: providerName,
-		maxAttempts:  maxAttempts,
+		maxAttempts:  cfg.MaxAttempts,
 		backoffFn: func(attempt int) time.Duration {
 			return time.Duration(attempt) * backoff
 		},
-		rng: rng,
+		rng:    rng,
+		budget: cfg.Budget,
+		clock:  clk,
 	}
 }
 
 func (r *retryingProvider) FetchGames(ctx context.Context, date string, tz string) ([]games.Game, error) {
 	var lastErr error
 
+	r.budget.recordCall()
 	for attempt := 1; attempt <= r.maxAttempts; attempt++ {
-		start := time.Now()
+		start := r.clock.Now()
 		gm, err := r.gameProvider.FetchGames(ctx, date, tz)
-		r.recordAttempt(time.Since(start), err)
+		r.recordAttempt(r.clock.Now().Sub(start), err)
 
 		if err == nil {
 			if attempt > 1 {
@@ -96,8 +135,23 @@ func (r *retryingProvider) FetchGames(ctx context.Context, date string, tz strin
 		if attempt == r.maxAttempts {
 			break
 		}
+		if !r.budget.tryRetry() {
+			if r.metrics != nil {
+				r.metrics.RecordRetryBudgetExhausted(r.providerName)
+			}
+			r.log(ctx, slog.LevelWarn, "provider retry budget exhausted", "provider", r.providerName, "attempt", attempt, "err", err)
+			return nil, lastErr
+		}
 
 		delay := r.computeDelay(err, attempt)
+		if rlErr, ok := AsRateLimitError(err); ok && rlErr.RetryAfter > 0 && exceedsDeadline(ctx, delay) {
+			r.log(ctx, slog.LevelWarn, "provider retry-after exceeds deadline",
+				"provider", r.providerName,
+				"attempt", attempt,
+				"retry_after_ms", rlErr.RetryAfter.Milliseconds(),
+			)
+			return nil, fmt.Errorf("%w: %w", ErrRetryAfterExceedsDeadline, err)
+		}
 		r.logRetry(ctx, attempt, delay, err)
 		if sleepErr := r.sleep(ctx, delay); sleepErr != nil {
 			return nil, sleepErr
@@ -108,6 +162,11 @@ func (r *retryingProvider) FetchGames(ctx context.Context, date string, tz strin
 	return nil, lastErr
 }
 
+// Unwrap returns the wrapped provider.
+func (r *retryingProvider) Unwrap() GameProvider {
+	return r.gameProvider
+}
+
 func (r *retryingProvider) computeDelay(err error, attempt int) time.Duration {
 	base := r.backoffFn(attempt)
 
@@ -123,6 +182,13 @@ func (r *retryingProvider) computeDelay(err error, attempt int) time.Duration {
 	return r.jitterDelay(base)
 }
 
+// exceedsDeadline reports whether sleeping delay would outlast ctx's deadline.
+// Deadlines run on the wall clock, so this does not use the injected clock.
+func exceedsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < delay
+}
+
 func (r *retryingProvider) jitterDelay(base time.Duration) time.Duration {
 	if base <= 0 {
 		return 0
@@ -141,13 +207,13 @@ func (r *retryingProvider) sleep(ctx context.Context, delay time.Duration) error
 		return nil
 	}
 
-	timer := time.NewTimer(delay)
+	timer := r.clock.NewTimer(delay)
 	defer timer.Stop()
 
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
-	case <-timer.C:
+	case <-timer.C():
 		return nil
 	}
 }
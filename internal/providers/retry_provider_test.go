@@ -11,6 +11,7 @@ import (
 
 	"github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
 type flakeyProvider struct {
@@ -71,6 +72,25 @@ func TestRetryingProviderRespectsContextCancel(t *testing.T) {
 	}
 }
 
+func TestRetryingProviderSleepsOnInjectedClock(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	fp := &flakeyProvider{failures: 1}
+	rp := NewRetryingProviderWithConfig(fp, nil, nil, RetryConfig{Name: "flakey", MaxAttempts: 2, Backoff: time.Hour, Clock: clk})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := rp.FetchGames(context.Background(), "", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the backoff to wait on the fake clock")
+	}
+	clk.Advance(time.Hour)
+	if err := <-done; err != nil || fp.calls != 2 {
+		t.Fatalf("expected success on the second attempt, got %v after %d calls", err, fp.calls)
+	}
+}
+
 func TestRetryingProviderUsesCustomBackoff(t *testing.T) {
 	fp := &flakeyProvider{failures: 1}
 	rp := NewRetryingProvider(fp, nil, metrics.NewRecorder(), "flakey", 2, time.Hour).(*retryingProvider)
@@ -237,7 +257,8 @@ func TestRetryingProviderLogRetryIncludesRateLimitFields(t *testing.T) {
 }
 
 type rateLimitThenSuccessProvider struct {
-	calls int
+	calls      int
+	retryAfter time.Duration
 }
 
 func (f *rateLimitThenSuccessProvider) FetchGames(ctx context.Context, date string, tz string) ([]games.Game, error) {
@@ -249,7 +270,66 @@ func (f *rateLimitThenSuccessProvider) FetchGames(ctx context.Context, date stri
 		return nil, &RateLimitError{
 			Provider:   "test",
 			StatusCode: 429,
+			RetryAfter: f.retryAfter,
 		}
 	}
 	return []games.Game{{ID: "ok"}}, nil
 }
+
+func TestRetryingProviderReturnsRateLimitWhenRetryAfterExceedsDeadline(t *testing.T) {
+	rlErr := &RateLimitError{Provider: "test", StatusCode: 429, RetryAfter: time.Minute}
+	inner := &teststubs.StubProvider{Err: rlErr}
+	rp := NewRetryingProvider(inner, nil, metrics.NewRecorder(), "rl", 3, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := rp.FetchGames(ctx, "", "")
+	if !errors.Is(err, ErrRetryAfterExceedsDeadline) {
+		t.Fatalf("expected ErrRetryAfterExceedsDeadline, got %v", err)
+	}
+	if got, ok := AsRateLimitError(err); !ok || got != rlErr {
+		t.Fatalf("expected the rate limit error to stay reachable, got %v", err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || time.Since(start) > time.Second {
+		t.Fatalf("expected an immediate return instead of sleeping into the deadline")
+	}
+	if inner.Calls.Load() != 1 {
+		t.Fatalf("expected no retry, got %d calls", inner.Calls.Load())
+	}
+}
+
+func TestRetryingProviderSleepsRetryAfterWithinDeadline(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	inner := &rateLimitThenSuccessProvider{retryAfter: 2 * time.Second}
+	rp := NewRetryingProviderWithConfig(inner, nil, nil, RetryConfig{Name: "rl", MaxAttempts: 2, Clock: clk})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := rp.FetchGames(ctx, "", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the retry to wait out Retry-After")
+	}
+	clk.Advance(2 * time.Second)
+	if err := <-done; err != nil || inner.calls != 2 {
+		t.Fatalf("expected success on the retry, got %v after %d calls", err, inner.calls)
+	}
+}
+
+func TestExceedsDeadline(t *testing.T) {
+	if exceedsDeadline(context.Background(), time.Hour) {
+		t.Fatalf("expected no deadline to never be exceeded")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if exceedsDeadline(ctx, time.Second) {
+		t.Fatalf("expected 1s to fit in 5s")
+	}
+	if !exceedsDeadline(ctx, time.Minute) {
+		t.Fatalf("expected 1m to exceed 5s")
+	}
+}
@@ -0,0 +1,88 @@
+package providers
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Transport defaults, tuned for paginated bursts against one upstream host:
+// enough idle connections per host that a burst of page requests reuses them
+// instead of redialing.
+const (
+	DefaultMaxIdleConns          = 100
+	DefaultMaxIdleConnsPerHost   = 10
+	DefaultIdleConnTimeout       = 90 * time.Second
+	DefaultTLSHandshakeTimeout   = 10 * time.Second
+	DefaultResponseHeaderTimeout = 8 * time.Second
+)
+
+// TransportConfig tunes the shared provider transport. Zero values use the
+// package defaults.
+type TransportConfig struct {
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	HTTP2                 bool // negotiate HTTP/2 over TLS; off by default
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[TransportConfig]*http.Transport{}
+)
+
+// SharedTransport returns the transport for cfg, building it on first use so
+// every provider with the same settings shares one connection pool.
+func SharedTransport(cfg TransportConfig) *http.Transport {
+	cfg = cfg.withDefaults()
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[cfg]; ok {
+		return t
+	}
+	t := newTransport(cfg)
+	transports[cfg] = t
+	return t
+}
+
+// NewHTTPClient returns a client on the shared transport for cfg.
+func NewHTTPClient(cfg TransportConfig, timeout time.Duration) *http.Client {
+	return &http.Client{Transport: SharedTransport(cfg), Timeout: timeout}
+}
+
+func (c TransportConfig) withDefaults() TransportConfig {
+	if c.MaxIdleConns <= 0 {
+		c.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if c.MaxIdleConnsPerHost <= 0 {
+		c.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if c.IdleConnTimeout <= 0 {
+		c.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if c.TLSHandshakeTimeout <= 0 {
+		c.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	}
+	if c.ResponseHeaderTimeout <= 0 {
+		c.ResponseHeaderTimeout = DefaultResponseHeaderTimeout
+	}
+	return c
+}
+
+func newTransport(cfg TransportConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	t.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	t.ForceAttemptHTTP2 = cfg.HTTP2
+	if !cfg.HTTP2 {
+		// A non-nil empty map is how net/http is told not to upgrade to h2.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
@@ -0,0 +1,38 @@
+package providers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSharedTransportIsBuiltOncePerConfig(t *testing.T) {
+	a := SharedTransport(TransportConfig{})
+	if a != SharedTransport(TransportConfig{MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost}) {
+		t.Fatalf("expected defaults to resolve to the same shared transport")
+	}
+	if a == SharedTransport(TransportConfig{MaxIdleConnsPerHost: 3}) {
+		t.Fatalf("expected different settings to get their own transport")
+	}
+	if a.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || a.ResponseHeaderTimeout != DefaultResponseHeaderTimeout {
+		t.Fatalf("expected tuned defaults, got %d %s", a.MaxIdleConnsPerHost, a.ResponseHeaderTimeout)
+	}
+}
+
+func TestSharedTransportHTTP2Option(t *testing.T) {
+	off := SharedTransport(TransportConfig{})
+	if off.ForceAttemptHTTP2 || off.TLSNextProto == nil {
+		t.Fatalf("expected HTTP/2 disabled by default")
+	}
+	on := SharedTransport(TransportConfig{HTTP2: true})
+	if !on.ForceAttemptHTTP2 || on.TLSNextProto != nil {
+		t.Fatalf("expected HTTP/2 enabled on request")
+	}
+}
+
+func TestNewHTTPClientUsesSharedTransport(t *testing.T) {
+	cfg := TransportConfig{IdleConnTimeout: time.Minute}
+	client := NewHTTPClient(cfg, 5*time.Second)
+	if client.Transport != SharedTransport(cfg) || client.Timeout != 5*time.Second {
+		t.Fatalf("unexpected client %+v", client)
+	}
+}
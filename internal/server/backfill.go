@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// ErrBackfillFailed is returned by Backfill when dates were still failing
+// after the syncer's retry passes.
+var ErrBackfillFailed = errors.New("backfill failed")
+
+// BackfillOptions selects what a one-shot backfill syncs.
+type BackfillOptions struct {
+	From, To string // inclusive YYYY-MM-DD range
+	Provider string // overrides cfg.Provider when set
+	DryRun   bool   // list the dates that would be fetched without fetching them
+}
+
+// Backfill syncs every date from opts.From to opts.To into the snapshot
+// directory and returns once it is done, for CI and cron. The provider gets
+// the server's rate-limit and retry wrappers and dates are spaced by
+// SNAPSHOT_SYNC_INTERVAL, as in the scheduled sync. Progress goes to out, one
+// line per attempted date.
+func Backfill(ctx context.Context, cfg config.Config, opts BackfillOptions, logger *slog.Logger, out io.Writer) error {
+	if opts.Provider != "" {
+		cfg.Provider = opts.Provider
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	budget := newRetryBudget(cfg.RetryBudget, serverClock)
+	provider := newProviderFactory(logger, nil, budget).build(cfg)
+	return backfill(ctx, cfg, provider, opts, logger, out)
+}
+
+func backfill(ctx context.Context, cfg config.Config, provider providers.GameProvider, opts BackfillOptions, logger *slog.Logger, out io.Writer) error {
+	loc := cfg.Location()
+	writer := newSnapshotWriter(cfg, logger, loc, serverClock)
+	// Backfilled dates are often older than the retention window; pruning on
+	// each write would delete them as they land.
+	writer.SetPruneGuard(func() bool { return true })
+
+	total := 0
+	seen := make(map[string]bool)
+	syncCfg := syncConfig(cfg, serverClock, nil)
+	syncCfg.OnDate = func(date string, err error) {
+		step := "retry"
+		if !seen[date] {
+			seen[date] = true
+			step = fmt.Sprintf("%d/%d", len(seen), total)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "[%s] %s failed: %v\n", step, date, err)
+			return
+		}
+		fmt.Fprintf(out, "[%s] %s ok\n", step, date)
+	}
+	syncer := snapshots.NewSyncer(provider, writer, syncCfg, logger, loc)
+
+	dates, err := syncer.RangeDates(opts.From, opts.To)
+	if err != nil {
+		return err
+	}
+	total = len(dates)
+	if opts.DryRun {
+		for _, date := range dates {
+			fmt.Fprintln(out, date)
+		}
+		fmt.Fprintf(out, "%d dates would be fetched\n", total)
+		return nil
+	}
+	if cutoff := timeutil.DateOffset(serverClock.Now(), loc, -cfg.Snapshots.RetentionDays); total > 0 && dates[0] < cutoff {
+		fmt.Fprintf(out, "note: dates before %s are outside SNAPSHOT_RETENTION_GAMES_DAYS and the server's next write prunes them unless pinned\n", cutoff)
+	}
+
+	failed, err := syncer.Backfill(ctx, dates)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "backfill done: %d dates, %d failed\n", total, len(failed))
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrBackfillFailed, strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// backfillProvider records fetched dates and fails each date in failures
+// that many times before succeeding.
+type backfillProvider struct {
+	mu       sync.Mutex
+	dates    []string
+	failures map[string]int
+}
+
+func (p *backfillProvider) FetchGames(_ context.Context, date, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dates = append(p.dates, date)
+	if p.failures[date] > 0 {
+		p.failures[date]--
+		return nil, errors.New("upstream down")
+	}
+	return []domaingames.Game{{ID: "g-" + date, Provider: "test", StatusKind: domaingames.StatusFinal}}, nil
+}
+
+func backfillConfig(t *testing.T) config.Config {
+	t.Helper()
+	cfg := config.Config{}
+	cfg.Snapshots.SnapshotFolder = t.TempDir()
+	cfg.Snapshots.Interval = time.Nanosecond
+	cfg.Snapshots.RetentionDays = 7
+	return cfg
+}
+
+func TestBackfillFailsWhenADateKeepsFailing(t *testing.T) {
+	cfg := backfillConfig(t)
+	provider := &backfillProvider{failures: map[string]int{"2024-01-02": 100}}
+	var out bytes.Buffer
+
+	err := backfill(context.Background(), cfg, provider, BackfillOptions{From: "2024-01-01", To: "2024-01-03"}, nil, &out)
+	if !errors.Is(err, ErrBackfillFailed) || !strings.Contains(err.Error(), "2024-01-02") {
+		t.Fatalf("expected the failing date reported, got %v", err)
+	}
+	for _, date := range []string{"2024-01-01", "2024-01-03"} {
+		if _, statErr := os.Stat(snapshots.GameSnapshotPath(cfg.Snapshots.SnapshotFolder, date)); statErr != nil {
+			t.Fatalf("expected %s written despite the other failure (pruning off): %v", date, statErr)
+		}
+	}
+	for _, want := range []string{"[1/3] 2024-01-01 ok", "[2/3] 2024-01-02 failed", "[retry] 2024-01-02 failed", "3 dates, 1 failed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected %q in progress output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestBackfillSucceedsOnceRetriesRecover(t *testing.T) {
+	cfg := backfillConfig(t)
+	provider := &backfillProvider{failures: map[string]int{"2024-01-02": 1}}
+	var out bytes.Buffer
+
+	if err := backfill(context.Background(), cfg, provider, BackfillOptions{From: "2024-01-01", To: "2024-01-02"}, nil, &out); err != nil {
+		t.Fatalf("expected a recovered date not to fail the backfill, got %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "[retry] 2024-01-02 ok") {
+		t.Fatalf("expected the retry in the output:\n%s", out.String())
+	}
+}
+
+func TestBackfillDryRunListsDatesWithoutFetching(t *testing.T) {
+	cfg := backfillConfig(t)
+	cfg.Snapshots.SeasonEnd = "2024-06-17"
+	provider := &backfillProvider{}
+	var out bytes.Buffer
+
+	if err := backfill(context.Background(), cfg, provider, BackfillOptions{From: "2024-06-16", To: "2024-06-19", DryRun: true}, nil, &out); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(provider.dates) != 0 {
+		t.Fatalf("expected no fetches on a dry run, got %v", provider.dates)
+	}
+	if got := out.String(); got != "2024-06-16\n2024-06-17\n2 dates would be fetched\n" {
+		t.Fatalf("expected the in-season dates listed, got %q", got)
+	}
+}
+
+func TestBackfillRejectsBadRanges(t *testing.T) {
+	cfg := backfillConfig(t)
+	for _, opts := range []BackfillOptions{{From: "2024-01-05", To: "2024-01-01"}, {From: "yesterday", To: "2024-01-01"}} {
+		if err := backfill(context.Background(), cfg, &backfillProvider{}, opts, nil, &bytes.Buffer{}); err == nil || errors.Is(err, ErrBackfillFailed) {
+			t.Fatalf("%+v: expected a range error, got %v", opts, err)
+		}
+	}
+}
+
+func TestBackfillValidatesTheProviderOverride(t *testing.T) {
+	err := Backfill(context.Background(), backfillConfig(t), BackfillOptions{From: "2024-01-01", To: "2024-01-01", Provider: "espn"}, nil, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "not a known provider") {
+		t.Fatalf("expected the provider override validated, got %v", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 type httpServer interface {
 	ListenAndServe() error
 	Shutdown(context.Context) error
+	Close() error
 	Addr() string
 	Handler() http.Handler
 }
@@ -26,5 +27,6 @@ func (s netHTTPServer) ListenAndServe() error {
 	return s.srv.ListenAndServe()
 }
 func (s netHTTPServer) Shutdown(ctx context.Context) error { return s.srv.Shutdown(ctx) }
+func (s netHTTPServer) Close() error                       { return s.srv.Close() }
 func (s netHTTPServer) Addr() string                       { return s.srv.Addr }
 func (s netHTTPServer) Handler() http.Handler              { return s.srv.Handler }
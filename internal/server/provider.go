@@ -2,28 +2,66 @@ package server
 
 import (
 	"log/slog"
+	"net/http"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/providers/balldontlie"
 	"github.com/preston-bernstein/nba-data-service/internal/providers/fixture"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/replay"
 )
 
-func selectProvider(cfg config.Config, logger *slog.Logger) providers.GameProvider {
+// NewProvider builds the provider cfg names without the server's rate-limit
+// and retry wrappers, for one-off calls such as the preflight check.
+func NewProvider(cfg config.Config) providers.GameProvider {
+	return selectProvider(cfg, nil, serverClock, nil, nil)
+}
+
+func selectProvider(cfg config.Config, logger *slog.Logger, clk clock.Clock, recorder *metrics.Recorder, quota *providers.QuotaTracker) providers.GameProvider {
 	switch cfg.Provider {
 	case "fixture", "":
-		return fixture.New()
+		return fixture.NewWithClock(clk)
 	case "balldontlie":
 		return balldontlie.NewClient(balldontlie.Config{
-			BaseURL:  cfg.Balldontlie.BaseURL,
-			APIKey:   cfg.Balldontlie.APIKey,
+			BaseURL:         cfg.Balldontlie.BaseURL,
+			APIKey:          cfg.Balldontlie.APIKey,
+			SecondaryAPIKey: cfg.Balldontlie.SecondaryAPIKey,
+			Timezone:        cfg.Balldontlie.Timezone,
+			MaxPages:        cfg.Balldontlie.MaxPages,
+			MaxBodyBytes:    int64(cfg.Balldontlie.MaxBodyBytes),
+			StrictDecode:    cfg.Balldontlie.StrictDecode,
+			CaptureDir:      cfg.Balldontlie.CaptureDir,
+			HTTPClient:      providerHTTPClient(cfg.Balldontlie),
+			Logger:          logger,
+			Clock:           clk,
+			Metrics:         recorder,
+			Quota:           quota,
+		})
+	case "replay":
+		return replay.NewClient(replay.Config{
+			Dir:      cfg.ReplayDir,
 			Timezone: cfg.Balldontlie.Timezone,
-			MaxPages: cfg.Balldontlie.MaxPages,
+			Clock:    clk,
 		})
 	default:
 		if logger != nil {
 			logger.Warn("unknown provider, falling back to fixture", slog.String("provider", cfg.Provider))
 		}
-		return fixture.New()
+		return fixture.NewWithClock(clk)
 	}
 }
+
+// providerHTTPClient builds the balldontlie client on the shared, tuned
+// provider transport.
+func providerHTTPClient(cfg config.BalldontlieConfig) *http.Client {
+	return providers.NewHTTPClient(providers.TransportConfig{
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   cfg.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		HTTP2:                 cfg.HTTP2,
+	}, cfg.Timeout)
+}
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/config"
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
@@ -13,15 +14,70 @@ import (
 type providerFactory struct {
 	logger  *slog.Logger
 	metrics *metrics.Recorder
+	clock   clock.Clock
+	budget  *providers.RetryBudget // shared by every retry wrapper the server builds
 }
 
-func newProviderFactory(logger *slog.Logger, metrics *metrics.Recorder) providerFactory {
-	return providerFactory{logger: logger, metrics: metrics}
+func newProviderFactory(logger *slog.Logger, metrics *metrics.Recorder, budget *providers.RetryBudget) providerFactory {
+	return providerFactory{logger: logger, metrics: metrics, clock: serverClock, budget: budget}
+}
+
+// newRetryBudget builds the server's one retry budget from cfg.
+func newRetryBudget(cfg config.RetryBudgetConfig, clk clock.Clock) *providers.RetryBudget {
+	minRetries := cfg.MinRetries
+	if minRetries == 0 {
+		// Config's 0 means no floor; the providers package reads 0 as "default".
+		minRetries = -1
+	}
+	return providers.NewRetryBudget(providers.RetryBudgetConfig{
+		Ratio:      cfg.Ratio,
+		Window:     cfg.Window,
+		MinRetries: minRetries,
+		Clock:      clk,
+	})
+}
+
+// newQuotaTracker builds the tracker the provider's responses feed and its
+// rate limiter reads from cfg.
+func (f providerFactory) newQuotaTracker(cfg config.Config) *providers.QuotaTracker {
+	threshold := cfg.Quota.Threshold
+	if threshold == 0 {
+		// Config's 0 turns throttling off; the providers package reads 0 as "default".
+		threshold = -1
+	}
+	return providers.NewQuotaTracker(providers.QuotaConfig{
+		Threshold: threshold,
+		Window:    time.Duration(cfg.Quota.Window),
+		Provider:  normalizeProviderName(cfg.Provider, nil),
+		Metrics:   f.metrics,
+		Logger:    f.logger,
+		Clock:     f.clock,
+	})
 }
 
 func (f providerFactory) build(cfg config.Config) providers.GameProvider {
-	base := selectProvider(cfg, f.logger)
-	// Shared rate limiter to respect upstream quota (1/min default if poll interval is shorter).
-	limited := providers.NewRateLimitedProvider(base, time.Minute, f.logger)
-	return providers.NewRetryingProvider(limited, f.logger, f.metrics, normalizeProviderName(cfg.Provider, base), 0, 0)
+	quota := f.newQuotaTracker(cfg)
+	base := selectProvider(cfg, f.logger, f.clock, f.metrics, quota)
+	// Shared rate limiter to respect upstream quota; reload can change its interval.
+	// The first call goes straight through so the poller's warm-up fetch is not delayed.
+	interval := time.Duration(cfg.ProviderRateLimit)
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	limited := providers.NewRateLimitedProviderWithConfig(base, providers.RateLimitConfig{
+		Interval:            interval,
+		AllowFirstImmediate: true,
+		Quota:               quota,
+		Clock:               f.clock,
+	}, f.logger)
+	return f.retry(limited, normalizeProviderName(cfg.Provider, base))
+}
+
+// retry wraps p with retries drawn from the factory's shared budget.
+func (f providerFactory) retry(p providers.GameProvider, name string) providers.GameProvider {
+	return providers.NewRetryingProviderWithConfig(p, f.logger, f.metrics, providers.RetryConfig{
+		Name:   name,
+		Budget: f.budget,
+		Clock:  f.clock,
+	})
 }
@@ -1,15 +1,90 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 )
 
 func TestProviderFactoryBuildsWithDefaultInterval(t *testing.T) {
-	factory := newProviderFactory(nil, nil)
+	factory := newProviderFactory(nil, nil, nil)
 	prov := factory.build(config.Config{Provider: "fixture"})
 	if prov == nil {
 		t.Fatalf("expected provider")
 	}
 }
+
+type countingErrProvider struct{ calls atomic.Int32 }
+
+func (p *countingErrProvider) FetchGames(context.Context, string, string) ([]domaingames.Game, error) {
+	p.calls.Add(1)
+	return nil, errors.New("upstream down")
+}
+
+func TestProviderFactoryRetryWrappersShareOneBudget(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	// Room for exactly one retry per window across every wrapper.
+	budget := newRetryBudget(config.RetryBudgetConfig{Ratio: 0.01, Window: time.Minute, MinRetries: 1}, clk)
+	factory := providerFactory{clock: clk, budget: budget}
+	first, second := &countingErrProvider{}, &countingErrProvider{}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = factory.retry(first, "flakey").FetchGames(context.Background(), "", "")
+		close(done)
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the first wrapper to back off before its retry")
+	}
+	clk.Advance(time.Second)
+	<-done
+	if _, err := factory.retry(second, "flakey").FetchGames(context.Background(), "", ""); err == nil {
+		t.Fatal("expected failure")
+	}
+	if first.calls.Load() != 2 || second.calls.Load() != 1 {
+		t.Fatalf("expected one retry shared between wrappers, got %d and %d attempts", first.calls.Load(), second.calls.Load())
+	}
+}
+
+func TestProviderFactoryWrapsRetriesOnce(t *testing.T) {
+	// retry -> rate limit -> fixture; a second retry layer would add a level.
+	depth := 0
+	for p := newProviderFactory(nil, nil, nil).build(config.Config{Provider: "fixture"}); ; depth++ {
+		u, ok := p.(providers.Unwrapper)
+		if !ok {
+			break
+		}
+		p = u.Unwrap()
+	}
+	if depth != 2 {
+		t.Fatalf("expected two wrappers around the base provider, got %d", depth)
+	}
+}
+
+func TestProviderFactoryQuotaTrackerHonorsThreshold(t *testing.T) {
+	rec := metrics.NewRecorder()
+	factory := providerFactory{metrics: rec, clock: teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))}
+
+	off := factory.newQuotaTracker(config.Config{Provider: "balldontlie"})
+	off.Observe(0)
+	if got := off.Interval(time.Minute); got != time.Minute {
+		t.Fatalf("expected a zero threshold to turn throttling off, got %s", got)
+	}
+
+	on := factory.newQuotaTracker(config.Config{Provider: "balldontlie", Quota: config.QuotaConfig{Threshold: 5, Window: config.Duration(time.Minute)}})
+	on.Observe(1)
+	if got := on.Interval(time.Minute); got <= time.Minute {
+		t.Fatalf("expected a stretched interval, got %s", got)
+	}
+	if estimate, ok := rec.QuotaEstimate("balldontlie"); !ok || !estimate.Throttled {
+		t.Fatalf("expected the estimate recorded under the provider name, got %+v %v", estimate, ok)
+	}
+}
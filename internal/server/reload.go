@@ -0,0 +1,119 @@
+package server
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/http/handlers"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// configLoad re-reads configuration on reload; tests swap it.
+var configLoad = config.Load
+
+// Settings a reload applies live, by config.Changed path.
+var (
+	reloadSchedule  = []string{"PollInterval", "PollLiveInterval", "PollPreGameInterval", "PollIdleInterval"}
+	reloadRetention = []string{"Snapshots.RetentionDays", "Snapshots.TeamsDays", "Snapshots.PlayersDays"}
+	reloadLogLevel  = "LogLevel"
+	reloadRateLimit = "ProviderRateLimit"
+)
+
+type scheduleSetter interface {
+	SetSchedule(poller.Config)
+}
+
+// reloader applies re-read config to the running components. cfg is the
+// config currently in effect: the startup config with every applied reload
+// on top, so a skipped setting keeps being reported until a restart.
+type reloader struct {
+	mu     sync.Mutex
+	cfg    config.Config
+	poller scheduleSetter // nil when the poller cannot be rescheduled
+	writer *snapshots.Writer
+	level  *slog.LevelVar // nil until SetLogLevel
+	// limiter is the provider chain's rate limiter; nil when it has none.
+	limiter providers.RateLimitSetter
+	logger  *slog.Logger
+}
+
+func newReloader(cfg config.Config, plr Poller, writer *snapshots.Writer, provider providers.GameProvider, logger *slog.Logger) *reloader {
+	r := &reloader{cfg: cfg, writer: writer, logger: logger}
+	if s, ok := plr.(scheduleSetter); ok {
+		r.poller = s
+	}
+	if l, ok := providers.As[providers.RateLimitSetter](provider); ok {
+		r.limiter = l
+	}
+	return r
+}
+
+func (r *reloader) setLevel(level *slog.LevelVar) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.level = level
+}
+
+// reload re-reads env and CONFIG_FILE. An invalid config applies nothing;
+// otherwise each changed setting is applied if reloadable and reported either
+// way.
+func (r *reloader) reload() (handlers.ConfigReload, error) {
+	next := configLoad()
+	if err := next.Validate(); err != nil {
+		return handlers.ConfigReload{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := handlers.ConfigReload{Applied: []string{}, Skipped: []string{}, Warnings: next.Fallbacks()}
+	var schedule, retention bool
+	for _, field := range config.Changed(r.cfg, next) {
+		switch {
+		case slices.Contains(reloadSchedule, field) && r.poller != nil:
+			schedule = true
+		case slices.Contains(reloadRetention, field) && r.writer != nil:
+			retention = true
+		case field == reloadLogLevel && r.level != nil:
+			r.level.Set(logging.ParseLevel(next.LogLevel))
+			r.cfg.LogLevel = next.LogLevel
+		case field == reloadRateLimit && r.limiter != nil:
+			r.limiter.SetInterval(time.Duration(next.ProviderRateLimit))
+			r.cfg.ProviderRateLimit = next.ProviderRateLimit
+		default:
+			result.Skipped = append(result.Skipped, field)
+			continue
+		}
+		result.Applied = append(result.Applied, field)
+	}
+
+	if schedule {
+		r.cfg.PollInterval = next.PollInterval
+		r.cfg.PollLiveInterval = next.PollLiveInterval
+		r.cfg.PollPreGameInterval = next.PollPreGameInterval
+		r.cfg.PollIdleInterval = next.PollIdleInterval
+		r.poller.SetSchedule(poller.Config{
+			Interval:        r.cfg.PollInterval,
+			LiveInterval:    r.cfg.PollLiveInterval,
+			PreGameInterval: r.cfg.PollPreGameInterval,
+			IdleInterval:    r.cfg.PollIdleInterval,
+		})
+	}
+	if retention {
+		r.cfg.Snapshots.RetentionDays = next.Snapshots.RetentionDays
+		r.cfg.Snapshots.TeamsDays = next.Snapshots.TeamsDays
+		r.cfg.Snapshots.PlayersDays = next.Snapshots.PlayersDays
+		r.writer.SetRetention(snapshots.RetentionConfig{
+			GamesDays:   r.cfg.Snapshots.RetentionDays,
+			TeamsDays:   r.cfg.Snapshots.TeamsDays,
+			PlayersDays: r.cfg.Snapshots.PlayersDays,
+		})
+	}
+	logging.Info(r.logger, "config reloaded", "applied", result.Applied, "skipped", result.Skipped)
+	return result, nil
+}
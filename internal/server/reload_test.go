@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func reloadConfig() config.Config {
+	return config.Config{
+		Port:         "4000",
+		Provider:     "fixture",
+		PollInterval: time.Hour,
+		LogLevel:     "info",
+		Snapshots:    config.SnapshotSyncConfig{DailyHourUTC: 2, RetentionDays: 30},
+	}
+}
+
+func stubConfigLoad(t *testing.T, cfg config.Config) {
+	t.Helper()
+	prev := configLoad
+	configLoad = func() config.Config { return cfg }
+	t.Cleanup(func() { configLoad = prev })
+}
+
+func TestReloadChangesPollIntervalAndKeepsSnapshots(t *testing.T) {
+	// Writer pruning runs on the wall clock, so poll "today" for real.
+	clk := teststubs.NewFakeClock(time.Now().UTC())
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	provider := &teststubs.StubProvider{Games: []domaingames.Game{{ID: "g1", StatusKind: domaingames.StatusFinal}}}
+	cfg := reloadConfig()
+	plr := poller.NewWithConfig(provider, writer, nil, nil, poller.Config{Interval: cfg.PollInterval, Clock: clk}, nil)
+	plr.Start(context.Background())
+	defer func() { _ = plr.Stop(context.Background()) }()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected poller to arm its timer")
+	}
+
+	next := cfg
+	next.PollInterval = 5 * time.Minute
+	next.Snapshots.RetentionDays = 7
+	stubConfigLoad(t, next)
+	result, err := newReloader(cfg, plr, writer, nil, nil).reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !slices.Equal(result.Applied, []string{"PollInterval", "Snapshots.RetentionDays"}) || len(result.Skipped) != 0 {
+		t.Fatalf("unexpected reload result %+v", result)
+	}
+	teststubs.WaitFor(t, time.Second, func() bool {
+		return plr.Status().CurrentInterval == 5*time.Minute
+	}, "expected poller on the new interval")
+	if calls := provider.Calls.Load(); calls != 1 {
+		t.Fatalf("expected reload not to refetch, got %d calls", calls)
+	}
+
+	m, err := writer.Manifest()
+	if err != nil || len(m.Games.Dates) != 1 {
+		t.Fatalf("expected the polled snapshot kept, got %+v (%v)", m.Games.Dates, err)
+	}
+	if err := writer.WriteGamesSnapshot(m.Games.Dates[0], domaingames.NewTodayResponse(m.Games.Dates[0], provider.Games)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if m, _ = writer.Manifest(); m.Retention.GamesDays != 7 {
+		t.Fatalf("expected new retention on the next write, got %d", m.Retention.GamesDays)
+	}
+}
+
+func TestReloadChangesLogLevel(t *testing.T) {
+	level := new(slog.LevelVar)
+	logger := logging.NewLogger(logging.Config{Level: "info", LevelVar: level})
+	r := newReloader(reloadConfig(), nil, nil, nil, logger)
+	r.setLevel(level)
+
+	next := reloadConfig()
+	next.LogLevel = "debug"
+	stubConfigLoad(t, next)
+	result, err := r.reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !slices.Equal(result.Applied, []string{"LogLevel"}) {
+		t.Fatalf("expected LogLevel applied, got %+v", result)
+	}
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatalf("expected debug logging after reload")
+	}
+}
+
+func TestReloadReportsAndIgnoresNonReloadableChanges(t *testing.T) {
+	cfg := reloadConfig()
+	r := newReloader(cfg, nil, nil, nil, nil)
+
+	next := cfg
+	next.Port = "5000"
+	next.PollInterval = time.Minute // no poller to reschedule
+	next.LogLevel = "debug"         // no level to change
+	stubConfigLoad(t, next)
+	for i := 0; i < 2; i++ {
+		result, err := r.reload()
+		if err != nil {
+			t.Fatalf("reload: %v", err)
+		}
+		if len(result.Applied) != 0 || !slices.Equal(result.Skipped, []string{"Port", "PollInterval", "LogLevel"}) {
+			t.Fatalf("reload %d: expected every change skipped, got %+v", i, result)
+		}
+	}
+	if r.cfg.Port != "4000" {
+		t.Fatalf("expected skipped settings left as started, got port %s", r.cfg.Port)
+	}
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	writer := snapshots.NewWriter(t.TempDir(), 30)
+	r := newReloader(reloadConfig(), nil, writer, nil, nil)
+
+	next := reloadConfig()
+	next.Provider = "espn"
+	next.Snapshots.RetentionDays = 1
+	stubConfigLoad(t, next)
+	if _, err := r.reload(); err == nil {
+		t.Fatalf("expected invalid config rejected")
+	}
+	if r.cfg.Snapshots.RetentionDays != 30 {
+		t.Fatalf("expected nothing applied, got retention %d", r.cfg.Snapshots.RetentionDays)
+	}
+}
+
+func TestReloadChangesProviderRateLimit(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	cfg := reloadConfig()
+	cfg.ProviderRateLimit = time.Hour
+	provider := providerFactory{clock: clk}.build(cfg)
+	if _, err := provider.FetchGames(context.Background(), "2024-01-15", ""); err != nil {
+		t.Fatalf("expected the first fetch through, got %v", err)
+	}
+
+	next := cfg
+	next.ProviderRateLimit = time.Minute
+	stubConfigLoad(t, next)
+	result, err := newReloader(cfg, nil, nil, provider, nil).reload()
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !slices.Equal(result.Applied, []string{"ProviderRateLimit"}) || len(result.Skipped) != 0 {
+		t.Fatalf("unexpected reload result %+v", result)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.FetchGames(context.Background(), "2024-01-15", "")
+		done <- err
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the next fetch to wait on the limiter")
+	}
+	clk.Advance(time.Minute)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("fetch: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the fetch released after the reloaded interval")
+	}
+}
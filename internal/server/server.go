@@ -4,8 +4,10 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/config"
 	httpserver "github.com/preston-bernstein/nba-data-service/internal/http"
 	"github.com/preston-bernstein/nba-data-service/internal/http/handlers"
@@ -14,11 +16,14 @@ import (
 	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/poller"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
-	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+	"github.com/preston-bernstein/nba-data-service/internal/webhook"
 )
 
 var metricsSetup = metrics.Setup
 
+// serverClock is the single time source handed to every component the server wires.
+var serverClock = clock.Real()
+
 type Server struct {
 	cfg           config.Config
 	logger        *slog.Logger
@@ -26,14 +31,17 @@ type Server struct {
 	httpServer    httpServer
 	metricsServer httpServer
 	poller        Poller
+	webhook       *webhook.Sender
 	metricsStop   func(context.Context) error
+	// draining is set when shutdown starts; /ready fails from then on.
+	draining *atomic.Bool
+	reloader *reloader
+	warm     warmStart // snapshots pre-loaded before the first poll
 }
 
 // New constructs a server with default provider and poller wiring.
 func New(cfg config.Config, logger *slog.Logger) *Server {
-	factory := newProviderFactory(logger, nil)
-	provider := factory.build(cfg)
-	return newServerWithProvider(cfg, logger, provider)
+	return newServerWithMetrics(cfg, logger, nil, nil)
 }
 
 func newServerWithProvider(cfg config.Config, logger *slog.Logger, provider providers.GameProvider) *Server {
@@ -43,15 +51,46 @@ func newServerWithProvider(cfg config.Config, logger *slog.Logger, provider prov
 func newServerWithMetrics(cfg config.Config, logger *slog.Logger, provider providers.GameProvider, recorder *metrics.Recorder) *Server {
 	recorder, metricsSrv, metricsShutdown := buildMetrics(cfg, logger, recorder)
 
+	clk := serverClock
+	recorder.SetClock(clk)
+	// One budget for every retry wrapper, so the poller, syncer and handlers
+	// together stay within it during an outage.
+	factory := newProviderFactory(logger, recorder, newRetryBudget(cfg.RetryBudget, clk))
 	if provider == nil {
-		provider = newProviderFactory(logger, recorder).build(cfg)
+		provider = factory.build(cfg)
 	} else {
-		provider = providers.NewRetryingProvider(provider, logger, recorder, normalizeProviderName(cfg.Provider, provider), 0, 0)
+		provider = factory.retry(provider, normalizeProviderName(cfg.Provider, provider))
+	}
+	loc := cfg.Location()
+	snaps := buildSnapshots(cfg, provider, logger, recorder, loc, clk)
+	plr := poller.NewWithConfig(provider, snaps.writer, logger, recorder, poller.Config{
+		Interval:             cfg.PollInterval,
+		LiveInterval:         cfg.PollLiveInterval,
+		PreGameInterval:      cfg.PollPreGameInterval,
+		IdleInterval:         cfg.PollIdleInterval,
+		StartJitter:          cfg.PollJitter,
+		ReplaceGames:         cfg.PollReplaceGames,
+		AllowFinalRegression: cfg.AllowRegression,
+		FetchTimeout:         cfg.PollFetchTimeout,
+		Clock:                clk,
+	}, loc)
+	hook := webhook.New(webhook.Config{
+		URL:         cfg.Webhook.URL,
+		Secret:      cfg.Webhook.Secret,
+		MaxAttempts: cfg.Webhook.MaxAttempts,
+		Timeout:     cfg.Webhook.Timeout,
+		Clock:       clk,
+	}, logger, recorder)
+	if hook != nil {
+		plr.OnChange(hook.Notify)
 	}
-	loc := timeutil.ResolveLocation(cfg.Balldontlie.Timezone)
-	snaps := buildSnapshots(cfg, provider, logger, loc)
-	plr := poller.New(provider, snaps.writer, logger, recorder, cfg.PollInterval, loc)
-	httpSrv := buildHTTPServer(cfg, logger, provider, recorder, plr, snaps, loc)
+	warm := warmGames(snaps.store, plr, loc, clk, logger, recorder)
+	recorder.ObserveNextRun("poller", plr.NextRun)
+	recorder.ObserveNextRun("snapshot_sync", snaps.syncer.NextRun)
+	recorder.RegisterObservables(todayGames{store: snaps.store, loc: loc, clk: clk}, snaps.writer)
+	draining := new(atomic.Bool)
+	reload := newReloader(cfg, plr, snaps.writer, provider, logger)
+	httpSrv := buildHTTPServer(cfg, logger, provider, recorder, plr, snaps, loc, clk, draining.Load, reload.reload)
 
 	return &Server{
 		cfg:           cfg,
@@ -60,7 +99,11 @@ func newServerWithMetrics(cfg config.Config, logger *slog.Logger, provider provi
 		httpServer:    httpSrv,
 		metricsServer: metricsSrv,
 		poller:        plr,
+		webhook:       hook,
 		metricsStop:   metricsShutdown,
+		draining:      draining,
+		reloader:      reload,
+		warm:          warm,
 	}
 }
 
@@ -71,28 +114,72 @@ func newServerWithDeps(cfg config.Config, logger *slog.Logger, httpSrv httpServe
 		logger:     logger,
 		httpServer: httpSrv,
 		poller:     plr,
+		draining:   new(atomic.Bool),
 	}
 }
 
-func buildHTTPServer(cfg config.Config, logger *slog.Logger, provider providers.GameProvider, recorder *metrics.Recorder, plr Poller, snaps snapshotComponents, loc *time.Location) httpServer {
+func buildHTTPServer(cfg config.Config, logger *slog.Logger, provider providers.GameProvider, recorder *metrics.Recorder, plr Poller, snaps snapshotComponents, loc *time.Location, clk clock.Clock, draining func() bool, reload func() (handlers.ConfigReload, error)) httpServer {
 	var statusFn func() poller.Status
 	if plr != nil {
 		statusFn = plr.Status
 	}
 
-	handler := handlers.NewHandler(snaps.store, logger, statusFn, loc)
-	admin := handlers.NewAdminHandler(snaps.writer, provider, cfg.Snapshots.AdminToken, logger)
-	router := httpserver.NewRouter(handler)
-	// Optionally mount admin refresh endpoint if token is set.
+	handler := handlers.NewHandlerWithClock(snaps.store, logger, statusFn, loc, clk)
+	handler.SetRecorder(recorder)
+	handler.SetSnapshotDir(cfg.Snapshots.SnapshotFolder)
+	handler.SetDrainCheck(draining)
+	handler.RegisterStatus("provider", providerStatus(normalizeProviderName(cfg.Provider, provider), provider))
+	handler.RegisterStatus("providerMetrics", providerMetricsStatus(recorder))
+	clients := middleware.NewClientTracker(cfg.ClientNames, 0, clk)
+	handler.RegisterStatus("clients", clients.Status)
+	stream := handlers.NewStreamHandler(snaps.store, logger, cfg.StreamMax, loc, clk)
+	stream.SetRecorder(recorder)
+	handler.RegisterStatus("streams", stream.Status)
+	if snaps.skew != nil {
+		handler.RegisterStatus("clock", func() any { return snaps.skew.Status() })
+	}
+	handler.RegisterStatus("sync", syncStatus(snaps.syncer))
+	hydration := snaps.hydration
+	handler.RegisterStatus("hydration", func() any { return hydration })
+	standings := handlers.NewStandingsHandler(snaps.writer, snaps.store, logger)
+	history := handlers.NewHistoryHandler(snaps.store, logger, loc, clk)
+	if sub, ok := plr.(interface {
+		OnChange(func(poller.GameChangeEvent))
+	}); ok {
+		sub.OnChange(stream.Publish)
+		sub.OnChange(standings.Observe)
+		sub.OnChange(history.Observe)
+	}
+	admin := handlers.NewAdminHandlerWithClock(snaps.writer, provider, cfg.Snapshots.AdminToken, logger, cfg.Snapshots.AdminTimeout, clk)
+	admin.SetConfigReload(reload)
+	// Read endpoints are bounded by HANDLER_TIMEOUT; the stream and admin
+	// refreshes run on their own clocks.
+	bounded := func(next http.Handler) http.Handler {
+		return handlers.WithTimeout(time.Duration(cfg.HandlerTimeout), logger, next)
+	}
+	router := httpserver.NewRouter(bounded(handler))
+	router.HandleGetOnly("GET /games/stream", stream)
+	router.Handle("GET /games/{id}/history", bounded(history))
+	router.Handle("GET /standings", bounded(standings))
+	headToHead := bounded(handlers.NewHeadToHeadHandler(snaps.writer, snaps.store, logger, clk))
+	// The exact pattern labels metrics; the prefix keeps malformed ids on the handler's JSON 400.
+	router.Handle("GET /teams/{id}/vs/{otherId}", headToHead)
+	router.Handle("GET /teams/", headToHead)
+	// Optionally mount admin endpoints if a token is set.
 	if admin != nil && cfg.Snapshots.AdminToken != "" {
-		if mux, ok := router.(*http.ServeMux); ok {
-			mux.HandleFunc("/admin/snapshots/refresh", admin.RefreshSnapshots)
-		}
+		admin.Register(router)
 	}
 	if logger == nil {
 		logger = logging.NewLogger(logging.Config{})
 	}
-	wrapped := middleware.LoggingMiddleware(logger, recorder, router)
+	keys := handlers.NewAPIKeyAuth(apiKeyConfig(cfg.APIKeys, clk), recorder, logger)
+	wrapped := middleware.LoggingMiddlewareWithOptions(logger, recorder, middleware.LoggingOptions{
+		Clients:           clients,
+		SampleRate:        cfg.LogSampleRate,
+		SlowThreshold:     time.Duration(cfg.LogSlowRequest),
+		Clock:             clk,
+		RedactQueryParams: cfg.LogRedactParams,
+	}, keys.Protect(router, "/games", "/teams/"))
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.Port,
@@ -105,10 +192,37 @@ func buildHTTPServer(cfg config.Config, logger *slog.Logger, provider providers.
 	return netHTTPServer{srv: srv}
 }
 
+func apiKeyConfig(cfg config.APIKeysConfig, clk clock.Clock) handlers.APIKeyConfig {
+	keys := make([]handlers.APIKey, 0, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		keys = append(keys, handlers.APIKey{Key: k.Key, Name: k.Name, RPM: k.RPM})
+	}
+	return handlers.APIKeyConfig{Keys: keys, Required: cfg.Required, Clock: clk}
+}
+
+// SetLogLevel hands the server the level behind its logger, so a reload can
+// change LOG_LEVEL live. Without it LogLevel changes are skipped.
+func (s *Server) SetLogLevel(level *slog.LevelVar) {
+	if s.reloader != nil {
+		s.reloader.setLevel(level)
+	}
+}
+
+// Reload re-reads env and CONFIG_FILE and applies the settings that can change
+// without a restart: poll intervals, snapshot retention and LOG_LEVEL. Other
+// changed settings are reported as skipped.
+func (s *Server) Reload() (handlers.ConfigReload, error) {
+	if s.reloader == nil {
+		return handlers.ConfigReload{Applied: []string{}, Skipped: []string{}}, nil
+	}
+	return s.reloader.reload()
+}
+
 // Run starts the poller and HTTP server, then waits for context cancellation to shut down gracefully.
 func (s *Server) Run(ctx context.Context, stop context.CancelFunc) {
 	s.startMetrics()
 	s.startServer(stop)
+	s.webhook.Start(ctx)
 	s.poller.Start(ctx)
 
 	<-ctx.Done()
@@ -121,7 +235,7 @@ func (s *Server) Run(ctx context.Context, stop context.CancelFunc) {
 
 func (s *Server) startServer(stop context.CancelFunc) {
 	if s.logger != nil {
-		s.logger.Info("http server starting", slog.String("addr", s.httpServer.Addr()))
+		s.logger.Info("http server starting", append([]any{slog.String("addr", s.httpServer.Addr())}, s.warm.attrs()...)...)
 	}
 	launchServer("http", s.httpServer, s.logger, func(err error) {
 		if stop != nil {
@@ -140,8 +254,22 @@ func (s *Server) startMetrics() {
 	launchServer("metrics", s.metricsServer, s.logger, nil)
 }
 
+// gracefulShutdown drains before stopping: /ready starts failing, the
+// pre-stop delay gives load balancers time to notice, and only then are
+// listeners closed. The delay and Shutdown share shutdownTimeout; the delay
+// takes at most half, and connections still open at the end are force-closed.
 func (s *Server) gracefulShutdown() {
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	start := serverClock.Now()
+	if s.draining != nil {
+		s.draining.Store(true)
+	}
+	if delay := min(time.Duration(s.cfg.ShutdownPreStop), shutdownTimeout/2); delay > 0 {
+		if s.logger != nil {
+			s.logger.Info("draining before shutdown", "delay_ms", delay.Milliseconds())
+		}
+		serverClock.Sleep(delay)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout-serverClock.Now().Sub(start))
 	defer cancel()
 
 	if s.metricsStop != nil {
@@ -160,11 +288,18 @@ func (s *Server) gracefulShutdown() {
 		s.logger.Error("failed to stop poller", "error", err)
 	}
 
-	if err := s.httpServer.Shutdown(shutdownCtx); err != nil && s.logger != nil {
-		s.logger.Error("graceful shutdown failed", "error", err)
+	if err := s.webhook.Stop(shutdownCtx); err != nil && s.logger != nil {
+		s.logger.Warn("webhook sender shutdown failed", "error", err)
 	}
 
-	// Stop rate-limited providers to avoid ticker leaks when present.
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		if s.logger != nil {
+			s.logger.Error("graceful shutdown failed; force-closing connections", "error", err)
+		}
+		_ = s.httpServer.Close()
+	}
+
+	// Release providers that hold background resources.
 	if rl, ok := s.pollerProvider().(interface{ Close() }); ok {
 		rl.Close()
 	}
@@ -196,6 +331,8 @@ func buildMetrics(cfg config.Config, logger *slog.Logger, recorder *metrics.Reco
 		ServiceName:  cfg.Metrics.ServiceName,
 		OtlpEndpoint: cfg.Metrics.OtlpEndpoint,
 		OtlpInsecure: cfg.Metrics.OtlpInsecure,
+
+		LatencyBuckets: cfg.Metrics.LatencyBuckets,
 	}
 
 	rec, handler, shutdown, err := metricsSetup(context.Background(), recCfg)
@@ -203,8 +340,9 @@ func buildMetrics(cfg config.Config, logger *slog.Logger, recorder *metrics.Reco
 		if logger != nil {
 			logger.Warn("metrics setup failed, continuing without telemetry", "err", err)
 		}
-		return metrics.NewRecorder(), nil, nil
+		return metrics.NewRecorderWithLimit(cfg.Metrics.MaxProviders, logger), nil, nil
 	}
+	rec.SetProviderLimit(cfg.Metrics.MaxProviders, logger)
 
 	var metricsSrv httpServer
 	if handler != nil && recCfg.Enabled {
@@ -3,11 +3,14 @@ package server
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -19,6 +22,8 @@ import (
 	"github.com/preston-bernstein/nba-data-service/internal/poller"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/providers/balldontlie"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/replay"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 	"github.com/preston-bernstein/nba-data-service/internal/testutil"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
@@ -37,15 +42,16 @@ func TestServerServesHealthAndGames(t *testing.T) {
 
 	// Snapshot folder must exist for poller to write snapshots.
 	snapshotFolder := t.TempDir()
+	// One poll and no background sync: either could still be writing when TempDir is removed.
 	cfg := config.Config{
-		PollInterval: 5 * time.Millisecond,
+		PollInterval: time.Hour,
 		Snapshots: config.SnapshotSyncConfig{
-			Enabled:        true,
 			SnapshotFolder: snapshotFolder,
 		},
 	}
 	srv := newServerWithProvider(cfg, nil, provider)
 	srv.poller.Start(ctx)
+	defer func() { _ = srv.poller.Stop(context.Background()) }()
 
 	select {
 	case <-provider.Notify:
@@ -53,15 +59,18 @@ func TestServerServesHealthAndGames(t *testing.T) {
 		t.Fatal("timed out waiting for poller to fetch")
 	}
 
-	// Wait a brief moment for the snapshot to be written.
-	time.Sleep(20 * time.Millisecond)
+	today := timeutil.FormatDate(time.Now().UTC())
+	// The manifest is written last, after the snapshot file.
+	testutil.WaitFor(t, 2*time.Second, func() bool {
+		m, err := os.ReadFile(filepath.Join(snapshotFolder, "manifest.json"))
+		return err == nil && strings.Contains(string(m), today)
+	}, "expected poller to write today's snapshot")
 
 	router := srv.Handler()
 
 	healthRec := testutil.Serve(router, http.MethodGet, "/health", nil)
 	testutil.AssertStatus(t, healthRec, http.StatusOK)
 
-	today := timeutil.FormatDate(time.Now().UTC())
 	gameRec := testutil.Serve(router, http.MethodGet, "/games?date="+today, nil)
 	testutil.AssertStatus(t, gameRec, http.StatusOK)
 
@@ -75,7 +84,7 @@ func TestServerServesHealthAndGames(t *testing.T) {
 
 func TestProviderFactoryWrapsProvider(t *testing.T) {
 	cfg := config.Config{Provider: "fixture"}
-	factory := newProviderFactory(nil, metrics.NewRecorder())
+	factory := newProviderFactory(nil, metrics.NewRecorder(), nil)
 	provider := factory.build(cfg)
 	if provider == nil {
 		t.Fatalf("expected provider")
@@ -83,7 +92,7 @@ func TestProviderFactoryWrapsProvider(t *testing.T) {
 }
 
 func TestSelectProviderFallsBackToFixture(t *testing.T) {
-	provider := selectProvider(config.Config{Provider: "unknown"}, nil)
+	provider := selectProvider(config.Config{Provider: "unknown"}, nil, nil, nil, nil)
 	if provider == nil {
 		t.Fatalf("expected provider fallback")
 	}
@@ -96,21 +105,28 @@ func TestSelectProviderChoosesBalldontlie(t *testing.T) {
 			BaseURL: "http://example.com",
 			APIKey:  "key",
 		},
-	}, nil)
+	}, nil, nil, nil, nil)
 	if _, ok := provider.(*balldontlie.Client); !ok {
 		t.Fatalf("expected balldontlie provider")
 	}
 }
 
+func TestSelectProviderChoosesReplay(t *testing.T) {
+	provider := selectProvider(config.Config{Provider: "replay", ReplayDir: t.TempDir()}, nil, nil, nil, nil)
+	if _, ok := provider.(*replay.Client); !ok {
+		t.Fatalf("expected replay provider, got %T", provider)
+	}
+}
+
 func TestSelectProviderDefaultsToFixture(t *testing.T) {
-	provider := selectProvider(config.Config{}, nil)
+	provider := selectProvider(config.Config{}, nil, nil, nil, nil)
 	if provider == nil {
 		t.Fatalf("expected provider")
 	}
 }
 
 func TestSelectProviderFixtureExplicit(t *testing.T) {
-	provider := selectProvider(config.Config{Provider: "fixture"}, nil)
+	provider := selectProvider(config.Config{Provider: "fixture"}, nil, nil, nil, nil)
 	if provider == nil {
 		t.Fatalf("expected fixture provider")
 	}
@@ -130,11 +146,27 @@ func TestNewConstructsServer(t *testing.T) {
 	}
 }
 
+func TestWebhookWiredOnlyWhenURLSet(t *testing.T) {
+	cfg := config.Config{Port: "0", Provider: "fixture"}
+	if srv := New(cfg, nil); srv.webhook != nil {
+		t.Fatalf("expected no webhook sender without WEBHOOK_URL")
+	}
+	cfg.Webhook = config.WebhookConfig{URL: "http://127.0.0.1:0/hook", Secret: "s"}
+	srv := New(cfg, nil)
+	if srv.webhook == nil {
+		t.Fatalf("expected webhook sender when URL configured")
+	}
+	srv.webhook.Start(context.Background())
+	if err := srv.webhook.Stop(context.Background()); err != nil {
+		t.Fatalf("expected webhook stop to succeed, got %v", err)
+	}
+}
+
 func TestNormalizeProviderName(t *testing.T) {
 	if got := normalizeProviderName("Balldontlie", nil); got != "balldontlie" {
 		t.Fatalf("expected lowercase raw, got %s", got)
 	}
-	provider := selectProvider(config.Config{Provider: "fixture"}, nil)
+	provider := selectProvider(config.Config{Provider: "fixture"}, nil, nil, nil, nil)
 	if got := normalizeProviderName("", provider); got == "" || got == "provider" {
 		t.Fatalf("expected derived provider name, got %s", got)
 	}
@@ -181,7 +213,7 @@ func TestLaunchServerSuccessDoesNotCallOnError(t *testing.T) {
 	launchServer("http", stub, nil, func(err error) {
 		called <- err
 	})
-	time.Sleep(10 * time.Millisecond)
+	testutil.WaitFor(t, time.Second, func() bool { return stub.ListenCalls.Load() == 1 }, "expected server to be launched")
 	select {
 	case err := <-called:
 		t.Fatalf("did not expect onError, got %v", err)
@@ -207,12 +239,11 @@ func TestLaunchServerLogsStartAndWarnsOnError(t *testing.T) {
 }
 
 func TestLaunchServerIgnoresErrServerClosed(t *testing.T) {
-	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger, buf := testutil.NewSyncBufferLogger()
 	stub := &testutil.CloseableHTTPServer{}
 	called := make(chan struct{}, 1)
 	launchServer("http", stub, logger, func(err error) { called <- struct{}{} })
-	time.Sleep(20 * time.Millisecond)
+	testutil.WaitFor(t, time.Second, func() bool { return strings.Contains(buf.String(), "starting http server") }, "expected start log")
 	select {
 	case <-called:
 		t.Fatalf("did not expect onError for ErrServerClosed")
@@ -234,25 +265,18 @@ func TestStartMetricsUsesServer(t *testing.T) {
 		metricsServer: stub,
 	}
 	s.startMetrics()
-	time.Sleep(10 * time.Millisecond)
-	if stub.ListenCalls == 0 {
-		t.Fatalf("expected metrics server to start")
-	}
+	testutil.WaitFor(t, time.Second, func() bool { return stub.ListenCalls.Load() > 0 }, "expected metrics server to start")
 }
 
 func TestStartMetricsLogsWhenLoggerPresent(t *testing.T) {
 	stub := &testutil.StubHTTPServer{AddrVal: "addr", ListenErr: http.ErrServerClosed}
-	var buf bytes.Buffer
-	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	logger, buf := testutil.NewSyncBufferLogger()
 	s := &Server{
 		logger:        logger,
 		metricsServer: stub,
 	}
 	s.startMetrics()
-	time.Sleep(10 * time.Millisecond)
-	if !strings.Contains(buf.String(), "metrics server starting") {
-		t.Fatalf("expected metrics start log, got %s", buf.String())
-	}
+	testutil.WaitFor(t, time.Second, func() bool { return strings.Contains(buf.String(), "metrics server starting") }, "expected metrics start log, got %s", buf)
 }
 
 func TestGracefulShutdownStopsAll(t *testing.T) {
@@ -316,8 +340,7 @@ func TestServerHandlesProviderErrorGracefully(t *testing.T) {
 	srv := newServerWithProvider(cfg, nil, &testutil.ErrProvider{Err: context.DeadlineExceeded})
 	srv.poller.Start(ctx)
 
-	// Give the poller a moment to attempt a fetch.
-	time.Sleep(20 * time.Millisecond)
+	testutil.WaitFor(t, 2*time.Second, func() bool { return srv.poller.Status().ConsecutiveFailures > 0 }, "expected poller to record a failed fetch")
 
 	router := srv.Handler()
 	gameRec := testutil.Serve(router, http.MethodGet, "/games/unknown", nil)
@@ -367,6 +390,83 @@ func TestGracefulShutdownTimesOutLongRunningShutdown(t *testing.T) {
 	if elapsed > 200*time.Millisecond {
 		t.Fatalf("shutdown took too long: %s", elapsed)
 	}
+	if blocking.CloseCalls != 1 {
+		t.Fatalf("expected force-close after Shutdown timed out, got %d", blocking.CloseCalls)
+	}
+}
+
+func TestGracefulShutdownDrainsBeforeClosingListeners(t *testing.T) {
+	const delay = 5 * time.Second
+	cfg := config.Config{
+		PollInterval:    time.Hour,
+		ShutdownPreStop: config.Duration(delay),
+		Snapshots:       config.SnapshotSyncConfig{SnapshotFolder: t.TempDir()},
+	}
+	srv := newServerWithProvider(cfg, nil, testutil.EmptyProvider{})
+	handler := srv.Handler()
+
+	health := func() string {
+		var body map[string]string
+		testutil.DecodeJSON(t, testutil.Serve(handler, http.MethodGet, "/health", nil), &body)
+		return body["status"]
+	}
+	if got := health(); got != "ok" {
+		t.Fatalf("expected ok before shutdown, got %q", got)
+	}
+
+	// Swapped in after wiring so the pre-stop sleep is the only timer on it.
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	prevClock := serverClock
+	serverClock = clk
+	t.Cleanup(func() { serverClock = prevClock })
+
+	var readyCode int
+	var healthStatus string
+	var waited time.Duration
+	start := clk.Now()
+	stub := &testutil.StubHTTPServer{HandlerVal: handler, OnShutdown: func() {
+		waited = clk.Now().Sub(start)
+		readyCode = testutil.Serve(handler, http.MethodGet, "/ready", nil).Code
+		healthStatus = health()
+	}}
+	srv.httpServer = stub
+	done := make(chan struct{})
+	go func() {
+		srv.gracefulShutdown()
+		close(done)
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected the pre-stop delay to sleep on the server clock")
+	}
+	clk.Advance(delay)
+	<-done
+
+	if stub.ShutdownCalls != 1 {
+		t.Fatalf("expected Shutdown once, got %d", stub.ShutdownCalls)
+	}
+	if readyCode != http.StatusServiceUnavailable || healthStatus != "draining" {
+		t.Fatalf("expected /ready 503 and /health draining before Shutdown, got %d %q", readyCode, healthStatus)
+	}
+	if waited < delay {
+		t.Fatalf("expected Shutdown after the %s pre-stop delay, called after %s", delay, waited)
+	}
+	if stub.CloseCalls != 0 {
+		t.Fatalf("expected no force-close after a clean Shutdown")
+	}
+}
+
+func TestGracefulShutdownSkipsZeroPreStopDelay(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	prevClock := serverClock
+	serverClock = clk
+	t.Cleanup(func() { serverClock = prevClock })
+
+	httpSrv := &testutil.StubHTTPServer{}
+	srv := newServerWithDeps(config.Config{ShutdownPreStop: 0}, nil, httpSrv, &testutil.StubPoller{})
+	srv.gracefulShutdown() // would block on the fake clock if it slept
+	if httpSrv.ShutdownCalls != 1 || clk.ActiveTimers() != 0 {
+		t.Fatalf("expected an immediate Shutdown, got %d calls and %d timers", httpSrv.ShutdownCalls, clk.ActiveTimers())
+	}
 }
 
 func TestGracefulShutdownContinuesWhenPollerStopErrors(t *testing.T) {
@@ -451,11 +551,126 @@ func TestServerHandlerSetsRequestIDHeader(t *testing.T) {
 	}
 }
 
+// The only request-ID context key lives in the middleware package; every
+// route's error writer must see the ID it generated, not fall back to a header.
+func TestErrorBodiesCarryMiddlewareRequestIDOnAllRoutes(t *testing.T) {
+	cfg := config.Config{
+		Port:     "0",
+		Provider: "fixture",
+		Snapshots: config.SnapshotSyncConfig{
+			SnapshotFolder: t.TempDir(),
+			AdminToken:     "secret",
+		},
+	}
+	handler := New(cfg, nil).Handler()
+
+	cases := []struct {
+		method, path string
+		admin        bool
+	}{
+		{http.MethodGet, "/games", false},
+		{http.MethodGet, "/games/missing", false},
+		{http.MethodGet, "/games/a/b", false},
+		{http.MethodGet, "/games/missing/history", false},
+		{http.MethodPost, "/games/stream", false},
+		{http.MethodPost, "/health", false},
+		{http.MethodGet, "/standings?unknown=1", false},
+		{http.MethodGet, "/teams/a/vs/a", false},
+		{http.MethodPost, "/admin/snapshots/refresh", false},
+		{http.MethodPost, "/admin/snapshots/refresh?date=bad", true},
+		{http.MethodGet, "/admin/snapshots/jobs/missing", true},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.admin {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code < 400 {
+			t.Fatalf("%s %s: expected error status, got %d", tc.method, tc.path, rr.Code)
+		}
+		id := rr.Header().Get("X-Request-ID")
+		if id == "" {
+			t.Fatalf("%s %s: expected generated X-Request-ID", tc.method, tc.path)
+		}
+		var body struct {
+			RequestID string `json:"requestId"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: expected JSON error body, got %q", tc.method, tc.path, rr.Body.String())
+		}
+		if body.RequestID != id {
+			t.Fatalf("%s %s: expected requestId %q, got %q", tc.method, tc.path, id, body.RequestID)
+		}
+	}
+}
+
+func TestStreamRouteMounted(t *testing.T) {
+	cfg := config.Config{
+		Port:     "0",
+		Provider: "fixture",
+		Snapshots: config.SnapshotSyncConfig{
+			SnapshotFolder: t.TempDir(),
+		},
+	}
+	srv := New(cfg, nil)
+	rr := httptest.NewRecorder()
+	// Non-GET is rejected by the stream handler rather than treated as a game ID.
+	req := httptest.NewRequest(http.MethodPost, "/games/stream", nil)
+	srv.Handler().ServeHTTP(rr, req)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected stream route mounted, got %d", rr.Code)
+	}
+}
+
+func TestStandingsRouteMounted(t *testing.T) {
+	cfg := config.Config{
+		Port:      "0",
+		Provider:  "fixture",
+		Snapshots: config.SnapshotSyncConfig{SnapshotFolder: t.TempDir()},
+	}
+	srv := New(cfg, nil)
+	rr := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/standings", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected standings route mounted, got %d", rr.Code)
+	}
+}
+
+func TestAPIKeysGuardGameRoutesOnly(t *testing.T) {
+	cfg := config.Config{
+		Port:      "0",
+		Provider:  "fixture",
+		Snapshots: config.SnapshotSyncConfig{SnapshotFolder: t.TempDir()},
+		APIKeys:   config.APIKeysConfig{Required: true, Keys: []config.APIKey{{Key: "k1", Name: "partner"}}},
+	}
+	srv := New(cfg, nil)
+	serve := func(path, key string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		rr := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rr, req)
+		return rr.Code
+	}
+	if code := serve("/games", ""); code != http.StatusUnauthorized {
+		t.Fatalf("expected /games to require a key, got %d", code)
+	}
+	if code := serve("/games", "k1"); code == http.StatusUnauthorized {
+		t.Fatalf("expected /games to accept a valid key, got %d", code)
+	}
+	if code := serve("/health", ""); code != http.StatusOK {
+		t.Fatalf("expected /health to stay open, got %d", code)
+	}
+}
+
 func TestAdminRouteMountedOnlyWithToken(t *testing.T) {
 	cfg := config.Config{
 		Port: "0",
 		Snapshots: config.SnapshotSyncConfig{
-			Enabled:        true,
 			SnapshotFolder: t.TempDir(),
 			AdminToken:     "secret",
 		},
@@ -468,6 +683,11 @@ func TestAdminRouteMountedOnlyWithToken(t *testing.T) {
 	if rr.Code != http.StatusUnauthorized {
 		t.Fatalf("expected admin route mounted with 401 without token, got %d", rr.Code)
 	}
+	rr = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected config reload mounted with 401 without token, got %d", rr.Code)
+	}
 
 	cfg.Snapshots.AdminToken = ""
 	srv = New(cfg, nil)
@@ -624,8 +844,8 @@ func TestRunCancelsAndStopsComponents(t *testing.T) {
 		close(done)
 	}()
 
-	// Let Start be invoked.
-	time.Sleep(10 * time.Millisecond)
+	// Run starts every component before it waits on ctx, so cancelling
+	// right away still exercises the full start/stop sequence.
 	cancel()
 
 	select {
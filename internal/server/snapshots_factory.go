@@ -5,36 +5,108 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	"github.com/preston-bernstein/nba-data-service/internal/config"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 type snapshotComponents struct {
 	store  snapshots.Store
 	writer *snapshots.Writer
 	syncer *snapshots.Syncer
+	skew   *snapshots.SkewChecker
+	// hydration is the startup scan of the snapshot root, kept for /status.
+	hydration snapshots.HydrationReport
 }
 
-func buildSnapshots(cfg config.Config, provider providers.GameProvider, logger *slog.Logger, loc *time.Location) snapshotComponents {
+func buildSnapshots(cfg config.Config, provider providers.GameProvider, logger *slog.Logger, recorder *metrics.Recorder, loc *time.Location, clk clock.Clock) snapshotComponents {
 	basePath := cfg.Snapshots.SnapshotFolder
-	writer := snapshots.NewWriter(basePath, cfg.Snapshots.RetentionDays)
-	store := snapshots.NewFSStore(basePath)
-
-	syncer := snapshots.NewSyncer(provider, writer, snapshots.SyncConfig{
-		Enabled:      cfg.Snapshots.Enabled,
-		Days:         cfg.Snapshots.Days,
-		FutureDays:   cfg.Snapshots.FutureDays,
-		Interval:     cfg.Snapshots.Interval,
-		DailyHourUTC: cfg.Snapshots.DailyHourUTC,
-	}, logger, loc)
+	writer := newSnapshotWriter(cfg, logger, loc, clk)
+	store := snapshots.NewFSStoreWithCache(basePath, cfg.Snapshots.CacheEntries)
+	store.SetClock(clk)
+	writer.SetWriteHook(store.ForgetMiss)
+	hydration := snapshots.Hydrate(basePath, clock.OrReal(clk).Now())
+	hydration.Log(logger)
+
+	var providerOffset func() (time.Duration, bool)
+	if reporter, ok := providers.As[providers.ClockOffsetReporter](provider); ok {
+		providerOffset = reporter.ServerClockOffset
+	}
+	skew := snapshots.NewSkewChecker(writer, providerOffset, snapshots.SkewConfig{
+		Threshold:  cfg.Snapshots.SkewThreshold,
+		FutureDays: cfg.Snapshots.FutureDays,
+		Clock:      clk,
+	}, logger)
+	// Check before the syncer's first write so a bad clock never prunes.
+	skew.Check()
+	go skew.Run(context.Background())
+
+	syncer := snapshots.NewSyncer(provider, writer, syncConfig(cfg, clk, recorder), logger, loc)
 	if cfg.Snapshots.Enabled {
 		go syncer.Run(context.Background())
 	}
 
 	return snapshotComponents{
-		store:  store,
-		writer: writer,
-		syncer: syncer,
+		store:     store,
+		writer:    writer,
+		syncer:    syncer,
+		skew:      skew,
+		hydration: hydration,
+	}
+}
+
+// newSnapshotWriter builds the games snapshot writer from cfg.
+func newSnapshotWriter(cfg config.Config, logger *slog.Logger, loc *time.Location, clk clock.Clock) *snapshots.Writer {
+	writer := snapshots.NewWriterWithRetention(cfg.Snapshots.SnapshotFolder, snapshots.RetentionConfig{
+		GamesDays:   cfg.Snapshots.RetentionDays,
+		TeamsDays:   cfg.Snapshots.TeamsDays,
+		PlayersDays: cfg.Snapshots.PlayersDays,
+	})
+	writer.SetLogger(logger)
+	writer.SetFreezeGrace(cfg.Snapshots.FreezeGrace)
+	writer.SetClock(clk)
+	writer.SetLocation(loc)
+	writer.SetHistory(snapshots.HistoryConfig{Enabled: cfg.Snapshots.History, RetentionDays: cfg.Snapshots.HistoryDays})
+	return writer
+}
+
+// syncConfig maps cfg's snapshot settings onto the syncer's.
+func syncConfig(cfg config.Config, clk clock.Clock, recorder *metrics.Recorder) snapshots.SyncConfig {
+	return snapshots.SyncConfig{
+		Enabled:        cfg.Snapshots.Enabled,
+		Days:           cfg.Snapshots.Days,
+		FutureDays:     cfg.Snapshots.FutureDays,
+		Interval:       cfg.Snapshots.Interval,
+		Concurrency:    cfg.Snapshots.Concurrency,
+		Zones:          cfg.Snapshots.Zones,
+		DailyHourUTC:   cfg.Snapshots.DailyHourUTC,
+		DailyMinuteUTC: cfg.Snapshots.DailyMinuteUTC,
+		Clock:          clk,
+		Season:         snapshots.Season{Start: cfg.Snapshots.SeasonStart, End: cfg.Snapshots.SeasonEnd},
+		ForceOffseason: cfg.Snapshots.ForceOffseason,
+		Recorder:       recorder,
+	}
+}
+
+// todayGames sizes the store for the games_in_store gauge as the number of
+// games in today's snapshot; a missing snapshot counts as zero.
+type todayGames struct {
+	store snapshots.Store
+	loc   *time.Location
+	clk   clock.Clock
+}
+
+func (t todayGames) GamesInStore() int {
+	if t.store == nil {
+		return 0
+	}
+	today := timeutil.DateIn(clock.OrReal(t.clk).Now(), t.loc)
+	snap, err := t.store.LoadGames(context.Background(), today)
+	if err != nil {
+		return 0
 	}
+	return len(snap.Games)
 }
@@ -2,10 +2,18 @@ package server
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/providers/fixture"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 func TestBuildSnapshotsRespectsConfig(t *testing.T) {
@@ -17,7 +25,7 @@ func TestBuildSnapshotsRespectsConfig(t *testing.T) {
 		},
 	}
 	prov := fixture.New()
-	components := buildSnapshots(cfg, prov, nil, nil)
+	components := buildSnapshots(cfg, prov, nil, nil, nil, nil)
 	if components.store == nil || components.writer == nil || components.syncer == nil {
 		t.Fatalf("expected snapshots components to be initialized")
 	}
@@ -31,3 +39,61 @@ func TestBuildSnapshotsRespectsConfig(t *testing.T) {
 	cancelFn()
 	<-done
 }
+
+type offsetProvider struct {
+	*fixture.Provider
+	offset time.Duration
+}
+
+func (p offsetProvider) ServerClockOffset() (time.Duration, bool) { return p.offset, true }
+
+func TestBuildSnapshotsWiresSkewCheckerToProviderClock(t *testing.T) {
+	cfg := config.Config{
+		Snapshots: config.SnapshotSyncConfig{
+			SnapshotFolder: t.TempDir(),
+			SkewThreshold:  time.Hour,
+		},
+	}
+	prov := providers.NewRetryingProvider(offsetProvider{Provider: fixture.New(), offset: 48 * time.Hour}, nil, nil, "fixture", 0, 0)
+	components := buildSnapshots(cfg, prov, nil, nil, nil, nil)
+	if components.skew == nil || !components.skew.Status().Suspected {
+		t.Fatalf("expected startup skew check to flag provider offset, got %+v", components.skew.Status())
+	}
+}
+
+func TestBuildSnapshotsRecordsHydrationReport(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "games", "2024-03-01.json"), []byte(`{"date":"2024-03-01","games":[{"id":"g1"}]}`), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	cfg := config.Config{Snapshots: config.SnapshotSyncConfig{SnapshotFolder: dir}}
+	components := buildSnapshots(cfg, fixture.New(), nil, nil, nil, nil)
+	if got := components.hydration.Dates(); len(got) != 1 || got[0] != "2024-03-01" || components.hydration.Games != 1 {
+		t.Fatalf("expected hydration report for seeded snapshot, got %+v", components.hydration)
+	}
+}
+
+func TestTodayGamesCountsTodaysSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	clk := teststubs.NewFakeClock(now)
+	store := snapshots.NewFSStoreWithCache(dir, 4)
+	sizer := todayGames{store: store, clk: clk}
+	if got := sizer.GamesInStore(); got != 0 {
+		t.Fatalf("expected 0 games without a snapshot, got %d", got)
+	}
+
+	today := timeutil.FormatDate(now)
+	writer := snapshots.NewWriter(dir, 7)
+	writer.SetWriteHook(store.ForgetMiss)
+	games := []domaingames.Game{{ID: "a", StartTime: now.Format(time.RFC3339)}, {ID: "b", StartTime: now.Format(time.RFC3339)}}
+	if err := writer.WriteGamesSnapshot(today, domaingames.NewTodayResponse(today, games)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if got := sizer.GamesInStore(); got != 2 {
+		t.Fatalf("expected 2 games after the write, got %d", got)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/http/handlers"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+)
+
+// providerStatus reports the provider name and, when supported, the active API key slot (never the key).
+func providerStatus(name string, provider providers.GameProvider) handlers.StatusFunc {
+	reporter, hasSlot := providers.As[providers.KeySlotReporter](provider)
+	return func() any {
+		status := map[string]string{"name": name}
+		if hasSlot {
+			status["apiKeySlot"] = reporter.ActiveKeySlot()
+		}
+		return status
+	}
+}
+
+type providerMetricsView struct {
+	Providers map[string]providerStatsView `json:"providers"`
+	Total     providerStatsView            `json:"total"`
+}
+
+type providerStatsView struct {
+	Calls                int        `json:"calls"`
+	Errors               int        `json:"errors"`
+	RateLimitHits        int        `json:"rateLimitHits"`
+	RetryBudgetExhausted int        `json:"retryBudgetExhausted"`
+	LastRetryAfterMs     int64      `json:"lastRetryAfterMs"`
+	LastCallLatencyMs    int64      `json:"lastCallLatencyMs"`
+	Evicted              bool       `json:"evicted,omitempty"`
+	Quota                *quotaView `json:"quota,omitempty"`
+}
+
+// quotaView is a provider's upstream quota forecast; remaining and resetsAt
+// are omitted while nothing is known about the current window.
+type quotaView struct {
+	Remaining  *int       `json:"remaining,omitempty"`
+	ResetsAt   *time.Time `json:"resetsAt,omitempty"`
+	Throttled  bool       `json:"throttled"`
+	IntervalMs int64      `json:"intervalMs"`
+}
+
+func newQuotaView(e metrics.QuotaEstimate) *quotaView {
+	view := &quotaView{Throttled: e.Throttled, IntervalMs: e.Interval.Milliseconds()}
+	if e.Known {
+		remaining, resetsAt := e.Remaining, e.ResetsAt
+		view.Remaining, view.ResetsAt = &remaining, &resetsAt
+	}
+	return view
+}
+
+func newProviderStatsView(s metrics.Snapshot) providerStatsView {
+	return providerStatsView{
+		Calls:                s.Calls,
+		Errors:               s.Errors,
+		RateLimitHits:        s.RateLimitHits,
+		RetryBudgetExhausted: s.RetryBudgetExhausted,
+		LastRetryAfterMs:     s.LastRetryAfter.Milliseconds(),
+		LastCallLatencyMs:    s.LastCallLatency.Milliseconds(),
+		Evicted:              s.Evicted,
+	}
+}
+
+// providerMetricsStatus reports the recorder's stats for every provider name
+// the decorator layers (retry, limit, failover) recorded under, plus their sum.
+// Providers whose quota is tracked also carry the current forecast.
+func providerMetricsStatus(recorder *metrics.Recorder) handlers.StatusFunc {
+	return func() any {
+		all := recorder.AllSnapshots()
+		view := providerMetricsView{
+			Providers: make(map[string]providerStatsView, len(all)),
+			Total:     newProviderStatsView(recorder.TotalSnapshot()),
+		}
+		for name, s := range all {
+			stats := newProviderStatsView(s)
+			if estimate, ok := recorder.QuotaEstimate(name); ok {
+				stats.Quota = newQuotaView(estimate)
+			}
+			view.Providers[name] = stats
+		}
+		return view
+	}
+}
+
+type syncStatusView struct {
+	NextSyncAt      *time.Time       `json:"nextSyncAt,omitempty"`
+	PendingFailures int              `json:"pendingFailures"`
+	Backfill        backfillView     `json:"backfill"`
+	LastRun         *syncLastRunView `json:"lastRun,omitempty"`
+	GamesRefreshed  *time.Time       `json:"gamesLastRefreshed,omitempty"`
+}
+
+type backfillView struct {
+	Running      bool     `json:"running"`
+	Total        int      `json:"total"`
+	Completed    int      `json:"completed"`
+	Failed       int      `json:"failed"`
+	CurrentDate  string   `json:"currentDate,omitempty"`
+	CurrentDates []string `json:"currentDates,omitempty"` // every date in flight, for concurrent runs
+	RetryPass    int      `json:"retryPass,omitempty"`
+}
+
+type syncLastRunView struct {
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// syncStatus reports backfill progress, when the daily snapshot sync next runs,
+// and how many backfill dates are still failing; nextSyncAt is omitted while it
+// is stopped and lastRun until a run completes.
+func syncStatus(syncer *snapshots.Syncer) handlers.StatusFunc {
+	return func() any {
+		st := syncer.Status()
+		view := syncStatusView{
+			PendingFailures: syncer.PendingFailures(),
+			Backfill: backfillView{
+				Running:      st.Running,
+				Total:        st.Total,
+				Completed:    st.Completed,
+				Failed:       st.Failed,
+				CurrentDate:  st.CurrentDate,
+				CurrentDates: st.CurrentDates,
+				RetryPass:    st.RetryPass,
+			},
+		}
+		if at, ok := syncer.NextRun(); ok {
+			view.NextSyncAt = &at
+		}
+		if !st.LastRunFinishedAt.IsZero() {
+			view.LastRun = &syncLastRunView{
+				StartedAt:  st.LastRunStartedAt,
+				FinishedAt: st.LastRunFinishedAt,
+				DurationMs: st.LastRunDuration.Milliseconds(),
+			}
+		}
+		if !st.GamesLastRefreshed.IsZero() {
+			at := st.GamesLastRefreshed
+			view.GamesRefreshed = &at
+		}
+		return view
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/balldontlie"
+	"github.com/preston-bernstein/nba-data-service/internal/providers/fixture"
+)
+
+func TestProviderStatusReportsKeySlotThroughWrappers(t *testing.T) {
+	base := balldontlie.NewClient(balldontlie.Config{APIKey: "primary-key"})
+	wrapped := providers.NewRetryingProvider(base, nil, nil, "balldontlie", 1, 0)
+
+	got, ok := providerStatus("balldontlie", wrapped)().(map[string]string)
+	if !ok {
+		t.Fatalf("expected map status")
+	}
+	if got["name"] != "balldontlie" || got["apiKeySlot"] != "primary" {
+		t.Fatalf("unexpected provider status %+v", got)
+	}
+	for _, v := range got {
+		if v == "primary-key" {
+			t.Fatalf("status must never expose key values")
+		}
+	}
+}
+
+func TestProviderStatusOmitsKeySlotForFixture(t *testing.T) {
+	got := providerStatus("fixture", fixture.New())().(map[string]string)
+	if _, ok := got["apiKeySlot"]; ok {
+		t.Fatalf("expected no key slot for fixture provider, got %+v", got)
+	}
+}
+
+func TestSyncStatusOmitsNextSyncWhenStopped(t *testing.T) {
+	got := syncStatus(nil)().(syncStatusView)
+	if got.NextSyncAt != nil {
+		t.Fatalf("expected no next sync for stopped syncer, got %v", got.NextSyncAt)
+	}
+}
+
+func TestSyncStatusOmitsLastRunBeforeFirstBackfill(t *testing.T) {
+	got := syncStatus(nil)().(syncStatusView)
+	if got.LastRun != nil || got.Backfill.Running || got.GamesRefreshed != nil {
+		t.Fatalf("expected empty sync progress for stopped syncer, got %+v", got)
+	}
+}
+
+func TestProviderMetricsStatusReportsEveryProviderAndTotal(t *testing.T) {
+	rec := metrics.NewRecorder()
+	rec.RecordProviderAttempt("balldontlie", 40*time.Millisecond, nil)
+	rec.RecordProviderAttempt("balldontlie-retry", 60*time.Millisecond, errors.New("boom"))
+
+	got := providerMetricsStatus(rec)().(providerMetricsView)
+	if len(got.Providers) != 2 || got.Providers["balldontlie-retry"].Errors != 1 {
+		t.Fatalf("unexpected providers %+v", got.Providers)
+	}
+	if got.Total.Calls != 2 || got.Total.Errors != 1 || got.Total.LastCallLatencyMs != 60 {
+		t.Fatalf("unexpected total %+v", got.Total)
+	}
+}
+
+func TestProviderMetricsStatusReportsQuota(t *testing.T) {
+	rec := metrics.NewRecorder()
+	resetsAt := time.Date(2024, 1, 15, 12, 1, 0, 0, time.UTC)
+	rec.RecordProviderAttempt("balldontlie-retry", time.Millisecond, nil)
+	rec.SetQuotaEstimate("balldontlie", metrics.QuotaEstimate{Remaining: 2, Known: true, Throttled: true, Interval: 2 * time.Minute, ResetsAt: resetsAt})
+
+	got := providerMetricsStatus(rec)().(providerMetricsView)
+	if got.Providers["balldontlie-retry"].Quota != nil {
+		t.Fatalf("expected no quota for an untracked name")
+	}
+	quota := got.Providers["balldontlie"].Quota
+	if quota == nil || quota.Remaining == nil || *quota.Remaining != 2 || !quota.Throttled || quota.IntervalMs != 120000 || !quota.ResetsAt.Equal(resetsAt) {
+		t.Fatalf("unexpected quota %+v", quota)
+	}
+
+	rec.SetQuotaEstimate("balldontlie", metrics.QuotaEstimate{Interval: time.Minute})
+	quota = providerMetricsStatus(rec)().(providerMetricsView).Providers["balldontlie"].Quota
+	if quota == nil || quota.Remaining != nil || quota.ResetsAt != nil || quota.Throttled {
+		t.Fatalf("expected an unknown window to omit remaining, got %+v", quota)
+	}
+}
@@ -3,8 +3,9 @@ package server
 import "time"
 
 const (
-	readTimeout  = 10 * time.Second
-	writeTimeout = 10 * time.Second
+	readTimeout = 10 * time.Second
+	// Longer than the default HANDLER_TIMEOUT so its 504 still reaches the client.
+	writeTimeout = 15 * time.Second
 	idleTimeout  = 60 * time.Second
 )
 
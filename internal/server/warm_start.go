@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// slateWarmer is the poller hook warmGames seeds today's games through.
+type slateWarmer interface {
+	Warm(date string, games []domaingames.Game)
+}
+
+// warmStart is what warmGames pre-loaded before the first poll.
+type warmStart struct {
+	Dates []string // snapshot dates loaded, today first
+	Games int      // games across those dates
+}
+
+// warmGames loads today's and yesterday's games snapshots, by the service
+// timezone, before the listener opens: the loads fill the store's cache, and
+// today's games seed the poller's slate. Yesterday is included for late games
+// still being asked about after midnight. A missing snapshot is skipped
+// quietly; any other failure is logged and skipped.
+func warmGames(store snapshots.Store, warmer slateWarmer, loc *time.Location, clk clock.Clock, logger *slog.Logger, recorder *metrics.Recorder) warmStart {
+	warm := warmStart{Dates: []string{}}
+	if store == nil {
+		return warm
+	}
+	now := clock.OrReal(clk).Now()
+	for i, offset := range []int{0, -1} {
+		date := timeutil.DateOffset(now, loc, offset)
+		snap, err := store.LoadGames(context.Background(), date)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				logging.Warn(logger, "warm start skipped snapshot", "date", date, "err", err)
+			}
+			continue
+		}
+		warm.Dates = append(warm.Dates, date)
+		warm.Games += len(snap.Games)
+		if i == 0 && warmer != nil {
+			warmer.Warm(date, snap.Games)
+		}
+	}
+	recorder.SetWarmStartGames(warm.Games)
+	return warm
+}
+
+// attrs describes the warm start for the startup log.
+func (w warmStart) attrs() []any {
+	return []any{
+		slog.Bool("warm_start", len(w.Dates) > 0),
+		slog.Int("warm_start_games", w.Games),
+		slog.Any("warm_start_dates", w.Dates),
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/config"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/snapshots"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/testutil"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// recordingWarmer captures what warmGames seeds into the poller.
+type recordingWarmer struct {
+	date  string
+	games []domaingames.Game
+}
+
+func (w *recordingWarmer) Warm(date string, games []domaingames.Game) {
+	w.date, w.games = date, games
+}
+
+func seedGames(t *testing.T, dir, date string, ids ...string) {
+	t.Helper()
+	games := make([]domaingames.Game, 0, len(ids))
+	for _, id := range ids {
+		games = append(games, domaingames.Game{ID: id})
+	}
+	writer := snapshots.NewWriter(dir, 30)
+	writer.SetPruneGuard(func() bool { return true }) // keep fixed past dates
+	if err := writer.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, games)); err != nil {
+		t.Fatalf("seed %s: %v", date, err)
+	}
+}
+
+func TestWarmGamesLoadsTodayAndYesterday(t *testing.T) {
+	dir := t.TempDir()
+	// 03:00 UTC is still the previous evening in New York.
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC))
+	loc, _ := time.LoadLocation("America/New_York")
+	seedGames(t, dir, "2024-01-15", "a", "b")
+	seedGames(t, dir, "2024-01-14", "c")
+	warmer := &recordingWarmer{}
+
+	warm := warmGames(snapshots.NewFSStoreWithCache(dir, 4), warmer, loc, clk, nil, nil)
+
+	if len(warm.Dates) != 2 || warm.Dates[0] != "2024-01-15" || warm.Dates[1] != "2024-01-14" || warm.Games != 3 {
+		t.Fatalf("expected today and yesterday in the service zone, got %+v", warm)
+	}
+	if warmer.date != "2024-01-15" || len(warmer.games) != 2 {
+		t.Fatalf("expected today's games seeded into the poller, got %s %+v", warmer.date, warmer.games)
+	}
+}
+
+func TestWarmGamesSkipsMissingAndCorruptSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	logger, buf := testutil.NewBufferLogger()
+	warmer := &recordingWarmer{}
+
+	warm := warmGames(snapshots.NewFSStoreWithCache(dir, 4), warmer, nil, clk, logger, nil)
+	if len(warm.Dates) != 0 || warm.Games != 0 || warmer.games != nil {
+		t.Fatalf("expected nothing warmed without snapshots, got %+v", warm)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected missing snapshots skipped quietly, got %s", buf.String())
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(snapshots.GameSnapshotPath(dir, "2024-01-15"), []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	warm = warmGames(snapshots.NewFSStoreWithCache(dir, 4), warmer, nil, clk, logger, nil)
+	if len(warm.Dates) != 0 || !strings.Contains(buf.String(), "warm start skipped snapshot") {
+		t.Fatalf("expected the corrupt snapshot skipped with a warning, got %+v log %s", warm, buf.String())
+	}
+}
+
+func TestNewServerWarmsFromSnapshotsBeforeFirstPoll(t *testing.T) {
+	dir := t.TempDir()
+	seedGames(t, dir, timeutil.FormatDate(time.Now().UTC()), "a", "b")
+	provider := &teststubs.StubProvider{}
+	cfg := config.Config{
+		Provider:  "fixture",
+		Snapshots: config.SnapshotSyncConfig{SnapshotFolder: dir, CacheEntries: 4},
+	}
+
+	srv := newServerWithMetrics(cfg, nil, provider, nil)
+
+	if srv.warm.Games < 2 || len(srv.warm.Dates) == 0 {
+		t.Fatalf("expected today's snapshot pre-loaded, got %+v", srv.warm)
+	}
+	if calls := provider.Calls.Load(); calls != 0 {
+		t.Fatalf("expected no provider calls before the first poll, got %d", calls)
+	}
+}
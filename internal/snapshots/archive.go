@@ -0,0 +1,422 @@
+package snapshots
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// ArchiveVersion is the snapshot archive format Export writes and Import accepts.
+const ArchiveVersion = 1
+
+const (
+	archiveIndexName     = "archive.json"
+	archiveManifestName  = "manifest.json"
+	maxArchiveEntryBytes = 64 << 20
+)
+
+var (
+	// ErrInvalidArchive is returned by Import for an archive that is not a
+	// snapshot archive or whose index, manifest and snapshots disagree.
+	ErrInvalidArchive = errors.New("invalid snapshot archive")
+	// ErrSnapshotsExist is returned by Import without Merge when the writer's
+	// directory already holds games snapshots.
+	ErrSnapshotsExist = errors.New("snapshot directory is not empty")
+)
+
+// archiveIndex is the archive's first entry: its format version and the
+// SHA-256 of every games snapshot it carries, keyed by date.
+type archiveIndex struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Games      map[string]string `json:"games"`
+}
+
+type archivedSnapshot struct {
+	data    []byte
+	modTime time.Time
+}
+
+type archive struct {
+	manifest Manifest
+	games    map[string]archivedSnapshot
+}
+
+// ImportOptions controls how Import treats snapshots already on disk.
+type ImportOptions struct {
+	// Merge imports into a directory that already has snapshots. A date on
+	// disk is kept when it is frozen or its file is at least as new as the
+	// archived one; other dates are replaced or added.
+	Merge bool
+}
+
+// ImportResult lists the dates Import wrote and the local dates it kept.
+type ImportResult struct {
+	Imported []string `json:"imported"`
+	Skipped  []string `json:"skipped,omitempty"`
+}
+
+// Export writes every games snapshot and the manifest to out as a gzipped tar
+// archive: an index with the format version and each snapshot's checksum, the
+// manifest trimmed to the exported dates, and games/<date>.json with its file
+// time. It returns the exported dates.
+func (w *Writer) Export(out io.Writer) ([]string, error) {
+	if w == nil {
+		return nil, errors.New("snapshot writer not configured")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	m, err := w.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	dates, err := w.snapshotDates()
+	if err != nil {
+		return nil, err
+	}
+	m.Games.Dates = dates
+	m.Games.Pinned = keepDates(m.Games.Pinned, dates)
+	dropFreezeState(&m.Games)
+
+	now := w.clock.Now().UTC()
+	index := archiveIndex{Version: ArchiveVersion, ExportedAt: now, Games: make(map[string]string, len(dates))}
+	games := make(map[string]archivedSnapshot, len(dates))
+	for _, date := range dates {
+		target := w.snapshotPath(kindGames, date, 0)
+		data, err := os.ReadFile(target)
+		if err != nil {
+			return nil, err
+		}
+		info, err := os.Stat(target)
+		if err != nil {
+			return nil, err
+		}
+		index.Games[date] = checksum(data)
+		games[date] = archivedSnapshot{data: data, modTime: info.ModTime()}
+	}
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	manifestData, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	if err := writeArchiveEntry(tw, archiveIndexName, indexData, now); err != nil {
+		return nil, err
+	}
+	if err := writeArchiveEntry(tw, archiveManifestName, manifestData, now); err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		if err := writeArchiveEntry(tw, archiveGamesName(date), games[date].data, games[date].modTime); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return dates, nil
+}
+
+// Import validates an archive written by Export and unpacks it into the
+// writer's directory. Nothing is written unless the whole archive checks out:
+// the version is supported, every snapshot matches its checksum, parses and
+// is named for a real date, and the manifest lists exactly those dates.
+// Imported dates keep their archived file times and manifest state (pins,
+// settled and frozen); retention applies from the next write as usual, so
+// old dates survive it only if pinned.
+func (w *Writer) Import(r io.Reader, opts ImportOptions) (ImportResult, error) {
+	if w == nil {
+		return ImportResult{}, errors.New("snapshot writer not configured")
+	}
+	a, err := readArchive(r)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	existing, err := w.snapshotDates()
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if !opts.Merge && len(existing) > 0 {
+		return ImportResult{}, fmt.Errorf("%w: %d games snapshots in %s", ErrSnapshotsExist, len(existing), w.basePath)
+	}
+	m, err := w.loadManifest()
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	result := ImportResult{Imported: []string{}}
+	for _, date := range a.manifest.Games.Dates {
+		snap := a.games[date]
+		if containsDate(existing, date) && w.keepLocal(m, date, snap.modTime) {
+			result.Skipped = append(result.Skipped, date)
+			continue
+		}
+		if err := w.unpackSnapshot(date, snap); err != nil {
+			return result, err
+		}
+		result.Imported = append(result.Imported, date)
+	}
+
+	if m.Games.Dates, err = w.snapshotDates(); err != nil {
+		return result, err
+	}
+	mergeImported(&m.Games, a.manifest.Games, result.Imported)
+	m.Retention = w.retention.manifest()
+	return result, w.saveManifest(m)
+}
+
+// keepLocal reports whether a merge should leave the date on disk alone.
+func (w *Writer) keepLocal(m Manifest, date string, archived time.Time) bool {
+	if containsDate(m.Games.Frozen, date) {
+		return true
+	}
+	info, err := os.Stat(w.snapshotPath(kindGames, date, 0))
+	return err == nil && !info.ModTime().Before(archived)
+}
+
+func (w *Writer) unpackSnapshot(date string, snap archivedSnapshot) error {
+	target := w.snapshotPath(kindGames, date, 0)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp := target + ".tmp"
+	if err := writeFileSynced(tmp, snap.data); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp, snap.modTime, snap.modTime); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return err
+	}
+	w.notifyWrite(kindGames, date)
+	return nil
+}
+
+// snapshotDates lists the games snapshot files named for a valid date.
+func (w *Writer) snapshotDates() ([]string, error) {
+	dates, err := w.listDates(kindGames)
+	if err != nil {
+		return nil, err
+	}
+	valid := dates[:0]
+	for _, date := range dates {
+		if validateDate(date) == nil {
+			valid = append(valid, date)
+		}
+	}
+	return valid, nil
+}
+
+// mergeImported carries the archived manifest state of each imported date
+// into meta. Local pins are kept: pinning is an operator decision about this
+// directory, not part of the data.
+func mergeImported(meta *GamesMeta, archived GamesMeta, imported []string) {
+	for _, date := range imported {
+		if containsDate(archived.Pinned, date) && !containsDate(meta.Pinned, date) {
+			meta.Pinned = append(meta.Pinned, date)
+		}
+		meta.Frozen = removeDate(meta.Frozen, date)
+		if containsDate(archived.Frozen, date) {
+			meta.Frozen = append(meta.Frozen, date)
+		}
+		delete(meta.Settled, date)
+		if at, ok := archived.Settled[date]; ok {
+			if meta.Settled == nil {
+				meta.Settled = make(map[string]time.Time)
+			}
+			meta.Settled[date] = at
+		}
+	}
+	sort.Strings(meta.Pinned)
+	sort.Strings(meta.Frozen)
+	if len(imported) > 0 && archived.LastRefreshed.After(meta.LastRefreshed) {
+		meta.LastRefreshed = archived.LastRefreshed
+	}
+	dropFreezeState(meta)
+}
+
+// readArchive reads and validates a whole archive in memory.
+func readArchive(r io.Reader) (archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return archive{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	defer func() {
+		_ = gz.Close()
+	}()
+	tr := tar.NewReader(gz)
+
+	var (
+		index        *archiveIndex
+		manifestData []byte
+		a            = archive{games: make(map[string]archivedSnapshot)}
+	)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return archive{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return archive{}, fmt.Errorf("%w: %s is not a regular file", ErrInvalidArchive, hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxArchiveEntryBytes+1))
+		if err != nil {
+			return archive{}, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, hdr.Name, err)
+		}
+		if len(data) > maxArchiveEntryBytes {
+			return archive{}, fmt.Errorf("%w: %s is over %d bytes", ErrInvalidArchive, hdr.Name, maxArchiveEntryBytes)
+		}
+		switch date, isGames := archiveGamesDate(hdr.Name); {
+		case hdr.Name == archiveIndexName && index == nil:
+			index = &archiveIndex{}
+			if err := json.Unmarshal(data, index); err != nil {
+				return archive{}, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, hdr.Name, err)
+			}
+		case hdr.Name == archiveManifestName && manifestData == nil:
+			manifestData = data
+		case isGames:
+			if err := validateDate(date); err != nil {
+				return archive{}, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, hdr.Name, err)
+			}
+			if _, dup := a.games[date]; dup {
+				return archive{}, fmt.Errorf("%w: %s appears twice", ErrInvalidArchive, hdr.Name)
+			}
+			a.games[date] = archivedSnapshot{data: data, modTime: hdr.ModTime}
+		default:
+			return archive{}, fmt.Errorf("%w: unexpected entry %s", ErrInvalidArchive, hdr.Name)
+		}
+	}
+
+	if index == nil || manifestData == nil {
+		return archive{}, fmt.Errorf("%w: missing %s or %s", ErrInvalidArchive, archiveIndexName, archiveManifestName)
+	}
+	if index.Version < 1 || index.Version > ArchiveVersion {
+		return archive{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidArchive, index.Version)
+	}
+	if err := json.Unmarshal(manifestData, &a.manifest); err != nil || a.manifest.Version < 1 {
+		return archive{}, fmt.Errorf("%w: unreadable manifest", ErrInvalidArchive)
+	}
+	if err := a.validate(index); err != nil {
+		return archive{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+	return a, nil
+}
+
+// validate checks that the index, manifest and snapshot entries describe the
+// same dates and that every snapshot is intact.
+func (a *archive) validate(index *archiveIndex) error {
+	dates := append([]string(nil), a.manifest.Games.Dates...)
+	sort.Strings(dates)
+	a.manifest.Games.Dates = dates
+	if len(dates) != len(a.games) || len(dates) != len(index.Games) {
+		return fmt.Errorf("manifest lists %d dates, index %d, archive has %d snapshots", len(dates), len(index.Games), len(a.games))
+	}
+	for i, date := range dates {
+		if i > 0 && dates[i-1] == date {
+			return fmt.Errorf("manifest lists %s twice", date)
+		}
+		snap, ok := a.games[date]
+		if !ok {
+			return fmt.Errorf("manifest lists %s but the archive has no snapshot for it", date)
+		}
+		if sum := checksum(snap.data); sum != index.Games[date] {
+			return fmt.Errorf("%s: checksum %s does not match index %q", archiveGamesName(date), sum, index.Games[date])
+		}
+		var payload domaingames.TodayResponse
+		if err := json.Unmarshal(snap.data, &payload); err != nil {
+			return fmt.Errorf("%s: %v", archiveGamesName(date), err)
+		}
+		if payload.Date != "" && payload.Date != date {
+			return fmt.Errorf("%s: snapshot is dated %s", archiveGamesName(date), payload.Date)
+		}
+	}
+	for _, list := range [][]string{a.manifest.Games.Pinned, a.manifest.Games.Frozen} {
+		for _, date := range list {
+			if !containsDate(dates, date) {
+				return fmt.Errorf("manifest pins or freezes %s, which has no snapshot", date)
+			}
+		}
+	}
+	for date := range a.manifest.Games.Settled {
+		if !containsDate(dates, date) {
+			return fmt.Errorf("manifest settles %s, which has no snapshot", date)
+		}
+	}
+	return nil
+}
+
+func writeArchiveEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX, // keeps sub-second file times
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, bytes.NewReader(data))
+	return err
+}
+
+func archiveGamesName(date string) string {
+	return path.Join(string(kindGames), date+".json")
+}
+
+// archiveGamesDate returns the date an entry name such as games/2024-01-15.json
+// is for; the date still needs validating.
+func archiveGamesDate(name string) (string, bool) {
+	dir, file := path.Split(name)
+	if dir != string(kindGames)+"/" || !strings.HasSuffix(file, ".json") {
+		return "", false
+	}
+	return strings.TrimSuffix(file, ".json"), true
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// keepDates returns the entries of list that are in dates.
+func keepDates(list, dates []string) []string {
+	var kept []string
+	for _, date := range list {
+		if containsDate(dates, date) {
+			kept = append(kept, date)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,221 @@
+package snapshots
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+func exportArchive(t *testing.T, w *Writer) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := w.Export(&buf); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// touch sets a snapshot's file time, which merges compare.
+func touch(t *testing.T, w *Writer, date string, at time.Time) {
+	t.Helper()
+	if err := os.Chtimes(w.snapshotPath(kindGames, date, 0), at, at); err != nil {
+		t.Fatalf("chtimes %s: %v", date, err)
+	}
+}
+
+func snapshotScore(t *testing.T, w *Writer, date string) int {
+	t.Helper()
+	snap, err := NewFSStore(w.BasePath()).LoadGames(context.Background(), date)
+	if err != nil {
+		t.Fatalf("load %s: %v", date, err)
+	}
+	return snap.Games[0].Score.Home
+}
+
+func scored(date string, home int) domaingames.TodayResponse {
+	return domaingames.TodayResponse{Date: date, Games: []domaingames.Game{{ID: "g1", Score: domaingames.Score{Home: home}}}}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src, _ := freezeWriter(t, time.Hour)
+	writeSnapshot(t, src, "2024-01-13", slate(domaingames.StatusFinal))
+	writeSimpleSnapshot(t, src, "2024-01-14")
+	if err := src.PinDate("2024-01-14"); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	data := exportArchive(t, src)
+
+	dst, _ := freezeWriter(t, time.Hour)
+	result, err := dst.Import(bytes.NewReader(data), ImportOptions{})
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	assertDatesEqual(t, result.Imported, []string{"2024-01-13", "2024-01-14"})
+	for _, date := range result.Imported {
+		want, _ := os.ReadFile(src.snapshotPath(kindGames, date, 0))
+		got, _ := os.ReadFile(dst.snapshotPath(kindGames, date, 0))
+		if !bytes.Equal(got, want) {
+			t.Fatalf("%s: expected the snapshot imported unchanged", date)
+		}
+	}
+	srcManifest, _ := src.Manifest()
+	dstManifest, _ := dst.Manifest()
+	if !reflect.DeepEqual(dstManifest.Games, srcManifest.Games) {
+		t.Fatalf("expected the manifest carried over\nsrc: %+v\ndst: %+v", srcManifest.Games, dstManifest.Games)
+	}
+
+	if _, err := dst.Import(bytes.NewReader(data), ImportOptions{}); !errors.Is(err, ErrSnapshotsExist) {
+		t.Fatalf("expected a second import without merge to be refused, got %v", err)
+	}
+	again, err := dst.Import(bytes.NewReader(data), ImportOptions{Merge: true})
+	if err != nil || len(again.Imported) != 0 || len(again.Skipped) != 2 {
+		t.Fatalf("expected re-importing the same archive to keep every date, got %+v %v", again, err)
+	}
+}
+
+func TestImportMergeKeepsNewerAndFrozenDates(t *testing.T) {
+	exported := time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC)
+	src, _ := freezeWriter(t, time.Hour)
+	for _, date := range []string{"2024-01-11", "2024-01-13", "2024-01-14", "2024-01-15"} {
+		writeSnapshot(t, src, date, scored(date, 100))
+		touch(t, src, date, exported)
+	}
+	data := exportArchive(t, src)
+
+	dst, _ := freezeWriter(t, 0) // settled slates freeze at once
+	final := scored("2024-01-11", 90)
+	final.Games[0].StatusKind = domaingames.StatusFinal
+	writeSnapshot(t, dst, "2024-01-11", final)
+	touch(t, dst, "2024-01-11", exported.Add(-time.Hour))
+	writeSnapshot(t, dst, "2024-01-12", scored("2024-01-12", 90))
+	writeSnapshot(t, dst, "2024-01-13", scored("2024-01-13", 90))
+	touch(t, dst, "2024-01-13", exported.Add(time.Hour))
+	writeSnapshot(t, dst, "2024-01-14", scored("2024-01-14", 90))
+	touch(t, dst, "2024-01-14", exported.Add(-time.Hour))
+
+	result, err := dst.Import(bytes.NewReader(data), ImportOptions{Merge: true})
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	assertDatesEqual(t, result.Imported, []string{"2024-01-14", "2024-01-15"})
+	assertDatesEqual(t, result.Skipped, []string{"2024-01-11", "2024-01-13"})
+
+	want := map[string]int{"2024-01-11": 90, "2024-01-12": 90, "2024-01-13": 90, "2024-01-14": 100, "2024-01-15": 100}
+	for date, score := range want {
+		if got := snapshotScore(t, dst, date); got != score {
+			t.Fatalf("%s: expected score %d after merge, got %d", date, score, got)
+		}
+	}
+	m, _ := dst.Manifest()
+	assertDatesEqual(t, m.Games.Dates, []string{"2024-01-11", "2024-01-12", "2024-01-13", "2024-01-14", "2024-01-15"})
+	if !containsDate(m.Games.Frozen, "2024-01-11") {
+		t.Fatalf("expected the local freeze kept, got %v", m.Games.Frozen)
+	}
+}
+
+// rewriteArchive re-packs data after edit changes its entries by name.
+func rewriteArchive(t *testing.T, data []byte, edit func(files map[string][]byte)) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar: %v", err)
+		}
+		files[hdr.Name], _ = io.ReadAll(tr)
+	}
+	edit(files)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, name := range names {
+		if err := writeArchiveEntry(tw, name, files[name], time.Now()); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	_ = tw.Close()
+	_ = gzw.Close()
+	return buf.Bytes()
+}
+
+func editJSON(t *testing.T, files map[string][]byte, name string, edit func(v map[string]any)) {
+	t.Helper()
+	var v map[string]any
+	if err := json.Unmarshal(files[name], &v); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	edit(v)
+	files[name], _ = json.Marshal(v)
+}
+
+func TestImportRejectsInvalidArchives(t *testing.T) {
+	src, _ := freezeWriter(t, time.Hour)
+	writeSimpleSnapshot(t, src, "2024-01-14")
+	writeSimpleSnapshot(t, src, "2024-01-15")
+	data := exportArchive(t, src)
+
+	cases := map[string]func(files map[string][]byte){
+		"checksum": func(files map[string][]byte) {
+			files["games/2024-01-15.json"] = []byte(`{"date":"2024-01-15","games":[]}`)
+		},
+		"version": func(files map[string][]byte) {
+			editJSON(t, files, archiveIndexName, func(v map[string]any) { v["version"] = ArchiveVersion + 1 })
+		},
+		"manifest dates": func(files map[string][]byte) {
+			editJSON(t, files, archiveManifestName, func(v map[string]any) {
+				v["games"].(map[string]any)["dates"] = []string{"2024-01-14"}
+			})
+		},
+		"date format": func(files map[string][]byte) {
+			files["games/2024-1-15.json"] = files["games/2024-01-15.json"]
+			delete(files, "games/2024-01-15.json")
+		},
+		"unknown entry": func(files map[string][]byte) {
+			files["../escape.json"] = []byte(`{}`)
+		},
+		"missing manifest": func(files map[string][]byte) {
+			delete(files, archiveManifestName)
+		},
+	}
+	for name, edit := range cases {
+		t.Run(name, func(t *testing.T) {
+			dst := NewWriter(t.TempDir(), 7)
+			_, err := dst.Import(bytes.NewReader(rewriteArchive(t, data, edit)), ImportOptions{})
+			if !errors.Is(err, ErrInvalidArchive) {
+				t.Fatalf("expected ErrInvalidArchive, got %v", err)
+			}
+			if entries, _ := os.ReadDir(dst.BasePath()); len(entries) != 0 {
+				t.Fatalf("expected nothing written for a rejected archive, got %v", entries)
+			}
+		})
+	}
+
+	if _, err := NewWriter(t.TempDir(), 7).Import(strings.NewReader("not an archive"), ImportOptions{}); !errors.Is(err, ErrInvalidArchive) {
+		t.Fatalf("expected a non-gzip input rejected, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package snapshots
+
+import (
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// Fields reported in FieldChange.Field; they match the /games/{id}/history fields.
+const (
+	DiffFieldStatus    = "status"
+	DiffFieldHomeScore = "score.home"
+	DiffFieldAwayScore = "score.away"
+)
+
+// GamesDiff is what changed from one games snapshot to another, with games
+// matched by provider ID.
+type GamesDiff struct {
+	Added   []domaingames.Game `json:"added"`
+	Removed []domaingames.Game `json:"removed"`
+	Changed []GameDiff         `json:"changed"`
+}
+
+// GameDiff lists the status and score changes of one game present in both.
+type GameDiff struct {
+	GameID  string        `json:"gameId"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// FieldChange is one field's value in the older and newer snapshot.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// DiffGames compares snapshot a against the later snapshot b. Added and
+// Changed follow b's order, Removed follows a's.
+func DiffGames(a, b domaingames.TodayResponse) GamesDiff {
+	diff := GamesDiff{Added: []domaingames.Game{}, Removed: []domaingames.Game{}, Changed: []GameDiff{}}
+	before := make(map[string]domaingames.Game, len(a.Games))
+	for _, g := range a.Games {
+		before[g.ID] = g
+	}
+	after := make(map[string]struct{}, len(b.Games))
+	for _, g := range b.Games {
+		after[g.ID] = struct{}{}
+		old, ok := before[g.ID]
+		if !ok {
+			diff.Added = append(diff.Added, g)
+			continue
+		}
+		var changes []FieldChange
+		if old.StatusKind != g.StatusKind {
+			changes = append(changes, FieldChange{Field: DiffFieldStatus, Old: old.StatusKind, New: g.StatusKind})
+		}
+		if old.Score.Home != g.Score.Home {
+			changes = append(changes, FieldChange{Field: DiffFieldHomeScore, Old: old.Score.Home, New: g.Score.Home})
+		}
+		if old.Score.Away != g.Score.Away {
+			changes = append(changes, FieldChange{Field: DiffFieldAwayScore, Old: old.Score.Away, New: g.Score.Away})
+		}
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, GameDiff{GameID: g.ID, Changes: changes})
+		}
+	}
+	for _, g := range a.Games {
+		if _, ok := after[g.ID]; !ok {
+			diff.Removed = append(diff.Removed, g)
+		}
+	}
+	return diff
+}
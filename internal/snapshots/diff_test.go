@@ -0,0 +1,48 @@
+package snapshots
+
+import (
+	"testing"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+func TestDiffGames(t *testing.T) {
+	a := domaingames.NewTodayResponse("2024-01-15", []domaingames.Game{
+		{ID: "kept", StatusKind: domaingames.StatusInProgress, Score: domaingames.Score{Home: 50, Away: 48}},
+		{ID: "gone", StatusKind: domaingames.StatusScheduled},
+		{ID: "same", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 101, Away: 99}},
+	})
+	b := domaingames.NewTodayResponse("2024-01-15", []domaingames.Game{
+		{ID: "same", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 101, Away: 99}},
+		{ID: "kept", StatusKind: domaingames.StatusFinal, Score: domaingames.Score{Home: 50, Away: 52}},
+		{ID: "new", StatusKind: domaingames.StatusScheduled},
+	})
+
+	diff := DiffGames(a, b)
+	if len(diff.Added) != 1 || diff.Added[0].ID != "new" {
+		t.Fatalf("expected one added game, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "gone" {
+		t.Fatalf("expected one removed game, got %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].GameID != "kept" {
+		t.Fatalf("expected only the changed game, got %+v", diff.Changed)
+	}
+	want := []FieldChange{
+		{Field: DiffFieldStatus, Old: domaingames.StatusInProgress, New: domaingames.StatusFinal},
+		{Field: DiffFieldAwayScore, Old: 48, New: 52},
+	}
+	got := diff.Changed[0].Changes
+	if len(got) != len(want) {
+		t.Fatalf("expected changes %+v, got %+v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("change %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	if empty := DiffGames(a, a); len(empty.Added)+len(empty.Removed)+len(empty.Changed) != 0 {
+		t.Fatalf("expected no differences against itself, got %+v", empty)
+	}
+}
@@ -0,0 +1,113 @@
+package snapshots
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+)
+
+// DefaultFreezeGrace is how long a date's games must stay final before the date freezes.
+const DefaultFreezeGrace = 6 * time.Hour
+
+// ErrSnapshotFrozen is returned when an unforced write targets a frozen date.
+var ErrSnapshotFrozen = errors.New("snapshot frozen")
+
+// SetFreezeGrace changes how long every game on a date must stay final before
+// the date is frozen; zero freezes on the first all-final write. Call before the
+// writer is shared.
+func (w *Writer) SetFreezeGrace(d time.Duration) {
+	if w == nil || d < 0 {
+		return
+	}
+	w.freezeGrace = d
+}
+
+// IsFrozen reports whether date is frozen: every game went final at least the
+// freeze grace ago, so the snapshot is treated as immutable until a forced write.
+func (w *Writer) IsFrozen(date string) bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	m, _ := w.loadManifest()
+	return containsDate(m.Games.Frozen, date)
+}
+
+// allSettled reports whether a slate is over: every game is final or canceled.
+func allSettled(games []domaingames.Game) bool {
+	if len(games) == 0 {
+		return false
+	}
+	for _, g := range games {
+		if g.StatusKind != domaingames.StatusFinal && g.StatusKind != domaingames.StatusCanceled {
+			return false
+		}
+	}
+	return true
+}
+
+// trackFreeze records when date's slate was first seen settled and freezes it
+// once the grace period has passed. An unsettled write clears both.
+func (w *Writer) trackFreeze(m *Manifest, date string, settled bool, now time.Time) {
+	meta := &m.Games
+	if !settled {
+		delete(meta.Settled, date)
+		meta.Frozen = removeDate(meta.Frozen, date)
+		return
+	}
+	if meta.Settled == nil {
+		meta.Settled = make(map[string]time.Time)
+	}
+	first, ok := meta.Settled[date]
+	if !ok {
+		first = now
+		meta.Settled[date] = now
+	}
+	if containsDate(meta.Frozen, date) || now.Sub(first) < w.freezeGrace {
+		return
+	}
+	meta.Frozen = append(meta.Frozen, date)
+	sort.Strings(meta.Frozen)
+	logging.Info(w.logger, "snapshot date frozen", "date", date, "settledAt", first.Format(time.RFC3339))
+}
+
+// trackEmpty records whether date's snapshot was written with no games.
+func trackEmpty(meta *GamesMeta, date string, empty bool) {
+	meta.Empty = removeDate(meta.Empty, date)
+	if empty {
+		meta.Empty = append(meta.Empty, date)
+		sort.Strings(meta.Empty)
+	}
+}
+
+// dropFreezeState forgets settled, frozen and empty dates that are no longer
+// on disk.
+func dropFreezeState(meta *GamesMeta) {
+	for date := range meta.Settled {
+		if !containsDate(meta.Dates, date) {
+			delete(meta.Settled, date)
+		}
+	}
+	kept := meta.Frozen[:0]
+	for _, date := range meta.Frozen {
+		if containsDate(meta.Dates, date) {
+			kept = append(kept, date)
+		}
+	}
+	meta.Frozen = kept
+	meta.Empty = keepDates(meta.Empty, meta.Dates)
+}
+
+func removeDate(dates []string, date string) []string {
+	kept := dates[:0]
+	for _, d := range dates {
+		if d != date {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,130 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+func slate(kinds ...domaingames.GameStatusKind) domaingames.TodayResponse {
+	var games []domaingames.Game
+	for i, k := range kinds {
+		games = append(games, domaingames.Game{ID: string(rune('a' + i)), StatusKind: k})
+	}
+	return domaingames.TodayResponse{Games: games}
+}
+
+// freezeWriter returns a writer running on a fake clock.
+func freezeWriter(t *testing.T, grace time.Duration) (*Writer, *teststubs.FakeClock) {
+	t.Helper()
+	w := NewWriter(t.TempDir(), 7)
+	w.SetFreezeGrace(grace)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	w.SetClock(clk)
+	return w, clk
+}
+
+func TestWriterFreezesDateAfterGrace(t *testing.T) {
+	w, clk := freezeWriter(t, time.Hour)
+	date := timeutil.FormatDate(clk.Now())
+
+	if err := w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal, domaingames.StatusInProgress)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal, domaingames.StatusFinal)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if w.IsFrozen(date) {
+		t.Fatalf("expected date not frozen inside the grace period")
+	}
+	m, _ := w.Manifest()
+	if _, ok := m.Games.Settled[date]; !ok {
+		t.Fatalf("expected settled time recorded, got %+v", m.Games)
+	}
+
+	clk.Advance(time.Hour)
+	if err := w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal, domaingames.StatusFinal)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !w.IsFrozen(date) {
+		t.Fatalf("expected date frozen once grace passed")
+	}
+	err := w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal, domaingames.StatusInProgress))
+	if !errors.Is(err, ErrSnapshotFrozen) {
+		t.Fatalf("expected frozen write to be refused, got %v", err)
+	}
+}
+
+func TestWriterUnsettledWriteResetsGrace(t *testing.T) {
+	w, clk := freezeWriter(t, time.Hour)
+	date := timeutil.FormatDate(clk.Now())
+	_ = w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal))
+	_ = w.WriteGamesSnapshot(date, slate(domaingames.StatusInProgress)) // stat correction reopened it
+	clk.Advance(2 * time.Hour)
+	_ = w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal))
+	if w.IsFrozen(date) {
+		t.Fatalf("expected grace to restart after an unsettled write")
+	}
+}
+
+func TestWriterForceOverridesFrozenDate(t *testing.T) {
+	w, clk := freezeWriter(t, 0)
+	date := timeutil.FormatDate(clk.Now())
+	if err := w.WriteGamesSnapshot(date, slate(domaingames.StatusFinal)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !w.IsFrozen(date) {
+		t.Fatalf("expected zero grace to freeze immediately")
+	}
+
+	corrected := slate(domaingames.StatusFinal)
+	corrected.Games[0].Score = domaingames.Score{Home: 101, Away: 99}
+	if err := w.WriteGamesSnapshotForce(date, corrected); err != nil {
+		t.Fatalf("forced write failed: %v", err)
+	}
+	got, err := NewFSStore(w.BasePath()).LoadGames(context.Background(), date)
+	if err != nil || got.Games[0].Score.Home != 101 {
+		t.Fatalf("expected corrected snapshot on disk, got %+v err=%v", got, err)
+	}
+	if !w.IsFrozen(date) {
+		t.Fatalf("expected a settled forced write to stay frozen")
+	}
+
+	if err := w.WriteGamesSnapshotForce(date, slate(domaingames.StatusInProgress)); err != nil {
+		t.Fatalf("forced write failed: %v", err)
+	}
+	if w.IsFrozen(date) {
+		t.Fatalf("expected an unsettled forced write to unfreeze the date")
+	}
+}
+
+func TestDropFreezeStateForPrunedDates(t *testing.T) {
+	now := time.Now()
+	old := timeutil.FormatDate(now.AddDate(0, 0, -30))
+	meta := GamesMeta{
+		Dates:   []string{timeutil.FormatDate(now)},
+		Settled: map[string]time.Time{old: now},
+		Frozen:  []string{old},
+	}
+	dropFreezeState(&meta)
+	if len(meta.Settled) != 0 || len(meta.Frozen) != 0 {
+		t.Fatalf("expected state for pruned dates dropped, got %+v", meta)
+	}
+}
+
+func TestAllSettled(t *testing.T) {
+	if allSettled(nil) {
+		t.Fatalf("expected an empty slate not to count as settled")
+	}
+	if !allSettled(slate(domaingames.StatusFinal, domaingames.StatusCanceled).Games) {
+		t.Fatalf("expected final and canceled games to settle a slate")
+	}
+	if allSettled(slate(domaingames.StatusFinal, domaingames.StatusPostponed).Games) {
+		t.Fatalf("expected postponed games to keep a slate open")
+	}
+}
@@ -0,0 +1,217 @@
+package snapshots
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// snapshotCache holds decoded snapshots keyed by (kind, date). An entry is valid
+// while the file's mtime and size match what was read; the least recently used
+// entry is evicted past max.
+type snapshotCache struct {
+	mu      sync.Mutex
+	max     int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key     string
+	modTime time.Time
+	size    int64
+	payload domaingames.TodayResponse
+	index   map[string]int // provider and canonical game IDs to positions in payload.Games
+}
+
+func newSnapshotCache(max int) *snapshotCache {
+	return &snapshotCache{
+		max:     max,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func cacheKey(kind snapshotKind, date string) string {
+	return string(kind) + "/" + date
+}
+
+// get returns the cached payload when info still describes the cached file.
+func (c *snapshotCache) get(key string, info os.FileInfo) (domaingames.TodayResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return domaingames.TodayResponse{}, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return domaingames.TodayResponse{}, false
+	}
+	c.order.MoveToFront(el)
+	return cloneResponse(entry.payload), true
+}
+
+// findGame looks id up in the cached entry's index; fresh is false when the
+// entry is missing or stale and the caller must load the file.
+func (c *snapshotCache) findGame(key string, info os.FileInfo, id string) (game domaingames.Game, found, fresh bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return domaingames.Game{}, false, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.modTime.Equal(info.ModTime()) || entry.size != info.Size() {
+		return domaingames.Game{}, false, false
+	}
+	c.order.MoveToFront(el)
+	i, ok := entry.index[id]
+	if !ok {
+		return domaingames.Game{}, false, true
+	}
+	return entry.payload.Games[i], true, true
+}
+
+func (c *snapshotCache) put(key string, info os.FileInfo, payload domaingames.TodayResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &cacheEntry{key: key, modTime: info.ModTime(), size: info.Size(), payload: cloneResponse(payload)}
+	entry.index = indexGames(entry.payload.Games)
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *snapshotCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Miss cache bounds: a miss is trusted for missTTL, and past maxMisses dates
+// the oldest miss is dropped.
+const (
+	missTTL   = time.Minute
+	maxMisses = 256
+)
+
+// missCache remembers dates whose games snapshot was missing, so bursts of
+// requests for an absent date skip the stat. Entries expire after ttl; a nil
+// missCache remembers nothing.
+type missCache struct {
+	mu      sync.Mutex
+	max     int
+	ttl     time.Duration
+	order   *list.List // front is the newest miss
+	entries map[string]*list.Element
+}
+
+type missEntry struct {
+	date string
+	seen time.Time
+}
+
+func newMissCache(max int, ttl time.Duration) *missCache {
+	return &missCache{
+		max:     max,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// has reports whether date was found missing within ttl of now, dropping an
+// expired entry.
+func (c *missCache) has(date string, now time.Time) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[date]
+	if !ok {
+		return false
+	}
+	if now.Sub(el.Value.(*missEntry).seen) >= c.ttl {
+		c.order.Remove(el)
+		delete(c.entries, date)
+		return false
+	}
+	return true
+}
+
+func (c *missCache) add(date string, now time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[date]; ok {
+		el.Value.(*missEntry).seen = now
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[date] = c.order.PushFront(&missEntry{date: date, seen: now})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*missEntry).date)
+	}
+}
+
+func (c *missCache) forget(date string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[date]; ok {
+		c.order.Remove(el)
+		delete(c.entries, date)
+	}
+}
+
+func (c *missCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// cloneResponse copies the games slice so callers cannot mutate cached data.
+func cloneResponse(resp domaingames.TodayResponse) domaingames.TodayResponse {
+	resp.Games = append([]domaingames.Game(nil), resp.Games...)
+	return resp
+}
+
+// indexGames maps both ID forms to positions; a provider ID wins if it ever
+// equals another game's canonical ID.
+func indexGames(games []domaingames.Game) map[string]int {
+	index := make(map[string]int, 2*len(games))
+	for i, g := range games {
+		if g.CanonicalID != "" {
+			if _, taken := index[g.CanonicalID]; !taken {
+				index[g.CanonicalID] = i
+			}
+		}
+	}
+	for i, g := range games {
+		if g.ID != "" {
+			index[g.ID] = i
+		}
+	}
+	return index
+}
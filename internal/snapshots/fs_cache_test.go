@@ -0,0 +1,197 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+func TestFSStoreCacheSeesWriterRewrite(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	date := timeutil.FormatDate(time.Now())
+	if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1"}})); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	store := NewFSStoreWithCache(dir, 4)
+	if got, err := store.LoadGames(context.Background(), date); err != nil || got.Games[0].ID != "g1" {
+		t.Fatalf("unexpected first load %+v err=%v", got, err)
+	}
+	if store.cache.len() != 1 {
+		t.Fatalf("expected snapshot cached, got %d entries", store.cache.len())
+	}
+
+	if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g2"}, {ID: "g3"}})); err != nil {
+		t.Fatalf("rewrite failed: %v", err)
+	}
+	got, err := store.LoadGames(context.Background(), date)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	if len(got.Games) != 2 || got.Games[0].ID != "g2" {
+		t.Fatalf("expected rewritten snapshot after invalidation, got %+v", got.Games)
+	}
+}
+
+func TestFSStoreCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	today := time.Now()
+	var dates []string
+	for i := range 3 {
+		d := timeutil.FormatDate(today.AddDate(0, 0, -i))
+		dates = append(dates, d)
+		if err := w.WriteGamesSnapshot(d, domaingames.NewTodayResponse(d, []domaingames.Game{{ID: d}})); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	store := NewFSStoreWithCache(dir, 2)
+	for _, d := range []string{dates[0], dates[1], dates[0], dates[2]} {
+		if _, err := store.LoadGames(context.Background(), d); err != nil {
+			t.Fatalf("load %s failed: %v", d, err)
+		}
+	}
+	if store.cache.len() != 2 {
+		t.Fatalf("expected cache capped at 2, got %d", store.cache.len())
+	}
+	if _, ok := store.cache.entries[cacheKey(kindGames, dates[1])]; ok {
+		t.Fatalf("expected least recently used date %s evicted", dates[1])
+	}
+}
+
+func TestFSStoreCacheReturnsCopies(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	date := timeutil.FormatDate(time.Now())
+	if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1"}})); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	store := NewFSStoreWithCache(dir, 4)
+	first, _ := store.LoadGames(context.Background(), date)
+	first.Games[0].ID = "mutated"
+	second, _ := store.LoadGames(context.Background(), date)
+	if second.Games[0].ID != "g1" {
+		t.Fatalf("expected caller mutation not to reach the cache, got %s", second.Games[0].ID)
+	}
+}
+
+func TestNewFSStoreWithCacheDisabled(t *testing.T) {
+	if NewFSStoreWithCache(t.TempDir(), 0).cache != nil {
+		t.Fatalf("expected maxEntries 0 to disable the cache")
+	}
+}
+
+func BenchmarkFSStoreLoadGames(b *testing.B) {
+	dir := b.TempDir()
+	w := NewWriter(dir, 7)
+	date := timeutil.FormatDate(time.Now())
+	games := make([]domaingames.Game, 0, 15)
+	for i := range 15 {
+		games = append(games, domaingames.Game{ID: fmt.Sprintf("g%d", i), StartTime: "2024-01-01T00:00:00Z", Status: "Final"})
+	}
+	if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, games)); err != nil {
+		b.Fatalf("write failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name  string
+		store *FSStore
+	}{
+		{"uncached", NewFSStore(dir)},
+		{"cached", NewFSStoreWithCache(dir, 64)},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			for b.Loop() {
+				if _, err := tc.store.LoadGames(context.Background(), date); err != nil {
+					b.Fatalf("load failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFSStoreRemembersMissesUntilTTL(t *testing.T) {
+	dir := t.TempDir()
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	store := NewFSStoreWithCache(dir, 4)
+	store.SetClock(clk)
+	date := "2024-01-10"
+
+	if _, err := store.LoadGames(context.Background(), date); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+	if store.misses.len() != 1 {
+		t.Fatalf("expected the miss remembered, got %d entries", store.misses.len())
+	}
+
+	// Written behind the store's back, so only the TTL can reveal it.
+	writeRawGames(t, dir, date)
+	if _, err := store.LoadGames(context.Background(), date); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected the cached miss within the TTL, got %v", err)
+	}
+	if _, ok := store.FindGameByID(context.Background(), date, "g1"); ok {
+		t.Fatalf("expected FindGameByID to honor the cached miss")
+	}
+
+	clk.Advance(missTTL)
+	got, err := store.LoadGames(context.Background(), date)
+	if err != nil || len(got.Games) != 1 {
+		t.Fatalf("expected the snapshot once the miss expired, got %+v err=%v", got, err)
+	}
+}
+
+func TestFSStoreForgetsMissWhenWriterCreatesSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	store := NewFSStoreWithCache(dir, 4)
+	store.SetClock(teststubs.NewFakeClock(time.Now()))
+	w.SetWriteHook(store.ForgetMiss)
+	date := timeutil.FormatDate(time.Now())
+
+	if _, err := store.LoadGames(context.Background(), date); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected a not-exist error, got %v", err)
+	}
+	if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1"}})); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if store.misses.len() != 0 {
+		t.Fatalf("expected the write to drop the miss, got %d entries", store.misses.len())
+	}
+	if _, ok := store.FindGameByID(context.Background(), date, "g1"); !ok {
+		t.Fatalf("expected the new snapshot to be found")
+	}
+}
+
+func TestMissCacheIsBounded(t *testing.T) {
+	c := newMissCache(2, time.Minute)
+	now := time.Now()
+	for _, date := range []string{"2024-01-01", "2024-01-02", "2024-01-03"} {
+		c.add(date, now)
+	}
+	if c.len() != 2 || c.has("2024-01-01", now) || !c.has("2024-01-03", now) {
+		t.Fatalf("expected the oldest miss evicted, got %d entries", c.len())
+	}
+}
+
+func writeRawGames(t *testing.T, dir, date string) {
+	t.Helper()
+	data, err := json.Marshal(domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1"}}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, string(kindGames)), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(GameSnapshotPath(dir, date), data, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
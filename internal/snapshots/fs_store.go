@@ -1,44 +1,198 @@
 package snapshots
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 )
 
-// Store defines how snapshots are loaded.
+// Store defines how snapshots are loaded. Implementations return ctx's error
+// without touching storage once ctx is done.
 type Store interface {
-	LoadGames(date string) (domaingames.TodayResponse, error)
-	FindGameByID(date, id string) (domaingames.Game, bool)
+	LoadGames(ctx context.Context, date string) (domaingames.TodayResponse, error)
+	FindGameByID(ctx context.Context, date, id string) (domaingames.Game, bool)
 }
 
 // FSStore loads snapshots from the filesystem.
 type FSStore struct {
 	basePath string
+	cache    *snapshotCache // nil reads every request from disk
+	misses   *missCache     // set with cache; dates recently found missing
+	clock    clock.Clock
 }
 
-// NewFSStore constructs an FS-backed snapshot store rooted at basePath.
+// NewFSStore constructs an FS-backed snapshot store rooted at basePath that
+// reads from disk on every load.
 func NewFSStore(basePath string) *FSStore {
-	return &FSStore{basePath: basePath}
+	return &FSStore{basePath: basePath, clock: clock.Real()}
+}
+
+// NewFSStoreWithCache is identical to NewFSStore but keeps up to maxEntries
+// decoded snapshots in memory, re-reading a file once its mtime or size changes.
+// It also remembers missing games snapshots for missTTL so repeated requests
+// for an absent date skip the stat; ForgetMiss drops one early. maxEntries <= 0
+// disables both caches.
+func NewFSStoreWithCache(basePath string, maxEntries int) *FSStore {
+	s := NewFSStore(basePath)
+	if maxEntries > 0 {
+		s.cache = newSnapshotCache(maxEntries)
+		s.misses = newMissCache(maxMisses, missTTL)
+	}
+	return s
+}
+
+// SetClock sets the time source for the miss cache's TTL; nil means the real
+// clock. Call before the store is shared.
+func (s *FSStore) SetClock(clk clock.Clock) {
+	if s == nil {
+		return
+	}
+	s.clock = clock.OrReal(clk)
+}
+
+// ForgetMiss drops a cached miss for date so the next load reads the disk. The
+// server installs it as the Writer's write hook.
+func (s *FSStore) ForgetMiss(date string) {
+	if s != nil {
+		s.misses.forget(date)
+	}
 }
 
 // LoadGames reads a snapshot for the given date (YYYY-MM-DD) from disk.
-// Files are expected at {basePath}/games/{date}.json with a TodayResponse payload.
-func (s *FSStore) LoadGames(date string) (domaingames.TodayResponse, error) {
+// Files are expected at {basePath}/games/{date}.json with a TodayResponse
+// payload. A ctx from WithLoadInfo records where the read was served from.
+func (s *FSStore) LoadGames(ctx context.Context, date string) (domaingames.TodayResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	if s != nil && s.cache != nil && validateDate(date) == nil {
+		return s.loadGamesCached(ctx, date)
+	}
+	payload, err := s.loadGames(date)
+	if err == nil {
+		noteLoad(ctx, LoadSourceSnapshot, nil, s.path(kindGames, date))
+	}
+	return payload, err
+}
+
+// LoadGamesForZone reads zone's games snapshot for date, written by
+// Writer.WriteGamesSnapshotForZone. A missing file returns an error
+// satisfying errors.Is(err, fs.ErrNotExist), so callers can fall back to
+// LoadGames. The decoded-snapshot cache applies; the miss cache does not.
+func (s *FSStore) LoadGamesForZone(ctx context.Context, date, zone string) (domaingames.TodayResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	if s == nil {
+		return domaingames.TodayResponse{}, errors.New("snapshot store not configured")
+	}
+	if err := validateDate(date); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	slug, err := ZoneSlug(zone)
+	if err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	path := filepath.Join(s.basePath, string(kindGames), slug, fmt.Sprintf("%s.json", date))
+	var info os.FileInfo
+	key := cacheKey(kindGames, slug+"/"+date)
+	if s.cache != nil {
+		if info, err = os.Stat(path); err != nil {
+			return domaingames.TodayResponse{}, err
+		}
+		if payload, ok := s.cache.get(key, info); ok {
+			noteLoad(ctx, LoadSourceCache, info, path)
+			return payload, nil
+		}
+	}
+	payload, err := decodeGames(path, date)
+	if err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	if s.cache != nil {
+		s.cache.put(key, info, payload)
+	}
+	noteLoad(ctx, LoadSourceSnapshot, info, path)
+	return payload, nil
+}
+
+// decodeGames reads the games snapshot at path, filling in date and any
+// missing canonical IDs.
+func decodeGames(path, date string) (domaingames.TodayResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	var payload domaingames.TodayResponse
+	if err := json.NewDecoder(f).Decode(&payload); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	return fillGames(date, payload), nil
+}
+
+func (s *FSStore) loadGames(date string) (domaingames.TodayResponse, error) {
 	var payload domaingames.TodayResponse
 	if err := s.load(kindGames, date, &payload); err != nil {
 		return domaingames.TodayResponse{}, err
 	}
+	return fillGames(date, payload), nil
+}
+
+func fillGames(date string, payload domaingames.TodayResponse) domaingames.TodayResponse {
 	if payload.Date == "" {
 		payload.Date = date
 	}
+	// Snapshots written before canonical IDs existed get them on read.
+	for _, g := range payload.Games {
+		if g.CanonicalID == "" {
+			domaingames.AssignCanonicalIDs(date, payload.Games)
+			break
+		}
+	}
+	return payload
+}
+
+// loadGamesCached stats the file first so a rewrite by the Writer (which
+// replaces the file) is seen on the next load.
+func (s *FSStore) loadGamesCached(ctx context.Context, date string) (domaingames.TodayResponse, error) {
+	path := s.path(kindGames, date)
+	if s.misses.has(date, s.clock.Now()) {
+		return domaingames.TodayResponse{}, &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.misses.add(date, s.clock.Now())
+		}
+		return domaingames.TodayResponse{}, err
+	}
+	key := cacheKey(kindGames, date)
+	if payload, ok := s.cache.get(key, info); ok {
+		noteLoad(ctx, LoadSourceCache, info, path)
+		return payload, nil
+	}
+	payload, err := s.loadGames(date)
+	if err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	s.cache.put(key, info, payload)
+	noteLoad(ctx, LoadSourceSnapshot, info, path)
 	return payload, nil
 }
 
+func (s *FSStore) path(kind snapshotKind, date string) string {
+	return filepath.Join(s.basePath, string(kind), fmt.Sprintf("%s.json", date))
+}
+
 func (s *FSStore) load(kind snapshotKind, date string, payload any) error {
 	if s == nil {
 		return errors.New("snapshot store not configured")
@@ -46,8 +200,10 @@ func (s *FSStore) load(kind snapshotKind, date string, payload any) error {
 	if date == "" {
 		return errors.New("snapshot date required")
 	}
-	path := filepath.Join(s.basePath, string(kind), fmt.Sprintf("%s.json", date))
-	f, err := os.Open(path)
+	if err := validateTarget(kind, date); err != nil {
+		return err
+	}
+	f, err := os.Open(s.path(kind, date))
 	if err != nil {
 		return err
 	}
@@ -61,14 +217,29 @@ func (s *FSStore) load(kind snapshotKind, date string, payload any) error {
 	return nil
 }
 
-// FindGameByID searches the snapshot for the given date and returns the game if found.
-func (s *FSStore) FindGameByID(date, id string) (domaingames.Game, bool) {
-	resp, err := s.LoadGames(date)
+// FindGameByID searches the snapshot for the given date and returns the game
+// whose provider ID or canonical ID is id. With the cache enabled the lookup
+// uses the cached entry's index instead of scanning.
+func (s *FSStore) FindGameByID(ctx context.Context, date, id string) (domaingames.Game, bool) {
+	if ctx.Err() != nil {
+		return domaingames.Game{}, false
+	}
+	if s != nil && s.cache != nil && validateDate(date) == nil {
+		if s.misses.has(date, s.clock.Now()) {
+			return domaingames.Game{}, false
+		}
+		if info, err := os.Stat(s.path(kindGames, date)); err == nil {
+			if g, found, fresh := s.cache.findGame(cacheKey(kindGames, date), info, id); fresh {
+				return g, found
+			}
+		}
+	}
+	resp, err := s.LoadGames(ctx, date)
 	if err != nil {
 		return domaingames.Game{}, false
 	}
 	for _, g := range resp.Games {
-		if g.ID == id {
+		if g.MatchesID(id) {
 			return g, true
 		}
 	}
@@ -1,12 +1,17 @@
 package snapshots
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 func TestFSStoreLoadGames(t *testing.T) {
@@ -22,7 +27,7 @@ func TestFSStoreLoadGames(t *testing.T) {
 	}
 
 	store := NewFSStore(dir)
-	got, err := store.LoadGames("2024-01-02")
+	got, err := store.LoadGames(context.Background(), "2024-01-02")
 	if err != nil {
 		t.Fatalf("failed to load games: %v", err)
 	}
@@ -31,20 +36,97 @@ func TestFSStoreLoadGames(t *testing.T) {
 	}
 }
 
+func TestFSStoreRoundTripsGameType(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 10)
+	date := timeutil.FormatDate(time.Now())
+	meta := domaingames.GameMeta{Season: "2024", Postseason: true, GameType: domaingames.GameTypePostseason}
+	snap := domaingames.TodayResponse{Date: date, Games: []domaingames.Game{{ID: "g1", Meta: meta}}}
+	if err := w.WriteGamesSnapshot(date, snap); err != nil {
+		t.Fatalf("write snapshot: %v", err)
+	}
+
+	got, err := NewFSStore(dir).LoadGames(context.Background(), date)
+	if err != nil {
+		t.Fatalf("load games: %v", err)
+	}
+	if got.Games[0].Meta != meta {
+		t.Fatalf("expected meta %+v to round-trip, got %+v", meta, got.Games[0].Meta)
+	}
+}
+
+func TestFSStoreLoadsSnapshotWithoutUpdatedAt(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatalf("failed to create games dir: %v", err)
+	}
+	old := []byte(`{"date":"2024-01-02","games":[{"id":"g1","statusKind":"FINAL","score":{"home":100,"away":98}}]}`)
+	if err := os.WriteFile(filepath.Join(dir, "games", "2024-01-02.json"), old, 0o644); err != nil {
+		t.Fatalf("failed to write games snapshot: %v", err)
+	}
+
+	got, err := NewFSStore(dir).LoadGames(context.Background(), "2024-01-02")
+	if err != nil {
+		t.Fatalf("failed to load games: %v", err)
+	}
+	if got.Games[0].UpdatedAt != nil || got.DataAsOf != nil {
+		t.Fatalf("expected no timestamps from an older snapshot, got %+v", got)
+	}
+}
+
 func TestFSStoreErrors(t *testing.T) {
 	store := NewFSStore(t.TempDir())
-	if _, err := store.LoadGames("2024-01-01"); err == nil {
+	if _, err := store.LoadGames(context.Background(), "2024-01-01"); err == nil {
 		t.Fatalf("expected error for missing game snapshot")
 	}
-	if _, err := store.LoadGames(""); err == nil {
+	if _, err := store.LoadGames(context.Background(), ""); err == nil {
 		t.Fatalf("expected error for empty date")
 	}
 	var nilStore *FSStore
-	if _, err := nilStore.LoadGames("2024-01-01"); err == nil {
+	if _, err := nilStore.LoadGames(context.Background(), "2024-01-01"); err == nil {
 		t.Fatalf("expected error for nil store")
 	}
 }
 
+func TestFSStoreStopsOnDoneContext(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatalf("failed to create games dir: %v", err)
+	}
+	data, _ := json.Marshal(domaingames.TodayResponse{Date: "2024-01-02", Games: []domaingames.Game{{ID: "g1"}}})
+	if err := os.WriteFile(filepath.Join(dir, "games", "2024-01-02.json"), data, 0o644); err != nil {
+		t.Fatalf("failed to write games snapshot: %v", err)
+	}
+
+	store := NewFSStoreWithCache(dir, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := store.LoadGames(ctx, "2024-01-02"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, ok := store.FindGameByID(ctx, "2024-01-02", "g1"); ok {
+		t.Fatalf("expected lookup to stop on a canceled context")
+	}
+	// The file was never read, so a live context still loads it.
+	if _, err := store.LoadGames(context.Background(), "2024-01-02"); err != nil {
+		t.Fatalf("expected load with live context, got %v", err)
+	}
+}
+
+func TestFSStoreRejectsMalformedDates(t *testing.T) {
+	dir := t.TempDir()
+	// A file one level up is reachable with a traversal date if unchecked.
+	if err := os.WriteFile(filepath.Join(dir, "escape.json"), []byte(`{"games":[]}`), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	store := NewFSStoreWithCache(filepath.Join(dir, "base"), 4)
+	for _, date := range []string{"../escape", "2024-1-02", "2024/01/02", "2024-02-30", " 2024-01-02"} {
+		if _, err := store.LoadGames(context.Background(), date); !errors.Is(err, ErrInvalidSnapshotDate) {
+			t.Fatalf("expected ErrInvalidSnapshotDate for %q, got %v", date, err)
+		}
+	}
+}
+
 func TestFSStoreFindGameByID(t *testing.T) {
 	dir := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
@@ -63,7 +145,7 @@ func TestFSStoreFindGameByID(t *testing.T) {
 	store := NewFSStore(dir)
 
 	// Found case.
-	g, ok := store.FindGameByID("2024-01-15", "game-2")
+	g, ok := store.FindGameByID(context.Background(), "2024-01-15", "game-2")
 	if !ok {
 		t.Fatalf("expected to find game-2")
 	}
@@ -72,14 +154,96 @@ func TestFSStoreFindGameByID(t *testing.T) {
 	}
 
 	// Not found case.
-	_, ok = store.FindGameByID("2024-01-15", "missing")
+	_, ok = store.FindGameByID(context.Background(), "2024-01-15", "missing")
 	if ok {
 		t.Fatalf("expected not to find missing game")
 	}
 
 	// Missing snapshot case.
-	_, ok = store.FindGameByID("2024-01-01", "game-1")
+	_, ok = store.FindGameByID(context.Background(), "2024-01-01", "game-1")
 	if ok {
 		t.Fatalf("expected not to find game in missing snapshot")
 	}
 }
+
+func TestFSStoreFindGameByCanonicalID(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	date := timeutil.FormatDate(time.Now())
+	canonical := date + "-lal-bos"
+	snap := domaingames.TodayResponse{Games: []domaingames.Game{{
+		ID:       "balldontlie-10",
+		HomeTeam: teams.Team{Abbreviation: "BOS"},
+		AwayTeam: teams.Team{Abbreviation: "LAL"},
+	}}}
+	if err := w.WriteGamesSnapshot(date, snap); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// Snapshots carry both ids.
+	data, err := os.ReadFile(filepath.Join(dir, "games", date+".json"))
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	var written domaingames.TodayResponse
+	if err := json.Unmarshal(data, &written); err != nil || written.Games[0].CanonicalID != canonical {
+		t.Fatalf("expected canonical id %q in snapshot, got %s (%v)", canonical, data, err)
+	}
+
+	for name, store := range map[string]*FSStore{"uncached": NewFSStore(dir), "cached": NewFSStoreWithCache(dir, 4)} {
+		for i := 0; i < 2; i++ { // second pass hits the cache index
+			for _, id := range []string{"balldontlie-10", canonical} {
+				if g, ok := store.FindGameByID(context.Background(), date, id); !ok || g.ID != "balldontlie-10" {
+					t.Fatalf("%s: expected lookup by %q, got %+v %v", name, id, g, ok)
+				}
+			}
+			if _, ok := store.FindGameByID(context.Background(), date, canonical+"-2"); ok {
+				t.Fatalf("%s: expected unknown canonical id not found", name)
+			}
+		}
+	}
+}
+
+func TestFSStoreAssignsCanonicalIDsToOlderSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatalf("failed to create games dir: %v", err)
+	}
+	legacy := `{"date":"2024-01-15","games":[{"id":"fixture-1","homeTeam":{"abbreviation":"NYK"},"awayTeam":{"abbreviation":"MIA"}}]}`
+	if err := os.WriteFile(filepath.Join(dir, "games", "2024-01-15.json"), []byte(legacy), 0o644); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	g, ok := NewFSStoreWithCache(dir, 4).FindGameByID(context.Background(), "2024-01-15", "2024-01-15-mia-nyk")
+	if !ok || g.ID != "fixture-1" {
+		t.Fatalf("expected legacy snapshot found by canonical id, got %+v %v", g, ok)
+	}
+}
+
+func TestFSStoreRecordsLoadInfo(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	writeSimpleSnapshot(t, w, "2024-01-15")
+	info, err := os.Stat(GameSnapshotPath(w.BasePath(), "2024-01-15"))
+	if err != nil {
+		t.Fatalf("stat snapshot: %v", err)
+	}
+
+	cached := NewFSStoreWithCache(w.BasePath(), 4)
+	uncached := NewFSStore(w.BasePath())
+	for i, tc := range []struct {
+		store *FSStore
+		want  LoadSource
+	}{{cached, LoadSourceSnapshot}, {cached, LoadSourceCache}, {uncached, LoadSourceSnapshot}} {
+		ctx, load := WithLoadInfo(context.Background())
+		if _, err := tc.store.LoadGames(ctx, "2024-01-15"); err != nil {
+			t.Fatalf("load %d: %v", i, err)
+		}
+		if load.Source != tc.want || !load.ModTime.Equal(info.ModTime()) {
+			t.Fatalf("load %d: expected %s at %s, got %+v", i, tc.want, info.ModTime(), load)
+		}
+	}
+
+	ctx, load := WithLoadInfo(context.Background())
+	if _, err := cached.LoadGames(ctx, "2024-01-14"); err == nil || load.Source != "" {
+		t.Fatalf("expected a missing snapshot to record nothing, got %+v %v", load, err)
+	}
+}
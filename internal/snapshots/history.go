@@ -0,0 +1,180 @@
+package snapshots
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+)
+
+// DefaultHistoryRetentionDays is how long archived versions are kept when
+// HistoryConfig.RetentionDays is unset.
+const DefaultHistoryRetentionDays = 7
+
+// historyIDLayout names archived versions by the UTC time they were replaced,
+// so IDs sort chronologically and cannot carry path separators.
+const historyIDLayout = "20060102T150405.000000000Z"
+
+// ErrInvalidHistoryVersion is returned for a version ID that is not one the
+// writer could have produced.
+var ErrInvalidHistoryVersion = errors.New("invalid snapshot history version")
+
+// HistoryConfig turns on archiving of replaced games snapshots under
+// games/history/{date}/{id}.json. Archives older than RetentionDays (default
+// DefaultHistoryRetentionDays) are pruned on the next archive.
+type HistoryConfig struct {
+	Enabled       bool
+	RetentionDays int
+}
+
+// HistoryVersion describes one archived games snapshot.
+type HistoryVersion struct {
+	ID         string    `json:"id"`
+	ArchivedAt time.Time `json:"archivedAt"`
+	Games      int       `json:"games"`
+}
+
+// SetHistory configures archiving of replaced games snapshots. Call before the
+// writer is shared.
+func (w *Writer) SetHistory(cfg HistoryConfig) {
+	if w == nil {
+		return
+	}
+	if cfg.RetentionDays <= 0 {
+		cfg.RetentionDays = DefaultHistoryRetentionDays
+	}
+	w.history = cfg
+}
+
+func (w *Writer) historyDir(date string) string {
+	return filepath.Join(w.basePath, string(kindGames), "history", date)
+}
+
+// archiveGamesLocked keeps previous, the bytes about to be replaced for date,
+// as a history version. Archiving is a debugging aid, so failures are logged
+// and never block the write. Callers hold w.mu.
+func (w *Writer) archiveGamesLocked(date string, previous []byte) {
+	now := w.clock.Now().UTC()
+	dir := w.historyDir(date)
+	path := filepath.Join(dir, now.Format(historyIDLayout)+".json")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		logging.Warn(w.logger, "snapshot history archive failed", "date", date, "err", err)
+		return
+	}
+	if err := writeFileSynced(path, previous); err != nil {
+		logging.Warn(w.logger, "snapshot history archive failed", "date", date, "err", err)
+		return
+	}
+	w.pruneHistoryLocked(now)
+}
+
+// pruneHistoryLocked removes versions archived before the retention cutoff,
+// then any date directory left empty.
+func (w *Writer) pruneHistoryLocked(now time.Time) {
+	cutoff := now.AddDate(0, 0, -w.history.RetentionDays)
+	root := filepath.Join(w.basePath, string(kindGames), "history")
+	dates, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, d := range dates {
+		if !d.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, d.Name())
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		kept := 0
+		for _, e := range entries {
+			at, ok := parseHistoryID(strings.TrimSuffix(e.Name(), ".json"))
+			if ok && at.Before(cutoff) {
+				if err := os.Remove(filepath.Join(dir, e.Name())); err == nil {
+					continue
+				}
+			}
+			kept++
+		}
+		if kept == 0 {
+			_ = os.Remove(dir)
+		}
+	}
+}
+
+// GamesHistory lists the archived versions of date's games snapshot, oldest
+// first. A date that was never archived has no versions.
+func (w *Writer) GamesHistory(date string) ([]HistoryVersion, error) {
+	if w == nil {
+		return nil, fmt.Errorf("snapshot writer not configured")
+	}
+	if err := validateDate(date); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(w.historyDir(date))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []HistoryVersion{}, nil
+		}
+		return nil, err
+	}
+	versions := []HistoryVersion{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		id := strings.TrimSuffix(e.Name(), ".json")
+		at, ok := parseHistoryID(id)
+		if !ok {
+			continue
+		}
+		snap, _, err := readSnapshotFile(filepath.Join(w.historyDir(date), e.Name()), date)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, HistoryVersion{ID: id, ArchivedAt: at, Games: len(snap.Games)})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].ID < versions[j].ID })
+	return versions, nil
+}
+
+// LoadGamesVersion reads one archived version of date's games snapshot. It
+// returns ErrInvalidHistoryVersion for a malformed id and ErrSnapshotNotFound
+// when no such version exists.
+func (w *Writer) LoadGamesVersion(date, id string) (domaingames.TodayResponse, error) {
+	if w == nil {
+		return domaingames.TodayResponse{}, fmt.Errorf("snapshot writer not configured")
+	}
+	if err := validateDate(date); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	if _, ok := parseHistoryID(id); !ok {
+		return domaingames.TodayResponse{}, fmt.Errorf("%w: %q", ErrInvalidHistoryVersion, id)
+	}
+	snap, _, err := readSnapshotFile(filepath.Join(w.historyDir(date), id+".json"), date)
+	if os.IsNotExist(err) {
+		return domaingames.TodayResponse{}, fmt.Errorf("%w: %s version %s", ErrSnapshotNotFound, date, id)
+	}
+	if err != nil {
+		return domaingames.TodayResponse{}, err
+	}
+	if snap.Date == "" {
+		snap.Date = date
+	}
+	return snap, nil
+}
+
+// parseHistoryID accepts only IDs in exactly the form the writer produces.
+func parseHistoryID(id string) (time.Time, bool) {
+	at, err := time.Parse(historyIDLayout, id)
+	if err != nil || at.Format(historyIDLayout) != id {
+		return time.Time{}, false
+	}
+	return at, true
+}
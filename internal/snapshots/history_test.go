@@ -0,0 +1,113 @@
+package snapshots
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func historyWriter(t *testing.T, clk *teststubs.FakeClock) *Writer {
+	t.Helper()
+	w := NewWriter(t.TempDir(), 30)
+	w.SetClock(clk)
+	w.SetHistory(HistoryConfig{Enabled: true, RetentionDays: 2})
+	return w
+}
+
+func TestWriterArchivesReplacedGamesSnapshot(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	w := historyWriter(t, clk)
+	date := "2024-01-15"
+	first := domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1", StatusKind: domaingames.StatusScheduled}})
+	if err := w.WriteGamesSnapshot(date, first); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if versions, _ := w.GamesHistory(date); len(versions) != 0 {
+		t.Fatalf("expected nothing archived for a new file, got %+v", versions)
+	}
+
+	// An identical rewrite is skipped, so there is nothing to archive.
+	if err := w.WriteGamesSnapshot(date, first); err != nil {
+		t.Fatalf("rewrite failed: %v", err)
+	}
+	if versions, _ := w.GamesHistory(date); len(versions) != 0 {
+		t.Fatalf("expected no archive on an identical write, got %+v", versions)
+	}
+
+	clk.Advance(time.Minute)
+	second := domaingames.NewTodayResponse(date, []domaingames.Game{{ID: "g1", StatusKind: domaingames.StatusInProgress}})
+	if err := w.WriteGamesSnapshot(date, second); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	versions, err := w.GamesHistory(date)
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("expected one archived version, got %+v err=%v", versions, err)
+	}
+	if !versions[0].ArchivedAt.Equal(clk.Now()) || versions[0].Games != 1 {
+		t.Fatalf("expected the version stamped from the writer clock, got %+v", versions[0])
+	}
+	archived, err := w.LoadGamesVersion(date, versions[0].ID)
+	if err != nil || archived.Games[0].StatusKind != domaingames.StatusScheduled {
+		t.Fatalf("expected the previous snapshot archived, got %+v err=%v", archived, err)
+	}
+}
+
+func TestWriterSkipsHistoryWhenDisabled(t *testing.T) {
+	w := NewWriter(t.TempDir(), 30)
+	date := "2024-01-15"
+	for _, id := range []string{"g1", "g2"} {
+		if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: id}})); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if versions, err := w.GamesHistory(date); err != nil || len(versions) != 0 {
+		t.Fatalf("expected no history when disabled, got %+v err=%v", versions, err)
+	}
+}
+
+func TestWriterPrunesExpiredHistory(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	w := historyWriter(t, clk)
+	write := func(date, id string) {
+		t.Helper()
+		if err := w.WriteGamesSnapshot(date, domaingames.NewTodayResponse(date, []domaingames.Game{{ID: id}})); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	write("2024-01-14", "a")
+	write("2024-01-14", "b") // archives "a" on day 0
+
+	clk.Advance(3 * 24 * time.Hour)
+	write("2024-01-17", "c")
+	write("2024-01-17", "d") // archives "c" and prunes day 0's archive
+
+	if versions, _ := w.GamesHistory("2024-01-14"); len(versions) != 0 {
+		t.Fatalf("expected the expired version pruned, got %+v", versions)
+	}
+	if _, err := os.Stat(w.historyDir("2024-01-14")); !os.IsNotExist(err) {
+		t.Fatalf("expected the emptied date directory removed, got %v", err)
+	}
+	if versions, _ := w.GamesHistory("2024-01-17"); len(versions) != 1 {
+		t.Fatalf("expected the fresh version kept, got %+v", versions)
+	}
+	if dates, _ := w.listDates(kindGames); len(dates) != 2 {
+		t.Fatalf("expected the history directory ignored as a date, got %v", dates)
+	}
+}
+
+func TestLoadGamesVersionErrors(t *testing.T) {
+	w := historyWriter(t, teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)))
+	if _, err := w.LoadGamesVersion("2024-01-15", "../../manifest"); !errors.Is(err, ErrInvalidHistoryVersion) {
+		t.Fatalf("expected an invalid version error, got %v", err)
+	}
+	if _, err := w.LoadGamesVersion("2024-01-15", "20240115T120000.000000000Z"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Fatalf("expected not found for a missing version, got %v", err)
+	}
+	if _, err := w.GamesHistory("2024-1-15"); !errors.Is(err, ErrInvalidSnapshotDate) {
+		t.Fatalf("expected an invalid date error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// Reasons a snapshot file is skipped during hydration.
+const (
+	SkipReasonName   = "name"   // file name is not YYYY-MM-DD.json
+	SkipReasonRead   = "read"   // file could not be opened
+	SkipReasonDecode = "decode" // file is not a valid TodayResponse document
+	SkipReasonSchema = "schema" // document decoded but its contents are inconsistent
+)
+
+// HydrationReport records what the startup pass found under the snapshot root.
+// It is built once and kept for the process lifetime.
+type HydrationReport struct {
+	At       time.Time      `json:"at"`
+	BasePath string         `json:"basePath"`
+	Loaded   []HydratedFile `json:"loaded"`
+	Skipped  []SkippedFile  `json:"skipped"`
+	Games    int            `json:"games"`
+	Teams    int            `json:"teams"`
+}
+
+// HydratedFile is one snapshot accepted during hydration.
+type HydratedFile struct {
+	Date  string `json:"date"`
+	File  string `json:"file"`
+	Games int    `json:"games"`
+}
+
+// SkippedFile is one snapshot rejected during hydration.
+type SkippedFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Dates returns the loaded snapshot dates in order.
+func (r HydrationReport) Dates() []string {
+	dates := make([]string, 0, len(r.Loaded))
+	for _, f := range r.Loaded {
+		dates = append(dates, f.Date)
+	}
+	return dates
+}
+
+// Hydrate reads every games snapshot under basePath and reports which were
+// usable. A missing directory yields an empty report.
+func Hydrate(basePath string, at time.Time) HydrationReport {
+	report := HydrationReport{
+		At:       at.UTC(),
+		BasePath: basePath,
+		Loaded:   []HydratedFile{},
+		Skipped:  []SkippedFile{},
+	}
+	dir := filepath.Join(basePath, string(kindGames))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return report
+	}
+	teams := make(map[string]struct{})
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		date := strings.TrimSuffix(e.Name(), ".json")
+		if _, err := timeutil.ParseDate(date); err != nil {
+			report.Skipped = append(report.Skipped, SkippedFile{File: path, Reason: SkipReasonName})
+			continue
+		}
+		snap, reason, err := readSnapshotFile(path, date)
+		if err != nil {
+			report.Skipped = append(report.Skipped, SkippedFile{File: path, Reason: reason, Detail: err.Error()})
+			continue
+		}
+		report.Loaded = append(report.Loaded, HydratedFile{Date: date, File: path, Games: len(snap.Games)})
+		report.Games += len(snap.Games)
+		for _, g := range snap.Games {
+			for _, id := range []string{g.HomeTeam.ID, g.AwayTeam.ID} {
+				if id != "" {
+					teams[id] = struct{}{}
+				}
+			}
+		}
+	}
+	sort.Slice(report.Loaded, func(i, j int) bool { return report.Loaded[i].Date < report.Loaded[j].Date })
+	report.Teams = len(teams)
+	return report
+}
+
+func readSnapshotFile(path, date string) (domaingames.TodayResponse, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return domaingames.TodayResponse{}, SkipReasonRead, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	var snap domaingames.TodayResponse
+	if err := json.NewDecoder(f).Decode(&snap); err != nil {
+		return domaingames.TodayResponse{}, SkipReasonDecode, err
+	}
+	if snap.Date != "" && snap.Date != date {
+		return domaingames.TodayResponse{}, SkipReasonSchema, fmt.Errorf("payload date %s does not match file name", snap.Date)
+	}
+	for i, g := range snap.Games {
+		if g.ID == "" {
+			return domaingames.TodayResponse{}, SkipReasonSchema, fmt.Errorf("game %d has no id", i)
+		}
+	}
+	return snap, "", nil
+}
+
+// Log writes the report as a single structured line.
+func (r HydrationReport) Log(logger *slog.Logger) {
+	skipped := make([]string, 0, len(r.Skipped))
+	for _, s := range r.Skipped {
+		skipped = append(skipped, filepath.Base(s.File)+":"+s.Reason)
+	}
+	logging.Info(logger, "snapshot hydration complete",
+		"basePath", r.BasePath,
+		"dates", r.Dates(),
+		"games", r.Games,
+		"teams", r.Teams,
+		"skipped", skipped,
+	)
+}
@@ -0,0 +1,108 @@
+package snapshots
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/domain/teams"
+)
+
+func seedSnapshotFile(t *testing.T, base, name string, body []byte) {
+	t.Helper()
+	path := filepath.Join(base, "games", name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func seedGames(t *testing.T, base, date string, games ...domaingames.Game) {
+	t.Helper()
+	body, err := json.Marshal(domaingames.NewTodayResponse(date, games))
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	seedSnapshotFile(t, base, date+".json", body)
+}
+
+func TestHydrateReportsLoadedAndSkippedFiles(t *testing.T) {
+	base := t.TempDir()
+	bos, nyk, lal := teams.Team{ID: "bos"}, teams.Team{ID: "nyk"}, teams.Team{ID: "lal"}
+	seedGames(t, base, "2024-03-02", domaingames.Game{ID: "g2", HomeTeam: bos, AwayTeam: lal})
+	seedGames(t, base, "2024-03-01",
+		domaingames.Game{ID: "g1", HomeTeam: bos, AwayTeam: nyk},
+		domaingames.Game{ID: "g3", HomeTeam: nyk, AwayTeam: lal},
+	)
+	seedSnapshotFile(t, base, "2024-03-03.json", []byte(`{"date":"2024-03-03","games":[`))
+	seedGames(t, base, "2024-03-04", domaingames.Game{HomeTeam: bos})
+	seedSnapshotFile(t, base, "notes.json", []byte(`{}`))
+
+	at := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	report := Hydrate(base, at)
+
+	if !report.At.Equal(at) || report.BasePath != base {
+		t.Fatalf("unexpected report header %+v", report)
+	}
+	if got := strings.Join(report.Dates(), ","); got != "2024-03-01,2024-03-02" {
+		t.Fatalf("expected two loaded dates in order, got %s", got)
+	}
+	if report.Loaded[0].Games != 2 || report.Loaded[0].File != filepath.Join(base, "games", "2024-03-01.json") {
+		t.Fatalf("unexpected loaded entry %+v", report.Loaded[0])
+	}
+	if report.Games != 3 || report.Teams != 3 {
+		t.Fatalf("expected 3 games across 3 teams, got %d/%d", report.Games, report.Teams)
+	}
+
+	reasons := map[string]string{}
+	for _, s := range report.Skipped {
+		reasons[filepath.Base(s.File)] = s.Reason
+	}
+	want := map[string]string{
+		"2024-03-03.json": SkipReasonDecode,
+		"2024-03-04.json": SkipReasonSchema,
+		"notes.json":      SkipReasonName,
+	}
+	if len(reasons) != len(want) {
+		t.Fatalf("expected %d skipped files, got %+v", len(want), report.Skipped)
+	}
+	for file, reason := range want {
+		if reasons[file] != reason {
+			t.Fatalf("expected %s skipped for %s, got %q", file, reason, reasons[file])
+		}
+	}
+}
+
+func TestHydrateMissingDirectoryIsEmpty(t *testing.T) {
+	report := Hydrate(filepath.Join(t.TempDir(), "absent"), time.Now())
+	if len(report.Loaded) != 0 || len(report.Skipped) != 0 || report.Games != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+}
+
+func TestHydrationReportLogsSingleLine(t *testing.T) {
+	base := t.TempDir()
+	seedGames(t, base, "2024-03-01", domaingames.Game{ID: "g1"})
+	seedSnapshotFile(t, base, "2024-03-02.json", []byte(`not json`))
+
+	var buf bytes.Buffer
+	Hydrate(base, time.Now()).Log(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	out := strings.TrimSpace(buf.String())
+	if strings.Count(out, "\n") != 0 {
+		t.Fatalf("expected a single log line, got %q", out)
+	}
+	for _, want := range []string{"snapshot hydration complete", "games=1", "2024-03-02.json:decode"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in log line %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package snapshots
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// LoadSource says where an FSStore games read was served from.
+type LoadSource string
+
+const (
+	LoadSourceCache    LoadSource = "cache"    // decoded snapshot held in memory
+	LoadSourceSnapshot LoadSource = "snapshot" // read from the snapshot file
+)
+
+// LoadInfo describes the last games snapshot an FSStore read with a context
+// from WithLoadInfo. It is filled in by the read itself, so a request that
+// loads one snapshot at a time needs no locking.
+type LoadInfo struct {
+	Source LoadSource
+	// ModTime is when the snapshot file served was last written.
+	ModTime time.Time
+}
+
+type loadInfoKey struct{}
+
+// WithLoadInfo returns ctx carrying an empty LoadInfo that FSStore games
+// reads made with it fill in; stores that do not record leave it zero.
+func WithLoadInfo(ctx context.Context) (context.Context, *LoadInfo) {
+	info := &LoadInfo{}
+	return context.WithValue(ctx, loadInfoKey{}, info), info
+}
+
+// noteLoad records a games read in ctx's LoadInfo, if it has one. stat, if
+// non-nil, is the served file's; otherwise path is stat'ed for its mtime.
+func noteLoad(ctx context.Context, source LoadSource, stat os.FileInfo, path string) {
+	info, ok := ctx.Value(loadInfoKey{}).(*LoadInfo)
+	if !ok {
+		return
+	}
+	if stat == nil {
+		stat, _ = os.Stat(path)
+	}
+	info.Source = source
+	info.ModTime = time.Time{}
+	if stat != nil {
+		info.ModTime = stat.ModTime().UTC()
+	}
+}
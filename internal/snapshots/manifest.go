@@ -2,35 +2,81 @@ package snapshots
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
 )
 
+// errManifestCorrupt marks a manifest that exists but cannot be trusted.
+var errManifestCorrupt = errors.New("snapshot manifest corrupt")
+
 // Manifest tracks snapshot metadata.
 type Manifest struct {
 	Version     int       `json:"version"`
 	GeneratedAt time.Time `json:"generatedAt"`
 	Retention   Retention `json:"retention"`
 	Games       GamesMeta `json:"games"`
+	Teams       *KindMeta `json:"teams,omitempty"`   // nil until a teams snapshot is written
+	Players     *KindMeta `json:"players,omitempty"` // nil until a players snapshot is written
 }
 
 type Retention struct {
-	GamesDays int `json:"gamesDays"`
+	GamesDays   int `json:"gamesDays"`
+	TeamsDays   int `json:"teamsDays,omitempty"`
+	PlayersDays int `json:"playersDays,omitempty"`
+}
+
+func (c RetentionConfig) manifest() Retention {
+	return Retention{GamesDays: c.GamesDays, TeamsDays: c.TeamsDays, PlayersDays: c.PlayersDays}
 }
 
 type GamesMeta struct {
 	Dates         []string  `json:"dates"`
 	LastRefreshed time.Time `json:"lastRefreshed"`
+	Pinned        []string  `json:"pinned,omitempty"` // dates exempt from retention pruning
+	// Settled maps a date to when all its games were first seen final; Frozen
+	// lists dates that stayed settled past the freeze grace and are immutable
+	// except for forced writes.
+	Settled map[string]time.Time `json:"settled,omitempty"`
+	Frozen  []string             `json:"frozen,omitempty"`
+	// Empty lists dates whose snapshot was written with no games, such as
+	// past off-days the syncer has confirmed and need not fetch again.
+	Empty []string `json:"empty,omitempty"`
+	// Zones maps a zone slug (see ZoneSlug) to its zone-scoped snapshots.
+	Zones map[string]*ZoneMeta `json:"zones,omitempty"`
 }
 
-func defaultManifest(retentionDays int) Manifest {
+// ZoneMeta tracks one zone's games snapshots; they share the games
+// retention window and pins but are never frozen.
+type ZoneMeta struct {
+	Zone          string    `json:"zone"`
+	Dates         []string  `json:"dates"`
+	LastRefreshed time.Time `json:"lastRefreshed"`
+}
+
+// KindMeta tracks the snapshot dates of a kind without pins or freezing.
+type KindMeta struct {
+	Dates         []string  `json:"dates"`
+	LastRefreshed time.Time `json:"lastRefreshed"`
+}
+
+// kindField returns the manifest field holding the teams or players meta.
+func (m *Manifest) kindField(kind snapshotKind) **KindMeta {
+	if kind == kindPlayers {
+		return &m.Players
+	}
+	return &m.Teams
+}
+
+func defaultManifest(retention Retention, now time.Time) Manifest {
 	return Manifest{
 		Version:     1,
-		GeneratedAt: time.Now().UTC(),
-		Retention: Retention{
-			GamesDays: retentionDays,
-		},
+		GeneratedAt: now.UTC(),
+		Retention:   retention,
 		Games: GamesMeta{
 			Dates:         []string{},
 			LastRefreshed: time.Time{},
@@ -38,31 +84,252 @@ func defaultManifest(retentionDays int) Manifest {
 	}
 }
 
-func readManifest(path string, retentionDays int) (Manifest, error) {
+// readManifest decodes the manifest at path; on failure it returns the
+// default manifest generated at now alongside the error.
+func readManifest(path string, retention Retention, now time.Time) (Manifest, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return defaultManifest(retentionDays), err
+		return defaultManifest(retention, now), err
 	}
 	defer func() {
 		_ = f.Close()
 	}()
 	var m Manifest
 	if err := json.NewDecoder(f).Decode(&m); err != nil {
-		return defaultManifest(retentionDays), err
+		return defaultManifest(retention, now), fmt.Errorf("%w: %v", errManifestCorrupt, err)
+	}
+	if m.Version < 1 {
+		return defaultManifest(retention, now), fmt.Errorf("%w: missing version", errManifestCorrupt)
 	}
 	return m, nil
 }
 
-func writeManifest(basePath string, m Manifest) error {
-	m.GeneratedAt = time.Now().UTC()
+// writeManifest atomically replaces the manifest, stamped as generated at now.
+func writeManifest(basePath string, m Manifest, now time.Time) error {
+	m.GeneratedAt = now.UTC()
 	path := filepath.Join(basePath, "manifest.json")
 	tmp := path + ".tmp"
 	data, err := json.MarshalIndent(m, "", "  ")
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := writeFileSynced(tmp, data); err != nil {
 		return err
 	}
 	return os.Rename(tmp, path)
 }
+
+// writeFileSynced writes data and fsyncs it so a crash cannot leave a renamed
+// but empty file behind.
+func writeFileSynced(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadManifest reads the manifest. A missing manifest yields the default; a
+// corrupt one is rebuilt from the snapshot files on disk and rewritten.
+func (w *Writer) loadManifest() (Manifest, error) {
+	m, err := readManifest(w.manifestPath(), w.retention.manifest(), w.clock.Now())
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if !errors.Is(err, errManifestCorrupt) {
+		return m, err
+	}
+	rebuilt, rebuildErr := w.rebuildManifest()
+	if rebuildErr != nil {
+		logging.Error(w.logger, "snapshot manifest corrupt and rebuild failed", rebuildErr, "path", w.manifestPath())
+		return m, rebuildErr
+	}
+	logging.Warn(w.logger, "snapshot manifest corrupt; rebuilt from snapshot files",
+		"path", w.manifestPath(),
+		"error", err,
+		"dates", len(rebuilt.Games.Dates),
+		"note", "pinned dates are not recoverable",
+	)
+	return rebuilt, nil
+}
+
+// rebuildManifest recreates the manifest from the snapshot directories and
+// writes it. LastRefreshed becomes the newest snapshot's mtime; pins cannot be
+// recovered.
+func (w *Writer) rebuildManifest() (Manifest, error) {
+	m, _, err := w.scanManifest(defaultManifest(w.retention.manifest(), w.clock.Now()))
+	if err != nil {
+		return m, err
+	}
+	if err := w.saveManifest(m); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// ManifestChange is one kind's outcome of RebuildManifest: the dates now
+// listed and how they differ from the replaced manifest.
+type ManifestChange struct {
+	Dates   []string `json:"dates"`
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+}
+
+// ManifestRebuild reports RebuildManifest's changes per snapshot kind.
+type ManifestRebuild struct {
+	Games   ManifestChange `json:"games"`
+	Teams   ManifestChange `json:"teams"`
+	Players ManifestChange `json:"players"`
+	// Zones is keyed by zone slug, covering zones listed before or found now.
+	Zones map[string]ManifestChange `json:"zones,omitempty"`
+}
+
+// Changed reports whether any kind or zone gained or lost dates.
+func (r ManifestRebuild) Changed() bool {
+	changes := []ManifestChange{r.Games, r.Teams, r.Players}
+	for _, c := range r.Zones {
+		changes = append(changes, c)
+	}
+	for _, c := range changes {
+		if len(c.Added) > 0 || len(c.Removed) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RebuildManifest replaces the manifest with one reconstructed from the
+// snapshot files, for when files were added or removed by hand and the date
+// lists no longer match the disk. Each kind's LastRefreshed becomes its newest
+// file's mtime (kept as is when the kind has no files); pins and freeze state
+// are kept for dates still on disk. The manifest holds no checksums (export
+// computes them from the files), so there are none to recompute.
+func (w *Writer) RebuildManifest() (ManifestRebuild, error) {
+	if w == nil {
+		return ManifestRebuild{}, errors.New("snapshot writer not configured")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	prev, err := readManifest(w.manifestPath(), w.retention.manifest(), w.clock.Now())
+	if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, errManifestCorrupt) {
+		return ManifestRebuild{}, err
+	}
+	m, report, err := w.scanManifest(prev)
+	if err != nil {
+		return report, err
+	}
+	if err := w.saveManifest(m); err != nil {
+		return report, err
+	}
+	logging.Info(w.logger, "snapshot manifest rebuilt",
+		"path", w.manifestPath(),
+		"gamesAdded", len(report.Games.Added),
+		"gamesRemoved", len(report.Games.Removed),
+		"teamsAdded", len(report.Teams.Added),
+		"teamsRemoved", len(report.Teams.Removed),
+		"playersAdded", len(report.Players.Added),
+		"playersRemoved", len(report.Players.Removed),
+		"zones", len(report.Zones),
+	)
+	return report, nil
+}
+
+// scanManifest returns prev with every kind's dates and LastRefreshed taken
+// from disk, and pins and freeze state dropped for dates that are gone.
+func (w *Writer) scanManifest(prev Manifest) (Manifest, ManifestRebuild, error) {
+	m := prev
+	m.Retention = w.retention.manifest()
+	var report ManifestRebuild
+	for _, kind := range []snapshotKind{kindGames, kindTeams, kindPlayers} {
+		dates, err := w.listDates(kind)
+		if err != nil {
+			return m, report, err
+		}
+		if dates == nil {
+			dates = []string{}
+		}
+		var (
+			list *[]string
+			last *time.Time
+		)
+		switch kind {
+		case kindGames:
+			list, last = &m.Games.Dates, &m.Games.LastRefreshed
+		default:
+			field := m.kindField(kind)
+			if *field == nil && len(dates) == 0 {
+				continue
+			}
+			// Copy so prev's meta is left untouched.
+			var meta KindMeta
+			if *field != nil {
+				meta = **field
+			}
+			*field = &meta
+			list, last = &meta.Dates, &meta.LastRefreshed
+		}
+		change := ManifestChange{
+			Dates:   dates,
+			Added:   missingDates(dates, *list),
+			Removed: missingDates(*list, dates),
+		}
+		*list = dates
+		if newest := w.newestModTime(kind, dates); !newest.IsZero() {
+			*last = newest
+		}
+		switch kind {
+		case kindGames:
+			report.Games = change
+		case kindTeams:
+			report.Teams = change
+		case kindPlayers:
+			report.Players = change
+		}
+	}
+	zones, zoneChanges, err := w.scanZones(prev.Games.Zones)
+	if err != nil {
+		return m, report, err
+	}
+	m.Games.Zones = zones
+	if len(zoneChanges) > 0 {
+		report.Zones = zoneChanges
+	}
+	m.Games.Pinned = keepDates(m.Games.Pinned, m.Games.Dates)
+	dropFreezeState(&m.Games)
+	return m, report, nil
+}
+
+// newestModTime returns the latest mtime among the kind's snapshot files.
+func (w *Writer) newestModTime(kind snapshotKind, dates []string) time.Time {
+	var newest time.Time
+	for _, d := range dates {
+		if info, err := os.Stat(w.snapshotPath(kind, d, 0)); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime().UTC()
+		}
+	}
+	return newest
+}
+
+// missingDates returns the entries of list that are not in other, never nil.
+func missingDates(list, other []string) []string {
+	missing := []string{}
+	for _, date := range list {
+		if !containsDate(other, date) {
+			missing = append(missing, date)
+		}
+	}
+	return missing
+}
+
+// saveManifest writes m stamped with the writer's clock.
+func (w *Writer) saveManifest(m Manifest) error {
+	return writeManifest(w.basePath, m, w.clock.Now())
+}
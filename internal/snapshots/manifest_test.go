@@ -1,9 +1,17 @@
 package snapshots
 
 import (
+	"bytes"
+	"errors"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 func TestReadManifestReturnsDefaultOnDecodeError(t *testing.T) {
@@ -13,7 +21,7 @@ func TestReadManifestReturnsDefaultOnDecodeError(t *testing.T) {
 		t.Fatalf("failed to write manifest: %v", err)
 	}
 
-	m, err := readManifest(path, 5)
+	m, err := readManifest(path, Retention{GamesDays: 5}, time.Now())
 	if err == nil {
 		t.Fatalf("expected decode error")
 	}
@@ -23,15 +31,15 @@ func TestReadManifestReturnsDefaultOnDecodeError(t *testing.T) {
 }
 
 func TestWriteManifestFailsWhenPathMissing(t *testing.T) {
-	if err := writeManifest(filepath.Join("does-not-exist", "missing"), defaultManifest(3)); err == nil {
+	if err := writeManifest(filepath.Join("does-not-exist", "missing"), defaultManifest(Retention{GamesDays: 3}, time.Now()), time.Now()); err == nil {
 		t.Fatalf("expected error when base path missing")
 	}
 }
 
 func TestWriteManifestSuccess(t *testing.T) {
 	dir := t.TempDir()
-	m := defaultManifest(4)
-	if err := writeManifest(dir, m); err != nil {
+	m := defaultManifest(Retention{GamesDays: 4}, time.Now())
+	if err := writeManifest(dir, m, time.Now()); err != nil {
 		t.Fatalf("expected manifest to be written, got %v", err)
 	}
 	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
@@ -42,3 +50,163 @@ func TestWriteManifestSuccess(t *testing.T) {
 		t.Fatalf("expected manifest content")
 	}
 }
+
+func TestManifestRebuiltFromSnapshotsWhenCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 7)
+	var logs bytes.Buffer
+	w.SetLogger(slog.New(slog.NewTextHandler(&logs, nil)))
+	today := time.Now()
+	want := []string{timeutil.FormatDate(today.AddDate(0, 0, -1)), timeutil.FormatDate(today)}
+	for _, d := range want {
+		if err := w.WriteGamesSnapshot(d, domaingames.TodayResponse{Date: d}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	path := filepath.Join(dir, "manifest.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read manifest failed: %v", err)
+	}
+	// Simulate a torn write: keep only the first half of the bytes.
+	if err := os.WriteFile(path, data[:len(data)/2], 0o644); err != nil {
+		t.Fatalf("corrupt manifest failed: %v", err)
+	}
+
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("expected rebuilt manifest, got %v", err)
+	}
+	if strings.Join(m.Games.Dates, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected rebuilt dates %v, got %v", want, m.Games.Dates)
+	}
+	if m.Games.LastRefreshed.IsZero() || m.Retention.GamesDays != 7 {
+		t.Fatalf("expected rebuilt metadata, got %+v", m)
+	}
+	if !strings.Contains(logs.String(), "snapshot manifest corrupt; rebuilt from snapshot files") {
+		t.Fatalf("expected recovery to be logged, got %q", logs.String())
+	}
+	onDisk, err := readManifest(path, Retention{}, time.Now())
+	if err != nil || len(onDisk.Games.Dates) != len(want) {
+		t.Fatalf("expected rebuilt manifest written back, got %+v err=%v", onDisk, err)
+	}
+}
+
+func TestReadManifestRejectsMissingVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	if _, err := readManifest(path, Retention{GamesDays: 5}, time.Now()); !errors.Is(err, errManifestCorrupt) {
+		t.Fatalf("expected corrupt manifest error, got %v", err)
+	}
+}
+
+func TestWriteManifestLeavesNoTempFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeManifest(dir, defaultManifest(Retention{GamesDays: 4}, time.Now()), time.Now()); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "manifest.json.tmp")); !os.IsNotExist(err) {
+		t.Fatalf("expected temp file renamed away, got %v", err)
+	}
+}
+
+// handEditedSnapshots writes games for three days and one teams catalog, then
+// removes 2024-01-14 and adds 2024-01-10 behind the writer's back. The added
+// file is dated after the others and its mtime is returned.
+func handEditedSnapshots(t *testing.T) (*Writer, time.Time) {
+	t.Helper()
+	w, _ := freezeWriter(t, time.Hour)
+	for _, d := range []string{"2024-01-13", "2024-01-14", "2024-01-15"} {
+		writeSimpleSnapshot(t, w, d)
+	}
+	if err := w.writeSnapshot(kindTeams, "2024-01-15", struct{}{}); err != nil {
+		t.Fatalf("write teams: %v", err)
+	}
+	if err := w.PinDate("2024-01-14"); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if err := w.PinDate("2024-01-15"); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	if err := os.Remove(w.snapshotPath(kindGames, "2024-01-14", 0)); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	added := w.snapshotPath(kindGames, "2024-01-10", 0)
+	if err := os.WriteFile(added, []byte(`{"date":"2024-01-10","games":[]}`), 0o644); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	later := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	if err := os.Chtimes(added, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	return w, later
+}
+
+func TestRebuildManifestReportsHandEdits(t *testing.T) {
+	w, addedAt := handEditedSnapshots(t)
+
+	report, err := w.RebuildManifest()
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	assertDatesEqual(t, report.Games.Dates, []string{"2024-01-10", "2024-01-13", "2024-01-15"})
+	assertDatesEqual(t, report.Games.Added, []string{"2024-01-10"})
+	assertDatesEqual(t, report.Games.Removed, []string{"2024-01-14"})
+	if !report.Changed() || len(report.Teams.Added) != 0 || len(report.Players.Dates) != 0 {
+		t.Fatalf("expected only games changed, got %+v", report)
+	}
+
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	assertDatesEqual(t, m.Games.Dates, report.Games.Dates)
+	assertDatesEqual(t, m.Games.Pinned, []string{"2024-01-15"})
+	if m.Players != nil {
+		t.Fatalf("expected no players meta without players snapshots, got %+v", m.Players)
+	}
+	if !m.Games.LastRefreshed.Equal(addedAt) {
+		t.Fatalf("expected LastRefreshed from the newest file, got %v", m.Games.LastRefreshed)
+	}
+
+	again, err := w.RebuildManifest()
+	if err != nil || again.Changed() {
+		t.Fatalf("expected a second rebuild to change nothing, got %+v %v", again, err)
+	}
+}
+
+func TestRebuildManifestWithoutManifestMatchesListDates(t *testing.T) {
+	w, _ := handEditedSnapshots(t)
+	if err := os.Remove(w.manifestPath()); err != nil {
+		t.Fatalf("remove manifest: %v", err)
+	}
+	if err := os.Remove(w.snapshotPath(kindGames, "2024-01-13", 0)); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	report, err := w.RebuildManifest()
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	for kind, got := range map[snapshotKind][]string{kindGames: m.Games.Dates, kindTeams: m.Teams.Dates} {
+		want, err := w.listDates(kind)
+		if err != nil {
+			t.Fatalf("list %s: %v", kind, err)
+		}
+		assertDatesEqual(t, got, want)
+	}
+	assertDatesEqual(t, report.Games.Added, []string{"2024-01-10", "2024-01-15"})
+	assertDatesEqual(t, report.Teams.Added, []string{"2024-01-15"})
+	if len(m.Games.Pinned) != 0 || m.Retention.GamesDays != 7 || m.Teams.LastRefreshed.IsZero() {
+		t.Fatalf("expected a fresh manifest with disk-derived times, got %+v", m)
+	}
+	if got := w.LastRefreshed(); len(got) != 2 {
+		t.Fatalf("expected games and teams refresh times, got %v", got)
+	}
+}
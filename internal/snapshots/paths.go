@@ -1,11 +1,75 @@
 package snapshots
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// Errors returned when a snapshot kind or date could not name a file under the
+// snapshot root. Callers validate input too; these stop a bad value from ever
+// reaching the filesystem.
+var (
+	ErrUnknownSnapshotKind = errors.New("unknown snapshot kind")
+	ErrInvalidSnapshotDate = errors.New("invalid snapshot date")
+	ErrInvalidSnapshotZone = errors.New("invalid snapshot zone")
 )
 
 // GameSnapshotPath builds the path to a games snapshot for a given date.
 func GameSnapshotPath(basePath, date string) string {
 	return filepath.Join(basePath, "games", fmt.Sprintf("%s.json", date))
 }
+
+// ZoneGameSnapshotPath builds the path to zone's games snapshot for date:
+// games/<zone slug>/<date>.json, beside the service-zone files.
+func ZoneGameSnapshotPath(basePath, zone, date string) (string, error) {
+	slug, err := ZoneSlug(zone)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(basePath, "games", slug, fmt.Sprintf("%s.json", date)), nil
+}
+
+// ZoneSlug names zone's snapshot directory: the IANA name lowercased with
+// "/" as "-", so "America/New_York" is "america-new_york". Zones time cannot
+// load, and "Local", are rejected.
+func ZoneSlug(zone string) (string, error) {
+	if zone == "" || zone == "Local" || strings.Contains(zone, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSnapshotZone, zone)
+	}
+	for _, r := range zone {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/_+-", r)) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidSnapshotZone, zone)
+		}
+	}
+	if _, err := time.LoadLocation(zone); err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidSnapshotZone, zone)
+	}
+	return strings.ToLower(strings.ReplaceAll(zone, "/", "-")), nil
+}
+
+// validateTarget rejects kinds outside the registered set and dates that are
+// not exactly YYYY-MM-DD, so neither can add separators or ".." to a path.
+func validateTarget(kind snapshotKind, date string) error {
+	switch kind {
+	case kindGames, kindTeams, kindPlayers:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownSnapshotKind, string(kind))
+	}
+	return validateDate(date)
+}
+
+func validateDate(date string) error {
+	if len(date) != len("2006-01-02") {
+		return fmt.Errorf("%w: %q", ErrInvalidSnapshotDate, date)
+	}
+	parsed, err := timeutil.ParseDate(date)
+	if err != nil || timeutil.FormatDate(parsed) != date {
+		return fmt.Errorf("%w: %q", ErrInvalidSnapshotDate, date)
+	}
+	return nil
+}
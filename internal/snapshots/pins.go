@@ -0,0 +1,140 @@
+package snapshots
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// ErrSnapshotNotFound is returned when an operation targets a date with no snapshot on disk.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// DiskUsage summarizes snapshot bytes on disk, with pinned dates counted separately.
+type DiskUsage struct {
+	Bytes       int64 `json:"bytes"`
+	PinnedBytes int64 `json:"pinnedBytes"`
+	Files       int   `json:"files"`
+	PinnedFiles int   `json:"pinnedFiles"`
+}
+
+// PinDate marks a games snapshot date as exempt from retention pruning.
+// Returns ErrSnapshotNotFound when no snapshot exists for the date.
+func (w *Writer) PinDate(date string) error {
+	if w == nil {
+		return errors.New("snapshot writer not configured")
+	}
+	if _, err := timeutil.ParseDate(date); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Checked under the lock so a concurrent prune cannot remove the file first.
+	if _, err := os.Stat(w.snapshotPath(kindGames, date, 0)); err != nil {
+		if os.IsNotExist(err) {
+			return ErrSnapshotNotFound
+		}
+		return err
+	}
+	m, _ := w.loadManifest()
+	if !containsDate(m.Games.Pinned, date) {
+		m.Games.Pinned = append(m.Games.Pinned, date)
+		sort.Strings(m.Games.Pinned)
+	}
+	return w.saveManifest(m)
+}
+
+// UnpinDate removes a date from the pinned list; the next prune applies normal retention to it.
+func (w *Writer) UnpinDate(date string) error {
+	if w == nil {
+		return errors.New("snapshot writer not configured")
+	}
+	if _, err := timeutil.ParseDate(date); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	m, _ := w.loadManifest()
+	kept := make([]string, 0, len(m.Games.Pinned))
+	for _, d := range m.Games.Pinned {
+		if d != date {
+			kept = append(kept, d)
+		}
+	}
+	m.Games.Pinned = kept
+	return w.saveManifest(m)
+}
+
+// Manifest returns the current manifest, or a default manifest when none has been written yet.
+// A corrupt manifest is rebuilt from the snapshot files first.
+func (w *Writer) Manifest() (Manifest, error) {
+	if w == nil {
+		return Manifest{}, errors.New("snapshot writer not configured")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.loadManifest()
+}
+
+// LastRefreshed reports when each snapshot kind was last written, for the
+// snapshot_age_seconds gauge. Kinds never written are left out.
+func (w *Writer) LastRefreshed() map[string]time.Time {
+	m, err := w.Manifest()
+	if err != nil {
+		return nil
+	}
+	var out map[string]time.Time
+	add := func(kind snapshotKind, at time.Time) {
+		if at.IsZero() {
+			return
+		}
+		if out == nil {
+			out = make(map[string]time.Time)
+		}
+		out[string(kind)] = at
+	}
+	add(kindGames, m.Games.LastRefreshed)
+	if m.Teams != nil {
+		add(kindTeams, m.Teams.LastRefreshed)
+	}
+	if m.Players != nil {
+		add(kindPlayers, m.Players.LastRefreshed)
+	}
+	return out
+}
+
+// Usage reports on-disk bytes of games snapshots, counting pinned dates separately.
+func (w *Writer) Usage() (DiskUsage, error) {
+	if w == nil {
+		return DiskUsage{}, errors.New("snapshot writer not configured")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	m, _ := w.loadManifest()
+	dates, err := w.listDates(kindGames)
+	if err != nil {
+		return DiskUsage{}, err
+	}
+	var usage DiskUsage
+	for _, d := range dates {
+		info, err := os.Stat(w.snapshotPath(kindGames, d, 0))
+		if err != nil {
+			continue
+		}
+		if containsDate(m.Games.Pinned, d) {
+			usage.PinnedBytes += info.Size()
+			usage.PinnedFiles++
+			continue
+		}
+		usage.Bytes += info.Size()
+		usage.Files++
+	}
+	return usage, nil
+}
+
+func (w *Writer) manifestPath() string {
+	return filepath.Join(w.basePath, "manifest.json")
+}
@@ -0,0 +1,129 @@
+package snapshots
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+func TestPinnedDateSurvivesPrune(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 1)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	w.SetClock(teststubs.NewFakeClock(now))
+	old := timeutil.FormatDate(now.AddDate(0, 0, -5))
+	older := timeutil.FormatDate(now.AddDate(0, 0, -6))
+	for _, d := range []string{old, older} {
+		if err := w.WriteGamesSnapshot(d, domaingames.NewTodayResponse(d, nil)); err != nil {
+			t.Fatalf("write %s failed: %v", d, err)
+		}
+	}
+	// Writes above prune each other; rewrite the file we want to pin directly.
+	path := filepath.Join(dir, "games", old+".json")
+	if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if err := w.PinDate(old); err != nil {
+		t.Fatalf("pin failed: %v", err)
+	}
+
+	today := timeutil.FormatDate(now)
+	if err := w.WriteGamesSnapshot(today, domaingames.NewTodayResponse(today, nil)); err != nil {
+		t.Fatalf("write today failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected pinned snapshot kept, got %v", err)
+	}
+
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest failed: %v", err)
+	}
+	if !containsDate(m.Games.Dates, old) || !containsDate(m.Games.Pinned, old) {
+		t.Fatalf("expected pinned date listed in manifest, got %+v", m.Games)
+	}
+
+	if err := w.UnpinDate(old); err != nil {
+		t.Fatalf("unpin failed: %v", err)
+	}
+	if err := w.WriteGamesSnapshot(today, domaingames.NewTodayResponse(today, nil)); err != nil {
+		t.Fatalf("rewrite today failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected unpinned snapshot pruned, got %v", err)
+	}
+}
+
+func TestPinDateRequiresExistingSnapshot(t *testing.T) {
+	w := NewWriter(t.TempDir(), 1)
+	if err := w.PinDate("2024-01-01"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Fatalf("expected ErrSnapshotNotFound, got %v", err)
+	}
+	if err := w.PinDate("bad-date"); err == nil {
+		t.Fatalf("expected invalid date error")
+	}
+	if err := w.UnpinDate("bad-date"); err == nil {
+		t.Fatalf("expected invalid date error on unpin")
+	}
+}
+
+func TestUsageCountsPinnedSeparately(t *testing.T) {
+	w := NewWriter(t.TempDir(), 30)
+	a := timeutil.FormatDate(time.Now())
+	b := timeutil.FormatDate(time.Now().AddDate(0, 0, -1))
+	for _, d := range []string{a, b} {
+		if err := w.WriteGamesSnapshot(d, domaingames.NewTodayResponse(d, nil)); err != nil {
+			t.Fatalf("write %s failed: %v", d, err)
+		}
+	}
+	if err := w.PinDate(b); err != nil {
+		t.Fatalf("pin failed: %v", err)
+	}
+	usage, err := w.Usage()
+	if err != nil {
+		t.Fatalf("usage failed: %v", err)
+	}
+	if usage.Files != 1 || usage.PinnedFiles != 1 || usage.Bytes == 0 || usage.PinnedBytes == 0 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+}
+
+func TestLastRefreshedTracksGamesWrites(t *testing.T) {
+	w := NewWriter(t.TempDir(), 30)
+	if got := w.LastRefreshed(); len(got) != 0 {
+		t.Fatalf("expected nothing refreshed before a write, got %v", got)
+	}
+	today := timeutil.FormatDate(time.Now())
+	if err := w.WriteGamesSnapshot(today, domaingames.NewTodayResponse(today, nil)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if at := w.LastRefreshed()["games"]; at.IsZero() || time.Since(at) > time.Minute {
+		t.Fatalf("expected games refreshed just now, got %v", at)
+	}
+	var nilWriter *Writer
+	if got := nilWriter.LastRefreshed(); got != nil {
+		t.Fatalf("expected nil for nil writer, got %v", got)
+	}
+}
+
+func TestPinsNilWriter(t *testing.T) {
+	var w *Writer
+	if err := w.PinDate("2024-01-01"); err == nil {
+		t.Fatalf("expected error for nil writer pin")
+	}
+	if err := w.UnpinDate("2024-01-01"); err == nil {
+		t.Fatalf("expected error for nil writer unpin")
+	}
+	if _, err := w.Manifest(); err == nil {
+		t.Fatalf("expected error for nil writer manifest")
+	}
+	if _, err := w.Usage(); err == nil {
+		t.Fatalf("expected error for nil writer usage")
+	}
+}
@@ -0,0 +1,123 @@
+package snapshots
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// ErrSnapshotWrite wraps the write error of a RefreshGames run whose fetch
+// succeeded, so callers can tell it from an upstream failure.
+var ErrSnapshotWrite = errors.New("snapshot write failed")
+
+// RefreshOptions adjusts a RefreshGames run.
+type RefreshOptions struct {
+	// Force overwrites a frozen date (see WriteGamesSnapshotForce).
+	Force bool
+	// Variant separates fetches that return different games for the same
+	// date, such as a timezone override; only runs with equal variants share.
+	Variant string
+	// Zone writes the games as that zone's snapshot (see
+	// WriteGamesSnapshotForZone) instead of the regular one.
+	Zone string
+}
+
+// RefreshGames runs fetch and writes its games as date's snapshot, unless it
+// returned none. Concurrent calls for the same date and options share one
+// run: followers block until the leader finishes and get its games and error,
+// so a burst of refreshes makes a single upstream call. Results are never
+// cached past the run. shared reports whether the result came from another
+// caller's run; the returned slice is shared and must not be modified.
+func (w *Writer) RefreshGames(date string, opts RefreshOptions, fetch func() ([]domaingames.Game, error)) (games []domaingames.Game, shared bool, err error) {
+	if w == nil {
+		return nil, false, fmt.Errorf("snapshot writer not configured")
+	}
+	return w.refreshes.do(refreshKey(date, opts), func() ([]domaingames.Game, error) {
+		games, err := fetch()
+		if err != nil || len(games) == 0 {
+			return games, err
+		}
+		snap := domaingames.NewTodayResponse(date, games)
+		write := func() error { return w.writeGames(date, snap, opts.Force) }
+		if opts.Zone != "" {
+			write = func() error { return w.WriteGamesSnapshotForZone(date, opts.Zone, snap) }
+		}
+		if err := write(); err != nil {
+			return games, fmt.Errorf("%w: %w", ErrSnapshotWrite, err)
+		}
+		return games, nil
+	})
+}
+
+// RefreshWaiters reports how many callers are blocked on date's in-progress
+// RefreshGames run with opts (primarily for testing).
+func (w *Writer) RefreshWaiters(date string, opts RefreshOptions) int {
+	if w == nil {
+		return 0
+	}
+	return w.refreshes.waiting(refreshKey(date, opts))
+}
+
+func refreshKey(date string, opts RefreshOptions) string {
+	key := cacheKey(kindGames, date)
+	if opts.Force {
+		key += "|force"
+	}
+	if opts.Variant != "" {
+		key += "|" + opts.Variant
+	}
+	if opts.Zone != "" {
+		key += "|zone=" + opts.Zone
+	}
+	return key
+}
+
+// refreshFlight is one in-progress RefreshGames run.
+type refreshFlight struct {
+	done    chan struct{}
+	games   []domaingames.Game
+	err     error
+	waiters int
+}
+
+// refreshGroup coalesces concurrent runs with the same key into one.
+type refreshGroup struct {
+	mu      sync.Mutex
+	flights map[string]*refreshFlight
+}
+
+func (g *refreshGroup) do(key string, fn func() ([]domaingames.Game, error)) ([]domaingames.Game, bool, error) {
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		f.waiters++
+		g.mu.Unlock()
+		<-f.done
+		return f.games, true, f.err
+	}
+	if g.flights == nil {
+		g.flights = make(map[string]*refreshFlight)
+	}
+	f := &refreshFlight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+	f.games, f.err = fn()
+	return f.games, false, f.err
+}
+
+func (g *refreshGroup) waiting(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.flights[key]; ok {
+		return f.waiters
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// waitForRefresh polls until date's run has started and n callers are
+// blocked on it.
+func waitForRefresh(t *testing.T, w *Writer, date string, opts RefreshOptions, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w.refreshes.mu.Lock()
+		_, running := w.refreshes.flights[refreshKey(date, opts)]
+		w.refreshes.mu.Unlock()
+		if running && w.RefreshWaiters(date, opts) >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %d refresh waiters, have %d", n, w.RefreshWaiters(date, opts))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRefreshGamesCoalescesConcurrentCalls(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+	release := make(chan struct{})
+	var fetches atomic.Int32
+	fetch := func() ([]domaingames.Game, error) {
+		fetches.Add(1)
+		<-release
+		return []domaingames.Game{{ID: "g1"}, {ID: "g2"}}, nil
+	}
+
+	const callers = 50
+	var (
+		wg     sync.WaitGroup
+		shared atomic.Int32
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			games, wasShared, err := w.RefreshGames(date, RefreshOptions{}, fetch)
+			if err != nil || len(games) != 2 {
+				t.Errorf("expected the leader's games, got %v %v", games, err)
+			}
+			if wasShared {
+				shared.Add(1)
+			}
+		}()
+	}
+	waitForRefresh(t, w, date, RefreshOptions{}, callers-1)
+	close(release)
+	wg.Wait()
+
+	if got := fetches.Load(); got != 1 {
+		t.Fatalf("expected exactly one fetch, got %d", got)
+	}
+	if got := shared.Load(); got != callers-1 {
+		t.Fatalf("expected %d shared results, got %d", callers-1, got)
+	}
+	requireSnapshotExists(t, w, date)
+	if w.RefreshWaiters(date, RefreshOptions{}) != 0 {
+		t.Fatal("expected the finished run forgotten")
+	}
+}
+
+func TestRefreshGamesKeepsVariantsApart(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = w.RefreshGames(date, RefreshOptions{}, func() ([]domaingames.Game, error) {
+			<-release
+			return nil, nil
+		})
+	}()
+	waitForRefresh(t, w, date, RefreshOptions{}, 0)
+
+	for _, opts := range []RefreshOptions{{Variant: "America/Los_Angeles"}, {Force: true}} {
+		ran := false
+		if _, shared, err := w.RefreshGames(date, opts, func() ([]domaingames.Game, error) {
+			ran = true
+			return nil, nil
+		}); err != nil || shared || !ran {
+			t.Fatalf("%+v: expected its own run, got shared=%v ran=%v err=%v", opts, shared, ran, err)
+		}
+	}
+	close(release)
+	<-done
+}
+
+func TestRefreshGamesErrors(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+
+	upstream := errors.New("upstream down")
+	if _, _, err := w.RefreshGames(date, RefreshOptions{}, func() ([]domaingames.Game, error) {
+		return nil, upstream
+	}); !errors.Is(err, upstream) || errors.Is(err, ErrSnapshotWrite) {
+		t.Fatalf("expected the fetch error unwrapped, got %v", err)
+	}
+
+	games, _, err := w.RefreshGames(date, RefreshOptions{}, func() ([]domaingames.Game, error) { return nil, nil })
+	if err != nil || len(games) != 0 {
+		t.Fatalf("expected an empty slate to succeed, got %v %v", games, err)
+	}
+	if _, statErr := os.Stat(w.snapshotPath(kindGames, date, 0)); !os.IsNotExist(statErr) {
+		t.Fatalf("expected no snapshot written for an empty slate, got %v", statErr)
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	broken := NewWriter(filepath.Join(file, "snapshots"), 7)
+	if _, _, err := broken.RefreshGames(date, RefreshOptions{}, func() ([]domaingames.Game, error) {
+		return []domaingames.Game{{ID: "g1"}}, nil
+	}); !errors.Is(err, ErrSnapshotWrite) {
+		t.Fatalf("expected a wrapped write error, got %v", err)
+	}
+
+	var nilWriter *Writer
+	if _, _, err := nilWriter.RefreshGames(date, RefreshOptions{}, nil); err == nil {
+		t.Fatal("expected an error from a nil writer")
+	}
+}
+
+func TestSyncerJoinsInFlightRefresh(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+	provider := &recordingProvider{}
+	syncer := NewSyncer(provider, w, SyncConfig{Enabled: true}, nil, nil)
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, _ = w.RefreshGames(date, RefreshOptions{}, func() ([]domaingames.Game, error) {
+			<-release
+			return []domaingames.Game{{ID: "admin"}}, nil
+		})
+	}()
+	// Start the sync once the leader is running so it joins rather than leads.
+	waitForRefresh(t, w, date, RefreshOptions{}, 0)
+	synced := make(chan error, 1)
+	go func() {
+		synced <- syncer.fetchAndWrite(context.Background(), date)
+	}()
+	waitForRefresh(t, w, date, RefreshOptions{}, 1)
+	close(release)
+	<-done
+
+	if err := <-synced; err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := provider.fetched(); len(got) != 0 {
+		t.Fatalf("expected the syncer to share the in-flight fetch, got provider calls %v", got)
+	}
+	requireSnapshotExists(t, w, date)
+}
@@ -0,0 +1,15 @@
+package snapshots
+
+// Season bounds the dates the syncer fetches, so the off-season does not
+// spend provider quota on empty slates. Start and End are inclusive
+// YYYY-MM-DD dates; an empty bound leaves that side open, and the zero
+// Season contains every date.
+type Season struct {
+	Start string
+	End   string
+}
+
+// Contains reports whether date falls within the season.
+func (s Season) Contains(date string) bool {
+	return (s.Start == "" || date >= s.Start) && (s.End == "" || date <= s.End)
+}
@@ -0,0 +1,92 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+func TestSeasonContains(t *testing.T) {
+	season := Season{Start: "2023-10-24", End: "2024-06-17"}
+	cases := map[string]bool{
+		"2023-10-23": false,
+		"2023-10-24": true,
+		"2024-01-15": true,
+		"2024-06-17": true,
+		"2024-06-18": false,
+	}
+	for date, want := range cases {
+		if got := season.Contains(date); got != want {
+			t.Fatalf("%s: expected contains=%v, got %v", date, want, got)
+		}
+	}
+	if !(Season{}).Contains("1999-01-01") || !(Season{End: "2024-06-17"}).Contains("1999-01-01") {
+		t.Fatalf("expected open bounds to contain every date on that side")
+	}
+}
+
+func TestBuildDatesSkipsOffSeasonUnlessForced(t *testing.T) {
+	// The day after the season ends: yesterday and earlier are in season.
+	now := time.Date(2024, 6, 18, 12, 0, 0, 0, time.UTC)
+	season := Season{Start: "2023-10-24", End: "2024-06-17"}
+	cases := []struct {
+		name  string
+		force bool
+		want  []string
+	}{
+		{"season", false, []string{"2024-06-17", "2024-06-16"}},
+		{"forced", true, []string{"2024-06-18", "2024-06-17", "2024-06-16", "2024-06-19"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := SyncConfig{Enabled: true, Days: 3, FutureDays: 1, Season: season, ForceOffseason: tc.force, Clock: teststubs.NewFakeClock(now)}
+			s := NewSyncer(nil, NewWriter(t.TempDir(), 10000), cfg, slog.New(slog.NewTextHandler(&buf, nil)), nil)
+			assertDatesEqual(t, s.buildDates(now), tc.want)
+			if logged := strings.Contains(buf.String(), "skipping off-season dates"); logged == tc.force {
+				t.Fatalf("expected skip log only without force, got %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestBackfillDropsOffSeasonPendingDates(t *testing.T) {
+	now := time.Date(2024, 6, 20, 12, 0, 0, 0, time.UTC)
+	writer := NewWriter(t.TempDir(), 10000)
+	clk := teststubs.NewFakeClock(now)
+	provider := &recordingProvider{}
+	cfg := SyncConfig{Enabled: true, Days: 5, Interval: time.Nanosecond, Season: Season{End: "2024-06-17"}, Clock: clk}
+	s := NewSyncer(provider, writer, cfg, nil, nil)
+	if err := s.savePending([]string{"2024-06-17", "2024-06-18"}); err != nil {
+		t.Fatalf("save pending: %v", err)
+	}
+
+	driveClock(t, clk, cfg.Interval, func() { s.backfill(context.Background(), now) })
+	assertDatesEqual(t, provider.fetched(), []string{"2024-06-17", "2024-06-16"})
+	if pending := s.pendingDates(); len(pending) != 0 {
+		t.Fatalf("expected the off-season pending date dropped, got %v", pending)
+	}
+}
+
+func TestEmptySlateLogLevelFollowsSeason(t *testing.T) {
+	var buf bytes.Buffer
+	// Today and future dates, which are left unwritten when empty.
+	clk := teststubs.NewFakeClock(time.Date(2024, 6, 17, 12, 0, 0, 0, time.UTC))
+	cfg := SyncConfig{Enabled: true, Season: Season{End: "2024-06-17"}, ForceOffseason: true, Clock: clk}
+	s := NewSyncer(emptyProvider{}, NewWriter(t.TempDir(), 7), cfg, slog.New(slog.NewTextHandler(&buf, nil)), nil)
+
+	_ = s.fetchAndWrite(context.Background(), "2024-06-17")
+	if out := buf.String(); !strings.Contains(out, "level=WARN") || strings.Contains(out, "off_season") {
+		t.Fatalf("expected an in-season empty slate to warn, got %s", out)
+	}
+	buf.Reset()
+	_ = s.fetchAndWrite(context.Background(), "2024-07-01")
+	if out := buf.String(); !strings.Contains(out, "level=INFO") || !strings.Contains(out, "off_season=true") {
+		t.Fatalf("expected an off-season empty slate at info, got %s", out)
+	}
+}
@@ -0,0 +1,172 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+const (
+	defaultSkewThreshold = 24 * time.Hour
+	defaultSkewInterval  = 10 * time.Minute
+)
+
+// SkewConfig controls the clock sanity check.
+type SkewConfig struct {
+	Threshold  time.Duration // allowed disagreement before the clock is suspect
+	FutureDays int           // snapshots legitimately written this far ahead of today
+	Interval   time.Duration // how often Run re-checks
+	Clock      clock.Clock   // defaults to the real clock
+}
+
+// SkewStatus is the latest clock sanity check result, exposed on /status.
+type SkewStatus struct {
+	Suspected      bool      `json:"clockSkewSuspected"`
+	Reason         string    `json:"reason,omitempty"`
+	NewestSnapshot string    `json:"newestSnapshot,omitempty"`
+	ProviderOffset string    `json:"providerOffset,omitempty"` // upstream Date minus local time
+	CheckedAt      time.Time `json:"checkedAt"`
+}
+
+// SkewChecker compares the local clock with the newest snapshot date and, when
+// available, the provider's clock. While skew is suspected it suppresses pruning on
+// the writer so a wrong clock cannot delete good snapshots.
+type SkewChecker struct {
+	writer         *Writer
+	providerOffset func() (time.Duration, bool)
+	cfg            SkewConfig
+	clock          clock.Clock
+	logger         *slog.Logger
+
+	mu     sync.RWMutex
+	status SkewStatus
+}
+
+// NewSkewChecker constructs a checker and installs it as writer's prune guard.
+// providerOffset may be nil when the provider does not report its clock.
+func NewSkewChecker(writer *Writer, providerOffset func() (time.Duration, bool), cfg SkewConfig, logger *slog.Logger) *SkewChecker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = defaultSkewThreshold
+	}
+	if cfg.FutureDays < 0 {
+		cfg.FutureDays = 0
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultSkewInterval
+	}
+	c := &SkewChecker{
+		writer:         writer,
+		providerOffset: providerOffset,
+		cfg:            cfg,
+		clock:          clock.OrReal(cfg.Clock),
+		logger:         logger,
+	}
+	writer.SetPruneGuard(c.Suspected)
+	return c
+}
+
+// Run re-checks every Interval until ctx is cancelled. Call Check once at startup
+// before any snapshot writes, then run this in a goroutine.
+func (c *SkewChecker) Run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	ticker := c.clock.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			c.Check()
+		}
+	}
+}
+
+// Check evaluates skew now, logging when the suspicion starts or clears.
+func (c *SkewChecker) Check() SkewStatus {
+	if c == nil {
+		return SkewStatus{}
+	}
+	now := c.clock.Now()
+	next := SkewStatus{CheckedAt: now}
+
+	if c.providerOffset != nil {
+		if offset, ok := c.providerOffset(); ok {
+			next.ProviderOffset = offset.Round(time.Second).String()
+			if offset > c.cfg.Threshold || -offset > c.cfg.Threshold {
+				next.Suspected = true
+				next.Reason = fmt.Sprintf("provider clock differs by %s", offset.Round(time.Second))
+			}
+		}
+	}
+
+	if newest, ok := c.newestSnapshot(); ok {
+		next.NewestSnapshot = timeutil.FormatDate(newest)
+		utc := now.UTC()
+		today := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+		// Snapshots dated further ahead than the sync window mean our clock is behind.
+		allowed := time.Duration(c.cfg.FutureDays)*24*time.Hour + c.cfg.Threshold
+		if ahead := newest.Sub(today); ahead > allowed && !next.Suspected {
+			next.Suspected = true
+			next.Reason = fmt.Sprintf("newest snapshot %s is %s ahead of today", next.NewestSnapshot, ahead)
+		}
+	}
+
+	c.mu.Lock()
+	prev := c.status
+	c.status = next
+	c.mu.Unlock()
+
+	switch {
+	case next.Suspected && !prev.Suspected:
+		logging.Error(c.logger, "clock skew suspected; pruning suspended", nil,
+			"reason", next.Reason,
+			"now", now.UTC().Format(time.RFC3339),
+		)
+	case !next.Suspected && prev.Suspected:
+		logging.Info(c.logger, "clock skew cleared; pruning resumed")
+	}
+	return next
+}
+
+// Suspected reports whether the last check found the clock untrustworthy.
+func (c *SkewChecker) Suspected() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status.Suspected
+}
+
+// Status returns the last check result.
+func (c *SkewChecker) Status() SkewStatus {
+	if c == nil {
+		return SkewStatus{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.status
+}
+
+func (c *SkewChecker) newestSnapshot() (time.Time, bool) {
+	m, err := c.writer.Manifest()
+	if err != nil {
+		return time.Time{}, false
+	}
+	var newest time.Time
+	for _, d := range m.Games.Dates {
+		parsed, err := timeutil.ParseDate(d)
+		if err == nil && parsed.After(newest) {
+			newest = parsed
+		}
+	}
+	return newest, !newest.IsZero()
+}
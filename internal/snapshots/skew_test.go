@@ -0,0 +1,108 @@
+package snapshots
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// writeFixedManifest records dates in the manifest and creates their snapshot files.
+func writeFixedManifest(t *testing.T, w *Writer, dates ...string) {
+	t.Helper()
+	for _, d := range dates {
+		path := filepath.Join(w.BasePath(), "games", d+".json")
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	m := defaultManifest(w.retention.manifest(), time.Now())
+	m.Games.Dates = dates
+	if err := writeManifest(w.BasePath(), m, time.Now()); err != nil {
+		t.Fatalf("write manifest failed: %v", err)
+	}
+}
+
+func TestSkewCheckerFlagsClockBehindSnapshots(t *testing.T) {
+	w := NewWriter(t.TempDir(), 7)
+	writeFixedManifest(t, w, "2024-03-01", "2024-03-10")
+	// Host clock stuck months before the newest snapshot.
+	clk := teststubs.NewFakeClock(time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	c := NewSkewChecker(w, nil, SkewConfig{FutureDays: 7, Clock: clk}, slog.New(slog.NewTextHandler(&buf, nil)))
+
+	status := c.Check()
+	if !status.Suspected || !c.Suspected() || status.NewestSnapshot != "2024-03-10" {
+		t.Fatalf("expected skew suspected, got %+v", status)
+	}
+	if !strings.Contains(status.Reason, "ahead of today") || !strings.Contains(buf.String(), "clock skew suspected") {
+		t.Fatalf("expected reason and error log, got %+v / %s", status, buf.String())
+	}
+
+	// Clock corrected: within the future window again.
+	clk.Set(time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC))
+	if status := c.Check(); status.Suspected {
+		t.Fatalf("expected skew cleared, got %+v", status)
+	}
+	if !strings.Contains(buf.String(), "clock skew cleared") {
+		t.Fatalf("expected clear log, got %s", buf.String())
+	}
+}
+
+func TestSkewCheckerUsesProviderOffset(t *testing.T) {
+	w := NewWriter(t.TempDir(), 7)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	offset, known := time.Duration(0), false
+	c := NewSkewChecker(w, func() (time.Duration, bool) { return offset, known }, SkewConfig{Threshold: time.Hour, Clock: clk}, nil)
+
+	if c.Check().Suspected {
+		t.Fatalf("expected no skew before provider has reported")
+	}
+	known, offset = true, -90*24*time.Hour
+	status := c.Check()
+	if !status.Suspected || status.ProviderOffset != "-2160h0m0s" {
+		t.Fatalf("expected provider skew suspected, got %+v", status)
+	}
+	offset = 30 * time.Second
+	if c.Check().Suspected {
+		t.Fatalf("expected small provider offset tolerated")
+	}
+}
+
+func TestSkewSuspicionSuppressesPruning(t *testing.T) {
+	w := NewWriter(t.TempDir(), 1)
+	old := timeutil.FormatDate(time.Now().UTC().AddDate(0, 0, -30))
+	writeFixedManifest(t, w, old)
+	// Provider says we're months off, so pruning must not trust time.Now.
+	c := NewSkewChecker(w, func() (time.Duration, bool) { return 120 * 24 * time.Hour, true }, SkewConfig{}, nil)
+	c.Check()
+
+	today := timeutil.FormatDate(time.Now().UTC())
+	if err := w.WriteGamesSnapshot(today, domaingames.TodayResponse{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(w.BasePath(), "games", old+".json")); err != nil {
+		t.Fatalf("expected old snapshot kept while skew suspected: %v", err)
+	}
+	m, _ := w.Manifest()
+	if len(m.Games.Dates) != 2 || m.Games.Dates[0] != old {
+		t.Fatalf("expected both dates in manifest, got %v", m.Games.Dates)
+	}
+}
+
+func TestSkewCheckerNilSafe(t *testing.T) {
+	var c *SkewChecker
+	if c.Suspected() || c.Check().Suspected || c.Status().Suspected {
+		t.Fatalf("expected nil checker to report no skew")
+	}
+}
@@ -2,34 +2,70 @@ package snapshots
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"slices"
+	"sync"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 // Syncer backfills and prunes game snapshots on a schedule.
 type Syncer struct {
-	provider  providers.GameProvider
-	writer    *Writer
-	cfg       SyncConfig
-	logger    *slog.Logger
-	now       func() time.Time
-	loc       *time.Location
-	newTicker func(time.Duration) *time.Ticker
+	provider providers.GameProvider
+	writer   *Writer
+	cfg      SyncConfig
+	logger   *slog.Logger
+	clock    clock.Clock
+	loc      *time.Location
+	// pause waits out upstream rate limits; tests replace it to observe delays.
+	pause func(ctx context.Context, d time.Duration)
+
+	mu        sync.RWMutex
+	nextRun   time.Time // zero while the daily schedule is not running
+	pending   []string  // failed backfill dates awaiting retry, mirrored to the state file
+	progress  syncProgress
+	holdUntil time.Time // workers wait until then before fetching, after a Retry-After
+
+	stateMu  sync.Mutex // orders pending updates with their state file saves
+	onDateMu sync.Mutex // serializes OnDate calls across workers
 }
 
 // SyncConfig controls snapshot sync behavior.
 type SyncConfig struct {
-	Enabled      bool
-	Days         int
-	FutureDays   int
-	Interval     time.Duration
-	DailyHourUTC int
+	Enabled        bool
+	Days           int
+	FutureDays     int
+	Interval       time.Duration
+	DailyHourUTC   int
+	DailyMinuteUTC int           // minute past DailyHourUTC for the daily run (0-59)
+	RetryPasses    int           // end-of-run retry passes over failed dates; 0 defaults to 3, negative disables
+	RetryBackoff   time.Duration // delay before the first retry pass, doubled per pass; defaults to Interval
+	// Concurrency is how many dates a run fetches at once; 0 or 1 syncs them
+	// one at a time. Each worker still waits Interval between its dates, and
+	// the provider's own rate limiter paces the HTTP calls they share.
+	Concurrency int
+	// Zones lists extra IANA zones whose slates each synced date is also
+	// fetched and written for (see Writer.WriteGamesSnapshotForZone); the
+	// regular snapshot, in the syncer's location, is always written.
+	Zones          []string
+	Clock          clock.Clock // defaults to the real clock
+	Season         Season      // dates outside it are skipped; zero syncs every date
+	ForceOffseason bool        // sync dates outside Season anyway, for backfills
+	// Recorder counts rate-limit pauses and normalized games; optional.
+	Recorder *metrics.Recorder
+	// OnDate, when set, is called after each date a run attempts with the
+	// error that left it failing, nil once it is written or skipped. Calls
+	// never overlap, even with Concurrency above 1.
+	OnDate func(date string, err error)
 }
 
 // NewSyncer constructs a snapshot syncer for games.
@@ -46,19 +82,35 @@ func NewSyncer(provider providers.GameProvider, writer *Writer, cfg SyncConfig,
 	if cfg.DailyHourUTC < 0 || cfg.DailyHourUTC > 23 {
 		cfg.DailyHourUTC = 2
 	}
+	if cfg.DailyMinuteUTC < 0 || cfg.DailyMinuteUTC > 59 {
+		cfg.DailyMinuteUTC = 0
+	}
+	if cfg.RetryPasses == 0 {
+		cfg.RetryPasses = 3
+	} else if cfg.RetryPasses < 0 {
+		cfg.RetryPasses = 0
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = cfg.Interval
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
 	if loc == nil {
 		loc = time.UTC
 	}
+	cfg.Zones = extraZones(cfg.Zones, loc)
 
-	return &Syncer{
-		provider:  provider,
-		writer:    writer,
-		cfg:       cfg,
-		logger:    logger,
-		now:       time.Now,
-		loc:       loc,
-		newTicker: time.NewTicker,
+	s := &Syncer{
+		provider: provider,
+		writer:   writer,
+		cfg:      cfg,
+		logger:   logger,
+		clock:    clock.OrReal(cfg.Clock),
+		loc:      loc,
 	}
+	s.pause = s.sleep
+	return s
 }
 
 // Run performs a backfill and schedules daily refreshes. Call in a goroutine.
@@ -74,92 +126,429 @@ func (s *Syncer) Run(ctx context.Context) {
 		"future_days", s.cfg.FutureDays,
 		"interval", s.cfg.Interval.String(),
 		"daily_hour_utc", s.cfg.DailyHourUTC,
+		"daily_minute_utc", s.cfg.DailyMinuteUTC,
+		"season_start", s.cfg.Season.Start,
+		"season_end", s.cfg.Season.End,
+		"force_offseason", s.cfg.ForceOffseason,
+		"concurrency", s.cfg.Concurrency,
 	)
 
-	now := s.now().In(s.loc)
+	now := s.clock.Now().In(s.loc)
 	s.backfill(ctx, now)
 	go s.daily(ctx)
 }
 
+// backfill syncs the window around now plus any dates left failing by an
+// earlier run, then retries this run's failures with doubling spacing.
 func (s *Syncer) backfill(ctx context.Context, now time.Time) {
 	dates := s.buildDates(now)
-	for i, date := range dates {
-		select {
-		case <-ctx.Done():
+	for _, date := range s.loadPending(now) {
+		if !containsDate(dates, date) {
+			dates = append(dates, date)
+		}
+	}
+	s.runDates(ctx, dates)
+}
+
+// Backfill syncs dates once each, paced and rate-limit aware like a scheduled
+// run, then retries failures up to RetryPasses times. It returns the dates
+// still failing, which also stay in the sync state file for the next run, and
+// ctx's error when it ended the run before every date was tried.
+func (s *Syncer) Backfill(ctx context.Context, dates []string) ([]string, error) {
+	if s == nil || s.writer == nil || s.provider == nil {
+		return nil, errors.New("snapshot syncer not configured")
+	}
+	s.runDates(ctx, dates)
+	pending := s.pendingDates()
+	var failed []string
+	for _, date := range dates {
+		if containsDate(pending, date) {
+			failed = append(failed, date)
+		}
+	}
+	return failed, ctx.Err()
+}
+
+// RangeDates lists the dates from from to to (inclusive, YYYY-MM-DD) that
+// Backfill would fetch: dates outside the season are left out unless
+// ForceOffseason is set, and frozen dates always are.
+func (s *Syncer) RangeDates(from, to string) ([]string, error) {
+	start, err := timeutil.ParseDate(from)
+	if err != nil {
+		return nil, fmt.Errorf("%w: from %q", ErrInvalidSnapshotDate, from)
+	}
+	end, err := timeutil.ParseDate(to)
+	if err != nil {
+		return nil, fmt.Errorf("%w: to %q", ErrInvalidSnapshotDate, to)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("backfill range ends (%s) before it starts (%s)", to, from)
+	}
+	var dates []string
+	for _, date := range timeutil.DateRange(start, time.UTC, 0, int(end.Sub(start).Hours()/24)) {
+		if s.inSeason(date) && !s.writer.IsFrozen(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// runDates syncs dates and then retries this run's failures with doubling
+// spacing.
+func (s *Syncer) runDates(ctx context.Context, dates []string) {
+	s.beginRun(len(dates))
+	defer s.endRun()
+	s.syncDates(ctx, dates)
+
+	for pass := 0; pass < s.cfg.RetryPasses; pass++ {
+		retry := s.pendingDates()
+		if len(retry) == 0 || ctx.Err() != nil {
+			return
+		}
+		s.sleep(ctx, s.cfg.RetryBackoff<<pass)
+		if ctx.Err() != nil {
 			return
-		default:
 		}
-		s.fetchAndWrite(ctx, date)
-		if i < len(dates)-1 {
-			s.sleep(ctx, s.cfg.Interval)
+		logging.Info(s.logger, "snapshot sync retrying failed dates", "pass", pass+1, "dates", retry)
+		s.setRetryPass(pass + 1)
+		s.syncDates(ctx, retry)
+	}
+	if failed := s.pendingDates(); len(failed) > 0 {
+		logging.Warn(s.logger, "snapshot sync dates still failing after retries", "dates", failed)
+	}
+}
+
+// syncDates attempts each date once, across Concurrency workers.
+func (s *Syncer) syncDates(ctx context.Context, dates []string) {
+	workers := min(s.cfg.Concurrency, len(dates))
+	if workers <= 1 {
+		for i, date := range dates {
+			if ctx.Err() != nil {
+				return
+			}
+			s.syncDate(ctx, date)
+			if i < len(dates)-1 {
+				s.sleep(ctx, s.cfg.Interval)
+			}
+		}
+		return
+	}
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first := true
+			for date := range queue {
+				if !first {
+					s.sleep(ctx, s.cfg.Interval)
+				}
+				first = false
+				if ctx.Err() != nil {
+					continue // skip dates handed out as the run was cancelled
+				}
+				s.syncDate(ctx, date)
+			}
+		}()
+	}
+feed:
+	for _, date := range dates {
+		select {
+		case <-ctx.Done():
+			break feed
+		case queue <- date:
 		}
 	}
+	close(queue)
+	wg.Wait()
+}
+
+// syncDate fetches and writes one date, retrying once after a Retry-After
+// pause, and records the outcome.
+func (s *Syncer) syncDate(ctx context.Context, date string) {
+	if s.writer.IsFrozen(date) {
+		logging.Info(s.logger, "snapshot sync skipped frozen date", "date", date)
+		s.setPending(date, false)
+		s.markAttempted(date)
+		return
+	}
+	s.waitForHold(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	s.setCurrent(date)
+	err := s.fetchAndWrite(ctx, date)
+	if s.waitOutRateLimit(ctx, date, err) {
+		err = s.fetchAndWrite(ctx, date)
+	}
+	s.setPending(date, err != nil)
+	s.markAttempted(date)
+	if s.cfg.OnDate != nil {
+		s.onDateMu.Lock()
+		s.cfg.OnDate(date, err)
+		s.onDateMu.Unlock()
+	}
+}
+
+// waitForHold blocks while another worker is waiting out a rate limit.
+func (s *Syncer) waitForHold(ctx context.Context) {
+	s.mu.RLock()
+	until := s.holdUntil
+	s.mu.RUnlock()
+	if wait := until.Sub(s.clock.Now()); wait > 0 {
+		s.pause(ctx, wait)
+	}
 }
 
+// dailyRecheck caps how long daily sleeps on one timer. Monotonic timers can
+// stall across a host suspend, so the wall clock is re-read at least this often.
+const dailyRecheck = time.Hour
+
+// daily runs a backfill once per day at DailyHourUTC:DailyMinuteUTC. It sleeps
+// until the exact due time rather than polling, and a wake that finds the due
+// time long past (suspend, clock jump) runs once and reschedules from now.
 func (s *Syncer) daily(ctx context.Context) {
-	ticker := s.newTicker(time.Hour)
-	defer ticker.Stop()
+	defer s.setNextRun(time.Time{})
+	next := nextDailyRun(s.clock.Now(), s.cfg.DailyHourUTC, s.cfg.DailyMinuteUTC)
+	s.setNextRun(next)
+	timer := s.clock.NewTimer(untilNext(s.clock.Now(), next))
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case now := <-ticker.C:
-			if now.UTC().Hour() == s.cfg.DailyHourUTC {
-				s.backfill(ctx, s.now().In(s.loc))
-			}
+		case <-timer.C():
+		}
+		now := s.clock.Now()
+		if now.Before(next) {
+			timer.Reset(untilNext(now, next))
+			continue
+		}
+		if late := now.Sub(next); late > dailyRecheck {
+			logging.Warn(s.logger, "snapshot daily sync running late", "due", next, "late", late.String())
+		}
+		next = nextDailyRun(now, s.cfg.DailyHourUTC, s.cfg.DailyMinuteUTC)
+		s.setNextRun(next)
+		s.backfill(ctx, now.In(s.loc))
+		if ctx.Err() != nil {
+			return
 		}
+		timer.Reset(untilNext(s.clock.Now(), next))
+	}
+}
+
+// nextDailyRun returns the first hourUTC:minuteUTC strictly after from.
+func nextDailyRun(from time.Time, hourUTC, minuteUTC int) time.Time {
+	utc := from.UTC()
+	at := time.Date(utc.Year(), utc.Month(), utc.Day(), hourUTC, minuteUTC, 0, 0, time.UTC)
+	if !at.After(utc) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+func untilNext(now, next time.Time) time.Duration {
+	return min(next.Sub(now), dailyRecheck)
+}
+
+func (s *Syncer) setNextRun(at time.Time) {
+	s.mu.Lock()
+	s.nextRun = at
+	s.mu.Unlock()
+}
+
+// NextRun reports when the next daily backfill is due; ok is false until the
+// initial backfill finishes and after the syncer stops.
+func (s *Syncer) NextRun() (at time.Time, ok bool) {
+	if s == nil {
+		return time.Time{}, false
 	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.nextRun, !s.nextRun.IsZero()
 }
 
+// buildDates lists the dates one run syncs, leaving out dates outside the
+// season unless ForceOffseason is set.
 func (s *Syncer) buildDates(now time.Time) []string {
 	var dates []string
-	today := timeutil.FormatDate(now)
-	yesterday := timeutil.FormatDate(now.AddDate(0, 0, -1))
+	skipped := 0
+	add := func(date string) {
+		if !s.inSeason(date) {
+			skipped++
+			return
+		}
+		dates = append(dates, date)
+	}
 
 	// Always refresh today and yesterday to capture live/final scores.
-	dates = append(dates, today, yesterday)
+	add(timeutil.DateIn(now, s.loc))
+	add(timeutil.DateOffset(now, s.loc, -1))
 
 	// Past window beyond yesterday: only fetch if missing (startup/outage).
+	// Off-days were written as empty snapshots, so they are not missing.
 	for i := 2; i < s.cfg.Days; i++ {
-		date := timeutil.FormatDate(now.AddDate(0, 0, -i))
+		date := timeutil.DateOffset(now, s.loc, -i)
 		if !s.hasSnapshot(date) {
-			dates = append(dates, date)
+			add(date)
 		}
 	}
 
 	// Future window: prefetch missing only.
-	for i := 1; i <= s.cfg.FutureDays; i++ {
-		date := timeutil.FormatDate(now.AddDate(0, 0, i))
+	for _, date := range timeutil.DateRange(now, s.loc, 1, s.cfg.FutureDays) {
 		if !s.hasSnapshot(date) {
-			dates = append(dates, date)
+			add(date)
 		}
 	}
 
+	if skipped > 0 {
+		logging.Info(s.logger, "snapshot sync skipping off-season dates",
+			"skipped", skipped,
+			"season_start", s.cfg.Season.Start,
+			"season_end", s.cfg.Season.End,
+		)
+	}
 	return dates
 }
 
-func (s *Syncer) fetchAndWrite(ctx context.Context, date string) {
-	start := time.Now()
-	games, err := s.provider.FetchGames(ctx, date, "")
-	if err != nil {
-		logging.Warn(s.logger, "snapshot sync fetch failed", "date", date, "err", err)
-		return
+// inSeason reports whether date should be synced under the season settings.
+func (s *Syncer) inSeason(date string) bool {
+	return s.cfg.ForceOffseason || s.cfg.Season.Contains(date)
+}
+
+// waitOutRateLimit pauses for max(RetryAfter, Interval) when err is a rate
+// limit carrying Retry-After, so the next request does not compound it; other
+// workers hold their next fetch as long. It reports whether date should be
+// fetched again.
+func (s *Syncer) waitOutRateLimit(ctx context.Context, date string, err error) bool {
+	rlErr, ok := providers.AsRateLimitError(err)
+	if !ok || rlErr.RetryAfter <= 0 {
+		return false
 	}
-	if len(games) == 0 {
-		logging.Warn(s.logger, "snapshot sync received no games", "date", date)
-		return
+	name := rlErr.Provider
+	if name == "" {
+		name = "snapshot_sync"
 	}
-	snap := domaingames.NewTodayResponse(date, games)
-	if err := s.writer.WriteGamesSnapshot(date, snap); err != nil {
-		logging.Warn(s.logger, "snapshot sync write failed", "date", date, "err", err)
-		return
+	s.cfg.Recorder.RecordRateLimit(name, rlErr.RetryAfter)
+	wait := max(rlErr.RetryAfter, s.cfg.Interval)
+	if s.cfg.Concurrency > 1 {
+		s.mu.Lock()
+		if until := s.clock.Now().Add(wait); until.After(s.holdUntil) {
+			s.holdUntil = until
+		}
+		s.mu.Unlock()
 	}
-	logging.Info(s.logger, "snapshot written",
+	logging.Warn(s.logger, "snapshot sync rate limited; pausing",
 		"date", date,
-		"count", len(games),
-		"duration_ms", time.Since(start).Milliseconds(),
+		"retry_after_ms", rlErr.RetryAfter.Milliseconds(),
+		"pause_ms", wait.Milliseconds(),
 	)
+	s.pause(ctx, wait)
+	return ctx.Err() == nil
+}
+
+// extraZones drops blanks, duplicates and loc itself, whose slate is the
+// regular snapshot.
+func extraZones(zones []string, loc *time.Location) []string {
+	var out []string
+	for _, zone := range zones {
+		if zone == "" || zone == loc.String() || slices.Contains(out, zone) {
+			continue
+		}
+		out = append(out, zone)
+	}
+	return out
+}
+
+// fetchAndWrite syncs one date, then its zone-scoped snapshots, and returns a
+// non-nil error when it should be retried; a retry refetches every zone.
+func (s *Syncer) fetchAndWrite(ctx context.Context, date string) error {
+	if err := s.fetchAndWriteZone(ctx, date, ""); err != nil {
+		return err
+	}
+	for _, zone := range s.cfg.Zones {
+		if err := s.fetchAndWriteZone(ctx, date, zone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchAndWriteZone syncs date's slate in zone, or the regular snapshot when
+// zone is empty. An empty slate is not a failure: off days legitimately have
+// no games. For a past date it is written as an empty snapshot, so the date
+// is no longer missing and later runs skip it; today and future dates are
+// left unwritten, since games may still be posted. The fetch and write go
+// through the writer's RefreshGames, so an admin refresh of the same date in
+// flight is joined rather than repeated.
+func (s *Syncer) fetchAndWriteZone(ctx context.Context, date, zone string) error {
+	start := s.clock.Now()
+	attrs := []any{"date", date}
+	if zone != "" {
+		attrs = append(attrs, "zone", zone)
+	}
+	games, shared, err := s.writer.RefreshGames(date, RefreshOptions{Zone: zone}, func() ([]domaingames.Game, error) {
+		games, err := s.provider.FetchGames(ctx, date, zone)
+		if err != nil {
+			return nil, err
+		}
+		games, summary := domaingames.Normalize(games)
+		s.cfg.Recorder.RecordNormalize(summary.Accepted, summary.Dropped, summary.Coerced)
+		if summary.Dropped > 0 || summary.Coerced > 0 || summary.BadStartTimes > 0 {
+			logging.Warn(s.logger, "snapshot sync normalized games", append(attrs,
+				"dropped", summary.Dropped,
+				"coerced", summary.Coerced,
+				"bad_start_times", summary.BadStartTimes,
+			)...)
+		}
+		return games, nil
+	})
+	switch {
+	case errors.Is(err, ErrSnapshotFrozen):
+		return nil
+	case errors.Is(err, ErrSnapshotWrite):
+		logging.Warn(s.logger, "snapshot sync write failed", append(attrs, "err", err)...)
+		return err
+	case err != nil:
+		logging.Warn(s.logger, "snapshot sync fetch failed", append(attrs, "err", err)...)
+		return err
+	}
+	if len(games) == 0 {
+		if date < timeutil.DateIn(s.clock.Now(), s.loc) {
+			return s.writeEmpty(date, zone, attrs)
+		}
+		// Outside the season (a forced backfill) an empty slate is expected.
+		if !s.cfg.Season.Contains(date) {
+			logging.Info(s.logger, "snapshot sync received no games", append(attrs, "off_season", true)...)
+		} else {
+			logging.Warn(s.logger, "snapshot sync received no games", attrs...)
+		}
+		return nil
+	}
+	logging.Info(s.logger, "snapshot written", append(attrs,
+		"count", len(games),
+		"shared", shared,
+		"duration_ms", s.clock.Now().Sub(start).Milliseconds(),
+	)...)
+	return nil
+}
+
+// writeEmpty records a past date the provider returned no games for as an
+// empty snapshot.
+func (s *Syncer) writeEmpty(date, zone string, attrs []any) error {
+	empty := domaingames.NewTodayResponse(date, []domaingames.Game{})
+	switch err := s.writer.WriteGamesSnapshotForZone(date, zone, empty); {
+	case errors.Is(err, ErrSnapshotFrozen):
+		return nil
+	case err != nil:
+		logging.Warn(s.logger, "snapshot sync write failed", append(attrs, "err", err)...)
+		return fmt.Errorf("%w: %w", ErrSnapshotWrite, err)
+	}
+	logging.Info(s.logger, "snapshot sync wrote empty snapshot", attrs...)
+	return nil
 }
 
 func (s *Syncer) hasSnapshot(date string) bool {
@@ -172,10 +561,10 @@ func (s *Syncer) hasSnapshot(date string) bool {
 }
 
 func (s *Syncer) sleep(ctx context.Context, d time.Duration) {
-	timer := time.NewTimer(d)
+	timer := s.clock.NewTimer(d)
 	defer timer.Stop()
 	select {
 	case <-ctx.Done():
-	case <-timer.C:
+	case <-timer.C():
 	}
 }
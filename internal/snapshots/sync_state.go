@@ -0,0 +1,124 @@
+package snapshots
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
+)
+
+// syncStateFile holds backfill dates that are still failing so a restart
+// resumes them instead of waiting for the next daily run.
+const syncStateFile = "sync_state.json"
+
+type syncState struct {
+	PendingDates []string  `json:"pendingDates"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func (s *Syncer) statePath() string {
+	return filepath.Join(s.writer.basePath, syncStateFile)
+}
+
+// loadPending reads the state file into s.pending, dropping dates that have
+// since left the backfill window or the season, and returns the dates to resume.
+func (s *Syncer) loadPending(now time.Time) []string {
+	data, err := os.ReadFile(s.statePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logging.Warn(s.logger, "snapshot sync state unreadable", "path", s.statePath(), "err", err)
+		}
+		return nil
+	}
+	var state syncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		logging.Warn(s.logger, "snapshot sync state corrupt; ignoring", "path", s.statePath(), "err", err)
+		return nil
+	}
+	oldest := timeutil.DateOffset(now, s.loc, -(s.cfg.Days - 1))
+	var pending []string
+	for _, d := range state.PendingDates {
+		if _, err := timeutil.ParseDate(d); err == nil && d >= oldest && s.inSeason(d) && !containsDate(pending, d) {
+			pending = append(pending, d)
+		}
+	}
+	sort.Strings(pending)
+
+	s.mu.Lock()
+	s.pending = pending
+	s.mu.Unlock()
+	if len(pending) > 0 {
+		logging.Info(s.logger, "snapshot sync resuming failed dates", "dates", pending)
+	}
+	return append([]string(nil), pending...)
+}
+
+// setPending records whether date is still failing and rewrites the state file
+// when the set changes, so an interrupted run never loses a failure.
+func (s *Syncer) setPending(date string, failed bool) {
+	// Held through the save so concurrent workers' saves land in order.
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.mu.Lock()
+	had := containsDate(s.pending, date)
+	if had == failed {
+		s.mu.Unlock()
+		return
+	}
+	if failed {
+		s.pending = append(s.pending, date)
+		sort.Strings(s.pending)
+	} else {
+		s.pending = removeDate(s.pending, date)
+	}
+	pending := append([]string(nil), s.pending...)
+	s.mu.Unlock()
+
+	if err := s.savePending(pending); err != nil {
+		logging.Warn(s.logger, "snapshot sync state write failed", "path", s.statePath(), "err", err)
+	}
+}
+
+func (s *Syncer) savePending(pending []string) error {
+	path := s.statePath()
+	if len(pending) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(syncState{PendingDates: pending, UpdatedAt: s.clock.Now().UTC()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := writeFileSynced(tmp, data); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *Syncer) pendingDates() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.pending...)
+}
+
+// PendingFailures reports how many backfill dates are still failing after the
+// last run's retries; they are retried on the next run or restart.
+func (s *Syncer) PendingFailures() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.pending)
+}
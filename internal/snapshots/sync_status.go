@@ -0,0 +1,115 @@
+package snapshots
+
+import (
+	"sort"
+	"time"
+)
+
+// SyncStatus is a point-in-time view of backfill progress.
+type SyncStatus struct {
+	Running     bool
+	Total       int    // dates in the current (or last) run
+	Completed   int    // dates attempted by its first pass
+	Failed      int    // dates still failing, including earlier runs'
+	CurrentDate string // date being fetched (the earliest one with Concurrency > 1), empty between fetches
+	// CurrentDates lists every date being fetched, sorted.
+	CurrentDates []string
+	RetryPass    int // 1-based while retrying failed dates
+
+	LastRunStartedAt  time.Time
+	LastRunFinishedAt time.Time // zero until a run completes
+	LastRunDuration   time.Duration
+	// GamesLastRefreshed comes from the manifest, so it includes poller and
+	// admin writes. Teams and players are not snapshotted yet.
+	GamesLastRefreshed time.Time
+}
+
+// syncProgress is the mutable half of SyncStatus, guarded by Syncer.mu.
+type syncProgress struct {
+	running    bool
+	total      int
+	completed  int
+	current    []string // dates being fetched, sorted
+	retryPass  int
+	startedAt  time.Time
+	finishedAt time.Time
+	duration   time.Duration
+}
+
+func (s *Syncer) beginRun(total int) {
+	s.mu.Lock()
+	s.progress = syncProgress{
+		running:   true,
+		total:     total,
+		startedAt: s.clock.Now().UTC(),
+	}
+	s.mu.Unlock()
+}
+
+func (s *Syncer) endRun() {
+	now := s.clock.Now().UTC()
+	s.mu.Lock()
+	s.progress.running = false
+	s.progress.current = nil
+	s.progress.retryPass = 0
+	s.progress.finishedAt = now
+	s.progress.duration = now.Sub(s.progress.startedAt)
+	s.mu.Unlock()
+}
+
+func (s *Syncer) setRetryPass(pass int) {
+	s.mu.Lock()
+	s.progress.retryPass = pass
+	s.mu.Unlock()
+}
+
+func (s *Syncer) setCurrent(date string) {
+	s.mu.Lock()
+	if !containsDate(s.progress.current, date) {
+		s.progress.current = append(s.progress.current, date)
+		sort.Strings(s.progress.current)
+	}
+	s.mu.Unlock()
+}
+
+// markAttempted drops date from the current dates and, on the first pass,
+// counts it done.
+func (s *Syncer) markAttempted(date string) {
+	s.mu.Lock()
+	s.progress.current = removeDate(s.progress.current, date)
+	if s.progress.retryPass == 0 {
+		s.progress.completed++
+	}
+	s.mu.Unlock()
+}
+
+// Status reports backfill progress and when the last run finished; safe to
+// call while a run is in flight.
+func (s *Syncer) Status() SyncStatus {
+	if s == nil {
+		return SyncStatus{}
+	}
+	s.mu.RLock()
+	p := s.progress
+	st := SyncStatus{
+		Running:           p.running,
+		Total:             p.total,
+		Completed:         p.completed,
+		Failed:            len(s.pending),
+		CurrentDates:      append([]string(nil), p.current...),
+		RetryPass:         p.retryPass,
+		LastRunStartedAt:  p.startedAt,
+		LastRunFinishedAt: p.finishedAt,
+		LastRunDuration:   p.duration,
+	}
+	s.mu.RUnlock()
+	if len(st.CurrentDates) > 0 {
+		st.CurrentDate = st.CurrentDates[0]
+	}
+	if s.writer != nil {
+		if m, err := s.writer.Manifest(); err == nil {
+			st.GamesLastRefreshed = m.Games.LastRefreshed
+		}
+	}
+	return st
+}
@@ -2,15 +2,23 @@ package snapshots
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"log/slog"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
 	"github.com/preston-bernstein/nba-data-service/internal/providers"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
 func simpleSnapshot(date string) domaingames.TodayResponse {
@@ -61,14 +69,46 @@ func assertDatesEqual(t *testing.T, got, want []string) {
 }
 
 type recordingProvider struct {
+	mu    sync.Mutex
 	dates []string
 }
 
 func (p *recordingProvider) FetchGames(ctx context.Context, date string, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.dates = append(p.dates, date)
 	return []domaingames.Game{{ID: date}}, nil
 }
 
+func (p *recordingProvider) fetched() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.dates...)
+}
+
+// driveClock runs fn while advancing clk by step whenever fn is blocked on a timer.
+func driveClock(t *testing.T, clk *teststubs.FakeClock, step time.Duration, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case <-done:
+			return
+		case <-deadline:
+			t.Fatal("timed out driving virtual clock")
+		default:
+		}
+		if clk.WaitForTimers(1, time.Millisecond) {
+			clk.Advance(step)
+		}
+	}
+}
+
 func TestSyncerBackfillsPastAndFuture(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -87,14 +127,15 @@ func TestSyncerBackfillsPastAndFuture(t *testing.T) {
 	writeSimpleSnapshot(t, writer, "2024-01-08")
 	writeSimpleSnapshot(t, writer, "2024-01-12")
 
+	clk := teststubs.NewFakeClock(now)
+	cfg.Clock = clk
 	syncer := NewSyncer(provider, writer, cfg, nil, nil)
-	syncer.now = func() time.Time { return now }
 
-	syncer.Run(ctx)
+	driveClock(t, clk, cfg.Interval, func() { syncer.Run(ctx) })
 	cancel()
 
 	expected := []string{"2024-01-10", "2024-01-09", "2024-01-11"}
-	assertDatesEqual(t, provider.dates, expected)
+	assertDatesEqual(t, provider.fetched(), expected)
 	for _, date := range expected {
 		requireSnapshotExists(t, writer, date)
 	}
@@ -134,10 +175,9 @@ func TestBuildDatesSkipsExistingSnapshots(t *testing.T) {
 	writeSimpleSnapshot(t, w, "2024-01-03") // past (beyond yesterday)
 	writeSimpleSnapshot(t, w, "2024-01-06") // future
 
-	s := NewSyncer(nil, w, SyncConfig{Enabled: true, Days: 5, FutureDays: 2}, nil, nil)
 	now := time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC)
-	s.now = func() time.Time { return now }
-	dates := s.buildDates(s.now())
+	s := NewSyncer(nil, w, SyncConfig{Enabled: true, Days: 5, FutureDays: 2, Clock: teststubs.NewFakeClock(now)}, nil, nil)
+	dates := s.buildDates(s.clock.Now())
 
 	want := map[string]bool{
 		"2024-01-05": true, // today
@@ -156,21 +196,138 @@ func TestBuildDatesSkipsExistingSnapshots(t *testing.T) {
 	}
 }
 
-func TestDailyUsesTicker(t *testing.T) {
+func TestBuildDatesAcrossDSTTransitions(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	cases := []struct {
+		name string
+		at   time.Time
+		loc  *time.Location
+		want []string
+	}{
+		// 00:30 local the day after spring forward; 24h earlier is still 03-09.
+		{"spring forward eastern", time.Date(2024, 3, 11, 4, 30, 0, 0, time.UTC), eastern, []string{"2024-03-11", "2024-03-10", "2024-03-09", "2024-03-12"}},
+		{"spring forward utc", time.Date(2024, 3, 11, 4, 30, 0, 0, time.UTC), nil, []string{"2024-03-11", "2024-03-10", "2024-03-09", "2024-03-12"}},
+		// 00:30 local the day after fall back; 23:30 in UTC terms is already 11-04.
+		{"fall back eastern", time.Date(2024, 11, 4, 5, 30, 0, 0, time.UTC), eastern, []string{"2024-11-04", "2024-11-03", "2024-11-02", "2024-11-05"}},
+		{"fall back eastern, evening", time.Date(2024, 11, 4, 4, 30, 0, 0, time.UTC), eastern, []string{"2024-11-03", "2024-11-02", "2024-11-01", "2024-11-04"}},
+		{"fall back utc", time.Date(2024, 11, 4, 4, 30, 0, 0, time.UTC), nil, []string{"2024-11-04", "2024-11-03", "2024-11-02", "2024-11-05"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clk := teststubs.NewFakeClock(tc.at)
+			s := NewSyncer(nil, NewWriter(t.TempDir(), 10000), SyncConfig{Enabled: true, Days: 3, FutureDays: 1, Clock: clk}, nil, tc.loc)
+			assertDatesEqual(t, s.buildDates(clk.Now()), tc.want)
+		})
+	}
+}
+
+func TestSyncerAndPruneAgreeOnLocalDate(t *testing.T) {
+	// 04:30 UTC is 23:30 the previous evening in New York: today is 01-14.
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 4, 30, 0, 0, time.UTC))
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	writer := NewWriter(t.TempDir(), 2)
+	writer.SetClock(clk)
+	writer.SetLocation(ny)
+	writeSimpleSnapshot(t, writer, "2024-01-12") // last day inside the window
+
+	provider := &recordingProvider{}
+	cfg := SyncConfig{Enabled: true, Days: 2, Interval: time.Nanosecond, Clock: clk}
+	s := NewSyncer(provider, writer, cfg, nil, ny)
+	driveClock(t, clk, cfg.Interval, func() { s.Run(context.Background()) })
+
+	assertDatesEqual(t, provider.fetched(), []string{"2024-01-14", "2024-01-13"})
+	for _, date := range []string{"2024-01-12", "2024-01-13", "2024-01-14"} {
+		requireSnapshotExists(t, writer, date)
+	}
+}
+
+func TestDailyRunsAtConfiguredTime(t *testing.T) {
 	writer := NewWriter(t.TempDir(), 5)
 	prov := &recordingProvider{}
+	// 00:30 UTC: the daily run is due at 02:00 exactly, not on an hourly tick.
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC))
 	cfg := SyncConfig{
 		Enabled:      true,
 		Days:         2,
 		FutureDays:   0,
-		Interval:     time.Nanosecond,
-		DailyHourUTC: time.Now().UTC().Hour(),
+		Interval:     time.Second,
+		DailyHourUTC: 2,
+		Clock:        clk,
 	}
 	s := NewSyncer(prov, writer, cfg, nil, nil)
-	s.now = func() time.Time { return time.Now().UTC() }
-	s.newTicker = func(d time.Duration) *time.Ticker {
-		return time.NewTicker(time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.daily(ctx)
+		close(done)
+	}()
+
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected daily loop to arm its timer")
+	}
+	clk.Advance(89 * time.Minute)
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected daily loop to re-arm after its recheck")
+	}
+	clk.Advance(time.Minute - time.Nanosecond)
+	if got := prov.fetched(); len(got) != 0 {
+		t.Fatalf("expected no sync before 02:00, got %v", got)
 	}
+	clk.Advance(time.Nanosecond)
+	// Backfill sleeps between dates; release the sleep once it is armed.
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected backfill to wait between dates")
+	}
+	clk.Advance(cfg.Interval)
+
+	teststubs.WaitFor(t, time.Second, func() bool { return len(prov.fetched()) >= 2 }, "expected daily backfill to fetch both dates")
+	cancel()
+	<-done
+
+	assertDatesEqual(t, prov.fetched(), []string{"2024-01-10", "2024-01-09"})
+}
+
+// clockedProvider records the virtual time of each fetch for today's date.
+type clockedProvider struct {
+	mu    sync.Mutex
+	clk   *teststubs.FakeClock
+	fires []time.Time
+}
+
+func (p *clockedProvider) FetchGames(ctx context.Context, date string, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := p.clk.Now()
+	if date == now.Format("2006-01-02") {
+		p.fires = append(p.fires, now)
+	}
+	return []domaingames.Game{{ID: date}}, nil
+}
+
+func (p *clockedProvider) fired() []time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]time.Time(nil), p.fires...)
+}
+
+func TestDailyFiresOncePerDayAtHourAndMinute(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	prov := &clockedProvider{clk: clk}
+	s := NewSyncer(prov, NewWriter(t.TempDir(), 5), SyncConfig{
+		Enabled:        true,
+		Days:           2,
+		Interval:       time.Second,
+		DailyHourUTC:   2,
+		DailyMinuteUTC: 15,
+		Clock:          clk,
+	}, nil, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
@@ -178,19 +335,125 @@ func TestDailyUsesTicker(t *testing.T) {
 		s.daily(ctx)
 		close(done)
 	}()
+	// Step three simulated days a minute at a time, waiting for the loop (or a
+	// backfill sleep) to arm before each step.
+	for i := 0; i < 3*24*60; i++ {
+		if !clk.WaitForTimers(1, time.Second) {
+			t.Fatalf("expected a pending timer at %s", clk.Now())
+		}
+		clk.Advance(time.Minute)
+	}
+	cancel()
+	<-done
 
-	time.Sleep(10 * time.Millisecond)
+	fires := prov.fired()
+	if len(fires) != 3 {
+		t.Fatalf("expected exactly one run per day, got %v", fires)
+	}
+	for i, at := range fires {
+		want := time.Date(2024, 1, 10+i, 2, 15, 0, 0, time.UTC)
+		if at.Before(want) || !at.Before(want.Add(time.Minute)) {
+			t.Fatalf("run %d: expected at %s, got %s", i, want, at)
+		}
+	}
+}
+
+func TestDailyRunsOnceAfterSuspendSizedJump(t *testing.T) {
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC))
+	prov := &clockedProvider{clk: clk}
+	s := NewSyncer(prov, NewWriter(t.TempDir(), 5), SyncConfig{
+		Enabled:      true,
+		Days:         2,
+		Interval:     time.Second,
+		DailyHourUTC: 2,
+		Clock:        clk,
+	}, testLogger(), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.daily(ctx)
+		close(done)
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected daily loop to arm its timer")
+	}
+	// Three days pass in one jump: the missed runs collapse into one.
+	clk.Advance(72 * time.Hour)
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected backfill to wait between dates")
+	}
+	clk.Advance(time.Second)
+	teststubs.WaitFor(t, time.Second, func() bool {
+		at, _ := s.NextRun()
+		return at.Equal(time.Date(2024, 1, 13, 2, 0, 0, 0, time.UTC))
+	}, "expected next run rescheduled from the wake time")
+	teststubs.WaitFor(t, time.Second, func() bool { return clk.ActiveTimers() == 1 }, "expected daily loop to re-arm after the catch-up run")
 	cancel()
 	<-done
 
-	if len(prov.dates) == 0 {
-		t.Fatalf("expected daily loop to trigger sync at least once, got dates=%v", prov.dates)
+	if fires := prov.fired(); len(fires) != 1 {
+		t.Fatalf("expected a single catch-up run, got %v", fires)
+	}
+}
+
+func TestNextDailyRun(t *testing.T) {
+	from := time.Date(2024, 1, 10, 2, 15, 0, 0, time.UTC)
+	if got := nextDailyRun(from, 2, 15); !got.Equal(from.AddDate(0, 0, 1)) {
+		t.Fatalf("expected the due time itself to roll to tomorrow, got %s", got)
+	}
+	if got := nextDailyRun(from.Add(-time.Second), 2, 15); !got.Equal(from) {
+		t.Fatalf("expected today's run, got %s", got)
+	}
+	ny := time.FixedZone("EST", -5*3600)
+	if got := nextDailyRun(time.Date(2024, 1, 9, 22, 0, 0, 0, ny), 2, 0); !got.Equal(time.Date(2024, 1, 11, 2, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected hour interpreted in UTC, got %s", got)
+	}
+}
+
+func TestSyncerNextRunFollowsDailySchedule(t *testing.T) {
+	writer := NewWriter(t.TempDir(), 5)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 10, 0, 30, 0, 0, time.UTC))
+	s := NewSyncer(emptyProvider{}, writer, SyncConfig{Enabled: true, DailyHourUTC: 2, Clock: clk}, nil, nil)
+	if _, ok := s.NextRun(); ok {
+		t.Fatalf("expected no next sync before the daily loop starts")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.daily(ctx)
+		close(done)
+	}()
+	if !clk.WaitForTimers(1, time.Second) {
+		t.Fatal("expected daily loop to arm its timer")
+	}
+	want := time.Date(2024, 1, 10, 2, 0, 0, 0, time.UTC)
+	if at, ok := s.NextRun(); !ok || !at.Equal(want) {
+		t.Fatalf("expected next sync %s, got %s ok=%v", want, at, ok)
+	}
+
+	// After the daily run, the next one is a day later.
+	clk.Advance(2 * time.Hour)
+	want = want.AddDate(0, 0, 1)
+	teststubs.WaitFor(t, time.Second, func() bool {
+		at, _ := s.NextRun()
+		return at.Equal(want)
+	}, "expected next sync %s after daily run", want)
+
+	cancel()
+	<-done
+	if _, ok := s.NextRun(); ok {
+		t.Fatalf("expected next sync cleared after stop")
+	}
+	var nilSyncer *Syncer
+	if _, ok := nilSyncer.NextRun(); ok {
+		t.Fatalf("expected nil syncer to report no next run")
 	}
 }
 
 func TestDailyReturnsOnCancel(t *testing.T) {
-	s := NewSyncer(nil, NewWriter(t.TempDir(), 1), SyncConfig{Enabled: true}, nil, nil)
-	s.newTicker = func(d time.Duration) *time.Ticker { return time.NewTicker(time.Hour) }
+	s := NewSyncer(nil, NewWriter(t.TempDir(), 1), SyncConfig{Enabled: true, Clock: teststubs.NewFakeClock(time.Now())}, nil, nil)
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 	s.daily(ctx) // should exit immediately without blocking
@@ -253,7 +516,7 @@ func TestFetchAndWriteHandlesErrorsAndSuccess(t *testing.T) {
 	if err := os.WriteFile(filePath, []byte("x"), 0o644); err != nil {
 		t.Fatalf("failed to create placeholder file: %v", err)
 	}
-	s = NewSyncer(goodProvider{games: []domaingames.Game{{ID: "g1"}}}, &Writer{basePath: filePath, retentionDays: 1}, SyncConfig{Enabled: true}, logger, nil)
+	s = NewSyncer(goodProvider{games: []domaingames.Game{{ID: "g1"}}}, NewWriterWithRetention(filePath, RetentionConfig{GamesDays: 1}), SyncConfig{Enabled: true}, logger, nil)
 	s.fetchAndWrite(context.Background(), "2024-01-03")
 
 	// Successful write path (large retention to avoid pruning).
@@ -285,3 +548,436 @@ func TestBackfillRespectsContextCancel(t *testing.T) {
 func testLogger() *slog.Logger {
 	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
 }
+
+func TestSyncerSkipsFrozenDates(t *testing.T) {
+	now := time.Now().UTC()
+	writer := NewWriter(t.TempDir(), 7)
+	writer.SetFreezeGrace(0)
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	writeSnapshot(t, writer, yesterday, domaingames.TodayResponse{Games: []domaingames.Game{{ID: "g1", StatusKind: domaingames.StatusFinal}}})
+
+	provider := &recordingProvider{}
+	s := NewSyncer(provider, writer, SyncConfig{Enabled: true, Days: 2, Interval: time.Nanosecond}, nil, nil)
+	s.backfill(context.Background(), now)
+
+	assertDatesEqual(t, provider.fetched(), []string{now.Format("2006-01-02")})
+}
+
+// flakyProvider fails each date in failFirst on its first fetch only.
+type flakyProvider struct {
+	mu        sync.Mutex
+	failFirst map[string]bool
+	calls     map[string]int
+}
+
+func (p *flakyProvider) FetchGames(ctx context.Context, date string, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.calls == nil {
+		p.calls = make(map[string]int)
+	}
+	p.calls[date]++
+	if p.failFirst[date] && p.calls[date] == 1 {
+		return nil, providers.ErrProviderUnavailable
+	}
+	return []domaingames.Game{{ID: date}}, nil
+}
+
+func TestSyncerRetriesFailedDatesAndClearsState(t *testing.T) {
+	now := time.Now().UTC()
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	writer := NewWriter(t.TempDir(), 7)
+	prov := &flakyProvider{failFirst: map[string]bool{yesterday: true}}
+	s := NewSyncer(prov, writer, SyncConfig{Enabled: true, Days: 2, Interval: time.Nanosecond}, testLogger(), nil)
+
+	s.backfill(context.Background(), now)
+
+	if prov.calls[yesterday] != 2 {
+		t.Fatalf("expected failed date retried once, got %d calls", prov.calls[yesterday])
+	}
+	requireSnapshotExists(t, writer, yesterday)
+	if s.PendingFailures() != 0 {
+		t.Fatalf("expected no pending failures, got %d", s.PendingFailures())
+	}
+	if _, err := os.Stat(s.statePath()); !os.IsNotExist(err) {
+		t.Fatalf("expected state file removed once retries succeed, got %v", err)
+	}
+}
+
+func TestSyncerPersistsAndResumesFailedDates(t *testing.T) {
+	now := time.Now().UTC()
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	writer := NewWriter(t.TempDir(), 7)
+	failing := NewSyncer(errProvider{err: providers.ErrProviderUnavailable}, writer,
+		SyncConfig{Enabled: true, Days: 3, Interval: time.Nanosecond, RetryPasses: -1}, testLogger(), nil)
+
+	failing.backfill(context.Background(), now)
+	if failing.PendingFailures() != 3 {
+		t.Fatalf("expected 3 pending failures, got %d", failing.PendingFailures())
+	}
+	data, err := os.ReadFile(failing.statePath())
+	if err != nil {
+		t.Fatalf("expected state file: %v", err)
+	}
+	var state syncState
+	if err := json.Unmarshal(data, &state); err != nil || len(state.PendingDates) != 3 {
+		t.Fatalf("expected 3 persisted dates, got %s (%v)", data, err)
+	}
+
+	// A restarted syncer picks the failures up even once they would otherwise
+	// be skipped (the 2-day-old date is only fetched when missing).
+	writeSimpleSnapshot(t, writer, now.AddDate(0, 0, -2).Format("2006-01-02"))
+	prov := &recordingProvider{}
+	resumed := NewSyncer(prov, writer, SyncConfig{Enabled: true, Days: 3, Interval: time.Nanosecond}, testLogger(), nil)
+	resumed.backfill(context.Background(), now)
+
+	assertDatesEqual(t, prov.fetched(), []string{now.Format("2006-01-02"), yesterday, now.AddDate(0, 0, -2).Format("2006-01-02")})
+	if resumed.PendingFailures() != 0 {
+		t.Fatalf("expected resumed failures cleared, got %d", resumed.PendingFailures())
+	}
+	if _, err := os.Stat(resumed.statePath()); !os.IsNotExist(err) {
+		t.Fatalf("expected state file cleared, got %v", err)
+	}
+}
+
+// gatedProvider blocks each fetch until release is signalled, failing dates in fail.
+type gatedProvider struct {
+	started chan string
+	release chan struct{}
+	fail    map[string]bool
+}
+
+func (p *gatedProvider) FetchGames(ctx context.Context, date string, _ string) ([]domaingames.Game, error) {
+	p.started <- date
+	<-p.release
+	if p.fail[date] {
+		return nil, providers.ErrProviderUnavailable
+	}
+	return []domaingames.Game{{ID: date}}, nil
+}
+
+func TestSyncerStatusTracksBackfillProgress(t *testing.T) {
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	writer := NewWriter(t.TempDir(), 7)
+	prov := &gatedProvider{
+		started: make(chan string),
+		release: make(chan struct{}),
+		fail:    map[string]bool{yesterday: true},
+	}
+	s := NewSyncer(prov, writer, SyncConfig{Enabled: true, Days: 2, Interval: time.Nanosecond, RetryPasses: -1}, testLogger(), nil)
+
+	if st := s.Status(); st.Running || !st.LastRunFinishedAt.IsZero() {
+		t.Fatalf("expected idle status before any run, got %+v", st)
+	}
+	done := make(chan struct{})
+	go func() {
+		s.backfill(context.Background(), now)
+		close(done)
+	}()
+
+	if got := <-prov.started; got != today {
+		t.Fatalf("expected today fetched first, got %s", got)
+	}
+	st := s.Status()
+	if !st.Running || st.Total != 2 || st.Completed != 0 || st.CurrentDate != today {
+		t.Fatalf("unexpected status during first fetch: %+v", st)
+	}
+	prov.release <- struct{}{}
+
+	<-prov.started
+	st = s.Status()
+	if st.Completed != 1 || st.CurrentDate != yesterday || st.Failed != 0 {
+		t.Fatalf("unexpected status during second fetch: %+v", st)
+	}
+	if st.GamesLastRefreshed.IsZero() {
+		t.Fatalf("expected games refresh time from manifest after first write")
+	}
+	prov.release <- struct{}{}
+	<-done
+
+	st = s.Status()
+	if st.Running || st.Total != 2 || st.Completed != 2 || st.Failed != 1 || st.CurrentDate != "" {
+		t.Fatalf("unexpected final status: %+v", st)
+	}
+	if st.LastRunFinishedAt.IsZero() || st.LastRunDuration < 0 || st.LastRunFinishedAt.Before(st.LastRunStartedAt) {
+		t.Fatalf("expected last run timing recorded, got %+v", st)
+	}
+}
+
+func TestSyncerStatusNilSafe(t *testing.T) {
+	var s *Syncer
+	if st := s.Status(); !reflect.DeepEqual(st, SyncStatus{}) {
+		t.Fatalf("expected zero status for nil syncer, got %+v", st)
+	}
+}
+
+// rateLimitedProvider rate limits each date with retryAfter on its first fetch.
+type rateLimitedProvider struct {
+	mu         sync.Mutex
+	retryAfter time.Duration
+	calls      map[string]int
+}
+
+func (p *rateLimitedProvider) FetchGames(ctx context.Context, date string, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.calls == nil {
+		p.calls = make(map[string]int)
+	}
+	p.calls[date]++
+	if p.calls[date] == 1 {
+		return nil, &providers.RateLimitError{Provider: "balldontlie", StatusCode: 429, RetryAfter: p.retryAfter}
+	}
+	return []domaingames.Game{{ID: date}}, nil
+}
+
+func TestSyncerPausesForRetryAfterAndRetriesDate(t *testing.T) {
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	writer := NewWriter(t.TempDir(), 7)
+	rec := metrics.NewRecorder()
+	prov := &rateLimitedProvider{retryAfter: 30 * time.Second}
+	s := NewSyncer(prov, writer, SyncConfig{Enabled: true, Days: 1, Interval: time.Nanosecond, RetryPasses: -1, Recorder: rec}, testLogger(), nil)
+	var pauses []time.Duration
+	s.pause = func(_ context.Context, d time.Duration) { pauses = append(pauses, d) }
+
+	s.backfill(context.Background(), now)
+
+	if len(pauses) != 2 || pauses[0] != 30*time.Second || pauses[1] != 30*time.Second {
+		t.Fatalf("expected a 30s pause per rate-limited date, got %v", pauses)
+	}
+	for _, date := range []string{today, yesterday} {
+		if prov.calls[date] != 2 {
+			t.Fatalf("expected %s fetched again after the pause, got %d calls", date, prov.calls[date])
+		}
+		requireSnapshotExists(t, writer, date)
+	}
+	if s.PendingFailures() != 0 {
+		t.Fatalf("expected no pending failures, got %d", s.PendingFailures())
+	}
+	if rec.RateLimitHits("balldontlie") != 2 || rec.LastRetryAfter("balldontlie") != 30*time.Second {
+		t.Fatalf("expected rate limit recorded, got %+v", rec.Snapshot("balldontlie"))
+	}
+}
+
+func TestSyncerRateLimitPauseIsAtLeastInterval(t *testing.T) {
+	s := NewSyncer(nil, nil, SyncConfig{Interval: time.Minute}, testLogger(), nil)
+	var paused time.Duration
+	s.pause = func(_ context.Context, d time.Duration) { paused = d }
+
+	if !s.waitOutRateLimit(context.Background(), "2024-01-01", &providers.RateLimitError{RetryAfter: time.Second}) {
+		t.Fatalf("expected retry after pause")
+	}
+	if paused != time.Minute {
+		t.Fatalf("expected pause floored at interval, got %s", paused)
+	}
+	if s.waitOutRateLimit(context.Background(), "2024-01-01", &providers.RateLimitError{}) {
+		t.Fatalf("expected no retry without Retry-After")
+	}
+	if s.waitOutRateLimit(context.Background(), "2024-01-01", providers.ErrProviderUnavailable) {
+		t.Fatalf("expected no retry for non rate limit errors")
+	}
+}
+
+func TestRangeDatesSkipsFrozenAndOffSeasonDates(t *testing.T) {
+	writer, _ := freezeWriter(t, 0)
+	writeSnapshot(t, writer, "2024-01-14", slate(domaingames.StatusFinal))
+	s := NewSyncer(&recordingProvider{}, writer, SyncConfig{Season: Season{End: "2024-01-16"}}, nil, nil)
+
+	dates, err := s.RangeDates("2024-01-13", "2024-01-17")
+	if err != nil {
+		t.Fatalf("range: %v", err)
+	}
+	assertDatesEqual(t, dates, []string{"2024-01-13", "2024-01-15", "2024-01-16"})
+	if _, err := s.RangeDates("2024-01-13", "2024-1-17"); !errors.Is(err, ErrInvalidSnapshotDate) {
+		t.Fatalf("expected a malformed bound rejected, got %v", err)
+	}
+}
+
+// interleavingProvider records fetch starts and finishes in order and holds
+// every fetch until release closes or its ctx ends.
+type interleavingProvider struct {
+	mu       sync.Mutex
+	events   []string
+	inFlight int
+	peak     int
+	release  chan struct{}
+}
+
+func (p *interleavingProvider) FetchGames(ctx context.Context, date, _ string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	p.events = append(p.events, "start "+date)
+	p.inFlight++
+	p.peak = max(p.peak, p.inFlight)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.events = append(p.events, "done "+date)
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+	select {
+	case <-p.release:
+		return []domaingames.Game{{ID: date}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *interleavingProvider) started() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, e := range p.events {
+		if strings.HasPrefix(e, "start ") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestSyncerBackfillRunsDatesConcurrently(t *testing.T) {
+	writer, _ := freezeWriter(t, time.Hour)
+	provider := &interleavingProvider{release: make(chan struct{})}
+	var inCallback, overlapped bool
+	var callbackMu sync.Mutex
+	s := NewSyncer(provider, writer, SyncConfig{
+		Enabled:     true,
+		Interval:    time.Nanosecond,
+		Concurrency: 3,
+		OnDate: func(string, error) {
+			callbackMu.Lock()
+			overlapped = overlapped || inCallback
+			inCallback = true
+			callbackMu.Unlock()
+			time.Sleep(time.Millisecond)
+			callbackMu.Lock()
+			inCallback = false
+			callbackMu.Unlock()
+		},
+	}, testLogger(), nil)
+	dates := []string{"2024-01-10", "2024-01-11", "2024-01-12", "2024-01-13", "2024-01-14", "2024-01-15"}
+
+	result := make(chan []string, 1)
+	go func() {
+		failed, _ := s.Backfill(context.Background(), dates)
+		result <- failed
+	}()
+	teststubs.WaitFor(t, 2*time.Second, func() bool { return provider.started() == 3 }, "expected three fetches in flight")
+	st := s.Status()
+	if len(st.CurrentDates) != 3 || st.CurrentDate != st.CurrentDates[0] || st.Completed != 0 {
+		t.Fatalf("expected three dates in flight and none completed, got %+v", st)
+	}
+	close(provider.release)
+
+	if failed := <-result; len(failed) != 0 {
+		t.Fatalf("expected every date written, got failures %v", failed)
+	}
+	for _, date := range dates {
+		requireSnapshotExists(t, writer, date)
+	}
+	if provider.peak != 3 {
+		t.Fatalf("expected at most and at least three concurrent fetches, peak %d (events %v)", provider.peak, provider.events)
+	}
+	// The first three fetches all start before any finishes.
+	for i, e := range provider.events[:3] {
+		if !strings.HasPrefix(e, "start ") {
+			t.Fatalf("expected three starts before a finish, event %d is %q in %v", i, e, provider.events)
+		}
+	}
+	if overlapped {
+		t.Fatal("expected OnDate calls never to overlap")
+	}
+	if st := s.Status(); st.Running || st.Completed != len(dates) || len(st.CurrentDates) != 0 {
+		t.Fatalf("expected a finished run, got %+v", st)
+	}
+}
+
+func TestSyncerConcurrentBackfillStopsOnCancel(t *testing.T) {
+	writer, _ := freezeWriter(t, time.Hour)
+	provider := &interleavingProvider{release: make(chan struct{})}
+	s := NewSyncer(provider, writer, SyncConfig{Enabled: true, Interval: time.Nanosecond, Concurrency: 3}, testLogger(), nil)
+	dates := make([]string, 0, 10)
+	for i := 1; i <= 10; i++ {
+		dates = append(dates, timeutil.FormatDate(time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Backfill(ctx, dates)
+		done <- err
+	}()
+	teststubs.WaitFor(t, 2*time.Second, func() bool { return provider.started() == 3 }, "expected three fetches in flight")
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the cancellation returned, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected every worker to stop promptly after cancel")
+	}
+	if got := provider.started(); got != 3 {
+		t.Fatalf("expected no fetches after cancel, got %d", got)
+	}
+	st := s.Status()
+	if st.Running || st.Total != len(dates) || st.Completed != 3 || st.Failed != 3 {
+		t.Fatalf("expected the partial run reflected in status, got %+v", st)
+	}
+}
+
+// emptyRecordingProvider records the dates it is asked for and returns no games.
+type emptyRecordingProvider struct{ recordingProvider }
+
+func (p *emptyRecordingProvider) FetchGames(ctx context.Context, date string, tz string) ([]domaingames.Game, error) {
+	_, _ = p.recordingProvider.FetchGames(ctx, date, tz)
+	return nil, nil
+}
+
+func TestSyncerWritesEmptySnapshotForPastOffDay(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	writer := NewWriter(t.TempDir(), 5000)
+	clk := teststubs.NewFakeClock(now)
+	provider := &emptyRecordingProvider{}
+	cfg := SyncConfig{Enabled: true, Days: 4, FutureDays: 1, Interval: time.Nanosecond, Clock: clk}
+	s := NewSyncer(provider, writer, cfg, nil, nil)
+
+	driveClock(t, clk, cfg.Interval, func() { s.backfill(context.Background(), now) })
+	assertDatesEqual(t, provider.fetched(), []string{"2024-01-10", "2024-01-09", "2024-01-08", "2024-01-07", "2024-01-11"})
+
+	for _, date := range []string{"2024-01-09", "2024-01-08", "2024-01-07"} {
+		got, err := NewFSStore(writer.BasePath()).LoadGames(context.Background(), date)
+		if err != nil || got.Date != date || got.Games == nil || len(got.Games) != 0 {
+			t.Fatalf("expected an empty snapshot for off-day %s, got %+v %v", date, got, err)
+		}
+	}
+	for _, date := range []string{"2024-01-10", "2024-01-11"} {
+		if _, err := os.Stat(GameSnapshotPath(writer.BasePath(), date)); !os.IsNotExist(err) {
+			t.Fatalf("expected no snapshot for empty %s, got %v", date, err)
+		}
+	}
+	m, err := readManifest(writer.manifestPath(), Retention{}, now)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	assertDatesEqual(t, m.Games.Empty, []string{"2024-01-07", "2024-01-08", "2024-01-09"})
+	if pending := s.pendingDates(); len(pending) != 0 {
+		t.Fatalf("expected no empty date left pending, got %v", pending)
+	}
+
+	// The next cycle retries today, yesterday and the future date, not the
+	// recorded off-days beyond yesterday.
+	provider.dates = nil
+	driveClock(t, clk, cfg.Interval, func() { s.backfill(context.Background(), now) })
+	assertDatesEqual(t, provider.fetched(), []string{"2024-01-10", "2024-01-09", "2024-01-11"})
+
+	// A later write with games clears the mark.
+	writeSimpleSnapshot(t, writer, "2024-01-08")
+	m, _ = readManifest(writer.manifestPath(), Retention{}, now)
+	assertDatesEqual(t, m.Games.Empty, []string{"2024-01-07", "2024-01-09"})
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
@@ -16,26 +19,157 @@ import (
 type snapshotKind string
 
 const (
-	kindGames snapshotKind = "games"
+	kindGames   snapshotKind = "games"
+	kindTeams   snapshotKind = "teams"
+	kindPlayers snapshotKind = "players"
 )
 
-// Writer persists snapshots and manifest with pruning.
+// Default retention windows, in days, applied when a RetentionConfig field is unset.
+// Team catalogs barely change, so they are kept effectively forever.
+const (
+	DefaultGamesRetentionDays   = 14
+	DefaultTeamsRetentionDays   = 3650
+	DefaultPlayersRetentionDays = 60
+)
+
+// RetentionConfig sets the pruning window per snapshot kind; zero or negative
+// values fall back to the Default*RetentionDays constants.
+type RetentionConfig struct {
+	GamesDays   int
+	TeamsDays   int
+	PlayersDays int
+}
+
+func (c RetentionConfig) withDefaults() RetentionConfig {
+	if c.GamesDays <= 0 {
+		c.GamesDays = DefaultGamesRetentionDays
+	}
+	if c.TeamsDays <= 0 {
+		c.TeamsDays = DefaultTeamsRetentionDays
+	}
+	if c.PlayersDays <= 0 {
+		c.PlayersDays = DefaultPlayersRetentionDays
+	}
+	return c
+}
+
+func (c RetentionConfig) days(kind snapshotKind) int {
+	switch kind {
+	case kindTeams:
+		return c.TeamsDays
+	case kindPlayers:
+		return c.PlayersDays
+	default:
+		return c.GamesDays
+	}
+}
+
+// Writer persists snapshots and manifest with pruning. It is safe for concurrent
+// use: mu serializes every snapshot write with its manifest read-modify-write and
+// prune, so concurrent writers cannot drop dates or prune a file mid-write.
 type Writer struct {
-	basePath      string
-	retentionDays int
+	mu          sync.Mutex
+	basePath    string
+	retention   RetentionConfig
+	pruneGuard  func() bool // reports true while pruning must be skipped
+	freezeGrace time.Duration
+	clock       clock.Clock
+	loc         *time.Location // zone whose midnight starts a retention day
+	logger      *slog.Logger
+	onWrite     func(date string) // runs after a games snapshot is in place
+	history     HistoryConfig
+	refreshes   refreshGroup // coalesces concurrent RefreshGames runs
 }
 
-// NewWriter constructs a writer rooted at basePath with a rolling window retention.
+// NewWriter constructs a writer rooted at basePath with a rolling window retention
+// for games; other kinds use their default windows.
 func NewWriter(basePath string, retentionDays int) *Writer {
-	if retentionDays <= 0 {
-		retentionDays = 14
-	}
+	return NewWriterWithRetention(basePath, RetentionConfig{GamesDays: retentionDays})
+}
+
+// NewWriterWithRetention constructs a writer with a separate retention window per kind.
+func NewWriterWithRetention(basePath string, retention RetentionConfig) *Writer {
 	return &Writer{
-		basePath:      basePath,
-		retentionDays: retentionDays,
+		basePath:    basePath,
+		retention:   retention.withDefaults(),
+		freezeGrace: DefaultFreezeGrace,
+		clock:       clock.Real(),
+		loc:         time.UTC,
 	}
 }
 
+// SetClock sets the time source for manifest timestamps, freezing and
+// retention pruning; nil means the real clock. Call before the writer is shared.
+func (w *Writer) SetClock(clk clock.Clock) {
+	if w == nil {
+		return
+	}
+	w.clock = clock.OrReal(clk)
+}
+
+// SetLocation sets the timezone whose calendar retention pruning counts days
+// in, so it agrees with the dates the poller and syncer write; nil means UTC.
+// Call before the writer is shared.
+func (w *Writer) SetLocation(loc *time.Location) {
+	if w == nil {
+		return
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	w.loc = loc
+}
+
+// Location returns the timezone set by SetLocation; UTC for a nil writer.
+func (w *Writer) Location() *time.Location {
+	if w == nil {
+		return time.UTC
+	}
+	return w.loc
+}
+
+// SetPruneGuard installs fn to suppress retention pruning while it returns true
+// (e.g. when SkewChecker suspects the clock). Call before the writer is shared.
+func (w *Writer) SetPruneGuard(fn func() bool) {
+	if w == nil {
+		return
+	}
+	w.pruneGuard = fn
+}
+
+// SetWriteHook installs fn to run, under the writer's lock, once a games
+// snapshot for date is on disk; the server uses it to drop an FSStore's cached
+// miss for that date. Call before the writer is shared.
+func (w *Writer) SetWriteHook(fn func(date string)) {
+	if w == nil {
+		return
+	}
+	w.onWrite = fn
+}
+
+// SetLogger sets the logger for manifest recovery. Call before the writer is shared.
+func (w *Writer) SetLogger(logger *slog.Logger) {
+	if w == nil {
+		return
+	}
+	w.logger = logger
+}
+
+// SetRetention changes the pruning windows; the next write prunes with them.
+// Unlike the other setters it is safe to call while the writer is shared.
+func (w *Writer) SetRetention(retention RetentionConfig) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.retention = retention.withDefaults()
+}
+
+func (w *Writer) pruneSuppressed() bool {
+	return w.pruneGuard != nil && w.pruneGuard()
+}
+
 func (w *Writer) snapshotPath(kind snapshotKind, date string, page int) string {
 	switch kind {
 	case kindGames:
@@ -54,28 +188,68 @@ func (w *Writer) BasePath() string {
 }
 
 // WriteGamesSnapshot writes the games snapshot for the given date (YYYY-MM-DD) and prunes old snapshots.
+// Frozen dates are not rewritten: it returns ErrSnapshotFrozen (see WriteGamesSnapshotForce).
 func (w *Writer) WriteGamesSnapshot(date string, snapshot domaingames.TodayResponse) error {
+	return w.writeGames(date, snapshot, false)
+}
+
+// WriteGamesSnapshotForce is WriteGamesSnapshot but also overwrites frozen dates,
+// for late stat corrections.
+func (w *Writer) WriteGamesSnapshotForce(date string, snapshot domaingames.TodayResponse) error {
+	return w.writeGames(date, snapshot, true)
+}
+
+func (w *Writer) writeGames(date string, snapshot domaingames.TodayResponse, force bool) error {
+	if w == nil {
+		return fmt.Errorf("snapshot writer not configured")
+	}
+	snapshot = prepareGames(date, snapshot)
+	settled := allSettled(snapshot.Games)
+	empty := len(snapshot.Games) == 0
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !force && date != "" {
+		if m, _ := w.loadManifest(); containsDate(m.Games.Frozen, date) {
+			return ErrSnapshotFrozen
+		}
+	}
+	return w.writeSnapshotLocked(kindGames, date, snapshot, 0, func(m *Manifest, now time.Time) {
+		w.trackFreeze(m, date, settled, now)
+		trackEmpty(&m.Games, date, empty)
+	})
+}
+
+// prepareGames fills in date and canonical IDs and sorts the games by start
+// time (see domaingames.SortGames), so equal slates marshal to equal bytes.
+func prepareGames(date string, snapshot domaingames.TodayResponse) domaingames.TodayResponse {
 	if snapshot.Date == "" {
 		snapshot.Date = date
 	}
-	sort.Slice(snapshot.Games, func(i, j int) bool {
-		return snapshot.Games[i].ID < snapshot.Games[j].ID
-	})
-	return w.writeSnapshot(kindGames, date, snapshot)
+	domaingames.AssignCanonicalIDs(date, snapshot.Games)
+	domaingames.SortGames(snapshot.Games, domaingames.SortStartTime)
+	return snapshot
 }
 
 func (w *Writer) writeSnapshot(kind snapshotKind, date string, payload any, page ...int) error {
 	if w == nil {
 		return fmt.Errorf("snapshot writer not configured")
 	}
-	if date == "" {
-		return fmt.Errorf("date required")
-	}
-
 	pageNum := 0
 	if len(page) > 0 {
 		pageNum = page[0]
 	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.writeSnapshotLocked(kind, date, payload, pageNum, nil)
+}
+
+// writeSnapshotLocked writes payload and updates the manifest, applying update
+// (when set) to the manifest before it is saved. Callers hold w.mu.
+func (w *Writer) writeSnapshotLocked(kind snapshotKind, date string, payload any, pageNum int, update func(*Manifest, time.Time)) error {
+	if err := validateTarget(kind, date); err != nil {
+		return err
+	}
+
 	target := w.snapshotPath(kind, date, pageNum)
 	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
 		return err
@@ -87,24 +261,35 @@ func (w *Writer) writeSnapshot(kind snapshotKind, date string, payload any, page
 		return err
 	}
 
-	if existing, err := os.ReadFile(target); err == nil && bytes.Equal(existing, data) {
-		return w.updateManifest(kind, date)
+	existing, readErr := os.ReadFile(target)
+	if readErr == nil && bytes.Equal(existing, data) {
+		w.notifyWrite(kind, date)
+		return w.updateManifest(kind, date, update)
 	}
 
-	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+	if err := writeFileSynced(tmp, data); err != nil {
 		return err
 	}
+	if kind == kindGames && w.history.Enabled && readErr == nil {
+		w.archiveGamesLocked(date, existing)
+	}
 	if err := os.Rename(tmp, target); err != nil {
 		return err
 	}
+	w.notifyWrite(kind, date)
+
+	return w.updateManifest(kind, date, update)
+}
 
-	return w.updateManifest(kind, date)
+func (w *Writer) notifyWrite(kind snapshotKind, date string) {
+	if kind == kindGames && w.onWrite != nil {
+		w.onWrite(date)
+	}
 }
 
-func (w *Writer) updateManifest(kind snapshotKind, date string) error {
-	manifestPath := filepath.Join(w.basePath, "manifest.json")
-	m, _ := readManifest(manifestPath, w.retentionDays)
-	now := time.Now().UTC()
+func (w *Writer) updateManifest(kind snapshotKind, date string, update func(*Manifest, time.Time)) error {
+	m, _ := w.loadManifest()
+	now := w.clock.Now().UTC()
 
 	dates, err := w.listDates(kind)
 	if err != nil {
@@ -113,19 +298,36 @@ func (w *Writer) updateManifest(kind snapshotKind, date string) error {
 	if !containsDate(dates, date) {
 		dates = append(dates, date)
 	}
-	pruned, err := w.pruneOldSnapshots(kind, dates)
-	if err != nil {
+	var pinned []string
+	if kind == kindGames {
+		pinned = m.Games.Pinned
+	}
+	pruned := dates
+	if w.pruneSuppressed() {
+		sort.Strings(pruned)
+	} else if pruned, err = w.pruneOldSnapshots(kind, dates, pinned); err != nil {
 		return err
 	}
 
+	m.Retention = w.retention.manifest()
 	switch kind {
 	case kindGames:
 		m.Games.Dates = pruned
 		m.Games.LastRefreshed = now
-		m.Retention.GamesDays = w.retentionDays
+		if update != nil {
+			update(&m, now)
+		}
+		dropFreezeState(&m.Games)
+	case kindTeams, kindPlayers:
+		field := m.kindField(kind)
+		if *field == nil {
+			*field = &KindMeta{}
+		}
+		(*field).Dates = pruned
+		(*field).LastRefreshed = now
 	}
 
-	return writeManifest(w.basePath, m)
+	return w.saveManifest(m)
 }
 
 func containsDate(dates []string, date string) bool {
@@ -138,7 +340,12 @@ func containsDate(dates []string, date string) bool {
 }
 
 func (w *Writer) listDates(kind snapshotKind) ([]string, error) {
-	dir := filepath.Join(w.basePath, string(kind))
+	return listDatesIn(filepath.Join(w.basePath, string(kind)))
+}
+
+// listDatesIn returns the sorted base names of the .json files in dir,
+// ignoring subdirectories; a missing dir has none.
+func listDatesIn(dir string) ([]string, error) {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -169,23 +376,30 @@ func (w *Writer) listDates(kind snapshotKind) ([]string, error) {
 	return dates, nil
 }
 
-func (w *Writer) pruneOldSnapshots(kind snapshotKind, dates []string) ([]string, error) {
-	now := time.Now().UTC()
-	cutoff := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -w.retentionDays)
+// pruneOldSnapshots removes snapshots older than the kind's retention window, always keeping pinned dates.
+// The window counts calendar days back from today in the writer's location.
+func (w *Writer) pruneOldSnapshots(kind snapshotKind, dates []string, pinned []string) ([]string, error) {
+	return w.pruneDates(w.retention.days(kind), dates, pinned, func(date string) string {
+		return w.snapshotPath(kind, date, 0)
+	}), nil
+}
+
+// pruneDates removes the file path returns for each date older than days,
+// except pinned ones, and returns the sorted dates kept.
+func (w *Writer) pruneDates(days int, dates, pinned []string, path func(date string) string) []string {
+	cutoff := timeutil.DateOffset(w.clock.Now(), w.loc, -days)
 	var keep []string
 	for _, d := range dates {
-		parsed, err := timeutil.ParseDate(d)
-		if err != nil {
+		if _, err := timeutil.ParseDate(d); err != nil {
 			keep = append(keep, d)
 			continue
 		}
-		if parsed.Before(cutoff) {
-			path := w.snapshotPath(kind, d, 0)
-			_ = os.Remove(path)
+		if d < cutoff && !containsDate(pinned, d) {
+			_ = os.Remove(path(d))
 			continue
 		}
 		keep = append(keep, d)
 	}
 	sort.Strings(keep)
-	return keep, nil
+	return keep
 }
@@ -2,13 +2,17 @@ package snapshots
 
 import (
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
 	"github.com/preston-bernstein/nba-data-service/internal/timeutil"
 )
 
@@ -33,7 +37,7 @@ func TestWriterWritesSnapshotAndManifest(t *testing.T) {
 	}
 
 	// Verify manifest updated.
-	m, err := readManifest(filepath.Join(dir, "manifest.json"), 0)
+	m, err := readManifest(filepath.Join(dir, "manifest.json"), Retention{}, time.Now())
 	if err != nil {
 		t.Fatalf("expected manifest read: %v", err)
 	}
@@ -88,7 +92,7 @@ func TestNewWriterDefaultsRetention(t *testing.T) {
 	if err := w.WriteGamesSnapshot(date, domaingames.TodayResponse{Games: []domaingames.Game{{ID: "g1"}}}); err != nil {
 		t.Fatalf("expected snapshot write with default retention, got %v", err)
 	}
-	m, err := readManifest(filepath.Join(w.BasePath(), "manifest.json"), 0)
+	m, err := readManifest(filepath.Join(w.BasePath(), "manifest.json"), Retention{}, time.Now())
 	if err != nil {
 		t.Fatalf("expected manifest read: %v", err)
 	}
@@ -97,6 +101,24 @@ func TestNewWriterDefaultsRetention(t *testing.T) {
 	}
 }
 
+func TestSetRetentionAppliesToNextWrite(t *testing.T) {
+	w := NewWriter(t.TempDir(), 30)
+	clk := teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	w.SetClock(clk)
+	w.SetRetention(RetentionConfig{GamesDays: 2})
+	date := timeutil.FormatDate(clk.Now())
+	if err := w.WriteGamesSnapshot(date, domaingames.TodayResponse{Games: []domaingames.Game{{ID: "g1"}}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	if m.Retention.GamesDays != 2 || m.Retention.TeamsDays != DefaultTeamsRetentionDays {
+		t.Fatalf("expected new retention with defaults filled, got %+v", m.Retention)
+	}
+}
+
 func TestBasePathHandlesNil(t *testing.T) {
 	var w *Writer
 	if w.BasePath() != "" {
@@ -107,8 +129,10 @@ func TestBasePathHandlesNil(t *testing.T) {
 func TestPruneOldSnapshotsRemovesExpiredAndKeepsInvalid(t *testing.T) {
 	dir := t.TempDir()
 	w := NewWriter(dir, 1)
-	old := timeutil.FormatDate(time.Now().AddDate(0, 0, -3))
-	recent := timeutil.FormatDate(time.Now())
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	w.SetClock(teststubs.NewFakeClock(now))
+	old := timeutil.FormatDate(now.AddDate(0, 0, -3))
+	recent := timeutil.FormatDate(now)
 	invalid := "not-a-date"
 
 	writeFile := func(date string) {
@@ -124,7 +148,7 @@ func TestPruneOldSnapshotsRemovesExpiredAndKeepsInvalid(t *testing.T) {
 	writeFile(recent)
 	writeFile(invalid)
 
-	pruned, err := w.pruneOldSnapshots(kindGames, []string{old, recent, invalid})
+	pruned, err := w.pruneOldSnapshots(kindGames, []string{old, recent, invalid}, nil)
 	if err != nil {
 		t.Fatalf("prune failed: %v", err)
 	}
@@ -143,6 +167,26 @@ func TestPruneOldSnapshotsRemovesExpiredAndKeepsInvalid(t *testing.T) {
 	}
 }
 
+func TestPruneOldSnapshotsUsesWriterClock(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 2)
+	w.SetClock(teststubs.NewFakeClock(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)))
+	for _, date := range []string{"2024-01-10", "2024-01-14"} {
+		if err := w.WriteGamesSnapshot(date, domaingames.TodayResponse{}); err != nil {
+			t.Fatalf("write %s: %v", date, err)
+		}
+	}
+
+	// Against the real clock both dates would be long past retention.
+	kept, err := w.pruneOldSnapshots(kindGames, []string{"2024-01-10", "2024-01-14"}, nil)
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if len(kept) != 1 || kept[0] != "2024-01-14" {
+		t.Fatalf("expected only the date inside retention kept, got %v", kept)
+	}
+}
+
 func TestListDatesIgnoresNonJSONAndDirs(t *testing.T) {
 	dir := t.TempDir()
 	if err := os.MkdirAll(filepath.Join(dir, "games", "nested"), 0o755); err != nil {
@@ -190,3 +234,194 @@ func TestContainsDate(t *testing.T) {
 		t.Fatalf("expected containsDate to return false for missing date")
 	}
 }
+
+// Only the games kind exists in this tree, so concurrency is exercised across
+// dates plus pin and unpin calls racing the writes.
+func TestWriterConcurrentWritesKeepEveryManifestDate(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriter(dir, 30)
+	today := time.Now()
+	const writers = 20
+
+	dates := make([]string, writers)
+	for i := range dates {
+		dates[i] = timeutil.FormatDate(today.AddDate(0, 0, -i))
+	}
+	var wg sync.WaitGroup
+	for i, d := range dates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := w.WriteGamesSnapshot(d, domaingames.TodayResponse{Games: []domaingames.Game{{ID: d}}}); err != nil {
+				t.Errorf("write %s failed: %v", d, err)
+			}
+			if i%5 == 0 {
+				_ = w.PinDate(d)
+				_, _ = w.Manifest()
+			}
+		}()
+	}
+	wg.Wait()
+
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest failed: %v", err)
+	}
+	for _, d := range dates {
+		if !containsDate(m.Games.Dates, d) {
+			t.Fatalf("expected manifest to list %s, got %v", d, m.Games.Dates)
+		}
+		if _, err := os.Stat(GameSnapshotPath(dir, d)); err != nil {
+			t.Fatalf("expected snapshot for %s on disk: %v", d, err)
+		}
+	}
+	if len(m.Games.Pinned) != writers/5 {
+		t.Fatalf("expected %d pinned dates, got %v", writers/5, m.Games.Pinned)
+	}
+}
+
+func TestPruneRetentionIsPerKind(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWriterWithRetention(dir, RetentionConfig{GamesDays: 14, PlayersDays: 2})
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	w.SetClock(teststubs.NewFakeClock(now))
+	day := func(offset int) string { return timeutil.FormatDate(now.AddDate(0, 0, offset)) }
+	payload := map[string]string{"kind": "catalog"}
+
+	// Seed teams/players snapshots older than the games window.
+	for _, kind := range []snapshotKind{kindTeams, kindPlayers} {
+		for _, d := range []string{day(-30), day(-5)} {
+			path := w.snapshotPath(kind, d, 0)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatalf("mkdir: %v", err)
+			}
+			if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+				t.Fatalf("seed %s: %v", kind, err)
+			}
+		}
+	}
+	writeSimpleSnapshot(t, w, day(-30))
+	writeSimpleSnapshot(t, w, day(-10))
+	writeSimpleSnapshot(t, w, day(0))
+
+	assertExists := func(kind snapshotKind, d string, want bool) {
+		t.Helper()
+		_, err := os.Stat(w.snapshotPath(kind, d, 0))
+		if exists := err == nil; exists != want {
+			t.Fatalf("%s %s: expected exists=%v, got %v", kind, d, want, exists)
+		}
+	}
+	assertExists(kindGames, day(-30), false)
+	assertExists(kindGames, day(-10), true)
+	assertExists(kindTeams, day(-30), true)
+	assertExists(kindPlayers, day(-30), true)
+
+	// Pruning players honors the players window and leaves games and teams alone.
+	if err := w.writeSnapshot(kindPlayers, day(0), payload); err != nil {
+		t.Fatalf("write players: %v", err)
+	}
+	assertExists(kindPlayers, day(-30), false)
+	assertExists(kindPlayers, day(-5), false)
+	assertExists(kindPlayers, day(0), true)
+	assertExists(kindGames, day(-10), true)
+	if err := w.writeSnapshot(kindTeams, day(0), payload); err != nil {
+		t.Fatalf("write teams: %v", err)
+	}
+	assertExists(kindTeams, day(-30), true)
+
+	m, err := w.Manifest()
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	want := Retention{GamesDays: 14, TeamsDays: DefaultTeamsRetentionDays, PlayersDays: 2}
+	if m.Retention != want {
+		t.Fatalf("expected manifest retention %+v, got %+v", want, m.Retention)
+	}
+	if !containsDate(m.Games.Dates, day(-10)) || containsDate(m.Games.Dates, day(-30)) {
+		t.Fatalf("expected games dates unaffected by other kinds, got %v", m.Games.Dates)
+	}
+}
+
+func TestPruneCutoffUsesWriterLocation(t *testing.T) {
+	// 04:30 UTC is 23:30 the previous evening in New York.
+	now := time.Date(2024, 1, 15, 4, 30, 0, 0, time.UTC)
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	cases := []struct {
+		name string
+		loc  *time.Location
+		kept []string
+	}{
+		{"new york", ny, []string{"2024-01-12", "2024-01-14"}},
+		{"utc", nil, []string{"2024-01-14"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWriter(t.TempDir(), 2)
+			w.SetClock(teststubs.NewFakeClock(now))
+			w.SetLocation(tc.loc)
+			dates := []string{"2024-01-11", "2024-01-12", "2024-01-14"}
+			for _, d := range dates {
+				path := w.snapshotPath(kindGames, d, 0)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatalf("mkdir: %v", err)
+				}
+				if err := os.WriteFile(path, []byte("{}"), 0o644); err != nil {
+					t.Fatalf("seed %s: %v", d, err)
+				}
+			}
+
+			kept, err := w.pruneOldSnapshots(kindGames, dates, nil)
+			if err != nil {
+				t.Fatalf("prune: %v", err)
+			}
+			assertDatesEqual(t, kept, tc.kept)
+			for _, d := range dates {
+				_, err := os.Stat(w.snapshotPath(kindGames, d, 0))
+				if exists := err == nil; exists != containsDate(tc.kept, d) {
+					t.Fatalf("%s: expected exists=%v, got %v", d, containsDate(tc.kept, d), exists)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSnapshotRejectsUnknownKindAndMalformedDate(t *testing.T) {
+	w := NewWriter(t.TempDir(), 7)
+	if err := w.writeSnapshot(snapshotKind("../other"), "2024-01-01", struct{}{}); !errors.Is(err, ErrUnknownSnapshotKind) {
+		t.Fatalf("expected ErrUnknownSnapshotKind, got %v", err)
+	}
+	for _, date := range []string{"", "2024-01-1", "2024-13-01", "20240101", "2024-01-01/..", "../../x"} {
+		if err := w.WriteGamesSnapshot(date, domaingames.TodayResponse{}); !errors.Is(err, ErrInvalidSnapshotDate) {
+			t.Fatalf("expected ErrInvalidSnapshotDate for %q, got %v", date, err)
+		}
+	}
+}
+
+func FuzzWriterStaysUnderBasePath(f *testing.F) {
+	for _, seed := range []string{"2024-01-01", "../2024-01-01", "2024-01-01/../../x", "..", "2024\\01\\01", "2024-02-29"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, date string) {
+		root := t.TempDir()
+		base := filepath.Join(root, "base")
+		w := NewWriter(base, 7)
+		_ = w.WriteGamesSnapshot(date, domaingames.TodayResponse{})
+		_ = w.writeSnapshot(kindTeams, date, struct{}{})
+
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || path == root || path == base {
+				return err
+			}
+			if !strings.HasPrefix(path, base+string(filepath.Separator)) {
+				t.Fatalf("date %q created %s outside %s", date, path, base)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("walk failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package snapshots
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+// WriteGamesSnapshotForZone writes snapshot as zone's games for date, the
+// slate on that calendar day in zone, under games/<zone slug>/ and prunes
+// the zone's old files with the games retention window and pins. An empty
+// zone, or the writer's own location, writes the regular snapshot instead.
+// Zone-scoped files are never frozen or archived to history.
+func (w *Writer) WriteGamesSnapshotForZone(date, zone string, snapshot domaingames.TodayResponse) error {
+	if w == nil {
+		return fmt.Errorf("snapshot writer not configured")
+	}
+	if zone == "" || zone == w.loc.String() {
+		return w.WriteGamesSnapshot(date, snapshot)
+	}
+	slug, err := ZoneSlug(zone)
+	if err != nil {
+		return err
+	}
+	if err := validateDate(date); err != nil {
+		return err
+	}
+	snapshot = prepareGames(date, snapshot)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := w.zonePath(slug, date)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if existing, readErr := os.ReadFile(target); readErr != nil || !bytes.Equal(existing, data) {
+		tmp := target + ".tmp"
+		if err := writeFileSynced(tmp, data); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, target); err != nil {
+			return err
+		}
+	}
+	return w.updateZoneManifest(zone, slug, date)
+}
+
+func (w *Writer) zonePath(slug, date string) string {
+	return filepath.Join(w.basePath, string(kindGames), slug, fmt.Sprintf("%s.json", date))
+}
+
+func (w *Writer) updateZoneManifest(zone, slug, date string) error {
+	m, _ := w.loadManifest()
+	now := w.clock.Now().UTC()
+
+	dates, err := listDatesIn(filepath.Join(w.basePath, string(kindGames), slug))
+	if err != nil {
+		return err
+	}
+	if !containsDate(dates, date) {
+		dates = append(dates, date)
+	}
+	if w.pruneSuppressed() {
+		sort.Strings(dates)
+	} else {
+		dates = w.pruneDates(w.retention.GamesDays, dates, m.Games.Pinned, func(d string) string {
+			return w.zonePath(slug, d)
+		})
+	}
+
+	m.Retention = w.retention.manifest()
+	if m.Games.Zones == nil {
+		m.Games.Zones = make(map[string]*ZoneMeta)
+	}
+	m.Games.Zones[slug] = &ZoneMeta{Zone: zone, Dates: dates, LastRefreshed: now}
+	return w.saveManifest(m)
+}
+
+// scanZones lists the zone directories under games/ for scanManifest. Zones
+// keep their name from prev; a directory prev does not know (slugs cannot be
+// reversed) is recorded with an empty zone.
+func (w *Writer) scanZones(prev map[string]*ZoneMeta) (map[string]*ZoneMeta, map[string]ManifestChange, error) {
+	root := filepath.Join(w.basePath, string(kindGames))
+	entries, err := os.ReadDir(root)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+	zones := make(map[string]*ZoneMeta)
+	changes := make(map[string]ManifestChange)
+	for _, e := range entries {
+		// games/history holds archived versions, not a zone.
+		if !e.IsDir() || e.Name() == "history" {
+			continue
+		}
+		slug := e.Name()
+		dates, err := listDatesIn(filepath.Join(root, slug))
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(dates) == 0 {
+			continue
+		}
+		var meta ZoneMeta
+		if old := prev[slug]; old != nil {
+			meta = *old
+		}
+		changes[slug] = ManifestChange{Dates: dates, Added: missingDates(dates, meta.Dates), Removed: missingDates(meta.Dates, dates)}
+		meta.Dates = dates
+		var newest time.Time
+		for _, d := range dates {
+			if info, err := os.Stat(w.zonePath(slug, d)); err == nil && info.ModTime().After(newest) {
+				newest = info.ModTime().UTC()
+			}
+		}
+		meta.LastRefreshed = newest
+		zones[slug] = &meta
+	}
+	for slug, old := range prev {
+		if _, ok := zones[slug]; !ok && old != nil {
+			changes[slug] = ManifestChange{Dates: []string{}, Added: []string{}, Removed: missingDates(old.Dates, nil)}
+		}
+	}
+	if len(zones) == 0 {
+		zones = nil
+	}
+	return zones, changes, nil
+}
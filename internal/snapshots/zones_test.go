@@ -0,0 +1,193 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+)
+
+func TestZoneSlug(t *testing.T) {
+	cases := map[string]string{
+		"America/New_York":               "america-new_york",
+		"Asia/Tokyo":                     "asia-tokyo",
+		"America/Argentina/Buenos_Aires": "america-argentina-buenos_aires",
+		"Etc/GMT+5":                      "etc-gmt+5",
+		"UTC":                            "utc",
+	}
+	for zone, want := range cases {
+		if got, err := ZoneSlug(zone); err != nil || got != want {
+			t.Fatalf("ZoneSlug(%q) = %q, %v; want %q", zone, got, err, want)
+		}
+	}
+	for _, zone := range []string{"", "Local", "Mars/Olympus", "../etc", "Asia/Tokyo/..", `Asia\Tokyo`} {
+		if _, err := ZoneSlug(zone); !errors.Is(err, ErrInvalidSnapshotZone) {
+			t.Fatalf("ZoneSlug(%q): expected ErrInvalidSnapshotZone, got %v", zone, err)
+		}
+	}
+}
+
+func TestWriteGamesSnapshotForZoneLayoutAndManifest(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+	if err := w.WriteGamesSnapshotForZone(date, "Asia/Tokyo", simpleSnapshot(date)); err != nil {
+		t.Fatalf("write zone snapshot: %v", err)
+	}
+	want := filepath.Join(w.BasePath(), "games", "asia-tokyo", date+".json")
+	if got, err := ZoneGameSnapshotPath(w.BasePath(), "Asia/Tokyo", date); err != nil || got != want {
+		t.Fatalf("expected zone path %s, got %s %v", want, got, err)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("expected zone snapshot at %s: %v", want, err)
+	}
+	if _, err := os.Stat(GameSnapshotPath(w.BasePath(), date)); !os.IsNotExist(err) {
+		t.Fatalf("expected no service-zone snapshot written, got %v", err)
+	}
+
+	m, err := readManifest(w.manifestPath(), Retention{}, time.Now())
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	meta := m.Games.Zones["asia-tokyo"]
+	if meta == nil || meta.Zone != "Asia/Tokyo" || meta.LastRefreshed.IsZero() {
+		t.Fatalf("expected Asia/Tokyo tracked in the manifest, got %+v", m.Games.Zones)
+	}
+	assertDatesEqual(t, meta.Dates, []string{date})
+	assertDatesEqual(t, m.Games.Dates, []string{})
+
+	// The service zone itself is the regular snapshot.
+	if err := w.WriteGamesSnapshotForZone(date, "UTC", simpleSnapshot(date)); err != nil {
+		t.Fatalf("write service-zone snapshot: %v", err)
+	}
+	requireSnapshotExists(t, w, date)
+	if err := w.WriteGamesSnapshotForZone(date, "Mars/Olympus", simpleSnapshot(date)); !errors.Is(err, ErrInvalidSnapshotZone) {
+		t.Fatalf("expected an unknown zone rejected, got %v", err)
+	}
+}
+
+func TestWriteGamesSnapshotForZonePrunesWithinZone(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	setup := true
+	w.SetPruneGuard(func() bool { return setup })
+	for _, date := range []string{"2024-01-01", "2024-01-02", "2024-01-14"} {
+		if err := w.WriteGamesSnapshotForZone(date, "Asia/Tokyo", simpleSnapshot(date)); err != nil {
+			t.Fatalf("write %s: %v", date, err)
+		}
+	}
+	writeSimpleSnapshot(t, w, "2024-01-02")
+	if err := w.PinDate("2024-01-02"); err != nil {
+		t.Fatalf("pin: %v", err)
+	}
+	setup = false
+	if err := w.WriteGamesSnapshotForZone("2024-01-15", "Asia/Tokyo", simpleSnapshot("2024-01-15")); err != nil {
+		t.Fatalf("write latest: %v", err)
+	}
+
+	m, err := readManifest(w.manifestPath(), Retention{}, time.Now())
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	// 2024-01-01 is past the 7-day window; the pinned 2024-01-02 stays.
+	assertDatesEqual(t, m.Games.Zones["asia-tokyo"].Dates, []string{"2024-01-02", "2024-01-14", "2024-01-15"})
+	if _, err := os.Stat(filepath.Join(w.BasePath(), "games", "asia-tokyo", "2024-01-01.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected the expired zone file removed, got %v", err)
+	}
+	assertDatesEqual(t, m.Games.Dates, []string{"2024-01-02"})
+}
+
+func TestRebuildManifestScansZones(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	w.SetHistory(HistoryConfig{Enabled: true, RetentionDays: 7})
+	writeSimpleSnapshot(t, w, "2024-01-14")
+	writeSnapshot(t, w, "2024-01-14", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "changed"}}})
+	if err := w.WriteGamesSnapshotForZone("2024-01-14", "Asia/Tokyo", simpleSnapshot("2024-01-14")); err != nil {
+		t.Fatalf("write zone: %v", err)
+	}
+	dir := filepath.Join(w.BasePath(), "games", "asia-tokyo")
+	if err := os.WriteFile(filepath.Join(dir, "2024-01-13.json"), []byte(`{"date":"2024-01-13","games":[]}`), 0o644); err != nil {
+		t.Fatalf("hand-add zone file: %v", err)
+	}
+
+	report, err := w.RebuildManifest()
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	if !report.Changed() || len(report.Zones) != 1 {
+		t.Fatalf("expected one changed zone and no history zone, got %+v", report.Zones)
+	}
+	assertDatesEqual(t, report.Zones["asia-tokyo"].Added, []string{"2024-01-13"})
+	m, err := readManifest(w.manifestPath(), Retention{}, time.Now())
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if meta := m.Games.Zones["asia-tokyo"]; meta == nil || meta.Zone != "Asia/Tokyo" {
+		t.Fatalf("expected the zone name kept across the rebuild, got %+v", m.Games.Zones)
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("remove zone dir: %v", err)
+	}
+	report, err = w.RebuildManifest()
+	if err != nil {
+		t.Fatalf("second rebuild: %v", err)
+	}
+	assertDatesEqual(t, report.Zones["asia-tokyo"].Removed, []string{"2024-01-13", "2024-01-14"})
+	if m, _ := readManifest(w.manifestPath(), Retention{}, time.Now()); m.Games.Zones != nil {
+		t.Fatalf("expected the removed zone dropped, got %+v", m.Games.Zones)
+	}
+}
+
+func TestFSStoreLoadGamesForZone(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	date := "2024-01-15"
+	if err := w.WriteGamesSnapshotForZone(date, "Asia/Tokyo", domaingames.TodayResponse{Games: []domaingames.Game{{ID: "tokyo"}}}); err != nil {
+		t.Fatalf("write zone: %v", err)
+	}
+	for _, store := range []*FSStore{NewFSStore(w.BasePath()), NewFSStoreWithCache(w.BasePath(), 4)} {
+		got, err := store.LoadGamesForZone(context.Background(), date, "Asia/Tokyo")
+		if err != nil || len(got.Games) != 1 || got.Games[0].ID != "tokyo" || got.Date != date {
+			t.Fatalf("expected the zone snapshot, got %+v %v", got, err)
+		}
+		if _, err := store.LoadGamesForZone(context.Background(), date, "Europe/London"); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected a missing zone to be ErrNotExist, got %v", err)
+		}
+		if _, err := store.LoadGamesForZone(context.Background(), date, "Mars/Olympus"); !errors.Is(err, ErrInvalidSnapshotZone) {
+			t.Fatalf("expected an invalid zone rejected, got %v", err)
+		}
+	}
+}
+
+// zoneProvider returns a game named after the date and tz it was asked for.
+type zoneProvider struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *zoneProvider) FetchGames(_ context.Context, date, tz string) ([]domaingames.Game, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, date+"|"+tz)
+	return []domaingames.Game{{ID: date + "@" + tz}}, nil
+}
+
+func TestSyncerWritesConfiguredZones(t *testing.T) {
+	w, _ := freezeWriter(t, time.Hour)
+	provider := &zoneProvider{}
+	s := NewSyncer(provider, w, SyncConfig{Interval: time.Nanosecond, Zones: []string{"Asia/Tokyo", "UTC", "Asia/Tokyo", ""}}, nil, time.UTC)
+
+	if failed, err := s.Backfill(context.Background(), []string{"2024-01-15"}); err != nil || len(failed) != 0 {
+		t.Fatalf("backfill: failed %v err %v", failed, err)
+	}
+	assertDatesEqual(t, provider.calls, []string{"2024-01-15|", "2024-01-15|Asia/Tokyo"})
+	got, err := NewFSStore(w.BasePath()).LoadGamesForZone(context.Background(), "2024-01-15", "Asia/Tokyo")
+	if err != nil || len(got.Games) != 1 || got.Games[0].ID != "2024-01-15@Asia/Tokyo" {
+		t.Fatalf("expected Tokyo's slate in its zone snapshot, got %+v %v", got, err)
+	}
+	requireSnapshotExists(t, w, "2024-01-15")
+}
@@ -0,0 +1,165 @@
+package teststubs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+)
+
+// FakeClock is a clock.Clock whose time only moves when Advance is called.
+// Timers and tickers fire synchronously during Advance, in deadline order.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+type fakeWaiter struct {
+	clock  *FakeClock
+	at     time.Time
+	period time.Duration // zero for timers
+	active bool
+	ch     chan time.Time
+}
+
+var _ clock.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock starting at start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the current virtual time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a timer that fires once virtual time reaches now+d.
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	return c.addWaiter(d, 0)
+}
+
+// NewTicker returns a ticker that fires every d of virtual time.
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	return fakeTicker{c.addWaiter(d, d)}
+}
+
+// Sleep blocks until another goroutine advances the clock by at least d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.NewTimer(d).C()
+}
+
+// Set moves virtual time to t, firing anything due along the way.
+func (c *FakeClock) Set(t time.Time) {
+	c.Advance(t.Sub(c.Now()))
+}
+
+// Advance moves virtual time forward by d, firing due timers and tickers.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	target := c.now.Add(d)
+	for {
+		w := c.nextDue(target)
+		if w == nil {
+			break
+		}
+		c.now = w.at
+		select {
+		case w.ch <- w.at:
+		default:
+		}
+		if w.period > 0 {
+			w.at = w.at.Add(w.period)
+		} else {
+			w.active = false
+		}
+	}
+	c.now = target
+}
+
+// ActiveTimers reports how many timers and tickers are waiting to fire.
+func (c *FakeClock) ActiveTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, w := range c.waiters {
+		if w.active {
+			n++
+		}
+	}
+	return n
+}
+
+// WaitForTimers polls until at least n timers are active, so tests can advance
+// only after a goroutine has armed its next wait. Returns false on timeout.
+func (c *FakeClock) WaitForTimers(n int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if c.ActiveTimers() >= n {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return c.ActiveTimers() >= n
+}
+
+func (c *FakeClock) addWaiter(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w := &fakeWaiter{clock: c, at: c.now.Add(d), period: period, active: true, ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return w
+}
+
+// nextDue returns the active waiter with the earliest deadline at or before target.
+func (c *FakeClock) nextDue(target time.Time) *fakeWaiter {
+	var next *fakeWaiter
+	for _, w := range c.waiters {
+		if !w.active || w.at.After(target) {
+			continue
+		}
+		if next == nil || w.at.Before(next.at) {
+			next = w
+		}
+	}
+	return next
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+// Stop deactivates the waiter and drains any undelivered tick, matching time.Timer since Go 1.23.
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	wasActive := w.active
+	w.active = false
+	w.drain()
+	return wasActive
+}
+
+// Reset rearms the waiter to fire d after the current virtual time.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	wasActive := w.active
+	w.drain()
+	w.at = w.clock.now.Add(d)
+	w.active = true
+	return wasActive
+}
+
+type fakeTicker struct{ w *fakeWaiter }
+
+func (t fakeTicker) C() <-chan time.Time { return t.w.ch }
+func (t fakeTicker) Stop()               { t.w.Stop() }
+
+func (w *fakeWaiter) drain() {
+	select {
+	case <-w.ch:
+	default:
+	}
+}
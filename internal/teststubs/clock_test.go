@@ -0,0 +1,105 @@
+package teststubs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeClockFiresTimersOnAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	timer := c.NewTimer(time.Minute)
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+
+	c.Advance(30 * time.Second)
+	select {
+	case at := <-timer.C():
+		if !at.Equal(start.Add(time.Minute)) {
+			t.Fatalf("expected fire at +1m, got %s", at)
+		}
+	default:
+		t.Fatal("expected timer to fire")
+	}
+	if c.ActiveTimers() != 0 {
+		t.Fatalf("expected fired timer inactive")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Fatalf("expected reset of fired timer to report inactive")
+	}
+	if !timer.Stop() {
+		t.Fatalf("expected stop of armed timer to report active")
+	}
+	c.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestFakeClockTickerAndSleep(t *testing.T) {
+	c := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ticker := c.NewTicker(time.Hour)
+	c.Advance(time.Hour)
+	<-ticker.C()
+	c.Advance(time.Hour)
+	<-ticker.C()
+	ticker.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Minute)
+		close(done)
+	}()
+	if !c.WaitForTimers(1, time.Second) {
+		t.Fatal("expected sleeper to arm a timer")
+	}
+	c.Set(c.Now().Add(time.Minute))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected sleep to return after advance")
+	}
+	if c.WaitForTimers(1, 5*time.Millisecond) {
+		t.Fatal("expected no active timers")
+	}
+}
+
+func TestWaitForReturnsOnceConditionHolds(t *testing.T) {
+	var calls int
+	WaitFor(t, time.Second, func() bool {
+		calls++
+		return calls == 3
+	}, "condition never held")
+	if calls != 3 {
+		t.Fatalf("expected WaitFor to stop polling once true, got %d calls", calls)
+	}
+}
+
+type fatalRecorder struct {
+	testing.TB
+	msg string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...any) {
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+func TestWaitForFailsAfterTimeout(t *testing.T) {
+	rec := &fatalRecorder{TB: t}
+	WaitFor(rec, 5*time.Millisecond, func() bool { return rec.msg != "" }, "waiting on %s", "nothing")
+	if !strings.Contains(rec.msg, "timed out after 5ms: waiting on nothing") {
+		t.Fatalf("expected timeout failure message, got %q", rec.msg)
+	}
+}
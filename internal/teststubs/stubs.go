@@ -37,10 +37,16 @@ type StubSnapshotStore struct {
 	Games    map[string]domaingames.TodayResponse // keyed by date
 	LoadErr  error
 	FindGame *domaingames.Game
+	Loads    atomic.Int64 // LoadGames and FindGameByID calls
 }
 
-// LoadGames returns games for the given date if present in the Games map.
-func (s *StubSnapshotStore) LoadGames(date string) (domaingames.TodayResponse, error) {
+// LoadGames returns games for the given date if present in the Games map, or
+// ctx's error once it is done.
+func (s *StubSnapshotStore) LoadGames(ctx context.Context, date string) (domaingames.TodayResponse, error) {
+	s.Loads.Add(1)
+	if err := ctx.Err(); err != nil {
+		return domaingames.TodayResponse{}, err
+	}
 	if s.LoadErr != nil {
 		return domaingames.TodayResponse{}, s.LoadErr
 	}
@@ -55,8 +61,12 @@ func (s *StubSnapshotStore) LoadGames(date string) (domaingames.TodayResponse, e
 }
 
 // FindGameByID searches the snapshot for the given date and returns the game if found.
-func (s *StubSnapshotStore) FindGameByID(date, id string) (domaingames.Game, bool) {
-	if s.FindGame != nil && s.FindGame.ID == id {
+func (s *StubSnapshotStore) FindGameByID(ctx context.Context, date, id string) (domaingames.Game, bool) {
+	s.Loads.Add(1)
+	if ctx.Err() != nil {
+		return domaingames.Game{}, false
+	}
+	if s.FindGame != nil && s.FindGame.MatchesID(id) {
 		return *s.FindGame, true
 	}
 	if s.Games == nil {
@@ -67,7 +77,7 @@ func (s *StubSnapshotStore) FindGameByID(date, id string) (domaingames.Game, boo
 		return domaingames.Game{}, false
 	}
 	for _, g := range resp.Games {
-		if g.ID == id {
+		if g.MatchesID(id) {
 			return g, true
 		}
 	}
@@ -91,3 +101,14 @@ func (w *StubSnapshotWriter) WriteGamesSnapshot(date string, snapshot domaingame
 	w.Written[date] = snapshot
 	return nil
 }
+
+// BlockingProvider blocks until the context is done and returns its error.
+type BlockingProvider struct{}
+
+// FetchGames waits for cancellation, simulating a hung upstream.
+func (BlockingProvider) FetchGames(ctx context.Context, date string, tz string) ([]domaingames.Game, error) {
+	_ = date
+	_ = tz
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -52,17 +52,17 @@ func TestStubSnapshotStore(t *testing.T) {
 		},
 	}
 
-	resp, err := s.LoadGames(date)
+	resp, err := s.LoadGames(context.Background(), date)
 	if err != nil || resp.Date != date {
 		t.Fatalf("expected loaded games, got %v err %v", resp, err)
 	}
 
-	game, ok := s.FindGameByID(date, "g1")
+	game, ok := s.FindGameByID(context.Background(), date, "g1")
 	if !ok || game.ID != "g1" {
 		t.Fatalf("expected game found, got %v ok=%v", game, ok)
 	}
 
-	_, ok = s.FindGameByID(date, "missing")
+	_, ok = s.FindGameByID(context.Background(), date, "missing")
 	if ok {
 		t.Fatalf("expected game not found")
 	}
@@ -72,7 +72,7 @@ func TestStubSnapshotStoreLoadErr(t *testing.T) {
 	loadErr := errors.New("load error")
 	s := &StubSnapshotStore{LoadErr: loadErr}
 
-	_, err := s.LoadGames("2024-01-01")
+	_, err := s.LoadGames(context.Background(), "2024-01-01")
 	if !errors.Is(err, loadErr) {
 		t.Fatalf("expected LoadErr to be returned, got %v", err)
 	}
@@ -81,7 +81,7 @@ func TestStubSnapshotStoreLoadErr(t *testing.T) {
 func TestStubSnapshotStoreNilGames(t *testing.T) {
 	s := &StubSnapshotStore{} // nil Games map
 
-	_, err := s.LoadGames("2024-01-01")
+	_, err := s.LoadGames(context.Background(), "2024-01-01")
 	if err == nil {
 		t.Fatal("expected error for nil Games map")
 	}
@@ -94,7 +94,7 @@ func TestStubSnapshotStoreDateNotFound(t *testing.T) {
 		},
 	}
 
-	_, err := s.LoadGames("2024-01-02") // different date
+	_, err := s.LoadGames(context.Background(), "2024-01-02") // different date
 	if err == nil {
 		t.Fatal("expected error for missing date")
 	}
@@ -104,13 +104,13 @@ func TestStubSnapshotStoreFindGameShortcut(t *testing.T) {
 	game := domaingames.Game{ID: "shortcut-game"}
 	s := &StubSnapshotStore{FindGame: &game}
 
-	found, ok := s.FindGameByID("any-date", "shortcut-game")
+	found, ok := s.FindGameByID(context.Background(), "any-date", "shortcut-game")
 	if !ok || found.ID != "shortcut-game" {
 		t.Fatalf("expected FindGame shortcut to return game, got %v ok=%v", found, ok)
 	}
 
 	// ID mismatch should fall through
-	_, ok = s.FindGameByID("any-date", "other-id")
+	_, ok = s.FindGameByID(context.Background(), "any-date", "other-id")
 	if ok {
 		t.Fatal("expected no match when FindGame ID doesn't match")
 	}
@@ -119,7 +119,7 @@ func TestStubSnapshotStoreFindGameShortcut(t *testing.T) {
 func TestStubSnapshotStoreFindGameNilGames(t *testing.T) {
 	s := &StubSnapshotStore{} // nil Games map, no FindGame
 
-	_, ok := s.FindGameByID("2024-01-01", "g1")
+	_, ok := s.FindGameByID(context.Background(), "2024-01-01", "g1")
 	if ok {
 		t.Fatal("expected not found for nil Games map")
 	}
@@ -132,7 +132,7 @@ func TestStubSnapshotStoreFindGameDateNotFound(t *testing.T) {
 		},
 	}
 
-	_, ok := s.FindGameByID("2024-01-02", "g1") // wrong date
+	_, ok := s.FindGameByID(context.Background(), "2024-01-02", "g1") // wrong date
 	if ok {
 		t.Fatal("expected not found for missing date")
 	}
@@ -155,3 +155,11 @@ func TestStubSnapshotWriter(t *testing.T) {
 		t.Fatalf("expected write error")
 	}
 }
+
+func TestBlockingProviderReturnsContextError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (BlockingProvider{}).FetchGames(ctx, "2024-01-01", ""); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
+	}
+}
@@ -0,0 +1,24 @@
+package teststubs
+
+import (
+	"testing"
+	"time"
+)
+
+// waitPollInterval is how often WaitFor re-checks its condition.
+const waitPollInterval = time.Millisecond
+
+// WaitFor polls cond until it reports true, failing the test with the
+// formatted message if timeout elapses first. Use it in place of a bare
+// time.Sleep before asserting on work done by another goroutine: it returns
+// as soon as the condition holds and tolerates a slow, loaded CI host.
+func WaitFor(t testing.TB, timeout time.Duration, cond func() bool, format string, args ...any) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out after %s: "+format, append([]any{timeout}, args...)...)
+		}
+		time.Sleep(waitPollInterval)
+	}
+}
@@ -1,6 +1,10 @@
 package testutil
 
-import "time"
+import (
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
 
 // NowAt returns a clock function fixed at the provided time.
 func NowAt(t time.Time) func() time.Time {
@@ -15,3 +19,8 @@ func MustParseRFC3339(v string) time.Time {
 	}
 	return t
 }
+
+// NewFakeClock returns a virtual clock starting at start; see teststubs.FakeClock.
+func NewFakeClock(start time.Time) *teststubs.FakeClock {
+	return teststubs.NewFakeClock(start)
+}
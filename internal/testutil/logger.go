@@ -3,6 +3,7 @@ package testutil
 import (
 	"bytes"
 	"log/slog"
+	"sync"
 )
 
 // NewBufferLogger returns a slog logger backed by a buffer and the buffer for assertions.
@@ -11,3 +12,29 @@ func NewBufferLogger() (*slog.Logger, *bytes.Buffer) {
 	logger := slog.New(slog.NewTextHandler(&buf, nil))
 	return logger, &buf
 }
+
+// SyncBuffer is a bytes.Buffer safe to write from one goroutine while a test
+// reads it from another.
+type SyncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *SyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the contents written so far.
+func (b *SyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// NewSyncBufferLogger is NewBufferLogger for loggers used by background goroutines.
+func NewSyncBufferLogger() (*slog.Logger, *SyncBuffer) {
+	buf := &SyncBuffer{}
+	return slog.New(slog.NewTextHandler(buf, nil)), buf
+}
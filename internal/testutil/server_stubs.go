@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/preston-bernstein/nba-data-service/internal/poller"
 )
@@ -31,27 +32,39 @@ func (p *StubPoller) Status() poller.Status {
 	return p.StatusVal
 }
 
-// StubHTTPServer implements httpServer for tests.
+// StubHTTPServer implements httpServer for tests. ListenCalls is atomic because
+// ListenAndServe runs on the server's own goroutine.
 type StubHTTPServer struct {
 	AddrVal       string
 	HandlerVal    http.Handler
-	ListenCalls   int
+	ListenCalls   atomic.Int32
 	ShutdownCalls int
+	CloseCalls    int
 	ListenErr     error
 	ShutdownErr   error
+	// OnShutdown, when set, runs at the start of Shutdown.
+	OnShutdown func()
 }
 
 func (s *StubHTTPServer) ListenAndServe() error {
-	s.ListenCalls++
+	s.ListenCalls.Add(1)
 	return s.ListenErr
 }
 
 func (s *StubHTTPServer) Shutdown(ctx context.Context) error {
 	_ = ctx
 	s.ShutdownCalls++
+	if s.OnShutdown != nil {
+		s.OnShutdown()
+	}
 	return s.ShutdownErr
 }
 
+func (s *StubHTTPServer) Close() error {
+	s.CloseCalls++
+	return nil
+}
+
 func (s *StubHTTPServer) Addr() string {
 	return s.AddrVal
 }
@@ -65,6 +78,7 @@ type BlockingHTTPServer struct {
 	AddrVal       string
 	HandlerVal    http.Handler
 	ShutdownCalls int
+	CloseCalls    int
 	Unblock       chan struct{}
 }
 
@@ -82,6 +96,11 @@ func (b *BlockingHTTPServer) Shutdown(ctx context.Context) error {
 	}
 }
 
+func (b *BlockingHTTPServer) Close() error {
+	b.CloseCalls++
+	return nil
+}
+
 func (b *BlockingHTTPServer) Addr() string {
 	return b.AddrVal
 }
@@ -105,6 +124,8 @@ func (e *ErrHTTPServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+func (e *ErrHTTPServer) Close() error { return nil }
+
 func (e *ErrHTTPServer) Addr() string {
 	return ":0"
 }
@@ -128,6 +149,8 @@ func (c *CloseableHTTPServer) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+func (c *CloseableHTTPServer) Close() error { return nil }
+
 func (c *CloseableHTTPServer) Addr() string {
 	return ":0"
 }
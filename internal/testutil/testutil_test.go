@@ -136,8 +136,8 @@ func TestServerStubs(t *testing.T) {
 	_ = sh.Shutdown(context.Background())
 	_ = sh.Handler()
 	_ = sh.Addr()
-	if sh.ListenCalls != 1 || sh.ShutdownCalls != 1 {
-		t.Fatalf("expected listen/shutdown calls, got %+v", sh)
+	if sh.ListenCalls.Load() != 1 || sh.ShutdownCalls != 1 {
+		t.Fatalf("expected listen/shutdown calls, got %d/%d", sh.ListenCalls.Load(), sh.ShutdownCalls)
 	}
 
 	b := &BlockingHTTPServer{Unblock: make(chan struct{}), HandlerVal: http.NewServeMux()}
@@ -192,6 +192,9 @@ func TestLoggerAndMetricsHelpers(t *testing.T) {
 	if buf.Len() == 0 {
 		t.Fatalf("expected buffered log output")
 	}
+	syncLogger, syncBuf := NewSyncBufferLogger()
+	go syncLogger.Info("from goroutine")
+	WaitFor(t, time.Second, func() bool { return strings.Contains(syncBuf.String(), "from goroutine") }, "expected goroutine log line")
 	rec, shutdown := NewRecorderWithShutdown()
 	if rec == nil || shutdown == nil {
 		t.Fatalf("expected recorder and shutdown")
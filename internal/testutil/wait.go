@@ -0,0 +1,14 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+// WaitFor polls cond until it holds or timeout elapses; see teststubs.WaitFor.
+func WaitFor(t testing.TB, timeout time.Duration, cond func() bool, format string, args ...any) {
+	t.Helper()
+	teststubs.WaitFor(t, timeout, cond, format, args...)
+}
@@ -1,6 +1,20 @@
 package timeutil
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxTimezoneNameLen bounds accepted IANA zone names; the longest real one is 32 bytes.
+const MaxTimezoneNameLen = 64
+
+// Reasons ValidateTimezoneName rejects a name.
+var (
+	ErrTimezoneTooLong    = errors.New("timezone name too long")
+	ErrTimezoneCharacters = errors.New("timezone name has invalid characters")
+	ErrTimezonePath       = errors.New("timezone name has empty path segments")
+)
 
 // DateLayout defines the canonical date format (YYYY-MM-DD).
 const DateLayout = "2006-01-02"
@@ -15,9 +29,59 @@ func FormatDate(t time.Time) string {
 	return t.Format(DateLayout)
 }
 
+// DateIn returns t's calendar date in loc; nil loc means UTC.
+func DateIn(t time.Time, loc *time.Location) string {
+	return DateOffset(t, loc, 0)
+}
+
+// DateOffset returns the calendar date days after t's date in loc (before it
+// when days is negative). It steps whole calendar days from a UTC midnight
+// rather than moving t, so a DST change in loc can never repeat or skip a date.
+func DateOffset(t time.Time, loc *time.Location, days int) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	y, m, d := t.In(loc).Date()
+	return FormatDate(time.Date(y, m, d+days, 0, 0, 0, 0, time.UTC))
+}
+
+// DateRange returns the calendar dates from offset from through offset to of
+// t's date in loc, inclusive and ascending; empty when from > to.
+func DateRange(t time.Time, loc *time.Location, from, to int) []string {
+	var dates []string
+	for days := from; days <= to; days++ {
+		dates = append(dates, DateOffset(t, loc, days))
+	}
+	return dates
+}
+
+// ValidateTimezoneName checks that name looks like an IANA zone (e.g.
+// America/Los_Angeles, Etc/GMT+5) before it reaches time.LoadLocation, logs, or
+// upstream requests. It does not check that the zone exists.
+func ValidateTimezoneName(name string) error {
+	if len(name) > MaxTimezoneNameLen {
+		return ErrTimezoneTooLong
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '/', c == '_', c == '-', c == '+':
+		default:
+			return ErrTimezoneCharacters
+		}
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" {
+			return ErrTimezonePath
+		}
+	}
+	return nil
+}
+
 // ResolveLocation loads an IANA timezone name or falls back to UTC.
 func ResolveLocation(name string) *time.Location {
-	if name == "" {
+	if name == "" || ValidateTimezoneName(name) != nil {
 		return time.UTC
 	}
 	if loc, err := time.LoadLocation(name); err == nil {
@@ -1,6 +1,8 @@
 package timeutil
 
 import (
+	"errors"
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,6 +25,50 @@ func TestFormatDateUsesLocation(t *testing.T) {
 	}
 }
 
+func TestDateMathAcrossDSTTransitions(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	cases := []struct {
+		name      string
+		at        time.Time
+		loc       *time.Location
+		today     string
+		yesterday string
+		tomorrow  string
+	}{
+		// 2024-03-10 springs forward at 02:00 Eastern; 03-11 00:30 minus 24h would land on 03-09.
+		{"spring forward eastern, just after midnight", time.Date(2024, 3, 11, 4, 30, 0, 0, time.UTC), eastern, "2024-03-11", "2024-03-10", "2024-03-12"},
+		{"spring forward eastern, late evening", time.Date(2024, 3, 11, 3, 30, 0, 0, time.UTC), eastern, "2024-03-10", "2024-03-09", "2024-03-11"},
+		{"spring forward utc", time.Date(2024, 3, 11, 3, 30, 0, 0, time.UTC), nil, "2024-03-11", "2024-03-10", "2024-03-12"},
+		// 2024-11-03 falls back at 02:00 Eastern; 01:30 happens twice.
+		{"fall back eastern, repeated hour", time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC), eastern, "2024-11-03", "2024-11-02", "2024-11-04"},
+		{"fall back eastern, just after midnight", time.Date(2024, 11, 4, 5, 30, 0, 0, time.UTC), eastern, "2024-11-04", "2024-11-03", "2024-11-05"},
+		{"fall back utc", time.Date(2024, 11, 4, 4, 30, 0, 0, time.UTC), time.UTC, "2024-11-04", "2024-11-03", "2024-11-05"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := DateIn(tc.at, tc.loc); got != tc.today {
+				t.Fatalf("expected today %s, got %s", tc.today, got)
+			}
+			if got := DateOffset(tc.at, tc.loc, -1); got != tc.yesterday {
+				t.Fatalf("expected yesterday %s, got %s", tc.yesterday, got)
+			}
+			if got := DateOffset(tc.at, tc.loc, 1); got != tc.tomorrow {
+				t.Fatalf("expected tomorrow %s, got %s", tc.tomorrow, got)
+			}
+			want := []string{tc.yesterday, tc.today, tc.tomorrow}
+			if got := DateRange(tc.at, tc.loc, -1, 1); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("expected range %v, got %v", want, got)
+			}
+		})
+	}
+	if got := DateRange(time.Now(), nil, 1, 0); len(got) != 0 {
+		t.Fatalf("expected an empty range when from > to, got %v", got)
+	}
+}
+
 func TestResolveLocationEmptyUsesUTC(t *testing.T) {
 	loc := ResolveLocation("")
 	if loc != time.UTC {
@@ -43,3 +89,56 @@ func TestResolveLocationInvalidNameFallsBackToUTC(t *testing.T) {
 		t.Fatalf("expected UTC fallback, got %v", loc)
 	}
 }
+
+func TestValidateTimezoneName(t *testing.T) {
+	cases := []struct {
+		name string
+		want error
+	}{
+		{"America/Los_Angeles", nil},
+		{"America/Argentina/Buenos_Aires", nil},
+		{"Etc/GMT+5", nil},
+		{"UTC", nil},
+		{"Not/AZone", nil}, // well-formed; existence is LoadLocation's job
+		{strings.Repeat("A", MaxTimezoneNameLen+1), ErrTimezoneTooLong},
+		{"../../etc/passwd", ErrTimezoneCharacters},
+		{"America/New York", ErrTimezoneCharacters},
+		{"UTC\n", ErrTimezoneCharacters},
+		{"America\\Chicago", ErrTimezoneCharacters},
+		{"/etc/localtime", ErrTimezonePath},
+		{"America//Chicago", ErrTimezonePath},
+		{"America/", ErrTimezonePath},
+	}
+	for _, tc := range cases {
+		if got := ValidateTimezoneName(tc.name); !errors.Is(got, tc.want) {
+			t.Fatalf("ValidateTimezoneName(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func FuzzValidateTimezoneName(f *testing.F) {
+	for _, seed := range []string{"America/New_York", "Etc/GMT-14", "../x", "a//b", "\x00", strings.Repeat("Z/", 40)} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		err := ValidateTimezoneName(name)
+		if again := ValidateTimezoneName(name); again != err {
+			t.Fatalf("inconsistent result for %q: %v then %v", name, err, again)
+		}
+		if err != nil {
+			if loc := ResolveLocation(name); loc != time.UTC {
+				t.Fatalf("expected rejected name %q to resolve to UTC, got %s", name, loc)
+			}
+			return
+		}
+		if len(name) > MaxTimezoneNameLen || strings.Contains(name, "..") || strings.HasPrefix(name, "/") {
+			t.Fatalf("accepted unsafe name %q", name)
+		}
+		for _, r := range name {
+			if r < 0x21 || r > 0x7e {
+				t.Fatalf("accepted non-printable or non-ASCII name %q", name)
+			}
+		}
+		_, _ = time.LoadLocation(name)
+	})
+}
@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/preston-bernstein/nba-data-service/internal/clock"
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/logging"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256=".
+const SignatureHeader = "X-Webhook-Signature"
+
+// EventType identifies the payload sent for a game status transition.
+const EventType = "game.status_changed"
+
+const (
+	defaultQueueSize   = 100
+	defaultMaxAttempts = 3
+	defaultBackoff     = time.Second
+	defaultTimeout     = 5 * time.Second
+)
+
+// Config controls webhook delivery.
+type Config struct {
+	URL         string
+	Secret      string        // signs payloads when set
+	QueueSize   int           // events buffered before new ones are dropped
+	MaxAttempts int           // total tries per event, including the first
+	Backoff     time.Duration // initial delay between retries; doubles each attempt
+	Timeout     time.Duration // per-attempt request timeout
+	Client      *http.Client
+	Clock       clock.Clock // defaults to the real clock
+}
+
+// Event is the JSON body POSTed to the webhook URL.
+type Event struct {
+	Type       string                     `json:"type"`
+	GameID     string                     `json:"gameId"`
+	OldStatus  domaingames.GameStatusKind `json:"oldStatus"`
+	NewStatus  domaingames.GameStatusKind `json:"newStatus"`
+	Score      domaingames.Score          `json:"score"`
+	OccurredAt time.Time                  `json:"occurredAt"`
+}
+
+// Sender delivers game status transitions to a webhook URL from a bounded queue,
+// so a slow or failing receiver never blocks the poller.
+type Sender struct {
+	cfg     Config
+	client  *http.Client
+	clock   clock.Clock
+	logger  *slog.Logger
+	metrics *metrics.Recorder
+
+	queue     chan Event
+	done      chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// New constructs a Sender, or returns nil when no URL is configured.
+func New(cfg Config, logger *slog.Logger, recorder *metrics.Recorder) *Sender {
+	if cfg.URL == "" {
+		return nil
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = defaultBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return &Sender{
+		cfg:     cfg,
+		client:  client,
+		clock:   clock.OrReal(cfg.Clock),
+		logger:  logger,
+		metrics: recorder,
+		queue:   make(chan Event, cfg.QueueSize),
+		done:    make(chan struct{}),
+	}
+}
+
+// Notify queues a webhook for status transitions and ignores other change kinds.
+// It never blocks: when the queue is full the event is dropped and counted.
+// Suitable for passing directly to Poller.OnChange.
+func (s *Sender) Notify(ev poller.GameChangeEvent) {
+	if s == nil || ev.Kind != poller.ChangeStatus {
+		return
+	}
+	event := Event{
+		Type:       EventType,
+		GameID:     ev.GameID,
+		OldStatus:  ev.OldStatus,
+		NewStatus:  ev.NewStatus,
+		Score:      ev.Score,
+		OccurredAt: ev.At,
+	}
+	select {
+	case s.queue <- event:
+	default:
+		s.metrics.RecordWebhookDropped()
+		logging.Warn(s.logger, "webhook queue full, dropping event", "game_id", ev.GameID)
+	}
+}
+
+// Start launches the delivery worker until ctx is cancelled or Stop is called.
+func (s *Sender) Start(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.startOnce.Do(func() {
+		s.wg.Add(1)
+		go s.run(ctx)
+	})
+}
+
+// Stop halts delivery, waiting for an in-flight attempt up to ctx's deadline.
+// Events still queued are discarded.
+func (s *Sender) Stop(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.stopOnce.Do(func() { close(s.done) })
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Sender) run(ctx context.Context) {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case event := <-s.queue:
+			err := s.deliver(ctx, event)
+			s.metrics.RecordWebhookDelivery(err)
+			if err != nil {
+				logging.Error(s.logger, "webhook delivery failed", err, "game_id", event.GameID)
+			}
+		}
+	}
+}
+
+// deliver POSTs event, retrying network errors, 429s, and 5xx responses with backoff.
+func (s *Sender) deliver(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	backoff := s.cfg.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := s.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.cfg.MaxAttempts {
+			return fmt.Errorf("after %d attempt(s): %w", attempt, err)
+		}
+		logging.Warn(s.logger, "webhook attempt failed, retrying",
+			"game_id", event.GameID,
+			"attempt", attempt,
+			"err", err,
+		)
+		if !s.wait(ctx, backoff) {
+			return fmt.Errorf("stopped after %d attempt(s): %w", attempt, err)
+		}
+		backoff *= 2
+	}
+}
+
+func (s *Sender) post(ctx context.Context, body []byte) (retry bool, err error) {
+	reqCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(s.cfg.Secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("webhook responded %d", resp.StatusCode)
+}
+
+func (s *Sender) wait(ctx context.Context, d time.Duration) bool {
+	timer := s.clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches body under secret. Receivers can use
+// it to authenticate deliveries.
+func Verify(secret string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
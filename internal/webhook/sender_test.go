@@ -0,0 +1,207 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	domaingames "github.com/preston-bernstein/nba-data-service/internal/domain/games"
+	"github.com/preston-bernstein/nba-data-service/internal/metrics"
+	"github.com/preston-bernstein/nba-data-service/internal/poller"
+	"github.com/preston-bernstein/nba-data-service/internal/teststubs"
+)
+
+type receivedRequest struct {
+	body      []byte
+	signature string
+}
+
+// receiver records requests and replies with the scripted statuses in order,
+// then 200 once the script is exhausted.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	got      []receivedRequest
+	hits     chan struct{}
+}
+
+func newReceiver(statuses ...int) (*receiver, *httptest.Server) {
+	rcv := &receiver{statuses: statuses, hits: make(chan struct{}, 16)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rcv.mu.Lock()
+		rcv.got = append(rcv.got, receivedRequest{body: body, signature: r.Header.Get(SignatureHeader)})
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status = rcv.statuses[0]
+			rcv.statuses = rcv.statuses[1:]
+		}
+		rcv.mu.Unlock()
+		w.WriteHeader(status)
+		rcv.hits <- struct{}{}
+	}))
+	return rcv, srv
+}
+
+func (r *receiver) waitFor(t *testing.T, n int) []receivedRequest {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.hits:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for request %d of %d", i+1, n)
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.got...)
+}
+
+var finalEvent = poller.GameChangeEvent{
+	Kind:      poller.ChangeStatus,
+	GameID:    "g1",
+	OldStatus: domaingames.StatusInProgress,
+	NewStatus: domaingames.StatusFinal,
+	Score:     domaingames.Score{Home: 104, Away: 98},
+	At:        time.Date(2024, 1, 15, 22, 30, 0, 0, time.UTC),
+}
+
+func waitForStats(t *testing.T, rec *metrics.Recorder, sent, failed int) {
+	t.Helper()
+	teststubs.WaitFor(t, 2*time.Second, func() bool {
+		s, f, _ := rec.WebhookStats()
+		return s == sent && f == failed
+	}, "expected sent=%d failed=%d", sent, failed)
+}
+
+func TestSenderPostsSignedPayload(t *testing.T) {
+	rcv, srv := newReceiver()
+	defer srv.Close()
+	rec := metrics.NewRecorder()
+	s := New(Config{URL: srv.URL, Secret: "shh"}, nil, rec)
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	s.Notify(finalEvent)
+	got := rcv.waitFor(t, 1)
+
+	var payload map[string]any
+	if err := json.Unmarshal(got[0].body, &payload); err != nil {
+		t.Fatalf("expected JSON payload: %v", err)
+	}
+	for _, key := range []string{"type", "gameId", "oldStatus", "newStatus", "score", "occurredAt"} {
+		if _, ok := payload[key]; !ok {
+			t.Fatalf("expected payload key %q, got %v", key, payload)
+		}
+	}
+	var event Event
+	if err := json.Unmarshal(got[0].body, &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	want := Event{
+		Type:       EventType,
+		GameID:     "g1",
+		OldStatus:  domaingames.StatusInProgress,
+		NewStatus:  domaingames.StatusFinal,
+		Score:      domaingames.Score{Home: 104, Away: 98},
+		OccurredAt: finalEvent.At,
+	}
+	if event != want {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	if !Verify("shh", got[0].body, got[0].signature) {
+		t.Fatalf("expected valid signature, got %q", got[0].signature)
+	}
+	if Verify("other", got[0].body, got[0].signature) {
+		t.Fatalf("expected signature to fail under wrong secret")
+	}
+	waitForStats(t, rec, 1, 0)
+}
+
+func TestSenderRetriesOn500(t *testing.T) {
+	rcv, srv := newReceiver(http.StatusInternalServerError, http.StatusBadGateway)
+	defer srv.Close()
+	rec := metrics.NewRecorder()
+	s := New(Config{URL: srv.URL, Backoff: time.Millisecond}, nil, rec)
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	s.Notify(finalEvent)
+	got := rcv.waitFor(t, 3)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(got))
+	}
+	if got[0].signature != "" {
+		t.Fatalf("expected no signature without a secret, got %q", got[0].signature)
+	}
+	waitForStats(t, rec, 1, 0)
+}
+
+func TestSenderGivesUpAndCountsFailures(t *testing.T) {
+	rcv, srv := newReceiver(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusBadRequest)
+	defer srv.Close()
+	rec := metrics.NewRecorder()
+	s := New(Config{URL: srv.URL, Backoff: time.Millisecond, MaxAttempts: 2}, nil, rec)
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	s.Notify(finalEvent)
+	rcv.waitFor(t, 2)
+	waitForStats(t, rec, 0, 1)
+
+	// 4xx responses other than 429 are not retried.
+	s.Notify(finalEvent)
+	rcv.waitFor(t, 1)
+	waitForStats(t, rec, 0, 2)
+}
+
+func TestNotifyIgnoresNonStatusChangesAndDropsWhenFull(t *testing.T) {
+	rec := metrics.NewRecorder()
+	s := New(Config{URL: "http://127.0.0.1:0", QueueSize: 1}, nil, rec)
+
+	s.Notify(poller.GameChangeEvent{Kind: poller.ChangeScore, GameID: "g1"})
+	if len(s.queue) != 0 {
+		t.Fatalf("expected score changes to be ignored")
+	}
+	s.Notify(finalEvent)
+	s.Notify(finalEvent) // worker not started; queue is full
+	if _, _, dropped := rec.WebhookStats(); dropped != 1 {
+		t.Fatalf("expected 1 dropped event, got %d", dropped)
+	}
+}
+
+func TestNewWithoutURLDisablesSender(t *testing.T) {
+	s := New(Config{}, nil, nil)
+	if s != nil {
+		t.Fatalf("expected nil sender without URL")
+	}
+	s.Notify(finalEvent)
+	s.Start(context.Background())
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("expected nil sender stop to succeed, got %v", err)
+	}
+}
+
+func TestStopInterruptsRetryBackoff(t *testing.T) {
+	rcv, srv := newReceiver(http.StatusInternalServerError)
+	defer srv.Close()
+	rec := metrics.NewRecorder()
+	s := New(Config{URL: srv.URL, Backoff: time.Hour}, nil, rec)
+	s.Start(context.Background())
+
+	s.Notify(finalEvent)
+	rcv.waitFor(t, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("expected stop to interrupt backoff, got %v", err)
+	}
+	if _, failed, _ := rec.WebhookStats(); failed != 1 {
+		t.Fatalf("expected interrupted delivery counted as failure, got %d", failed)
+	}
+}